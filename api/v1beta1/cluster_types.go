@@ -24,6 +24,7 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/utils/ptr"
 
@@ -186,6 +187,12 @@ type MachineDeploymentTopology struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling configures the Cluster Autoscaler for this MachineDeployment.
+	// When set, the Cluster Autoscaler annotations are kept in sync on the MachineDeployment and
+	// replicas must not be set, so the Cluster Autoscaler is responsible for the management of the number of Replicas.
+	// +optional
+	Autoscaling *AutoscalingTopology `json:"autoscaling,omitempty"`
+
 	// MachineHealthCheck allows to enable, disable and override
 	// the MachineHealthCheck configuration in the ClusterClass for this MachineDeployment.
 	// +optional
@@ -225,6 +232,56 @@ type MachineDeploymentTopology struct {
 	Variables *MachineDeploymentVariables `json:"variables,omitempty"`
 }
 
+// AutoscalingTopology configures the Cluster Autoscaler for a set of worker nodes in the topology.
+type AutoscalingTopology struct {
+	// MinSize is the minimum number of nodes the Cluster Autoscaler can scale the set of worker nodes down to.
+	// A value of 0 requires the Cluster Autoscaler to know the capacity of the nodes, see scaleFromZero.
+	// +kubebuilder:validation:Minimum=0
+	MinSize int32 `json:"minSize"`
+
+	// MaxSize is the maximum number of nodes the Cluster Autoscaler can scale the set of worker nodes up to.
+	// +kubebuilder:validation:Minimum=1
+	MaxSize int32 `json:"maxSize"`
+
+	// ScaleFromZero describes the nodes of the set of worker nodes, so the Cluster Autoscaler can scale it
+	// up from zero when the infrastructure provider doesn't report the capacity of the nodes.
+	// +optional
+	ScaleFromZero *ScaleFromZeroCapacity `json:"scaleFromZero,omitempty"`
+}
+
+// ScaleFromZeroCapacity describes the nodes of a set of worker nodes for the Cluster Autoscaler.
+type ScaleFromZeroCapacity struct {
+	// CPU is the CPU capacity of a node.
+	// +optional
+	CPU *resource.Quantity `json:"cpu,omitempty"`
+
+	// Memory is the memory capacity of a node.
+	// +optional
+	Memory *resource.Quantity `json:"memory,omitempty"`
+
+	// GPU is the GPU capacity of a node.
+	// +optional
+	GPU *GPUCapacity `json:"gpu,omitempty"`
+
+	// Labels are the labels the nodes are expected to have once they join the cluster.
+	// +optional
+	Labels map[string]string `json:"labels,omitempty"`
+
+	// Taints are the taints the nodes are expected to have once they join the cluster.
+	// +optional
+	Taints []corev1.Taint `json:"taints,omitempty"`
+}
+
+// GPUCapacity describes the GPUs of a node for the Cluster Autoscaler.
+type GPUCapacity struct {
+	// Type is the resource name of the GPUs, e.g. nvidia.com/gpu.
+	Type string `json:"type"`
+
+	// Count is the number of GPUs of a node.
+	// +kubebuilder:validation:Minimum=1
+	Count int32 `json:"count"`
+}
+
 // MachineHealthCheckTopology defines a MachineHealthCheck for a group of machines.
 type MachineHealthCheckTopology struct {
 	// Enable controls if a MachineHealthCheck should be created for the target machines.
@@ -299,6 +356,12 @@ type MachinePoolTopology struct {
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
 
+	// Autoscaling configures the Cluster Autoscaler for this MachinePool.
+	// When set, the Cluster Autoscaler annotations are kept in sync on the MachinePool and
+	// replicas must not be set, so the Cluster Autoscaler is responsible for the management of the number of Replicas.
+	// +optional
+	Autoscaling *AutoscalingTopology `json:"autoscaling,omitempty"`
+
 	// Variables can be used to customize the MachinePool through patches.
 	// +optional
 	Variables *MachinePoolVariables `json:"variables,omitempty"`
@@ -107,6 +107,11 @@ const (
 	// older MachineSets when Machines are deleted and add the new replicas to the latest MachineSet.
 	DisableMachineCreateAnnotation = "cluster.x-k8s.io/disable-machine-create"
 
+	// RotateCertificateAuthoritiesAnnotation is an annotation that can be applied to a Cluster to request the rotation of the
+	// certificate authorities and of the service account keys generated by Cluster API; the annotation is removed when
+	// the rotation is started.
+	RotateCertificateAuthoritiesAnnotation = "cluster.x-k8s.io/rotate-certificate-authorities"
+
 	// WatchLabel is a label othat can be applied to any Cluster API object.
 	//
 	// Controllers which allow for selective reconciliation may check this label and proceed
@@ -191,6 +196,38 @@ const (
 	// Note: It can be used by setting as top level annotation on MachineDeployment and MachineSets.
 	AutoscalerMaxSizeAnnotation = "cluster.x-k8s.io/cluster-api-autoscaler-node-group-max-size"
 
+	// AutoscalerCapacityCPUAnnotation defines the CPU capacity of the nodes of a node group.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityCPUAnnotation = "capacity.cluster-autoscaler.kubernetes.io/cpu"
+
+	// AutoscalerCapacityMemoryAnnotation defines the memory capacity of the nodes of a node group.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityMemoryAnnotation = "capacity.cluster-autoscaler.kubernetes.io/memory"
+
+	// AutoscalerCapacityGPUTypeAnnotation defines the resource name of the GPUs of the nodes of a node group.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityGPUTypeAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-type"
+
+	// AutoscalerCapacityGPUCountAnnotation defines the number of GPUs of the nodes of a node group.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityGPUCountAnnotation = "capacity.cluster-autoscaler.kubernetes.io/gpu-count"
+
+	// AutoscalerCapacityLabelsAnnotation defines the labels of the nodes of a node group, as a comma separated
+	// list of key=value pairs.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityLabelsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/labels"
+
+	// AutoscalerCapacityTaintsAnnotation defines the taints of the nodes of a node group, as a comma separated
+	// list of key=value:effect entries.
+	// The annotation is used by the autoscaler when scaling a node group from zero.
+	// Note: It can be used by setting as top level annotation on MachineDeployment, MachineSets and MachinePools.
+	AutoscalerCapacityTaintsAnnotation = "capacity.cluster-autoscaler.kubernetes.io/taints"
+
 	// VariableDefinitionFromInline indicates a patch or variable was defined in the `.spec` of a ClusterClass
 	// rather than from an external patch extension.
 	VariableDefinitionFromInline = "inline"
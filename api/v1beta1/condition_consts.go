@@ -104,6 +104,36 @@ const (
 	WaitingForControlPlaneAvailableReason = "WaitingForControlPlaneAvailable"
 )
 
+// Conditions and condition Reasons for the rotation of the certificate authorities of a Cluster.
+
+const (
+	// TrustBundleDistributedCondition reports if the certificate authorities generated during a certificate authorities
+	// rotation have been added to the trust bundles, and all the machines have been rolled out to trust them.
+	TrustBundleDistributedCondition ConditionType = "TrustBundleDistributed"
+
+	// CertificateAuthoritiesRotatedCondition reports if the certificate authorities generated during a certificate
+	// authorities rotation are used for signing certificates, and all the machines and the Kubeconfig have been
+	// rolled out to use certificates signed by them.
+	CertificateAuthoritiesRotatedCondition ConditionType = "CertificateAuthoritiesRotated"
+
+	// PreviousCertificateAuthoritiesRetiredCondition reports if the certificate authorities replaced during a
+	// certificate authorities rotation have been removed from the trust bundles, and all the machines have been
+	// rolled out to stop trusting them.
+	PreviousCertificateAuthoritiesRetiredCondition ConditionType = "PreviousCertificateAuthoritiesRetired"
+
+	// WaitingForControlPlaneRolloutReason (Severity=Info) documents a certificate authorities rotation waiting
+	// for the control plane machines to be rolled out.
+	WaitingForControlPlaneRolloutReason = "WaitingForControlPlaneRollout"
+
+	// WaitingForWorkersRolloutReason (Severity=Info) documents a certificate authorities rotation waiting
+	// for the worker machines to be rolled out.
+	WaitingForWorkersRolloutReason = "WaitingForWorkersRollout"
+
+	// CertificateAuthoritiesRotationFailedReason (Severity=Warning) documents a certificate authorities rotation
+	// which failed to distribute the trust bundle or to roll out machines; the operation will be retried.
+	CertificateAuthoritiesRotationFailedReason = "CertificateAuthoritiesRotationFailed"
+)
+
 // Conditions and condition Reasons for the Machine object.
 
 const (
@@ -142,6 +172,16 @@ const (
 
 	// WaitingForVolumeDetachReason (Severity=Info) provide evidence that a machine node waiting for volumes to be attached.
 	WaitingForVolumeDetachReason = "WaitingForVolumeDetach"
+
+	// IPAddressesBoundCondition reports whether all the IPAddressClaims required by a machine are bound.
+	// The infrastructure for the machine is not provisioned until this condition is true.
+	IPAddressesBoundCondition ConditionType = "IPAddressesBound"
+
+	// WaitingForIPAddressesReason (Severity=Info) documents a machine waiting for its IPAddressClaims to be bound.
+	WaitingForIPAddressesReason = "WaitingForIPAddresses"
+
+	// IPAddressClaimFailedReason (Severity=Warning) documents a machine failing to create or read its IPAddressClaims.
+	IPAddressClaimFailedReason = "IPAddressClaimFailed"
 )
 
 const (
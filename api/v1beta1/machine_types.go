@@ -132,10 +132,31 @@ type MachineSpec struct {
 	// Defaults to 10 seconds.
 	// +optional
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+
+	// IPAddressClaims is a list of IP addresses the Machine requires. For each entry the Machine controller
+	// creates an IPAddressClaim against the referenced pool, and the infrastructure for the Machine is not
+	// provisioned until all the claims are bound. Bound addresses are reported in status.ipAddresses.
+	// This field is immutable.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	IPAddressClaims []MachineIPAddressClaim `json:"ipAddressClaims,omitempty"`
 }
 
 // ANCHOR_END: MachineSpec
 
+// MachineIPAddressClaim defines an IP address required by a Machine.
+type MachineIPAddressClaim struct {
+	// Name identifies the IP address within the Machine, e.g. the name of the network interface
+	// the infrastructure provider should configure with it. It must be unique within the Machine.
+	// +kubebuilder:validation:MinLength=1
+	// +kubebuilder:validation:MaxLength=63
+	Name string `json:"name"`
+
+	// PoolRef is a reference to the pool the IP address is allocated from.
+	PoolRef corev1.TypedLocalObjectReference `json:"poolRef"`
+}
+
 // ANCHOR: MachineStatus
 
 // MachineStatus defines the observed state of Machine.
@@ -206,6 +227,12 @@ type MachineStatus struct {
 	// +optional
 	CertificatesExpiryDate *metav1.Time `json:"certificatesExpiryDate,omitempty"`
 
+	// IPAddresses are the IP addresses bound to the IPAddressClaims created for spec.ipAddressClaims.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	IPAddresses []MachineIPAddress `json:"ipAddresses,omitempty"`
+
 	// BootstrapReady is the state of the bootstrap provider.
 	// +optional
 	BootstrapReady bool `json:"bootstrapReady"`
@@ -225,6 +252,25 @@ type MachineStatus struct {
 
 // ANCHOR_END: MachineStatus
 
+// MachineIPAddress is an IP address bound to a Machine.
+type MachineIPAddress struct {
+	// Name is the name of the entry in spec.ipAddressClaims the address was allocated for.
+	Name string `json:"name"`
+
+	// ClaimName is the name of the IPAddressClaim the address is bound to.
+	ClaimName string `json:"claimName"`
+
+	// Address is the IP address.
+	Address string `json:"address"`
+
+	// Prefix is the prefix length of the network the address belongs to.
+	Prefix int `json:"prefix"`
+
+	// Gateway is the network gateway of the network the address belongs to.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+}
+
 // SetTypedPhase sets the Phase field to the string representation of MachinePhase.
 func (m *MachineStatus) SetTypedPhase(p MachinePhase) {
 	m.Phase = string(p)
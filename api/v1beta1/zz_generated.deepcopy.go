@@ -44,6 +44,26 @@ func (in *APIEndpoint) DeepCopy() *APIEndpoint {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *AutoscalingTopology) DeepCopyInto(out *AutoscalingTopology) {
+	*out = *in
+	if in.ScaleFromZero != nil {
+		in, out := &in.ScaleFromZero, &out.ScaleFromZero
+		*out = new(ScaleFromZeroCapacity)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new AutoscalingTopology.
+func (in *AutoscalingTopology) DeepCopy() *AutoscalingTopology {
+	if in == nil {
+		return nil
+	}
+	out := new(AutoscalingTopology)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Bootstrap) DeepCopyInto(out *Bootstrap) {
 	*out = *in
@@ -680,6 +700,21 @@ func (in FailureDomains) DeepCopy() FailureDomains {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GPUCapacity) DeepCopyInto(out *GPUCapacity) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GPUCapacity.
+func (in *GPUCapacity) DeepCopy() *GPUCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(GPUCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *JSONPatch) DeepCopyInto(out *JSONPatch) {
 	*out = *in
@@ -1150,6 +1185,11 @@ func (in *MachineDeploymentTopology) DeepCopyInto(out *MachineDeploymentTopology
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingTopology)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.MachineHealthCheck != nil {
 		in, out := &in.MachineHealthCheck, &out.MachineHealthCheck
 		*out = new(MachineHealthCheckTopology)
@@ -1407,6 +1447,37 @@ func (in *MachineHealthCheckTopology) DeepCopy() *MachineHealthCheckTopology {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineIPAddress) DeepCopyInto(out *MachineIPAddress) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineIPAddress.
+func (in *MachineIPAddress) DeepCopy() *MachineIPAddress {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineIPAddress)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MachineIPAddressClaim) DeepCopyInto(out *MachineIPAddressClaim) {
+	*out = *in
+	in.PoolRef.DeepCopyInto(&out.PoolRef)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineIPAddressClaim.
+func (in *MachineIPAddressClaim) DeepCopy() *MachineIPAddressClaim {
+	if in == nil {
+		return nil
+	}
+	out := new(MachineIPAddressClaim)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MachineList) DeepCopyInto(out *MachineList) {
 	*out = *in
@@ -1557,6 +1628,11 @@ func (in *MachinePoolTopology) DeepCopyInto(out *MachinePoolTopology) {
 		*out = new(int32)
 		**out = **in
 	}
+	if in.Autoscaling != nil {
+		in, out := &in.Autoscaling, &out.Autoscaling
+		*out = new(AutoscalingTopology)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Variables != nil {
 		in, out := &in.Variables, &out.Variables
 		*out = new(MachinePoolVariables)
@@ -1774,6 +1850,13 @@ func (in *MachineSpec) DeepCopyInto(out *MachineSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.IPAddressClaims != nil {
+		in, out := &in.IPAddressClaims, &out.IPAddressClaims
+		*out = make([]MachineIPAddressClaim, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MachineSpec.
@@ -1822,6 +1905,11 @@ func (in *MachineStatus) DeepCopyInto(out *MachineStatus) {
 		in, out := &in.CertificatesExpiryDate, &out.CertificatesExpiryDate
 		*out = (*in).DeepCopy()
 	}
+	if in.IPAddresses != nil {
+		in, out := &in.IPAddresses, &out.IPAddresses
+		*out = make([]MachineIPAddress, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(Conditions, len(*in))
@@ -2011,6 +2099,50 @@ func (in *PatchSelectorMatchMachinePoolClass) DeepCopy() *PatchSelectorMatchMach
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ScaleFromZeroCapacity) DeepCopyInto(out *ScaleFromZeroCapacity) {
+	*out = *in
+	if in.CPU != nil {
+		in, out := &in.CPU, &out.CPU
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.Memory != nil {
+		in, out := &in.Memory, &out.Memory
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.GPU != nil {
+		in, out := &in.GPU, &out.GPU
+		*out = new(GPUCapacity)
+		**out = **in
+	}
+	if in.Labels != nil {
+		in, out := &in.Labels, &out.Labels
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	if in.Taints != nil {
+		in, out := &in.Taints, &out.Taints
+		*out = make([]v1.Taint, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ScaleFromZeroCapacity.
+func (in *ScaleFromZeroCapacity) DeepCopy() *ScaleFromZeroCapacity {
+	if in == nil {
+		return nil
+	}
+	out := new(ScaleFromZeroCapacity)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Topology) DeepCopyInto(out *Topology) {
 	*out = *in
@@ -82,6 +82,10 @@ var (
 	// CABPK specific flags.
 	clusterConcurrency             int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerMaxClusters int
+	clusterCacheTrackerIdleTimeout time.Duration
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
 	kubeadmConfigConcurrency       int
 	tokenTTL                       time.Duration
 )
@@ -130,6 +134,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.IntVar(&clusterCacheTrackerMaxClusters, "clustercachetracker-max-clusters", 0,
+		"Maximum number of workload clusters with a cached client; when reached, the cache of the least recently used workload cluster without watches is stopped. 0 means unlimited")
+
+	fs.DurationVar(&clusterCacheTrackerIdleTimeout, "clustercachetracker-idle-timeout", 0,
+		"Duration after which the cache of a workload cluster without watches which has not been accessed is stopped. 0 means caches are never stopped because they are idle")
+
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 0,
+		"Maximum queries per second from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 0,
+		"Maximum burst for throttle from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
 	fs.IntVar(&kubeadmConfigConcurrency, "kubeadmconfig-concurrency", 10,
 		"Number of kubeadm configs to process simultaneously")
 
@@ -301,6 +317,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		remote.ClusterCacheTrackerOptions{
 			SecretCachingClient: secretCachingClient,
 			ControllerName:      controllerName,
+			MaxAccessors:        clusterCacheTrackerMaxClusters,
+			AccessorIdleTimeout: clusterCacheTrackerIdleTimeout,
+			ClientQPS:           clusterCacheTrackerClientQPS,
+			ClientBurst:         clusterCacheTrackerClientBurst,
 			Log:                 &ctrl.Log,
 		},
 	)
@@ -41,4 +41,8 @@ const (
 	// `clusterctl move` is invoked, then NO resources for ANY workload cluster will be created on the
 	// destination management cluster until the annotation is removed.
 	BlockMoveAnnotation = "clusterctl.cluster.x-k8s.io/block-move"
+
+	// PreviousVersionAnnotation is set by clusterctl upgrade apply on the inventory object of an upgraded provider
+	// and reports the version of the provider before the upgrade; it is used by clusterctl upgrade rollback.
+	PreviousVersionAnnotation = "clusterctl.cluster.x-k8s.io/previous-version"
 )
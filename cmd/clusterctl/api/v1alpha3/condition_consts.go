@@ -0,0 +1,50 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition Reasons for the ManagedProvider object.
+
+const (
+	// ProviderInstalledCondition reports if the provider is installed in the management cluster with the desired version.
+	ProviderInstalledCondition clusterv1.ConditionType = "ProviderInstalled"
+
+	// WaitingForCoreProviderReason (Severity=Info) documents a ManagedProvider waiting for the core provider
+	// to be installed before installing the provider.
+	WaitingForCoreProviderReason = "WaitingForCoreProvider"
+
+	// InstallFailedReason (Severity=Error) documents a ManagedProvider failing to install the provider.
+	InstallFailedReason = "InstallFailed"
+
+	// UpgradeFailedReason (Severity=Error) documents a ManagedProvider failing to upgrade the provider to the desired version.
+	UpgradeFailedReason = "UpgradeFailed"
+
+	// ConfigurationUpdateFailedReason (Severity=Error) documents a ManagedProvider failing to apply the provider's
+	// components again after a change to the variables or the image overrides.
+	ConfigurationUpdateFailedReason = "ConfigurationUpdateFailed"
+
+	// DeletingReason (Severity=Info) documents a ManagedProvider deleting the provider.
+	DeletingReason = "Deleting"
+
+	// DeleteFailedReason (Severity=Warning) documents a ManagedProvider failing to delete the provider.
+	DeleteFailedReason = "DeleteFailed"
+
+	// InvalidSpecReason (Severity=Error) documents a ManagedProvider with a spec that cannot be reconciled,
+	// e.g. because the provider is installed more than once.
+	InvalidSpecReason = "InvalidSpec"
+)
@@ -0,0 +1,160 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha3
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ManagedProviderFinalizer is set on a ManagedProvider to allow the clusterctl operator to
+	// delete the provider components before the object is removed.
+	ManagedProviderFinalizer = "managedprovider.clusterctl.cluster.x-k8s.io"
+)
+
+// ManagedProviderSpec defines the desired state of a provider managed by the clusterctl operator.
+type ManagedProviderSpec struct {
+	// ProviderName indicates the name of the provider, e.g. cluster-api, kubeadm, docker.
+	ProviderName string `json:"providerName"`
+
+	// Type indicates the type of the provider.
+	// See ProviderType for a list of supported values.
+	// +kubebuilder:validation:Enum=CoreProvider;BootstrapProvider;InfrastructureProvider;ControlPlaneProvider;IPAMProvider;RuntimeExtensionProvider;AddonProvider
+	Type string `json:"type"`
+
+	// Version indicates the desired version of the provider.
+	// If empty, the latest release is installed and the provider is never upgraded automatically.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// TargetNamespace defines the namespace where the provider should be installed.
+	// If empty, the provider's default namespace is used.
+	// +optional
+	TargetNamespace string `json:"targetNamespace,omitempty"`
+
+	// FetchURL defines the URL of the provider's components, in the same format used by the
+	// providers list in the clusterctl configuration file.
+	// If empty, the URL of the providers known by clusterctl is used.
+	// +optional
+	FetchURL string `json:"fetchURL,omitempty"`
+
+	// Variables defines the values of the variables used for processing the provider's components.
+	// +optional
+	Variables map[string]string `json:"variables,omitempty"`
+
+	// VariablesSecretName is the name of a Secret in the same namespace of the ManagedProvider
+	// whose keys and values are used as variables for processing the provider's components;
+	// values in the Secret take precedence over the ones defined in Variables.
+	// +optional
+	VariablesSecretName string `json:"variablesSecretName,omitempty"`
+
+	// ImageOverrides defines overrides for the images of the provider's components.
+	// +optional
+	ImageOverrides []ImageOverride `json:"imageOverrides,omitempty"`
+}
+
+// ImageOverride defines an override for images of the provider's components.
+type ImageOverride struct {
+	// Component is the name of the component the override applies to, e.g. cluster-api or cert-manager/cert-manager-cainjector;
+	// if empty, the override applies to all the components.
+	// +optional
+	Component string `json:"component,omitempty"`
+
+	// Repository sets the container registry to pull images from.
+	// +optional
+	Repository string `json:"repository,omitempty"`
+
+	// Tag allows to specify a tag for the images.
+	// +optional
+	Tag string `json:"tag,omitempty"`
+}
+
+// ManagedProviderStatus defines the observed state of a provider managed by the clusterctl operator.
+type ManagedProviderStatus struct {
+	// InstalledVersion is the version of the provider currently installed, as recorded in the provider inventory.
+	// +optional
+	InstalledVersion string `json:"installedVersion,omitempty"`
+
+	// InstalledNamespace is the namespace where the provider is currently installed.
+	// +optional
+	InstalledNamespace string `json:"installedNamespace,omitempty"`
+
+	// ConfigurationHash is the hash of the configuration used for processing the provider's components currently
+	// installed, i.e. fetchURL, variables, the content of the variables Secret and image overrides; when the hash
+	// changes, the provider's components are applied again.
+	// +optional
+	ConfigurationHash string `json:"configurationHash,omitempty"`
+
+	// ObservedGeneration is the latest generation observed by the controller.
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+
+	// Conditions defines current service state of the ManagedProvider.
+	// +optional
+	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+}
+
+// +kubebuilder:resource:path=managedproviders,scope=Namespaced,categories=cluster-api
+// +kubebuilder:storageversion
+// +kubebuilder:object:root=true
+// +kubebuilder:subresource:status
+// +kubebuilder:printcolumn:name="Type",type="string",JSONPath=".spec.type"
+// +kubebuilder:printcolumn:name="Provider",type="string",JSONPath=".spec.providerName"
+// +kubebuilder:printcolumn:name="Desired",type="string",JSONPath=".spec.version"
+// +kubebuilder:printcolumn:name="Installed",type="string",JSONPath=".status.installedVersion"
+// +kubebuilder:printcolumn:name="Ready",type="string",JSONPath=".status.conditions[?(@.type=='Ready')].status"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of ManagedProvider"
+
+// ManagedProvider declares a provider whose lifecycle (install, upgrade, delete) is managed by the clusterctl operator.
+type ManagedProvider struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   ManagedProviderSpec   `json:"spec,omitempty"`
+	Status ManagedProviderStatus `json:"status,omitempty"`
+}
+
+// GetProviderType parse the ManagedProvider.Spec.Type string field and return the typed representation.
+func (m *ManagedProvider) GetProviderType() ProviderType {
+	p := Provider{Type: m.Spec.Type}
+	return p.GetProviderType()
+}
+
+// GetConditions returns the set of conditions for this object.
+func (m *ManagedProvider) GetConditions() clusterv1.Conditions {
+	return m.Status.Conditions
+}
+
+// SetConditions sets the conditions on this object.
+func (m *ManagedProvider) SetConditions(conditions clusterv1.Conditions) {
+	m.Status.Conditions = conditions
+}
+
+// +kubebuilder:object:root=true
+
+// ManagedProviderList contains a list of ManagedProvider.
+type ManagedProviderList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []ManagedProvider `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &ManagedProvider{}, &ManagedProviderList{})
+}
@@ -53,6 +53,58 @@ type Provider struct {
 	// Deprecated: providers complying with the Cluster API v1alpha4 contract or above must watch all namespaces; this field will be removed in a future version of this API
 	// +optional
 	WatchedNamespace string `json:"watchedNamespace,omitempty"`
+
+	// Patches are the patches applied to the provider components when the provider was installed or upgraded,
+	// as defined in the clusterctl configuration. They are re-applied when upgrading the provider, unless
+	// different patches are defined in the clusterctl configuration.
+	// +optional
+	Patches []ProviderPatch `json:"patches,omitempty"`
+}
+
+// ProviderPatch defines a patch to be applied to the provider components, using the same format of the
+// patches in a Kustomization.
+type ProviderPatch struct {
+	// Target selects the provider components the patch applies to.
+	// If not set, the target is identified by the apiVersion, kind, name and namespace of the patch,
+	// which in this case must be a strategic merge patch.
+	// +optional
+	Target *PatchSelector `json:"target,omitempty"`
+
+	// Patch is the content of the patch, either a strategic merge patch or a JSON 6902 patch,
+	// in YAML or JSON format.
+	Patch string `json:"patch"`
+}
+
+// PatchSelector selects the provider components a patch applies to.
+// Empty fields match all the values; Name and Namespace support regular expressions.
+type PatchSelector struct {
+	// Group of the target components.
+	// +optional
+	Group string `json:"group,omitempty"`
+
+	// Version of the target components.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// Kind of the target components.
+	// +optional
+	Kind string `json:"kind,omitempty"`
+
+	// Name of the target components.
+	// +optional
+	Name string `json:"name,omitempty"`
+
+	// Namespace of the target components.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// LabelSelector is a label selector for the target components.
+	// +optional
+	LabelSelector string `json:"labelSelector,omitempty"`
+
+	// AnnotationSelector is an annotation selector for the target components.
+	// +optional
+	AnnotationSelector string `json:"annotationSelector,omitempty"`
 }
 
 // ManifestLabel returns the cluster.x-k8s.io/provider label value for an entry in the provider inventory.
@@ -0,0 +1,472 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/bundle"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/util"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+const (
+	// DefaultBundleFile is the default name of the bundle created by CreateBundle.
+	DefaultBundleFile = "clusterctl-bundle.tar.gz"
+
+	bundleScheme = "bundle"
+	metadataFile = "metadata.yaml"
+)
+
+// CreateBundleOptions carries the options supported by CreateBundle.
+type CreateBundleOptions struct {
+	// CoreProvider version (e.g. cluster-api:v1.1.5) to add to the bundle. If unspecified, the
+	// cluster-api core provider's latest release is used.
+	CoreProvider string
+
+	// BootstrapProviders and versions (e.g. kubeadm:v1.1.5) to add to the bundle.
+	// If unspecified, the kubeadm bootstrap provider's latest release is used.
+	BootstrapProviders []string
+
+	// ControlPlaneProviders and versions (e.g. kubeadm:v1.1.5) to add to the bundle.
+	// If unspecified, the kubeadm control plane provider latest release is used.
+	ControlPlaneProviders []string
+
+	// InfrastructureProviders and versions (e.g. aws:v0.5.0) to add to the bundle.
+	InfrastructureProviders []string
+
+	// IPAMProviders and versions (e.g. infoblox:v0.0.1) to add to the bundle.
+	IPAMProviders []string
+
+	// RuntimeExtensionProviders and versions (e.g. test:v0.0.1) to add to the bundle.
+	RuntimeExtensionProviders []string
+
+	// AddonProviders and versions (e.g. helm:v0.1.0) to add to the bundle.
+	AddonProviders []string
+
+	// Flavors of the cluster templates to add to the bundle for the infrastructure providers, in addition to the default one.
+	Flavors []string
+
+	// Output is the path of the bundle to create. If unspecified, DefaultBundleFile is used.
+	Output string
+
+	// SkipImages instructs CreateBundle to not add the container images to the bundle.
+	SkipImages bool
+}
+
+// CreateBundle creates a bundle with everything required for running init without network access.
+func (c *clusterctlClient) CreateBundle(ctx context.Context, options CreateBundleOptions) error {
+	log := logf.Log
+
+	if options.Output == "" {
+		options.Output = DefaultBundleFile
+	}
+	if options.CoreProvider == "" {
+		options.CoreProvider = config.ClusterAPIProviderName
+	}
+	if len(options.BootstrapProviders) == 0 {
+		options.BootstrapProviders = append(options.BootstrapProviders, config.KubeadmBootstrapProviderName)
+	}
+	if len(options.ControlPlaneProviders) == 0 {
+		options.ControlPlaneProviders = append(options.ControlPlaneProviders, config.KubeadmControlPlaneProviderName)
+	}
+
+	if options.CoreProvider == NoopProvider {
+		return errors.New("the '-' value can not be used for the core provider")
+	}
+
+	b := &bundleBuilder{
+		manifest: &bundle.Manifest{},
+		files:    map[string][]byte{},
+		images:   sets.Set[string]{},
+	}
+
+	providers := []struct {
+		providerType clusterctlv1.ProviderType
+		names        []string
+	}{
+		{clusterctlv1.CoreProviderType, []string{options.CoreProvider}},
+		{clusterctlv1.BootstrapProviderType, options.BootstrapProviders},
+		{clusterctlv1.ControlPlaneProviderType, options.ControlPlaneProviders},
+		{clusterctlv1.InfrastructureProviderType, options.InfrastructureProviders},
+		{clusterctlv1.IPAMProviderType, options.IPAMProviders},
+		{clusterctlv1.RuntimeExtensionProviderType, options.RuntimeExtensionProviders},
+		{clusterctlv1.AddonProviderType, options.AddonProviders},
+	}
+	for _, p := range providers {
+		for _, provider := range p.names {
+			// It is possible to opt-out from bootstrap/control-plane providers using '-' as a provider name (NoopProvider).
+			if provider == NoopProvider {
+				continue
+			}
+			log.Info("Adding provider to the bundle", "Provider", provider, "Type", p.providerType)
+			if err := c.addProviderToBundle(ctx, b, provider, p.providerType, options.Flavors); err != nil {
+				return errors.Wrapf(err, "failed to add the %q provider to the bundle", provider)
+			}
+		}
+	}
+
+	log.Info("Adding cert-manager to the bundle")
+	if err := c.addCertManagerToBundle(ctx, b); err != nil {
+		return errors.Wrap(err, "failed to add cert-manager to the bundle")
+	}
+
+	var imagesDir string
+	if !options.SkipImages {
+		b.manifest.Images = sets.List(b.images)
+
+		var err error
+		imagesDir, err = os.MkdirTemp("", "clusterctl-bundle")
+		if err != nil {
+			return errors.Wrap(err, "failed to create a temporary directory for the images")
+		}
+		defer os.RemoveAll(imagesDir)
+
+		if err := c.imageCopier.Save(ctx, imagesDir, b.manifest.Images); err != nil {
+			return errors.Wrap(err, "failed to add images to the bundle")
+		}
+	}
+
+	log.Info("Writing bundle", "File", options.Output)
+	return b.write(options.Output, imagesDir)
+}
+
+// bundleBuilder collects the content of a bundle before writing it.
+type bundleBuilder struct {
+	manifest *bundle.Manifest
+	files    map[string][]byte
+	images   sets.Set[string]
+}
+
+// addRelease adds a release and its files to the bundle.
+func (b *bundleBuilder) addRelease(release bundle.Release, files map[string][]byte) {
+	for name, data := range files {
+		release.Files = append(release.Files, name)
+		b.files[bundle.ReleasePath(release.Label(), release.Version, name)] = data
+	}
+	sort.Strings(release.Files)
+	b.manifest.Releases = append(b.manifest.Releases, release)
+}
+
+// addImages adds the images referenced by a YAML manifest to the bundle.
+func (b *bundleBuilder) addImages(data []byte) error {
+	objs, err := utilyaml.ToUnstructured(data)
+	if err != nil {
+		return err
+	}
+	images, err := util.InspectImages(objs)
+	if err != nil {
+		return err
+	}
+	b.images.Insert(images...)
+	return nil
+}
+
+// write writes the bundle to a file; the release files are written before the images, so they can be
+// read without scanning the entire archive.
+func (b *bundleBuilder) write(output, imagesDir string) error {
+	f, err := os.Create(output) //nolint:gosec
+	if err != nil {
+		return errors.Wrapf(err, "failed to create bundle %s", output)
+	}
+	defer f.Close()
+
+	w, err := bundle.NewWriter(f, b.manifest)
+	if err != nil {
+		return err
+	}
+
+	names := make([]string, 0, len(b.files))
+	for name := range b.files {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := w.AddFile(name, b.files[name]); err != nil {
+			return err
+		}
+	}
+
+	if imagesDir != "" {
+		if err := w.AddDir(bundle.ImagesDir, imagesDir); err != nil {
+			return err
+		}
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return f.Close()
+}
+
+func (c *clusterctlClient) addProviderToBundle(ctx context.Context, b *bundleBuilder, provider string, providerType clusterctlv1.ProviderType, flavors []string) error {
+	name, version, err := parseProviderName(provider)
+	if err != nil {
+		return err
+	}
+
+	providerConfig, err := c.configClient.Providers().Get(name, providerType)
+	if err != nil {
+		return err
+	}
+
+	repositoryClient, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: providerConfig})
+	if err != nil {
+		return err
+	}
+
+	// Resolve the version to add to the bundle, if not explicitly specified.
+	if version == "" {
+		components, err := repositoryClient.Components().Get(ctx, repository.ComponentsOptions{SkipTemplateProcess: true})
+		if err != nil {
+			return err
+		}
+		version = components.Version()
+	}
+
+	componentsFile, err := componentsFileName(providerConfig)
+	if err != nil {
+		return err
+	}
+
+	components, err := repositoryClient.Components().Raw(ctx, repository.ComponentsOptions{Version: version})
+	if err != nil {
+		return err
+	}
+	if err := b.addImages(components); err != nil {
+		return errors.Wrap(err, "failed to get the images from the provider components")
+	}
+
+	metadata, err := repositoryClient.Metadata(version).Get(ctx)
+	if err != nil {
+		return err
+	}
+	metadata.APIVersion = clusterctlv1.GroupVersion.String()
+	metadata.Kind = "Metadata"
+	metadataData, err := yaml.Marshal(metadata)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the provider metadata")
+	}
+
+	files := map[string][]byte{
+		componentsFile: components,
+		metadataFile:   metadataData,
+	}
+
+	// Cluster templates are expected to exist for the infrastructure providers only; the default template
+	// is added if it exists, while the templates for the requested flavors must exist.
+	if providerType == clusterctlv1.InfrastructureProviderType {
+		if template, err := repositoryClient.GetFile(ctx, version, "cluster-template.yaml"); err == nil {
+			files["cluster-template.yaml"] = template
+		}
+		for _, flavor := range flavors {
+			templateFile := fmt.Sprintf("cluster-template-%s.yaml", flavor)
+			template, err := repositoryClient.GetFile(ctx, version, templateFile)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get the cluster template for flavor %q", flavor)
+			}
+			files[templateFile] = template
+		}
+	}
+
+	b.addRelease(bundle.Release{
+		Name:           providerConfig.Name(),
+		Type:           string(providerConfig.Type()),
+		Version:        version,
+		ComponentsFile: componentsFile,
+	}, files)
+	return nil
+}
+
+func (c *clusterctlClient) addCertManagerToBundle(ctx context.Context, b *bundleBuilder) error {
+	certManagerConfig, err := c.configClient.CertManager().Get()
+	if err != nil {
+		return err
+	}
+
+	// Given that cert manager components yaml are stored in a repository like providers components yaml,
+	// we are using the same machinery to retrieve the file by using a fake provider object using
+	// the cert manager repository url.
+	certManagerFakeProvider := config.NewProvider(bundle.CertManagerName, certManagerConfig.URL(), "")
+	repositoryClient, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: certManagerFakeProvider})
+	if err != nil {
+		return err
+	}
+
+	componentsFile, err := componentsFileName(certManagerFakeProvider)
+	if err != nil {
+		return err
+	}
+
+	components, err := repositoryClient.Components().Raw(ctx, repository.ComponentsOptions{Version: certManagerConfig.Version()})
+	if err != nil {
+		return err
+	}
+	if err := b.addImages(components); err != nil {
+		return errors.Wrap(err, "failed to get the images from the cert-manager manifest")
+	}
+
+	b.addRelease(bundle.Release{
+		Name:           bundle.CertManagerName,
+		Version:        certManagerConfig.Version(),
+		ComponentsFile: componentsFile,
+	}, map[string][]byte{componentsFile: components})
+	return nil
+}
+
+// componentsFileName returns the name of the components file of a provider, which is the last element of its URL.
+func componentsFileName(provider config.Provider) (string, error) {
+	u, err := url.Parse(provider.URL())
+	if err != nil {
+		return "", errors.Wrapf(err, "invalid url for provider %s", provider.ManifestLabel())
+	}
+	name := path.Base(u.Path)
+	if name == "." || name == "/" {
+		return "", errors.Errorf("invalid url for provider %s: the url must point to the components file", provider.ManifestLabel())
+	}
+	return name, nil
+}
+
+// useBundle sets the configuration of the providers, of the cert-manager and of the image overrides for reading
+// everything from a bundle; if a registry is given, it also pushes the images in the bundle to the registry.
+func (c *clusterctlClient) useBundle(ctx context.Context, bundlePath, registry string, pushImages bool) error {
+	log := logf.Log
+
+	bundlePath, err := filepath.Abs(bundlePath)
+	if err != nil {
+		return errors.Wrapf(err, "invalid bundle path %s", bundlePath)
+	}
+	b, err := bundle.Open(bundlePath)
+	if err != nil {
+		return err
+	}
+
+	bundleURL := url.URL{Scheme: bundleScheme, Path: filepath.ToSlash(bundlePath)}
+	if !strings.HasPrefix(bundleURL.Path, "/") {
+		// Windows paths must be prefixed with a / in URLs, like for local repositories.
+		bundleURL.Path = "/" + bundleURL.Path
+	}
+
+	// Point the providers included in the bundle to the bundle, preserving all the other provider configurations.
+	providerConfigs, err := c.configClient.Providers().List()
+	if err != nil {
+		return err
+	}
+	type configProvider struct {
+		Name string                    `json:"name,omitempty"`
+		URL  string                    `json:"url,omitempty"`
+		Type clusterctlv1.ProviderType `json:"type,omitempty"`
+	}
+	providers := []configProvider{}
+	for _, p := range providerConfigs {
+		providerURL := p.URL()
+		if len(b.Manifest().ReleasesFor(p.ManifestLabel())) > 0 {
+			providerURL = bundleURL.String()
+		}
+		providers = append(providers, configProvider{Name: p.Name(), URL: providerURL, Type: p.Type()})
+	}
+	for _, release := range b.Manifest().Releases {
+		if release.Type == "" || providerConfigExists(providerConfigs, release) {
+			continue
+		}
+		providers = append(providers, configProvider{Name: release.Name, URL: bundleURL.String(), Type: clusterctlv1.ProviderType(release.Type)})
+	}
+	data, err := yaml.Marshal(providers)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the provider configurations")
+	}
+	c.configClient.Variables().Set(config.ProvidersConfigKey, string(data))
+
+	// Read cert-manager from the bundle too.
+	if certManager := b.Manifest().ReleasesFor(bundle.CertManagerName); len(certManager) > 0 {
+		certManagerConfig, err := c.configClient.CertManager().Get()
+		if err != nil {
+			return err
+		}
+		data, err := yaml.Marshal(map[string]string{
+			"url":     bundleURL.String(),
+			"version": certManager[0].Version,
+			"timeout": certManagerConfig.Timeout(),
+		})
+		if err != nil {
+			return errors.Wrap(err, "failed to marshal the cert-manager configuration")
+		}
+		c.configClient.Variables().Set(config.CertManagerConfigKey, string(data))
+	}
+
+	if registry == "" {
+		return nil
+	}
+
+	// Rewrite all the images to the registry.
+	data, err = yaml.Marshal(map[string]map[string]string{
+		"all": {"repository": registry},
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the image override configuration")
+	}
+	c.configClient.Variables().Set("images", string(data))
+
+	if !pushImages || len(b.Manifest().Images) == 0 {
+		return nil
+	}
+
+	targets := map[string]string{}
+	for _, image := range b.Manifest().Images {
+		target, err := c.configClient.ImageMeta().AlterImage("", image)
+		if err != nil {
+			return err
+		}
+		targets[image] = target
+	}
+
+	imagesDir, err := os.MkdirTemp("", "clusterctl-bundle")
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary directory for the images")
+	}
+	defer os.RemoveAll(imagesDir)
+
+	log.Info("Pushing images from the bundle", "Registry", registry)
+	if err := b.ExtractDir(bundle.ImagesDir, imagesDir); err != nil {
+		return err
+	}
+	return c.imageCopier.Push(ctx, imagesDir, targets)
+}
+
+func providerConfigExists(providerConfigs []config.Provider, release bundle.Release) bool {
+	for _, p := range providerConfigs {
+		if p.ManifestLabel() == release.Label() {
+			return true
+		}
+	}
+	return false
+}
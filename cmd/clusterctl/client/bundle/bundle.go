@@ -0,0 +1,310 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+)
+
+const (
+	// FormatVersion is the version of the bundle format written by this version of clusterctl.
+	FormatVersion = "v1alpha1"
+
+	// ManifestFile is the name of the file describing the content of a bundle.
+	ManifestFile = "clusterctl-bundle.yaml"
+
+	// RepositoryDir is the directory of a bundle containing provider releases, with the
+	// {provider-label}/{version}/{file} layout used by local filesystem repositories.
+	RepositoryDir = "repository"
+
+	// ImagesDir is the directory of a bundle containing the container images, stored in the OCI image layout.
+	ImagesDir = "images"
+
+	// CertManagerName is the name used for the cert-manager release in a bundle.
+	CertManagerName = "cert-manager"
+)
+
+// Manifest describes the content of a bundle.
+type Manifest struct {
+	// FormatVersion is the version of the bundle format.
+	FormatVersion string `json:"formatVersion"`
+
+	// Releases are the provider releases included in the bundle, including the cert-manager one.
+	Releases []Release `json:"releases"`
+
+	// Images are the container images included in the bundle.
+	Images []string `json:"images,omitempty"`
+}
+
+// Release describes a provider release included in a bundle.
+type Release struct {
+	// Name of the provider.
+	Name string `json:"name"`
+
+	// Type of the provider; empty for cert-manager.
+	Type string `json:"type,omitempty"`
+
+	// Version of the release.
+	Version string `json:"version"`
+
+	// ComponentsFile is the name of the file containing the provider components.
+	ComponentsFile string `json:"componentsFile"`
+
+	// Files are the names of all the files of the release, including the components file.
+	Files []string `json:"files"`
+}
+
+// Label returns the label used for identifying the provider of a release, e.g. infrastructure-aws.
+func (r *Release) Label() string {
+	return clusterctlv1.ManifestLabel(r.Name, clusterctlv1.ProviderType(r.Type))
+}
+
+// ReleasesFor returns the releases for the provider with the given label.
+func (m *Manifest) ReleasesFor(label string) []Release {
+	releases := []Release{}
+	for _, r := range m.Releases {
+		if r.Label() == label {
+			releases = append(releases, r)
+		}
+	}
+	return releases
+}
+
+// ReleasePath returns the path of a file of a provider release in a bundle.
+func ReleasePath(label, version, file string) string {
+	return path.Join(RepositoryDir, label, version, file)
+}
+
+// Writer writes a bundle archive; the manifest is written first, so it can be read without
+// scanning the entire archive.
+type Writer struct {
+	gz *gzip.Writer
+	tw *tar.Writer
+}
+
+// NewWriter returns a Writer writing a bundle with the given manifest to w.
+func NewWriter(w io.Writer, manifest *Manifest) (*Writer, error) {
+	gz := gzip.NewWriter(w)
+	bw := &Writer{
+		gz: gz,
+		tw: tar.NewWriter(gz),
+	}
+
+	manifest.FormatVersion = FormatVersion
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to marshal the bundle manifest")
+	}
+	if err := bw.AddFile(ManifestFile, data); err != nil {
+		return nil, err
+	}
+	return bw, nil
+}
+
+// AddFile adds a file to the bundle.
+func (w *Writer) AddFile(name string, data []byte) error {
+	if err := w.tw.WriteHeader(&tar.Header{
+		Typeflag: tar.TypeReg,
+		Name:     name,
+		Mode:     0600,
+		Size:     int64(len(data)),
+	}); err != nil {
+		return errors.Wrapf(err, "failed to add %s to the bundle", name)
+	}
+	if _, err := w.tw.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to add %s to the bundle", name)
+	}
+	return nil
+}
+
+// AddDir adds all the files in dir to the bundle, under the given prefix.
+func (w *Writer) AddDir(prefix, dir string) error {
+	return filepath.Walk(dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+
+		rel, err := filepath.Rel(dir, p)
+		if err != nil {
+			return err
+		}
+		name := path.Join(prefix, filepath.ToSlash(rel))
+
+		if err := w.tw.WriteHeader(&tar.Header{
+			Typeflag: tar.TypeReg,
+			Name:     name,
+			Mode:     0600,
+			Size:     info.Size(),
+		}); err != nil {
+			return errors.Wrapf(err, "failed to add %s to the bundle", name)
+		}
+
+		f, err := os.Open(p) //nolint:gosec
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		if _, err := io.Copy(w.tw, f); err != nil {
+			return errors.Wrapf(err, "failed to add %s to the bundle", name)
+		}
+		return nil
+	})
+}
+
+// Close completes writing the bundle; it does not close the underlying writer.
+func (w *Writer) Close() error {
+	if err := w.tw.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the bundle")
+	}
+	if err := w.gz.Close(); err != nil {
+		return errors.Wrap(err, "failed to write the bundle")
+	}
+	return nil
+}
+
+// Bundle provides read access to a bundle archive.
+type Bundle struct {
+	path     string
+	manifest *Manifest
+}
+
+// Open opens the bundle archive at the given path and reads its manifest.
+func Open(path string) (*Bundle, error) {
+	b := &Bundle{path: path}
+
+	data, err := b.ReadFile(ManifestFile)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid bundle %s", path)
+	}
+
+	manifest := &Manifest{}
+	if err := yaml.Unmarshal(data, manifest); err != nil {
+		return nil, errors.Wrapf(err, "invalid bundle %s: failed to read %s", path, ManifestFile)
+	}
+	if manifest.FormatVersion != FormatVersion {
+		return nil, errors.Errorf("invalid bundle %s: format version %q is not supported", path, manifest.FormatVersion)
+	}
+	b.manifest = manifest
+	return b, nil
+}
+
+// Path returns the path of the bundle archive.
+func (b *Bundle) Path() string {
+	return b.path
+}
+
+// Manifest returns the manifest of the bundle.
+func (b *Bundle) Manifest() *Manifest {
+	return b.manifest
+}
+
+// ReadFile returns the content of a file in the bundle.
+func (b *Bundle) ReadFile(name string) ([]byte, error) {
+	var data []byte
+	err := b.walk(func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if hdr.Name != name {
+			return false, nil
+		}
+		var err error
+		data, err = io.ReadAll(r)
+		return true, err
+	})
+	if err != nil {
+		return nil, err
+	}
+	if data == nil {
+		return nil, errors.Errorf("file %s does not exist in bundle %s", name, b.path)
+	}
+	return data, nil
+}
+
+// ExtractDir extracts all the files under the given prefix, e.g. ImagesDir, to dir.
+func (b *Bundle) ExtractDir(prefix, dir string) error {
+	prefix = strings.TrimSuffix(prefix, "/") + "/"
+	return b.walk(func(hdr *tar.Header, r io.Reader) (bool, error) {
+		if hdr.Typeflag != tar.TypeReg || !strings.HasPrefix(hdr.Name, prefix) {
+			return false, nil
+		}
+
+		rel := strings.TrimPrefix(hdr.Name, prefix)
+		target := filepath.Join(dir, filepath.FromSlash(rel))
+		// Protect from archive entries pointing outside of dir.
+		if !strings.HasPrefix(target, filepath.Clean(dir)+string(os.PathSeparator)) {
+			return false, errors.Errorf("invalid file %s in bundle %s", hdr.Name, b.path)
+		}
+
+		if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+			return false, err
+		}
+		f, err := os.OpenFile(target, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600) //nolint:gosec
+		if err != nil {
+			return false, err
+		}
+		defer f.Close()
+		if _, err := io.Copy(f, r); err != nil { //nolint:gosec // The size of the bundle is controlled by the user.
+			return false, errors.Wrapf(err, "failed to extract %s from bundle %s", hdr.Name, b.path)
+		}
+		return false, nil
+	})
+}
+
+// walk calls fn for each file in the bundle, until fn returns true or an error.
+func (b *Bundle) walk(fn func(hdr *tar.Header, r io.Reader) (bool, error)) error {
+	f, err := os.Open(b.path)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open bundle %s", b.path)
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return errors.Wrapf(err, "failed to read bundle %s", b.path)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return errors.Wrapf(err, "failed to read bundle %s", b.path)
+		}
+		done, err := fn(hdr, tr)
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+	}
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestBundle(t *testing.T) {
+	g := NewWithT(t)
+
+	manifest := &Manifest{
+		Releases: []Release{
+			{Name: "cluster-api", Type: "CoreProvider", Version: "v1.6.0", ComponentsFile: "core-components.yaml", Files: []string{"core-components.yaml"}},
+			{Name: "docker", Type: "InfrastructureProvider", Version: "v1.6.0", ComponentsFile: "infrastructure-components.yaml", Files: []string{"infrastructure-components.yaml"}},
+			{Name: CertManagerName, Version: "v1.14.2", ComponentsFile: "cert-manager.yaml", Files: []string{"cert-manager.yaml"}},
+		},
+		Images: []string{"registry.k8s.io/cluster-api/cluster-api-controller:v1.6.0"},
+	}
+
+	imagesDir := t.TempDir()
+	g.Expect(os.MkdirAll(filepath.Join(imagesDir, "blobs", "sha256"), 0750)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(imagesDir, "index.json"), []byte("index"), 0600)).To(Succeed())
+	g.Expect(os.WriteFile(filepath.Join(imagesDir, "blobs", "sha256", "abc"), []byte("blob"), 0600)).To(Succeed())
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	f, err := os.Create(bundlePath) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	w, err := NewWriter(f, manifest)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(w.AddFile(ReleasePath("cluster-api", "v1.6.0", "core-components.yaml"), []byte("core"))).To(Succeed())
+	g.Expect(w.AddDir(ImagesDir, imagesDir)).To(Succeed())
+	g.Expect(w.Close()).To(Succeed())
+	g.Expect(f.Close()).To(Succeed())
+
+	b, err := Open(bundlePath)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(b.Path()).To(Equal(bundlePath))
+	g.Expect(b.Manifest().FormatVersion).To(Equal(FormatVersion))
+	g.Expect(b.Manifest().Images).To(Equal(manifest.Images))
+
+	g.Expect(b.Manifest().ReleasesFor("cluster-api")).To(HaveLen(1))
+	g.Expect(b.Manifest().ReleasesFor("infrastructure-docker")).To(HaveLen(1))
+	g.Expect(b.Manifest().ReleasesFor(CertManagerName)).To(HaveLen(1))
+	g.Expect(b.Manifest().ReleasesFor("bootstrap-kubeadm")).To(BeEmpty())
+
+	data, err := b.ReadFile("repository/cluster-api/v1.6.0/core-components.yaml")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal("core"))
+
+	_, err = b.ReadFile("repository/cluster-api/v1.6.0/metadata.yaml")
+	g.Expect(err).To(HaveOccurred())
+
+	extractDir := t.TempDir()
+	g.Expect(b.ExtractDir(ImagesDir, extractDir)).To(Succeed())
+	data, err = os.ReadFile(filepath.Join(extractDir, "index.json")) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal("index"))
+	data, err = os.ReadFile(filepath.Join(extractDir, "blobs", "sha256", "abc")) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(data)).To(Equal("blob"))
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("fails if the file is not a bundle", func(t *testing.T) {
+		g := NewWithT(t)
+
+		bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		g.Expect(os.WriteFile(bundlePath, []byte("not a bundle"), 0600)).To(Succeed())
+
+		_, err := Open(bundlePath)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if the format version is not supported", func(t *testing.T) {
+		g := NewWithT(t)
+
+		bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+		g.Expect(os.WriteFile(bundlePath, testArchive(g, map[string]string{ManifestFile: "formatVersion: v0"}), 0600)).To(Succeed())
+
+		_, err := Open(bundlePath)
+		g.Expect(err).To(MatchError(ContainSubstring("is not supported")))
+	})
+}
+
+func TestBundle_ExtractDir(t *testing.T) {
+	g := NewWithT(t)
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	g.Expect(os.WriteFile(bundlePath, testArchive(g, map[string]string{
+		ManifestFile:            "formatVersion: " + FormatVersion,
+		"images/../../evil.txt": "evil",
+	}), 0600)).To(Succeed())
+
+	b, err := Open(bundlePath)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(b.ExtractDir(ImagesDir, t.TempDir())).ToNot(Succeed())
+}
+
+func testArchive(g *WithT, files map[string]string) []byte {
+	buf := &bytes.Buffer{}
+	gz := gzip.NewWriter(buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		g.Expect(tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: 0600, Size: int64(len(content))})).To(Succeed())
+		_, err := tw.Write([]byte(content))
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(tw.Close()).To(Succeed())
+	g.Expect(gz.Close()).To(Succeed())
+	return buf.Bytes()
+}
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package bundle
+
+import (
+	"context"
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/containerd/containerd/content/local"
+	"github.com/containerd/containerd/images"
+	"github.com/containerd/containerd/platforms"
+	"github.com/containerd/containerd/remotes"
+	"github.com/containerd/containerd/remotes/docker"
+	"github.com/distribution/reference"
+	"github.com/opencontainers/image-spec/specs-go"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// ImageCopier copies container images between registries and a directory using the OCI image layout.
+type ImageCopier interface {
+	// Save copies the given images from their registries to the OCI image layout in dir.
+	Save(ctx context.Context, dir string, images []string) error
+
+	// Push copies the images in the OCI image layout in dir to the registries; targets maps the name
+	// of each image in the layout to the name of the image to push.
+	Push(ctx context.Context, dir string, targets map[string]string) error
+}
+
+// NewImageCopier returns an ImageCopier using the registry API; registries on localhost are accessed using plain HTTP.
+func NewImageCopier() ImageCopier {
+	return &registryImageCopier{
+		resolver: docker.NewResolver(docker.ResolverOptions{
+			Hosts: docker.ConfigureDefaultRegistries(
+				docker.WithAuthorizer(docker.NewDockerAuthorizer()),
+				docker.WithPlainHTTP(docker.MatchLocalhost),
+			),
+		}),
+	}
+}
+
+type registryImageCopier struct {
+	resolver remotes.Resolver
+}
+
+var _ ImageCopier = &registryImageCopier{}
+
+func (c *registryImageCopier) Save(ctx context.Context, dir string, imageRefs []string) error {
+	log := logf.Log
+
+	store, err := local.NewStore(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create image store in %s", dir)
+	}
+	// The local store uses the ingest directory for temporary files only.
+	defer os.RemoveAll(filepath.Join(dir, "ingest"))
+
+	index := ocispec.Index{
+		Versioned: specs.Versioned{SchemaVersion: 2},
+		MediaType: ocispec.MediaTypeImageIndex,
+	}
+	for _, imageRef := range imageRefs {
+		log.Info("Saving image", "Image", imageRef)
+
+		ref, err := reference.ParseDockerRef(imageRef)
+		if err != nil {
+			return errors.Wrapf(err, "invalid image %s", imageRef)
+		}
+
+		name, desc, err := c.resolver.Resolve(ctx, ref.String())
+		if err != nil {
+			return errors.Wrapf(err, "failed to resolve image %s", imageRef)
+		}
+		fetcher, err := c.resolver.Fetcher(ctx, name)
+		if err != nil {
+			return errors.Wrapf(err, "failed to fetch image %s", imageRef)
+		}
+
+		// Fetch the image, including the manifests for all the platforms.
+		handler := images.Handlers(
+			remotes.FetchHandler(store, fetcher),
+			images.ChildrenHandler(store),
+		)
+		if err := images.Dispatch(ctx, handler, nil, desc); err != nil {
+			return errors.Wrapf(err, "failed to fetch image %s", imageRef)
+		}
+
+		desc.Annotations = map[string]string{
+			images.AnnotationImageName: imageRef,
+		}
+		if tagged, ok := ref.(reference.Tagged); ok {
+			desc.Annotations[ocispec.AnnotationRefName] = tagged.Tag()
+		}
+		index.Manifests = append(index.Manifests, desc)
+	}
+
+	data, err := json.Marshal(index)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the image index")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageIndexFile), data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the image index")
+	}
+
+	data, err = json.Marshal(ocispec.ImageLayout{Version: ocispec.ImageLayoutVersion})
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the image layout")
+	}
+	if err := os.WriteFile(filepath.Join(dir, ocispec.ImageLayoutFile), data, 0600); err != nil {
+		return errors.Wrap(err, "failed to write the image layout")
+	}
+	return nil
+}
+
+func (c *registryImageCopier) Push(ctx context.Context, dir string, targets map[string]string) error {
+	log := logf.Log
+
+	store, err := local.NewStore(dir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to open image store in %s", dir)
+	}
+	defer os.RemoveAll(filepath.Join(dir, "ingest"))
+
+	data, err := os.ReadFile(filepath.Join(dir, ocispec.ImageIndexFile)) //nolint:gosec
+	if err != nil {
+		return errors.Wrap(err, "failed to read the image index")
+	}
+	index := ocispec.Index{}
+	if err := json.Unmarshal(data, &index); err != nil {
+		return errors.Wrap(err, "failed to read the image index")
+	}
+
+	for _, desc := range index.Manifests {
+		imageRef := desc.Annotations[images.AnnotationImageName]
+		target, ok := targets[imageRef]
+		if !ok {
+			continue
+		}
+		log.Info("Pushing image", "Image", imageRef, "Target", target)
+
+		ref, err := reference.ParseDockerRef(target)
+		if err != nil {
+			return errors.Wrapf(err, "invalid image %s", target)
+		}
+		pusher, err := c.resolver.Pusher(ctx, ref.String())
+		if err != nil {
+			return errors.Wrapf(err, "failed to push image %s", target)
+		}
+		if err := remotes.PushContent(ctx, pusher, desc, store, nil, platforms.All, nil); err != nil {
+			return errors.Wrapf(err, "failed to push image %s", target)
+		}
+	}
+	return nil
+}
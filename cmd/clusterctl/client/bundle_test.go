@@ -0,0 +1,248 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/bundle"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+func Test_clusterctlClient_CreateBundle(t *testing.T) {
+	tests := []struct {
+		name         string
+		options      CreateBundleOptions
+		wantReleases []string
+		wantImages   []string
+		wantErr      bool
+	}{
+		{
+			name: "create a bundle with the default providers, the infrastructure provider and cert-manager",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+			},
+			wantReleases: []string{
+				"cluster-api/v1.0.0",
+				"bootstrap-kubeadm/v2.0.0",
+				"control-plane-kubeadm/v2.0.0",
+				"infrastructure-infra/v3.0.0",
+				"cert-manager/v1.10.2",
+			},
+			wantImages: []string{
+				"quay.io/jetstack/cert-manager-controller:v1.10.2",
+				"registry.k8s.io/cluster-api-aws/cluster-api-aws-controller:v0.5.3",
+			},
+		},
+		{
+			name: "create a bundle with explicit versions, without images",
+			options: CreateBundleOptions{
+				CoreProvider:            "cluster-api:v1.1.0",
+				BootstrapProviders:      []string{"kubeadm:v2.1.0"},
+				ControlPlaneProviders:   []string{NoopProvider},
+				InfrastructureProviders: []string{"infra:v3.1.0"},
+				SkipImages:              true,
+			},
+			wantReleases: []string{
+				"cluster-api/v1.1.0",
+				"bootstrap-kubeadm/v2.1.0",
+				"infrastructure-infra/v3.1.0",
+				"cert-manager/v1.10.2",
+			},
+		},
+		{
+			name: "fails if the template for a flavor does not exist",
+			options: CreateBundleOptions{
+				InfrastructureProviders: []string{"infra"},
+				Flavors:                 []string{"not-existing"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "fails if the core provider is the NoopProvider",
+			options: CreateBundleOptions{
+				CoreProvider: NoopProvider,
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			client, copier := fakeBundleClient()
+
+			tt.options.Output = filepath.Join(t.TempDir(), "bundle.tar.gz")
+			err := client.CreateBundle(ctx, tt.options)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			b, err := bundle.Open(tt.options.Output)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			releases := []string{}
+			for _, r := range b.Manifest().Releases {
+				releases = append(releases, r.Label()+"/"+r.Version)
+
+				for _, f := range r.Files {
+					_, err := b.ReadFile(bundle.ReleasePath(r.Label(), r.Version, f))
+					g.Expect(err).ToNot(HaveOccurred())
+				}
+			}
+			g.Expect(releases).To(Equal(tt.wantReleases))
+
+			g.Expect(b.Manifest().Images).To(Equal(tt.wantImages))
+			g.Expect(copier.saved).To(Equal(tt.wantImages))
+			if len(tt.wantImages) > 0 {
+				_, err = b.ReadFile(bundle.ImagesDir + "/index.json")
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func Test_clusterctlClient_CreateBundle_Templates(t *testing.T) {
+	g := NewWithT(t)
+
+	client, _ := fakeBundleClient()
+
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	g.Expect(client.CreateBundle(ctx, CreateBundleOptions{
+		InfrastructureProviders: []string{"infra"},
+		SkipImages:              true,
+		Output:                  output,
+	})).To(Succeed())
+
+	b, err := bundle.Open(output)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	releases := b.Manifest().ReleasesFor("infrastructure-infra")
+	g.Expect(releases).To(HaveLen(1))
+	g.Expect(releases[0].ComponentsFile).To(Equal("url"))
+	g.Expect(releases[0].Files).To(ConsistOf("url", "metadata.yaml", "cluster-template.yaml"))
+
+	data, err := b.ReadFile(bundle.ReleasePath("infrastructure-infra", "v3.0.0", "cluster-template.yaml"))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(Equal(templateYAML("ns4", "test")))
+}
+
+func Test_clusterctlClient_useBundle(t *testing.T) {
+	g := NewWithT(t)
+
+	client, copier := fakeBundleClient()
+
+	output := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	g.Expect(client.CreateBundle(ctx, CreateBundleOptions{
+		InfrastructureProviders: []string{"infra"},
+		Output:                  output,
+	})).To(Succeed())
+
+	g.Expect(client.internalClient.useBundle(ctx, output, "localhost:5000", true)).To(Succeed())
+
+	bundleURL := "bundle://" + filepath.ToSlash(output)
+
+	// Providers in the bundle are read from the bundle, all the others are not changed.
+	configClient := client.internalClient.configClient
+	p, err := configClient.Providers().Get("infra", clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p.URL()).To(Equal(bundleURL))
+
+	p, err = configClient.Providers().Get(config.AWSProviderName, clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p.URL()).ToNot(Equal(bundleURL))
+
+	certManager, err := configClient.CertManager().Get()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(certManager.URL()).To(Equal(bundleURL))
+	g.Expect(certManager.Version()).To(Equal("v1.10.2"))
+
+	// Images are rewritten to the registry, and pushed there.
+	image, err := configClient.ImageMeta().AlterImage("infrastructure-infra", "registry.k8s.io/cluster-api-aws/cluster-api-aws-controller:v0.5.3")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(image).To(Equal("localhost:5000/cluster-api-aws-controller:v0.5.3"))
+
+	g.Expect(copier.pushed).To(Equal(map[string]string{
+		"quay.io/jetstack/cert-manager-controller:v1.10.2":                  "localhost:5000/cert-manager-controller:v1.10.2",
+		"registry.k8s.io/cluster-api-aws/cluster-api-aws-controller:v0.5.3": "localhost:5000/cluster-api-aws-controller:v0.5.3",
+	}))
+}
+
+// fakeBundleClient returns a clusterctl client with repositories for the default providers, an infrastructure
+// provider and cert-manager, and a fake ImageCopier.
+func fakeBundleClient() (*fakeClient, *fakeImageCopier) {
+	certManagerURL := "https://github.com/cert-manager/cert-manager/releases/v1.10.2/cert-manager.yaml"
+
+	cfg := fakeConfig(
+		[]config.Provider{capiProviderConfig, bootstrapProviderConfig, controlPlaneProviderConfig, infraProviderConfig},
+		map[string]string{"SOME_VARIABLE": "value"},
+	)
+	cfg.fakeReader.WithCertManager(certManagerURL, "v1.10.2", "")
+
+	repositories := fakeRepositories(cfg, nil)
+	repositories = append(repositories, newFakeRepository(ctx, config.NewProvider(bundle.CertManagerName, certManagerURL, ""), cfg).
+		WithPaths("root", "cert-manager.yaml").
+		WithDefaultVersion("v1.10.2").
+		WithFile("v1.10.2", "cert-manager.yaml", []byte(certManagerYAML)))
+
+	client := fakeClusterCtlClient(cfg, repositories, nil)
+
+	copier := &fakeImageCopier{}
+	client.internalClient.imageCopier = copier
+	return client, copier
+}
+
+const certManagerYAML = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: cert-manager
+  namespace: cert-manager
+spec:
+  template:
+    spec:
+      containers:
+      - image: quay.io/jetstack/cert-manager-controller:v1.10.2
+        name: cert-manager-controller
+`
+
+type fakeImageCopier struct {
+	saved  []string
+	pushed map[string]string
+}
+
+var _ bundle.ImageCopier = &fakeImageCopier{}
+
+func (f *fakeImageCopier) Save(_ context.Context, dir string, images []string) error {
+	f.saved = images
+	return os.WriteFile(filepath.Join(dir, "index.json"), []byte("{}"), 0600)
+}
+
+func (f *fakeImageCopier) Push(_ context.Context, dir string, targets map[string]string) error {
+	if _, err := os.Stat(filepath.Join(dir, "index.json")); err != nil {
+		return err
+	}
+	f.pushed = targets
+	return nil
+}
@@ -21,6 +21,7 @@ import (
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/alpha"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/bundle"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
@@ -41,6 +42,9 @@ type Client interface {
 	// Init initializes a management cluster by adding the requested list of providers.
 	Init(ctx context.Context, options InitOptions) ([]Components, error)
 
+	// CreateBundle creates a bundle with everything required for running init without network access.
+	CreateBundle(ctx context.Context, options CreateBundleOptions) error
+
 	// InitImages returns the list of images required for executing the init command.
 	InitImages(ctx context.Context, options InitOptions) ([]string, error)
 
@@ -65,6 +69,9 @@ type Client interface {
 	// ApplyUpgrade executes an upgrade plan.
 	ApplyUpgrade(ctx context.Context, options ApplyUpgradeOptions) error
 
+	// RollbackUpgrade rolls back the providers changed by the last upgrade to the version they had before.
+	RollbackUpgrade(ctx context.Context, options RollbackUpgradeOptions) error
+
 	// ProcessYAML provides a direct way to process a yaml and inspect its
 	// variables.
 	ProcessYAML(ctx context.Context, options ProcessYAMLOptions) (YamlPrinter, error)
@@ -72,6 +79,14 @@ type Client interface {
 	// DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 	DescribeCluster(ctx context.Context, options DescribeClusterOptions) (*tree.ObjectTree, error)
 
+	// DescribeClusters returns the object trees representing the status of all the Cluster API clusters in a namespace,
+	// or in all the namespaces.
+	DescribeClusters(ctx context.Context, options DescribeClusterOptions) ([]*tree.ObjectTree, error)
+
+	// CreateSupportBundle collects diagnostic information about a workload cluster, e.g. its Cluster API objects, the
+	// related events, the provider controller logs and the workload cluster's nodes, into a gzipped tarball.
+	CreateSupportBundle(ctx context.Context, options SupportBundleOptions) error
+
 	// AlphaClient is an Interface for alpha features in clusterctl
 	AlphaClient
 }
@@ -108,6 +123,7 @@ type clusterctlClient struct {
 	repositoryClientFactory RepositoryClientFactory
 	clusterClientFactory    ClusterClientFactory
 	alphaClient             alpha.Client
+	imageCopier             bundle.ImageCopier
 }
 
 // RepositoryClientFactoryInput represents the inputs required by the factory.
@@ -157,6 +173,14 @@ func InjectClusterClientFactory(factory ClusterClientFactory) Option {
 	}
 }
 
+// InjectImageCopier allows to override the default ImageCopier used for
+// adding container images to bundles and pushing them to a registry.
+func InjectImageCopier(imageCopier bundle.ImageCopier) Option {
+	return func(c *clusterctlClient) {
+		c.imageCopier = imageCopier
+	}
+}
+
 // New returns a configClient.
 func New(ctx context.Context, path string, options ...Option) (Client, error) {
 	return newClusterctlClient(ctx, path, options...)
@@ -194,6 +218,11 @@ func newClusterctlClient(ctx context.Context, path string, options ...Option) (*
 		client.alphaClient = c
 	}
 
+	// if there is an injected imageCopier, use it, otherwise use a default one.
+	if client.imageCopier == nil {
+		client.imageCopier = bundle.NewImageCopier()
+	}
+
 	return client, nil
 }
 
@@ -99,6 +99,10 @@ func (f fakeClient) Init(ctx context.Context, options InitOptions) ([]Components
 	return f.internalClient.Init(ctx, options)
 }
 
+func (f fakeClient) CreateBundle(ctx context.Context, options CreateBundleOptions) error {
+	return f.internalClient.CreateBundle(ctx, options)
+}
+
 func (f fakeClient) InitImages(ctx context.Context, options InitOptions) ([]string, error) {
 	return f.internalClient.InitImages(ctx, options)
 }
@@ -123,6 +127,10 @@ func (f fakeClient) ApplyUpgrade(ctx context.Context, options ApplyUpgradeOption
 	return f.internalClient.ApplyUpgrade(ctx, options)
 }
 
+func (f fakeClient) RollbackUpgrade(ctx context.Context, options RollbackUpgradeOptions) error {
+	return f.internalClient.RollbackUpgrade(ctx, options)
+}
+
 func (f fakeClient) ProcessYAML(ctx context.Context, options ProcessYAMLOptions) (YamlPrinter, error) {
 	return f.internalClient.ProcessYAML(ctx, options)
 }
@@ -135,6 +143,14 @@ func (f fakeClient) DescribeCluster(ctx context.Context, options DescribeCluster
 	return f.internalClient.DescribeCluster(ctx, options)
 }
 
+func (f fakeClient) DescribeClusters(ctx context.Context, options DescribeClusterOptions) ([]*tree.ObjectTree, error) {
+	return f.internalClient.DescribeClusters(ctx, options)
+}
+
+func (f fakeClient) CreateSupportBundle(ctx context.Context, options SupportBundleOptions) error {
+	return f.internalClient.CreateSupportBundle(ctx, options)
+}
+
 func (f fakeClient) RolloutPause(ctx context.Context, options RolloutPauseOptions) error {
 	return f.internalClient.RolloutPause(ctx, options)
 }
@@ -325,6 +341,10 @@ func (f *fakeClusterClient) Topology() cluster.TopologyClient {
 	return f.internalclient.Topology()
 }
 
+func (f *fakeClusterClient) SupportBundle() cluster.SupportBundleCollector {
+	return f.internalclient.SupportBundle()
+}
+
 func (f *fakeClusterClient) WithObjs(objs ...client.Object) *fakeClusterClient {
 	f.fakeProxy.WithObjs(objs...)
 	return f
@@ -387,6 +407,18 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) Verification() config.VerificationClient {
+	return f.internalclient.Verification()
+}
+
+func (f fakeConfigClient) Patches() config.PatchesClient {
+	return f.internalclient.Patches()
+}
+
+func (f fakeConfigClient) TemplateProcessors() config.TemplateProcessorsClient {
+	return f.internalclient.TemplateProcessors()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
@@ -470,6 +502,10 @@ func (f fakeRepositoryClient) Metadata(version string) repository.MetadataClient
 	}
 }
 
+func (f fakeRepositoryClient) GetFile(ctx context.Context, version, path string) ([]byte, error) {
+	return f.fakeRepository.GetFile(ctx, version, path)
+}
+
 func (f *fakeRepositoryClient) WithPaths(rootPath, componentsPath string) *fakeRepositoryClient {
 	f.fakeRepository.WithPaths(rootPath, componentsPath)
 	return f
@@ -806,6 +806,18 @@ func (f fakeConfigClient) ImageMeta() config.ImageMetaClient {
 	return f.internalclient.ImageMeta()
 }
 
+func (f fakeConfigClient) Verification() config.VerificationClient {
+	return f.internalclient.Verification()
+}
+
+func (f fakeConfigClient) Patches() config.PatchesClient {
+	return f.internalclient.Patches()
+}
+
+func (f fakeConfigClient) TemplateProcessors() config.TemplateProcessorsClient {
+	return f.internalclient.TemplateProcessors()
+}
+
 func (f *fakeConfigClient) WithVar(key, value string) *fakeConfigClient {
 	f.fakeReader.WithVar(key, value)
 	return f
@@ -82,6 +82,9 @@ type Client interface {
 
 	// Topology returns a TopologyClient that can be used for performing dry run executions of the topology reconciler.
 	Topology() TopologyClient
+
+	// SupportBundle returns a SupportBundleCollector that can be used for collecting diagnostic information about a workload cluster.
+	SupportBundle() SupportBundleCollector
 }
 
 // PollImmediateWaiter tries a condition func until it returns true, an error, or the timeout is reached.
@@ -147,6 +150,10 @@ func (c *clusterClient) Topology() TopologyClient {
 	return newTopologyClient(c.proxy, c.ProviderInventory())
 }
 
+func (c *clusterClient) SupportBundle() SupportBundleCollector {
+	return newSupportBundleCollector(c.proxy, c.ProviderInventory(), c.WorkloadCluster())
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*clusterClient)
 
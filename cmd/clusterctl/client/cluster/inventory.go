@@ -42,6 +42,9 @@ const (
 	waitInventoryCRDTimeout  = 1 * time.Minute
 )
 
+// managedProviderCRDName is the name of the ManagedProvider CRD, which is installed only in operator mode.
+var managedProviderCRDName = fmt.Sprintf("managedproviders.%s", clusterctlv1.GroupVersion.Group)
+
 // CheckCAPIContractOption is some configuration that modifies options for CheckCAPIContract.
 type CheckCAPIContractOption interface {
 	// Apply applies this configuration to the given CheckCAPIContractOptions.
@@ -96,6 +99,10 @@ type InventoryClient interface {
 	// is embedded in the clusterctl binary.
 	EnsureCustomResourceDefinitions(ctx context.Context) error
 
+	// EnsureManagedProviderCustomResourceDefinition installs the CRD required for creating ManagedProvider objects,
+	// if necessary. This CRD is required only when running clusterctl in operator mode.
+	EnsureManagedProviderCustomResourceDefinition(ctx context.Context) error
+
 	// Create an inventory item for a provider instance installed in the cluster.
 	Create(context.Context, clusterctlv1.Provider) error
 
@@ -175,20 +182,74 @@ func (p *inventoryClient) EnsureCustomResourceDefinitions(ctx context.Context) e
 
 	log.V(1).Info("Installing the clusterctl inventory CRD")
 
+	// The ManagedProvider CRD is installed only when running clusterctl in operator mode.
+	objs, err := clusterctlAPIObjects(func(o unstructured.Unstructured) bool {
+		return o.GetName() != managedProviderCRDName
+	})
+	if err != nil {
+		return err
+	}
+	return p.createCRDs(ctx, objs)
+}
+
+func (p *inventoryClient) EnsureManagedProviderCustomResourceDefinition(ctx context.Context) error {
+	log := logf.Log
+
+	// Check the CRD already exists, if yes, exit immediately.
+	// Nb. The operation is wrapped in a retry loop to make EnsureManagedProviderCustomResourceDefinition more resilient to unexpected conditions.
+	var crdIsInstalled bool
+	checkCRDBackoff := newReadBackoff()
+	if err := retryWithExponentialBackoff(ctx, checkCRDBackoff, func(ctx context.Context) error {
+		var err error
+		crdIsInstalled, err = checkManagedProviderCRD(ctx, p.proxy)
+		return err
+	}); err != nil {
+		return err
+	}
+	if crdIsInstalled {
+		return nil
+	}
+
+	log.V(1).Info("Installing the clusterctl ManagedProvider CRD")
+
+	objs, err := clusterctlAPIObjects(func(o unstructured.Unstructured) bool {
+		return o.GetName() == managedProviderCRDName
+	})
+	if err != nil {
+		return err
+	}
+	return p.createCRDs(ctx, objs)
+}
+
+// clusterctlAPIObjects returns the objects defined in the clusterctl API manifest embedded in the clusterctl binary
+// matching the given filter.
+func clusterctlAPIObjects(filter func(unstructured.Unstructured) bool) ([]unstructured.Unstructured, error) {
 	// Transform the yaml in a list of objects.
 	objs, err := utilyaml.ToUnstructured(config.ClusterctlAPIManifest)
 	if err != nil {
-		return errors.Wrap(err, "failed to parse yaml for clusterctl inventory CRDs")
+		return nil, errors.Wrap(err, "failed to parse yaml for clusterctl inventory CRDs")
+	}
+
+	filtered := []unstructured.Unstructured{}
+	for i := range objs {
+		if filter(objs[i]) {
+			filtered = append(filtered, objs[i])
+		}
 	}
+	return filtered, nil
+}
+
+// createCRDs creates the given objects, waiting for CRDs to be established.
+func (p *inventoryClient) createCRDs(ctx context.Context, objs []unstructured.Unstructured) error {
+	log := logf.Log
 
-	// Install the CRDs.
 	createInventoryObjectBackoff := newWriteBackoff()
 	for i := range objs {
 		o := objs[i]
 		log.V(5).Info("Creating", logf.UnstructuredToValues(o)...)
 
 		// Create the Kubernetes object.
-		// Nb. The operation is wrapped in a retry loop to make EnsureCustomResourceDefinitions more resilient to unexpected conditions.
+		// Nb. The operation is wrapped in a retry loop to make the install more resilient to unexpected conditions.
 		if err := retryWithExponentialBackoff(ctx, createInventoryObjectBackoff, func(ctx context.Context) error {
 			return p.createObj(ctx, o)
 		}); err != nil {
@@ -247,6 +308,22 @@ func checkInventoryCRDs(ctx context.Context, proxy Proxy) (bool, error) {
 	return true, errors.Errorf("clusterctl inventory CRD does not defines the %s version", clusterctlv1.GroupVersion.Version)
 }
 
+// checkManagedProviderCRD checks if the ManagedProvider CRD is installed in the cluster.
+func checkManagedProviderCRD(ctx context.Context, proxy Proxy) (bool, error) {
+	c, err := proxy.NewClient(ctx)
+	if err != nil {
+		return false, err
+	}
+
+	if err := c.Get(ctx, client.ObjectKey{Name: managedProviderCRDName}, &apiextensionsv1.CustomResourceDefinition{}); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrap(err, "failed to check if the clusterctl ManagedProvider CRD exists")
+	}
+	return true, nil
+}
+
 func (p *inventoryClient) createObj(ctx context.Context, o unstructured.Unstructured) error {
 	c, err := p.proxy.NewClient(ctx)
 	if err != nil {
@@ -297,10 +374,17 @@ func (p *inventoryClient) Create(ctx context.Context, m clusterctlv1.Provider) e
 
 		// otherwise patch the provider object
 		// NB. we are using client.Merge PatchOption so the new objects gets compared with the current one server side
+		// NB. patches are omitted when empty, so they must be removed explicitly when they are no longer applied.
+		removePatches := len(m.Patches) == 0 && len(currentProvider.Patches) > 0
 		m.SetResourceVersion(currentProvider.GetResourceVersion())
 		if err := cl.Patch(ctx, &m, client.Merge); err != nil {
 			return errors.Wrapf(err, "failed to patch provider object")
 		}
+		if removePatches {
+			if err := cl.Patch(ctx, &m, client.RawPatch(types.MergePatchType, []byte(`{"patches":null}`))); err != nil {
+				return errors.Wrapf(err, "failed to patch provider object")
+			}
+		}
 
 		return nil
 	})
@@ -86,6 +86,29 @@ func Test_inventoryClient_CheckInventoryCRDs(t *testing.T) {
 	}
 }
 
+func Test_inventoryClient_EnsureManagedProviderCustomResourceDefinition(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	proxy := test.NewFakeProxy()
+	p := newInventoryClient(proxy, fakePollImmediateWaiter)
+
+	// The ManagedProvider CRD is not installed together with the inventory CRD.
+	g.Expect(p.EnsureCustomResourceDefinitions(ctx)).To(Succeed())
+	installed, err := checkManagedProviderCRD(ctx, proxy)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(installed).To(BeFalse())
+
+	g.Expect(p.EnsureManagedProviderCustomResourceDefinition(ctx)).To(Succeed())
+	installed, err = checkManagedProviderCRD(ctx, proxy)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(installed).To(BeTrue())
+
+	// Ensuring the CRD again is a no-op.
+	g.Expect(p.EnsureManagedProviderCustomResourceDefinition(ctx)).To(Succeed())
+}
+
 var fooProvider = clusterctlv1.Provider{ObjectMeta: metav1.ObjectMeta{Name: "foo", Namespace: "ns1", ResourceVersion: "999"}}
 var v1alpha4Contract = "v1alpha4"
 
@@ -140,6 +163,13 @@ func Test_inventoryClient_Create(t *testing.T) {
 	// since this test object is used in a Create request, wherein setting ResourceVersion should no be set
 	providerV2.ResourceVersion = ""
 	providerV3 := fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v0.3.0", "")
+	providerV3WithPatches := fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v0.3.0", "")
+	providerV3WithPatches.Patches = []clusterctlv1.ProviderPatch{
+		{
+			Target: &clusterctlv1.PatchSelector{Kind: "Deployment"},
+			Patch:  "- op: add\n  path: /spec/replicas\n  value: 2\n",
+		},
+	}
 
 	tests := []struct {
 		name          string
@@ -174,6 +204,32 @@ func Test_inventoryClient_Create(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "Patches a provider recording the patches applied to its components",
+			fields: fields{
+				proxy: test.NewFakeProxy().WithObjs(&providerV2),
+			},
+			args: args{
+				m: providerV3WithPatches,
+			},
+			wantProviders: []clusterctlv1.Provider{
+				providerV3WithPatches,
+			},
+			wantErr: false,
+		},
+		{
+			name: "Patches a provider removing the patches no longer applied to its components",
+			fields: fields{
+				proxy: test.NewFakeProxy().WithObjs(providerV3WithPatches.DeepCopy()),
+			},
+			args: args{
+				m: providerV3,
+			},
+			wantProviders: []clusterctlv1.Provider{
+				providerV3,
+			},
+			wantErr: false,
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
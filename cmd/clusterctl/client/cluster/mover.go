@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
@@ -28,6 +29,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -48,13 +50,76 @@ import (
 // ResourceMutatorFunc holds the type for mutators to be applied on resources during a move operation.
 type ResourceMutatorFunc func(u *unstructured.Unstructured) error
 
+// MoveFilter restricts a move operation to a subset of the Clusters existing in a namespace, together with the objects
+// they depend on, e.g. ClusterClasses, templates, secrets and identities.
+// All the Clusters are moved if both ClusterNames and ClusterSelector are empty.
+type MoveFilter struct {
+	// ClusterNames is the list of names of the Clusters to be moved; names are looked up in the namespace being moved,
+	// and they can be qualified as <namespace>/<name> to select a Cluster when moving all the namespaces.
+	ClusterNames []string
+
+	// ClusterSelector selects the Clusters to be moved by labels, in addition to the Clusters listed in ClusterNames.
+	ClusterSelector labels.Selector
+
+	// CopySharedObjects allows to move Clusters depending on objects which are in use also by Clusters not being moved,
+	// e.g. a ClusterClass; shared objects are copied to the target management cluster and kept in the source management cluster.
+	// If false, the move is refused when there are shared objects.
+	CopySharedObjects bool
+}
+
+func (f MoveFilter) isEmpty() bool {
+	return len(f.ClusterNames) == 0 && (f.ClusterSelector == nil || f.ClusterSelector.Empty())
+}
+
+// matches returns true if a node referring to a Cluster object in the namespace being moved
+// (or in any namespace, if empty) is selected by the filter.
+func (f MoveFilter) matches(namespace string, cluster *node) bool {
+	for _, name := range f.ClusterNames {
+		if clusterNameMatches(namespace, name, cluster) {
+			return true
+		}
+	}
+	if f.ClusterSelector == nil || f.ClusterSelector.Empty() {
+		return false
+	}
+	clusterLabels, _ := cluster.additionalInfo[clusterLabelsKey].(labels.Set)
+	return f.ClusterSelector.Matches(clusterLabels)
+}
+
+// clusterNameMatches returns true if a node referring to a Cluster object has the given name, either in the form
+// <name> or <namespace>/<name>, in the namespace being moved (or in any namespace, if empty).
+func clusterNameMatches(namespace, name string, cluster *node) bool {
+	if ns, n, ok := strings.Cut(name, "/"); ok {
+		if namespace != "" && ns != namespace {
+			return false
+		}
+		namespace, name = ns, n
+	}
+	if namespace != "" && cluster.identity.Namespace != namespace {
+		return false
+	}
+	return cluster.identity.Name == name
+}
+
 // ObjectMover defines methods for moving Cluster API objects to another management cluster.
 type ObjectMover interface {
-	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
-	Move(ctx context.Context, namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
+	// Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster;
+	// the filter allows to move only a subset of the Clusters.
+	Move(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error
 
-	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory.
-	ToDirectory(ctx context.Context, namespace string, directory string) error
+	// ResumeMove resumes a move operation for a namespace (or for all the namespaces if empty) which did not complete, e.g. because of an error,
+	// continuing from the last completed step recorded in the move journal stored in the target management cluster;
+	// the filter must select the same Clusters selected by the move operation.
+	ResumeMove(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, mutators ...ResourceMutatorFunc) error
+
+	// AbortMove aborts a move operation for a namespace (or for all the namespaces if empty) which did not complete, e.g. because of an error,
+	// by deleting the objects already created in the target management cluster and resuming the Clusters in the source management cluster;
+	// the filter must select the same Clusters selected by the move operation.
+	AbortMove(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, mutators ...ResourceMutatorFunc) error
+
+	// ToDirectory writes all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target directory;
+	// the filter allows to write only a subset of the Clusters.
+	ToDirectory(ctx context.Context, namespace string, filter MoveFilter, directory string) error
 
 	// FromDirectory reads all the Cluster API objects existing in a configured directory to a target management cluster.
 	FromDirectory(ctx context.Context, toCluster Client, directory string) error
@@ -65,12 +130,13 @@ type objectMover struct {
 	fromProxy             Proxy
 	fromProviderInventory InventoryClient
 	dryRun                bool
+	journalClient         *moveJournalClient
 }
 
 // ensure objectMover implements the ObjectMover interface.
 var _ ObjectMover = &objectMover{}
 
-func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
+func (o *objectMover) Move(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, dryRun bool, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 	log.Info("Performing move...")
 	o.dryRun = dryRun
@@ -87,11 +153,30 @@ func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Clie
 		}
 	}
 
-	objectGraph, err := o.getObjectGraph(ctx, namespace)
+	// checks that there is not a previous move operation which did not complete, and prepare for recording the progress of this move operation.
+	if !o.dryRun {
+		o.journalClient = newMoveJournalClient(toCluster.Proxy(), namespace, filter)
+		journal, err := o.journalClient.Get(ctx)
+		if err != nil {
+			return err
+		}
+		if journal != nil {
+			return errors.New("a previous move operation for the same Clusters did not complete; it must be resumed or aborted before starting a new move operation")
+		}
+	}
+
+	objectGraph, err := o.getObjectGraph(ctx, namespace, filter)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
 
+	// During a partial move, refuse to move objects which are in use also by Clusters not being moved, unless explicitly allowed.
+	if !filter.CopySharedObjects {
+		if err := objectGraph.checkSharedNodes(); err != nil {
+			return err
+		}
+	}
+
 	// Move the objects to the target cluster.
 	var proxy Proxy
 	if !o.dryRun {
@@ -101,11 +186,43 @@ func (o *objectMover) Move(ctx context.Context, namespace string, toCluster Clie
 	return o.move(ctx, objectGraph, proxy, mutators...)
 }
 
-func (o *objectMover) ToDirectory(ctx context.Context, namespace string, directory string) error {
+func (o *objectMover) ResumeMove(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, mutators ...ResourceMutatorFunc) error {
+	log := logf.Log
+	log.Info("Resuming move...")
+
+	o.journalClient = newMoveJournalClient(toCluster.Proxy(), namespace, filter)
+	journal, err := o.journalClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if journal == nil {
+		return errors.New("failed to find a move operation to resume; the namespace and the selected Clusters must be the same used for the move operation")
+	}
+
+	return o.runMove(ctx, journal.toMoveSequence(), journal, toCluster.Proxy(), mutators...)
+}
+
+func (o *objectMover) AbortMove(ctx context.Context, namespace string, filter MoveFilter, toCluster Client, mutators ...ResourceMutatorFunc) error {
+	log := logf.Log
+	log.Info("Aborting move...")
+
+	o.journalClient = newMoveJournalClient(toCluster.Proxy(), namespace, filter)
+	journal, err := o.journalClient.Get(ctx)
+	if err != nil {
+		return err
+	}
+	if journal == nil {
+		return errors.New("failed to find a move operation to abort; the namespace and the selected Clusters must be the same used for the move operation")
+	}
+
+	return o.abortMove(ctx, journal.toMoveSequence(), journal, toCluster.Proxy(), mutators...)
+}
+
+func (o *objectMover) ToDirectory(ctx context.Context, namespace string, filter MoveFilter, directory string) error {
 	log := logf.Log
 	log.Info("Moving to directory...")
 
-	objectGraph, err := o.getObjectGraph(ctx, namespace)
+	objectGraph, err := o.getObjectGraph(ctx, namespace, filter)
 	if err != nil {
 		return errors.Wrap(err, "failed to get object graph")
 	}
@@ -184,7 +301,7 @@ func (o *objectMover) filesToObjs(dir string) ([]unstructured.Unstructured, erro
 	return objs, nil
 }
 
-func (o *objectMover) getObjectGraph(ctx context.Context, namespace string) (*objectGraph, error) {
+func (o *objectMover) getObjectGraph(ctx context.Context, namespace string, filter MoveFilter) (*objectGraph, error) {
 	objectGraph := newObjectGraph(o.fromProxy, o.fromProviderInventory)
 
 	// Gets all the types defined by the CRDs installed by clusterctl plus the ConfigMap/Secret core types.
@@ -200,6 +317,11 @@ func (o *objectMover) getObjectGraph(ctx context.Context, namespace string) (*ob
 		return nil, errors.Wrap(err, "failed to discover the object graph")
 	}
 
+	// Restricts the object graph to the selected Clusters, if any.
+	if err := objectGraph.filterClusters(namespace, filter); err != nil {
+		return nil, errors.Wrap(err, "failed to select the Clusters to move")
+	}
+
 	// Checks if Cluster API has already completed the provisioning of the infrastructure for the objects involved in the move/toDirectory operation.
 	// This is required because if the infrastructure is provisioned, then we can reasonably assume that the objects we are moving/backing up are
 	// not currently waiting for long-running reconciliation loops, and so we can safely rely on the pause field on the Cluster object
@@ -316,54 +438,92 @@ func getMachineObj(ctx context.Context, proxy Proxy, machine *node, machineObj *
 
 // Move moves all the Cluster API objects existing in a namespace (or from all the namespaces if empty) to a target management cluster.
 func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	// Define the move sequence by processing the ownerReference chain, so we ensure that a Kubernetes object is moved only after its owners.
+	// The sequence is bases on object graph nodes, each one representing a Kubernetes object; nodes are grouped, so bulk of nodes can be moved in parallel. e.g.
+	// - All the Clusters should be moved first (group 1, processed in parallel)
+	// - All the MachineDeployments should be moved second (group 1, processed in parallel)
+	// - then all the MachineSets, then all the Machines, etc.
+	moveSequence := getMoveSequence(graph)
+
+	return o.runMove(ctx, moveSequence, newMoveJournal(moveSequence), toProxy, mutators...)
+}
+
+// runMove moves the objects in a move sequence, recording the progress in the move journal, so it is possible to
+// resume the move operation from the last completed step or to abort it in case of errors.
+func (o *objectMover) runMove(ctx context.Context, moveSequence *moveSequence, journal *moveJournal, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
 	log := logf.Log
 
-	clusters := graph.getClusters()
+	clusters := moveSequence.getClusters()
 	log.Info("Moving Cluster API objects", "Clusters", len(clusters))
 
-	clusterClasses := graph.getClusterClasses()
+	// NOTE: ClusterClasses shared with Clusters not being moved are not paused, because they are copied to the
+	// target cluster and they must keep working in the source cluster.
+	clusterClasses := moveSequence.getClusterClasses()
 	log.Info("Moving Cluster API objects", "ClusterClasses", len(clusterClasses))
 
-	// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
-	log.V(1).Info("Pausing the source cluster")
-	if err := setClusterPause(ctx, o.fromProxy, clusters, true, o.dryRun); err != nil {
-		return err
+	if shared := moveSequence.getSharedNodes(); len(shared) > 0 {
+		log.Info("Copying objects shared with Clusters not being moved", "Count", len(shared))
 	}
 
-	log.V(1).Info("Pausing the source ClusterClasses")
-	if err := setClusterClassPause(ctx, o.fromProxy, clusterClasses, true, o.dryRun); err != nil {
-		return errors.Wrap(err, "error pausing ClusterClasses")
+	// Records the move sequence before making any change, so it is possible to resume or abort the move operation.
+	if err := o.saveJournal(ctx, journal); err != nil {
+		return err
 	}
 
-	log.Info("Waiting for all resources to be ready to move")
-	// exponential backoff configuration which returns durations for a total time of ~2m.
-	// Example: 0, 5s, 8s, 11s, 17s, 26s, 38s, 57s, 86s, 128s
-	waitForMoveUnblockedBackoff := wait.Backoff{
-		Duration: 5 * time.Second,
-		Factor:   1.5,
-		Steps:    10,
-		Jitter:   0.1,
+	// Nb. objects are deleted from the source cluster only after all the objects are created in the target cluster, so
+	// when resuming a move operation, the source Clusters and ClusterClasses exist until the first group is deleted.
+	if journal.DeletedGroups == 0 {
+		// Sets the pause field on the Cluster object in the source management cluster, so the controllers stop reconciling it.
+		log.V(1).Info("Pausing the source cluster")
+		if err := setClusterPause(ctx, o.fromProxy, clusters, true, o.dryRun); err != nil {
+			return err
+		}
+
+		log.V(1).Info("Pausing the source ClusterClasses")
+		if err := setClusterClassPause(ctx, o.fromProxy, clusterClasses, true, o.dryRun); err != nil {
+			return errors.Wrap(err, "error pausing ClusterClasses")
+		}
 	}
-	if err := waitReadyForMove(ctx, o.fromProxy, graph.getMoveNodes(), o.dryRun, waitForMoveUnblockedBackoff); err != nil {
-		return errors.Wrap(err, "error waiting for resources to be ready to move")
+
+	if journal.CreatedGroups == 0 {
+		log.Info("Waiting for all resources to be ready to move")
+		// exponential backoff configuration which returns durations for a total time of ~2m.
+		// Example: 0, 5s, 8s, 11s, 17s, 26s, 38s, 57s, 86s, 128s
+		waitForMoveUnblockedBackoff := wait.Backoff{
+			Duration: 5 * time.Second,
+			Factor:   1.5,
+			Steps:    10,
+			Jitter:   0.1,
+		}
+		if err := waitReadyForMove(ctx, o.fromProxy, moveSequence.getNodes(), o.dryRun, waitForMoveUnblockedBackoff); err != nil {
+			return errors.Wrap(err, "error waiting for resources to be ready to move")
+		}
 	}
 
 	// Nb. DO NOT call ensureNamespaces at this point because:
 	// - namespace will be ensured to exist before creating the resource.
 	// - If it's done here, we might create a namespace that can end up unused on target cluster (due to mutators).
 
-	// Define the move sequence by processing the ownerReference chain, so we ensure that a Kubernetes object is moved only after its owners.
-	// The sequence is bases on object graph nodes, each one representing a Kubernetes object; nodes are grouped, so bulk of nodes can be moved in parallel. e.g.
-	// - All the Clusters should be moved first (group 1, processed in parallel)
-	// - All the MachineDeployments should be moved second (group 1, processed in parallel)
-	// - then all the MachineSets, then all the Machines, etc.
-	moveSequence := getMoveSequence(graph)
+	if journal.CreatedGroups < len(moveSequence.groups) {
+		// When resuming a move operation, reads the UIDs of the objects already created in the target cluster, so the
+		// ownerReferences to them can be re-created.
+		for groupIndex := 0; groupIndex < journal.CreatedGroups; groupIndex++ {
+			if err := o.readTargetGroup(ctx, moveSequence.getGroup(groupIndex), toProxy, mutators...); err != nil {
+				return err
+			}
+		}
 
-	// Create all objects group by group, ensuring all the ownerReferences are re-created.
-	log.Info("Creating objects in the target cluster")
-	for groupIndex := 0; groupIndex < len(moveSequence.groups); groupIndex++ {
-		if err := o.createGroup(ctx, moveSequence.getGroup(groupIndex), toProxy, mutators...); err != nil {
-			return err
+		// Create all objects group by group, ensuring all the ownerReferences are re-created.
+		log.Info("Creating objects in the target cluster")
+		for groupIndex := journal.CreatedGroups; groupIndex < len(moveSequence.groups); groupIndex++ {
+			if err := o.createGroup(ctx, moveSequence.getGroup(groupIndex), toProxy, mutators...); err != nil {
+				return err
+			}
+
+			journal.CreatedGroups = groupIndex + 1
+			if err := o.saveJournal(ctx, journal); err != nil {
+				return err
+			}
 		}
 	}
 
@@ -373,10 +533,15 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 
 	// Delete all objects group by group in reverse order.
 	log.Info("Deleting objects from the source cluster")
-	for groupIndex := len(moveSequence.groups) - 1; groupIndex >= 0; groupIndex-- {
+	for groupIndex := len(moveSequence.groups) - 1 - journal.DeletedGroups; groupIndex >= 0; groupIndex-- {
 		if err := o.deleteGroup(ctx, moveSequence.getGroup(groupIndex)); err != nil {
 			return err
 		}
+
+		journal.DeletedGroups = len(moveSequence.groups) - groupIndex
+		if err := o.saveJournal(ctx, journal); err != nil {
+			return err
+		}
 	}
 
 	// Resume the ClusterClasses in the target management cluster, so the controllers start reconciling it.
@@ -387,7 +552,65 @@ func (o *objectMover) move(ctx context.Context, graph *objectGraph, toProxy Prox
 
 	// Reset the pause field on the Cluster object in the target management cluster, so the controllers start reconciling it.
 	log.V(1).Info("Resuming the target cluster")
-	return setClusterPause(ctx, toProxy, clusters, false, o.dryRun, mutators...)
+	if err := setClusterPause(ctx, toProxy, clusters, false, o.dryRun, mutators...); err != nil {
+		return err
+	}
+
+	// The move operation is completed, so the journal is not required anymore.
+	return o.deleteJournal(ctx)
+}
+
+// abortMove aborts a move operation by deleting the objects already created in the target management cluster
+// and by resuming the Clusters and the ClusterClasses in the source management cluster.
+func (o *objectMover) abortMove(ctx context.Context, moveSequence *moveSequence, journal *moveJournal, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	log := logf.Log
+
+	if journal.DeletedGroups > 0 {
+		return errors.New("the move operation cannot be aborted because objects have already been deleted from the source cluster; the move operation must be resumed instead")
+	}
+
+	// Delete all the objects created in the target cluster group by group in reverse order, including the group
+	// that was being created when the move operation stopped.
+	log.Info("Deleting objects from the target cluster")
+	lastGroupIndex := journal.CreatedGroups
+	if lastGroupIndex > len(moveSequence.groups)-1 {
+		lastGroupIndex = len(moveSequence.groups) - 1
+	}
+	for groupIndex := lastGroupIndex; groupIndex >= 0; groupIndex-- {
+		if err := o.deleteTargetGroup(ctx, moveSequence.getGroup(groupIndex), toProxy, mutators...); err != nil {
+			return err
+		}
+	}
+
+	// Resume the ClusterClasses in the source management cluster, so the controllers start reconciling it.
+	log.V(1).Info("Resuming the source ClusterClasses")
+	if err := setClusterClassPause(ctx, o.fromProxy, moveSequence.getClusterClasses(), false, o.dryRun); err != nil {
+		return errors.Wrap(err, "error resuming ClusterClasses")
+	}
+
+	// Reset the pause field on the Cluster object in the source management cluster, so the controllers start reconciling it.
+	log.V(1).Info("Resuming the source cluster")
+	if err := setClusterPause(ctx, o.fromProxy, moveSequence.getClusters(), false, o.dryRun); err != nil {
+		return err
+	}
+
+	return o.deleteJournal(ctx)
+}
+
+// saveJournal stores the move journal in the target cluster, if required.
+func (o *objectMover) saveJournal(ctx context.Context, journal *moveJournal) error {
+	if o.dryRun || o.journalClient == nil {
+		return nil
+	}
+	return o.journalClient.Save(ctx, journal)
+}
+
+// deleteJournal deletes the move journal from the target cluster, if required.
+func (o *objectMover) deleteJournal(ctx context.Context) error {
+	if o.dryRun || o.journalClient == nil {
+		return nil
+	}
+	return o.journalClient.Delete(ctx)
 }
 
 func (o *objectMover) toDirectory(ctx context.Context, graph *objectGraph, directory string) error {
@@ -505,6 +728,48 @@ func (s *moveSequence) getGroup(i int) moveGroup {
 	return s.groups[i]
 }
 
+// getNodes returns the list of nodes in the move sequence.
+func (s *moveSequence) getNodes() []*node {
+	nodes := []*node{}
+	for _, group := range s.groups {
+		nodes = append(nodes, group...)
+	}
+	return nodes
+}
+
+// getClusters returns the list of Clusters in the move sequence.
+func (s *moveSequence) getClusters() []*node {
+	clusters := []*node{}
+	for _, n := range s.getNodes() {
+		if isClusterNode(n) {
+			clusters = append(clusters, n)
+		}
+	}
+	return clusters
+}
+
+// getClusterClasses returns the list of ClusterClasses in the move sequence, excluding the ClusterClasses shared with Clusters not being moved.
+func (s *moveSequence) getClusterClasses() []*node {
+	clusterClasses := []*node{}
+	for _, n := range s.getNodes() {
+		if n.identity.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("ClusterClass").GroupKind() && !n.isShared {
+			clusterClasses = append(clusterClasses, n)
+		}
+	}
+	return clusterClasses
+}
+
+// getSharedNodes returns the list of nodes in the move sequence that are in use also by Clusters not being moved.
+func (s *moveSequence) getSharedNodes() []*node {
+	nodes := []*node{}
+	for _, n := range s.getNodes() {
+		if n.isShared {
+			nodes = append(nodes, n)
+		}
+	}
+	return nodes
+}
+
 // Define the move sequence by processing the ownerReference chain.
 func getMoveSequence(graph *objectGraph) *moveSequence {
 	moveSequence := &moveSequence{
@@ -998,6 +1263,9 @@ func (o *objectMover) createTargetObject(ctx context.Context, nodeToCreate *node
 		// If the object already exists, try to update it if it is node a global object / something belonging to a global object hierarchy (e.g. a secrets owned by a global identity object).
 		if nodeToCreate.isGlobal || nodeToCreate.isGlobalHierarchy {
 			log.V(5).Info("Object already exists, skipping upgrade because it is global/it is owned by a global object", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
+		} else if nodeToCreate.isShared {
+			// Nb. Shared objects might have been copied by a previous partial move, and they could be in use by Clusters in the target cluster.
+			log.V(5).Info("Object already exists, skipping upgrade because it is shared with Clusters not being moved", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
 		} else {
 			// Nb. This should not happen, but it is supported to make move more resilient to unexpected interrupt/restarts of the move process.
 			log.V(5).Info("Object already exists, updating", nodeToCreate.identity.Kind, nodeToCreate.identity.Name, "Namespace", nodeToCreate.identity.Namespace)
@@ -1138,6 +1406,128 @@ func (o *objectMover) restoreTargetObject(ctx context.Context, nodeToCreate *nod
 	return nil
 }
 
+// readTargetGroup reads the UIDs of the Kubernetes objects already created in the target management cluster corresponding to the object graph nodes in a moveGroup.
+func (o *objectMover) readTargetGroup(ctx context.Context, group moveGroup, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	readTargetObjectBackoff := newReadBackoff()
+	errList := []error{}
+
+	for _, nodeToRead := range group {
+		err := retryWithExponentialBackoff(ctx, readTargetObjectBackoff, func(ctx context.Context) error {
+			return o.readTargetObject(ctx, nodeToRead, toProxy, mutators...)
+		})
+		if err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// readTargetObject reads the UID of the Kubernetes object already created in the target management cluster corresponding to the object graph node.
+func (o *objectMover) readTargetObject(ctx context.Context, nodeToRead *node, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	if o.dryRun {
+		return nil
+	}
+
+	cTo, err := toProxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Get a mutated copy of the object to identify the target namespace.
+	obj, err := applyMutators(nodeToObject(nodeToRead), mutators...)
+	if err != nil {
+		return err
+	}
+
+	if err := cTo.Get(ctx, client.ObjectKeyFromObject(obj), obj); err != nil {
+		return errors.Wrapf(err, "error reading %q %s/%s",
+			obj.GroupVersionKind(), obj.GetNamespace(), obj.GetName())
+	}
+
+	// Stores the newUID assigned to the object created in the target cluster.
+	nodeToRead.newUID = obj.GetUID()
+	return nil
+}
+
+// deleteTargetGroup deletes all the Kubernetes objects from the target management cluster corresponding to the object graph nodes in a moveGroup.
+func (o *objectMover) deleteTargetGroup(ctx context.Context, group moveGroup, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	deleteTargetObjectBackoff := newWriteBackoff()
+	errList := []error{}
+
+	for _, nodeToDelete := range group {
+		// Nb. The operation is wrapped in a retry loop to make abort more resilient to unexpected conditions.
+		err := retryWithExponentialBackoff(ctx, deleteTargetObjectBackoff, func(ctx context.Context) error {
+			return o.deleteTargetObject(ctx, nodeToDelete, toProxy, mutators...)
+		})
+		if err != nil {
+			errList = append(errList, err)
+		}
+	}
+
+	return kerrors.NewAggregate(errList)
+}
+
+// deleteTargetObject deletes the Kubernetes object corresponding to the node from the target management cluster, taking care of removing all the finalizers so
+// the objects gets immediately deleted (force delete).
+func (o *objectMover) deleteTargetObject(ctx context.Context, nodeToDelete *node, toProxy Proxy, mutators ...ResourceMutatorFunc) error {
+	// Don't delete global objects or shared objects, because they could have existed in the target cluster before the move operation.
+	if nodeToDelete.isGlobal || nodeToDelete.isGlobalHierarchy || nodeToDelete.isShared {
+		return nil
+	}
+
+	log := logf.Log
+	log.V(1).Info("Deleting from target", nodeToDelete.identity.Kind, nodeToDelete.identity.Name, "Namespace", nodeToDelete.identity.Namespace)
+
+	if o.dryRun {
+		return nil
+	}
+
+	cTo, err := toProxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	// Get a mutated copy of the object to identify the target namespace.
+	targetObj, err := applyMutators(nodeToObject(nodeToDelete), mutators...)
+	if err != nil {
+		return err
+	}
+
+	if err := cTo.Get(ctx, client.ObjectKeyFromObject(targetObj), targetObj); err != nil {
+		if apierrors.IsNotFound(err) {
+			// If the object was not created, move on.
+			return nil
+		}
+		return errors.Wrapf(err, "error reading %q %s/%s",
+			targetObj.GroupVersionKind(), targetObj.GetNamespace(), targetObj.GetName())
+	}
+
+	if len(targetObj.GetFinalizers()) > 0 {
+		if err := cTo.Patch(ctx, targetObj, removeFinalizersPatch); err != nil {
+			return errors.Wrapf(err, "error removing finalizers from %q %s/%s",
+				targetObj.GroupVersionKind(), targetObj.GetNamespace(), targetObj.GetName())
+		}
+	}
+
+	if err := cTo.Delete(ctx, targetObj); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "error deleting %q %s/%s",
+			targetObj.GroupVersionKind(), targetObj.GetNamespace(), targetObj.GetName())
+	}
+
+	return nil
+}
+
+// nodeToObject returns an object with the identity of a node.
+func nodeToObject(n *node) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetAPIVersion(n.identity.APIVersion)
+	obj.SetKind(n.identity.Kind)
+	obj.SetNamespace(n.identity.Namespace)
+	obj.SetName(n.identity.Name)
+	return obj
+}
+
 // Recreate all the OwnerReferences using the newUID of the owner nodes.
 func (o *objectMover) buildOwnerChain(obj *unstructured.Unstructured, n *node) {
 	if len(n.owners) > 0 {
@@ -1196,6 +1586,11 @@ func (o *objectMover) deleteSourceObject(ctx context.Context, nodeToDelete *node
 		return nil
 	}
 
+	// Don't delete nodes in use also by Clusters not being moved (e.g. a ClusterClass shared with Clusters left in the source cluster).
+	if nodeToDelete.isShared {
+		return nil
+	}
+
 	log := logf.Log
 	log.V(1).Info("Deleting", nodeToDelete.identity.Kind, nodeToDelete.identity.Name, "Namespace", nodeToDelete.identity.Namespace)
 
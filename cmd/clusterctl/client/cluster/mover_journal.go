@@ -0,0 +1,291 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+)
+
+const (
+	// moveJournalNamespace is the namespace of the ConfigMaps storing move journals in the target management cluster.
+	moveJournalNamespace = metav1.NamespaceSystem
+
+	// moveJournalKey is the key of the ConfigMap data storing the move journal.
+	moveJournalKey = "journal"
+
+	// moveJournalMaxSize is the maximum size of the data of a ConfigMap, including keys, which limits the size of a move journal.
+	moveJournalMaxSize = 1024 * 1024
+)
+
+// moveJournal records the move sequence and the progress of a move operation, so it is possible
+// to resume or to abort the operation in case of errors.
+type moveJournal struct {
+	// Groups is the move sequence, as computed by the object graph at the beginning of the move operation.
+	Groups [][]moveJournalEntry `json:"groups"`
+
+	// CreatedGroups is the number of groups already created in the target management cluster.
+	CreatedGroups int `json:"createdGroups"`
+
+	// DeletedGroups is the number of groups already deleted from the source management cluster,
+	// starting from the last group in the move sequence.
+	DeletedGroups int `json:"deletedGroups"`
+}
+
+// moveJournalEntry records a node in the move sequence.
+type moveJournalEntry struct {
+	Identity          corev1.ObjectReference `json:"identity"`
+	Owners            []moveJournalOwner     `json:"owners,omitempty"`
+	IsGlobal          bool                   `json:"isGlobal,omitempty"`
+	IsGlobalHierarchy bool                   `json:"isGlobalHierarchy,omitempty"`
+	IsShared          bool                   `json:"isShared,omitempty"`
+	BlockingMove      bool                   `json:"blockingMove,omitempty"`
+}
+
+// moveJournalOwner records an owner of a node in the move sequence, identified by the UID in the source management cluster.
+type moveJournalOwner struct {
+	UID                types.UID `json:"uid"`
+	Controller         *bool     `json:"controller,omitempty"`
+	BlockOwnerDeletion *bool     `json:"blockOwnerDeletion,omitempty"`
+}
+
+// newMoveJournal returns a journal for a move operation processing the given move sequence.
+func newMoveJournal(sequence *moveSequence) *moveJournal {
+	journal := &moveJournal{
+		Groups: make([][]moveJournalEntry, 0, len(sequence.groups)),
+	}
+	for _, group := range sequence.groups {
+		entries := make([]moveJournalEntry, 0, len(group))
+		for _, n := range group {
+			entry := moveJournalEntry{
+				Identity:          n.identity,
+				IsGlobal:          n.isGlobal,
+				IsGlobalHierarchy: n.isGlobalHierarchy,
+				IsShared:          n.isShared,
+				BlockingMove:      n.blockingMove,
+			}
+			for owner, attributes := range n.owners {
+				entry.Owners = append(entry.Owners, moveJournalOwner{
+					UID:                owner.identity.UID,
+					Controller:         attributes.Controller,
+					BlockOwnerDeletion: attributes.BlockOwnerDeletion,
+				})
+			}
+			entries = append(entries, entry)
+		}
+		journal.Groups = append(journal.Groups, entries)
+	}
+	return journal
+}
+
+// toMoveSequence rebuilds the move sequence recorded in the journal.
+func (j *moveJournal) toMoveSequence() *moveSequence {
+	sequence := &moveSequence{
+		groups:   []moveGroup{},
+		nodesMap: make(map[*node]empty),
+	}
+
+	nodes := map[types.UID]*node{}
+	for _, entries := range j.Groups {
+		for _, entry := range entries {
+			nodes[entry.Identity.UID] = &node{
+				identity:          entry.Identity,
+				owners:            make(map[*node]ownerReferenceAttributes),
+				softOwners:        make(map[*node]empty),
+				tenant:            make(map[*node]empty),
+				isGlobal:          entry.IsGlobal,
+				isGlobalHierarchy: entry.IsGlobalHierarchy,
+				isShared:          entry.IsShared,
+				blockingMove:      entry.BlockingMove,
+			}
+		}
+	}
+
+	for _, entries := range j.Groups {
+		group := moveGroup{}
+		for _, entry := range entries {
+			n := nodes[entry.Identity.UID]
+			for _, owner := range entry.Owners {
+				// Nb. owners are always part of the move sequence, because a node is added to the sequence only after all its owners.
+				if ownerNode, ok := nodes[owner.UID]; ok {
+					n.addOwner(ownerNode, ownerReferenceAttributes{
+						Controller:         owner.Controller,
+						BlockOwnerDeletion: owner.BlockOwnerDeletion,
+					})
+				}
+			}
+			group = append(group, n)
+		}
+		sequence.addGroup(group)
+	}
+	return sequence
+}
+
+// moveJournalClient stores the journal of a move operation in a ConfigMap in the target management cluster.
+type moveJournalClient struct {
+	proxy Proxy
+	name  string
+}
+
+// newMoveJournalClient returns a moveJournalClient for the move operation of a namespace (or of all the namespaces if empty);
+// when the filter restricts the move to a subset of the Clusters, the journal is specific to the selected Clusters,
+// so move operations for different Clusters in the same namespace do not interfere with each other.
+func newMoveJournalClient(proxy Proxy, namespace string, filter MoveFilter) *moveJournalClient {
+	name := "clusterctl-move-all-namespaces"
+	if namespace != "" {
+		name = fmt.Sprintf("clusterctl-move-%s", namespace)
+	}
+	if !filter.isEmpty() {
+		name = fmt.Sprintf("%s-%s", name, moveJournalFilterHash(filter))
+	}
+	return &moveJournalClient{
+		proxy: proxy,
+		name:  name,
+	}
+}
+
+// moveJournalFilterHash returns a short hash identifying the Clusters selected by a filter; the order
+// in which Cluster names are provided does not matter.
+func moveJournalFilterHash(filter MoveFilter) string {
+	names := append([]string{}, filter.ClusterNames...)
+	sort.Strings(names)
+	selector := ""
+	if filter.ClusterSelector != nil {
+		selector = filter.ClusterSelector.String()
+	}
+	hash := sha256.Sum256([]byte(strings.Join(names, ",") + "\n" + selector))
+	return hex.EncodeToString(hash[:])[:10]
+}
+
+// Get returns the journal of the move operation, if any.
+func (c *moveJournalClient) Get(ctx context.Context) (*moveJournal, error) {
+	var journal *moveJournal
+	getJournalBackoff := newReadBackoff()
+	if err := retryWithExponentialBackoff(ctx, getJournalBackoff, func(ctx context.Context) error {
+		cs, err := c.proxy.NewClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		configMap := &corev1.ConfigMap{}
+		if err := cs.Get(ctx, client.ObjectKey{Namespace: moveJournalNamespace, Name: c.name}, configMap); err != nil {
+			if apierrors.IsNotFound(err) {
+				journal = nil
+				return nil
+			}
+			return errors.Wrapf(err, "failed to read the move journal %s/%s", moveJournalNamespace, c.name)
+		}
+
+		journal = &moveJournal{}
+		if err := json.Unmarshal([]byte(configMap.Data[moveJournalKey]), journal); err != nil {
+			return errors.Wrapf(err, "failed to parse the move journal %s/%s", moveJournalNamespace, c.name)
+		}
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	return journal, nil
+}
+
+// Save stores the journal of the move operation.
+func (c *moveJournalClient) Save(ctx context.Context, journal *moveJournal) error {
+	data, err := json.Marshal(journal)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal the move journal")
+	}
+	if size := len(moveJournalKey) + len(data); size > moveJournalMaxSize {
+		return errors.Errorf("the move journal is too big to be stored in a ConfigMap (%d bytes, maximum %d bytes); "+
+			"move a subset of the Clusters at a time, e.g. using the --cluster or --selector flags", size, moveJournalMaxSize)
+	}
+
+	saveJournalBackoff := newWriteBackoff()
+	return retryWithExponentialBackoff(ctx, saveJournalBackoff, func(ctx context.Context) error {
+		cs, err := c.proxy.NewClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		configMap := &corev1.ConfigMap{}
+		key := client.ObjectKey{Namespace: moveJournalNamespace, Name: c.name}
+		if err := cs.Get(ctx, key, configMap); err != nil {
+			if !apierrors.IsNotFound(err) {
+				return errors.Wrapf(err, "failed to read the move journal %s/%s", moveJournalNamespace, c.name)
+			}
+
+			configMap = &corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Namespace: moveJournalNamespace,
+					Name:      c.name,
+					Labels: map[string]string{
+						clusterctlv1.ClusterctlLabel: "",
+					},
+				},
+				Data: map[string]string{
+					moveJournalKey: string(data),
+				},
+			}
+			if err := cs.Create(ctx, configMap); err != nil {
+				return errors.Wrapf(err, "failed to create the move journal %s/%s", moveJournalNamespace, c.name)
+			}
+			return nil
+		}
+
+		configMap.Data = map[string]string{
+			moveJournalKey: string(data),
+		}
+		if err := cs.Update(ctx, configMap); err != nil {
+			return errors.Wrapf(err, "failed to update the move journal %s/%s", moveJournalNamespace, c.name)
+		}
+		return nil
+	})
+}
+
+// Delete deletes the journal of the move operation.
+func (c *moveJournalClient) Delete(ctx context.Context) error {
+	deleteJournalBackoff := newWriteBackoff()
+	return retryWithExponentialBackoff(ctx, deleteJournalBackoff, func(ctx context.Context) error {
+		cs, err := c.proxy.NewClient(ctx)
+		if err != nil {
+			return err
+		}
+
+		configMap := &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: moveJournalNamespace,
+				Name:      c.name,
+			},
+		}
+		if err := cs.Delete(ctx, configMap); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete the move journal %s/%s", moveJournalNamespace, c.name)
+		}
+		return nil
+	})
+}
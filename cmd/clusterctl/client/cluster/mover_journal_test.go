@@ -0,0 +1,386 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func moveJournalTestObjs() []client.Object {
+	return test.NewFakeCluster("ns1", "cluster1").
+		WithMachineDeployments(
+			test.NewFakeMachineDeployment("md1").
+				WithMachineSets(
+					test.NewFakeMachineSet("ms1").
+						WithMachines(
+							test.NewFakeMachine("m1"),
+							test.NewFakeMachine("m2"),
+						),
+				),
+		).Objs()
+}
+
+func getMoveJournalTestGraph(t *testing.T, objs []client.Object) *objectGraph {
+	t.Helper()
+
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	graph := getObjectGraphWithObjs(objs)
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+	return graph
+}
+
+func Test_moveJournal_toMoveSequence(t *testing.T) {
+	g := NewWithT(t)
+
+	graph := getMoveJournalTestGraph(t, moveJournalTestObjs())
+	moveSequence := getMoveSequence(graph)
+
+	// Round trip the journal, as if it was read from the target cluster.
+	data, err := json.Marshal(newMoveJournal(moveSequence))
+	g.Expect(err).ToNot(HaveOccurred())
+	journal := &moveJournal{}
+	g.Expect(json.Unmarshal(data, journal)).To(Succeed())
+
+	got := journal.toMoveSequence()
+	g.Expect(got.groups).To(HaveLen(len(moveSequence.groups)))
+	for i := range moveSequence.groups {
+		wantGroup := map[string][]string{}
+		for _, n := range moveSequence.getGroup(i) {
+			owners := []string{}
+			for owner := range n.owners {
+				owners = append(owners, string(owner.identity.UID))
+			}
+			wantGroup[string(n.identity.UID)] = owners
+		}
+
+		gotGroup := map[string][]string{}
+		for _, n := range got.getGroup(i) {
+			owners := []string{}
+			for owner := range n.owners {
+				owners = append(owners, string(owner.identity.UID))
+			}
+			gotGroup[string(n.identity.UID)] = owners
+		}
+
+		g.Expect(gotGroup).To(HaveLen(len(wantGroup)))
+		for uid, owners := range wantGroup {
+			g.Expect(gotGroup).To(HaveKey(uid))
+			g.Expect(gotGroup[uid]).To(ConsistOf(owners))
+		}
+	}
+}
+
+func Test_moveJournalClient(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	journalClient := newMoveJournalClient(test.NewFakeProxy(), "ns1", MoveFilter{})
+
+	journal, err := journalClient.Get(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal).To(BeNil())
+
+	want := &moveJournal{
+		Groups: [][]moveJournalEntry{
+			{
+				{Identity: newTestObjectReference("cluster.x-k8s.io/v1beta1", "Cluster", "ns1", "cluster1")},
+			},
+		},
+	}
+	g.Expect(journalClient.Save(ctx, want)).To(Succeed())
+
+	want.CreatedGroups = 1
+	g.Expect(journalClient.Save(ctx, want)).To(Succeed())
+
+	journal, err = journalClient.Get(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal).To(BeComparableTo(want))
+
+	g.Expect(journalClient.Delete(ctx)).To(Succeed())
+
+	journal, err = journalClient.Get(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal).To(BeNil())
+}
+
+func Test_newMoveJournalClient(t *testing.T) {
+	g := NewWithT(t)
+
+	proxy := test.NewFakeProxy()
+	g.Expect(newMoveJournalClient(proxy, "", MoveFilter{}).name).To(Equal("clusterctl-move-all-namespaces"))
+	g.Expect(newMoveJournalClient(proxy, "ns1", MoveFilter{}).name).To(Equal("clusterctl-move-ns1"))
+	g.Expect(newMoveJournalClient(proxy, "ns1", MoveFilter{CopySharedObjects: true}).name).To(Equal("clusterctl-move-ns1"))
+
+	// Move operations for different Clusters in the same namespace use different journals.
+	cluster1 := newMoveJournalClient(proxy, "ns1", MoveFilter{ClusterNames: []string{"cluster1"}}).name
+	cluster2 := newMoveJournalClient(proxy, "ns1", MoveFilter{ClusterNames: []string{"cluster2"}}).name
+	selector := newMoveJournalClient(proxy, "ns1", MoveFilter{ClusterSelector: labels.SelectorFromSet(labels.Set{"env": "dev"})}).name
+	g.Expect(cluster1).To(HavePrefix("clusterctl-move-ns1-"))
+	g.Expect(sets.New(cluster1, cluster2, selector, "clusterctl-move-ns1")).To(HaveLen(4))
+
+	// The same Clusters use the same journal, irrespective of the order of the names.
+	g.Expect(newMoveJournalClient(proxy, "ns1", MoveFilter{ClusterNames: []string{"cluster1", "cluster2"}}).name).
+		To(Equal(newMoveJournalClient(proxy, "ns1", MoveFilter{ClusterNames: []string{"cluster2", "cluster1"}}).name))
+}
+
+func Test_moveJournalClient_SaveTooBig(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	journalClient := newMoveJournalClient(test.NewFakeProxy(), "ns1", MoveFilter{})
+
+	journal := &moveJournal{Groups: [][]moveJournalEntry{{}}}
+	for i := 0; len(journal.Groups[0])*100 < moveJournalMaxSize; i++ {
+		journal.Groups[0] = append(journal.Groups[0], moveJournalEntry{
+			Identity: newTestObjectReference("cluster.x-k8s.io/v1beta1", "Machine", "ns1", fmt.Sprintf("machine%d", i)),
+		})
+	}
+	err := journalClient.Save(ctx, journal)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("the move journal is too big"))
+
+	journal, err = journalClient.Get(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(journal).To(BeNil())
+}
+
+func Test_objectMover_resumeMove(t *testing.T) {
+	tests := []struct {
+		name          string
+		createdGroups int
+		deletedGroups int
+	}{
+		{
+			name:          "resume a move stopped while creating objects in the target cluster",
+			createdGroups: 2,
+		},
+		{
+			name:          "resume a move stopped while deleting objects from the source cluster",
+			createdGroups: -1, // all
+			deletedGroups: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			objs := moveJournalTestObjs()
+			graph := getMoveJournalTestGraph(t, objs)
+			toProxy := getFakeProxyWithCRDs()
+			journalClient := newMoveJournalClient(toProxy, "ns1", MoveFilter{})
+
+			// Simulate a move operation that stopped halfway.
+			mover := objectMover{
+				fromProxy:     graph.proxy,
+				journalClient: journalClient,
+			}
+			moveSequence := getMoveSequence(graph)
+			journal := newMoveJournal(moveSequence)
+			g.Expect(setClusterPause(ctx, graph.proxy, graph.getClusters(), true, false)).To(Succeed())
+
+			createdGroups := tt.createdGroups
+			if createdGroups < 0 {
+				createdGroups = len(moveSequence.groups)
+			}
+			for i := 0; i < createdGroups; i++ {
+				g.Expect(mover.createGroup(ctx, moveSequence.getGroup(i), toProxy)).To(Succeed())
+			}
+			for i := 0; i < tt.deletedGroups; i++ {
+				g.Expect(mover.deleteGroup(ctx, moveSequence.getGroup(len(moveSequence.groups)-1-i))).To(Succeed())
+			}
+			journal.CreatedGroups = createdGroups
+			journal.DeletedGroups = tt.deletedGroups
+			g.Expect(journalClient.Save(ctx, journal)).To(Succeed())
+
+			// Resume the move operation with a new mover, reading the move sequence from the journal.
+			savedJournal, err := journalClient.Get(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(savedJournal).ToNot(BeNil())
+
+			resumeMover := objectMover{
+				fromProxy:     graph.proxy,
+				journalClient: journalClient,
+			}
+			g.Expect(resumeMover.runMove(ctx, savedJournal.toMoveSequence(), savedJournal, toProxy)).To(Succeed())
+
+			// Check all the objects are moved, and the owner references are re-created in the target cluster.
+			csFrom, err := graph.proxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			csTo, err := toProxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			for _, o := range objs {
+				key := client.ObjectKeyFromObject(o)
+
+				oFrom := &unstructured.Unstructured{}
+				oFrom.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+				g.Expect(apierrors.IsNotFound(csFrom.Get(ctx, key, oFrom))).To(BeTrue(), "%s %v not deleted in source cluster", o.GetObjectKind().GroupVersionKind().Kind, key)
+
+				oTo := &unstructured.Unstructured{}
+				oTo.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+				g.Expect(csTo.Get(ctx, key, oTo)).To(Succeed(), "%s %v not created in target cluster", o.GetObjectKind().GroupVersionKind().Kind, key)
+				g.Expect(oTo.GetOwnerReferences()).To(HaveLen(len(o.GetOwnerReferences())))
+				for _, ref := range oTo.GetOwnerReferences() {
+					g.Expect(ref.UID).ToNot(BeEmpty())
+				}
+			}
+
+			// Check the Cluster is resumed in the target cluster and the journal is deleted.
+			cluster := &clusterv1.Cluster{}
+			g.Expect(csTo.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, cluster)).To(Succeed())
+			g.Expect(cluster.Spec.Paused).To(BeFalse())
+
+			savedJournal, err = journalClient.Get(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(savedJournal).To(BeNil())
+		})
+	}
+}
+
+func Test_objectMover_abortMove(t *testing.T) {
+	tests := []struct {
+		name          string
+		createdGroups int
+		deletedGroups int
+		wantErr       bool
+	}{
+		{
+			name:          "abort a move stopped before creating objects in the target cluster",
+			createdGroups: 0,
+		},
+		{
+			name:          "abort a move stopped while creating objects in the target cluster",
+			createdGroups: 2,
+		},
+		{
+			name:          "abort a move stopped after creating all the objects in the target cluster",
+			createdGroups: -1, // all
+		},
+		{
+			name:          "fails to abort a move stopped while deleting objects from the source cluster",
+			createdGroups: -1, // all
+			deletedGroups: 1,
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			objs := moveJournalTestObjs()
+			graph := getMoveJournalTestGraph(t, objs)
+			toProxy := getFakeProxyWithCRDs()
+			journalClient := newMoveJournalClient(toProxy, "ns1", MoveFilter{})
+
+			// Simulate a move operation that stopped halfway.
+			mover := objectMover{
+				fromProxy:     graph.proxy,
+				journalClient: journalClient,
+			}
+			moveSequence := getMoveSequence(graph)
+			journal := newMoveJournal(moveSequence)
+			g.Expect(setClusterPause(ctx, graph.proxy, graph.getClusters(), true, false)).To(Succeed())
+
+			createdGroups := tt.createdGroups
+			if createdGroups < 0 {
+				createdGroups = len(moveSequence.groups)
+			}
+			for i := 0; i < createdGroups; i++ {
+				g.Expect(mover.createGroup(ctx, moveSequence.getGroup(i), toProxy)).To(Succeed())
+			}
+			journal.CreatedGroups = createdGroups
+			journal.DeletedGroups = tt.deletedGroups
+			g.Expect(journalClient.Save(ctx, journal)).To(Succeed())
+
+			savedJournal, err := journalClient.Get(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(savedJournal).ToNot(BeNil())
+
+			abortMover := objectMover{
+				fromProxy:     graph.proxy,
+				journalClient: journalClient,
+			}
+			err = abortMover.abortMove(ctx, savedJournal.toMoveSequence(), savedJournal, toProxy)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// Check all the objects are deleted from the target cluster and kept in the source cluster.
+			csFrom, err := graph.proxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			csTo, err := toProxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			for _, o := range objs {
+				key := client.ObjectKeyFromObject(o)
+
+				oFrom := &unstructured.Unstructured{}
+				oFrom.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+				g.Expect(csFrom.Get(ctx, key, oFrom)).To(Succeed(), "%s %v not kept in source cluster", o.GetObjectKind().GroupVersionKind().Kind, key)
+
+				oTo := &unstructured.Unstructured{}
+				oTo.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+				g.Expect(apierrors.IsNotFound(csTo.Get(ctx, key, oTo))).To(BeTrue(), "%s %v not deleted in target cluster", o.GetObjectKind().GroupVersionKind().Kind, key)
+			}
+
+			// Check the Cluster is resumed in the source cluster and the journal is deleted.
+			cluster := &clusterv1.Cluster{}
+			g.Expect(csFrom.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, cluster)).To(Succeed())
+			g.Expect(cluster.Spec.Paused).To(BeFalse())
+
+			savedJournal, err = journalClient.Get(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(savedJournal).To(BeNil())
+		})
+	}
+}
+
+func newTestObjectReference(apiVersion, kind, namespace, name string) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: apiVersion,
+		Kind:       kind,
+		Namespace:  namespace,
+		Name:       name,
+		UID:        types.UID(fmt.Sprintf("%s, Kind=%s, %s/%s", apiVersion, kind, namespace, name)),
+	}
+}
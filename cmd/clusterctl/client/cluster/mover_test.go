@@ -1327,6 +1327,73 @@ func Test_objectMover_move(t *testing.T) {
 	}
 }
 
+func Test_objectMover_move_partial(t *testing.T) {
+	g := NewWithT(t)
+
+	ctx := context.Background()
+
+	objs := []client.Object{}
+	objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "foo").WithTopologyClass("class1").Objs()...)
+	objs = append(objs, test.NewFakeCluster("ns1", "bar").WithTopologyClass("class1").Objs()...)
+
+	// Create an objectGraph bound a source cluster with all the CRDs for the types involved in the test.
+	graph := getObjectGraphWithObjs(objs)
+
+	// Get all the types to be considered for discovery
+	g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+
+	// trigger discovery the content of the source cluster
+	g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+	// select only the foo cluster; the ClusterClass is shared with the bar cluster.
+	g.Expect(graph.filterClusters("", MoveFilter{ClusterNames: []string{"foo"}, CopySharedObjects: true})).To(Succeed())
+	g.Expect(graph.checkSharedNodes()).ToNot(Succeed())
+
+	// gets a fakeProxy to an empty cluster with all the required CRDs
+	toProxy := getFakeProxyWithCRDs()
+
+	// Run move
+	mover := objectMover{
+		fromProxy: graph.proxy,
+	}
+	g.Expect(mover.move(ctx, graph, toProxy)).To(Succeed())
+
+	csFrom, err := graph.proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	csTo, err := toProxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	for _, o := range objs {
+		key := client.ObjectKeyFromObject(o)
+
+		oFrom := &unstructured.Unstructured{}
+		oFrom.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+		errFrom := csFrom.Get(ctx, key, oFrom)
+
+		oTo := &unstructured.Unstructured{}
+		oTo.SetGroupVersionKind(o.GetObjectKind().GroupVersionKind())
+		errTo := csTo.Get(ctx, key, oTo)
+
+		switch {
+		case strings.HasPrefix(o.GetName(), "class1"):
+			// shared objects are copied
+			g.Expect(errFrom).ToNot(HaveOccurred(), "%s %v not kept in source cluster", oFrom.GetKind(), key)
+			g.Expect(errTo).ToNot(HaveOccurred(), "%s %v not created in target cluster", oFrom.GetKind(), key)
+			g.Expect(oFrom.GetAnnotations()).ToNot(HaveKey(clusterv1.PausedAnnotation))
+		case strings.HasPrefix(o.GetName(), "foo"):
+			// objects of the selected cluster are moved
+			g.Expect(apierrors.IsNotFound(errFrom)).To(BeTrue(), "%s %v not deleted in source cluster", oFrom.GetKind(), key)
+			g.Expect(errTo).ToNot(HaveOccurred(), "%s %v not created in target cluster", oFrom.GetKind(), key)
+		case strings.HasPrefix(o.GetName(), "bar"):
+			// objects of other clusters are not moved
+			g.Expect(errFrom).ToNot(HaveOccurred(), "%s %v not kept in source cluster", oFrom.GetKind(), key)
+			g.Expect(apierrors.IsNotFound(errTo)).To(BeTrue(), "%s %v created in target cluster", oFrom.GetKind(), key)
+		}
+	}
+}
+
 func Test_objectMover_move_with_Mutator(t *testing.T) {
 	// NB. we are testing the move and move sequence using the same set of moveTests, but checking the results at different stages of the move process
 	// we use same mutator function for all tests and validate outcome based on input.
@@ -19,6 +19,7 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
 	"strings"
 
 	"github.com/pkg/errors"
@@ -27,6 +28,7 @@ import (
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
@@ -39,6 +41,7 @@ import (
 
 const clusterTopologyNameKey = "cluster.spec.topology.class"
 const clusterResourceSetBindingClusterNameKey = "clusterresourcesetbinding.spec.clustername"
+const clusterLabelsKey = "cluster.metadata.labels"
 
 type empty struct{}
 
@@ -96,6 +99,11 @@ type node struct {
 	// blockingMove is true when the object should prevent a move operation from proceeding as indicated by
 	// the presence of the block-move annotation.
 	blockingMove bool
+
+	// isShared gets set to true during a partial move if this object is required by the Clusters being moved,
+	// but it is in use also by Clusters which are not being moved, e.g. a ClusterClass.
+	// When this flag is true the object is copied to the target cluster and it should not be deleted from the source cluster.
+	isShared bool
 }
 
 type discoveryTypeInfo struct {
@@ -128,6 +136,16 @@ func (n *node) isSoftOwnedBy(other *node) bool {
 	return ok
 }
 
+// hasTenantIn returns true if any of the tenants of the node is in the given set.
+func (n *node) hasTenantIn(nodes map[*node]empty) bool {
+	for tenant := range n.tenant {
+		if _, ok := nodes[tenant]; ok {
+			return true
+		}
+	}
+	return false
+}
+
 func (n *node) getFilename() string {
 	return n.identity.Kind + "_" + n.identity.Namespace + "_" + n.identity.Name + ".yaml"
 }
@@ -150,6 +168,14 @@ func (n *node) captureAdditionalInformation(obj *unstructured.Unstructured) erro
 			}
 			n.additionalInfo[clusterTopologyNameKey] = cluster.Spec.Topology.Class
 		}
+
+		// Capture the labels of the cluster, so it is possible to select clusters by labels during a partial move.
+		if len(cluster.Labels) > 0 {
+			if n.additionalInfo == nil {
+				n.additionalInfo = map[string]interface{}{}
+			}
+			n.additionalInfo[clusterLabelsKey] = labels.Set(cluster.Labels)
+		}
 	}
 
 	// If the node is a ClusterResourceSetBinding capture the name of the cluster it is referencing to.
@@ -512,6 +538,17 @@ func (o *objectGraph) getClusters() []*node {
 	return clusters
 }
 
+// getSharedNodes returns the list of nodes existing in the object graph that are in use also by Clusters not being moved.
+func (o *objectGraph) getSharedNodes() []*node {
+	nodes := []*node{}
+	for _, node := range o.uidToNode {
+		if node.isShared {
+			nodes = append(nodes, node)
+		}
+	}
+	return nodes
+}
+
 // getClusterClasses returns the list of ClusterClasses existing in the object graph.
 func (o *objectGraph) getClusterClasses() []*node {
 	clusterClasses := []*node{}
@@ -665,6 +702,135 @@ func (o *objectGraph) setTenant(node, tenant *node, isGlobalHierarchy bool) {
 	}
 }
 
+// filterClusters restricts the object graph to the Clusters selected by the filter and to the objects they depend on,
+// e.g. ClusterClasses and their templates; objects required by the selected Clusters which are in use also by
+// Clusters not being moved are marked as shared.
+// NOTE: objects not being moved are removed from the graph, and so OwnerReferences to them are not re-created in the
+// target cluster.
+func (o *objectGraph) filterClusters(namespace string, filter MoveFilter) error {
+	if filter.isEmpty() {
+		return nil
+	}
+
+	log := logf.Log
+
+	selected := map[*node]empty{}
+	unselected := map[*node]empty{}
+	for _, cluster := range o.getClusters() {
+		if filter.matches(namespace, cluster) {
+			selected[cluster] = empty{}
+			continue
+		}
+		unselected[cluster] = empty{}
+	}
+
+	for _, name := range filter.ClusterNames {
+		found := 0
+		for cluster := range selected {
+			if clusterNameMatches(namespace, name, cluster) {
+				found++
+			}
+		}
+		switch {
+		case found == 0:
+			return errors.Errorf("failed to find Cluster %q", name)
+		case found > 1:
+			return errors.Errorf("found more than one Cluster named %q, use <namespace>/<name> to select only one of them", name)
+		}
+	}
+	if len(selected) == 0 {
+		return errors.New("failed to find Clusters matching the selector")
+	}
+
+	// Detects the objects not belonging to a Cluster, e.g. ClusterClasses or ClusterResourceSets, which are in use
+	// by the selected Clusters or by the Clusters not being moved, by looking at the tenants of the objects belonging to Clusters.
+	usedBySelected := map[*node]empty{}
+	usedByUnselected := map[*node]empty{}
+	for _, n := range o.getNodes() {
+		inSelected, inUnselected := n.hasTenantIn(selected), n.hasTenantIn(unselected)
+		for tenant := range n.tenant {
+			if isClusterNode(tenant) {
+				continue
+			}
+			if inSelected {
+				usedBySelected[tenant] = empty{}
+			}
+			if inUnselected {
+				usedByUnselected[tenant] = empty{}
+			}
+		}
+	}
+
+	excluded := map[*node]empty{}
+	for _, n := range o.getMoveNodes() {
+		keep, shared := false, false
+		switch {
+		case n.hasTenantIn(selected) || n.hasTenantIn(unselected):
+			// Objects belonging to Clusters are moved only if they belong to a selected Cluster.
+			keep = n.hasTenantIn(selected)
+			shared = n.hasTenantIn(unselected)
+		case n.isGlobal || n.isGlobalHierarchy:
+			// Global objects are never deleted from the source cluster, so they are always copied.
+			keep = true
+		default:
+			linked := false
+			for tenant := range n.tenant {
+				if _, ok := usedBySelected[tenant]; ok {
+					keep, linked = true, true
+				}
+				if _, ok := usedByUnselected[tenant]; ok {
+					shared, linked = true, true
+				}
+			}
+			// Objects not linked to any Cluster, e.g. a namespaced identity, could be in use by any Cluster, so during
+			// a partial move they are reported as shared; the move is refused unless copying shared objects is allowed.
+			if !linked {
+				keep, shared = true, len(unselected) > 0
+			}
+		}
+
+		if !keep {
+			excluded[n] = empty{}
+			continue
+		}
+		n.isShared = shared
+	}
+
+	// Removes the excluded objects from the graph.
+	for uid, n := range o.uidToNode {
+		if _, ok := excluded[n]; ok {
+			delete(o.uidToNode, uid)
+			continue
+		}
+		for other := range excluded {
+			delete(n.owners, other)
+			delete(n.softOwners, other)
+			delete(n.tenant, other)
+		}
+	}
+
+	log.Info("Selected Clusters for partial move", "Count", len(selected))
+	return nil
+}
+
+// checkSharedNodes returns an error if there are objects in use also by Clusters not being moved.
+func (o *objectGraph) checkSharedNodes() error {
+	shared := []string{}
+	for _, n := range o.getSharedNodes() {
+		shared = append(shared, n.identityStr())
+	}
+	if len(shared) == 0 {
+		return nil
+	}
+	sort.Strings(shared)
+	return errors.Errorf("the following objects are in use also by Clusters which are not being moved, they can be copied to the target cluster if copying shared objects is allowed: [%s]", strings.Join(shared, "; "))
+}
+
+// isClusterNode returns true if the node refers to a Cluster object.
+func isClusterNode(n *node) bool {
+	return n.identity.GroupVersionKind().GroupKind() == clusterv1.GroupVersion.WithKind("Cluster").GroupKind()
+}
+
 // checkVirtualNode logs if nodes are still virtual.
 func (o *objectGraph) checkVirtualNode() {
 	log := logf.Log
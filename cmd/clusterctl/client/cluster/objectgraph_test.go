@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -2516,6 +2517,347 @@ func Test_objectGraph_setGlobalIdentityTenants(t *testing.T) {
 	}
 }
 
+func Test_objectGraph_filterClusters(t *testing.T) {
+	type fields struct {
+		objs []client.Object
+	}
+	tests := []struct {
+		name          string
+		fields        fields
+		namespace     string
+		filter        MoveFilter
+		wantMoveNodes []string
+		wantShared    []string
+		wantErr       bool
+	}{
+		{
+			name: "Empty filter selects all the Clusters",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/bar",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/bar",
+				"/v1, Kind=Secret, ns1/bar-ca",
+				"/v1, Kind=Secret, ns1/bar-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Select a Cluster by name",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Select a Cluster by name in the namespace being moved",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns2", "foo").Objs()...)
+					return objs
+				}(),
+			},
+			namespace: "ns1",
+			filter:    MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Select a Cluster by namespace and name",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns2", "foo").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"ns2/foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns2/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns2/foo",
+				"/v1, Kind=Secret, ns2/foo-ca",
+				"/v1, Kind=Secret, ns2/foo-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Fails if a Cluster name matches Clusters in more than one namespace",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns2", "foo").Objs()...)
+					return objs
+				}(),
+			},
+			filter:  MoveFilter{ClusterNames: []string{"foo"}},
+			wantErr: true,
+		},
+		{
+			name: "Fails if a Cluster does not exist in the namespace being moved",
+			fields: fields{
+				objs: test.NewFakeCluster("ns1", "foo").Objs(),
+			},
+			namespace: "ns1",
+			filter:    MoveFilter{ClusterNames: []string{"ns2/foo"}},
+			wantErr:   true,
+		},
+		{
+			name: "Select a Cluster by labels",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").WithLabels(map[string]string{"env": "dev"}).Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterSelector: labels.SelectorFromSet(labels.Set{"env": "dev"})},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/bar",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/bar",
+				"/v1, Kind=Secret, ns1/bar-ca",
+				"/v1, Kind=Secret, ns1/bar-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Fails if a Cluster does not exist",
+			fields: fields{
+				objs: test.NewFakeCluster("ns1", "foo").Objs(),
+			},
+			filter:  MoveFilter{ClusterNames: []string{"bar"}},
+			wantErr: true,
+		},
+		{
+			name: "Fails if no Cluster matches the selector",
+			fields: fields{
+				objs: test.NewFakeCluster("ns1", "foo").Objs(),
+			},
+			filter:  MoveFilter{ClusterSelector: labels.SelectorFromSet(labels.Set{"env": "dev"})},
+			wantErr: true,
+		},
+		{
+			name: "Select a Cluster with its own ClusterClass",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").WithTopologyClass("class1").Objs()...)
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class2").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").WithTopologyClass("class2").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Select a Cluster with a ClusterClass shared with a Cluster not being moved",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeClusterClass("ns1", "class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").WithTopologyClass("class1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").WithTopologyClass("class1").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+			},
+			wantShared: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=ClusterClass, ns1/class1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureClusterTemplate, ns1/class1",
+				"controlplane.cluster.x-k8s.io/v1beta1, Kind=GenericControlPlaneTemplate, ns1/class1",
+			},
+		},
+		{
+			name: "Select a Cluster with a ClusterResourceSet applied also to a Cluster not being moved",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster1").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "cluster2").Objs()...)
+
+					objs = append(objs, test.NewFakeClusterResourceSet("ns1", "crs1").
+						WithSecret("resource-s1").
+						WithConfigMap("resource-c1").
+						ApplyToCluster(test.SelectClusterObj(objs, "ns1", "cluster1")).
+						ApplyToCluster(test.SelectClusterObj(objs, "ns1", "cluster2")).
+						Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"cluster1"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/cluster1",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/cluster1",
+				"/v1, Kind=Secret, ns1/cluster1-ca",
+				"/v1, Kind=Secret, ns1/cluster1-kubeconfig",
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSetBinding, ns1/cluster1",
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSet, ns1/crs1",
+				"/v1, Kind=Secret, ns1/resource-s1",
+				"/v1, Kind=ConfigMap, ns1/resource-c1",
+			},
+			wantShared: []string{
+				"addons.cluster.x-k8s.io/v1beta1, Kind=ClusterResourceSet, ns1/crs1",
+				"/v1, Kind=Secret, ns1/resource-s1",
+				"/v1, Kind=ConfigMap, ns1/resource-c1",
+			},
+		},
+		{
+			name: "Objects not linked to any Cluster are reported as shared",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+					objs = append(objs, test.NewFakeExternalObject("ns1", "externalObject1").Objs()...)
+					objs = append(objs, test.NewFakeClusterExternalObject("externalObject2").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericExternalObject, ns1/externalObject1",
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericClusterExternalObject, externalObject2", // global objects are always copied
+			},
+			wantShared: []string{
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericExternalObject, ns1/externalObject1",
+			},
+		},
+		{
+			name: "Objects not linked to any Cluster are not shared when all the Clusters are moved",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeExternalObject("ns1", "externalObject1").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericExternalObject, ns1/externalObject1",
+			},
+			wantShared: []string{},
+		},
+		{
+			name: "Objects not linked to any Cluster are copied if copying shared objects is allowed",
+			fields: fields{
+				objs: func() []client.Object {
+					objs := []client.Object{}
+					objs = append(objs, test.NewFakeCluster("ns1", "foo").Objs()...)
+					objs = append(objs, test.NewFakeCluster("ns1", "bar").Objs()...)
+					objs = append(objs, test.NewFakeExternalObject("ns1", "externalObject1").Objs()...)
+					return objs
+				}(),
+			},
+			filter: MoveFilter{ClusterNames: []string{"foo"}, CopySharedObjects: true},
+			wantMoveNodes: []string{
+				"cluster.x-k8s.io/v1beta1, Kind=Cluster, ns1/foo",
+				"infrastructure.cluster.x-k8s.io/v1beta1, Kind=GenericInfrastructureCluster, ns1/foo",
+				"/v1, Kind=Secret, ns1/foo-ca",
+				"/v1, Kind=Secret, ns1/foo-kubeconfig",
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericExternalObject, ns1/externalObject1",
+			},
+			wantShared: []string{
+				"external.cluster.x-k8s.io/v1beta1, Kind=GenericExternalObject, ns1/externalObject1",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			graph := getObjectGraphWithObjs(tt.fields.objs)
+			g.Expect(graph.getDiscoveryTypes(ctx)).To(Succeed())
+			g.Expect(graph.Discovery(ctx, "")).To(Succeed())
+
+			err := graph.filterClusters(tt.namespace, tt.filter)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			gotMoveNodes := []string{}
+			for _, n := range graph.getMoveNodes() {
+				gotMoveNodes = append(gotMoveNodes, string(n.identity.UID))
+			}
+			g.Expect(gotMoveNodes).To(ConsistOf(tt.wantMoveNodes))
+
+			gotShared := []string{}
+			for _, n := range graph.getSharedNodes() {
+				gotShared = append(gotShared, string(n.identity.UID))
+			}
+			g.Expect(gotShared).To(ConsistOf(tt.wantShared))
+
+			// Owners not being moved must be removed from the graph, so they are not re-created in the target cluster.
+			moveSequence := getMoveSequence(graph)
+			g.Expect(moveSequence.nodesMap).To(HaveLen(len(tt.wantMoveNodes)))
+		})
+	}
+}
+
 func deduplicateObjects(objs []client.Object) []client.Object {
 	res := []client.Object{}
 	uniqueObjectKeys := sets.Set[string]{}
@@ -82,6 +82,7 @@ type proxy struct {
 	kubeconfig         Kubeconfig
 	timeout            time.Duration
 	configLoadingRules *clientcmd.ClientConfigLoadingRules
+	restConfig         *rest.Config
 }
 
 var _ Proxy = &proxy{}
@@ -89,6 +90,11 @@ var _ Proxy = &proxy{}
 // CurrentNamespace returns the namespace for the specified context or the
 // first valid context as determined by the default config loading rules.
 func (k *proxy) CurrentNamespace() (string, error) {
+	// If the proxy is using an injected rest config, e.g. when running in cluster, there is no context to read the namespace from.
+	if k.restConfig != nil {
+		return metav1.NamespaceDefault, nil
+	}
+
 	config, err := k.configLoadingRules.Load()
 	if err != nil {
 		return "", errors.Wrap(err, "failed to load Kubeconfig")
@@ -131,21 +137,27 @@ func (k *proxy) ValidateKubernetesVersion() error {
 
 // GetConfig returns the config for a kubernetes client.
 func (k *proxy) GetConfig() (*rest.Config, error) {
-	config, err := k.configLoadingRules.Load()
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to load Kubeconfig")
-	}
+	var restConfig *rest.Config
+	if k.restConfig != nil {
+		restConfig = rest.CopyConfig(k.restConfig)
+		restConfig.Timeout = k.timeout
+	} else {
+		config, err := k.configLoadingRules.Load()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to load Kubeconfig")
+		}
 
-	configOverrides := &clientcmd.ConfigOverrides{
-		CurrentContext: k.kubeconfig.Context,
-		Timeout:        k.timeout.String(),
-	}
-	restConfig, err := clientcmd.NewDefaultClientConfig(*config, configOverrides).ClientConfig()
-	if err != nil {
-		if strings.HasPrefix(err.Error(), "invalid configuration:") {
-			return nil, errors.New(strings.Replace(err.Error(), "invalid configuration:", "invalid kubeconfig file; clusterctl requires a valid kubeconfig file to connect to the management cluster:", 1))
+		configOverrides := &clientcmd.ConfigOverrides{
+			CurrentContext: k.kubeconfig.Context,
+			Timeout:        k.timeout.String(),
+		}
+		restConfig, err = clientcmd.NewDefaultClientConfig(*config, configOverrides).ClientConfig()
+		if err != nil {
+			if strings.HasPrefix(err.Error(), "invalid configuration:") {
+				return nil, errors.New(strings.Replace(err.Error(), "invalid configuration:", "invalid kubeconfig file; clusterctl requires a valid kubeconfig file to connect to the management cluster:", 1))
+			}
+			return nil, err
 		}
-		return nil, err
 	}
 	restConfig.UserAgent = fmt.Sprintf("clusterctl/%s (%s)", version.Get().GitVersion, version.Get().Platform)
 
@@ -374,6 +386,19 @@ func InjectKubeconfigPaths(paths []string) ProxyOption {
 	}
 }
 
+// InjectRESTConfig sets the rest config used for connecting to the cluster, e.g. when running in cluster;
+// if set, the kubeconfig and the kubeconfig paths loading rules are ignored.
+func InjectRESTConfig(config *rest.Config) ProxyOption {
+	return func(p *proxy) {
+		p.restConfig = config
+	}
+}
+
+// NewProxy returns a Proxy for the cluster identified by the given kubeconfig.
+func NewProxy(kubeconfig Kubeconfig, opts ...ProxyOption) Proxy {
+	return newProxy(kubeconfig, opts...)
+}
+
 func newProxy(kubeconfig Kubeconfig, opts ...ProxyOption) Proxy {
 	// If a kubeconfig file isn't provided, find one in the standard locations.
 	rules := clientcmd.NewDefaultClientConfigLoadingRules()
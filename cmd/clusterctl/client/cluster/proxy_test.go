@@ -24,6 +24,7 @@ import (
 	"time"
 
 	. "github.com/onsi/gomega"
+	"k8s.io/client-go/rest"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
 	"sigs.k8s.io/cluster-api/version"
@@ -101,6 +102,24 @@ func TestProxyGetConfig(t *testing.T) {
 		g.Expect(err).ToNot(HaveOccurred())
 		g.Expect(conf.Timeout.String()).To(Equal("23s"))
 	})
+
+	t.Run("inject rest config", func(t *testing.T) {
+		g := NewWithT(t)
+
+		restConfig := &rest.Config{Host: "https://in-cluster:443"}
+		proxy := newProxy(Kubeconfig{Path: "does-not-exist"}, InjectRESTConfig(restConfig))
+		conf, err := proxy.GetConfig()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(conf.Host).To(Equal("https://in-cluster:443"))
+		g.Expect(conf.QPS).To(BeEquivalentTo(20))
+		g.Expect(conf.Timeout.String()).To(Equal("30s"))
+		// The injected config must not be changed.
+		g.Expect(restConfig.QPS).To(BeEquivalentTo(0))
+
+		namespace, err := proxy.CurrentNamespace()
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(namespace).To(Equal("default"))
+	})
 }
 
 // These tests are emulating the files passed in via KUBECONFIG env var by
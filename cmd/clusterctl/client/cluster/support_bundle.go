@@ -0,0 +1,503 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io"
+	"path"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+const (
+	// redactedValue replaces sensitive values in the objects included in a support bundle.
+	redactedValue = "REDACTED"
+
+	// defaultSupportBundleLogTailLines is the default number of log lines collected for each container.
+	defaultSupportBundleLogTailLines = int64(1000)
+
+	// lastAppliedConfigAnnotation is the annotation used by kubectl apply to store the last applied configuration,
+	// which includes the data of Secrets.
+	lastAppliedConfigAnnotation = "kubectl.kubernetes.io/last-applied-configuration"
+)
+
+// SupportBundleOptions carries the options supported by SupportBundleCollector.Collect.
+type SupportBundleOptions struct {
+	// ClusterName is the name of the workload cluster to collect diagnostics for.
+	ClusterName string
+
+	// Namespace where the workload cluster is located.
+	Namespace string
+
+	// LogTailLines is the number of log lines to collect for each provider controller container.
+	// If not set, the last 1000 lines are collected.
+	LogTailLines int64
+}
+
+// SupportBundleCollector collects diagnostic information about a workload cluster.
+type SupportBundleCollector interface {
+	// Collect collects the Cluster API objects of a workload cluster, the related events, the logs of the provider
+	// controllers and the nodes and kube-system pods of the workload cluster, and writes them as a gzipped tarball into w.
+	// Secrets, bootstrap tokens, the content of bootstrap files and the literal values of environment variables in pods
+	// are redacted.
+	// NOTE: Collection is best effort, errors not preventing the bundle to be created are reported in the errors.txt file
+	// of the bundle.
+	Collect(ctx context.Context, options SupportBundleOptions, w io.Writer) error
+}
+
+// supportBundleCollector implements SupportBundleCollector.
+type supportBundleCollector struct {
+	proxy             Proxy
+	providerInventory InventoryClient
+	workloadCluster   WorkloadCluster
+
+	// newClientset returns the client-go client used for reading the logs of the provider controllers.
+	newClientset func(config *rest.Config) (kubernetes.Interface, error)
+
+	// newWorkloadClient returns the client used for reading objects from the workload cluster.
+	newWorkloadClient func(kubeconfig []byte) (client.Client, error)
+}
+
+// ensure supportBundleCollector implements SupportBundleCollector.
+var _ SupportBundleCollector = &supportBundleCollector{}
+
+func newSupportBundleCollector(proxy Proxy, providerInventory InventoryClient, workloadCluster WorkloadCluster) *supportBundleCollector {
+	return &supportBundleCollector{
+		proxy:             proxy,
+		providerInventory: providerInventory,
+		workloadCluster:   workloadCluster,
+		newClientset: func(config *rest.Config) (kubernetes.Interface, error) {
+			return kubernetes.NewForConfig(config)
+		},
+		newWorkloadClient: func(kubeconfig []byte) (client.Client, error) {
+			config, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+			if err != nil {
+				return nil, err
+			}
+			return client.New(config, client.Options{Scheme: localScheme})
+		},
+	}
+}
+
+// supportBundleWriter writes files into a gzipped tarball, keeping track of the errors occurred while collecting them.
+type supportBundleWriter struct {
+	gw        *gzip.Writer
+	tw        *tar.Writer
+	timestamp time.Time
+	errs      []string
+}
+
+func newSupportBundleWriter(w io.Writer) *supportBundleWriter {
+	gw := gzip.NewWriter(w)
+	return &supportBundleWriter{
+		gw:        gw,
+		tw:        tar.NewWriter(gw),
+		timestamp: time.Now(),
+	}
+}
+
+// addFile adds a file with the given content to the bundle.
+func (b *supportBundleWriter) addFile(name string, data []byte) error {
+	if err := b.tw.WriteHeader(&tar.Header{
+		Name:    name,
+		Mode:    0o600,
+		Size:    int64(len(data)),
+		ModTime: b.timestamp,
+	}); err != nil {
+		return errors.Wrapf(err, "failed to write %q to the support bundle", name)
+	}
+	if _, err := b.tw.Write(data); err != nil {
+		return errors.Wrapf(err, "failed to write %q to the support bundle", name)
+	}
+	return nil
+}
+
+// addYAML adds a file with the YAML representation of obj to the bundle.
+func (b *supportBundleWriter) addYAML(name string, obj interface{}) error {
+	data, err := yaml.Marshal(obj)
+	if err != nil {
+		return errors.Wrapf(err, "failed to serialize %q", name)
+	}
+	return b.addFile(name, data)
+}
+
+// addError records an error occurred while collecting the content of the bundle.
+func (b *supportBundleWriter) addError(err error) {
+	logf.Log.V(1).Info("Failed to collect support bundle content", "Error", err.Error())
+	b.errs = append(b.errs, err.Error())
+}
+
+// Close writes the errors.txt file, if required, and flushes the bundle.
+func (b *supportBundleWriter) Close() error {
+	if len(b.errs) > 0 {
+		if err := b.addFile("errors.txt", []byte(strings.Join(b.errs, "\n")+"\n")); err != nil {
+			return err
+		}
+	}
+	if err := b.tw.Close(); err != nil {
+		return errors.Wrap(err, "failed to close the support bundle")
+	}
+	return errors.Wrap(b.gw.Close(), "failed to close the support bundle")
+}
+
+func (s *supportBundleCollector) Collect(ctx context.Context, options SupportBundleOptions, w io.Writer) error {
+	log := logf.Log
+
+	if options.ClusterName == "" {
+		return errors.New("cluster name must be set")
+	}
+	if options.LogTailLines <= 0 {
+		options.LogTailLines = defaultSupportBundleLogTailLines
+	}
+
+	// Gets the objects belonging to the Cluster using the same logic used by move, including the objects shared with other
+	// Clusters, e.g. ClusterClasses.
+	log.Info("Discovering Cluster API objects", "Cluster", options.ClusterName, "Namespace", options.Namespace)
+	graph := newObjectGraph(s.proxy, s.providerInventory)
+	if err := graph.getDiscoveryTypes(ctx); err != nil {
+		return err
+	}
+	if err := graph.Discovery(ctx, options.Namespace); err != nil {
+		return err
+	}
+	if err := graph.filterClusters(options.Namespace, MoveFilter{ClusterNames: []string{options.ClusterName}, CopySharedObjects: true}); err != nil {
+		return err
+	}
+
+	c, err := s.proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	b := newSupportBundleWriter(w)
+	uids, err := s.collectObjects(ctx, c, graph, b)
+	if err != nil {
+		return err
+	}
+	if err := s.collectClusterEvents(ctx, c, options.Namespace, uids, b); err != nil {
+		return err
+	}
+	if err := s.collectProviders(ctx, c, options.LogTailLines, b); err != nil {
+		return err
+	}
+	if err := s.collectWorkloadCluster(ctx, options, b); err != nil {
+		return err
+	}
+	return b.Close()
+}
+
+// collectObjects adds the objects in the object graph to the bundle, returning the UIDs of the collected objects.
+func (s *supportBundleCollector) collectObjects(ctx context.Context, c client.Client, graph *objectGraph, b *supportBundleWriter) (sets.Set[types.UID], error) {
+	log := logf.Log
+	uids := sets.Set[types.UID]{}
+
+	nodes := []*node{}
+	for _, n := range graph.getMoveNodes() {
+		if n.virtual {
+			continue
+		}
+		nodes = append(nodes, n)
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		return nodes[i].getFilename() < nodes[j].getFilename()
+	})
+
+	log.Info("Collecting Cluster API objects", "Count", len(nodes))
+	for _, n := range nodes {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(n.identity.APIVersion)
+		obj.SetKind(n.identity.Kind)
+		key := client.ObjectKey{Namespace: n.identity.Namespace, Name: n.identity.Name}
+		if err := retryWithExponentialBackoff(ctx, newReadBackoff(), func(ctx context.Context) error {
+			return c.Get(ctx, key, obj)
+		}); err != nil {
+			b.addError(errors.Wrapf(err, "failed to get %s", n.identityStr()))
+			continue
+		}
+		redactObject(obj)
+		if err := b.addYAML(path.Join("objects", n.getFilename()), obj.Object); err != nil {
+			return nil, err
+		}
+		uids.Insert(obj.GetUID())
+	}
+	return uids, nil
+}
+
+// collectClusterEvents adds to the bundle the events in the namespace of the Cluster involving the collected objects.
+func (s *supportBundleCollector) collectClusterEvents(ctx context.Context, c client.Client, namespace string, uids sets.Set[types.UID], b *supportBundleWriter) error {
+	events := &corev1.EventList{}
+	if err := c.List(ctx, events, client.InNamespace(namespace)); err != nil {
+		b.addError(errors.Wrapf(err, "failed to list events in namespace %q", namespace))
+		return nil
+	}
+
+	filtered := &corev1.EventList{}
+	for _, event := range events.Items {
+		if uids.Has(event.InvolvedObject.UID) {
+			filtered.Items = append(filtered.Items, event)
+		}
+	}
+	sortEvents(filtered.Items)
+	return b.addYAML("events.yaml", filtered)
+}
+
+// collectProviders adds to the bundle the events and the controller logs from the namespaces of the providers
+// listed in the inventory.
+func (s *supportBundleCollector) collectProviders(ctx context.Context, c client.Client, logTailLines int64, b *supportBundleWriter) error {
+	log := logf.Log
+
+	providers, err := s.providerInventory.List(ctx)
+	if err != nil {
+		b.addError(errors.Wrap(err, "failed to list the providers in the inventory"))
+		return nil
+	}
+	namespaces := sets.Set[string]{}
+	for _, p := range providers.Items {
+		namespaces.Insert(p.Namespace)
+	}
+
+	var cs kubernetes.Interface
+	config, err := s.proxy.GetConfig()
+	if err == nil {
+		cs, err = s.newClientset(config)
+	}
+	if err != nil {
+		b.addError(errors.Wrap(err, "failed to create the client for reading controller logs"))
+	}
+
+	for _, namespace := range sets.List(namespaces) {
+		log.Info("Collecting provider logs and events", "Namespace", namespace)
+		dir := path.Join("providers", namespace)
+
+		events := &corev1.EventList{}
+		if err := c.List(ctx, events, client.InNamespace(namespace)); err != nil {
+			b.addError(errors.Wrapf(err, "failed to list events in namespace %q", namespace))
+		} else {
+			sortEvents(events.Items)
+			if err := b.addYAML(path.Join(dir, "events.yaml"), events); err != nil {
+				return err
+			}
+		}
+
+		pods := &corev1.PodList{}
+		if err := c.List(ctx, pods, client.InNamespace(namespace)); err != nil {
+			b.addError(errors.Wrapf(err, "failed to list pods in namespace %q", namespace))
+			continue
+		}
+		redactPods(pods)
+		if err := b.addYAML(path.Join(dir, "pods.yaml"), pods); err != nil {
+			return err
+		}
+		if cs == nil {
+			continue
+		}
+
+		for _, pod := range pods.Items {
+			for _, status := range pod.Status.ContainerStatuses {
+				if err := s.collectLogs(ctx, cs, pod, status.Name, false, logTailLines, dir, b); err != nil {
+					return err
+				}
+				// Collect the logs of the previous instance of crashing containers.
+				if status.RestartCount > 0 {
+					if err := s.collectLogs(ctx, cs, pod, status.Name, true, logTailLines, dir, b); err != nil {
+						return err
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// collectLogs adds the logs of a container to the bundle.
+func (s *supportBundleCollector) collectLogs(ctx context.Context, cs kubernetes.Interface, pod corev1.Pod, container string, previous bool, tailLines int64, dir string, b *supportBundleWriter) error {
+	logs, err := cs.CoreV1().Pods(pod.Namespace).GetLogs(pod.Name, &corev1.PodLogOptions{
+		Container: container,
+		Previous:  previous,
+		TailLines: &tailLines,
+	}).DoRaw(ctx)
+	if err != nil {
+		b.addError(errors.Wrapf(err, "failed to get logs for container %q of pod %s/%s", container, pod.Namespace, pod.Name))
+		return nil
+	}
+
+	filename := container + ".log"
+	if previous {
+		filename = container + ".previous.log"
+	}
+	return b.addFile(path.Join(dir, "logs", pod.Name, filename), logs)
+}
+
+// collectWorkloadCluster adds to the bundle the nodes and the kube-system pods of the workload cluster,
+// using the kubeconfig stored in the management cluster.
+func (s *supportBundleCollector) collectWorkloadCluster(ctx context.Context, options SupportBundleOptions, b *supportBundleWriter) error {
+	log := logf.Log
+	log.Info("Collecting nodes and kube-system pods from the workload cluster")
+
+	kubeconfig, err := s.workloadCluster.GetKubeconfig(ctx, options.ClusterName, options.Namespace)
+	if err != nil {
+		b.addError(errors.Wrap(err, "failed to get the kubeconfig of the workload cluster"))
+		return nil
+	}
+	c, err := s.newWorkloadClient([]byte(kubeconfig))
+	if err != nil {
+		b.addError(errors.Wrap(err, "failed to create the client for the workload cluster"))
+		return nil
+	}
+
+	nodes := &corev1.NodeList{}
+	if err := c.List(ctx, nodes); err != nil {
+		b.addError(errors.Wrap(err, "failed to list nodes in the workload cluster"))
+	} else {
+		for i := range nodes.Items {
+			nodes.Items[i].SetManagedFields(nil)
+		}
+		if err := b.addYAML(path.Join("workload-cluster", "nodes.yaml"), nodes); err != nil {
+			return err
+		}
+	}
+
+	pods := &corev1.PodList{}
+	if err := c.List(ctx, pods, client.InNamespace(metav1.NamespaceSystem)); err != nil {
+		b.addError(errors.Wrap(err, "failed to list kube-system pods in the workload cluster"))
+		return nil
+	}
+	redactPods(pods)
+	return b.addYAML(path.Join("workload-cluster", "kube-system-pods.yaml"), pods)
+}
+
+// redactObject removes sensitive information from an object before adding it to a support bundle.
+func redactObject(obj *unstructured.Unstructured) {
+	obj.SetManagedFields(nil)
+
+	// The last applied configuration includes a copy of all the fields redacted below.
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations[lastAppliedConfigAnnotation]; ok {
+		annotations[lastAppliedConfigAnnotation] = redactedValue
+		obj.SetAnnotations(annotations)
+	}
+
+	if obj.GroupVersionKind().GroupKind() != corev1.SchemeGroupVersion.WithKind("Secret").GroupKind() {
+		// Objects other than Secrets, e.g. KubeadmConfig or KubeadmControlPlane, could embed secrets like bootstrap
+		// tokens or the content of files; given that the same fields could exist at different paths (e.g. spec.files,
+		// spec.kubeadmConfigSpec.files, spec.template.spec.files), they are searched for in the whole object.
+		redactSensitiveFields(obj.Object)
+		return
+	}
+	for _, field := range []string{"data", "stringData"} {
+		values, ok := obj.Object[field].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		for k := range values {
+			values[k] = redactedValue
+		}
+	}
+}
+
+// redactSensitiveFields recursively redacts the fields of a bootstrap configuration which could contain secrets:
+// the bootstrap token used for joining a node (bootstrapToken.token), the bootstrap tokens created by kubeadm init
+// (bootstrapTokens[].token) and the inline content of files written on the machines (files[].content).
+func redactSensitiveFields(value interface{}) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for key, field := range v {
+			switch key {
+			case "bootstrapToken":
+				redactField(field, "token")
+			case "bootstrapTokens", "files":
+				items, _ := field.([]interface{})
+				for _, item := range items {
+					redactField(item, "token", "content")
+				}
+			}
+			redactSensitiveFields(field)
+		}
+	case []interface{}:
+		for _, item := range v {
+			redactSensitiveFields(item)
+		}
+	}
+}
+
+// redactField redacts the given fields of value, if value is an object and the fields are set.
+func redactField(value interface{}, fields ...string) {
+	obj, ok := value.(map[string]interface{})
+	if !ok {
+		return
+	}
+	for _, field := range fields {
+		if _, ok := obj[field]; ok {
+			obj[field] = redactedValue
+		}
+	}
+}
+
+// redactPods removes sensitive information from pods before adding them to a support bundle.
+// NOTE: The literal values of environment variables are redacted because they could contain credentials; values
+// read from Secrets or ConfigMaps are references, so they are kept.
+func redactPods(pods *corev1.PodList) {
+	for i := range pods.Items {
+		pod := &pods.Items[i]
+		pod.SetManagedFields(nil)
+		if _, ok := pod.Annotations[lastAppliedConfigAnnotation]; ok {
+			pod.Annotations[lastAppliedConfigAnnotation] = redactedValue
+		}
+		for j := range pod.Spec.InitContainers {
+			redactEnv(pod.Spec.InitContainers[j].Env)
+		}
+		for j := range pod.Spec.Containers {
+			redactEnv(pod.Spec.Containers[j].Env)
+		}
+		for j := range pod.Spec.EphemeralContainers {
+			redactEnv(pod.Spec.EphemeralContainers[j].Env)
+		}
+	}
+}
+
+func redactEnv(env []corev1.EnvVar) {
+	for i := range env {
+		if env[i].Value != "" {
+			env[i].Value = redactedValue
+		}
+	}
+}
+
+// sortEvents sorts events by last timestamp, so the bundle reads like a timeline.
+func sortEvents(events []corev1.Event) {
+	sort.SliceStable(events, func(i, j int) bool {
+		return events[i].LastTimestamp.Before(&events[j].LastTimestamp)
+	})
+}
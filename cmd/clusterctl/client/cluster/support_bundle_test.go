@@ -0,0 +1,345 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"io"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/client-go/kubernetes"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"k8s.io/client-go/rest"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_supportBundleCollector_Collect(t *testing.T) {
+	workloadObjs := []client.Object{
+		&corev1.Node{ObjectMeta: metav1.ObjectMeta{Name: "node1"}},
+		&corev1.Pod{
+			ObjectMeta: metav1.ObjectMeta{Name: "kube-apiserver-node1", Namespace: metav1.NamespaceSystem},
+			Spec: corev1.PodSpec{
+				Containers: []corev1.Container{{
+					Name: "kube-apiserver",
+					Env:  []corev1.EnvVar{{Name: "CLOUD_CREDENTIALS", Value: "super-secret-env"}},
+				}},
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		clusterName       string
+		workloadClientErr error
+		wantFiles         []string
+		wantNotFiles      []string
+		wantErrorsFile    bool
+		wantErr           bool
+	}{
+		{
+			name:        "collects objects, events, provider logs and workload cluster objects",
+			clusterName: "cluster1",
+			wantFiles: []string{
+				"objects/Cluster_ns1_cluster1.yaml",
+				"objects/Secret_ns1_cluster1-kubeconfig.yaml",
+				"events.yaml",
+				"providers/infra1-system/events.yaml",
+				"providers/infra1-system/pods.yaml",
+				"providers/infra1-system/logs/controller-manager/manager.log",
+				"providers/infra1-system/logs/controller-manager/manager.previous.log",
+				"workload-cluster/nodes.yaml",
+				"workload-cluster/kube-system-pods.yaml",
+			},
+			wantNotFiles: []string{
+				"objects/Cluster_ns1_cluster2.yaml",
+				"objects/Secret_ns1_cluster2-kubeconfig.yaml",
+			},
+		},
+		{
+			name:              "records errors when the workload cluster is not reachable",
+			clusterName:       "cluster1",
+			workloadClientErr: errors.New("connection refused"),
+			wantFiles: []string{
+				"objects/Cluster_ns1_cluster1.yaml",
+				"providers/infra1-system/logs/controller-manager/manager.log",
+			},
+			wantNotFiles: []string{
+				"workload-cluster/nodes.yaml",
+			},
+			wantErrorsFile: true,
+		},
+		{
+			name:        "fails if the cluster does not exist",
+			clusterName: "cluster3",
+			wantErr:     true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			proxy := getFakeProxyWithCRDs().
+				WithObjs(test.NewFakeCluster("ns1", "cluster1").Objs()...).
+				WithObjs(test.NewFakeCluster("ns1", "cluster2").Objs()...).
+				WithObjs(&corev1.Pod{
+					ObjectMeta: metav1.ObjectMeta{Name: "controller-manager", Namespace: "infra1-system"},
+					Status: corev1.PodStatus{
+						ContainerStatuses: []corev1.ContainerStatus{{Name: "manager", RestartCount: 1}},
+					},
+				}).
+				WithProviderInventory("infra1", clusterctlv1.InfrastructureProviderType, "v1.2.3", "infra1-system")
+
+			c, err := proxy.NewClient(ctx)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			// Adds data to the kubeconfig secret, so it is possible to verify it gets redacted.
+			secret := &corev1.Secret{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1-kubeconfig"}, secret)).To(Succeed())
+			secret.Data = map[string][]byte{"value": []byte("super-secret-kubeconfig")}
+			g.Expect(c.Update(ctx, secret)).To(Succeed())
+
+			// Adds events for the cluster and for the provider.
+			cluster := &clusterv1.Cluster{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: "ns1", Name: "cluster1"}, cluster)).To(Succeed())
+			g.Expect(c.Create(ctx, &corev1.Event{
+				ObjectMeta:     metav1.ObjectMeta{Name: "cluster1-event", Namespace: "ns1"},
+				InvolvedObject: corev1.ObjectReference{UID: cluster.UID},
+				Message:        "cluster1 event",
+			})).To(Succeed())
+			g.Expect(c.Create(ctx, &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "unrelated-event", Namespace: "ns1"},
+				Message:    "unrelated event",
+			})).To(Succeed())
+			g.Expect(c.Create(ctx, &corev1.Event{
+				ObjectMeta: metav1.ObjectMeta{Name: "provider-event", Namespace: "infra1-system"},
+				Message:    "provider event",
+			})).To(Succeed())
+
+			inventory := newInventoryClient(proxy, fakePollImmediateWaiter)
+			collector := newSupportBundleCollector(proxy, inventory, newWorkloadCluster(proxy))
+			collector.newClientset = func(*rest.Config) (kubernetes.Interface, error) {
+				return k8sfake.NewSimpleClientset(), nil
+			}
+			collector.newWorkloadClient = func(kubeconfig []byte) (client.Client, error) {
+				g.Expect(string(kubeconfig)).To(Equal("super-secret-kubeconfig"))
+				if tt.workloadClientErr != nil {
+					return nil, tt.workloadClientErr
+				}
+				return fake.NewClientBuilder().WithObjects(workloadObjs...).Build(), nil
+			}
+
+			out := &bytes.Buffer{}
+			err = collector.Collect(ctx, SupportBundleOptions{ClusterName: tt.clusterName, Namespace: "ns1"}, out)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+
+			files := readSupportBundle(t, out.Bytes())
+			for _, name := range tt.wantFiles {
+				g.Expect(files).To(HaveKey(name))
+			}
+			for _, name := range tt.wantNotFiles {
+				g.Expect(files).ToNot(HaveKey(name))
+			}
+			if tt.wantErrorsFile {
+				g.Expect(files).To(HaveKey("errors.txt"))
+				g.Expect(files["errors.txt"]).To(ContainSubstring(tt.workloadClientErr.Error()))
+			} else {
+				g.Expect(files).ToNot(HaveKey("errors.txt"))
+			}
+
+			g.Expect(files["objects/Secret_ns1_cluster1-kubeconfig.yaml"]).To(ContainSubstring(redactedValue))
+			g.Expect(files["objects/Secret_ns1_cluster1-kubeconfig.yaml"]).ToNot(ContainSubstring("c3VwZXItc2VjcmV0")) // base64 of "super-secret"
+			g.Expect(files["events.yaml"]).To(ContainSubstring("cluster1 event"))
+			g.Expect(files["events.yaml"]).ToNot(ContainSubstring("unrelated event"))
+			g.Expect(files["providers/infra1-system/events.yaml"]).To(ContainSubstring("provider event"))
+			g.Expect(files["providers/infra1-system/logs/controller-manager/manager.log"]).To(Equal("fake logs"))
+			if tt.workloadClientErr == nil {
+				g.Expect(files["workload-cluster/nodes.yaml"]).To(ContainSubstring("node1"))
+				g.Expect(files["workload-cluster/kube-system-pods.yaml"]).To(ContainSubstring("kube-apiserver-node1"))
+				g.Expect(files["workload-cluster/kube-system-pods.yaml"]).ToNot(ContainSubstring("super-secret-env"))
+			}
+		})
+	}
+}
+
+func Test_redactObject(t *testing.T) {
+	g := NewWithT(t)
+
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Secret",
+		"metadata": map[string]interface{}{
+			"name": "foo",
+			"annotations": map[string]interface{}{
+				lastAppliedConfigAnnotation: `{"data":{"password":"c2VjcmV0"}}`,
+				"foo":                       "bar",
+			},
+			"managedFields": []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"data":       map[string]interface{}{"password": "c2VjcmV0"},
+		"stringData": map[string]interface{}{"token": "secret"},
+	}}
+	redactObject(obj)
+
+	g.Expect(obj.Object["data"]).To(Equal(map[string]interface{}{"password": redactedValue}))
+	g.Expect(obj.Object["stringData"]).To(Equal(map[string]interface{}{"token": redactedValue}))
+	g.Expect(obj.GetAnnotations()).To(Equal(map[string]string{lastAppliedConfigAnnotation: redactedValue, "foo": "bar"}))
+	g.Expect(obj.GetManagedFields()).To(BeEmpty())
+
+	// Bootstrap tokens and the content of files are redacted in bootstrap configurations, wherever they are.
+	kubeadmConfig := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "bootstrap.cluster.x-k8s.io/v1beta1",
+		"kind":       "KubeadmConfig",
+		"metadata":   map[string]interface{}{"name": "foo"},
+		"spec": map[string]interface{}{
+			"initConfiguration": map[string]interface{}{
+				"bootstrapTokens": []interface{}{map[string]interface{}{"token": "abcdef.0123456789abcdef", "ttl": "24h0m0s"}},
+			},
+			"joinConfiguration": map[string]interface{}{
+				"discovery": map[string]interface{}{
+					"bootstrapToken": map[string]interface{}{"token": "abcdef.0123456789abcdef", "apiServerEndpoint": "10.0.0.1:6443"},
+				},
+			},
+			"files": []interface{}{
+				map[string]interface{}{"path": "/etc/foo", "content": "secret"},
+				map[string]interface{}{"path": "/etc/bar", "contentFrom": map[string]interface{}{"secret": map[string]interface{}{"name": "bar", "key": "bar"}}},
+			},
+		},
+	}}
+	redactObject(kubeadmConfig)
+	g.Expect(kubeadmConfig.Object["spec"]).To(Equal(map[string]interface{}{
+		"initConfiguration": map[string]interface{}{
+			"bootstrapTokens": []interface{}{map[string]interface{}{"token": redactedValue, "ttl": "24h0m0s"}},
+		},
+		"joinConfiguration": map[string]interface{}{
+			"discovery": map[string]interface{}{
+				"bootstrapToken": map[string]interface{}{"token": redactedValue, "apiServerEndpoint": "10.0.0.1:6443"},
+			},
+		},
+		"files": []interface{}{
+			map[string]interface{}{"path": "/etc/foo", "content": redactedValue},
+			map[string]interface{}{"path": "/etc/bar", "contentFrom": map[string]interface{}{"secret": map[string]interface{}{"name": "bar", "key": "bar"}}},
+		},
+	}))
+
+	kubeadmControlPlane := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "controlplane.cluster.x-k8s.io/v1beta1",
+		"kind":       "KubeadmControlPlane",
+		"metadata": map[string]interface{}{
+			"name":        "foo",
+			"annotations": map[string]interface{}{lastAppliedConfigAnnotation: `{"spec":{"kubeadmConfigSpec":{"files":[{"content":"secret"}]}}}`},
+		},
+		"spec": map[string]interface{}{
+			"kubeadmConfigSpec": map[string]interface{}{
+				"files": []interface{}{map[string]interface{}{"path": "/etc/foo", "content": "secret"}},
+			},
+		},
+	}}
+	redactObject(kubeadmControlPlane)
+	g.Expect(kubeadmControlPlane.Object["spec"]).To(Equal(map[string]interface{}{
+		"kubeadmConfigSpec": map[string]interface{}{
+			"files": []interface{}{map[string]interface{}{"path": "/etc/foo", "content": redactedValue}},
+		},
+	}))
+	g.Expect(kubeadmControlPlane.GetAnnotations()).To(Equal(map[string]string{lastAppliedConfigAnnotation: redactedValue}))
+
+	// Other fields of objects other than Secrets are not redacted.
+	configMap := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata":   map[string]interface{}{"name": "foo"},
+		"data":       map[string]interface{}{"foo": "bar"},
+	}}
+	redactObject(configMap)
+	g.Expect(configMap.Object["data"]).To(Equal(map[string]interface{}{"foo": "bar"}))
+}
+
+func Test_redactPods(t *testing.T) {
+	g := NewWithT(t)
+
+	pods := &corev1.PodList{Items: []corev1.Pod{{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:          "foo",
+			ManagedFields: []metav1.ManagedFieldsEntry{{Manager: "kubelet"}},
+		},
+		Spec: corev1.PodSpec{
+			InitContainers: []corev1.Container{{
+				Name: "init",
+				Env:  []corev1.EnvVar{{Name: "PASSWORD", Value: "secret"}},
+			}},
+			Containers: []corev1.Container{{
+				Name: "manager",
+				Env: []corev1.EnvVar{
+					{Name: "TOKEN", Value: "secret"},
+					{Name: "EMPTY"},
+					{Name: "FROM_SECRET", ValueFrom: &corev1.EnvVarSource{
+						SecretKeyRef: &corev1.SecretKeySelector{LocalObjectReference: corev1.LocalObjectReference{Name: "foo"}, Key: "foo"},
+					}},
+				},
+			}},
+		},
+	}}}
+	redactPods(pods)
+
+	pod := pods.Items[0]
+	g.Expect(pod.ManagedFields).To(BeEmpty())
+	g.Expect(pod.Spec.InitContainers[0].Env[0].Value).To(Equal(redactedValue))
+	g.Expect(pod.Spec.Containers[0].Env[0].Value).To(Equal(redactedValue))
+	g.Expect(pod.Spec.Containers[0].Env[1].Value).To(BeEmpty())
+	g.Expect(pod.Spec.Containers[0].Env[2].Value).To(BeEmpty())
+	g.Expect(pod.Spec.Containers[0].Env[2].ValueFrom).ToNot(BeNil())
+}
+
+// readSupportBundle returns the content of the files in a support bundle.
+func readSupportBundle(t *testing.T, data []byte) map[string]string {
+	t.Helper()
+
+	g := NewWithT(t)
+	gr, err := gzip.NewReader(bytes.NewReader(data))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	files := map[string]string{}
+	tr := tar.NewReader(gr)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		g.Expect(err).ToNot(HaveOccurred())
+		content, err := io.ReadAll(tr)
+		g.Expect(err).ToNot(HaveOccurred())
+		files[hdr.Name] = string(content)
+	}
+	return files
+}
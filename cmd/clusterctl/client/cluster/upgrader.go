@@ -19,10 +19,15 @@ package cluster
 import (
 	"context"
 	"sort"
+	"strings"
 	"time"
 
 	"github.com/pkg/errors"
 	appsv1 "k8s.io/api/apps/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
 	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/apimachinery/pkg/util/version"
 	"k8s.io/apimachinery/pkg/util/wait"
@@ -33,9 +38,13 @@ import (
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 )
 
+// crdConversionCheckLimit is the number of objects listed for each version of a CRD to check conversions work.
+const crdConversionCheckLimit = 50
+
 // ProviderUpgrader defines methods for supporting provider upgrade.
 type ProviderUpgrader interface {
 	// Plan returns a set of suggested Upgrade plans for the management cluster.
@@ -46,6 +55,9 @@ type ProviderUpgrader interface {
 
 	// ApplyCustomPlan plan executes an upgrade using the UpgradeItems provided by the user.
 	ApplyCustomPlan(ctx context.Context, opts UpgradeOptions, providersToUpgrade ...UpgradeItem) error
+
+	// Rollback rolls back the providers changed by the last upgrade to the version they had before.
+	Rollback(ctx context.Context, opts UpgradeOptions) error
 }
 
 // UpgradePlan defines a list of possible upgrade targets for a management cluster.
@@ -58,6 +70,10 @@ type UpgradePlan struct {
 type UpgradeOptions struct {
 	WaitProviders       bool
 	WaitProviderTimeout time.Duration
+
+	// RollbackOnFailure instructs the upgrade to wait for the upgraded providers to be available and for the
+	// conversion of their CRDs to work, and to roll back the providers to their previous version if not.
+	RollbackOnFailure bool
 }
 
 // isPartialUpgrade returns true if at least one upgradeItem in the plan does not have a target version.
@@ -81,6 +97,12 @@ func (u *UpgradeItem) UpgradeRef() string {
 	return u.InstanceName()
 }
 
+// upgradeComponents are the components a provider should be upgraded to.
+type upgradeComponents struct {
+	UpgradeItem
+	components repository.Components
+}
+
 type providerUpgrader struct {
 	configClient            config.Client
 	proxy                   Proxy
@@ -170,7 +192,7 @@ func (u *providerUpgrader) ApplyPlan(ctx context.Context, opts UpgradeOptions, c
 	}
 
 	// Do the upgrade
-	return u.doUpgrade(ctx, upgradePlan, opts)
+	return u.doUpgrade(ctx, upgradePlan, opts, false)
 }
 
 func (u *providerUpgrader) ApplyCustomPlan(ctx context.Context, opts UpgradeOptions, upgradeItems ...UpgradeItem) error {
@@ -185,7 +207,50 @@ func (u *providerUpgrader) ApplyCustomPlan(ctx context.Context, opts UpgradeOpti
 	}
 
 	// Do the upgrade
-	return u.doUpgrade(ctx, upgradePlan, opts)
+	return u.doUpgrade(ctx, upgradePlan, opts, false)
+}
+
+func (u *providerUpgrader) Rollback(ctx context.Context, opts UpgradeOptions) error {
+	log := logf.Log
+	log.Info("Performing rollback...")
+
+	// Gets the providers changed by the last upgrade, and the version they had before.
+	providerList, err := u.providerInventory.List(ctx)
+	if err != nil {
+		return err
+	}
+	rollbackItems := []UpgradeItem{}
+	for _, provider := range providerList.Items {
+		previousVersion, ok := provider.Annotations[clusterctlv1.PreviousVersionAnnotation]
+		if !ok || previousVersion == "" || previousVersion == provider.Version {
+			continue
+		}
+		rollbackItems = append(rollbackItems, UpgradeItem{Provider: provider, NextVersion: previousVersion})
+	}
+	if len(rollbackItems) == 0 {
+		return errors.New("unable to roll back: no providers have been upgraded by clusterctl upgrade apply")
+	}
+
+	// Create a custom upgrade plan for going back to the previous versions, taking care of ensuring
+	// all the providers in the management cluster are consistent with the API Version of Cluster API (contract).
+	rollbackPlan, err := u.createCustomPlan(ctx, rollbackItems)
+	if err != nil {
+		return err
+	}
+
+	// Roll back using the same steps of an upgrade; there is nothing to roll back to if a rollback fails.
+	opts.RollbackOnFailure = false
+	if err := u.doUpgrade(ctx, rollbackPlan, opts, true); err != nil {
+		return err
+	}
+
+	// Forget the previous versions, so the rollback can't be rolled back.
+	for _, item := range rollbackPlan.Providers {
+		if err := u.setPreviousVersion(ctx, item.Provider, ""); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // getUpgradePlan returns the upgrade plan for a specific set of providers/contract
@@ -336,9 +401,11 @@ func (u *providerUpgrader) getUpgradeComponents(ctx context.Context, provider Up
 		return nil, err
 	}
 
+	// Re-apply the patches recorded in the inventory, unless different patches are defined in the clusterctl configuration.
 	options := repository.ComponentsOptions{
-		Version:         provider.NextVersion,
-		TargetNamespace: provider.Namespace,
+		Version:          provider.NextVersion,
+		TargetNamespace:  provider.Namespace,
+		InventoryPatches: provider.Patches,
 	}
 	components, err := providerRepository.Components().Get(ctx, options)
 	if err != nil {
@@ -347,7 +414,9 @@ func (u *providerUpgrader) getUpgradeComponents(ctx context.Context, provider Up
 	return components, nil
 }
 
-func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePlan, opts UpgradeOptions) error {
+// doUpgrade replaces the components of the providers in the upgrade plan with the components of the next version;
+// if isRollback is true, the next versions are the versions the providers had before the last upgrade.
+func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePlan, opts UpgradeOptions, isRollback bool) error {
 	// Check for multiple instances of the same provider if current contract is v1alpha3.
 	// TODO(killianmuldoon) Assess if we can remove this piece of code.
 	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
@@ -362,27 +431,89 @@ func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePl
 		return providers[a].GetProviderType().Order() < providers[b].GetProviderType().Order()
 	})
 
-	// Migrate CRs to latest CRD storage version, if necessary.
-	// Note: We have to do this before the providers are scaled down or deleted
-	// so conversion webhooks still work.
+	// Make sure each upgrade item carries the provider as currently recorded in the inventory, so the
+	// version being replaced is known also for items created from user input.
+	providerList, err := u.providerInventory.List(ctx)
+	if err != nil {
+		return err
+	}
+	for i := range providers {
+		for _, p := range providerList.Items {
+			if p.Namespace == providers[i].Namespace && p.Name == providers[i].Name {
+				providers[i].Provider = p
+				break
+			}
+		}
+	}
+
+	// Gets the provider components for the target versions.
+	upgradeQueue := []upgradeComponents{}
 	for _, upgradeItem := range providers {
 		// If there is not a specified next version, skip it (we are already up-to-date).
 		if upgradeItem.NextVersion == "" {
 			continue
 		}
 
-		// Gets the provider components for the target version.
 		components, err := u.getUpgradeComponents(ctx, upgradeItem)
 		if err != nil {
 			return err
 		}
+		upgradeQueue = append(upgradeQueue, upgradeComponents{UpgradeItem: upgradeItem, components: components})
+	}
+
+	// Snapshot the provider components for the current versions, so it is possible to roll back
+	// without depending on the repositories being reachable when the upgrade fails.
+	rollbackQueue := []upgradeComponents{}
+	if opts.RollbackOnFailure {
+		for _, item := range upgradeQueue {
+			rollbackItem := UpgradeItem{Provider: item.Provider, NextVersion: item.Version}
+			components, err := u.getUpgradeComponents(ctx, rollbackItem)
+			if err != nil {
+				return errors.Wrapf(err, "failed to get the components of the current version of provider %s, required to roll back the upgrade", item.InstanceName())
+			}
+			rollbackQueue = append(rollbackQueue, upgradeComponents{UpgradeItem: rollbackItem, components: components})
+		}
+	}
+
+	if isRollback {
+		// Refuse to roll back if objects could be stored in versions unknown to the previous CRDs.
+		if err := u.checkRollbackStoredVersions(ctx, upgradeQueue); err != nil {
+			return errors.Wrap(err, "unable to roll back")
+		}
+	} else {
+		// Record the versions being replaced before changing anything, so the upgrade can be rolled back
+		// even if it fails halfway.
+		if err := u.recordPreviousVersions(ctx, upgradeQueue); err != nil {
+			return err
+		}
+	}
+
+	if err := u.applyComponents(ctx, upgradePlan.Contract, upgradeQueue); err != nil {
+		return u.rollback(ctx, upgradePlan.Contract, rollbackQueue, opts, err)
+	}
+
+	if opts.RollbackOnFailure {
+		if err := u.verifyUpgrade(ctx, upgradeQueue, opts); err != nil {
+			return u.rollback(ctx, upgradePlan.Contract, rollbackQueue, opts, err)
+		}
+		return nil
+	}
+
+	return waitForProvidersReady(ctx, InstallOptions{WaitProviders: opts.WaitProviders, WaitProviderTimeout: opts.WaitProviderTimeout}, componentsOf(upgradeQueue), u.proxy)
+}
 
+// applyComponents replaces the components of the providers with the given ones.
+func (u *providerUpgrader) applyComponents(ctx context.Context, contract string, queue []upgradeComponents) error {
+	// Migrate CRs to latest CRD storage version, if necessary.
+	// Note: We have to do this before the providers are scaled down or deleted
+	// so conversion webhooks still work.
+	for _, item := range queue {
 		c, err := u.proxy.NewClient(ctx)
 		if err != nil {
 			return err
 		}
 
-		if err := NewCRDMigrator(c).Run(ctx, components.Objs()); err != nil {
+		if err := NewCRDMigrator(c).Run(ctx, item.components.Objs()); err != nil {
 			return err
 		}
 	}
@@ -395,38 +526,17 @@ func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePl
 	// * new provider Pods fail to startup because they try to list resources.
 	// * list resources fails, because the API server hits the old provider Pod when trying to
 	//   call the conversion webhook for those resources.
-	for _, upgradeItem := range providers {
-		// If there is not a specified next version, skip it (we are already up-to-date).
-		if upgradeItem.NextVersion == "" {
-			continue
-		}
-
-		// Scale down provider.
-		if err := u.scaleDownProvider(ctx, upgradeItem.Provider); err != nil {
+	for _, item := range queue {
+		if err := u.scaleDownProvider(ctx, item.Provider); err != nil {
 			return err
 		}
 	}
 
-	installQueue := []repository.Components{}
-
-	// Delete old providers and deploy new ones if necessary, i.e. there is a NextVersion.
-	for _, upgradeItem := range providers {
-		// If there is not a specified next version, skip it (we are already up-to-date).
-		if upgradeItem.NextVersion == "" {
-			continue
-		}
-
-		// Gets the provider components for the target version.
-		components, err := u.getUpgradeComponents(ctx, upgradeItem)
-		if err != nil {
-			return err
-		}
-
-		installQueue = append(installQueue, components)
-
+	// Delete old providers and deploy new ones.
+	for _, item := range queue {
 		// Delete the provider, preserving CRD, namespace and the inventory.
 		if err := u.providerComponents.Delete(ctx, DeleteOptions{
-			Provider:         upgradeItem.Provider,
+			Provider:         item.Provider,
 			IncludeNamespace: false,
 			IncludeCRDs:      false,
 			SkipInventory:    true,
@@ -435,19 +545,206 @@ func (u *providerUpgrader) doUpgrade(ctx context.Context, upgradePlan *UpgradePl
 		}
 
 		// Install the new version of the provider components.
-		if err := installComponentsAndUpdateInventory(ctx, components, u.providerComponents, u.providerInventory); err != nil {
+		if err := installComponentsAndUpdateInventory(ctx, item.components, u.providerComponents, u.providerInventory); err != nil {
 			return err
 		}
 	}
 
 	// Delete webhook namespace since it's not needed from v1alpha4.
-	if upgradePlan.Contract == clusterv1.GroupVersion.Version {
+	if contract == clusterv1.GroupVersion.Version {
 		if err := u.providerComponents.DeleteWebhookNamespace(ctx); err != nil {
 			return err
 		}
 	}
+	return nil
+}
+
+// verifyUpgrade checks that the Deployments of the upgraded providers become available and that
+// the conversion webhooks of their CRDs work.
+func (u *providerUpgrader) verifyUpgrade(ctx context.Context, queue []upgradeComponents, opts UpgradeOptions) error {
+	log := logf.Log
+	log.Info("Waiting for upgraded providers to be available...")
+
+	components := componentsOf(queue)
+	if err := waitManagerDeploymentsReady(ctx, InstallOptions{WaitProviders: true, WaitProviderTimeout: opts.WaitProviderTimeout}, components, u.proxy); err != nil {
+		return err
+	}
+
+	log.Info("Checking conversion of upgraded CRDs...")
+	var lastErr error
+	if err := wait.PollUntilContextTimeout(ctx, time.Second, opts.WaitProviderTimeout, true, func(ctx context.Context) (bool, error) {
+		lastErr = u.checkCRDConversions(ctx, components)
+		return lastErr == nil, nil
+	}); err != nil {
+		if lastErr != nil {
+			return errors.Wrapf(lastErr, "CRD conversions are not working after %s", opts.WaitProviderTimeout)
+		}
+		return err
+	}
+	return nil
+}
+
+// checkCRDConversions lists objects in all the served versions of the CRDs using a conversion webhook,
+// so the API server has to call the webhook of the provider to convert objects stored in a different version.
+func (u *providerUpgrader) checkCRDConversions(ctx context.Context, components []repository.Components) error {
+	c, err := u.proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range components {
+		for _, obj := range item.Objs() {
+			if obj.GetKind() != "CustomResourceDefinition" {
+				continue
+			}
+
+			crd := &apiextensionsv1.CustomResourceDefinition{}
+			if err := c.Get(ctx, client.ObjectKey{Name: obj.GetName()}, crd); err != nil {
+				return errors.Wrapf(err, "failed to get CustomResourceDefinition %s", obj.GetName())
+			}
+			if crd.Spec.Conversion == nil || crd.Spec.Conversion.Strategy != apiextensionsv1.WebhookConverter {
+				continue
+			}
+
+			for _, version := range crd.Spec.Versions {
+				if !version.Served {
+					continue
+				}
+				list := &unstructured.UnstructuredList{}
+				list.SetAPIVersion(schema.GroupVersion{Group: crd.Spec.Group, Version: version.Name}.String())
+				list.SetKind(crd.Spec.Names.ListKind)
+				if err := c.List(ctx, list, client.Limit(crdConversionCheckLimit)); err != nil {
+					return errors.Wrapf(err, "failed to list %s in version %s", crd.Spec.Names.Kind, version.Name)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// rollback re-installs the snapshotted components of the previous versions of the providers after a failed upgrade.
+func (u *providerUpgrader) rollback(ctx context.Context, contract string, queue []upgradeComponents, opts UpgradeOptions, upgradeErr error) error {
+	if !opts.RollbackOnFailure {
+		return upgradeErr
+	}
+
+	log := logf.Log
+	log.Info("Upgrade failed, rolling back providers to the previous version", "error", upgradeErr.Error())
+
+	if err := u.checkRollbackStoredVersions(ctx, queue); err != nil {
+		return errors.Wrapf(upgradeErr, "upgrade failed and it is not possible to roll back: %v", err)
+	}
+	if err := u.applyComponents(ctx, contract, queue); err != nil {
+		return errors.Wrapf(upgradeErr, "upgrade failed and rollback failed with %v", err)
+	}
+	if err := waitManagerDeploymentsReady(ctx, InstallOptions{WaitProviders: true, WaitProviderTimeout: opts.WaitProviderTimeout}, componentsOf(queue), u.proxy); err != nil {
+		return errors.Wrapf(upgradeErr, "upgrade failed and providers are not ready after rollback: %v", err)
+	}
+	return errors.Wrap(upgradeErr, "upgrade failed, providers have been rolled back to the previous version")
+}
+
+// recordPreviousVersions records in the inventory the versions the providers have before the upgrade,
+// so the upgrade can be rolled back later.
+func (u *providerUpgrader) recordPreviousVersions(ctx context.Context, queue []upgradeComponents) error {
+	for _, item := range queue {
+		if item.NextVersion == item.Version {
+			continue
+		}
+		if err := u.setPreviousVersion(ctx, item.Provider, item.Version); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRollbackStoredVersions checks that all the versions in status.storedVersions of the current CRDs exist in the
+// CRDs of the previous versions of the providers; if not, objects could be stored in a version unknown to the previous
+// versions, and re-installing their CRDs would fail or make those objects unreadable.
+func (u *providerUpgrader) checkRollbackStoredVersions(ctx context.Context, queue []upgradeComponents) error {
+	c, err := u.proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, item := range queue {
+		for _, obj := range item.components.Objs() {
+			if obj.GetKind() != "CustomResourceDefinition" {
+				continue
+			}
+
+			previousCRD := &apiextensionsv1.CustomResourceDefinition{}
+			if err := scheme.Scheme.Convert(&obj, previousCRD, nil); err != nil {
+				return errors.Wrapf(err, "failed to convert CRD %q", obj.GetName())
+			}
+			previousVersions := sets.Set[string]{}
+			for _, version := range previousCRD.Spec.Versions {
+				previousVersions.Insert(version.Name)
+			}
+
+			// If the CRD does not exist, there are no objects stored for it.
+			currentCRD := &apiextensionsv1.CustomResourceDefinition{}
+			exists := true
+			if err := retryWithExponentialBackoff(ctx, newReadBackoff(), func(ctx context.Context) error {
+				if err := c.Get(ctx, client.ObjectKeyFromObject(previousCRD), currentCRD); err != nil {
+					if apierrors.IsNotFound(err) {
+						exists = false
+						return nil
+					}
+					return err
+				}
+				return nil
+			}); err != nil {
+				return errors.Wrapf(err, "failed to get CustomResourceDefinition %s", previousCRD.Name)
+			}
+			if !exists {
+				continue
+			}
+
+			if unknownVersions := sets.New[string](currentCRD.Status.StoredVersions...).Difference(previousVersions); unknownVersions.Len() > 0 {
+				return errors.Errorf("CustomResourceDefinition %s has objects stored in versions %s which do not exist in provider %s version %s",
+					currentCRD.Name, strings.Join(sets.List(unknownVersions), ", "), item.InstanceName(), item.NextVersion)
+			}
+		}
+	}
+	return nil
+}
+
+// setPreviousVersion sets the PreviousVersionAnnotation on the inventory object of a provider; an empty version removes it.
+func (u *providerUpgrader) setPreviousVersion(ctx context.Context, provider clusterctlv1.Provider, version string) error {
+	return retryWithExponentialBackoff(ctx, newWriteBackoff(), func(ctx context.Context) error {
+		c, err := u.proxy.NewClient(ctx)
+		if err != nil {
+			return err
+		}
 
-	return waitForProvidersReady(ctx, InstallOptions(opts), installQueue, u.proxy)
+		current := &clusterctlv1.Provider{}
+		if err := c.Get(ctx, client.ObjectKeyFromObject(&provider), current); err != nil {
+			return errors.Wrapf(err, "failed to get provider object %s", provider.InstanceName())
+		}
+
+		annotations := current.GetAnnotations()
+		if annotations == nil {
+			annotations = map[string]string{}
+		}
+		if version == "" {
+			delete(annotations, clusterctlv1.PreviousVersionAnnotation)
+		} else {
+			annotations[clusterctlv1.PreviousVersionAnnotation] = version
+		}
+		current.SetAnnotations(annotations)
+		if err := c.Update(ctx, current); err != nil {
+			return errors.Wrapf(err, "failed to update provider object %s", provider.InstanceName())
+		}
+		return nil
+	})
+}
+
+func componentsOf(queue []upgradeComponents) []repository.Components {
+	components := make([]repository.Components, 0, len(queue))
+	for _, item := range queue {
+		components = append(components, item.components)
+	}
+	return components
 }
 
 func (u *providerUpgrader) scaleDownProvider(ctx context.Context, provider clusterctlv1.Provider) error {
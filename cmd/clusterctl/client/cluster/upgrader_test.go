@@ -22,6 +22,9 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
@@ -1062,3 +1065,188 @@ func Test_providerUpgrader_ApplyCustomPlan(t *testing.T) {
 		})
 	}
 }
+
+func Test_providerUpgrader_getUpgradeComponents(t *testing.T) {
+	componentsYaml := []byte(`apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: infra-controller-manager
+  namespace: infra-system
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        image: registry.k8s.io/infra:v2.0.1
+`)
+	inventoryPatches := []clusterctlv1.ProviderPatch{
+		{
+			Target: &clusterctlv1.PatchSelector{Kind: "Deployment"},
+			Patch:  "- op: add\n  path: /spec/replicas\n  value: 2\n",
+		},
+	}
+
+	tests := []struct {
+		name         string
+		reader       *test.FakeReader
+		wantReplicas interface{}
+		wantPatches  []clusterctlv1.ProviderPatch
+	}{
+		{
+			name: "re-applies the patches recorded in the inventory",
+			reader: test.NewFakeReader().
+				WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com"),
+			wantReplicas: 2,
+			wantPatches:  inventoryPatches,
+		},
+		{
+			name: "applies the patches defined in the clusterctl configuration instead of the patches recorded in the inventory",
+			reader: test.NewFakeReader().
+				WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com").
+				WithVar(config.PatchesConfigKey, "infrastructure-infra: []\n"),
+			wantReplicas: nil,
+			wantPatches:  []clusterctlv1.ProviderPatch{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			configClient, _ := config.New(ctx, "", config.InjectReader(tt.reader))
+			repo := repository.NewMemoryRepository().
+				WithPaths("root", "components.yaml").
+				WithVersions("v2.0.0", "v2.0.1").
+				WithFile("v2.0.1", "components.yaml", componentsYaml)
+
+			u := &providerUpgrader{
+				configClient: configClient,
+				repositoryClientFactory: func(ctx context.Context, provider config.Provider, configClient config.Client, _ ...repository.Option) (repository.Client, error) {
+					return repository.New(ctx, provider, configClient, repository.InjectRepository(repo))
+				},
+			}
+
+			provider := fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system")
+			provider.Patches = inventoryPatches
+			components, err := u.getUpgradeComponents(ctx, UpgradeItem{Provider: provider, NextVersion: "v2.0.1"})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(components.InventoryObject().Patches).To(Equal(tt.wantPatches))
+			for _, o := range components.Objs() {
+				if o.GetKind() != "Deployment" {
+					continue
+				}
+				if tt.wantReplicas == nil {
+					g.Expect(o.Object["spec"]).ToNot(HaveKey("replicas"))
+					continue
+				}
+				g.Expect(o.Object["spec"]).To(HaveKeyWithValue("replicas", BeNumerically("==", tt.wantReplicas)))
+			}
+		})
+	}
+}
+
+func Test_providerUpgrader_checkRollbackStoredVersions(t *testing.T) {
+	previousComponentsYaml := []byte(`apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: foos.infrastructure.cluster.x-k8s.io
+spec:
+  group: infrastructure.cluster.x-k8s.io
+  names:
+    kind: Foo
+    listKind: FooList
+    plural: foos
+    singular: foo
+  scope: Namespaced
+  versions:
+  - name: v1alpha1
+    served: true
+    storage: true
+`)
+
+	currentCRD := func(storedVersions ...string) *apiextensionsv1.CustomResourceDefinition {
+		return &apiextensionsv1.CustomResourceDefinition{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: apiextensionsv1.SchemeGroupVersion.String(),
+				Kind:       "CustomResourceDefinition",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name: "foos.infrastructure.cluster.x-k8s.io",
+			},
+			Spec: apiextensionsv1.CustomResourceDefinitionSpec{
+				Group: "infrastructure.cluster.x-k8s.io",
+				Names: apiextensionsv1.CustomResourceDefinitionNames{
+					Kind:     "Foo",
+					ListKind: "FooList",
+					Plural:   "foos",
+					Singular: "foo",
+				},
+				Scope: apiextensionsv1.NamespaceScoped,
+				Versions: []apiextensionsv1.CustomResourceDefinitionVersion{
+					{Name: "v1alpha1", Served: true},
+					{Name: "v1alpha2", Served: true, Storage: true},
+				},
+			},
+			Status: apiextensionsv1.CustomResourceDefinitionStatus{
+				StoredVersions: storedVersions,
+			},
+		}
+	}
+
+	tests := []struct {
+		name    string
+		objs    []client.Object
+		wantErr bool
+	}{
+		{
+			name: "pass if the CRD does not exist",
+		},
+		{
+			name: "pass if all the stored versions exist in the previous CRD",
+			objs: []client.Object{currentCRD("v1alpha1")},
+		},
+		{
+			name:    "fail if objects are stored in a version which does not exist in the previous CRD",
+			objs:    []client.Object{currentCRD("v1alpha1", "v1alpha2")},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ctx := context.Background()
+
+			configClient, _ := config.New(ctx, "", config.InjectReader(test.NewFakeReader().
+				WithProvider("infra", clusterctlv1.InfrastructureProviderType, "https://somewhere.com")))
+			repo := repository.NewMemoryRepository().
+				WithPaths("root", "components.yaml").
+				WithVersions("v2.0.0", "v2.0.1").
+				WithFile("v2.0.0", "components.yaml", previousComponentsYaml)
+
+			u := &providerUpgrader{
+				configClient: configClient,
+				proxy:        test.NewFakeProxy().WithObjs(tt.objs...),
+				repositoryClientFactory: func(ctx context.Context, provider config.Provider, configClient config.Client, _ ...repository.Option) (repository.Client, error) {
+					return repository.New(ctx, provider, configClient, repository.InjectRepository(repo))
+				},
+			}
+
+			rollbackItem := UpgradeItem{
+				Provider:    fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"),
+				NextVersion: "v2.0.0",
+			}
+			components, err := u.getUpgradeComponents(ctx, rollbackItem)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = u.checkRollbackStoredVersions(ctx, []upgradeComponents{{UpgradeItem: rollbackItem, components: components}})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+		})
+	}
+}
@@ -24,6 +24,7 @@ import (
 	"k8s.io/apimachinery/pkg/util/validation"
 
 	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/repository"
 )
 
@@ -60,6 +61,11 @@ func (c *clusterctlClient) getComponentsByName(ctx context.Context, provider str
 	return components, nil
 }
 
+// skipVerification disables the verification of the files fetched from provider repositories for all the providers.
+func (c *clusterctlClient) skipVerification() {
+	c.configClient.Variables().Set(config.VerificationConfigKey, "all:\n  skip: true\n")
+}
+
 // parseProviderName defines a utility function that parses the abbreviated syntax for name[:version].
 func parseProviderName(provider string) (name string, version string, err error) {
 	t := strings.Split(strings.ToLower(provider), ":")
@@ -68,3 +68,19 @@ func Test_parseProviderName(t *testing.T) {
 		})
 	}
 }
+
+func Test_clusterctlClient_skipVerification(t *testing.T) {
+	g := NewWithT(t)
+
+	client := newFakeClient(ctx, nil)
+
+	verification, err := client.configClient.Verification().Get("infrastructure-aws")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(verification.Skip).To(BeFalse())
+
+	client.internalClient.skipVerification()
+
+	verification, err = client.configClient.Verification().Get("infrastructure-aws")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(verification.Skip).To(BeTrue())
+}
@@ -80,10 +80,14 @@ func (c *clusterctlClient) ProcessYAML(ctx context.Context, options ProcessYAMLO
 		if err != nil {
 			return nil, err
 		}
+		processor, err := c.templateProcessor("", "")
+		if err != nil {
+			return nil, err
+		}
 		return repository.NewTemplate(repository.TemplateInput{
 			RawArtifact:           content,
 			ConfigVariablesClient: c.configClient.Variables(),
-			Processor:             yaml.NewSimpleProcessor(),
+			Processor:             processor,
 			TargetNamespace:       "",
 			SkipTemplateProcess:   options.SkipTemplateProcess,
 		})
@@ -93,10 +97,15 @@ func (c *clusterctlClient) ProcessYAML(ctx context.Context, options ProcessYAMLO
 	// leveraging the template client which exposes GetFromURL() is available
 	// on the cluster client so we create a cluster client with default
 	// configs to access it.
+	processor, err := c.templateProcessor("", "")
+	if err != nil {
+		return nil, err
+	}
 	clstr, err := c.clusterClientFactory(
 		ClusterClientFactoryInput{
 			// use the default kubeconfig
 			Kubeconfig: Kubeconfig{},
+			Processor:  processor,
 		},
 	)
 	if err != nil {
@@ -151,7 +160,8 @@ type GetClusterTemplateOptions struct {
 	ListVariablesOnly bool
 
 	// YamlProcessor defines the yaml processor to use for the cluster
-	// template processing. If not defined, SimpleProcessor will be used.
+	// template processing. If not defined, the processor defined in the clusterctl
+	// configuration will be used, defaulting to SimpleProcessor.
 	YamlProcessor Processor
 }
 
@@ -217,8 +227,18 @@ func (c *clusterctlClient) GetClusterTemplate(ctx context.Context, options GetCl
 		options.ProviderRepositorySource = &ProviderRepositorySourceOptions{}
 	}
 
+	// If the yaml processor is not set, use the one defined in the clusterctl configuration; when reading the template
+	// from a provider repository, the processor is selected later, once the provider is known.
+	processor := options.YamlProcessor
+	if processor == nil && options.ProviderRepositorySource == nil {
+		var err error
+		if processor, err = c.templateProcessor("", ""); err != nil {
+			return nil, err
+		}
+	}
+
 	// Gets  the client for the current management cluster
-	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{options.Kubeconfig, options.YamlProcessor})
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{options.Kubeconfig, processor})
 	if err != nil {
 		return nil, err
 	}
@@ -332,6 +352,12 @@ func (c *clusterctlClient) getTemplateFromRepository(ctx context.Context, cluste
 		return nil, err
 	}
 
+	if processor == nil {
+		if processor, err = c.templateProcessor(providerConfig.ManifestLabel(), source.Flavor); err != nil {
+			return nil, err
+		}
+	}
+
 	repo, err := c.repositoryClientFactory(ctx, RepositoryClientFactoryInput{Provider: providerConfig, Processor: processor})
 	if err != nil {
 		return nil, err
@@ -376,6 +402,16 @@ func (c *clusterctlClient) getTemplateFromURL(ctx context.Context, cluster clust
 	return cluster.Template().GetFromURL(ctx, source.URL, targetNamespace, listVariablesOnly)
 }
 
+// templateProcessor returns the yaml processor defined in the clusterctl configuration for the templates of the
+// provider with the given label and flavor; if none is defined, the simple processor is used.
+func (c *clusterctlClient) templateProcessor(providerLabel, flavor string) (Processor, error) {
+	name, err := c.configClient.TemplateProcessors().Get(providerLabel, flavor)
+	if err != nil {
+		return nil, err
+	}
+	return yaml.NewProcessor(name)
+}
+
 // templateOptionsToVariables injects some of the templateOptions to the configClient so they can be consumed as a variables from the template.
 func (c *clusterctlClient) templateOptionsToVariables(options GetClusterTemplateOptions) error {
 	// the TargetNamespace, if valid, can be used in templates using the ${ NAMESPACE } variable.
@@ -28,6 +28,8 @@ import (
 // 2. The configuration of the providers (name, type and URL of the provider repository)
 // 3. Variables used when installing providers/creating clusters. Variables can be read from the environment or from the config file
 // 4. The configuration about image overrides.
+// 5. The configuration about the verification of the files fetched from provider repositories.
+// 6. The patches to be applied to provider components.
 type Client interface {
 	// CertManager provide access to the cert-manager configurations.
 	CertManager() CertManagerClient
@@ -40,6 +42,15 @@ type Client interface {
 
 	// ImageMeta provide access to image meta configurations.
 	ImageMeta() ImageMetaClient
+
+	// Verification provide access to the configurations for verifying files fetched from provider repositories.
+	Verification() VerificationClient
+
+	// Patches provide access to the patches to be applied to provider components.
+	Patches() PatchesClient
+
+	// TemplateProcessors provide access to the template processors to be used for cluster templates.
+	TemplateProcessors() TemplateProcessorsClient
 }
 
 // configClient implements Client.
@@ -66,6 +77,18 @@ func (c *configClient) ImageMeta() ImageMetaClient {
 	return newImageMetaClient(c.reader)
 }
 
+func (c *configClient) Verification() VerificationClient {
+	return newVerificationClient(c.reader)
+}
+
+func (c *configClient) Patches() PatchesClient {
+	return newPatchesClient(c.reader)
+}
+
+func (c *configClient) TemplateProcessors() TemplateProcessorsClient {
+	return newTemplateProcessorsClient(c.reader)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*configClient)
 
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/pkg/errors"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+)
+
+const (
+	// PatchesConfigKey defines the name of the top level config key for the patches to be applied to provider components.
+	PatchesConfigKey = "patches"
+
+	allPatchesConfig = "all"
+)
+
+// PatchesClient has methods to work with the patches to be applied to provider components.
+type PatchesClient interface {
+	// Get returns the patches that apply to the provider with the given label, e.g. infrastructure-aws;
+	// the patches for all the providers are applied before the patches for the provider.
+	// If no patches are defined for the provider nor for all the providers, nil is returned, while an
+	// empty list is returned if the configuration explicitly defines no patches.
+	Get(providerLabel string) ([]clusterctlv1.ProviderPatch, error)
+}
+
+// patchesClient implements PatchesClient.
+type patchesClient struct {
+	reader Reader
+}
+
+// ensure patchesClient implements PatchesClient.
+var _ PatchesClient = &patchesClient{}
+
+func newPatchesClient(reader Reader) *patchesClient {
+	return &patchesClient{
+		reader: reader,
+	}
+}
+
+func (p *patchesClient) Get(providerLabel string) ([]clusterctlv1.ProviderPatch, error) {
+	var config map[string][]clusterctlv1.ProviderPatch
+	if err := p.reader.UnmarshalKey(PatchesConfigKey, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal patches configurations")
+	}
+
+	var patches []clusterctlv1.ProviderPatch
+	for _, key := range []string{allPatchesConfig, providerLabel} {
+		keyPatches, ok := config[key]
+		if !ok {
+			continue
+		}
+		if patches == nil {
+			patches = []clusterctlv1.ProviderPatch{}
+		}
+		for i, patch := range keyPatches {
+			if patch.Patch == "" {
+				return nil, errors.Errorf("invalid patches configuration for %q: patch %d is empty", key, i)
+			}
+			patches = append(patches, patch)
+		}
+	}
+	return patches, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_patchesClient_Get(t *testing.T) {
+	replicasPatch := clusterctlv1.ProviderPatch{
+		Target: &clusterctlv1.PatchSelector{Kind: "Deployment"},
+		Patch:  "- op: replace\n  path: /spec/replicas\n  value: 2\n",
+	}
+	tolerationsPatch := clusterctlv1.ProviderPatch{
+		Patch: "apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: capa-controller-manager\n",
+	}
+
+	tests := []struct {
+		name          string
+		config        string
+		providerLabel string
+		want          []clusterctlv1.ProviderPatch
+		wantErr       bool
+	}{
+		{
+			name:          "no patches config",
+			providerLabel: "infrastructure-aws",
+			want:          nil,
+		},
+		{
+			name: "patches for all the providers are applied before the patches for the provider",
+			config: `infrastructure-aws:
+- patch: |
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: capa-controller-manager
+all:
+- target:
+    kind: Deployment
+  patch: |
+    - op: replace
+      path: /spec/replicas
+      value: 2
+`,
+			providerLabel: "infrastructure-aws",
+			want:          []clusterctlv1.ProviderPatch{replicasPatch, tolerationsPatch},
+		},
+		{
+			name: "patches for other providers are ignored",
+			config: `infrastructure-aws:
+- patch: |
+    apiVersion: apps/v1
+    kind: Deployment
+    metadata:
+      name: capa-controller-manager
+`,
+			providerLabel: "infrastructure-docker",
+			want:          nil,
+		},
+		{
+			name:          "an empty list of patches for the provider is returned",
+			config:        "infrastructure-aws: []\n",
+			providerLabel: "infrastructure-aws",
+			want:          []clusterctlv1.ProviderPatch{},
+		},
+		{
+			name: "fails for empty patches",
+			config: `infrastructure-aws:
+- target:
+    kind: Deployment
+`,
+			providerLabel: "infrastructure-aws",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := test.NewFakeReader()
+			if tt.config != "" {
+				reader.WithVar(PatchesConfigKey, tt.config)
+			}
+
+			got, err := newPatchesClient(reader).Get(tt.providerLabel)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -31,6 +31,7 @@ import (
 type MemoryReader struct {
 	variables map[string]string
 	providers []configProvider
+	images    map[string]imageMeta
 }
 
 var _ Reader = &MemoryReader{}
@@ -40,6 +41,7 @@ func NewMemoryReader() *MemoryReader {
 	return &MemoryReader{
 		variables: map[string]string{},
 		providers: []configProvider{},
+		images:    map[string]imageMeta{},
 	}
 }
 
@@ -51,9 +53,9 @@ func (f *MemoryReader) Init(_ context.Context, _ string) error {
 	}
 	f.variables["providers"] = string(data)
 
-	// images is not used by the operator, but it is read by the clusterctrl
-	// code, so we need a correct empty "images".
-	data, err = yaml.Marshal(map[string]imageMeta{})
+	// images is read by the clusterctl code, so we need a correct "images"
+	// entry also when no image overrides are set.
+	data, err = yaml.Marshal(f.images)
 	if err != nil {
 		return err
 	}
@@ -99,3 +101,23 @@ func (f *MemoryReader) AddProvider(name string, ttype clusterctlv1.ProviderType,
 
 	return f, nil
 }
+
+// AddImageOverride adds an override for the images of the given component to the "images" map entry and returns any errors.
+// If component is empty, the override applies to all the components.
+func (f *MemoryReader) AddImageOverride(component, repository, tag string) (*MemoryReader, error) {
+	if component == "" {
+		component = allImageConfig
+	}
+	f.images[component] = imageMeta{
+		Repository: repository,
+		Tag:        tag,
+	}
+
+	data, err := yaml.Marshal(f.images)
+	if err != nil {
+		return f, err
+	}
+	f.variables[imagesConfigKey] = string(data)
+
+	return f, nil
+}
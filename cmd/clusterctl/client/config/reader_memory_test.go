@@ -31,6 +31,7 @@ func TestMemoryReader(t *testing.T) {
 		variables  map[string]string
 		providers  []configProvider
 		imageMetas map[string]imageMeta
+		images     []imageOverride
 		wantErr    bool
 	}{
 		{
@@ -49,6 +50,19 @@ func TestMemoryReader(t *testing.T) {
 				"three": "3",
 			},
 		},
+		{
+			name:      "image overrides",
+			providers: []configProvider{},
+			images: []imageOverride{
+				{component: "", repository: "example.com/all"},
+				{component: "cluster-api", repository: "example.com/capi", tag: "v1.0.0"},
+			},
+			imageMetas: map[string]imageMeta{
+				"all":         {Repository: "example.com/all"},
+				"cluster-api": {Repository: "example.com/capi", Tag: "v1.0.0"},
+			},
+			variables: map[string]string{},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -62,6 +76,10 @@ func TestMemoryReader(t *testing.T) {
 				_, err := f.AddProvider(p.Name, p.Type, p.URL)
 				g.Expect(err).ToNot(HaveOccurred())
 			}
+			for _, i := range tt.images {
+				_, err := f.AddImageOverride(i.component, i.repository, i.tag)
+				g.Expect(err).ToNot(HaveOccurred())
+			}
 			for n, v := range tt.variables {
 				f.Set(n, v)
 			}
@@ -85,3 +103,9 @@ func TestMemoryReader(t *testing.T) {
 		})
 	}
 }
+
+type imageOverride struct {
+	component  string
+	repository string
+	tag        string
+}
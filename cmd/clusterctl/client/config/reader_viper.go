@@ -30,6 +30,7 @@ import (
 	"github.com/adrg/xdg"
 	"github.com/pkg/errors"
 	"github.com/spf13/viper"
+	"sigs.k8s.io/yaml"
 
 	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
 )
@@ -188,6 +189,10 @@ func (v *viperReader) Set(key, value string) {
 }
 
 func (v *viperReader) UnmarshalKey(key string, rawval interface{}) error {
+	// Values set as a string, e.g. using Set, are expected to be in YAML format, like for the MemoryReader.
+	if value, ok := viper.Get(key).(string); ok {
+		return yaml.Unmarshal([]byte(value), rawval)
+	}
 	return viper.UnmarshalKey(key, rawval)
 }
 
@@ -27,6 +27,7 @@ import (
 	"testing"
 
 	. "github.com/onsi/gomega"
+	"github.com/spf13/viper"
 )
 
 func Test_viperReader_Init(t *testing.T) {
@@ -260,6 +261,42 @@ func Test_viperReader_Set(t *testing.T) {
 	}
 }
 
+func Test_viperReader_UnmarshalKey(t *testing.T) {
+	g := NewWithT(t)
+
+	dir, err := os.MkdirTemp("", "clusterctl")
+	g.Expect(err).ToNot(HaveOccurred())
+	defer os.RemoveAll(dir)
+
+	configFile := filepath.Join(dir, "clusterctl.yaml")
+
+	g.Expect(os.WriteFile(configFile, []byte("cert-manager:\n  url: https://example.com/cert-manager.yaml\n"), 0600)).To(Succeed())
+
+	ctx := context.Background()
+
+	v := &viperReader{}
+	g.Expect(v.Init(ctx, configFile)).To(Succeed())
+
+	t.Run("reads a value from the config file", func(t *testing.T) {
+		g := NewWithT(t)
+
+		got := &configCertManager{}
+		g.Expect(v.UnmarshalKey(CertManagerConfigKey, got)).To(Succeed())
+		g.Expect(got.URL).To(Equal("https://example.com/cert-manager.yaml"))
+	})
+
+	t.Run("reads a value set in YAML format", func(t *testing.T) {
+		g := NewWithT(t)
+
+		defer viper.Set(ProvidersConfigKey, nil)
+		v.Set(ProvidersConfigKey, "- name: foo\n  type: InfrastructureProvider\n  url: bundle:///bundle.tar.gz\n")
+
+		got := []configProvider{}
+		g.Expect(v.UnmarshalKey(ProvidersConfigKey, &got)).To(Succeed())
+		g.Expect(got).To(Equal([]configProvider{{Name: "foo", Type: "InfrastructureProvider", URL: "bundle:///bundle.tar.gz"}}))
+	})
+}
+
 func Test_viperReader_checkDefaultConfig(t *testing.T) {
 	g := NewWithT(t)
 	dir, err := os.MkdirTemp("", "clusterctl")
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+const (
+	// TemplateProcessorsConfigKey defines the name of the top level config key for the template processors.
+	TemplateProcessorsConfigKey = "templateProcessors"
+
+	allTemplateProcessorsConfig = "all"
+)
+
+// TemplateProcessorsClient has methods to work with the template processors to be used for cluster templates.
+type TemplateProcessorsClient interface {
+	// Get returns the name of the template processor to be used for the templates of the provider with the
+	// given label, e.g. infrastructure-aws, and the given flavor. The processor defined for "<provider-label>/<flavor>"
+	// takes precedence over the processor defined for the provider, which takes precedence over the processor
+	// defined for all the providers. If no processor is defined, an empty string is returned.
+	Get(providerLabel, flavor string) (string, error)
+}
+
+// templateProcessorsClient implements TemplateProcessorsClient.
+type templateProcessorsClient struct {
+	reader Reader
+}
+
+// ensure templateProcessorsClient implements TemplateProcessorsClient.
+var _ TemplateProcessorsClient = &templateProcessorsClient{}
+
+func newTemplateProcessorsClient(reader Reader) *templateProcessorsClient {
+	return &templateProcessorsClient{
+		reader: reader,
+	}
+}
+
+func (p *templateProcessorsClient) Get(providerLabel, flavor string) (string, error) {
+	var config map[string]string
+	if err := p.reader.UnmarshalKey(TemplateProcessorsConfigKey, &config); err != nil {
+		return "", errors.Wrap(err, "failed to unmarshal template processors configurations")
+	}
+
+	keys := []string{}
+	if providerLabel != "" {
+		if flavor != "" {
+			keys = append(keys, fmt.Sprintf("%s/%s", providerLabel, flavor))
+		}
+		keys = append(keys, providerLabel)
+	}
+	keys = append(keys, allTemplateProcessorsConfig)
+
+	// NOTE: keys are matched case insensitive, because the config reader lowercases map keys.
+	for _, key := range keys {
+		if name, ok := config[strings.ToLower(key)]; ok {
+			return name, nil
+		}
+	}
+	return "", nil
+}
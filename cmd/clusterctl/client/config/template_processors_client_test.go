@@ -0,0 +1,96 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_templateProcessorsClient_Get(t *testing.T) {
+	config := `all: simple
+infrastructure-aws: gotemplate
+infrastructure-aws/nodepools: cue
+`
+	tests := []struct {
+		name          string
+		config        string
+		providerLabel string
+		flavor        string
+		want          string
+		wantErr       bool
+	}{
+		{
+			name:          "no template processors config",
+			providerLabel: "infrastructure-aws",
+			want:          "",
+		},
+		{
+			name:          "the processor for the provider and flavor takes precedence",
+			config:        config,
+			providerLabel: "infrastructure-aws",
+			flavor:        "nodepools",
+			want:          "cue",
+		},
+		{
+			name:          "the processor for the provider is used for other flavors",
+			config:        config,
+			providerLabel: "infrastructure-aws",
+			flavor:        "machinepool",
+			want:          "gotemplate",
+		},
+		{
+			name:          "the processor for all the providers is used for other providers",
+			config:        config,
+			providerLabel: "infrastructure-docker",
+			flavor:        "nodepools",
+			want:          "simple",
+		},
+		{
+			name:   "the processor for all the providers is used for templates not coming from a provider",
+			config: config,
+			want:   "simple",
+		},
+		{
+			name:          "fails for invalid config",
+			config:        "- simple\n",
+			providerLabel: "infrastructure-aws",
+			wantErr:       true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := test.NewFakeReader()
+			if tt.config != "" {
+				reader.WithVar(TemplateProcessorsConfigKey, tt.config)
+			}
+
+			got, err := newTemplateProcessorsClient(reader).Get(tt.providerLabel, tt.flavor)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
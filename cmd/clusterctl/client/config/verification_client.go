@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"github.com/pkg/errors"
+)
+
+const (
+	// VerificationConfigKey defines the name of the top level config key for the verification of the files
+	// fetched from provider repositories.
+	VerificationConfigKey = "verification"
+
+	// DefaultChecksumsFile defines the default name of the checksums file published alongside release assets.
+	DefaultChecksumsFile = "checksums.txt"
+
+	// SignatureSuffix defines the suffix appended to the name of the checksums file for getting the name of
+	// the file containing its cosign signature.
+	SignatureSuffix = ".sig"
+
+	allVerificationConfig = "all"
+)
+
+// VerificationClient has methods to work with the configuration for verifying files fetched from provider repositories.
+type VerificationClient interface {
+	// Get returns the verification configuration that applies to the provider with the given label, e.g. infrastructure-aws;
+	// the configuration for all the providers is merged with the configuration for the provider.
+	Get(providerLabel string) (*Verification, error)
+}
+
+// Verification defines how the files fetched from a provider repository are verified.
+type Verification struct {
+	// ChecksumsFile is the name of the file published alongside release assets with the sha256 checksums
+	// of the other files, in the format used by sha256sum.
+	ChecksumsFile string
+
+	// PublicKeys are the cosign public keys, either PEM encoded or paths to PEM files, allowed to sign the checksums file;
+	// if set, a valid signature for the checksums file is required.
+	PublicKeys []string
+
+	// Required requires the checksums file to be published alongside the release assets; it defaults to true.
+	// NOTE: If the checksums file is published, files not listed in it or not matching their checksum are always
+	// refused; only Skip disables the verification.
+	Required bool
+
+	// Skip disables the verification.
+	Skip bool
+}
+
+// verificationClient implements VerificationClient.
+type verificationClient struct {
+	reader Reader
+}
+
+// ensure verificationClient implements VerificationClient.
+var _ VerificationClient = &verificationClient{}
+
+func newVerificationClient(reader Reader) *verificationClient {
+	return &verificationClient{
+		reader: reader,
+	}
+}
+
+func (p *verificationClient) Get(providerLabel string) (*Verification, error) {
+	var config map[string]verificationConfig
+	if err := p.reader.UnmarshalKey(VerificationConfigKey, &config); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal verification configurations")
+	}
+
+	// Gets the verification configuration for all the providers and for the selected provider,
+	// and returns the union of both.
+	c := &verificationConfig{}
+	if allConfig, ok := config[allVerificationConfig]; ok {
+		c.Union(&allConfig)
+	}
+	if providerConfig, ok := config[providerLabel]; ok {
+		c.Union(&providerConfig)
+	}
+
+	v := &Verification{
+		ChecksumsFile: c.ChecksumsFile,
+		PublicKeys:    c.PublicKeys,
+		Required:      true,
+	}
+	if v.ChecksumsFile == "" {
+		v.ChecksumsFile = DefaultChecksumsFile
+	}
+	if c.Required != nil {
+		v.Required = *c.Required
+	}
+	if c.Skip != nil {
+		v.Skip = *c.Skip
+	}
+	return v, nil
+}
+
+// verificationConfig is the verification configuration in the clusterctl config file.
+type verificationConfig struct {
+	ChecksumsFile string   `json:"checksumsFile,omitempty"`
+	PublicKeys    []string `json:"publicKeys,omitempty"`
+	Required      *bool    `json:"required,omitempty"`
+	Skip          *bool    `json:"skip,omitempty"`
+}
+
+// Union allows to merge two verification configurations; in case both define new values for the same field,
+// the other configuration takes precedence on the existing one.
+func (c *verificationConfig) Union(other *verificationConfig) {
+	if other.ChecksumsFile != "" {
+		c.ChecksumsFile = other.ChecksumsFile
+	}
+	if len(other.PublicKeys) > 0 {
+		c.PublicKeys = other.PublicKeys
+	}
+	if other.Required != nil {
+		c.Required = other.Required
+	}
+	if other.Skip != nil {
+		c.Skip = other.Skip
+	}
+}
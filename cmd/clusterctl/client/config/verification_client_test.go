@@ -0,0 +1,108 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_verificationClient_Get(t *testing.T) {
+	tests := []struct {
+		name          string
+		config        string
+		providerLabel string
+		want          *Verification
+	}{
+		{
+			name:          "no verification config",
+			providerLabel: "infrastructure-aws",
+			want: &Verification{
+				ChecksumsFile: DefaultChecksumsFile,
+				Required:      true,
+			},
+		},
+		{
+			name: "verification config for all the providers",
+			config: `all:
+  checksumsFile: SHA256SUMS
+`,
+			providerLabel: "infrastructure-aws",
+			want: &Verification{
+				ChecksumsFile: "SHA256SUMS",
+				Required:      true,
+			},
+		},
+		{
+			name: "verification config for the provider takes precedence on the config for all the providers",
+			config: `all:
+  checksumsFile: SHA256SUMS
+  required: true
+infrastructure-aws:
+  publicKeys:
+  - /home/user/cosign.pub
+  required: false
+`,
+			providerLabel: "infrastructure-aws",
+			want: &Verification{
+				ChecksumsFile: "SHA256SUMS",
+				PublicKeys:    []string{"/home/user/cosign.pub"},
+				Required:      false,
+			},
+		},
+		{
+			name: "verification config for other providers is ignored",
+			config: `infrastructure-aws:
+  skip: true
+`,
+			providerLabel: "infrastructure-docker",
+			want: &Verification{
+				ChecksumsFile: DefaultChecksumsFile,
+				Required:      true,
+			},
+		},
+		{
+			name: "verification skipped for the provider",
+			config: `infrastructure-aws:
+  skip: true
+`,
+			providerLabel: "infrastructure-aws",
+			want: &Verification{
+				ChecksumsFile: DefaultChecksumsFile,
+				Required:      true,
+				Skip:          true,
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := test.NewFakeReader()
+			if tt.config != "" {
+				reader.WithVar(VerificationConfigKey, tt.config)
+			}
+
+			got, err := newVerificationClient(reader).Get(tt.providerLabel)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
@@ -1094,6 +1094,43 @@ v3: default3`,
 	}
 }
 
+func Test_clusterctlClient_ProcessYAML_withTemplateProcessor(t *testing.T) {
+	g := NewWithT(t)
+	template := `{{- range fromYaml .NODE_POOLS }}
+{{ .name }}: {{ .replicas | default $.DEFAULT_REPLICAS }}
+{{- end }}`
+
+	config1 := newFakeConfig(ctx).
+		WithVar(config.TemplateProcessorsConfigKey, "all: gotemplate\n").
+		WithVar("NODE_POOLS", "[{name: md-0}, {name: md-1, replicas: 3}]").
+		WithVar("DEFAULT_REPLICAS", "1").
+		WithProvider(infraProviderConfig)
+	cluster1 := newFakeCluster(cluster.Kubeconfig{}, config1)
+
+	client := newFakeClient(ctx, config1).WithCluster(cluster1)
+
+	printer, err := client.ProcessYAML(ctx, ProcessYAMLOptions{
+		ReaderSource: &ReaderSourceOptions{
+			Reader: strings.NewReader(template),
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(printer.Variables()).To(ConsistOf("NODE_POOLS", "DEFAULT_REPLICAS"))
+
+	yaml, err := printer.Yaml()
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(yaml)).To(Equal("md-0: 1\nmd-1: 3"))
+
+	// Fails for unknown template processors.
+	config1.WithVar(config.TemplateProcessorsConfigKey, "all: foo\n")
+	_, err = client.ProcessYAML(ctx, ProcessYAMLOptions{
+		ReaderSource: &ReaderSourceOptions{
+			Reader: strings.NewReader(template),
+		},
+	})
+	g.Expect(err).To(HaveOccurred())
+}
+
 // errReader returns a non-EOF error on the first read.
 type errReader struct{}
 
@@ -18,7 +18,14 @@ package client
 
 import (
 	"context"
+	"os"
+	"sort"
 
+	"github.com/pkg/errors"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
 )
 
@@ -32,8 +39,12 @@ type DescribeClusterOptions struct {
 	Namespace string
 
 	// ClusterName to be used for the workload cluster.
+	// It is ignored by DescribeClusters.
 	ClusterName string
 
+	// AllNamespaces instructs DescribeClusters to describe the clusters in all the namespaces.
+	AllNamespaces bool
+
 	// ShowOtherConditions is a list of comma separated kind or kind/name for which we should add the ShowObjectConditionsAnnotation
 	// to signal to the presentation layer to show all the conditions for the objects.
 	ShowOtherConditions string
@@ -59,8 +70,121 @@ type DescribeClusterOptions struct {
 	Grouping bool
 }
 
+// SupportBundleOptions carries the options supported by CreateSupportBundle.
+type SupportBundleOptions struct {
+	// Kubeconfig defines the kubeconfig to use for accessing the management cluster. If empty,
+	// default rules for kubeconfig discovery will be used.
+	Kubeconfig Kubeconfig
+
+	// Namespace where the workload cluster is located. If unspecified, the current namespace will be used.
+	Namespace string
+
+	// ClusterName is the name of the workload cluster to collect diagnostics for.
+	ClusterName string
+
+	// Output is the path of the support bundle file to be created.
+	Output string
+
+	// LogTailLines is the number of log lines to collect for each provider controller container.
+	// If not set, the last 1000 lines are collected.
+	LogTailLines int64
+}
+
 // DescribeCluster returns the object tree representing the status of a Cluster API cluster.
 func (c *clusterctlClient) DescribeCluster(ctx context.Context, options DescribeClusterOptions) (*tree.ObjectTree, error) {
+	client, err := c.describeClient(ctx, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	// Gets the object tree representing the status of a Cluster API cluster.
+	return tree.Discovery(ctx, client, options.Namespace, options.ClusterName, options.toDiscoverOptions())
+}
+
+// DescribeClusters returns the object trees representing the status of all the Cluster API clusters in a namespace,
+// or in all the namespaces, sorted by namespace and name.
+func (c *clusterctlClient) DescribeClusters(ctx context.Context, options DescribeClusterOptions) ([]*tree.ObjectTree, error) {
+	proxyClient, err := c.describeClient(ctx, &options)
+	if err != nil {
+		return nil, err
+	}
+
+	listOptions := []client.ListOption{}
+	if !options.AllNamespaces {
+		listOptions = append(listOptions, client.InNamespace(options.Namespace))
+	}
+	clusters := &clusterv1.ClusterList{}
+	if err := proxyClient.List(ctx, clusters, listOptions...); err != nil {
+		return nil, errors.Wrap(err, "failed to list Clusters")
+	}
+	sort.Slice(clusters.Items, func(i, j int) bool {
+		if clusters.Items[i].Namespace == clusters.Items[j].Namespace {
+			return clusters.Items[i].Name < clusters.Items[j].Name
+		}
+		return clusters.Items[i].Namespace < clusters.Items[j].Namespace
+	})
+
+	trees := make([]*tree.ObjectTree, 0, len(clusters.Items))
+	for _, cluster := range clusters.Items {
+		objectTree, err := tree.Discovery(ctx, proxyClient, cluster.Namespace, cluster.Name, options.toDiscoverOptions())
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to describe Cluster %s/%s", cluster.Namespace, cluster.Name)
+		}
+		trees = append(trees, objectTree)
+	}
+	return trees, nil
+}
+
+// CreateSupportBundle collects diagnostic information about a workload cluster into a gzipped tarball.
+func (c *clusterctlClient) CreateSupportBundle(ctx context.Context, options SupportBundleOptions) error {
+	if options.ClusterName == "" {
+		return errors.New("cluster name must be set")
+	}
+	if options.Output == "" {
+		return errors.New("support bundle output path must be set")
+	}
+
+	// gets access to the management cluster
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return err
+	}
+
+	// If the option specifying the Namespace is empty, try to detect it.
+	if options.Namespace == "" {
+		currentNamespace, err := clusterClient.Proxy().CurrentNamespace()
+		if err != nil {
+			return err
+		}
+		options.Namespace = currentNamespace
+	}
+
+	f, err := os.Create(options.Output) //nolint:gosec
+	if err != nil {
+		return errors.Wrapf(err, "failed to create support bundle %s", options.Output)
+	}
+	defer f.Close()
+
+	if err := clusterClient.SupportBundle().Collect(ctx, cluster.SupportBundleOptions{
+		ClusterName:  options.ClusterName,
+		Namespace:    options.Namespace,
+		LogTailLines: options.LogTailLines,
+	}, f); err != nil {
+		// Do not leave an incomplete support bundle behind.
+		_ = f.Close()
+		_ = os.Remove(options.Output)
+		return err
+	}
+	return f.Close()
+}
+
+// describeClient returns a client for the management cluster, defaulting the namespace in the options if not set.
+func (c *clusterctlClient) describeClient(ctx context.Context, options *DescribeClusterOptions) (client.Client, error) {
 	// gets access to the management cluster
 	cluster, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -73,7 +197,7 @@ func (c *clusterctlClient) DescribeCluster(ctx context.Context, options Describe
 	}
 
 	// If the option specifying the Namespace is empty, try to detect it.
-	if options.Namespace == "" {
+	if options.Namespace == "" && !options.AllNamespaces {
 		currentNamespace, err := cluster.Proxy().CurrentNamespace()
 		if err != nil {
 			return nil, err
@@ -82,19 +206,17 @@ func (c *clusterctlClient) DescribeCluster(ctx context.Context, options Describe
 	}
 
 	// Fetch the Cluster client.
-	client, err := cluster.Proxy().NewClient(ctx)
-	if err != nil {
-		return nil, err
-	}
+	return cluster.Proxy().NewClient(ctx)
+}
 
-	// Gets the object tree representing the status of a Cluster API cluster.
-	return tree.Discovery(ctx, client, options.Namespace, options.ClusterName, tree.DiscoverOptions{
-		ShowOtherConditions:     options.ShowOtherConditions,
-		ShowMachineSets:         options.ShowMachineSets,
-		ShowClusterResourceSets: options.ShowClusterResourceSets,
-		ShowTemplates:           options.ShowTemplates,
-		AddTemplateVirtualNode:  options.AddTemplateVirtualNode,
-		Echo:                    options.Echo,
-		Grouping:                options.Grouping,
-	})
+func (o DescribeClusterOptions) toDiscoverOptions() tree.DiscoverOptions {
+	return tree.DiscoverOptions{
+		ShowOtherConditions:     o.ShowOtherConditions,
+		ShowMachineSets:         o.ShowMachineSets,
+		ShowClusterResourceSets: o.ShowClusterResourceSets,
+		ShowTemplates:           o.ShowTemplates,
+		AddTemplateVirtualNode:  o.AddTemplateVirtualNode,
+		Echo:                    o.Echo,
+		Grouping:                o.Grouping,
+	}
 }
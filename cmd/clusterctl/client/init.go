@@ -84,6 +84,18 @@ type InitOptions struct {
 	// NOTE this should only be used for development
 	IgnoreValidationErrors bool
 
+	// Bundle is the path of a bundle created with CreateBundle; if set, the providers and the cert-manager included
+	// in the bundle are read from it instead of from their repositories.
+	Bundle string
+
+	// BundleRegistry is the container registry where the images in the bundle should be pushed, e.g. localhost:5000;
+	// if set, the images of all the components are rewritten to point to this registry.
+	BundleRegistry string
+
+	// SkipVerification disables the verification of the files fetched from provider repositories using
+	// the published checksums and signatures.
+	SkipVerification bool
+
 	// allowMissingProviderCRD is used to allow for a missing provider CRD when listing images.
 	// It is set to false to enforce that provider CRD is available when performing the standard init operation.
 	allowMissingProviderCRD bool
@@ -99,6 +111,17 @@ func (c *clusterctlClient) Init(ctx context.Context, options InitOptions) ([]Com
 		options.WaitProviderTimeout = time.Duration(5*60) * time.Second
 	}
 
+	if options.SkipVerification {
+		c.skipVerification()
+	}
+
+	// If requested, read the providers and the cert-manager from a bundle.
+	if options.Bundle != "" {
+		if err := c.useBundle(ctx, options.Bundle, options.BundleRegistry, true); err != nil {
+			return nil, err
+		}
+	}
+
 	// gets access to the management cluster
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -176,6 +199,17 @@ func (c *clusterctlClient) Init(ctx context.Context, options InitOptions) ([]Com
 
 // InitImages returns the list of images required for init.
 func (c *clusterctlClient) InitImages(ctx context.Context, options InitOptions) ([]string, error) {
+	if options.SkipVerification {
+		c.skipVerification()
+	}
+
+	// If requested, read the providers and the cert-manager from a bundle; images are not pushed when listing them.
+	if options.Bundle != "" {
+		if err := c.useBundle(ctx, options.Bundle, options.BundleRegistry, false); err != nil {
+			return nil, err
+		}
+	}
+
 	// gets access to the management cluster
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -21,6 +21,7 @@ import (
 	"os"
 
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/labels"
 
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
 )
@@ -39,6 +40,17 @@ type MoveOptions struct {
 	// namespace will be used.
 	Namespace string
 
+	// ClusterNames restricts the move to the Clusters with the given names in Namespace, together with the objects they depend on.
+	// If both ClusterNames and ClusterSelector are empty, all the Clusters in the namespace will be moved.
+	ClusterNames []string
+
+	// ClusterSelector restricts the move to the Clusters matching the given label selector, together with the objects they depend on.
+	ClusterSelector string
+
+	// CopySharedObjects allows to move a subset of the Clusters even if they depend on objects which are in use also by
+	// Clusters not being moved, e.g. a ClusterClass; such objects are copied to the target management cluster instead of being moved.
+	CopySharedObjects bool
+
 	// ExperimentalResourceMutatorFn accepts any number of resource mutator functions that are applied on all resources being moved.
 	// This is an experimental feature and is exposed only from the library and not (yet) through the CLI.
 	ExperimentalResourceMutators []cluster.ResourceMutatorFunc
@@ -51,6 +63,16 @@ type MoveOptions struct {
 
 	// DryRun means the move action is a dry run, no real action will be performed.
 	DryRun bool
+
+	// Resume resumes a previous move operation which did not complete, e.g. because of an error, continuing
+	// from the last completed step recorded in the move journal stored in the target management cluster.
+	// Namespace, ClusterNames and ClusterSelector must be the same used for the move operation.
+	Resume bool
+
+	// Abort aborts a previous move operation which did not complete, e.g. because of an error, deleting the
+	// objects already created in the target management cluster and resuming the Clusters in the source management cluster.
+	// Namespace, ClusterNames and ClusterSelector must be the same used for the move operation.
+	Abort bool
 }
 
 func (c *clusterctlClient) Move(ctx context.Context, options MoveOptions) error {
@@ -66,6 +88,15 @@ func (c *clusterctlClient) Move(ctx context.Context, options MoveOptions) error
 		return errors.Errorf("at least one of FromDirectory, ToDirectory and ToKubeconfig must be set")
 	}
 
+	if options.Resume || options.Abort {
+		if options.Resume && options.Abort {
+			return errors.Errorf("can't set both Resume and Abort")
+		}
+		if options.DryRun || options.FromDirectory != "" || options.ToDirectory != "" {
+			return errors.Errorf("Resume and Abort can't be used together with DryRun, FromDirectory or ToDirectory")
+		}
+	}
+
 	if options.ToDirectory != "" {
 		return c.toDirectory(ctx, options)
 	} else if options.FromDirectory != "" {
@@ -99,7 +130,19 @@ func (c *clusterctlClient) move(ctx context.Context, options MoveOptions) error
 		}
 	}
 
-	return fromCluster.ObjectMover().Move(ctx, options.Namespace, toCluster, options.DryRun, options.ExperimentalResourceMutators...)
+	filter, err := options.moveFilter()
+	if err != nil {
+		return err
+	}
+
+	if options.Resume {
+		return fromCluster.ObjectMover().ResumeMove(ctx, options.Namespace, filter, toCluster, options.ExperimentalResourceMutators...)
+	}
+	if options.Abort {
+		return fromCluster.ObjectMover().AbortMove(ctx, options.Namespace, filter, toCluster, options.ExperimentalResourceMutators...)
+	}
+
+	return fromCluster.ObjectMover().Move(ctx, options.Namespace, filter, toCluster, options.DryRun, options.ExperimentalResourceMutators...)
 }
 
 func (c *clusterctlClient) fromDirectory(ctx context.Context, options MoveOptions) error {
@@ -134,7 +177,28 @@ func (c *clusterctlClient) toDirectory(ctx context.Context, options MoveOptions)
 		return err
 	}
 
-	return fromCluster.ObjectMover().ToDirectory(ctx, options.Namespace, options.ToDirectory)
+	filter, err := options.moveFilter()
+	if err != nil {
+		return err
+	}
+
+	return fromCluster.ObjectMover().ToDirectory(ctx, options.Namespace, filter, options.ToDirectory)
+}
+
+// moveFilter returns the filter restricting the move to the selected Clusters, if any.
+func (o MoveOptions) moveFilter() (cluster.MoveFilter, error) {
+	filter := cluster.MoveFilter{
+		ClusterNames:      o.ClusterNames,
+		CopySharedObjects: o.CopySharedObjects,
+	}
+	if o.ClusterSelector != "" {
+		selector, err := labels.Parse(o.ClusterSelector)
+		if err != nil {
+			return cluster.MoveFilter{}, errors.Wrapf(err, "invalid cluster selector %q", o.ClusterSelector)
+		}
+		filter.ClusterSelector = selector
+	}
+	return filter, nil
 }
 
 func (c *clusterctlClient) getClusterClient(ctx context.Context, kubeconfig Kubeconfig) (cluster.Client, error) {
@@ -81,6 +81,92 @@ func Test_clusterctlClient_Move(t *testing.T) {
 			},
 			wantErr: true,
 		},
+		{
+			name: "does not return error when moving a subset of the clusters",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:    Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					ClusterNames:    []string{"foo"},
+					ClusterSelector: "env=dev",
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if the cluster selector is invalid",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig:  Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:    Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					ClusterSelector: "env in (dev",
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "does not return error when resuming a move",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Resume:         true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "does not return error when aborting a move",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Abort:          true,
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "returns an error if both Resume and Abort are set",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					ToKubeconfig:   Kubeconfig{Path: "kubeconfig", Context: "worker-context"},
+					Resume:         true,
+					Abort:          true,
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "returns an error if Resume is set with DryRun",
+			fields: fields{
+				client: fakeClientForMove(),
+			},
+			args: args{
+				options: MoveOptions{
+					FromKubeconfig: Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					DryRun:         true,
+					Resume:         true,
+				},
+			},
+			wantErr: true,
+		},
 		{
 			name: "returns an error if both move ToDirectory and FromDirectory is set",
 			fields: fields{
@@ -303,15 +389,25 @@ func fakeClientForMove() *fakeClient {
 
 type fakeObjectMover struct {
 	moveErr          error
+	resumeErr        error
+	abortErr         error
 	toDirectoryErr   error
 	fromDirectoryErr error
 }
 
-func (f *fakeObjectMover) Move(_ context.Context, _ string, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
+func (f *fakeObjectMover) Move(_ context.Context, _ string, _ cluster.MoveFilter, _ cluster.Client, _ bool, _ ...cluster.ResourceMutatorFunc) error {
 	return f.moveErr
 }
 
-func (f *fakeObjectMover) ToDirectory(_ context.Context, _ string, _ string) error {
+func (f *fakeObjectMover) ResumeMove(_ context.Context, _ string, _ cluster.MoveFilter, _ cluster.Client, _ ...cluster.ResourceMutatorFunc) error {
+	return f.resumeErr
+}
+
+func (f *fakeObjectMover) AbortMove(_ context.Context, _ string, _ cluster.MoveFilter, _ cluster.Client, _ ...cluster.ResourceMutatorFunc) error {
+	return f.abortErr
+}
+
+func (f *fakeObjectMover) ToDirectory(_ context.Context, _ string, _ cluster.MoveFilter, _ string) error {
 	return f.toDirectoryErr
 }
 
@@ -55,6 +55,10 @@ type Client interface {
 
 	// Metadata provide access to YAML with the provider's metadata.
 	Metadata(version string) MetadataClient
+
+	// GetFile returns a file for a given provider version, as it is stored in the provider repository
+	// (without applying local overrides or variable substitution).
+	GetFile(ctx context.Context, version, path string) ([]byte, error)
 }
 
 // repositoryClient implements Client.
@@ -92,6 +96,10 @@ func (c *repositoryClient) Metadata(version string) MetadataClient {
 	return newMetadataClient(c.Provider, version, c.repository, c.configClient.Variables())
 }
 
+func (c *repositoryClient) GetFile(ctx context.Context, version, path string) ([]byte, error) {
+	return c.repository.GetFile(ctx, version, path)
+}
+
 // Option is a configuration option supplied to New.
 type Option func(*repositoryClient)
 
@@ -136,6 +144,18 @@ func newRepositoryClient(ctx context.Context, provider config.Provider, configCl
 		if err != nil {
 			return nil, errors.Wrapf(err, "failed to get repository client for the %s with name %s", provider.Type(), provider.Name())
 		}
+
+		// Files fetched from remote repositories are verified, unless the verification is skipped.
+		switch r.(type) {
+		case *gitHubRepository, *gitLabRepository:
+			verification, err := configClient.Verification().Get(provider.ManifestLabel())
+			if err != nil {
+				return nil, err
+			}
+			if !verification.Skip {
+				r = newVerifyingRepository(r, provider.ManifestLabel(), verification)
+			}
+		}
 		client.repository = r
 	}
 
@@ -197,6 +217,15 @@ func repositoryFactory(ctx context.Context, providerConfig config.Provider, conf
 		return nil, errors.Errorf("invalid provider url. Only GitHub and GitLab are supported for %q schema", rURL.Scheme)
 	}
 
+	// if the url is a bundle created with clusterctl bundle create
+	if rURL.Scheme == bundleScheme {
+		repo, err := newBundleRepository(ctx, providerConfig)
+		if err != nil {
+			return nil, errors.Wrap(err, "error creating the bundle repository client")
+		}
+		return repo, err
+	}
+
 	// if the url is a local filesystem repository
 	if rURL.Scheme == "file" || rURL.Scheme == "" {
 		repo, err := newLocalRepository(ctx, providerConfig, configVariablesClient)
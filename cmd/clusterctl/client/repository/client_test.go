@@ -89,11 +89,45 @@ func Test_newRepositoryClient_LocalFileSystemRepository(t *testing.T) {
 			repoClient, err := newRepositoryClient(ctx, tt.fields.provider, configClient)
 			gs.Expect(err).ToNot(HaveOccurred())
 
-			gs.Expect(repoClient.repository).To(BeAssignableToTypeOf(tt.expected))
+			repository := repoClient.repository
+			// Remote repositories are wrapped for verifying the files fetched from them.
+			if r, ok := repository.(*verifyingRepository); ok {
+				repository = r.Repository
+			}
+			gs.Expect(repository).To(BeAssignableToTypeOf(tt.expected))
 		})
 	}
 }
 
+func Test_newRepositoryClient_Verification(t *testing.T) {
+	ctx := context.Background()
+
+	provider := config.NewProvider("bar", "https://github.com/o/r/releases/v0.4.1/file.yaml", clusterctlv1.BootstrapProviderType)
+
+	t.Run("files fetched from remote repositories are verified", func(t *testing.T) {
+		g := NewWithT(t)
+
+		configClient, err := config.New(ctx, "", config.InjectReader(test.NewFakeReader()))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		repoClient, err := newRepositoryClient(ctx, provider, configClient)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(repoClient.repository).To(BeAssignableToTypeOf(&verifyingRepository{}))
+	})
+
+	t.Run("verification can be skipped", func(t *testing.T) {
+		g := NewWithT(t)
+
+		reader := test.NewFakeReader().WithVar(config.VerificationConfigKey, "bootstrap-bar:\n  skip: true\n")
+		configClient, err := config.New(ctx, "", config.InjectReader(reader))
+		g.Expect(err).ToNot(HaveOccurred())
+
+		repoClient, err := newRepositoryClient(ctx, provider, configClient)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(repoClient.repository).To(BeAssignableToTypeOf(&gitHubRepository{}))
+	})
+}
+
 func Test_newRepositoryClient_YamlProcessor(t *testing.T) {
 	tests := []struct {
 		name   string
@@ -57,6 +57,7 @@ const (
 // 2. Ensure all the provider components are deployed in the target namespace (apply only to namespaced objects)
 // 3. Ensure all the ClusterRoleBinding which are referencing namespaced objects have the name prefixed with the namespace name
 // 4. Adds labels to all the components in order to allow easy identification of the provider objects.
+// 5. Applies the patches defined in the clusterctl configuration for the provider.
 type Components interface {
 	// Provider holds configuration of the provider the provider components belong to.
 	config.Provider
@@ -95,6 +96,7 @@ type components struct {
 	variables       []string
 	images          []string
 	targetNamespace string
+	patches         []clusterctlv1.ProviderPatch
 	objs            []unstructured.Unstructured
 }
 
@@ -134,6 +136,7 @@ func (c *components) InventoryObject() clusterctlv1.Provider {
 		ProviderName: c.Name(),
 		Type:         string(c.Type()),
 		Version:      c.version,
+		Patches:      c.patches,
 	}
 }
 
@@ -172,6 +175,10 @@ type ComponentsOptions struct {
 	// SkipTemplateProcess allows for skipping the call to the template processor, including also variable replacement in the component YAML.
 	// NOTE this works only if the rawYaml is a valid yaml by itself, like e.g when using envsubst/the simple processor.
 	SkipTemplateProcess bool
+	// InventoryPatches are the patches recorded in the inventory by a previous installation or upgrade of the provider;
+	// they are applied when no patches are defined for the provider in the clusterctl configuration, so upgrades
+	// preserve them.
+	InventoryPatches []clusterctlv1.ProviderPatch
 }
 
 // ComponentsInput represents all the inputs required by NewComponents.
@@ -192,6 +199,8 @@ type ComponentsInput struct {
 // 3. Ensure all the provider components are deployed in the target namespace (apply only to namespaced objects)
 // 4. Ensure all the ClusterRoleBinding which are referencing namespaced objects have the name prefixed with the namespace name
 // 5. Adds labels to all the components in order to allow easy identification of the provider objects.
+// 6. Applies the patches defined in the clusterctl configuration for the provider, or the patches recorded in the inventory
+// if none is defined.
 func NewComponents(input ComponentsInput) (Components, error) {
 	variables, err := input.Processor.GetVariables(input.RawYaml)
 	if err != nil {
@@ -214,20 +223,6 @@ func NewComponents(input ComponentsInput) (Components, error) {
 		return nil, errors.Wrap(err, "failed to parse yaml")
 	}
 
-	// Apply image overrides, if defined
-	objs, err = util.FixImages(objs, func(image string) (string, error) {
-		return input.ConfigClient.ImageMeta().AlterImage(input.Provider.ManifestLabel(), image)
-	})
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to apply image overrides")
-	}
-
-	// Inspect the list of objects for the images required by the provider component.
-	images, err := util.InspectImages(objs)
-	if err != nil {
-		return nil, errors.Wrap(err, "failed to detect required images")
-	}
-
 	// inspect the list of objects for the default target namespace
 	// the default target namespace is the namespace object defined in the component yaml read from the repository, if any
 	defaultTargetNamespace, err := inspectTargetNamespace(objs)
@@ -259,12 +254,41 @@ func NewComponents(input ComponentsInput) (Components, error) {
 	// Add common labels.
 	objs = addCommonLabels(objs, input.Provider)
 
+	// Apply the patches defined in the clusterctl configuration; if none is defined, re-apply the patches
+	// recorded in the inventory, e.g. when upgrading a provider.
+	patches, err := input.ConfigClient.Patches().Get(input.Provider.ManifestLabel())
+	if err != nil {
+		return nil, err
+	}
+	if patches == nil {
+		patches = input.Options.InventoryPatches
+	}
+	objs, err = applyPatches(objs, patches)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply patches")
+	}
+
+	// Apply image overrides, if defined
+	objs, err = util.FixImages(objs, func(image string) (string, error) {
+		return input.ConfigClient.ImageMeta().AlterImage(input.Provider.ManifestLabel(), image)
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to apply image overrides")
+	}
+
+	// Inspect the list of objects for the images required by the provider component.
+	images, err := util.InspectImages(objs)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to detect required images")
+	}
+
 	return &components{
 		Provider:        input.Provider,
 		version:         input.Options.Version,
 		variables:       variables,
 		images:          images,
 		targetNamespace: input.Options.TargetNamespace,
+		patches:         patches,
 		objs:            objs,
 	}, nil
 }
@@ -0,0 +1,81 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+	"sigs.k8s.io/yaml"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+const (
+	patchesKustomizationRoot = "/"
+	patchesComponentsFile    = "components.yaml"
+)
+
+// patchesKustomization is the Kustomization used for applying patches to the provider components.
+type patchesKustomization struct {
+	APIVersion string                       `json:"apiVersion"`
+	Kind       string                       `json:"kind"`
+	Resources  []string                     `json:"resources"`
+	Patches    []clusterctlv1.ProviderPatch `json:"patches"`
+}
+
+// applyPatches applies patches to the provider components, using Kustomize so patches behave exactly
+// like the patches in a Kustomization.
+func applyPatches(objs []unstructured.Unstructured, patches []clusterctlv1.ProviderPatch) ([]unstructured.Unstructured, error) {
+	if len(patches) == 0 {
+		return objs, nil
+	}
+
+	components, err := utilyaml.FromUnstructured(objs)
+	if err != nil {
+		return nil, err
+	}
+	kustomization, err := yaml.Marshal(patchesKustomization{
+		APIVersion: "kustomize.config.k8s.io/v1beta1",
+		Kind:       "Kustomization",
+		Resources:  []string{patchesComponentsFile},
+		Patches:    patches,
+	})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to generate the Kustomization for applying patches")
+	}
+
+	fSys := filesys.MakeFsInMemory()
+	if err := fSys.WriteFile(patchesKustomizationRoot+patchesComponentsFile, components); err != nil {
+		return nil, err
+	}
+	if err := fSys.WriteFile(patchesKustomizationRoot+"kustomization.yaml", kustomization); err != nil {
+		return nil, err
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, patchesKustomizationRoot)
+	if err != nil {
+		return nil, err
+	}
+	patched, err := resources.AsYaml()
+	if err != nil {
+		return nil, err
+	}
+	return utilyaml.ToUnstructured(patched)
+}
@@ -0,0 +1,240 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	yaml "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	utilyaml "sigs.k8s.io/cluster-api/util/yaml"
+)
+
+func Test_applyPatches(t *testing.T) {
+	objs, err := utilyaml.ToUnstructured(utilyaml.JoinYaml(namespaceYaml, controllerYaml, configMapYaml))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		patches []clusterctlv1.ProviderPatch
+		check   func(g *WithT, objs []unstructured.Unstructured)
+		wantErr bool
+	}{
+		{
+			name:    "no patches",
+			patches: nil,
+			check: func(g *WithT, got []unstructured.Unstructured) {
+				g.Expect(got).To(Equal(objs))
+			},
+		},
+		{
+			name: "strategic merge patch without target",
+			patches: []clusterctlv1.ProviderPatch{
+				{
+					Patch: `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: my-controller
+spec:
+  template:
+    spec:
+      containers:
+      - name: manager
+        args:
+        - --feature-gates=MachinePool=true
+      tolerations:
+      - key: node-role.kubernetes.io/control-plane
+        effect: NoSchedule
+`,
+				},
+			},
+			check: func(g *WithT, got []unstructured.Unstructured) {
+				containers, _, _ := unstructured.NestedSlice(got[1].Object, "spec", "template", "spec", "containers")
+				g.Expect(containers).To(HaveLen(1))
+				container := containers[0].(map[string]interface{})
+				// Strategic merge patches merge containers by name.
+				g.Expect(container["image"]).To(Equal("docker.io/library/image:latest"))
+				g.Expect(container["args"]).To(Equal([]interface{}{"--feature-gates=MachinePool=true"}))
+				tolerations, _, _ := unstructured.NestedSlice(got[1].Object, "spec", "template", "spec", "tolerations")
+				g.Expect(tolerations).To(HaveLen(1))
+			},
+		},
+		{
+			name: "JSON 6902 patch with target",
+			patches: []clusterctlv1.ProviderPatch{
+				{
+					Target: &clusterctlv1.PatchSelector{Group: "apps", Kind: "Deployment"},
+					Patch: `- op: add
+  path: /spec/replicas
+  value: 2
+`,
+				},
+			},
+			check: func(g *WithT, got []unstructured.Unstructured) {
+				replicas, _, _ := unstructured.NestedFieldNoCopy(got[1].Object, "spec", "replicas")
+				g.Expect(replicas).To(BeNumerically("==", 2))
+			},
+		},
+		{
+			name: "patches preserve the order of the components",
+			patches: []clusterctlv1.ProviderPatch{
+				{
+					Target: &clusterctlv1.PatchSelector{Kind: "ConfigMap", Name: "manager"},
+					Patch:  `[{"op": "replace", "path": "/data/variable", "value": "bar"}]`,
+				},
+			},
+			check: func(g *WithT, got []unstructured.Unstructured) {
+				g.Expect(got).To(HaveLen(3))
+				g.Expect(got[0].GetKind()).To(Equal("Namespace"))
+				g.Expect(got[1].GetKind()).To(Equal("Deployment"))
+				g.Expect(got[2].GetKind()).To(Equal("ConfigMap"))
+				value, _, _ := unstructured.NestedString(got[2].Object, "data", "variable")
+				g.Expect(value).To(Equal("bar"))
+			},
+		},
+		{
+			name: "fails for invalid patches",
+			patches: []clusterctlv1.ProviderPatch{
+				{
+					Target: &clusterctlv1.PatchSelector{Kind: "ConfigMap"},
+					Patch:  `[{"op": "replace", "path": "/data/variable"`,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			got, err := applyPatches(objs, tt.patches)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			tt.check(g, got)
+		})
+	}
+}
+
+func TestNewComponents_Patches(t *testing.T) {
+	configPatches := `infrastructure-p1:
+- target:
+    kind: Deployment
+  patch: |
+    - op: add
+      path: /spec/replicas
+      value: 2
+`
+	inventoryPatches := []clusterctlv1.ProviderPatch{
+		{
+			Target: &clusterctlv1.PatchSelector{Kind: "Deployment"},
+			Patch:  "- op: add\n  path: /spec/replicas\n  value: 3\n",
+		},
+	}
+
+	tests := []struct {
+		name             string
+		config           string
+		inventoryPatches []clusterctlv1.ProviderPatch
+		wantReplicas     int64
+		wantPatches      int
+	}{
+		{
+			name:         "no patches",
+			wantReplicas: 0,
+			wantPatches:  0,
+		},
+		{
+			name:         "patches from the clusterctl configuration",
+			config:       configPatches,
+			wantReplicas: 2,
+			wantPatches:  1,
+		},
+		{
+			name:             "patches from the clusterctl configuration take precedence on patches from the inventory",
+			config:           configPatches,
+			inventoryPatches: inventoryPatches,
+			wantReplicas:     2,
+			wantPatches:      1,
+		},
+		{
+			name:             "patches from the inventory are re-applied if there are no patches in the clusterctl configuration",
+			inventoryPatches: inventoryPatches,
+			wantReplicas:     3,
+			wantPatches:      1,
+		},
+		{
+			name:             "patches from the inventory are not applied if the clusterctl configuration defines no patches",
+			config:           "infrastructure-p1: []\n",
+			inventoryPatches: inventoryPatches,
+			wantReplicas:     0,
+			wantPatches:      0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			reader := test.NewFakeReader().WithVar(variableName, variableValue)
+			if tt.config != "" {
+				reader.WithVar(config.PatchesConfigKey, tt.config)
+			}
+			configClient, err := config.New(context.Background(), "", config.InjectReader(reader))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			components, err := NewComponents(ComponentsInput{
+				Provider:     config.NewProvider("p1", "", clusterctlv1.InfrastructureProviderType),
+				ConfigClient: configClient,
+				Processor:    yaml.NewSimpleProcessor(),
+				RawYaml:      utilyaml.JoinYaml(namespaceYaml, controllerYaml, configMapYaml),
+				Options: ComponentsOptions{
+					Version:          "v1.0.0",
+					InventoryPatches: tt.inventoryPatches,
+				},
+			})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			found := false
+			for _, o := range components.Objs() {
+				if o.GetKind() != "Deployment" {
+					continue
+				}
+				found = true
+				// Patches are applied after the common labels are added.
+				g.Expect(o.GetLabels()).To(HaveKeyWithValue(clusterctlv1.ClusterctlLabel, ""))
+				replicas, _, _ := unstructured.NestedFieldNoCopy(o.Object, "spec", "replicas")
+				if tt.wantReplicas == 0 {
+					g.Expect(replicas).To(BeNil())
+					continue
+				}
+				g.Expect(replicas).To(BeNumerically("==", tt.wantReplicas))
+			}
+			g.Expect(found).To(BeTrue())
+			g.Expect(components.InventoryObject().Patches).To(HaveLen(tt.wantPatches))
+		})
+	}
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"net/url"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/bundle"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+const (
+	bundleScheme = "bundle"
+)
+
+// bundleRepository provides support for providers releases stored in a bundle created with
+// clusterctl bundle create. As part of the provider object, the URL is expected to contain the absolute
+// path to the bundle archive on the local filesystem, e.g. bundle:///home/user/clusterctl-bundle.tar.gz.
+//
+// Inside the bundle, the files for each release are stored with the same layout used by the
+// local repositories, {provider-label}/{version}/{file}, under the repository folder.
+type bundleRepository struct {
+	bundle         *bundle.Bundle
+	providerLabel  string
+	defaultVersion string
+	componentsPath string
+	releases       map[string]bundle.Release
+}
+
+var _ Repository = &bundleRepository{}
+
+// DefaultVersion returns the default version for the bundle repository.
+func (r *bundleRepository) DefaultVersion() string {
+	return r.defaultVersion
+}
+
+// RootPath returns the empty string as it is not applicable to bundle repositories.
+func (r *bundleRepository) RootPath() string {
+	return ""
+}
+
+// ComponentsPath returns the path to the components file for the bundle repository.
+func (r *bundleRepository) ComponentsPath() string {
+	return r.componentsPath
+}
+
+// GetFile returns a file for a given provider version.
+func (r *bundleRepository) GetFile(ctx context.Context, version, fileName string) ([]byte, error) {
+	var err error
+
+	if version == latestVersionTag {
+		version, err = latestRelease(ctx, r)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the latest release")
+		}
+	} else if version == "" {
+		version = r.defaultVersion
+	}
+
+	release, ok := r.releases[version]
+	if !ok {
+		return nil, errors.Errorf("release %s for provider %s is not included in bundle %s", version, r.providerLabel, r.bundle.Path())
+	}
+
+	// The components file can be requested using the components path of the default version.
+	if fileName == r.componentsPath {
+		fileName = release.ComponentsFile
+	}
+
+	content, err := r.bundle.ReadFile(bundle.ReleasePath(r.providerLabel, version, fileName))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read file %q from bundle release %s", fileName, version)
+	}
+	return content, nil
+}
+
+// GetVersions returns the list of versions that are available in the bundle for the provider.
+func (r *bundleRepository) GetVersions(_ context.Context) ([]string, error) {
+	versions := make([]string, 0, len(r.releases))
+	for v := range r.releases {
+		versions = append(versions, v)
+	}
+	return versions, nil
+}
+
+// newBundleRepository returns a new bundleRepository.
+func newBundleRepository(ctx context.Context, providerConfig config.Provider) (*bundleRepository, error) {
+	url, err := url.Parse(providerConfig.URL())
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid url")
+	}
+
+	path := url.Path
+	if runtime.GOOS == "windows" {
+		// in case of windows, we should take care of removing the additional / which is required by the URI standard
+		// for windows local paths, like for local repositories.
+		path = filepath.FromSlash(strings.TrimPrefix(path, "/"))
+	}
+	if !filepath.IsAbs(path) {
+		return nil, errors.Errorf("invalid path: path %q must be an absolute path", providerConfig.URL())
+	}
+
+	b, err := bundle.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	repo := &bundleRepository{
+		bundle:        b,
+		providerLabel: providerConfig.ManifestLabel(),
+		releases:      map[string]bundle.Release{},
+	}
+	for _, release := range b.Manifest().ReleasesFor(repo.providerLabel) {
+		repo.releases[release.Version] = release
+	}
+	if len(repo.releases) == 0 {
+		return nil, errors.Errorf("bundle %s does not contain releases for provider %s", path, repo.providerLabel)
+	}
+
+	repo.defaultVersion, err = latestContractRelease(ctx, repo, clusterv1.GroupVersion.Version)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get latest version")
+	}
+	repo.componentsPath = repo.releases[repo.defaultVersion].ComponentsFile
+	return repo, nil
+}
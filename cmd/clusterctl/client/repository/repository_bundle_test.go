@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/bundle"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func Test_bundleRepository(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	bundlePath := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	writeTestBundle(g, bundlePath)
+
+	t.Run("reads the releases of a provider", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := config.NewProvider("foo", "bundle://"+filepath.ToSlash(bundlePath), clusterctlv1.InfrastructureProviderType)
+		repo, err := repositoryFactory(ctx, p, test.NewFakeVariableClient())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(repo.DefaultVersion()).To(Equal("v1.1.0"))
+		g.Expect(repo.RootPath()).To(Equal(""))
+		g.Expect(repo.ComponentsPath()).To(Equal("infrastructure-components.yaml"))
+
+		versions, err := repo.GetVersions(ctx)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(versions).To(ConsistOf("v1.0.0", "v1.1.0"))
+
+		content, err := repo.GetFile(ctx, "", repo.ComponentsPath())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal("components v1.1.0"))
+
+		// The components file is found even if the name is different in other releases.
+		content, err = repo.GetFile(ctx, "v1.0.0", repo.ComponentsPath())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal("components v1.0.0"))
+
+		content, err = repo.GetFile(ctx, latestVersionTag, "cluster-template.yaml")
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal("template v1.1.0"))
+
+		_, err = repo.GetFile(ctx, "v1.0.0", "cluster-template.yaml")
+		g.Expect(err).To(HaveOccurred())
+
+		_, err = repo.GetFile(ctx, "v2.0.0", repo.ComponentsPath())
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("reads the cert-manager release", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := config.NewProvider(bundle.CertManagerName, "bundle://"+filepath.ToSlash(bundlePath), "")
+		repo, err := repositoryFactory(ctx, p, test.NewFakeVariableClient())
+		g.Expect(err).ToNot(HaveOccurred())
+
+		g.Expect(repo.DefaultVersion()).To(Equal("v1.14.2"))
+		content, err := repo.GetFile(ctx, "v1.14.2", repo.ComponentsPath())
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(content)).To(Equal("cert-manager"))
+	})
+
+	t.Run("fails if the bundle does not include the provider", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := config.NewProvider("bar", "bundle://"+filepath.ToSlash(bundlePath), clusterctlv1.InfrastructureProviderType)
+		_, err := repositoryFactory(ctx, p, test.NewFakeVariableClient())
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if the bundle does not exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		p := config.NewProvider("foo", "bundle://"+filepath.ToSlash(filepath.Join(t.TempDir(), "not-existing.tar.gz")), clusterctlv1.InfrastructureProviderType)
+		_, err := repositoryFactory(ctx, p, test.NewFakeVariableClient())
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
+func writeTestBundle(g *WithT, bundlePath string) {
+	f, err := os.Create(bundlePath) //nolint:gosec
+	g.Expect(err).ToNot(HaveOccurred())
+	defer f.Close()
+
+	w, err := bundle.NewWriter(f, &bundle.Manifest{
+		Releases: []bundle.Release{
+			{Name: "foo", Type: string(clusterctlv1.InfrastructureProviderType), Version: "v1.0.0", ComponentsFile: "components.yaml", Files: []string{"components.yaml"}},
+			{Name: "foo", Type: string(clusterctlv1.InfrastructureProviderType), Version: "v1.1.0", ComponentsFile: "infrastructure-components.yaml", Files: []string{"infrastructure-components.yaml", "cluster-template.yaml"}},
+			{Name: bundle.CertManagerName, Version: "v1.14.2", ComponentsFile: "cert-manager.yaml", Files: []string{"cert-manager.yaml"}},
+		},
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(w.AddFile(bundle.ReleasePath("infrastructure-foo", "v1.0.0", "components.yaml"), []byte("components v1.0.0"))).To(Succeed())
+	g.Expect(w.AddFile(bundle.ReleasePath("infrastructure-foo", "v1.1.0", "infrastructure-components.yaml"), []byte("components v1.1.0"))).To(Succeed())
+	g.Expect(w.AddFile(bundle.ReleasePath("infrastructure-foo", "v1.1.0", "cluster-template.yaml"), []byte("template v1.1.0"))).To(Succeed())
+	g.Expect(w.AddFile(bundle.ReleasePath(bundle.CertManagerName, "v1.14.2", "cert-manager.yaml"), []byte("cert-manager"))).To(Succeed())
+	g.Expect(w.Close()).To(Succeed())
+}
@@ -448,7 +448,7 @@ func (g *gitHubRepository) downloadFilesFromRelease(ctx context.Context, release
 		}
 	}
 	if assetID == nil {
-		return nil, errors.Errorf("failed to get file %q from %q release", fileName, *release.TagName)
+		return nil, errors.Wrapf(errNotFound, "failed to get file %q from %q release", fileName, *release.TagName)
 	}
 
 	var reader io.ReadCloser
@@ -161,6 +161,9 @@ func (g *gitLabRepository) GetFile(ctx context.Context, version, path string) ([
 
 	defer response.Body.Close()
 
+	if response.StatusCode == http.StatusNotFound {
+		return nil, errors.Wrapf(errNotFound, "failed to get file %q with version %q from %q", path, version, url)
+	}
 	if response.StatusCode != http.StatusOK {
 		return nil, errors.Errorf("failed to get file %q with version %q from %q, got %d", path, version, url, response.StatusCode)
 	}
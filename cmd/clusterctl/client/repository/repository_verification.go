@@ -0,0 +1,236 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"os"
+	"path"
+	"strings"
+
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+// verifyingRepository wraps a repository, verifying the files fetched from it using the checksums file
+// published alongside the release assets and, if public keys are configured, the cosign signature of the checksums file.
+type verifyingRepository struct {
+	Repository
+	providerLabel string
+	verification  *config.Verification
+
+	// checksums caches the checksums for each version; a nil value is used for versions without a checksums file.
+	checksums map[string]map[string]string
+}
+
+var _ Repository = &verifyingRepository{}
+
+// newVerifyingRepository returns a new verifyingRepository.
+func newVerifyingRepository(repository Repository, providerLabel string, verification *config.Verification) *verifyingRepository {
+	return &verifyingRepository{
+		Repository:    repository,
+		providerLabel: providerLabel,
+		verification:  verification,
+		checksums:     map[string]map[string]string{},
+	}
+}
+
+// GetFile returns a file for a given provider version, after verifying it.
+func (r *verifyingRepository) GetFile(ctx context.Context, version, fileName string) ([]byte, error) {
+	content, err := r.Repository.GetFile(ctx, version, fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.verify(ctx, version, fileName, content); err != nil {
+		return nil, errors.Wrapf(err, "failed to verify %q for provider %s version %s; use --skip-verification or set skip: true in the %s configuration for the provider to skip verification", fileName, r.providerLabel, version, config.VerificationConfigKey)
+	}
+	return content, nil
+}
+
+// required returns true if the checksums file must be published alongside the release assets.
+func (r *verifyingRepository) required() bool {
+	return r.verification.Required || len(r.verification.PublicKeys) > 0
+}
+
+func (r *verifyingRepository) verify(ctx context.Context, version, fileName string, content []byte) error {
+	log := logf.Log
+
+	checksums, err := r.getChecksums(ctx, version)
+	if err != nil {
+		return err
+	}
+	if checksums == nil {
+		log.V(1).Info("Checksums are not published for the release, skipping verification", "File", fileName, "Provider", r.providerLabel, "Version", version)
+		return nil
+	}
+
+	checksum, ok := checksums[path.Base(fileName)]
+	if !ok {
+		return errors.Errorf("the file is not listed in %q", r.verification.ChecksumsFile)
+	}
+
+	sum := sha256.Sum256(content)
+	if hex.EncodeToString(sum[:]) != checksum {
+		return errors.Errorf("sha256 checksum %s does not match the checksum %s in %q", hex.EncodeToString(sum[:]), checksum, r.verification.ChecksumsFile)
+	}
+	log.V(5).Info("Verified", "File", fileName, "Provider", r.providerLabel, "Version", version)
+	return nil
+}
+
+// getChecksums returns the checksums for a version, after verifying the signature of the checksums file if required.
+func (r *verifyingRepository) getChecksums(ctx context.Context, version string) (map[string]string, error) {
+	log := logf.Log
+
+	if checksums, ok := r.checksums[version]; ok {
+		return checksums, nil
+	}
+
+	data, err := r.Repository.GetFile(ctx, version, r.verification.ChecksumsFile)
+	if err != nil {
+		// Releases without a checksums file can be used only if the verification is not required;
+		// any other error, e.g. a network error, must not lead to using files without verifying them.
+		if !isNotFound(err) || r.required() {
+			return nil, errors.Wrapf(err, "failed to get %q", r.verification.ChecksumsFile)
+		}
+		log.Info("Checksums are not published for the release and verification is not required, files are not verified", "ChecksumsFile", r.verification.ChecksumsFile, "Provider", r.providerLabel, "Version", version)
+		r.checksums[version] = nil
+		return nil, nil
+	}
+
+	if len(r.verification.PublicKeys) > 0 {
+		signatureFile := r.verification.ChecksumsFile + config.SignatureSuffix
+		signature, err := r.Repository.GetFile(ctx, version, signatureFile)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get %q", signatureFile)
+		}
+		if err := verifySignature(data, signature, r.verification.PublicKeys); err != nil {
+			return nil, errors.Wrapf(err, "invalid signature for %q", r.verification.ChecksumsFile)
+		}
+	}
+
+	checksums, err := parseChecksums(data)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid %q", r.verification.ChecksumsFile)
+	}
+	r.checksums[version] = checksums
+	return checksums, nil
+}
+
+// isNotFound returns true if the error reports a file that does not exist in the repository.
+func isNotFound(err error) bool {
+	return errors.Is(err, errNotFound) || errors.Is(err, os.ErrNotExist)
+}
+
+// parseChecksums parses a checksums file in the format used by sha256sum, e.g.
+// 6f1e...9a3c  infrastructure-components.yaml.
+func parseChecksums(data []byte) (map[string]string, error) {
+	checksums := map[string]string{}
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			return nil, errors.Errorf("invalid line %q", line)
+		}
+		checksum := strings.ToLower(fields[0])
+		if _, err := hex.DecodeString(checksum); err != nil || len(checksum) != sha256.Size*2 {
+			return nil, errors.Errorf("invalid sha256 checksum in line %q", line)
+		}
+		// sha256sum prefixes the file name with * in binary mode; file names can also be relative paths.
+		name := path.Base(strings.TrimPrefix(fields[1], "*"))
+		checksums[name] = checksum
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return checksums, nil
+}
+
+// verifySignature verifies a signature created with cosign sign-blob, using any of the given public keys.
+// Public keys can be PEM encoded or paths to PEM files.
+func verifySignature(data, signature []byte, publicKeys []string) error {
+	// cosign sign-blob writes base64 encoded signatures.
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		sig = signature
+	}
+	digest := sha256.Sum256(data)
+
+	for _, k := range publicKeys {
+		publicKey, err := loadPublicKey(k)
+		if err != nil {
+			return err
+		}
+
+		switch key := publicKey.(type) {
+		case *ecdsa.PublicKey:
+			if ecdsa.VerifyASN1(key, digest[:], sig) {
+				return nil
+			}
+		case *rsa.PublicKey:
+			if rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], sig) == nil {
+				return nil
+			}
+		case ed25519.PublicKey:
+			if ed25519.Verify(key, data, sig) {
+				return nil
+			}
+		default:
+			return errors.Errorf("unsupported public key type %T", publicKey)
+		}
+	}
+	return errors.New("the signature does not match any of the configured public keys")
+}
+
+// loadPublicKey loads a PEM encoded public key, reading it from a file if the value is not PEM encoded.
+func loadPublicKey(value string) (crypto.PublicKey, error) {
+	data := []byte(value)
+	if !strings.HasPrefix(strings.TrimSpace(value), "-----BEGIN") {
+		var err error
+		data, err = os.ReadFile(value) //nolint:gosec
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read public key %s", value)
+		}
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, errors.New("invalid public key: failed to decode PEM data")
+	}
+	publicKey, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, errors.Wrap(err, "invalid public key")
+	}
+	return publicKey, nil
+}
@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package repository
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+)
+
+func Test_verifyingRepository_GetFile(t *testing.T) {
+	g := NewWithT(t)
+
+	components := []byte("components")
+	checksums := []byte(fmt.Sprintf("%s  components.yaml\n", sha256Hex(components)))
+
+	ecdsaKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	digest := sha256.Sum256(checksums)
+	ecdsaSignature, err := ecdsa.SignASN1(rand.Reader, ecdsaKey, digest[:])
+	g.Expect(err).ToNot(HaveOccurred())
+	ecdsaPublicKey := publicKeyPEM(g, &ecdsaKey.PublicKey)
+
+	ed25519PublicKey, ed25519Key, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+	ed25519Signature := ed25519.Sign(ed25519Key, checksums)
+
+	otherKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Public keys can also be read from files.
+	publicKeyFile := filepath.Join(t.TempDir(), "cosign.pub")
+	g.Expect(os.WriteFile(publicKeyFile, []byte(ecdsaPublicKey), 0600)).To(Succeed())
+
+	tests := []struct {
+		name         string
+		repository   Repository
+		verification *config.Verification
+		wantErr      bool
+	}{
+		{
+			name: "pass if checksums are not published and verification is not required",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile},
+		},
+		{
+			name: "fail if checksums are not published and verification is required",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, Required: true},
+			wantErr:      true,
+		},
+		{
+			name: "pass if the checksum matches",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, Required: true},
+		},
+		{
+			name: "pass if the checksum matches, with a custom checksums file",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "SHA256SUMS", checksums),
+			verification: &config.Verification{ChecksumsFile: "SHA256SUMS", Required: true},
+		},
+		{
+			name: "fail if the checksum does not match",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", []byte("tampered")).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile},
+			wantErr:      true,
+		},
+		{
+			name: "fail if the checksums file cannot be fetched, even if verification is not required",
+			repository: &failingChecksumsRepository{
+				Repository: NewMemoryRepository().
+					WithFile("v1.0.0", "components.yaml", components),
+			},
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile},
+			wantErr:      true,
+		},
+		{
+			name: "fail if the file is not listed in the checksums file, even if verification is not required",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", []byte(fmt.Sprintf("%s  metadata.yaml\n", sha256Hex(components)))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile},
+			wantErr:      true,
+		},
+		{
+			name: "fail if the file is not listed in the checksums file and verification is required",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", []byte(fmt.Sprintf("%s  metadata.yaml\n", sha256Hex(components)))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, Required: true},
+			wantErr:      true,
+		},
+		{
+			name: "pass if the signature is valid for an ECDSA key",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", []byte(base64.StdEncoding.EncodeToString(ecdsaSignature))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, PublicKeys: []string{publicKeyPEM(g, &otherKey.PublicKey), ecdsaPublicKey}},
+		},
+		{
+			name: "pass if the signature is valid for an ECDSA key read from a file",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", []byte(base64.StdEncoding.EncodeToString(ecdsaSignature))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, PublicKeys: []string{publicKeyFile}},
+		},
+		{
+			name: "pass if the signature is valid for an ed25519 key",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", []byte(base64.StdEncoding.EncodeToString(ed25519Signature))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, PublicKeys: []string{publicKeyPEM(g, ed25519PublicKey)}},
+		},
+		{
+			name: "fail if the signature does not match the public keys",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums).
+				WithFile("v1.0.0", "checksums.txt.sig", []byte(base64.StdEncoding.EncodeToString(ecdsaSignature))),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, PublicKeys: []string{publicKeyPEM(g, &otherKey.PublicKey)}},
+			wantErr:      true,
+		},
+		{
+			name: "fail if the signature is missing",
+			repository: NewMemoryRepository().
+				WithFile("v1.0.0", "components.yaml", components).
+				WithFile("v1.0.0", "checksums.txt", checksums),
+			verification: &config.Verification{ChecksumsFile: config.DefaultChecksumsFile, PublicKeys: []string{ecdsaPublicKey}},
+			wantErr:      true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := newVerifyingRepository(tt.repository, "infrastructure-foo", tt.verification)
+			got, err := r.GetFile(context.Background(), "v1.0.0", "components.yaml")
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(components))
+		})
+	}
+}
+
+func Test_parseChecksums(t *testing.T) {
+	g := NewWithT(t)
+
+	sum := sha256Hex([]byte("foo"))
+	got, err := parseChecksums([]byte(fmt.Sprintf("# checksums\n%s  core-components.yaml\n\n%s *out/metadata.yaml\n", sum, sum)))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(got).To(Equal(map[string]string{
+		"core-components.yaml": sum,
+		"metadata.yaml":        sum,
+	}))
+
+	_, err = parseChecksums([]byte("foo  core-components.yaml\n"))
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = parseChecksums([]byte(sum + "\n"))
+	g.Expect(err).To(HaveOccurred())
+}
+
+// failingChecksumsRepository is a repository failing to fetch the checksums file with an error other than not found.
+type failingChecksumsRepository struct {
+	Repository
+}
+
+func (r *failingChecksumsRepository) GetFile(ctx context.Context, version, path string) ([]byte, error) {
+	if path == config.DefaultChecksumsFile {
+		return nil, errors.New("connection reset by peer")
+	}
+	return r.Repository.GetFile(ctx, version, path)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func publicKeyPEM(g *WithT, publicKey interface{}) string {
+	der, err := x509.MarshalPKIXPublicKey(publicKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	return string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der}))
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// OutputSchemaVersion is the version of the schema used for the machine-readable representation of object trees.
+// Fields are only added to this schema; removing or changing fields requires a new version.
+const OutputSchemaVersion = "v1alpha1"
+
+// Output is the machine-readable representation of an ObjectTree, e.g. used by `clusterctl describe cluster -o json`.
+type Output struct {
+	// SchemaVersion is the version of the schema of this document.
+	SchemaVersion string `json:"schemaVersion"`
+
+	// Options are the options used to build the object tree.
+	Options OutputOptions `json:"options"`
+
+	// Root is the root node of the object tree, the Cluster.
+	Root OutputNode `json:"root"`
+}
+
+// OutputList is the machine-readable representation of a list of ObjectTrees, e.g. when describing all the clusters.
+type OutputList struct {
+	// SchemaVersion is the version of the schema of this document.
+	SchemaVersion string `json:"schemaVersion"`
+
+	// Items are the object trees.
+	Items []Output `json:"items"`
+}
+
+// OutputOptions are the options used to build an object tree.
+type OutputOptions struct {
+	// ShowOtherConditions is the list of comma separated kind or kind/name for which all the conditions are shown.
+	ShowOtherConditions string `json:"showOtherConditions,omitempty"`
+
+	// ShowMachineSets is true if MachineSets are included in the tree.
+	ShowMachineSets bool `json:"showMachineSets"`
+
+	// ShowClusterResourceSets is true if ClusterResourceSets are included in the tree.
+	ShowClusterResourceSets bool `json:"showClusterResourceSets"`
+
+	// ShowTemplates is true if infrastructure and bootstrap config templates are included in the tree.
+	ShowTemplates bool `json:"showTemplates"`
+
+	// Echo is true if objects with the same ready condition of their parent are included in the tree.
+	Echo bool `json:"echo"`
+
+	// Grouping is true if sibling objects with the same ready condition are grouped.
+	Grouping bool `json:"grouping"`
+}
+
+// OutputNode is a node of the object tree.
+type OutputNode struct {
+	// Kind is the kind of the object; for group nodes it is the kind of the grouped objects
+	// with the Group suffix, e.g. MachineGroup.
+	Kind string `json:"kind"`
+
+	// APIVersion is the API version of the object; it is empty for virtual objects.
+	APIVersion string `json:"apiVersion,omitempty"`
+
+	// Namespace is the namespace of the object.
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name is the name of the object; it is empty for group nodes.
+	Name string `json:"name,omitempty"`
+
+	// MetaName is the name used for the object in the presentation layer, e.g. ControlPlane.
+	MetaName string `json:"metaName,omitempty"`
+
+	// Virtual is true if the node does not correspond to a real object, e.g. Workers or a group node.
+	Virtual bool `json:"virtual,omitempty"`
+
+	// Deleting is true if the object is being deleted.
+	Deleting bool `json:"deleting,omitempty"`
+
+	// Grouping is true if sibling children of the node with the same ready condition are grouped.
+	Grouping bool `json:"grouping,omitempty"`
+
+	// GroupItems are the names of the objects represented by a group node.
+	GroupItems []string `json:"groupItems,omitempty"`
+
+	// Ready is the ready condition of the object, if any.
+	Ready *OutputCondition `json:"ready,omitempty"`
+
+	// Conditions are all the conditions of the object except the ready condition.
+	Conditions []OutputCondition `json:"conditions,omitempty"`
+
+	// Children are the children of the node, sorted in the same order used by the text output.
+	Children []OutputNode `json:"children,omitempty"`
+}
+
+// OutputCondition is a condition of an object in the object tree.
+type OutputCondition struct {
+	Type               string      `json:"type"`
+	Status             string      `json:"status"`
+	Severity           string      `json:"severity,omitempty"`
+	Reason             string      `json:"reason,omitempty"`
+	Message            string      `json:"message,omitempty"`
+	LastTransitionTime metav1.Time `json:"lastTransitionTime,omitempty"`
+}
+
+// Output returns the machine-readable representation of the object tree.
+func (od ObjectTree) Output() Output {
+	return Output{
+		SchemaVersion: OutputSchemaVersion,
+		Options: OutputOptions{
+			ShowOtherConditions:     od.options.ShowOtherConditions,
+			ShowMachineSets:         od.options.ShowMachineSets,
+			ShowClusterResourceSets: od.options.ShowClusterResourceSets,
+			ShowTemplates:           od.options.ShowTemplates,
+			Echo:                    od.options.Echo,
+			Grouping:                od.options.Grouping,
+		},
+		Root: od.outputNode(od.root),
+	}
+}
+
+func (od ObjectTree) outputNode(obj client.Object) OutputNode {
+	node := OutputNode{
+		Kind:      obj.GetObjectKind().GroupVersionKind().Kind,
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+		MetaName:  GetMetaName(obj),
+		Virtual:   IsVirtualObject(obj),
+		Deleting:  !obj.GetDeletionTimestamp().IsZero(),
+		Grouping:  IsGroupingObject(obj),
+	}
+	if !node.Virtual {
+		node.APIVersion = obj.GetObjectKind().GroupVersionKind().GroupVersion().String()
+	}
+	if IsGroupObject(obj) {
+		// The name of group nodes is randomly generated, so it is not part of the output.
+		node.Name = ""
+		node.GroupItems = strings.Split(GetGroupItems(obj), GroupItemsSeparator)
+	}
+	if ready := GetReadyCondition(obj); ready != nil {
+		c := outputCondition(ready)
+		node.Ready = &c
+	}
+	for _, c := range GetOtherConditions(obj) {
+		node.Conditions = append(node.Conditions, outputCondition(c))
+	}
+
+	children := od.GetObjectsByParent(obj.GetUID())
+	// Children are sorted like in the text output: objects with higher z-order first, then by kind and name.
+	sort.Slice(children, func(i, j int) bool {
+		if GetZOrder(children[i]) == GetZOrder(children[j]) {
+			return sortKey(children[i]) < sortKey(children[j])
+		}
+		return GetZOrder(children[i]) > GetZOrder(children[j])
+	})
+	for _, child := range children {
+		node.Children = append(node.Children, od.outputNode(child))
+	}
+	return node
+}
+
+func sortKey(obj client.Object) string {
+	if IsVirtualObject(obj) && !IsGroupObject(obj) {
+		if metaName := GetMetaName(obj); metaName != "" {
+			return metaName
+		}
+		return obj.GetName()
+	}
+	key := obj.GetObjectKind().GroupVersionKind().Kind + "/" + obj.GetName()
+	if metaName := GetMetaName(obj); metaName != "" {
+		key = metaName + " - " + key
+	}
+	return key
+}
+
+func outputCondition(c *clusterv1.Condition) OutputCondition {
+	return OutputCondition{
+		Type:               string(c.Type),
+		Status:             string(c.Status),
+		Severity:           string(c.Severity),
+		Reason:             c.Reason,
+		Message:            c.Message,
+		LastTransitionTime: c.LastTransitionTime,
+	}
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tree
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func Test_Output(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := fakeCluster("my-cluster",
+		withClusterCondition(conditions.TrueCondition(clusterv1.ReadyCondition)),
+		withClusterCondition(conditions.FalseCondition(clusterv1.ControlPlaneInitializedCondition, "WaitingForControlPlane", clusterv1.ConditionSeverityInfo, "waiting")),
+	)
+	cluster.APIVersion = clusterv1.GroupVersion.String()
+	tree := NewObjectTree(cluster, ObjectTreeOptions{Grouping: true, Echo: true})
+
+	workers := VirtualObject("ns", "WorkerGroup", "Workers")
+	tree.Add(cluster, workers, GroupingObject(true))
+	tree.Add(workers, fakeMachine("machine-b", withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition))))
+	tree.Add(workers, fakeMachine("machine-a", withMachineCondition(conditions.TrueCondition(clusterv1.ReadyCondition))))
+	tree.Add(workers, fakeMachine("machine-c", withMachineCondition(conditions.FalseCondition(clusterv1.ReadyCondition, "Failed", clusterv1.ConditionSeverityError, "boom"))), ZOrder(1))
+
+	output := tree.Output()
+	g.Expect(output.SchemaVersion).To(Equal(OutputSchemaVersion))
+	g.Expect(output.Options).To(Equal(OutputOptions{Grouping: true, Echo: true}))
+
+	root := output.Root
+	g.Expect(root.Kind).To(Equal("Cluster"))
+	g.Expect(root.APIVersion).To(Equal(clusterv1.GroupVersion.String()))
+	g.Expect(root.Namespace).To(Equal("ns"))
+	g.Expect(root.Name).To(Equal("my-cluster"))
+	g.Expect(root.Ready).ToNot(BeNil())
+	g.Expect(root.Ready.Status).To(Equal(string(corev1.ConditionTrue)))
+	g.Expect(root.Conditions).To(HaveLen(1))
+	g.Expect(root.Conditions[0].Type).To(Equal(string(clusterv1.ControlPlaneInitializedCondition)))
+	g.Expect(root.Conditions[0].Severity).To(Equal(string(clusterv1.ConditionSeverityInfo)))
+	g.Expect(root.Conditions[0].Reason).To(Equal("WaitingForControlPlane"))
+	g.Expect(root.Conditions[0].Message).To(Equal("waiting"))
+
+	g.Expect(root.Children).To(HaveLen(1))
+	workersNode := root.Children[0]
+	g.Expect(workersNode.Virtual).To(BeTrue())
+	g.Expect(workersNode.APIVersion).To(BeEmpty())
+	g.Expect(workersNode.Grouping).To(BeTrue())
+
+	// Children are sorted by z-order, then the ready machines are grouped.
+	g.Expect(workersNode.Children).To(HaveLen(2))
+	g.Expect(workersNode.Children[0].Name).To(Equal("machine-c"))
+	g.Expect(workersNode.Children[0].Ready.Severity).To(Equal(string(clusterv1.ConditionSeverityError)))
+	group := workersNode.Children[1]
+	g.Expect(group.Kind).To(Equal("MachineGroup"))
+	g.Expect(group.Name).To(BeEmpty())
+	g.Expect(group.Virtual).To(BeTrue())
+	g.Expect(group.GroupItems).To(Equal([]string{"machine-a", "machine-b"}))
+	g.Expect(group.Ready.Status).To(Equal(string(corev1.ConditionTrue)))
+}
@@ -123,6 +123,14 @@ type ApplyUpgradeOptions struct {
 
 	// WaitProviderTimeout sets the timeout per provider upgrade.
 	WaitProviderTimeout time.Duration
+
+	// RollbackOnFailure instructs the upgrade apply command to wait till the providers are available and the conversion
+	// of their CRDs works, and to roll back the providers to the previous version if not.
+	RollbackOnFailure bool
+
+	// SkipVerification disables the verification of the files fetched from provider repositories using
+	// the published checksums and signatures.
+	SkipVerification bool
 }
 
 func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgradeOptions) error {
@@ -136,6 +144,10 @@ func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgrad
 		options.WaitProviderTimeout = time.Duration(5*60) * time.Second
 	}
 
+	if options.SkipVerification {
+		c.skipVerification()
+	}
+
 	// Get the client for interacting with the management cluster.
 	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
 	if err != nil {
@@ -173,6 +185,7 @@ func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgrad
 	opts := cluster.UpgradeOptions{
 		WaitProviders:       options.WaitProviders,
 		WaitProviderTimeout: options.WaitProviderTimeout,
+		RollbackOnFailure:   options.RollbackOnFailure,
 	}
 
 	// If we are upgrading a specific set of providers only, process the providers and call ApplyCustomPlan.
@@ -219,6 +232,42 @@ func (c *clusterctlClient) ApplyUpgrade(ctx context.Context, options ApplyUpgrad
 	return clusterClient.ProviderUpgrader().ApplyPlan(ctx, opts, options.Contract)
 }
 
+// RollbackUpgradeOptions carries the options supported by RollbackUpgrade.
+type RollbackUpgradeOptions struct {
+	// Kubeconfig to use for accessing the management cluster. If empty, default discovery rules apply.
+	Kubeconfig Kubeconfig
+
+	// WaitProviders instructs the upgrade rollback command to wait till the providers are successfully rolled back.
+	WaitProviders bool
+
+	// WaitProviderTimeout sets the timeout per provider rollback.
+	WaitProviderTimeout time.Duration
+}
+
+func (c *clusterctlClient) RollbackUpgrade(ctx context.Context, options RollbackUpgradeOptions) error {
+	// Default WaitProviderTimeout as we cannot rely on defaulting in the CLI
+	// when clusterctl is used as a library.
+	if options.WaitProviderTimeout.Nanoseconds() == 0 {
+		options.WaitProviderTimeout = time.Duration(5*60) * time.Second
+	}
+
+	// Get the client for interacting with the management cluster.
+	clusterClient, err := c.clusterClientFactory(ClusterClientFactoryInput{Kubeconfig: options.Kubeconfig})
+	if err != nil {
+		return err
+	}
+
+	// Ensure this command only runs against management clusters with the current Cluster API contract.
+	if err := clusterClient.ProviderInventory().CheckCAPIContract(ctx); err != nil {
+		return err
+	}
+
+	return clusterClient.ProviderUpgrader().Rollback(ctx, cluster.UpgradeOptions{
+		WaitProviders:       options.WaitProviders,
+		WaitProviderTimeout: options.WaitProviderTimeout,
+	})
+}
+
 func addUpgradeItems(ctx context.Context, clusterClient cluster.Client, upgradeItems []cluster.UpgradeItem, providerType clusterctlv1.ProviderType, providers ...string) ([]cluster.UpgradeItem, error) {
 	for _, upgradeReference := range providers {
 		providerUpgradeItem, err := parseUpgradeItem(ctx, clusterClient, upgradeReference, providerType)
@@ -183,8 +183,29 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 			wantProviders: &clusterctlv1.ProviderList{
 				ListMeta: metav1.ListMeta{},
 				Items: []clusterctlv1.Provider{ // both providers should be upgraded
-					fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"),
-					fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"),
+					withPreviousVersion(fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"), "v1.0.0"),
+					withPreviousVersion(fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"), "v2.0.0"),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "apply a plan with rollback on failure",
+			fields: fields{
+				client: fakeClientForUpgrade(), // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+			},
+			args: args{
+				options: ApplyUpgradeOptions{
+					Kubeconfig:        Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"},
+					Contract:          test.CurrentCAPIContract,
+					RollbackOnFailure: true,
+				},
+			},
+			wantProviders: &clusterctlv1.ProviderList{
+				ListMeta: metav1.ListMeta{},
+				Items: []clusterctlv1.Provider{ // both providers should be upgraded
+					withPreviousVersion(fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"), "v1.0.0"),
+					withPreviousVersion(fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"), "v2.0.0"),
 				},
 			},
 			wantErr: false,
@@ -207,7 +228,7 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 			wantProviders: &clusterctlv1.ProviderList{
 				ListMeta: metav1.ListMeta{},
 				Items: []clusterctlv1.Provider{ // only one provider should be upgraded
-					fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"),
+					withPreviousVersion(fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"), "v1.0.0"),
 					fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.0", "infra-system"),
 				},
 			},
@@ -232,7 +253,7 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 				ListMeta: metav1.ListMeta{},
 				Items: []clusterctlv1.Provider{ // only one provider should be upgraded
 					fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "cluster-api-system"),
-					fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"),
+					withPreviousVersion(fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"), "v2.0.0"),
 				},
 			},
 			wantErr: false,
@@ -255,8 +276,8 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 			wantProviders: &clusterctlv1.ProviderList{
 				ListMeta: metav1.ListMeta{},
 				Items: []clusterctlv1.Provider{
-					fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"),
-					fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"),
+					withPreviousVersion(fakeProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.1", "cluster-api-system"), "v1.0.0"),
+					withPreviousVersion(fakeProvider("infra", clusterctlv1.InfrastructureProviderType, "v2.0.1", "infra-system"), "v2.0.0"),
 				},
 			},
 			wantErr: false,
@@ -299,6 +320,46 @@ func Test_clusterctlClient_ApplyUpgrade(t *testing.T) {
 	}
 }
 
+func Test_clusterctlClient_RollbackUpgrade(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	client := fakeClientForUpgrade() // core v1.0.0 (v1.0.1 available), infra v2.0.0 (v2.0.1 available)
+	kubeconfig := Kubeconfig{Path: "kubeconfig", Context: "mgmt-context"}
+
+	// Rollback fails if no providers have been upgraded.
+	err := client.RollbackUpgrade(ctx, RollbackUpgradeOptions{Kubeconfig: kubeconfig})
+	g.Expect(err).To(MatchError(ContainSubstring("no providers have been upgraded")))
+
+	g.Expect(client.ApplyUpgrade(ctx, ApplyUpgradeOptions{
+		Kubeconfig:   kubeconfig,
+		CoreProvider: "cluster-api-system/cluster-api:v1.0.1",
+	})).To(Succeed())
+
+	// Rollback restores the version the providers had before the upgrade.
+	g.Expect(client.RollbackUpgrade(ctx, RollbackUpgradeOptions{Kubeconfig: kubeconfig})).To(Succeed())
+
+	proxy := client.clusters[cluster.Kubeconfig(kubeconfig)].Proxy()
+	c, err := proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	gotProviders := &clusterctlv1.ProviderList{}
+	g.Expect(c.List(ctx, gotProviders)).To(Succeed())
+
+	versions := map[string]string{}
+	for _, provider := range gotProviders.Items {
+		versions[provider.Name] = provider.Version
+		g.Expect(provider.Annotations).ToNot(HaveKey(clusterctlv1.PreviousVersionAnnotation))
+	}
+	g.Expect(versions).To(Equal(map[string]string{
+		"cluster-api":          "v1.0.0",
+		"infrastructure-infra": "v2.0.0",
+	}))
+
+	// A rollback can't be rolled back.
+	err = client.RollbackUpgrade(ctx, RollbackUpgradeOptions{Kubeconfig: kubeconfig})
+	g.Expect(err).To(MatchError(ContainSubstring("no providers have been upgraded")))
+}
+
 func fakeClientForUpgrade() *fakeClient {
 	core := config.NewProvider("cluster-api", "https://somewhere.com", clusterctlv1.CoreProviderType)
 	infra := config.NewProvider("infra", "https://somewhere.com", clusterctlv1.InfrastructureProviderType)
@@ -312,6 +373,7 @@ func fakeClientForUpgrade() *fakeClient {
 	repository1 := newFakeRepository(ctx, core, config1).
 		WithPaths("root", "components.yaml").
 		WithDefaultVersion("v1.0.1").
+		WithFile("v1.0.0", "components.yaml", componentsYAML("ns2")).
 		WithFile("v1.0.1", "components.yaml", componentsYAML("ns2")).
 		WithVersions("v1.0.0", "v1.0.1").
 		WithMetadata("v1.0.1", &clusterctlv1.Metadata{
@@ -322,6 +384,7 @@ func fakeClientForUpgrade() *fakeClient {
 	repository2 := newFakeRepository(ctx, infra, config1).
 		WithPaths("root", "components.yaml").
 		WithDefaultVersion("v2.0.0").
+		WithFile("v2.0.0", "components.yaml", componentsYAML("ns2")).
 		WithFile("v2.0.1", "components.yaml", componentsYAML("ns2")).
 		WithVersions("v2.0.0", "v2.0.1").
 		WithMetadata("v2.0.1", &clusterctlv1.Metadata{
@@ -367,6 +430,11 @@ func fakeProvider(name string, providerType clusterctlv1.ProviderType, version,
 	}
 }
 
+func withPreviousVersion(provider clusterctlv1.Provider, version string) clusterctlv1.Provider {
+	provider.Annotations = map[string]string{clusterctlv1.PreviousVersionAnnotation: version}
+	return provider
+}
+
 func Test_parseUpgradeItem(t *testing.T) {
 	type args struct {
 		provider string
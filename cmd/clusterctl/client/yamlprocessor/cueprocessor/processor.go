@@ -0,0 +1,257 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package cueprocessor implements a clusterctl yaml processor for templates written in CUE.
+//
+// The CUE processor is provided by a separate Go module, so the CUE libraries are not a dependency of the
+// Cluster API module; programs embedding the clusterctl library can make the processor available with the
+// "cue" name by importing this package, e.g.
+//
+//	import _ "sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor/cueprocessor"
+package cueprocessor
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"cuelang.org/go/cue"
+	"cuelang.org/go/cue/cuecontext"
+	cueyaml "cuelang.org/go/encoding/yaml"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+)
+
+const (
+	// cueVariablesField is the name of the field of CUE templates defining the variables.
+	cueVariablesField = "vars"
+
+	// cueObjectsField is the name of the field of CUE templates defining the list of objects to be generated.
+	cueObjectsField = "objects"
+)
+
+func init() {
+	yamlprocessor.RegisterProcessor(yamlprocessor.CUEProcessorName, func() yamlprocessor.Processor {
+		return NewProcessor()
+	})
+}
+
+// Processor is a yaml processor for templates written in CUE. Templates
+// must define the variables as fields of a top level "vars" struct and the
+// generated objects as a top level "objects" list, e.g.
+//
+//	vars: {
+//		CLUSTER_NAME:         string
+//		WORKER_MACHINE_COUNT: *1 | int
+//	}
+//	objects: [{apiVersion: "cluster.x-k8s.io/v1beta1", kind: "Cluster", metadata: name: vars.CLUSTER_NAME}]
+//
+// Variables with a default value are optional. Values of variables which do
+// not accept strings are decoded as yaml, thus allowing e.g. to pass a list of
+// node pools.
+// See https://cuelang.org for more details.
+type Processor struct{}
+
+var _ yamlprocessor.Processor = &Processor{}
+
+// NewProcessor returns a new CUE template processor.
+func NewProcessor() *Processor {
+	return &Processor{}
+}
+
+// GetTemplateName returns the name of the template that the CUE processor
+// uses. It follows the cluster template naming convention of
+// "cluster-template<-flavor>.cue".
+func (tp *Processor) GetTemplateName(_, flavor string) string {
+	name := "cluster-template"
+	if flavor != "" {
+		name = fmt.Sprintf("%s-%s", name, flavor)
+	}
+	return fmt.Sprintf("%s.cue", name)
+}
+
+// GetClusterClassTemplateName returns the name of the cluster class template
+// that the CUE processor uses. It follows the cluster class template naming
+// convention of "clusterclass<-name>.cue".
+func (tp *Processor) GetClusterClassTemplateName(_, name string) string {
+	return fmt.Sprintf("clusterclass-%s.cue", name)
+}
+
+// GetVariables returns a list of the variables specified in the template.
+func (tp *Processor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	varNames := make([]string, 0, len(variables))
+	for k := range variables {
+		varNames = append(varNames, k)
+	}
+	sort.Strings(varNames)
+	return varNames, nil
+}
+
+// GetVariableMap returns a map of the variables specified in the template
+// with their default values; required variables are mapped to nil.
+func (tp *Processor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	value, err := compileCUETemplate(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	return inspectCUEVariables(value)
+}
+
+// Process returns the final yaml with the objects generated by the template
+// using the values of the variables. If there are required variables without
+// corresponding values, it will return the raw yaml along with an error.
+func (tp *Processor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	value, err := compileCUETemplate(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+	variables, err := inspectCUEVariables(value)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	var missingVariables []string
+	values := map[string]string{}
+	for name, defaultValue := range variables {
+		v, err := variablesClient(name)
+		if err != nil {
+			if defaultValue == nil {
+				missingVariables = append(missingVariables, name)
+			}
+			continue
+		}
+		values[name] = v
+	}
+
+	if len(missingVariables) > 0 {
+		return rawArtifact, yamlprocessor.NewMissingVariablesError(missingVariables)
+	}
+
+	// NOTE: Variables are filled in a stable order, so errors are deterministic.
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		path := cue.MakePath(cue.Str(cueVariablesField), cue.Str(name))
+		if value, err = fillCUEVariable(value, path, values[name]); err != nil {
+			return rawArtifact, errors.Wrapf(err, "failed to set the value for variable %q", name)
+		}
+	}
+
+	objects := value.LookupPath(cue.ParsePath(cueObjectsField))
+	if !objects.Exists() {
+		return rawArtifact, errors.Errorf("template does not define the %q field", cueObjectsField)
+	}
+	if err := objects.Validate(cue.Concrete(true)); err != nil {
+		return rawArtifact, errors.Wrap(err, "failed to evaluate template")
+	}
+	iter, err := objects.List()
+	if err != nil {
+		return rawArtifact, errors.Wrapf(err, "the %q field must be a list", cueObjectsField)
+	}
+
+	var docs []string
+	for iter.Next() {
+		b, err := iter.Value().MarshalJSON()
+		if err != nil {
+			return rawArtifact, errors.Wrap(err, "failed to encode object")
+		}
+		doc, err := yaml.JSONToYAML(b)
+		if err != nil {
+			return rawArtifact, errors.Wrap(err, "failed to encode object")
+		}
+		docs = append(docs, string(doc))
+	}
+	return []byte(strings.Join(docs, "---\n")), nil
+}
+
+func compileCUETemplate(rawArtifact []byte) (cue.Value, error) {
+	value := cuecontext.New().CompileBytes(rawArtifact)
+	if err := value.Err(); err != nil {
+		return cue.Value{}, errors.Wrap(err, "failed to compile template")
+	}
+	return value, nil
+}
+
+// inspectCUEVariables returns a map of the fields of the vars struct with
+// their default values; fields without a concrete default value are required,
+// and thus mapped to nil.
+func inspectCUEVariables(value cue.Value) (map[string]*string, error) {
+	variables := map[string]*string{}
+	vars := value.LookupPath(cue.ParsePath(cueVariablesField))
+	if !vars.Exists() {
+		return variables, nil
+	}
+
+	iter, err := vars.Fields(cue.Optional(true))
+	if err != nil {
+		return nil, errors.Wrapf(err, "the %q field must be a struct", cueVariablesField)
+	}
+	for iter.Next() {
+		name := iter.Selector().Unquoted()
+		defaultValue, ok := iter.Value().Default()
+		if !ok && !iter.Value().IsConcrete() {
+			variables[name] = nil
+			continue
+		}
+		s, err := cueValueString(defaultValue)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to read the default value for variable %q", name)
+		}
+		variables[name] = &s
+	}
+	return variables, nil
+}
+
+// fillCUEVariable fills the field at path with value; if the field does not
+// accept strings, value is decoded as yaml.
+func fillCUEVariable(v cue.Value, path cue.Path, value string) (cue.Value, error) {
+	field := v.LookupPath(path)
+	if field.IncompleteKind()&cue.StringKind != 0 {
+		v = v.FillPath(path, value)
+	} else {
+		f, err := cueyaml.Extract("", value)
+		if err != nil {
+			return v, errors.Wrap(err, "failed to decode value as yaml")
+		}
+		v = v.FillPath(path, v.Context().BuildFile(f))
+	}
+	if err := v.LookupPath(path).Err(); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// cueValueString returns a concrete value as a string; non-string values are
+// encoded as json.
+func cueValueString(v cue.Value) (string, error) {
+	if v.Kind() == cue.StringKind {
+		return v.String()
+	}
+	b, err := v.MarshalJSON()
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,218 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cueprocessor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+const cueTemplate = `
+vars: {
+	CLUSTER_NAME:         string
+	NAMESPACE:            *"default" | string
+	WORKER_MACHINE_COUNT: *1 | int
+	NODE_POOLS:           *[] | [...{name: string, replicas: int}]
+}
+
+objects: [
+	{
+		apiVersion: "cluster.x-k8s.io/v1beta1"
+		kind:       "Cluster"
+		metadata: {
+			name:      vars.CLUSTER_NAME
+			namespace: vars.NAMESPACE
+		}
+	},
+	for pool in vars.NODE_POOLS {
+		apiVersion: "cluster.x-k8s.io/v1beta1"
+		kind:       "MachineDeployment"
+		metadata: {
+			name:      "\(vars.CLUSTER_NAME)-\(pool.name)"
+			namespace: vars.NAMESPACE
+		}
+		spec: replicas: pool.replicas
+	},
+]
+`
+
+func TestProcessor_GetTemplateName(t *testing.T) {
+	g := NewWithT(t)
+	p := NewProcessor()
+	g.Expect(p.GetTemplateName("some-version", "some-flavor")).To(Equal("cluster-template-some-flavor.cue"))
+	g.Expect(p.GetTemplateName("", "")).To(Equal("cluster-template.cue"))
+	g.Expect(p.GetClusterClassTemplateName("", "some-class")).To(Equal("clusterclass-some-class.cue"))
+}
+
+func TestProcessor_GetVariableMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]*string
+		wantErr bool
+	}{
+		{
+			name: "fields without default values are required",
+			data: cueTemplate,
+			want: map[string]*string{
+				"CLUSTER_NAME":         nil,
+				"NAMESPACE":            ptr.To("default"),
+				"WORKER_MACHINE_COUNT": ptr.To("1"),
+				"NODE_POOLS":           ptr.To("[]"),
+			},
+		},
+		{
+			name: "templates without vars have no variables",
+			data: "objects: []",
+			want: map[string]*string{},
+		},
+		{
+			name:    "fails for invalid templates",
+			data:    "vars: {",
+			wantErr: true,
+		},
+		{
+			name:    "fails if vars is not a struct",
+			data:    "vars: []",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewProcessor()
+
+			got, err := p.GetVariableMap([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			vars, err := p.GetVariables([]byte(tt.data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(vars).To(HaveLen(len(tt.want)))
+		})
+	}
+}
+
+func TestProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name                  string
+		data                  string
+		configVariablesClient config.VariablesClient
+		want                  string
+		wantErr               bool
+		missingVariables      []string
+	}{
+		{
+			name: "generates objects using default values",
+			data: cueTemplate,
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CLUSTER_NAME", "foo"),
+			want: `apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: foo
+  namespace: default
+`,
+		},
+		{
+			name: "generates objects looping over node pools",
+			data: cueTemplate,
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CLUSTER_NAME", "foo").
+				WithVar("NAMESPACE", "bar").
+				WithVar("NODE_POOLS", "- name: md-0\n  replicas: 3\n"),
+			want: `apiVersion: cluster.x-k8s.io/v1beta1
+kind: Cluster
+metadata:
+  name: foo
+  namespace: bar
+---
+apiVersion: cluster.x-k8s.io/v1beta1
+kind: MachineDeployment
+metadata:
+  name: foo-md-0
+  namespace: bar
+spec:
+  replicas: 3
+`,
+		},
+		{
+			name:                  "returns error with missing template variables listed",
+			data:                  cueTemplate,
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+			missingVariables:      []string{"CLUSTER_NAME"},
+		},
+		{
+			name: "returns error if a variable value does not match its type",
+			data: cueTemplate,
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CLUSTER_NAME", "foo").
+				WithVar("WORKER_MACHINE_COUNT", "many"),
+			wantErr: true,
+		},
+		{
+			name:                  "returns error if objects is not defined",
+			data:                  "vars: {}",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+		{
+			name:                  "returns error if objects are not concrete",
+			data:                  "objects: [{kind: string}]",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewProcessor()
+
+			got, err := p.Process([]byte(tt.data), tt.configVariablesClient.Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				for _, name := range tt.missingVariables {
+					g.Expect(err.Error()).To(ContainSubstring(name))
+				}
+				g.Expect(got).To(Equal([]byte(tt.data)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestRegisterProcessor(t *testing.T) {
+	g := NewWithT(t)
+
+	// Importing this package makes the CUE processor available in clusterctl.
+	p, err := yamlprocessor.NewProcessor(yamlprocessor.CUEProcessorName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&Processor{}))
+}
@@ -0,0 +1,329 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"bytes"
+	"sort"
+	"strings"
+	"text/template"
+	"text/template/parse"
+
+	"github.com/Masterminds/sprig/v3"
+	"github.com/pkg/errors"
+	"sigs.k8s.io/yaml"
+)
+
+// GoTemplateProcessor is a yaml processor that uses Go text/template for
+// processing templates, thus allowing e.g. to loop over node pools or to
+// branch on variables. Variables are referenced in the format {{ .VAR }} and
+// are optional if a default value is specified in the format
+// {{ .VAR | default "value" }} or if they are only used as a condition, e.g.
+// {{ if .VAR }}. Variable values are always strings; fromYaml can be used
+// to decode structured values, e.g. {{ range fromYaml .NODE_POOLS }}.
+// Templates can use the functions provided by sprig, with the exception of
+// env and expandenv, plus fromYaml and toYaml.
+// See https://masterminds.github.io/sprig/ for more details.
+type GoTemplateProcessor struct{}
+
+var _ Processor = &GoTemplateProcessor{}
+
+// NewGoTemplateProcessor returns a new Go template processor.
+func NewGoTemplateProcessor() *GoTemplateProcessor {
+	return &GoTemplateProcessor{}
+}
+
+// GetTemplateName returns the name of the template that the Go template
+// processor uses. It follows the cluster template naming convention of
+// "cluster-template<-flavor>.yaml".
+func (tp *GoTemplateProcessor) GetTemplateName(version, flavor string) string {
+	return NewSimpleProcessor().GetTemplateName(version, flavor)
+}
+
+// GetClusterClassTemplateName returns the name of the cluster class template
+// that the Go template processor uses. It follows the cluster class template
+// naming convention of "clusterclass<-name>.yaml".
+func (tp *GoTemplateProcessor) GetClusterClassTemplateName(version, name string) string {
+	return NewSimpleProcessor().GetClusterClassTemplateName(version, name)
+}
+
+// GetVariables returns a list of the variables specified in the template.
+func (tp *GoTemplateProcessor) GetVariables(rawArtifact []byte) ([]string, error) {
+	variables, err := tp.GetVariableMap(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	varNames := make([]string, 0, len(variables))
+	for k := range variables {
+		varNames = append(varNames, k)
+	}
+	sort.Strings(varNames)
+	return varNames, nil
+}
+
+// GetVariableMap returns a map of the variables specified in the template
+// with their default values; required variables are mapped to nil.
+func (tp *GoTemplateProcessor) GetVariableMap(rawArtifact []byte) (map[string]*string, error) {
+	t, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return nil, err
+	}
+	return inspectGoTemplateVariables(t), nil
+}
+
+// Process returns the final yaml obtained by executing the template with the
+// values of the variables it uses. If there are required variables without
+// corresponding values, it will return the raw yaml along with an error.
+func (tp *GoTemplateProcessor) Process(rawArtifact []byte, variablesClient func(string) (string, error)) ([]byte, error) {
+	t, err := parseGoTemplate(rawArtifact)
+	if err != nil {
+		return rawArtifact, err
+	}
+
+	// Values are set only for the variables defined in the variablesClient; unset
+	// optional variables are left to the default function or to the condition using them.
+	var missingVariables []string
+	values := map[string]interface{}{}
+	for name, defaultValue := range inspectGoTemplateVariables(t) {
+		v, err := variablesClient(name)
+		if err != nil {
+			if defaultValue == nil {
+				missingVariables = append(missingVariables, name)
+			}
+			continue
+		}
+		values[name] = v
+	}
+
+	if len(missingVariables) > 0 {
+		return rawArtifact, &errMissingVariables{missingVariables}
+	}
+
+	var out bytes.Buffer
+	if err := t.Execute(&out, values); err != nil {
+		return rawArtifact, errors.Wrap(err, "failed to execute template")
+	}
+	return out.Bytes(), nil
+}
+
+// goTemplateFuncMap returns the functions available in templates.
+func goTemplateFuncMap() template.FuncMap {
+	funcMap := sprig.TxtFuncMap()
+	// Variables must be read via the variablesClient, which already takes care of os environment variables.
+	delete(funcMap, "env")
+	delete(funcMap, "expandenv")
+
+	funcMap["fromYaml"] = func(s string) (interface{}, error) {
+		var v interface{}
+		if err := yaml.Unmarshal([]byte(s), &v); err != nil {
+			return nil, errors.Wrap(err, "failed to decode yaml")
+		}
+		return v, nil
+	}
+	funcMap["toYaml"] = func(v interface{}) (string, error) {
+		b, err := yaml.Marshal(v)
+		if err != nil {
+			return "", errors.Wrap(err, "failed to encode yaml")
+		}
+		return strings.TrimSuffix(string(b), "\n"), nil
+	}
+	return funcMap
+}
+
+func parseGoTemplate(rawArtifact []byte) (*template.Template, error) {
+	t, err := template.New("template").Funcs(goTemplateFuncMap()).Parse(string(rawArtifact))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to parse template")
+	}
+	return t, nil
+}
+
+// inspectGoTemplateVariables walks through the parse trees of the template and
+// returns a map of the variable names with their default values. A variable is
+// required, and thus mapped to nil, if it is used at least once without a
+// default value and not only as a condition.
+func inspectGoTemplateVariables(t *template.Template) map[string]*string {
+	v := goTemplateVariables{}
+	for _, tt := range t.Templates() {
+		if tt.Tree == nil || tt.Tree.Root == nil {
+			continue
+		}
+		// NOTE: named templates are assumed to be invoked with the root data, e.g. {{ template "name" . }}.
+		v.traverse(tt.Tree.Root, true)
+	}
+
+	variables := make(map[string]*string, len(v))
+	for name, ref := range v {
+		if ref.required {
+			variables[name] = nil
+			continue
+		}
+		defaultValue := ref.defaultValue
+		variables[name] = &defaultValue
+	}
+	return variables
+}
+
+type goTemplateVariable struct {
+	required     bool
+	defaultValue string
+}
+
+type goTemplateVariables map[string]*goTemplateVariable
+
+func (v goTemplateVariables) add(name string, required bool, defaultValue string) {
+	ref, ok := v[name]
+	if !ok {
+		v[name] = &goTemplateVariable{required: required, defaultValue: defaultValue}
+		return
+	}
+	ref.required = ref.required || required
+	if ref.defaultValue == "" {
+		ref.defaultValue = defaultValue
+	}
+}
+
+// traverse recursively walks down the node and tracks the variables it uses;
+// isRoot reports if dot is the root data, which is not the case e.g. inside
+// the body of range and with actions.
+func (v goTemplateVariables) traverse(node parse.Node, isRoot bool) {
+	switch n := node.(type) {
+	case *parse.ListNode:
+		if n == nil {
+			return
+		}
+		for _, ln := range n.Nodes {
+			v.traverse(ln, isRoot)
+		}
+	case *parse.ActionNode:
+		v.traversePipe(n.Pipe, isRoot)
+	case *parse.IfNode:
+		v.traverseCondition(n.Pipe, isRoot)
+		v.traverse(n.List, isRoot)
+		v.traverse(n.ElseList, isRoot)
+	case *parse.WithNode:
+		v.traverseCondition(n.Pipe, isRoot)
+		v.traverse(n.List, false)
+		v.traverse(n.ElseList, isRoot)
+	case *parse.RangeNode:
+		v.traverseCondition(n.Pipe, isRoot)
+		v.traverse(n.List, false)
+		v.traverse(n.ElseList, isRoot)
+	case *parse.TemplateNode:
+		v.traversePipe(n.Pipe, isRoot)
+	}
+}
+
+// traverseCondition tracks the variables used in the pipeline of an if, with
+// or range action; a variable used as the entire condition is optional.
+func (v goTemplateVariables) traverseCondition(pipe *parse.PipeNode, isRoot bool) {
+	if pipe != nil && len(pipe.Cmds) == 1 && len(pipe.Cmds[0].Args) == 1 {
+		if name, ok := variableName(pipe.Cmds[0].Args[0], isRoot); ok {
+			v.add(name, false, "")
+			return
+		}
+	}
+	v.traversePipe(pipe, isRoot)
+}
+
+// traversePipe tracks the variables used in a pipeline; a variable is optional
+// if its value is passed to the default function, either as in
+// {{ .VAR | default "value" }} or as in {{ default "value" .VAR }}.
+func (v goTemplateVariables) traversePipe(pipe *parse.PipeNode, isRoot bool) {
+	if pipe == nil {
+		return
+	}
+	for i, cmd := range pipe.Cmds {
+		if isDefaultCmd(cmd) {
+			switch {
+			// {{ default "value" .VAR }}
+			case len(cmd.Args) == 3:
+				if name, ok := variableName(cmd.Args[2], isRoot); ok {
+					v.add(name, false, literalValue(cmd.Args[1]))
+					v.traverseArgs(cmd.Args[1:2], isRoot)
+					continue
+				}
+			// {{ .VAR | default "value" }}
+			case len(cmd.Args) == 2 && i > 0 && len(pipe.Cmds[i-1].Args) == 1:
+				if name, ok := variableName(pipe.Cmds[i-1].Args[0], isRoot); ok {
+					v.add(name, false, literalValue(cmd.Args[1]))
+				}
+			}
+		}
+		if i+1 < len(pipe.Cmds) && isDefaultCmd(pipe.Cmds[i+1]) && len(pipe.Cmds[i+1].Args) == 2 && len(cmd.Args) == 1 {
+			if _, ok := variableName(cmd.Args[0], isRoot); ok {
+				// Tracked together with the default function that follows.
+				continue
+			}
+		}
+		v.traverseArgs(cmd.Args, isRoot)
+	}
+}
+
+func (v goTemplateVariables) traverseArgs(args []parse.Node, isRoot bool) {
+	for _, arg := range args {
+		if name, ok := variableName(arg, isRoot); ok {
+			v.add(name, true, "")
+			continue
+		}
+		switch a := arg.(type) {
+		case *parse.PipeNode:
+			v.traversePipe(a, isRoot)
+		case *parse.ChainNode:
+			v.traverseArgs([]parse.Node{a.Node}, isRoot)
+		}
+	}
+}
+
+// variableName returns the name of the variable referenced by node, if any;
+// variables can be referenced as {{ .VAR }} when dot is the root data, or as
+// {{ $.VAR }} everywhere.
+func variableName(node parse.Node, isRoot bool) (string, bool) {
+	switch n := node.(type) {
+	case *parse.FieldNode:
+		if isRoot {
+			return n.Ident[0], true
+		}
+	case *parse.VariableNode:
+		if len(n.Ident) > 1 && n.Ident[0] == "$" {
+			return n.Ident[1], true
+		}
+	}
+	return "", false
+}
+
+func isDefaultCmd(cmd *parse.CommandNode) bool {
+	if len(cmd.Args) == 0 {
+		return false
+	}
+	id, ok := cmd.Args[0].(*parse.IdentifierNode)
+	return ok && id.Ident == "default"
+}
+
+// literalValue returns the value of a literal node as a string, or an empty
+// string if the node is not a literal.
+func literalValue(node parse.Node) string {
+	switch n := node.(type) {
+	case *parse.StringNode:
+		return n.Text
+	case *parse.NumberNode:
+		return n.Text
+	case *parse.BoolNode:
+		return n.String()
+	}
+	return ""
+}
@@ -0,0 +1,207 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/utils/ptr"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+)
+
+func TestGoTemplateProcessor_GetTemplateName(t *testing.T) {
+	g := NewWithT(t)
+	p := NewGoTemplateProcessor()
+	g.Expect(p.GetTemplateName("some-version", "some-flavor")).To(Equal("cluster-template-some-flavor.yaml"))
+	g.Expect(p.GetTemplateName("", "")).To(Equal("cluster-template.yaml"))
+	g.Expect(p.GetClusterClassTemplateName("", "some-class")).To(Equal("clusterclass-some-class.yaml"))
+}
+
+func TestGoTemplateProcessor_GetVariableMap(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    map[string]*string
+		wantErr bool
+	}{
+		{
+			name: "variables without default values are required",
+			data: "name: {{ .CLUSTER_NAME }}\nnamespace: {{ $.NAMESPACE | quote }}",
+			want: map[string]*string{
+				"CLUSTER_NAME": nil,
+				"NAMESPACE":    nil,
+			},
+		},
+		{
+			name: "variables with default values are optional",
+			data: `replicas: {{ .WORKER_MACHINE_COUNT | default 1 }}
+image: {{ default "kindest/node" .IMAGE }}
+version: {{ .KUBERNETES_VERSION | default "v1.29.0" | quote }}`,
+			want: map[string]*string{
+				"WORKER_MACHINE_COUNT": ptr.To("1"),
+				"IMAGE":                ptr.To("kindest/node"),
+				"KUBERNETES_VERSION":   ptr.To("v1.29.0"),
+			},
+		},
+		{
+			name: "variables only used as conditions are optional",
+			data: "{{ if .ENABLE_MHC }}kind: MachineHealthCheck{{ end }}{{ with .NODE_POOLS }}pools: {{ . }}{{ end }}",
+			want: map[string]*string{
+				"ENABLE_MHC": ptr.To(""),
+				"NODE_POOLS": ptr.To(""),
+			},
+		},
+		{
+			name: "variables used both with and without default values are required",
+			data: "{{ .CLUSTER_NAME | default \"foo\" }} {{ .CLUSTER_NAME }}",
+			want: map[string]*string{
+				"CLUSTER_NAME": nil,
+			},
+		},
+		{
+			name: "variables are detected inside range, with and named templates",
+			data: `{{ define "pool" }}name: {{ $.CLUSTER_NAME }}{{ end }}
+{{ range $i, $pool := fromYaml .NODE_POOLS }}
+- {{ $pool.name }}-{{ $.CLUSTER_NAME }}: {{ .replicas | default $.DEFAULT_REPLICAS }}
+{{ end }}
+{{ template "pool" . }}`,
+			want: map[string]*string{
+				"CLUSTER_NAME":     nil,
+				"NODE_POOLS":       nil,
+				"DEFAULT_REPLICAS": nil,
+			},
+		},
+		{
+			name:    "fails for invalid templates",
+			data:    "{{ .CLUSTER_NAME ",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewGoTemplateProcessor()
+
+			got, err := p.GetVariableMap([]byte(tt.data))
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(got).To(Equal(tt.want))
+
+			vars, err := p.GetVariables([]byte(tt.data))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(vars).To(HaveLen(len(tt.want)))
+		})
+	}
+}
+
+func TestGoTemplateProcessor_Process(t *testing.T) {
+	tests := []struct {
+		name                  string
+		data                  string
+		configVariablesClient config.VariablesClient
+		want                  string
+		wantErr               bool
+		missingVariables      []string
+	}{
+		{
+			name: "replaces variables and uses default values",
+			data: "name: {{ .CLUSTER_NAME }}\nreplicas: {{ .WORKER_MACHINE_COUNT | default 1 }}\nimage: {{ default \"kindest/node\" .IMAGE }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CLUSTER_NAME", "foo").WithVar("IMAGE", "bar"),
+			want: "name: foo\nreplicas: 1\nimage: bar",
+		},
+		{
+			name: "branches on variables",
+			data: "{{ if .ENABLE_MHC }}mhc: true{{ else }}mhc: false{{ end }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("ENABLE_MHC", "true"),
+			want: "mhc: true",
+		},
+		{
+			name:                  "branches on unset variables",
+			data:                  "{{ if .ENABLE_MHC }}mhc: true{{ else }}mhc: false{{ end }}",
+			configVariablesClient: test.NewFakeVariableClient(),
+			want:                  "mhc: false",
+		},
+		{
+			name: "loops over node pools",
+			data: `{{- range fromYaml .NODE_POOLS }}
+---
+name: {{ $.CLUSTER_NAME }}-{{ .name }}
+replicas: {{ .replicas }}
+{{- end }}`,
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CLUSTER_NAME", "foo").
+				WithVar("NODE_POOLS", "[{name: md-0, replicas: 1}, {name: md-1, replicas: 3}]"),
+			want: "\n---\nname: foo-md-0\nreplicas: 1\n---\nname: foo-md-1\nreplicas: 3",
+		},
+		{
+			name: "encodes values as yaml",
+			data: "labels:\n{{ fromYaml .LABELS | toYaml | indent 2 }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("LABELS", `{"a": "b", "c": "d"}`),
+			want: "labels:\n  a: b\n  c: d",
+		},
+		{
+			name: "returns error with missing template variables listed",
+			data: "{{ .BAR }} {{ .BAZ }} {{ .CAR }} {{ .DAR | default \"dar\" }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("CAR", "car"),
+			wantErr:          true,
+			missingVariables: []string{"BAR", "BAZ"},
+		},
+		{
+			name:                  "env is not available in templates",
+			data:                  "{{ env \"HOME\" }}",
+			configVariablesClient: test.NewFakeVariableClient(),
+			wantErr:               true,
+		},
+		{
+			name: "returns error if the template fails to execute",
+			data: "{{ range fromYaml .NODE_POOLS }}{{ end }}",
+			configVariablesClient: test.NewFakeVariableClient().
+				WithVar("NODE_POOLS", "[foo"),
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			p := NewGoTemplateProcessor()
+
+			got, err := p.Process([]byte(tt.data), tt.configVariablesClient.Get)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				if len(tt.missingVariables) != 0 {
+					e, ok := err.(*errMissingVariables)
+					g.Expect(ok).To(BeTrue())
+					g.Expect(e.Missing).To(ConsistOf(tt.missingVariables))
+				}
+				g.Expect(got).To(Equal([]byte(tt.data)))
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(got)).To(Equal(tt.want))
+		})
+	}
+}
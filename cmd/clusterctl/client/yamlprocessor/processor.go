@@ -17,6 +17,42 @@ limitations under the License.
 // Package yamlprocessor implements YAML processing.
 package yamlprocessor
 
+import (
+	"sync"
+
+	"github.com/pkg/errors"
+)
+
+// Names of the yaml processors available in clusterctl.
+const (
+	// SimpleProcessorName is the name of the SimpleProcessor.
+	SimpleProcessorName = "simple"
+
+	// GoTemplateProcessorName is the name of the GoTemplateProcessor.
+	GoTemplateProcessorName = "gotemplate"
+
+	// CUEProcessorName is the name of the processor for templates written in CUE.
+	// NOTE: The CUE processor is not built into clusterctl; it is provided by the
+	// sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor/cueprocessor Go module,
+	// which registers it when imported.
+	CUEProcessorName = "cue"
+)
+
+var (
+	// registeredProcessors are the yaml processors registered by other Go modules.
+	registeredProcessors     = map[string]func() Processor{}
+	registeredProcessorsLock sync.RWMutex
+)
+
+// RegisterProcessor makes a yaml processor implemented outside of this package,
+// e.g. in a separate Go module, available with the given name.
+func RegisterProcessor(name string, factory func() Processor) {
+	registeredProcessorsLock.Lock()
+	defer registeredProcessorsLock.Unlock()
+
+	registeredProcessors[name] = factory
+}
+
 // Processor defines the methods necessary for creating a specific yaml
 // processor.
 type Processor interface {
@@ -40,3 +76,31 @@ type Processor interface {
 	// yaml with values retrieved from the values getter
 	Process([]byte, func(string) (string, error)) ([]byte, error)
 }
+
+// NewProcessor returns the yaml processor with the given name; if name is
+// empty, the simple processor is returned.
+func NewProcessor(name string) (Processor, error) {
+	switch name {
+	case "", SimpleProcessorName:
+		return NewSimpleProcessor(), nil
+	case GoTemplateProcessorName:
+		return NewGoTemplateProcessor(), nil
+	}
+
+	registeredProcessorsLock.RLock()
+	defer registeredProcessorsLock.RUnlock()
+
+	if factory, ok := registeredProcessors[name]; ok {
+		return factory(), nil
+	}
+	if name == CUEProcessorName {
+		return nil, errors.Errorf("template processor %q is not available; it must be enabled by importing the sigs.k8s.io/cluster-api/cmd/clusterctl/client/yamlprocessor/cueprocessor Go package", name)
+	}
+	return nil, errors.Errorf("invalid template processor %q, valid values are %q and %q", name, SimpleProcessorName, GoTemplateProcessorName)
+}
+
+// NewMissingVariablesError returns the error reported by processors when the values for
+// the given required variables are not set.
+func NewMissingVariablesError(missing []string) error {
+	return &errMissingVariables{missing}
+}
@@ -0,0 +1,58 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package yamlprocessor
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestNewProcessor(t *testing.T) {
+	g := NewWithT(t)
+
+	p, err := NewProcessor("")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&SimpleProcessor{}))
+
+	p, err = NewProcessor(GoTemplateProcessorName)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&GoTemplateProcessor{}))
+
+	// The CUE processor is available only when registered.
+	_, err = NewProcessor(CUEProcessorName)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("cueprocessor"))
+
+	_, err = NewProcessor("foo")
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestRegisterProcessor(t *testing.T) {
+	g := NewWithT(t)
+
+	RegisterProcessor("test", func() Processor { return NewGoTemplateProcessor() })
+	defer func() {
+		registeredProcessorsLock.Lock()
+		delete(registeredProcessors, "test")
+		registeredProcessorsLock.Unlock()
+	}()
+
+	p, err := NewProcessor("test")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(p).To(BeAssignableToTypeOf(&GoTemplateProcessor{}))
+}
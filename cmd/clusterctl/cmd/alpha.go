@@ -31,6 +31,7 @@ func init() {
 	// Alpha commands should be added here.
 	alphaCmd.AddCommand(rolloutCmd)
 	alphaCmd.AddCommand(topologyCmd)
+	alphaCmd.AddCommand(operatorCmd)
 
 	RootCmd.AddCommand(alphaCmd)
 }
@@ -0,0 +1,36 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+)
+
+var bundleCmd = &cobra.Command{
+	Use:     "bundle",
+	GroupID: groupManagement,
+	Short:   "Manage bundles for initializing management clusters without network access",
+	Long: LongDesc(`
+		Manage bundles for initializing management clusters without network access.
+
+		A bundle contains the provider components, metadata, cluster templates, the cert-manager manifest
+		and the container images required for running 'clusterctl init --bundle' in an air-gapped environment.`),
+}
+
+func init() {
+	RootCmd.AddCommand(bundleCmd)
+}
@@ -0,0 +1,116 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type bundleCreateOptions struct {
+	coreProvider              string
+	bootstrapProviders        []string
+	controlPlaneProviders     []string
+	infrastructureProviders   []string
+	ipamProviders             []string
+	runtimeExtensionProviders []string
+	addonProviders            []string
+	flavors                   []string
+	output                    string
+	skipImages                bool
+}
+
+var bundleCreateOpts = &bundleCreateOptions{}
+
+var bundleCreateCmd = &cobra.Command{
+	Use:   "create",
+	Short: "Create a bundle for initializing a management cluster without network access",
+	Long: LongDesc(`
+		Create a bundle for initializing a management cluster without network access.
+
+		The bundle includes the components, the metadata and the cluster templates of the selected providers,
+		the cert-manager manifest and all the container images referenced by them, stored in the OCI image layout.
+		Like for 'clusterctl init', the core provider, the kubeadm bootstrap provider and the kubeadm
+		control plane provider are added by default.
+
+		Image overrides in the clusterctl configuration are not applied to the images in the bundle;
+		use 'clusterctl init --bundle-registry' to rewrite the images when using the bundle.`),
+
+	Example: Examples(`
+		# Create a bundle with the default providers and the docker infrastructure provider.
+		clusterctl bundle create --infrastructure docker
+
+		# Create a bundle with specific versions of the providers, including the templates for the given flavors.
+		clusterctl bundle create --core cluster-api:v1.6.0 --infrastructure docker:v1.6.0 --flavor development,clusterclass
+
+		# Create a bundle without container images, e.g. when the images are already available in the target environment.
+		clusterctl bundle create --infrastructure docker --skip-images --output my-bundle.tar.gz`),
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runBundleCreate()
+	},
+}
+
+func init() {
+	bundleCreateCmd.Flags().StringVar(&bundleCreateOpts.coreProvider, "core", "",
+		"Core provider version (e.g. cluster-api:v1.1.5) to add to the bundle. If unspecified, Cluster API's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.infrastructureProviders, "infrastructure", "i", nil,
+		"Infrastructure providers and versions (e.g. aws:v0.5.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.bootstrapProviders, "bootstrap", "b", nil,
+		"Bootstrap providers and versions (e.g. kubeadm:v1.1.5) to add to the bundle. If unspecified, Kubeadm bootstrap provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.controlPlaneProviders, "control-plane", "c", nil,
+		"Control plane providers and versions (e.g. kubeadm:v1.1.5) to add to the bundle. If unspecified, the Kubeadm control plane provider's latest release is used.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.ipamProviders, "ipam", nil,
+		"IPAM providers and versions (e.g. in-cluster:v0.1.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.runtimeExtensionProviders, "runtime-extension", nil,
+		"Runtime extension providers and versions to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVar(&bundleCreateOpts.addonProviders, "addon", nil,
+		"Add-on providers and versions (e.g. helm:v0.1.0) to add to the bundle.")
+	bundleCreateCmd.Flags().StringSliceVarP(&bundleCreateOpts.flavors, "flavor", "f", nil,
+		"Flavors of the cluster templates to add to the bundle for the infrastructure providers, in addition to the default template.")
+	bundleCreateCmd.Flags().StringVarP(&bundleCreateOpts.output, "output", "o", client.DefaultBundleFile,
+		"Path of the bundle to create.")
+	bundleCreateCmd.Flags().BoolVar(&bundleCreateOpts.skipImages, "skip-images", false,
+		"If true, container images are not added to the bundle.")
+
+	bundleCmd.AddCommand(bundleCreateCmd)
+}
+
+func runBundleCreate() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.CreateBundle(ctx, client.CreateBundleOptions{
+		CoreProvider:              bundleCreateOpts.coreProvider,
+		BootstrapProviders:        bundleCreateOpts.bootstrapProviders,
+		ControlPlaneProviders:     bundleCreateOpts.controlPlaneProviders,
+		InfrastructureProviders:   bundleCreateOpts.infrastructureProviders,
+		IPAMProviders:             bundleCreateOpts.ipamProviders,
+		RuntimeExtensionProviders: bundleCreateOpts.runtimeExtensionProviders,
+		AddonProviders:            bundleCreateOpts.addonProviders,
+		Flavors:                   bundleCreateOpts.flavors,
+		Output:                    bundleCreateOpts.output,
+		SkipImages:                bundleCreateOpts.skipImages,
+	})
+}
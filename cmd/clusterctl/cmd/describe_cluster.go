@@ -18,7 +18,9 @@ package cmd
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"sort"
 	"strings"
@@ -32,6 +34,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/util/duration"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
@@ -54,10 +57,24 @@ var (
 	cyan   = color.New(color.FgCyan)
 )
 
+const (
+	// DescribeClusterOutputJSON is an option used to print the object tree in json format.
+	DescribeClusterOutputJSON = "json"
+	// DescribeClusterOutputYaml is an option used to print the object tree in yaml format.
+	DescribeClusterOutputYaml = "yaml"
+)
+
+var (
+	// DescribeClusterOutputs is a list of valid describe cluster outputs in addition to the default text output.
+	DescribeClusterOutputs = []string{DescribeClusterOutputJSON, DescribeClusterOutputYaml}
+)
+
 type describeClusterOptions struct {
 	kubeconfig              string
 	kubeconfigContext       string
 	namespace               string
+	allNamespaces           bool
+	output                  string
 	showOtherConditions     string
 	showMachineSets         bool
 	showClusterResourceSets bool
@@ -66,12 +83,13 @@ type describeClusterOptions struct {
 	grouping                bool
 	disableGrouping         bool
 	color                   bool
+	supportBundle           string
 }
 
 var dc = &describeClusterOptions{}
 
 var describeClusterClusterCmd = &cobra.Command{
-	Use:   "cluster NAME",
+	Use:   "cluster [NAME]",
 	Short: "Describe workload clusters",
 	Long: LongDesc(`
 		Provide an "at glance" view of a Cluster API cluster designed to help the user in quickly
@@ -94,16 +112,39 @@ var describeClusterClusterCmd = &cobra.Command{
 
 		# Describe the cluster named test-1 showing the MachineInfrastructure and BootstrapConfig objects
 		# also when their status is the same as the status of the corresponding machine object.
-		clusterctl describe cluster test-1 --echo`),
+		clusterctl describe cluster test-1 --echo
+
+		# Describe the cluster named test-1 in json format.
+		clusterctl describe cluster test-1 -o json
+
+		# Describe all the clusters in all the namespaces in yaml format.
+		clusterctl describe cluster --all-namespaces -o yaml
+
+		# Collect the diagnostic information for the cluster named test-1 into a support bundle,
+		# with secrets redacted.
+		clusterctl describe cluster test-1 --support-bundle test-1-support-bundle.tar.gz`),
 
 	Args: func(_ *cobra.Command, args []string) error {
+		if dc.allNamespaces {
+			if dc.supportBundle != "" {
+				return errors.New("--support-bundle cannot be used together with --all-namespaces")
+			}
+			if len(args) != 0 {
+				return errors.New("a cluster name cannot be specified together with --all-namespaces")
+			}
+			return nil
+		}
 		if len(args) != 1 {
 			return errors.New("please specify a cluster name")
 		}
 		return nil
 	},
 	RunE: func(cmd *cobra.Command, args []string) error {
-		return runDescribeCluster(cmd, args[0])
+		name := ""
+		if len(args) > 0 {
+			name = args[0]
+		}
+		return runDescribeCluster(cmd, name)
 	},
 }
 
@@ -114,6 +155,10 @@ func init() {
 		"Context to be used within the kubeconfig file. If empty, current context will be used.")
 	describeClusterClusterCmd.Flags().StringVarP(&dc.namespace, "namespace", "n", "",
 		"The namespace where the workload cluster is located. If unspecified, the current namespace will be used.")
+	describeClusterClusterCmd.Flags().BoolVarP(&dc.allNamespaces, "all-namespaces", "A", false,
+		"Describe all the clusters in all the namespaces.")
+	describeClusterClusterCmd.Flags().StringVarP(&dc.output, "output", "o", "",
+		fmt.Sprintf("Output format. Valid values: %v. If unspecified, the object tree is printed as a table.", DescribeClusterOutputs))
 
 	describeClusterClusterCmd.Flags().StringVar(&dc.showOtherConditions, "show-conditions", "",
 		"list of comma separated kind or kind/name for which the command should show all the object's conditions (use 'all' to show conditions for everything).")
@@ -134,6 +179,10 @@ func init() {
 		"use --grouping instead.")
 	describeClusterClusterCmd.Flags().BoolVarP(&dc.color, "color", "c", false, "Enable or disable color output; if not set color is enabled by default only if using tty. The flag is overridden by the NO_COLOR env variable if set.")
 
+	describeClusterClusterCmd.Flags().StringVar(&dc.supportBundle, "support-bundle", "",
+		"Path of a gzipped tarball to be created with the diagnostic information for the cluster, e.g. the Cluster API objects, events, provider logs and the workload cluster's nodes and kube-system pods. Secrets are redacted.")
+	describeClusterClusterCmd.MarkFlagsMutuallyExclusive("support-bundle", "output")
+
 	// completions
 	describeClusterClusterCmd.ValidArgsFunction = resourceNameCompletionFunc(
 		describeClusterClusterCmd.Flags().Lookup("kubeconfig"),
@@ -147,6 +196,10 @@ func init() {
 }
 
 func runDescribeCluster(cmd *cobra.Command, name string) error {
+	if dc.output != "" && dc.output != DescribeClusterOutputJSON && dc.output != DescribeClusterOutputYaml {
+		return errors.Errorf("invalid output format %q, valid values: %v", dc.output, DescribeClusterOutputs)
+	}
+
 	ctx := context.Background()
 
 	c, err := client.New(ctx, cfgFile)
@@ -154,10 +207,24 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		return err
 	}
 
-	tree, err := c.DescribeCluster(ctx, client.DescribeClusterOptions{
+	if dc.supportBundle != "" {
+		if err := c.CreateSupportBundle(ctx, client.SupportBundleOptions{
+			Kubeconfig:  client.Kubeconfig{Path: dc.kubeconfig, Context: dc.kubeconfigContext},
+			Namespace:   dc.namespace,
+			ClusterName: name,
+			Output:      dc.supportBundle,
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("Support bundle for Cluster %q written to %s\n", name, dc.supportBundle)
+		return nil
+	}
+
+	options := client.DescribeClusterOptions{
 		Kubeconfig:              client.Kubeconfig{Path: dc.kubeconfig, Context: dc.kubeconfigContext},
 		Namespace:               dc.namespace,
 		ClusterName:             name,
+		AllNamespaces:           dc.allNamespaces,
 		ShowOtherConditions:     dc.showOtherConditions,
 		ShowClusterResourceSets: dc.showClusterResourceSets,
 		ShowTemplates:           dc.showTemplates,
@@ -165,19 +232,71 @@ func runDescribeCluster(cmd *cobra.Command, name string) error {
 		AddTemplateVirtualNode:  true,
 		Echo:                    dc.echo,
 		Grouping:                dc.grouping && !dc.disableGrouping,
-	})
+	}
+
+	var trees []*tree.ObjectTree
+	if dc.allNamespaces {
+		trees, err = c.DescribeClusters(ctx, options)
+	} else {
+		var objectTree *tree.ObjectTree
+		objectTree, err = c.DescribeCluster(ctx, options)
+		trees = append(trees, objectTree)
+	}
 	if err != nil {
 		return err
 	}
 
+	if dc.output != "" {
+		return printObjectTreesOutput(os.Stdout, dc.output, trees, dc.allNamespaces)
+	}
+
 	if cmd.Flags().Changed("color") {
 		color.NoColor = !dc.color
 	}
 
-	printObjectTree(tree)
+	for i, objectTree := range trees {
+		if i > 0 {
+			fmt.Println()
+		}
+		printObjectTree(objectTree)
+	}
 	return nil
 }
 
+// printObjectTreesOutput prints the object trees in a machine-readable format.
+// A single object tree is printed as a tree.Output document, while a list of object trees,
+// e.g. when describing the clusters in all the namespaces, is printed as a tree.OutputList document.
+func printObjectTreesOutput(w io.Writer, format string, trees []*tree.ObjectTree, list bool) error {
+	var doc interface{}
+	if list {
+		outputList := tree.OutputList{
+			SchemaVersion: tree.OutputSchemaVersion,
+			Items:         []tree.Output{},
+		}
+		for _, objectTree := range trees {
+			outputList.Items = append(outputList.Items, objectTree.Output())
+		}
+		doc = outputList
+	} else {
+		doc = trees[0].Output()
+	}
+
+	var out []byte
+	var err error
+	switch format {
+	case DescribeClusterOutputJSON:
+		out, err = json.MarshalIndent(doc, "", "  ")
+		out = append(out, '\n')
+	case DescribeClusterOutputYaml:
+		out, err = yaml.Marshal(doc)
+	}
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize the object tree")
+	}
+	_, err = w.Write(out)
+	return err
+}
+
 // printObjectTree prints the cluster status to stdout.
 func printObjectTree(tree *tree.ObjectTree) {
 	// Creates the output table
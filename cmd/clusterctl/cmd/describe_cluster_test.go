@@ -18,6 +18,7 @@ package cmd
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"strings"
 	"testing"
@@ -29,6 +30,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/tree"
@@ -296,6 +298,53 @@ func Test_TreePrefix(t *testing.T) {
 
 type objectOption func(object ctrlclient.Object)
 
+func Test_printObjectTreesOutput(t *testing.T) {
+	newTree := func(name string) *tree.ObjectTree {
+		root := fakeObject(name, withCondition(conditions.TrueCondition(clusterv1.ReadyCondition)))
+		objectTree := tree.NewObjectTree(root, tree.ObjectTreeOptions{Grouping: true})
+		objectTree.Add(root, fakeObject("machine"))
+		return objectTree
+	}
+
+	t.Run("json output for a single cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var out bytes.Buffer
+		g.Expect(printObjectTreesOutput(&out, DescribeClusterOutputJSON, []*tree.ObjectTree{newTree("cluster-1")}, false)).To(Succeed())
+
+		got := tree.Output{}
+		g.Expect(json.Unmarshal(out.Bytes(), &got)).To(Succeed())
+		g.Expect(got.SchemaVersion).To(Equal(tree.OutputSchemaVersion))
+		g.Expect(got.Options.Grouping).To(BeTrue())
+		g.Expect(got.Root.Name).To(Equal("cluster-1"))
+		g.Expect(got.Root.Ready.Status).To(Equal("True"))
+		g.Expect(got.Root.Children).To(HaveLen(1))
+		g.Expect(got.Root.Children[0].Name).To(Equal("machine"))
+	})
+
+	t.Run("yaml output for all the clusters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var out bytes.Buffer
+		g.Expect(printObjectTreesOutput(&out, DescribeClusterOutputYaml, []*tree.ObjectTree{newTree("cluster-1"), newTree("cluster-2")}, true)).To(Succeed())
+
+		got := tree.OutputList{}
+		g.Expect(yaml.Unmarshal(out.Bytes(), &got)).To(Succeed())
+		g.Expect(got.SchemaVersion).To(Equal(tree.OutputSchemaVersion))
+		g.Expect(got.Items).To(HaveLen(2))
+		g.Expect(got.Items[0].Root.Name).To(Equal("cluster-1"))
+		g.Expect(got.Items[1].Root.Name).To(Equal("cluster-2"))
+	})
+
+	t.Run("empty list for all the clusters", func(t *testing.T) {
+		g := NewWithT(t)
+
+		var out bytes.Buffer
+		g.Expect(printObjectTreesOutput(&out, DescribeClusterOutputJSON, nil, true)).To(Succeed())
+		g.Expect(out.String()).To(ContainSubstring(`"items": []`))
+	})
+}
+
 func fakeObject(name string, options ...objectOption) ctrlclient.Object {
 	c := &clusterv1.Cluster{ // suing type cluster for simplicity, but this could be any object
 		TypeMeta: metav1.TypeMeta{
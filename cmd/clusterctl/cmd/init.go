@@ -39,6 +39,9 @@ type initOptions struct {
 	validate                  bool
 	waitProviders             bool
 	waitProviderTimeout       int
+	bundle                    string
+	bundleRegistry            string
+	skipVerification          bool
 }
 
 var initOpts = &initOptions{}
@@ -82,7 +85,11 @@ var initCmd = &cobra.Command{
 		clusterctl init --infrastructure=aws,vsphere
 
 		# Initialize a management cluster with a custom target namespace for the provider resources.
-		clusterctl init --infrastructure aws --target-namespace foo`),
+		clusterctl init --infrastructure aws --target-namespace foo
+
+		# Initialize a management cluster without network access, using a bundle created with 'clusterctl bundle create'
+		# and pushing the images in the bundle to a local registry.
+		clusterctl init --infrastructure docker --bundle clusterctl-bundle.tar.gz --bundle-registry localhost:5000`),
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
 		return runInit()
@@ -108,6 +115,12 @@ func init() {
 		"Runtime extension providers and versions to add to the management cluster; please note that clusterctl doesn't include any default runtime extensions and thus it is required to use custom configuration files to register runtime extensions.")
 	initCmd.PersistentFlags().StringSliceVar(&initOpts.addonProviders, "addon", nil,
 		"Add-on providers and versions (e.g. helm:v0.1.0) to add to the management cluster.")
+	initCmd.PersistentFlags().StringVar(&initOpts.bundle, "bundle", "",
+		"Path of a bundle created with 'clusterctl bundle create'; the providers and the cert-manager included in the bundle are read from it instead of from their repositories.")
+	initCmd.PersistentFlags().StringVar(&initOpts.bundleRegistry, "bundle-registry", "",
+		"Container registry (e.g. localhost:5000) where to push the images in the bundle; if set, all the images are rewritten to point to this registry. This value is ignored if --bundle is not set.")
+	initCmd.PersistentFlags().BoolVar(&initOpts.skipVerification, "skip-verification", false,
+		"Skip the verification of the files fetched from provider repositories using the published checksums and signatures.")
 	initCmd.Flags().StringVarP(&initOpts.targetNamespace, "target-namespace", "n", "",
 		"The target namespace where the providers should be deployed. If unspecified, the provider components' default namespace is used.")
 	initCmd.Flags().BoolVar(&initOpts.waitProviders, "wait-providers", false,
@@ -143,6 +156,9 @@ func runInit() error {
 		WaitProviders:             initOpts.waitProviders,
 		WaitProviderTimeout:       time.Duration(initOpts.waitProviderTimeout) * time.Second,
 		IgnoreValidationErrors:    !initOpts.validate,
+		Bundle:                    initOpts.bundle,
+		BundleRegistry:            initOpts.bundleRegistry,
+		SkipVerification:          initOpts.skipVerification,
 	}
 
 	if _, err := c.Init(ctx, options); err != nil {
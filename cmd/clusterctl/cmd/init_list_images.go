@@ -66,6 +66,9 @@ func runInitListImages() error {
 		RuntimeExtensionProviders: initOpts.runtimeExtensionProviders,
 		AddonProviders:            initOpts.addonProviders,
 		LogUsageInstructions:      false,
+		Bundle:                    initOpts.bundle,
+		BundleRegistry:            initOpts.bundleRegistry,
+		SkipVerification:          initOpts.skipVerification,
 	}
 
 	images, err := c.InitImages(ctx, options)
@@ -31,9 +31,14 @@ type moveOptions struct {
 	toKubeconfig          string
 	toKubeconfigContext   string
 	namespace             string
+	clusters              []string
+	selector              string
+	copySharedObjects     bool
 	fromDirectory         string
 	toDirectory           string
 	dryRun                bool
+	resume                bool
+	abort                 bool
 }
 
 var mo = &moveOptions{}
@@ -51,6 +56,23 @@ var moveCmd = &cobra.Command{
 		Move Cluster API objects and all dependencies between management clusters.
 		clusterctl move --to-kubeconfig=target-kubeconfig.yaml
 
+		Move only the Cluster named my-cluster and all its dependencies between management clusters.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --cluster my-cluster
+
+		Move only the Clusters with the label env=dev and all their dependencies between management clusters,
+		copying the objects in use also by Clusters not being moved, e.g. ClusterClasses.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --selector env=dev --copy-shared-objects
+
+		Resume a move operation which did not complete, e.g. because of an error.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --resume
+
+		Resume a move operation for the Cluster named my-cluster which did not complete.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --cluster my-cluster --resume
+
+		Abort a move operation which did not complete, deleting the objects already created in the target
+		management cluster and resuming the Clusters in the source management cluster.
+		clusterctl move --to-kubeconfig=target-kubeconfig.yaml --abort
+
 		Write Cluster API objects and all dependencies from a management cluster to directory.
 		clusterctl move --to-directory /tmp/backup-directory
 
@@ -74,8 +96,18 @@ func init() {
 		"Context to be used within the kubeconfig file for the destination management cluster. If empty, current context will be used.")
 	moveCmd.Flags().StringVarP(&mo.namespace, "namespace", "n", "",
 		"The namespace where the workload cluster is hosted. If unspecified, the current context's namespace is used.")
+	moveCmd.Flags().StringSliceVar(&mo.clusters, "cluster", nil,
+		"The name of a Cluster to be moved, together with all its dependencies; can be repeated. If unspecified, all the Clusters in the namespace are moved.")
+	moveCmd.Flags().StringVarP(&mo.selector, "selector", "l", "",
+		"Label selector for the Clusters to be moved, together with all their dependencies. If unspecified, all the Clusters in the namespace are moved.")
+	moveCmd.Flags().BoolVar(&mo.copySharedObjects, "copy-shared-objects", false,
+		"When moving a subset of the Clusters, copy the objects in use also by Clusters not being moved (e.g. ClusterClasses) instead of refusing the move.")
 	moveCmd.Flags().BoolVar(&mo.dryRun, "dry-run", false,
 		"Enable dry run, don't really perform the move actions")
+	moveCmd.Flags().BoolVar(&mo.resume, "resume", false,
+		"Resume a previous move operation which did not complete, continuing from the last completed step. The --namespace, --cluster and --selector flags must be the same used for the move operation.")
+	moveCmd.Flags().BoolVar(&mo.abort, "abort", false,
+		"Abort a previous move operation which did not complete, deleting the objects already created in the target management cluster and resuming the Clusters in the source management cluster. The --namespace, --cluster and --selector flags must be the same used for the move operation.")
 	moveCmd.Flags().StringVar(&mo.toDirectory, "to-directory", "",
 		"Write Cluster API objects and all dependencies from a management cluster to directory.")
 	moveCmd.Flags().StringVar(&mo.fromDirectory, "from-directory", "",
@@ -84,6 +116,9 @@ func init() {
 	moveCmd.MarkFlagsMutuallyExclusive("to-directory", "to-kubeconfig")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "to-directory")
 	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "kubeconfig")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "cluster")
+	moveCmd.MarkFlagsMutuallyExclusive("from-directory", "selector")
+	moveCmd.MarkFlagsMutuallyExclusive("resume", "abort", "dry-run", "to-directory", "from-directory")
 
 	RootCmd.AddCommand(moveCmd)
 }
@@ -104,11 +139,16 @@ func runMove() error {
 	}
 
 	return c.Move(ctx, client.MoveOptions{
-		FromKubeconfig: client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
-		ToKubeconfig:   client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
-		FromDirectory:  mo.fromDirectory,
-		ToDirectory:    mo.toDirectory,
-		Namespace:      mo.namespace,
-		DryRun:         mo.dryRun,
+		FromKubeconfig:    client.Kubeconfig{Path: mo.fromKubeconfig, Context: mo.fromKubeconfigContext},
+		ToKubeconfig:      client.Kubeconfig{Path: mo.toKubeconfig, Context: mo.toKubeconfigContext},
+		FromDirectory:     mo.fromDirectory,
+		ToDirectory:       mo.toDirectory,
+		Namespace:         mo.namespace,
+		ClusterNames:      mo.clusters,
+		ClusterSelector:   mo.selector,
+		CopySharedObjects: mo.copySharedObjects,
+		DryRun:            mo.dryRun,
+		Resume:            mo.resume,
+		Abort:             mo.abort,
 	})
 }
@@ -0,0 +1,177 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/healthz"
+	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
+
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/controllers"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	logf "sigs.k8s.io/cluster-api/cmd/clusterctl/log"
+)
+
+type operatorOptions struct {
+	kubeconfig              string
+	kubeconfigContext       string
+	namespace               string
+	metricsBindAddr         string
+	healthAddr              string
+	leaderElect             bool
+	leaderElectionNamespace string
+	waitProviderTimeout     int
+	watchFilterValue        string
+}
+
+var oo = &operatorOptions{}
+
+var operatorCmd = &cobra.Command{
+	Use:   "operator",
+	Short: "Run clusterctl as an operator managing the providers declared by ManagedProvider objects",
+	Long: LongDesc(`
+		The operator command runs a controller that installs, upgrades and deletes the providers in the
+		management cluster according to ManagedProvider objects, using the same logic of the init, upgrade
+		and delete commands; this allows to manage the providers declaratively, e.g. using GitOps.
+
+		The operator is usually run in the management cluster; in this case, it connects to the management
+		cluster using the in-cluster configuration. Each ManagedProvider defines the provider name, type,
+		version, variables and image overrides used for processing the provider's components.`),
+
+	Example: Examples(`
+		# Runs the operator in the management cluster.
+		clusterctl alpha operator
+
+		# Runs the operator against the management cluster defined in a kubeconfig file, only
+		# reconciling ManagedProviders in the capi-operator namespace.
+		clusterctl alpha operator --kubeconfig=kubeconfig.yaml --namespace=capi-operator --leader-elect=false`),
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runOperator()
+	},
+}
+
+func init() {
+	operatorCmd.Flags().StringVar(&oo.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, the in-cluster configuration or the default discovery rules apply.")
+	operatorCmd.Flags().StringVar(&oo.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	operatorCmd.Flags().StringVar(&oo.namespace, "namespace", "",
+		"Namespace that the operator watches for ManagedProvider objects. If empty, all namespaces are watched.")
+	operatorCmd.Flags().StringVar(&oo.metricsBindAddr, "metrics-bind-addr", "localhost:8080",
+		"The address the metrics endpoint binds to.")
+	operatorCmd.Flags().StringVar(&oo.healthAddr, "health-addr", ":9440",
+		"The address the health endpoint binds to.")
+	operatorCmd.Flags().BoolVar(&oo.leaderElect, "leader-elect", true,
+		"Enable leader election for the operator. Enabling this will ensure there is only one active operator.")
+	operatorCmd.Flags().StringVar(&oo.leaderElectionNamespace, "leader-election-namespace", "",
+		"Namespace where the leader election lease is created. If empty, the namespace the operator is running in is used.")
+	operatorCmd.Flags().IntVar(&oo.waitProviderTimeout, "wait-provider-timeout", 5*60,
+		"Wait timeout per provider installation or upgrade in seconds.")
+	operatorCmd.Flags().StringVar(&oo.watchFilterValue, "watch-filter", "",
+		"Label value that the operator watches to filter ManagedProviders to reconcile.")
+}
+
+func runOperator() error {
+	ctx := ctrl.SetupSignalHandler()
+
+	ctrl.SetLogger(logf.Log)
+
+	restConfig, err := operatorRESTConfig()
+	if err != nil {
+		return err
+	}
+
+	// Ensure the custom resource definitions required by clusterctl, including the ManagedProvider CRD, are in place.
+	configClient, err := config.New(ctx, "", config.InjectReader(config.NewMemoryReader()))
+	if err != nil {
+		return err
+	}
+	proxy := cluster.NewProxy(cluster.Kubeconfig{}, cluster.InjectRESTConfig(restConfig))
+	inventory := cluster.New(cluster.Kubeconfig{}, configClient, cluster.InjectProxy(proxy)).ProviderInventory()
+	if err := inventory.EnsureCustomResourceDefinitions(ctx); err != nil {
+		return err
+	}
+	if err := inventory.EnsureManagedProviderCustomResourceDefinition(ctx); err != nil {
+		return err
+	}
+
+	cacheOptions := cache.Options{}
+	if oo.namespace != "" {
+		cacheOptions.ByObject = map[client.Object]cache.ByObject{
+			&clusterctlv1.ManagedProvider{}: {Namespaces: map[string]cache.Config{oo.namespace: {}}},
+			&corev1.Secret{}:                {Namespaces: map[string]cache.Config{oo.namespace: {}}},
+		}
+	}
+
+	mgr, err := ctrl.NewManager(restConfig, ctrl.Options{
+		Scheme:                  scheme.Scheme,
+		Metrics:                 metricsserver.Options{BindAddress: oo.metricsBindAddr},
+		HealthProbeBindAddress:  oo.healthAddr,
+		LeaderElection:          oo.leaderElect,
+		LeaderElectionID:        "clusterctl-operator-leader-election",
+		LeaderElectionNamespace: oo.leaderElectionNamespace,
+		Cache:                   cacheOptions,
+	})
+	if err != nil {
+		return errors.Wrap(err, "failed to create the operator manager")
+	}
+
+	if err := mgr.AddHealthzCheck("healthz", healthz.Ping); err != nil {
+		return errors.Wrap(err, "failed to set up health check")
+	}
+	if err := mgr.AddReadyzCheck("readyz", healthz.Ping); err != nil {
+		return errors.Wrap(err, "failed to set up ready check")
+	}
+
+	if err := (&controllers.ManagedProviderReconciler{
+		Client:              mgr.GetClient(),
+		RESTConfig:          restConfig,
+		WaitProviderTimeout: time.Duration(oo.waitProviderTimeout) * time.Second,
+		WatchFilterValue:    oo.watchFilterValue,
+	}).SetupWithManager(ctx, mgr, controller.Options{}); err != nil {
+		return err
+	}
+
+	return mgr.Start(ctx)
+}
+
+// operatorRESTConfig returns the rest config for the management cluster; when no kubeconfig is specified,
+// the in-cluster configuration or the default discovery rules apply.
+func operatorRESTConfig() (*rest.Config, error) {
+	if oo.kubeconfig != "" || oo.kubeconfigContext != "" {
+		return cluster.NewProxy(cluster.Kubeconfig{Path: oo.kubeconfig, Context: oo.kubeconfigContext}).GetConfig()
+	}
+
+	restConfig, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get the configuration for the management cluster")
+	}
+	return restConfig, nil
+}
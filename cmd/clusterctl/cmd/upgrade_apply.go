@@ -39,6 +39,8 @@ type upgradeApplyOptions struct {
 	addonProviders            []string
 	waitProviders             bool
 	waitProviderTimeout       int
+	rollbackOnFailure         bool
+	skipVerification          bool
 }
 
 var ua = &upgradeApplyOptions{}
@@ -60,7 +62,10 @@ var upgradeApplyCmd = &cobra.Command{
 		clusterctl upgrade apply --contract v1alpha4
 
 		# Upgrades only the aws provider to the v2.0.1 version.
-		clusterctl upgrade apply --infrastructure aws:v2.0.1`),
+		clusterctl upgrade apply --infrastructure aws:v2.0.1
+
+		# Upgrades only the aws provider to the v2.0.1 version, rolling back if the new version does not work.
+		clusterctl upgrade apply --infrastructure aws:v2.0.1 --rollback-on-failure`),
 	Args: cobra.NoArgs,
 	RunE: func(*cobra.Command, []string) error {
 		return runUpgradeApply()
@@ -92,7 +97,11 @@ func init() {
 	upgradeApplyCmd.Flags().BoolVar(&ua.waitProviders, "wait-providers", false,
 		"Wait for providers to be upgraded.")
 	upgradeApplyCmd.Flags().IntVar(&ua.waitProviderTimeout, "wait-provider-timeout", 5*60,
-		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers is false")
+		"Wait timeout per provider upgrade in seconds. This value is ignored if --wait-providers and --rollback-on-failure are false")
+	upgradeApplyCmd.Flags().BoolVar(&ua.rollbackOnFailure, "rollback-on-failure", false,
+		"Wait for the upgraded providers to be available and for the conversion of their CRDs to work, and roll back the providers to the previous version if not.")
+	upgradeApplyCmd.Flags().BoolVar(&ua.skipVerification, "skip-verification", false,
+		"Skip the verification of the files fetched from provider repositories using the published checksums and signatures.")
 }
 
 func runUpgradeApply() error {
@@ -130,5 +139,7 @@ func runUpgradeApply() error {
 		AddonProviders:            ua.addonProviders,
 		WaitProviders:             ua.waitProviders,
 		WaitProviderTimeout:       time.Duration(ua.waitProviderTimeout) * time.Second,
+		RollbackOnFailure:         ua.rollbackOnFailure,
+		SkipVerification:          ua.skipVerification,
 	})
 }
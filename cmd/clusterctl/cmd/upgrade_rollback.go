@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cmd
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+)
+
+type upgradeRollbackOptions struct {
+	kubeconfig          string
+	kubeconfigContext   string
+	waitProviders       bool
+	waitProviderTimeout int
+}
+
+var ur = &upgradeRollbackOptions{}
+
+var upgradeRollbackCmd = &cobra.Command{
+	Use:   "rollback",
+	Short: "Roll back Cluster API core and providers in a management cluster to the version before the last upgrade",
+	Long: LongDesc(`
+		The upgrade rollback command rolls back the providers changed by the last clusterctl upgrade apply
+		to the version they had before the upgrade.
+
+		Rolling back is only possible if the previous versions support the API Version of Cluster API (contract)
+		of the management cluster.`),
+
+	Example: Examples(`
+		# Rolls back the providers changed by the last upgrade.
+		clusterctl upgrade rollback`),
+	Args: cobra.NoArgs,
+	RunE: func(*cobra.Command, []string) error {
+		return runUpgradeRollback()
+	},
+}
+
+func init() {
+	upgradeRollbackCmd.Flags().StringVar(&ur.kubeconfig, "kubeconfig", "",
+		"Path to the kubeconfig file to use for accessing the management cluster. If unspecified, default discovery rules apply.")
+	upgradeRollbackCmd.Flags().StringVar(&ur.kubeconfigContext, "kubeconfig-context", "",
+		"Context to be used within the kubeconfig file. If empty, current context will be used.")
+	upgradeRollbackCmd.Flags().BoolVar(&ur.waitProviders, "wait-providers", false,
+		"Wait for providers to be rolled back.")
+	upgradeRollbackCmd.Flags().IntVar(&ur.waitProviderTimeout, "wait-provider-timeout", 5*60,
+		"Wait timeout per provider rollback in seconds. This value is ignored if --wait-providers is false")
+}
+
+func runUpgradeRollback() error {
+	ctx := context.Background()
+
+	c, err := client.New(ctx, cfgFile)
+	if err != nil {
+		return err
+	}
+
+	return c.RollbackUpgrade(ctx, client.RollbackUpgradeOptions{
+		Kubeconfig:          client.Kubeconfig{Path: ur.kubeconfig, Context: ur.kubeconfigContext},
+		WaitProviders:       ur.waitProviders,
+		WaitProviderTimeout: time.Duration(ur.waitProviderTimeout) * time.Second,
+	})
+}
@@ -0,0 +1,522 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/labels"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+const (
+	defaultWaitProviderTimeout = 5 * time.Minute
+)
+
+// ClientFactory creates the clusterctl clients used for reconciling a ManagedProvider; the clients must
+// read the clusterctl configuration, e.g. providers, variables and image overrides, from the given reader.
+type ClientFactory func(ctx context.Context, reader config.Reader) (clusterctlclient.Client, cluster.Client, error)
+
+// ManagedProviderReconciler reconciles a ManagedProvider object by installing, upgrading and deleting
+// the corresponding provider using the same library used by the clusterctl init, upgrade and delete commands.
+type ManagedProviderReconciler struct {
+	Client client.Client
+
+	// RESTConfig is used by clusterctl for connecting to the management cluster.
+	RESTConfig *rest.Config
+
+	// WaitProviderTimeout is the time to wait for a provider to be available after install or upgrade.
+	// Defaults to 5 minutes.
+	WaitProviderTimeout time.Duration
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	// ClientFactory allows to override how clusterctl clients are created; if nil, clients connecting
+	// to the management cluster with RESTConfig are used.
+	ClientFactory ClientFactory
+}
+
+func (r *ManagedProviderReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	if r.ClientFactory == nil {
+		if r.RESTConfig == nil {
+			return errors.New("either RESTConfig or ClientFactory must be set")
+		}
+		r.ClientFactory = r.defaultClientFactory
+	}
+
+	// clusterctl operations on a management cluster must not run concurrently.
+	options.MaxConcurrentReconciles = 1
+
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&clusterctlv1.ManagedProvider{}).
+		// Changes to the provider inventory could be relevant for any ManagedProvider, e.g. when the
+		// core provider is installed or when providers are changed using the clusterctl CLI.
+		Watches(
+			&clusterctlv1.Provider{},
+			handler.EnqueueRequestsFromMapFunc(r.providerToManagedProviders),
+		).
+		// Changes to the variables Secret require the provider's components to be applied again.
+		Watches(
+			&corev1.Secret{},
+			handler.EnqueueRequestsFromMapFunc(r.secretToManagedProviders),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPaused(ctrl.LoggerFrom(ctx))).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+	return nil
+}
+
+func (r *ManagedProviderReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	managedProvider := &clusterctlv1.ManagedProvider{}
+	if err := r.Client.Get(ctx, req.NamespacedName, managedProvider); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Return early if the ManagedProvider is paused.
+	if annotations.HasPaused(managedProvider) {
+		log.Info("Reconciliation is paused for this object")
+		return ctrl.Result{}, nil
+	}
+
+	// Return early if the ManagedProvider does not have the watch filter label; this is checked here because
+	// ManagedProviders are also reconciled in response to changes to the provider inventory and to Secrets.
+	if r.WatchFilterValue != "" && !labels.HasWatchLabel(managedProvider, r.WatchFilterValue) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(managedProvider, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		conditions.SetSummary(managedProvider, conditions.WithConditions(clusterctlv1.ProviderInstalledCondition))
+
+		patchOpts := []patch.Option{
+			patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+				clusterv1.ReadyCondition,
+				clusterctlv1.ProviderInstalledCondition,
+			}},
+			patch.WithStatusObservedGeneration{},
+		}
+		if err := patchHelper.Patch(ctx, managedProvider, patchOpts...); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Handle deletion reconciliation loop.
+	if !managedProvider.ObjectMeta.DeletionTimestamp.IsZero() {
+		return r.reconcileDelete(ctx, managedProvider)
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
+	if !controllerutil.ContainsFinalizer(managedProvider, clusterctlv1.ManagedProviderFinalizer) {
+		controllerutil.AddFinalizer(managedProvider, clusterctlv1.ManagedProviderFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	return r.reconcileNormal(ctx, managedProvider)
+}
+
+func (r *ManagedProviderReconciler) reconcileNormal(ctx context.Context, managedProvider *clusterctlv1.ManagedProvider) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	providerType := managedProvider.GetProviderType()
+	if providerType == clusterctlv1.ProviderTypeUnknown {
+		conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.InvalidSpecReason, clusterv1.ConditionSeverityError, "Provider type %q is not supported", managedProvider.Spec.Type)
+		return ctrl.Result{}, nil
+	}
+
+	configuration, err := r.configuration(ctx, managedProvider)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	configurationHash, err := configuration.hash()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	clusterctlClient, clusterClient, err := r.clients(ctx, managedProvider, configuration)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	providerList, err := clusterClient.ProviderInventory().List(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	installed, err := installedProvider(providerList, managedProvider)
+	if err != nil {
+		conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.InvalidSpecReason, clusterv1.ConditionSeverityError, err.Error())
+		return ctrl.Result{}, nil
+	}
+
+	switch {
+	case installed == nil:
+		// Providers other than the core provider can only be installed after the core provider;
+		// the ManagedProvider will be reconciled again when the core provider is added to the inventory.
+		if providerType != clusterctlv1.CoreProviderType && len(providerList.FilterCore()) == 0 {
+			log.Info("Waiting for the core provider to be installed")
+			conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.WaitingForCoreProviderReason, clusterv1.ConditionSeverityInfo, "")
+			return ctrl.Result{}, nil
+		}
+
+		log.Info("Installing provider", "Provider", managedProvider.Spec.ProviderName, "Type", providerType, "Version", managedProvider.Spec.Version)
+		if _, err := clusterctlClient.Init(ctx, r.initOptions(managedProvider)); err != nil {
+			conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.InstallFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, errors.Wrapf(err, "failed to install provider %s", managedProvider.Spec.ProviderName)
+		}
+	case managedProvider.Spec.Version != "" && managedProvider.Spec.Version != installed.Version:
+		log.Info("Upgrading provider", "Provider", installed.InstanceName(), "Version", installed.Version, "NextVersion", managedProvider.Spec.Version)
+		if err := clusterctlClient.ApplyUpgrade(ctx, r.applyUpgradeOptions(managedProvider, installed, managedProvider.Spec.Version)); err != nil {
+			conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.UpgradeFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, errors.Wrapf(err, "failed to upgrade provider %s", installed.InstanceName())
+		}
+	case managedProvider.Status.ConfigurationHash != "" && managedProvider.Status.ConfigurationHash != configurationHash:
+		// Apply the components of the installed version again, processing them with the new configuration.
+		// NOTE: Providers installed before the ManagedProvider was created do not have a configuration hash yet; in this
+		// case we don't know the configuration used for processing the components, so they are not applied again.
+		log.Info("Applying configuration changes to provider", "Provider", installed.InstanceName(), "Version", installed.Version)
+		if err := clusterctlClient.ApplyUpgrade(ctx, r.applyUpgradeOptions(managedProvider, installed, installed.Version)); err != nil {
+			conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.ConfigurationUpdateFailedReason, clusterv1.ConditionSeverityError, err.Error())
+			return ctrl.Result{}, errors.Wrapf(err, "failed to apply configuration changes to provider %s", installed.InstanceName())
+		}
+	}
+
+	// Read the provider inventory again, so the status reflects what is actually installed.
+	providerList, err = clusterClient.ProviderInventory().List(ctx)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	installed, err = installedProvider(providerList, managedProvider)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if installed == nil {
+		return ctrl.Result{}, errors.Errorf("provider %s not found in the provider inventory", managedProvider.Spec.ProviderName)
+	}
+
+	managedProvider.Status.InstalledVersion = installed.Version
+	managedProvider.Status.InstalledNamespace = installed.Namespace
+	managedProvider.Status.ConfigurationHash = configurationHash
+	conditions.MarkTrue(managedProvider, clusterctlv1.ProviderInstalledCondition)
+	return ctrl.Result{}, nil
+}
+
+func (r *ManagedProviderReconciler) reconcileDelete(ctx context.Context, managedProvider *clusterctlv1.ManagedProvider) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !controllerutil.ContainsFinalizer(managedProvider, clusterctlv1.ManagedProviderFinalizer) {
+		return ctrl.Result{}, nil
+	}
+
+	if managedProvider.GetProviderType() != clusterctlv1.ProviderTypeUnknown {
+		configuration, err := r.configuration(ctx, managedProvider)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		_, clusterClient, err := r.clients(ctx, managedProvider, configuration)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		providerList, err := clusterClient.ProviderInventory().List(ctx)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		installed, err := installedProvider(providerList, managedProvider)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+
+		// Delete the provider components, preserving the namespace and the CRDs like clusterctl delete does by default.
+		if installed != nil {
+			log.Info("Deleting provider", "Provider", installed.InstanceName())
+			conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+			if err := clusterClient.ProviderComponents().Delete(ctx, cluster.DeleteOptions{Provider: *installed}); err != nil {
+				conditions.MarkFalse(managedProvider, clusterctlv1.ProviderInstalledCondition, clusterctlv1.DeleteFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return ctrl.Result{}, errors.Wrapf(err, "failed to delete provider %s", installed.InstanceName())
+			}
+		}
+	}
+
+	controllerutil.RemoveFinalizer(managedProvider, clusterctlv1.ManagedProviderFinalizer)
+	return ctrl.Result{}, nil
+}
+
+// managedProviderConfiguration is the configuration used for processing the components of a ManagedProvider.
+type managedProviderConfiguration struct {
+	FetchURL       string
+	Variables      map[string]string
+	ImageOverrides []clusterctlv1.ImageOverride
+}
+
+// hash returns the hash of the configuration, so changes can be detected without storing variables in the status.
+func (c *managedProviderConfiguration) hash() (string, error) {
+	h, err := hash.Compute(c)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute the configuration hash")
+	}
+	return fmt.Sprintf("%d", h), nil
+}
+
+// configuration returns the configuration used for processing the components of a ManagedProvider, reading
+// the variables from the ManagedProvider spec and from the variables Secret, if any.
+func (r *ManagedProviderReconciler) configuration(ctx context.Context, managedProvider *clusterctlv1.ManagedProvider) (*managedProviderConfiguration, error) {
+	configuration := &managedProviderConfiguration{
+		FetchURL:       managedProvider.Spec.FetchURL,
+		Variables:      map[string]string{},
+		ImageOverrides: managedProvider.Spec.ImageOverrides,
+	}
+
+	for k, v := range managedProvider.Spec.Variables {
+		configuration.Variables[k] = v
+	}
+
+	if managedProvider.Spec.VariablesSecretName != "" {
+		secret := &corev1.Secret{}
+		key := client.ObjectKey{Namespace: managedProvider.Namespace, Name: managedProvider.Spec.VariablesSecretName}
+		if err := r.Client.Get(ctx, key, secret); err != nil {
+			return nil, errors.Wrapf(err, "failed to get variables Secret %s", key)
+		}
+		for k, v := range secret.Data {
+			configuration.Variables[k] = string(v)
+		}
+	}
+	return configuration, nil
+}
+
+// clients returns the clusterctl clients to be used for reconciling a ManagedProvider; the clusterctl configuration
+// is built from the given ManagedProvider configuration.
+func (r *ManagedProviderReconciler) clients(ctx context.Context, managedProvider *clusterctlv1.ManagedProvider, configuration *managedProviderConfiguration) (clusterctlclient.Client, cluster.Client, error) {
+	reader := config.NewMemoryReader()
+	if configuration.FetchURL != "" {
+		if _, err := reader.AddProvider(managedProvider.Spec.ProviderName, managedProvider.GetProviderType(), configuration.FetchURL); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	for k, v := range configuration.Variables {
+		reader.Set(k, v)
+	}
+
+	for _, o := range configuration.ImageOverrides {
+		if _, err := reader.AddImageOverride(o.Component, o.Repository, o.Tag); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	if err := reader.Init(ctx, ""); err != nil {
+		return nil, nil, err
+	}
+
+	return r.ClientFactory(ctx, reader)
+}
+
+// defaultClientFactory returns clusterctl clients connecting to the management cluster using RESTConfig.
+func (r *ManagedProviderReconciler) defaultClientFactory(ctx context.Context, reader config.Reader) (clusterctlclient.Client, cluster.Client, error) {
+	configClient, err := config.New(ctx, "", config.InjectReader(reader))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	proxy := cluster.NewProxy(cluster.Kubeconfig{}, cluster.InjectRESTConfig(r.RESTConfig))
+	clusterClient := cluster.New(cluster.Kubeconfig{}, configClient, cluster.InjectProxy(proxy))
+
+	c, err := clusterctlclient.New(ctx, "",
+		clusterctlclient.InjectConfig(configClient),
+		clusterctlclient.InjectClusterClientFactory(func(input clusterctlclient.ClusterClientFactoryInput) (cluster.Client, error) {
+			return cluster.New(cluster.Kubeconfig{}, configClient, cluster.InjectProxy(proxy), cluster.InjectYamlProcessor(input.Processor)), nil
+		}),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+	return c, clusterClient, nil
+}
+
+func (r *ManagedProviderReconciler) initOptions(managedProvider *clusterctlv1.ManagedProvider) clusterctlclient.InitOptions {
+	ref := managedProvider.Spec.ProviderName
+	if managedProvider.Spec.Version != "" {
+		ref = fmt.Sprintf("%s:%s", ref, managedProvider.Spec.Version)
+	}
+
+	refs := newProviderRefs(managedProvider.GetProviderType(), ref)
+	options := clusterctlclient.InitOptions{
+		CoreProvider:              refs.core,
+		BootstrapProviders:        refs.bootstrap,
+		ControlPlaneProviders:     refs.controlPlane,
+		InfrastructureProviders:   refs.infrastructure,
+		IPAMProviders:             refs.ipam,
+		RuntimeExtensionProviders: refs.runtimeExtension,
+		AddonProviders:            refs.addon,
+		TargetNamespace:           managedProvider.Spec.TargetNamespace,
+		WaitProviders:             true,
+		WaitProviderTimeout:       r.waitProviderTimeout(),
+	}
+
+	// Each provider is declared by its own ManagedProvider, so opt-out from the kubeadm bootstrap and
+	// control plane providers being installed together with the core provider.
+	if managedProvider.GetProviderType() == clusterctlv1.CoreProviderType {
+		options.BootstrapProviders = []string{clusterctlclient.NoopProvider}
+		options.ControlPlaneProviders = []string{clusterctlclient.NoopProvider}
+	}
+	return options
+}
+
+func (r *ManagedProviderReconciler) applyUpgradeOptions(managedProvider *clusterctlv1.ManagedProvider, installed *clusterctlv1.Provider, version string) clusterctlclient.ApplyUpgradeOptions {
+	ref := fmt.Sprintf("%s/%s:%s", installed.Namespace, installed.ProviderName, version)
+
+	refs := newProviderRefs(managedProvider.GetProviderType(), ref)
+	return clusterctlclient.ApplyUpgradeOptions{
+		CoreProvider:              refs.core,
+		BootstrapProviders:        refs.bootstrap,
+		ControlPlaneProviders:     refs.controlPlane,
+		InfrastructureProviders:   refs.infrastructure,
+		IPAMProviders:             refs.ipam,
+		RuntimeExtensionProviders: refs.runtimeExtension,
+		AddonProviders:            refs.addon,
+		WaitProviders:             true,
+		WaitProviderTimeout:       r.waitProviderTimeout(),
+		RollbackOnFailure:         true,
+	}
+}
+
+func (r *ManagedProviderReconciler) waitProviderTimeout() time.Duration {
+	if r.WaitProviderTimeout == 0 {
+		return defaultWaitProviderTimeout
+	}
+	return r.WaitProviderTimeout
+}
+
+// secretToManagedProviders returns the ManagedProviders using a Secret as variables Secret.
+func (r *ManagedProviderReconciler) secretToManagedProviders(ctx context.Context, o client.Object) []reconcile.Request {
+	managedProviders := &clusterctlv1.ManagedProviderList{}
+	if err := r.Client.List(ctx, managedProviders, client.InNamespace(o.GetNamespace())); err != nil {
+		return nil
+	}
+
+	requests := []reconcile.Request{}
+	for i := range managedProviders.Items {
+		if managedProviders.Items[i].Spec.VariablesSecretName == o.GetName() {
+			requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&managedProviders.Items[i])})
+		}
+	}
+	return requests
+}
+
+// providerToManagedProviders returns all the ManagedProviders in the cluster.
+func (r *ManagedProviderReconciler) providerToManagedProviders(ctx context.Context, _ client.Object) []reconcile.Request {
+	managedProviders := &clusterctlv1.ManagedProviderList{}
+	if err := r.Client.List(ctx, managedProviders); err != nil {
+		return nil
+	}
+
+	requests := make([]reconcile.Request, 0, len(managedProviders.Items))
+	for i := range managedProviders.Items {
+		requests = append(requests, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&managedProviders.Items[i])})
+	}
+	return requests
+}
+
+// installedProvider returns the entry of the provider inventory matching a ManagedProvider, if any.
+func installedProvider(providerList *clusterctlv1.ProviderList, managedProvider *clusterctlv1.ManagedProvider) (*clusterctlv1.Provider, error) {
+	var providers []clusterctlv1.Provider
+	for _, p := range providerList.FilterByProviderNameAndType(managedProvider.Spec.ProviderName, managedProvider.GetProviderType()) {
+		if managedProvider.Spec.TargetNamespace != "" && p.Namespace != managedProvider.Spec.TargetNamespace {
+			continue
+		}
+		providers = append(providers, p)
+	}
+
+	switch len(providers) {
+	case 0:
+		return nil, nil
+	case 1:
+		return &providers[0], nil
+	default:
+		return nil, errors.Errorf("provider %s is installed in more than one namespace, targetNamespace must be set", managedProvider.Spec.ProviderName)
+	}
+}
+
+// providerRefs holds a provider reference in the field matching the provider type, so it can be
+// copied to clusterctl options.
+type providerRefs struct {
+	core             string
+	bootstrap        []string
+	controlPlane     []string
+	infrastructure   []string
+	ipam             []string
+	runtimeExtension []string
+	addon            []string
+}
+
+func newProviderRefs(providerType clusterctlv1.ProviderType, ref string) providerRefs {
+	refs := providerRefs{}
+	switch providerType {
+	case clusterctlv1.CoreProviderType:
+		refs.core = ref
+	case clusterctlv1.BootstrapProviderType:
+		refs.bootstrap = []string{ref}
+	case clusterctlv1.ControlPlaneProviderType:
+		refs.controlPlane = []string{ref}
+	case clusterctlv1.InfrastructureProviderType:
+		refs.infrastructure = []string{ref}
+	case clusterctlv1.IPAMProviderType:
+		refs.ipam = []string{ref}
+	case clusterctlv1.RuntimeExtensionProviderType:
+		refs.runtimeExtension = []string{ref}
+	case clusterctlv1.AddonProviderType:
+		refs.addon = []string{ref}
+	}
+	return refs
+}
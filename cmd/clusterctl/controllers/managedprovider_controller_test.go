@@ -0,0 +1,416 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	clusterctlv1 "sigs.k8s.io/cluster-api/cmd/clusterctl/api/v1alpha3"
+	clusterctlclient "sigs.k8s.io/cluster-api/cmd/clusterctl/client"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/cluster"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/client/config"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/scheme"
+	"sigs.k8s.io/cluster-api/cmd/clusterctl/internal/test"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestManagedProviderReconciler_Reconcile(t *testing.T) {
+	tests := []struct {
+		name                string
+		managedProvider     *clusterctlv1.ManagedProvider
+		proxy               *test.FakeProxy
+		wantInit            []clusterctlclient.InitOptions
+		wantUpgrade         []clusterctlclient.ApplyUpgradeOptions
+		wantInstalled       string
+		wantConditionReason string
+	}{
+		{
+			name:            "install the core provider without the default bootstrap and control plane providers",
+			managedProvider: newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0"),
+			proxy:           test.NewFakeProxy(),
+			wantInit: []clusterctlclient.InitOptions{{
+				CoreProvider:          "cluster-api:v1.0.0",
+				BootstrapProviders:    []string{clusterctlclient.NoopProvider},
+				ControlPlaneProviders: []string{clusterctlclient.NoopProvider},
+				WaitProviders:         true,
+				WaitProviderTimeout:   defaultWaitProviderTimeout,
+			}},
+			wantInstalled: "v1.0.0",
+		},
+		{
+			name:                "wait for the core provider before installing other providers",
+			managedProvider:     newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0"),
+			proxy:               test.NewFakeProxy(),
+			wantConditionReason: clusterctlv1.WaitingForCoreProviderReason,
+		},
+		{
+			name:            "install an infrastructure provider",
+			managedProvider: newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0"),
+			proxy: test.NewFakeProxy().
+				WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system"),
+			wantInit: []clusterctlclient.InitOptions{{
+				InfrastructureProviders: []string{"docker:v1.0.0"},
+				WaitProviders:           true,
+				WaitProviderTimeout:     defaultWaitProviderTimeout,
+			}},
+			wantInstalled: "v1.0.0",
+		},
+		{
+			name:            "upgrade a provider to the desired version",
+			managedProvider: newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.1.0"),
+			proxy: test.NewFakeProxy().
+				WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system"),
+			wantUpgrade: []clusterctlclient.ApplyUpgradeOptions{{
+				CoreProvider:        "capi-system/cluster-api:v1.1.0",
+				WaitProviders:       true,
+				WaitProviderTimeout: defaultWaitProviderTimeout,
+				RollbackOnFailure:   true,
+			}},
+			wantInstalled: "v1.1.0",
+		},
+		{
+			name:            "do not upgrade a provider without a desired version",
+			managedProvider: newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, ""),
+			proxy: test.NewFakeProxy().
+				WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system"),
+			wantInstalled: "v1.0.0",
+		},
+		{
+			name: "apply the components again when the configuration changes",
+			managedProvider: func() *clusterctlv1.ManagedProvider {
+				m := newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0")
+				m.Status.ConfigurationHash = "outdated"
+				return m
+			}(),
+			proxy: test.NewFakeProxy().
+				WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system"),
+			wantUpgrade: []clusterctlclient.ApplyUpgradeOptions{{
+				CoreProvider:        "capi-system/cluster-api:v1.0.0",
+				WaitProviders:       true,
+				WaitProviderTimeout: defaultWaitProviderTimeout,
+				RollbackOnFailure:   true,
+			}},
+			wantInstalled: "v1.0.0",
+		},
+		{
+			name:            "fail if the provider is installed more than once",
+			managedProvider: newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0"),
+			proxy: test.NewFakeProxy().
+				WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system").
+				WithProviderInventory("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0", "ns1").
+				WithProviderInventory("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0", "ns2"),
+			wantConditionReason: clusterctlv1.InvalidSpecReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			ctx := context.Background()
+
+			c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+				WithObjects(tt.managedProvider).
+				WithStatusSubresource(&clusterctlv1.ManagedProvider{}).
+				Build()
+			clusterctl := &fakeClusterctlClient{proxy: tt.proxy}
+			r := &ManagedProviderReconciler{
+				Client:        c,
+				ClientFactory: clusterctl.factory,
+			}
+
+			_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(tt.managedProvider)})
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(clusterctl.initOptions).To(Equal(tt.wantInit))
+			g.Expect(clusterctl.upgradeOptions).To(BeComparableTo(tt.wantUpgrade))
+
+			got := &clusterctlv1.ManagedProvider{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(tt.managedProvider), got)).To(Succeed())
+			g.Expect(got.Status.InstalledVersion).To(Equal(tt.wantInstalled))
+			if tt.wantInstalled != "" {
+				configuration, err := r.configuration(ctx, got)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(configuration.hash()).To(Equal(got.Status.ConfigurationHash))
+			}
+			if tt.wantConditionReason != "" {
+				g.Expect(conditions.IsFalse(got, clusterctlv1.ProviderInstalledCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(got, clusterctlv1.ProviderInstalledCondition)).To(Equal(tt.wantConditionReason))
+				return
+			}
+			g.Expect(conditions.IsTrue(got, clusterctlv1.ProviderInstalledCondition)).To(BeTrue())
+			g.Expect(conditions.IsTrue(got, clusterv1.ReadyCondition)).To(BeTrue())
+		})
+	}
+}
+
+func TestManagedProviderReconciler_ReconcileConfigurationChanges(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	managedProvider := newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0")
+	managedProvider.Spec.VariablesSecretName = "capi-variables"
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: managedProvider.Namespace,
+			Name:      "capi-variables",
+		},
+		Data: map[string][]byte{
+			"EXP_CLUSTER_RESOURCE_SET": []byte("false"),
+		},
+	}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).
+		WithObjects(managedProvider, secret).
+		WithStatusSubresource(&clusterctlv1.ManagedProvider{}).
+		Build()
+	clusterctl := &fakeClusterctlClient{proxy: test.NewFakeProxy()}
+	r := &ManagedProviderReconciler{
+		Client:        c,
+		ClientFactory: clusterctl.factory,
+	}
+	key := client.ObjectKeyFromObject(managedProvider)
+
+	// The first reconcile installs the provider.
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterctl.initOptions).To(HaveLen(1))
+
+	// Reconciling again without configuration changes is a no-op.
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterctl.initOptions).To(HaveLen(1))
+	g.Expect(clusterctl.upgradeOptions).To(BeEmpty())
+
+	// Changes to the variables Secret apply the components of the installed version again.
+	secret.Data["EXP_CLUSTER_RESOURCE_SET"] = []byte("true")
+	g.Expect(c.Update(ctx, secret)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterctl.upgradeOptions).To(HaveLen(1))
+	g.Expect(clusterctl.upgradeOptions[0].CoreProvider).To(Equal("cluster-api-system/cluster-api:v1.0.0"))
+
+	// Changes to the image overrides apply the components of the installed version again.
+	got := &clusterctlv1.ManagedProvider{}
+	g.Expect(c.Get(ctx, key, got)).To(Succeed())
+	got.Spec.ImageOverrides = []clusterctlv1.ImageOverride{{Repository: "example.com/all"}}
+	g.Expect(c.Update(ctx, got)).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterctl.upgradeOptions).To(HaveLen(2))
+
+	// Reconciling again after the configuration changes have been applied is a no-op.
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: key})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterctl.upgradeOptions).To(HaveLen(2))
+}
+
+func TestManagedProviderReconciler_secretToManagedProviders(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	withSecret := newManagedProvider("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0")
+	withSecret.Spec.VariablesSecretName = "variables"
+	withOtherSecret := newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0")
+	withOtherSecret.Spec.VariablesSecretName = "other-variables"
+	withoutSecret := newManagedProvider("kubeadm", clusterctlv1.BootstrapProviderType, "v1.0.0")
+	inOtherNamespace := newManagedProvider("aws", clusterctlv1.InfrastructureProviderType, "v1.0.0")
+	inOtherNamespace.Namespace = "other"
+	inOtherNamespace.Spec.VariablesSecretName = "variables"
+
+	r := &ManagedProviderReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(withSecret, withOtherSecret, withoutSecret, inOtherNamespace).Build(),
+	}
+
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "default",
+			Name:      "variables",
+		},
+	}
+	g.Expect(r.secretToManagedProviders(ctx, secret)).To(ConsistOf(
+		reconcile.Request{NamespacedName: client.ObjectKeyFromObject(withSecret)},
+	))
+}
+
+func TestManagedProviderReconciler_reconcileDelete(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	managedProvider := newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0")
+	proxy := test.NewFakeProxy().
+		WithProviderInventory("cluster-api", clusterctlv1.CoreProviderType, "v1.0.0", "capi-system").
+		WithProviderInventory("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0", "capd-system")
+	clusterctl := &fakeClusterctlClient{proxy: proxy}
+	r := &ManagedProviderReconciler{
+		Client:        fake.NewClientBuilder().WithScheme(scheme.Scheme).Build(),
+		ClientFactory: clusterctl.factory,
+	}
+
+	_, err := r.reconcileDelete(ctx, managedProvider)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(managedProvider.Finalizers).To(BeEmpty())
+
+	c, err := proxy.NewClient(ctx)
+	g.Expect(err).ToNot(HaveOccurred())
+	providers := &clusterctlv1.ProviderList{}
+	g.Expect(c.List(ctx, providers)).To(Succeed())
+	g.Expect(providers.Items).To(HaveLen(1))
+	g.Expect(providers.Items[0].ProviderName).To(Equal("cluster-api"))
+}
+
+func TestManagedProviderReconciler_clients(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	managedProvider := newManagedProvider("docker", clusterctlv1.InfrastructureProviderType, "v1.0.0")
+	managedProvider.Spec.FetchURL = "https://example.com/docker/v1.0.0/infrastructure-components.yaml"
+	managedProvider.Spec.Variables = map[string]string{
+		"FOO": "foo",
+		"BAR": "bar",
+	}
+	managedProvider.Spec.VariablesSecretName = "docker-variables"
+	managedProvider.Spec.ImageOverrides = []clusterctlv1.ImageOverride{
+		{Repository: "example.com/all"},
+	}
+	secret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: managedProvider.Namespace,
+			Name:      "docker-variables",
+		},
+		Data: map[string][]byte{
+			"BAR": []byte("bar-from-secret"),
+		},
+	}
+
+	var reader config.Reader
+	r := &ManagedProviderReconciler{
+		Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(secret).Build(),
+		ClientFactory: func(_ context.Context, r config.Reader) (clusterctlclient.Client, cluster.Client, error) {
+			reader = r
+			return nil, nil, nil
+		},
+	}
+
+	configuration, err := r.configuration(ctx, managedProvider)
+	g.Expect(err).ToNot(HaveOccurred())
+	_, _, err = r.clients(ctx, managedProvider, configuration)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	configClient, err := config.New(ctx, "", config.InjectReader(reader))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	g.Expect(configClient.Variables().Get("FOO")).To(Equal("foo"))
+	g.Expect(configClient.Variables().Get("BAR")).To(Equal("bar-from-secret"))
+
+	provider, err := configClient.Providers().Get("docker", clusterctlv1.InfrastructureProviderType)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(provider.URL()).To(Equal(managedProvider.Spec.FetchURL))
+
+	image, err := configClient.ImageMeta().AlterImage("infrastructure-docker", "registry.k8s.io/cluster-api/capd-manager:v1.0.0")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(image).To(Equal("example.com/all/capd-manager:v1.0.0"))
+}
+
+func newManagedProvider(name string, providerType clusterctlv1.ProviderType, version string) *clusterctlv1.ManagedProvider {
+	return &clusterctlv1.ManagedProvider{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace:  "default",
+			Name:       name,
+			Finalizers: []string{clusterctlv1.ManagedProviderFinalizer},
+		},
+		Spec: clusterctlv1.ManagedProviderSpec{
+			ProviderName: name,
+			Type:         string(providerType),
+			Version:      version,
+		},
+	}
+}
+
+// fakeClusterctlClient records the calls to Init and ApplyUpgrade, and updates the provider inventory accordingly.
+type fakeClusterctlClient struct {
+	clusterctlclient.Client
+
+	proxy          *test.FakeProxy
+	initOptions    []clusterctlclient.InitOptions
+	upgradeOptions []clusterctlclient.ApplyUpgradeOptions
+}
+
+func (f *fakeClusterctlClient) factory(ctx context.Context, reader config.Reader) (clusterctlclient.Client, cluster.Client, error) {
+	configClient, err := config.New(ctx, "", config.InjectReader(reader))
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, cluster.New(cluster.Kubeconfig{}, configClient, cluster.InjectProxy(f.proxy)), nil
+}
+
+func (f *fakeClusterctlClient) Init(ctx context.Context, options clusterctlclient.InitOptions) ([]clusterctlclient.Components, error) {
+	f.initOptions = append(f.initOptions, options)
+
+	c, err := f.proxy.NewClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	refs := append([]string{options.CoreProvider}, options.InfrastructureProviders...)
+	for _, ref := range refs {
+		if ref == "" {
+			continue
+		}
+		name, version, _ := strings.Cut(ref, ":")
+		providerType := clusterctlv1.InfrastructureProviderType
+		if ref == options.CoreProvider {
+			providerType = clusterctlv1.CoreProviderType
+		}
+		if err := c.Create(ctx, &clusterctlv1.Provider{
+			ObjectMeta: metav1.ObjectMeta{
+				Namespace: name + "-system",
+				Name:      clusterctlv1.ManifestLabel(name, providerType),
+			},
+			ProviderName: name,
+			Type:         string(providerType),
+			Version:      version,
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return nil, nil
+}
+
+func (f *fakeClusterctlClient) ApplyUpgrade(ctx context.Context, options clusterctlclient.ApplyUpgradeOptions) error {
+	f.upgradeOptions = append(f.upgradeOptions, options)
+
+	c, err := f.proxy.NewClient(ctx)
+	if err != nil {
+		return err
+	}
+	instance, version, _ := strings.Cut(options.CoreProvider, ":")
+	namespace, name, _ := strings.Cut(instance, "/")
+	provider := &clusterctlv1.Provider{}
+	if err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: clusterctlv1.ManifestLabel(name, clusterctlv1.CoreProviderType)}, provider); err != nil {
+		return err
+	}
+	provider.Version = version
+	return c.Update(ctx, provider)
+}
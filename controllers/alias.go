@@ -35,6 +35,7 @@ import (
 	machinedeploymenttopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machinedeployment"
 	machinesettopologycontroller "sigs.k8s.io/cluster-api/internal/controllers/topology/machineset"
 	runtimeclient "sigs.k8s.io/cluster-api/internal/runtime/client"
+	"sigs.k8s.io/cluster-api/util/sharding"
 )
 
 // Following types provides access to reconcilers implemented in internal/controllers, thus
@@ -45,9 +46,13 @@ type ClusterReconciler struct {
 	Client                    client.Client
 	UnstructuredCachingClient client.Client
 	APIReader                 client.Reader
+	Tracker                   *remote.ClusterCacheTracker
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
 }
 
 func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -55,7 +60,9 @@ func (r *ClusterReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		Client:                    r.Client,
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		APIReader:                 r.APIReader,
+		Tracker:                   r.Tracker,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -69,6 +76,9 @@ type MachineReconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
+
 	// NodeDrainClientTimeout timeout of the client used for draining nodes.
 	NodeDrainClientTimeout time.Duration
 }
@@ -80,6 +90,7 @@ func (r *MachineReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manag
 		APIReader:                 r.APIReader,
 		Tracker:                   r.Tracker,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 		NodeDrainClientTimeout:    r.NodeDrainClientTimeout,
 	}).SetupWithManager(ctx, mgr, options)
 }
@@ -93,6 +104,9 @@ type MachineSetReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
 }
 
 func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -102,6 +116,7 @@ func (r *MachineSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Ma
 		APIReader:                 r.APIReader,
 		Tracker:                   r.Tracker,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -131,6 +146,9 @@ type MachineHealthCheckReconciler struct {
 
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
 }
 
 func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -138,6 +156,7 @@ func (r *MachineHealthCheckReconciler) SetupWithManager(ctx context.Context, mgr
 		Client:           r.Client,
 		Tracker:          r.Tracker,
 		WatchFilterValue: r.WatchFilterValue,
+		Sharder:          r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -154,6 +173,9 @@ type ClusterTopologyReconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
+
 	// UnstructuredCachingClient provides a client that forces caching of unstructured objects,
 	// thus allowing to optimize reads for templates or provider specific objects in a managed topology.
 	UnstructuredCachingClient client.Client
@@ -167,6 +189,7 @@ func (r *ClusterTopologyReconciler) SetupWithManager(ctx context.Context, mgr ct
 		RuntimeClient:             r.RuntimeClient,
 		UnstructuredCachingClient: r.UnstructuredCachingClient,
 		WatchFilterValue:          r.WatchFilterValue,
+		Sharder:                   r.Sharder,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
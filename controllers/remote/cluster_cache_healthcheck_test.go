@@ -137,6 +137,7 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 
 			go cct.healthCheckCluster(ctx, &healthCheckInput{
 				cluster:            testClusterKey,
+				cache:              cc,
 				cfg:                env.Config,
 				httpClient:         httpClient,
 				interval:           testPollInterval,
@@ -160,7 +161,7 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			ctx, cancel := context.WithTimeout(ctx, time.Second)
 			defer cancel()
 			// Delete the cluster accessor and lock the cluster to simulate creation of a new cluster accessor
-			cct.deleteAccessor(ctx, testClusterKey)
+			cct.deleteAccessor(ctx, testClusterKey, evictionReasonClusterDeleted)
 			g.Expect(cct.clusterLock.TryLock(testClusterKey)).To(BeTrue())
 			startHealthCheck := time.Now()
 
@@ -168,6 +169,7 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			g.Expect(err).ToNot(HaveOccurred())
 			cct.healthCheckCluster(ctx, &healthCheckInput{
 				cluster:            testClusterKey,
+				cache:              cc,
 				cfg:                env.Config,
 				httpClient:         httpClient,
 				interval:           testPollInterval,
@@ -195,6 +197,7 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			go cct.healthCheckCluster(ctx,
 				&healthCheckInput{
 					cluster:            testClusterKey,
+					cache:              cc,
 					cfg:                env.Config,
 					httpClient:         httpClient,
 					interval:           testPollInterval,
@@ -210,6 +213,40 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			}, 5*time.Second, 1*time.Second).Should(BeFalse())
 		})
 
+		t.Run("with a replaced cluster accessor", func(t *testing.T) {
+			g := NewWithT(t)
+			ns := setup(t, g)
+			defer teardown(t, g, ns)
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			// Start the health check for a cluster accessor which has been replaced in the meantime, e.g. because
+			// it has been evicted and created again.
+			_, staleCancel := context.WithCancel(ctx)
+			staleCache := &stoppableCache{cancelFunc: staleCancel}
+
+			httpClient, err := rest.HTTPClientFor(env.Config)
+			g.Expect(err).ToNot(HaveOccurred())
+			go cct.healthCheckCluster(ctx,
+				&healthCheckInput{
+					cluster:            testClusterKey,
+					cache:              staleCache,
+					cfg:                env.Config,
+					httpClient:         httpClient,
+					interval:           testPollInterval,
+					requestTimeout:     testPollTimeout,
+					unhealthyThreshold: testUnhealthyThreshold,
+					path:               "/clusterAccessor",
+				})
+
+			// The stale health check should not delete the new cluster accessor, even if the health check fails.
+			g.Consistently(func() bool {
+				_, ok := cct.loadAccessor(testClusterKey)
+				return ok
+			}, 5*time.Second, 1*time.Second).Should(BeTrue())
+		})
+
 		t.Run("with an invalid config", func(t *testing.T) {
 			g := NewWithT(t)
 			ns := setup(t, g)
@@ -232,6 +269,7 @@ func TestClusterCacheHealthCheck(t *testing.T) {
 			g.Expect(err).ToNot(HaveOccurred())
 			go cct.healthCheckCluster(ctx, &healthCheckInput{
 				cluster:            testClusterKey,
+				cache:              cc,
 				cfg:                config,
 				httpClient:         httpClient,
 				interval:           testPollInterval,
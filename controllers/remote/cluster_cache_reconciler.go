@@ -73,7 +73,7 @@ func (r *ClusterCacheReconciler) Reconcile(ctx context.Context, req reconcile.Re
 
 	log.V(2).Info("Cluster no longer exists")
 
-	r.Tracker.deleteAccessor(ctx, req.NamespacedName)
+	r.Tracker.deleteAccessor(ctx, req.NamespacedName, evictionReasonClusterDeleted)
 
 	return reconcile.Result{}, nil
 }
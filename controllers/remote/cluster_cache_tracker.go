@@ -23,6 +23,7 @@ import (
 	"net/http"
 	"os"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/go-logr/logr"
@@ -98,6 +99,16 @@ type ClusterCacheTracker struct {
 	// This information will be used to detected if the controller is running on a workload cluster, so
 	// that we can then access the apiserver directly.
 	controllerPodMetadata *metav1.ObjectMeta
+
+	// maxAccessors is the maximum number of clusterAccessors; 0 means unbounded.
+	maxAccessors int
+
+	// accessorIdleTimeout is the duration after which an unused clusterAccessor is evicted; 0 means never.
+	accessorIdleTimeout time.Duration
+
+	// clientQPS and clientBurst limit the requests to the apiserver of each cluster.
+	clientQPS   float32
+	clientBurst int
 }
 
 // ClusterCacheTrackerOptions defines options to configure
@@ -122,6 +133,30 @@ type ClusterCacheTrackerOptions struct {
 	// This is used to calculate the user agent string.
 	// If not set, it defaults to "cluster-cache-tracker".
 	ControllerName string
+
+	// MaxAccessors is the maximum number of workload clusters with a cached client.
+	// When the limit is reached, the cache of the least recently used workload cluster is stopped
+	// before a cache is created for another workload cluster.
+	// NOTE: The cache of a workload cluster with watches is never stopped because of this limit, because
+	// the controllers relying on those watches would silently stop receiving events; as a consequence,
+	// the number of workload clusters with a cached client could exceed MaxAccessors.
+	// If not set, the number of workload clusters with a cached client is unbounded.
+	MaxAccessors int
+
+	// AccessorIdleTimeout is the duration after which the cache of a workload cluster which has not been
+	// accessed is stopped; the cache is created again the next time the workload cluster is accessed.
+	// NOTE: The cache of a workload cluster with watches is never stopped because it is idle, because
+	// the controllers relying on those watches would silently stop receiving events.
+	// If not set, caches are never stopped because they are idle.
+	AccessorIdleTimeout time.Duration
+
+	// ClientQPS is the maximum number of queries per second to the apiserver of each workload cluster.
+	// If not set, the client-go default is used.
+	ClientQPS float32
+
+	// ClientBurst is the maximum burst of queries to the apiserver of each workload cluster.
+	// If not set, the client-go default is used.
+	ClientBurst int
 }
 
 func setDefaultOptions(opts *ClusterCacheTrackerOptions) {
@@ -176,6 +211,10 @@ func NewClusterCacheTracker(manager ctrl.Manager, options ClusterCacheTrackerOpt
 		clusterAccessors:      make(map[client.ObjectKey]*clusterAccessor),
 		clusterLock:           newKeyedMutex(),
 		indexes:               options.Indexes,
+		maxAccessors:          options.MaxAccessors,
+		accessorIdleTimeout:   options.AccessorIdleTimeout,
+		clientQPS:             options.ClientQPS,
+		clientBurst:           options.ClientBurst,
 	}, nil
 }
 
@@ -221,6 +260,19 @@ type clusterAccessor struct {
 	watches                  sets.Set[string]
 	config                   *rest.Config
 	etcdClientCertificateKey *rsa.PrivateKey
+
+	// lastAccessed is the time the clusterAccessor has been used for the last time, in Unix nanoseconds.
+	lastAccessed atomic.Int64
+}
+
+// touch records that the clusterAccessor has been used.
+func (a *clusterAccessor) touch() {
+	a.lastAccessed.Store(time.Now().UnixNano())
+}
+
+// idleFor returns how long the clusterAccessor has not been used.
+func (a *clusterAccessor) idleFor() time.Duration {
+	return time.Since(time.Unix(0, a.lastAccessed.Load()))
 }
 
 // clusterAccessorExists returns true if a clusterAccessor exists for cluster.
@@ -242,11 +294,53 @@ func (t *ClusterCacheTracker) loadAccessor(cluster client.ObjectKey) (*clusterAc
 }
 
 // storeAccessor stores a clusterAccessor.
+// If the maximum number of clusterAccessors is reached, the least recently used clusterAccessor without watches
+// is evicted first.
 func (t *ClusterCacheTracker) storeAccessor(cluster client.ObjectKey, accessor *clusterAccessor) {
 	t.clusterAccessorsLock.Lock()
 	defer t.clusterAccessorsLock.Unlock()
 
+	if t.maxAccessors > 0 && len(t.clusterAccessors) >= t.maxAccessors {
+		if lru, ok := leastRecentlyUsedAccessor(t.clusterAccessors); ok {
+			t.log.V(2).Info("Evicting least recently used clusterAccessor", "Cluster", klog.KRef(lru.Namespace, lru.Name))
+			t.deleteAccessorLocked(lru, evictionReasonLRU)
+		} else {
+			t.log.V(4).Info("Maximum number of clusterAccessors reached, but all of them have watches and cannot be evicted")
+		}
+	}
+
+	accessor.touch()
 	t.clusterAccessors[cluster] = accessor
+	t.updateAccessorMetricsLocked()
+}
+
+// leastRecentlyUsedAccessor returns the cluster of the clusterAccessor which has been used least recently.
+// clusterAccessors with watches are ignored, because evicting them would silently stop the watches.
+// NOTE: This must be called while holding the clusterAccessorsLock.
+func leastRecentlyUsedAccessor(accessors map[client.ObjectKey]*clusterAccessor) (client.ObjectKey, bool) {
+	var lru client.ObjectKey
+	var lruIdleFor time.Duration
+	found := false
+	for cluster, accessor := range accessors {
+		if accessor.watches.Len() > 0 {
+			continue
+		}
+		if idleFor := accessor.idleFor(); !found || idleFor > lruIdleFor {
+			lru, lruIdleFor, found = cluster, idleFor, true
+		}
+	}
+	return lru, found
+}
+
+// updateAccessorMetricsLocked updates the metrics about the clusterAccessors.
+// NOTE: This must be called while holding the clusterAccessorsLock.
+func (t *ClusterCacheTracker) updateAccessorMetricsLocked() {
+	watches := 0
+	for _, accessor := range t.clusterAccessors {
+		watches += accessor.watches.Len()
+	}
+	accessorsGauge.WithLabelValues(t.controllerName).Set(float64(len(t.clusterAccessors)))
+	watchesGauge.WithLabelValues(t.controllerName).Set(float64(watches))
 }
 
 // getClusterAccessor returns a clusterAccessor for cluster.
@@ -259,6 +353,7 @@ func (t *ClusterCacheTracker) getClusterAccessor(ctx context.Context, cluster cl
 
 	// If the clusterAccessor already exists, return early.
 	if accessor, ok := t.loadAccessor(cluster); ok {
+		accessor.touch()
 		return accessor, nil
 	}
 
@@ -303,6 +398,12 @@ func (t *ClusterCacheTracker) newClusterAccessor(ctx context.Context, cluster cl
 	if err != nil {
 		return nil, errors.Wrapf(err, "error fetching REST client config for remote cluster %q", cluster.String())
 	}
+	if t.clientQPS > 0 {
+		config.QPS = t.clientQPS
+	}
+	if t.clientBurst > 0 {
+		config.Burst = t.clientBurst
+	}
 
 	// Create a http client and a mapper for the cluster.
 	httpClient, mapper, err := t.createHTTPClientAndMapper(config, cluster)
@@ -504,6 +605,7 @@ func (t *ClusterCacheTracker) createCachedClient(ctx context.Context, config *re
 	// Start cluster healthcheck!!!
 	go t.healthCheckCluster(cacheCtx, &healthCheckInput{
 		cluster:    cluster,
+		cache:      cache,
 		cfg:        config,
 		httpClient: httpClient,
 	})
@@ -515,22 +617,54 @@ func (t *ClusterCacheTracker) createCachedClient(ctx context.Context, config *re
 }
 
 // deleteAccessor stops a clusterAccessor's cache and removes the clusterAccessor from the tracker.
-func (t *ClusterCacheTracker) deleteAccessor(_ context.Context, cluster client.ObjectKey) {
+func (t *ClusterCacheTracker) deleteAccessor(_ context.Context, cluster client.ObjectKey, reason string) {
 	t.clusterAccessorsLock.Lock()
 	defer t.clusterAccessorsLock.Unlock()
 
+	t.deleteAccessorLocked(cluster, reason)
+	t.updateAccessorMetricsLocked()
+}
+
+// deleteAccessorWithCache stops a clusterAccessor's cache and removes the clusterAccessor from the tracker,
+// but only if the clusterAccessor stored for the cluster is still the one using the given cache.
+// This ensures a clusterAccessor created after the given one has been evicted is not deleted.
+func (t *ClusterCacheTracker) deleteAccessorWithCache(cluster client.ObjectKey, cache *stoppableCache, reason string) {
+	t.clusterAccessorsLock.Lock()
+	defer t.clusterAccessorsLock.Unlock()
+
+	if a, exists := t.clusterAccessors[cluster]; !exists || a.cache != cache {
+		return
+	}
+	t.deleteAccessorLocked(cluster, reason)
+	t.updateAccessorMetricsLocked()
+}
+
+// accessorHasWatches returns true if watches have been added to the clusterAccessor.
+func (t *ClusterCacheTracker) accessorHasWatches(accessor *clusterAccessor) bool {
+	t.clusterAccessorsLock.RLock()
+	defer t.clusterAccessorsLock.RUnlock()
+
+	return accessor.watches.Len() > 0
+}
+
+// deleteAccessorLocked stops a clusterAccessor's cache and removes the clusterAccessor from the tracker.
+// NOTE: This must be called while holding the clusterAccessorsLock.
+func (t *ClusterCacheTracker) deleteAccessorLocked(cluster client.ObjectKey, reason string) {
 	a, exists := t.clusterAccessors[cluster]
 	if !exists {
 		return
 	}
 
 	log := t.log.WithValues("Cluster", klog.KRef(cluster.Namespace, cluster.Name))
-	log.V(2).Info("Deleting clusterAccessor")
-	log.V(4).Info("Stopping cache")
-	a.cache.Stop()
-	log.V(4).Info("Cache stopped")
+	log.V(2).Info("Deleting clusterAccessor", "reason", reason)
+	if a.cache != nil {
+		log.V(4).Info("Stopping cache")
+		a.cache.Stop()
+		log.V(4).Info("Cache stopped")
+	}
 
 	delete(t.clusterAccessors, cluster)
+	accessorEvictionsTotal.WithLabelValues(t.controllerName, reason).Inc()
 }
 
 // Watcher is a scoped-down interface from Controller that only knows how to watch.
@@ -589,7 +723,11 @@ func (t *ClusterCacheTracker) Watch(ctx context.Context, input WatchInput) error
 		return errors.Wrapf(err, "failed to add %s watch on cluster %s: failed to create watch", input.Kind, klog.KRef(input.Cluster.Namespace, input.Cluster.Name))
 	}
 
+	// NOTE: watches are read when evicting clusterAccessors, which happens while holding the clusterAccessorsLock.
+	t.clusterAccessorsLock.Lock()
 	accessor.watches.Insert(input.Name)
+	t.updateAccessorMetricsLocked()
+	t.clusterAccessorsLock.Unlock()
 
 	return nil
 }
@@ -597,6 +735,7 @@ func (t *ClusterCacheTracker) Watch(ctx context.Context, input WatchInput) error
 // healthCheckInput provides the input for the healthCheckCluster method.
 type healthCheckInput struct {
 	cluster            client.ObjectKey
+	cache              *stoppableCache
 	httpClient         *http.Client
 	cfg                *rest.Config
 	interval           time.Duration
@@ -629,6 +768,7 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 	in.setDefaults()
 
 	unhealthyCount := 0
+	evictionReason := evictionReasonUnhealthy
 
 	// This gets us a client that can make raw http(s) calls to the remote apiserver. We only need to create it once
 	// and we can reuse it inside the polling loop.
@@ -657,7 +797,8 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 			return false, nil
 		}
 
-		if _, ok := t.loadAccessor(in.cluster); !ok {
+		accessor, ok := t.loadAccessor(in.cluster)
+		if !ok {
 			// If there is no accessor but the cluster is locked, we're probably in the middle of the cluster accessor
 			// creation and we should requeue the health check until it's done.
 			if ok := t.clusterLock.TryLock(in.cluster); !ok {
@@ -670,10 +811,26 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 			return true, nil
 		}
 
+		// If the clusterAccessor has been replaced, e.g. because it has been evicted and created again,
+		// this health check is stale; stop it and leave the new clusterAccessor to its own health check.
+		if accessor.cache != in.cache {
+			return true, nil
+		}
+
+		// If the clusterAccessor has not been used for longer than the idle timeout, stop the health check
+		// so that the clusterAccessor is evicted and the memory used by its cache is released.
+		// NOTE: clusterAccessors with watches are not evicted, because this would silently stop the watches.
+		if t.accessorIdleTimeout > 0 && accessor.idleFor() > t.accessorIdleTimeout && !t.accessorHasWatches(accessor) {
+			t.log.V(2).Info("Cluster accessor is idle, evicting it", "Cluster", klog.KRef(in.cluster.Namespace, in.cluster.Name))
+			evictionReason = evictionReasonIdle
+			return true, nil
+		}
+
 		// An error here means there was either an issue connecting or the API returned an error.
 		// If no error occurs, reset the unhealthy counter.
 		_, err := restClient.Get().AbsPath(in.path).Timeout(in.requestTimeout).DoRaw(ctx)
 		if err != nil {
+			healthCheckFailuresTotal.WithLabelValues(t.controllerName).Inc()
 			if apierrors.IsUnauthorized(err) {
 				// Unauthorized means that the underlying kubeconfig is not authorizing properly anymore, which
 				// usually is the result of automatic kubeconfig refreshes, meaning that we have to throw away the
@@ -705,7 +862,9 @@ func (t *ClusterCacheTracker) healthCheckCluster(ctx context.Context, in *health
 	// Ensure in any case that the accessor is deleted (even if it is a no-op).
 	// NB. It is crucial to ensure the accessor was deleted, so it can be later recreated when the
 	// cluster is reachable again
-	t.deleteAccessor(ctx, in.cluster)
+	// NB. Only the accessor this health check has been started for is deleted; if it has already been
+	// evicted, a new accessor could have been created for the same cluster in the meantime.
+	t.deleteAccessorWithCache(in.cluster, in.cache, evictionReason)
 }
 
 // newClientWithTimeout returns a new client which sets the specified timeout on all Get and List calls.
@@ -20,12 +20,15 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/davecgh/go-spew/spew"
+	"github.com/go-logr/logr"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"k8s.io/client-go/kubernetes/scheme"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -319,6 +322,116 @@ func TestClusterCacheTracker(t *testing.T) {
 	})
 }
 
+func TestClusterCacheTrackerAccessorEviction(t *testing.T) {
+	t.Run("evicts the least recently used accessor when the maximum is reached", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cct := &ClusterCacheTracker{
+			log:              logr.Discard(),
+			controllerName:   "test-controller",
+			clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
+			clusterLock:      newKeyedMutex(),
+			maxAccessors:     2,
+		}
+
+		newAccessor := func() (*clusterAccessor, context.Context) {
+			cacheCtx, cancel := context.WithCancel(ctx)
+			return &clusterAccessor{
+				cache:   &stoppableCache{cancelFunc: cancel},
+				watches: sets.Set[string]{},
+			}, cacheCtx
+		}
+
+		clusterA := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-a"}
+		clusterB := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-b"}
+		clusterC := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-c"}
+
+		accessorA, cacheCtxA := newAccessor()
+		cct.storeAccessor(clusterA, accessorA)
+		accessorB, cacheCtxB := newAccessor()
+		cct.storeAccessor(clusterB, accessorB)
+
+		// Make cluster-b the least recently used cluster.
+		accessorB.lastAccessed.Store(time.Now().Add(-time.Hour).UnixNano())
+
+		accessorC, _ := newAccessor()
+		cct.storeAccessor(clusterC, accessorC)
+
+		g.Expect(cct.clusterAccessorExists(clusterA)).To(BeTrue())
+		g.Expect(cct.clusterAccessorExists(clusterB)).To(BeFalse())
+		g.Expect(cct.clusterAccessorExists(clusterC)).To(BeTrue())
+		g.Expect(cacheCtxA.Err()).ToNot(HaveOccurred())
+		g.Expect(cacheCtxB.Err()).To(HaveOccurred())
+	})
+
+	t.Run("does not evict accessors with watches", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cct := &ClusterCacheTracker{
+			log:              logr.Discard(),
+			controllerName:   "test-controller",
+			clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
+			clusterLock:      newKeyedMutex(),
+			maxAccessors:     2,
+		}
+
+		newAccessor := func(watches ...string) (*clusterAccessor, context.Context) {
+			cacheCtx, cancel := context.WithCancel(ctx)
+			return &clusterAccessor{
+				cache:   &stoppableCache{cancelFunc: cancel},
+				watches: sets.New[string](watches...),
+			}, cacheCtx
+		}
+
+		clusterA := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-a"}
+		clusterB := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-b"}
+		clusterC := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-c"}
+		clusterD := client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: "cluster-d"}
+
+		accessorA, cacheCtxA := newAccessor()
+		cct.storeAccessor(clusterA, accessorA)
+		accessorB, cacheCtxB := newAccessor("watch-nodes")
+		cct.storeAccessor(clusterB, accessorB)
+
+		// Make cluster-b, which has watches, the least recently used cluster.
+		accessorB.lastAccessed.Store(time.Now().Add(-time.Hour).UnixNano())
+
+		accessorC, cacheCtxC := newAccessor("watch-nodes")
+		cct.storeAccessor(clusterC, accessorC)
+
+		// cluster-a is evicted instead of cluster-b, because it is the only one without watches.
+		g.Expect(cct.clusterAccessorExists(clusterA)).To(BeFalse())
+		g.Expect(cct.clusterAccessorExists(clusterB)).To(BeTrue())
+		g.Expect(cct.clusterAccessorExists(clusterC)).To(BeTrue())
+		g.Expect(cacheCtxA.Err()).To(HaveOccurred())
+		g.Expect(cacheCtxB.Err()).ToNot(HaveOccurred())
+
+		// If all the accessors have watches, the maximum is exceeded.
+		accessorD, _ := newAccessor()
+		cct.storeAccessor(clusterD, accessorD)
+
+		g.Expect(cct.clusterAccessors).To(HaveLen(3))
+		g.Expect(cacheCtxB.Err()).ToNot(HaveOccurred())
+		g.Expect(cacheCtxC.Err()).ToNot(HaveOccurred())
+	})
+
+	t.Run("does not evict accessors without a maximum", func(t *testing.T) {
+		g := NewWithT(t)
+
+		cct := &ClusterCacheTracker{
+			log:              logr.Discard(),
+			controllerName:   "test-controller",
+			clusterAccessors: make(map[client.ObjectKey]*clusterAccessor),
+			clusterLock:      newKeyedMutex(),
+		}
+
+		for i := 0; i < 3; i++ {
+			cct.storeAccessor(client.ObjectKey{Namespace: metav1.NamespaceDefault, Name: fmt.Sprintf("cluster-%d", i)}, &clusterAccessor{watches: sets.Set[string]{}})
+		}
+		g.Expect(cct.clusterAccessors).To(HaveLen(3))
+	})
+}
+
 type testController struct {
 	ch chan string
 }
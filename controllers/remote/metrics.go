@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package remote
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+	ctrlmetrics "sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+func init() {
+	// Register the metrics at the controller-runtime metrics registry.
+	ctrlmetrics.Registry.MustRegister(accessorsGauge)
+	ctrlmetrics.Registry.MustRegister(watchesGauge)
+	ctrlmetrics.Registry.MustRegister(accessorEvictionsTotal)
+	ctrlmetrics.Registry.MustRegister(healthCheckFailuresTotal)
+}
+
+// Metrics subsystem and all of the keys used by the ClusterCacheTracker.
+const (
+	clusterCacheTrackerSubsystem = "capi_cluster_cache_tracker"
+
+	// evictionReasonIdle is used when an accessor is evicted because it has not been used for the idle timeout.
+	evictionReasonIdle = "idle"
+	// evictionReasonLRU is used when an accessor is evicted to make room for a new one.
+	evictionReasonLRU = "lru"
+	// evictionReasonUnhealthy is used when an accessor is evicted because the health check of the cluster failed.
+	evictionReasonUnhealthy = "unhealthy"
+	// evictionReasonClusterDeleted is used when an accessor is evicted because the Cluster has been deleted.
+	evictionReasonClusterDeleted = "cluster_deleted"
+)
+
+var (
+	// accessorsGauge reports the number of cluster accessors, i.e. the number of clusters with a cached client.
+	accessorsGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheTrackerSubsystem,
+		Name:      "accessors",
+		Help:      "Number of cluster accessors, i.e. of workload clusters with a cache, partitioned by controller.",
+	}, []string{"controller"})
+
+	// watchesGauge reports the number of watches across all the cluster accessors.
+	watchesGauge = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Subsystem: clusterCacheTrackerSubsystem,
+		Name:      "watches",
+		Help:      "Number of watches on workload clusters across all cluster accessors, partitioned by controller.",
+	}, []string{"controller"})
+
+	// accessorEvictionsTotal reports the number of cluster accessors which have been evicted.
+	accessorEvictionsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: clusterCacheTrackerSubsystem,
+		Name:      "accessor_evictions_total",
+		Help:      "Number of cluster accessors which have been evicted, partitioned by controller and reason.",
+	}, []string{"controller", "reason"})
+
+	// healthCheckFailuresTotal reports the number of failed health checks of workload clusters.
+	healthCheckFailuresTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Subsystem: clusterCacheTrackerSubsystem,
+		Name:      "health_check_failures_total",
+		Help:      "Number of failed health checks of workload clusters, partitioned by controller.",
+	}, []string{"controller"})
+)
@@ -54,6 +54,10 @@ const (
 	// RollingUpdateInProgressReason (Severity=Warning) documents a KubeadmControlPlane object executing a
 	// rolling upgrade for aligning the machines spec to the desired state.
 	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// InPlaceUpdateInProgressReason (Severity=Warning) documents a KubeadmControlPlane object updating
+	// the control plane components in place for aligning the machines spec to the desired state.
+	InPlaceUpdateInProgressReason = "InPlaceUpdateInProgress"
 )
 
 const (
@@ -132,6 +136,24 @@ const (
 	// EtcdMemberUnhealthyReason (Severity=Error) documents a Machine's etcd member is unhealthy.
 	EtcdMemberUnhealthyReason = "EtcdMemberUnhealthy"
 
+	// EtcdMemberLearnerReason (Severity=Info) documents a Machine's etcd member that joined the etcd cluster as a learner
+	// (non-voting member) and it is not yet promoted to voting member.
+	EtcdMemberLearnerReason = "EtcdMemberLearner"
+
+	// EtcdLearnersPromotedCondition documents that the etcd members which joined the etcd cluster as learners
+	// (non-voting members) have been promoted to voting members.
+	// NOTE: This condition exists only if a stacked etcd cluster is used and etcd learners have been observed, e.g.
+	// when kubeadm joins control plane machines with the EtcdLearnerMode feature gate enabled.
+	EtcdLearnersPromotedCondition clusterv1.ConditionType = "EtcdLearnersPromoted"
+
+	// WaitingForEtcdLearnersReason (Severity=Info) documents a KubeadmControlPlane waiting for etcd learners
+	// to be in sync with the leader before promoting them to voting members.
+	WaitingForEtcdLearnersReason = "WaitingForEtcdLearners"
+
+	// EtcdLearnersPromotionFailedReason (Severity=Warning) documents a KubeadmControlPlane failing to promote
+	// etcd learners to voting members.
+	EtcdLearnersPromotionFailedReason = "EtcdLearnersPromotionFailed"
+
 	// MachinesCreatedCondition documents that the machines controlled by the KubeadmControlPlane are created.
 	// When this condition is false, it indicates that there was an error when cloning the infrastructure/bootstrap template or
 	// when generating the machine object.
@@ -149,3 +171,40 @@ const (
 	// generate a machine object.
 	MachineGenerationFailedReason = "MachineGenerationFailed"
 )
+
+const (
+	// EtcdPoolReadyCondition documents the status of the dedicated etcd pool managed by the KubeadmControlPlane.
+	// NOTE: This condition exists only if KubeadmControlPlane.spec.etcdPool is set.
+	EtcdPoolReadyCondition clusterv1.ConditionType = "EtcdPoolReady"
+
+	// WaitingForEtcdPoolReason (Severity=Info) documents a KubeadmControlPlane waiting for all the etcd pool
+	// machines to be provisioned before initializing the control plane.
+	WaitingForEtcdPoolReason = "WaitingForEtcdPool"
+
+	// EtcdPoolScalingUpReason (Severity=Info) documents a KubeadmControlPlane that is increasing the number of etcd machines.
+	EtcdPoolScalingUpReason = "EtcdPoolScalingUp"
+
+	// EtcdPoolScalingDownReason (Severity=Info) documents a KubeadmControlPlane that is decreasing the number of etcd machines.
+	EtcdPoolScalingDownReason = "EtcdPoolScalingDown"
+
+	// EtcdPoolRollingUpdateInProgressReason (Severity=Warning) documents a KubeadmControlPlane executing a
+	// rolling upgrade of the etcd pool for aligning the etcd machines spec to the desired state.
+	EtcdPoolRollingUpdateInProgressReason = "EtcdPoolRollingUpdateInProgress"
+
+	// EtcdPoolWaitingForControlPlaneReason (Severity=Info) documents a KubeadmControlPlane waiting for the
+	// control plane machines to be rolled out with the current etcd pool endpoints before changing
+	// the etcd pool membership again.
+	EtcdPoolWaitingForControlPlaneReason = "EtcdPoolWaitingForControlPlane"
+
+	// EtcdPoolWaitingForMembersReason (Severity=Info) documents a KubeadmControlPlane waiting for all the etcd pool
+	// machines to be provisioned and to host a healthy etcd member before changing the etcd pool membership again.
+	EtcdPoolWaitingForMembersReason = "EtcdPoolWaitingForMembers"
+
+	// EtcdPoolMemberAddFailedReason (Severity=Warning) documents a KubeadmControlPlane failing to add
+	// the etcd member for a new etcd machine.
+	EtcdPoolMemberAddFailedReason = "EtcdPoolMemberAddFailed"
+
+	// EtcdPoolMemberRemovalFailedReason (Severity=Warning) documents a KubeadmControlPlane failing to remove
+	// an etcd member before deleting the corresponding etcd machine.
+	EtcdPoolMemberRemovalFailedReason = "EtcdPoolMemberRemovalFailed"
+)
@@ -68,12 +68,28 @@ const (
 	// DefaultMinHealthyPeriod defines the default minimum period before we consider a remediation on a
 	// machine unrelated from the previous remediation.
 	DefaultMinHealthyPeriod = 1 * time.Hour
+
+	// InPlaceUpdateFailedAnnotation is a machine annotation documenting that the in-place update of the control plane
+	// components failed for the machine; the value of the annotation is the hash of the control plane components
+	// configuration that failed to be applied, and the machine is rolled out instead.
+	InPlaceUpdateFailedAnnotation = "controlplane.cluster.x-k8s.io/in-place-update-failed"
+
+	// EtcdPoolNameLabel is the label set on machines of a dedicated etcd pool managed by a KubeadmControlPlane.
+	// The value of the label is the name of the KubeadmControlPlane.
+	EtcdPoolNameLabel = "controlplane.cluster.x-k8s.io/etcd-pool-name"
+
+	// EtcdPoolEndpointsAnnotation is a control plane machine annotation that stores the comma separated list of
+	// etcd pool endpoints the machine has been bootstrapped with.
+	// This annotation is used to detect changes in the etcd pool membership and to roll out control plane machines
+	// once the etcd pool is stable.
+	EtcdPoolEndpointsAnnotation = "controlplane.cluster.x-k8s.io/etcd-pool-endpoints"
 )
 
 // KubeadmControlPlaneSpec defines the desired state of KubeadmControlPlane.
 type KubeadmControlPlaneSpec struct {
 	// Number of desired machines. Defaults to 1. When stacked etcd is used only
-	// odd numbers are permitted, as per [etcd best practice](https://etcd.io/docs/v3.3.12/faq/#why-an-odd-number-of-cluster-members).
+	// odd numbers are permitted, as per [etcd best practice](https://etcd.io/docs/v3.3.12/faq/#why-an-odd-number-of-cluster-members),
+	// unless control plane machines join etcd as learners (Kubernetes >= v1.27 and etcd >= v3.4).
 	// This is a pointer to distinguish between explicit zero and not specified.
 	// +optional
 	Replicas *int32 `json:"replicas,omitempty"`
@@ -117,6 +133,14 @@ type KubeadmControlPlaneSpec struct {
 	// The RemediationStrategy that controls how control plane machine remediation happens.
 	// +optional
 	RemediationStrategy *RemediationStrategy `json:"remediationStrategy,omitempty"`
+
+	// EtcdPool defines a dedicated pool of etcd machines created and managed by the KubeadmControlPlane.
+	// When set, etcd is not stacked on the control plane machines; instead the API servers are configured
+	// to use the etcd pool as an external etcd, with endpoints and certificates wired automatically.
+	// This field can only be set at creation time, and it is mutually exclusive with
+	// kubeadmConfigSpec.clusterConfiguration.etcd.
+	// +optional
+	EtcdPool *EtcdPool `json:"etcdPool,omitempty"`
 }
 
 // KubeadmControlPlaneMachineTemplate defines the template for Machines
@@ -149,6 +173,46 @@ type KubeadmControlPlaneMachineTemplate struct {
 	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
 }
 
+// EtcdPool defines a dedicated pool of etcd machines managed by a KubeadmControlPlane.
+type EtcdPool struct {
+	// Number of desired etcd machines. Defaults to 3.
+	// Only odd numbers are permitted, as per [etcd best practice](https://etcd.io/docs/v3.3.12/faq/#why-an-odd-number-of-cluster-members).
+	// This is a pointer to distinguish between explicit zero and not specified.
+	// +optional
+	Replicas *int32 `json:"replicas,omitempty"`
+
+	// MachineTemplate contains information about how etcd machines
+	// should be shaped when creating or updating the etcd pool.
+	MachineTemplate EtcdPoolMachineTemplate `json:"machineTemplate"`
+}
+
+// EtcdPoolMachineTemplate defines the template for etcd Machines
+// in a KubeadmControlPlane object.
+type EtcdPoolMachineTemplate struct {
+	// Standard object's metadata.
+	// More info: https://git.k8s.io/community/contributors/devel/sig-architecture/api-conventions.md#metadata
+	// +optional
+	ObjectMeta clusterv1.ObjectMeta `json:"metadata,omitempty"`
+
+	// InfrastructureRef is a required reference to a custom resource
+	// offered by an infrastructure provider.
+	InfrastructureRef corev1.ObjectReference `json:"infrastructureRef"`
+
+	// BootstrapConfigRef is a required reference to a bootstrap config template
+	// offered by a bootstrap provider able to run etcd on a machine (e.g. etcdadm).
+	// The etcd member of each new machine is added by the KubeadmControlPlane once the machine
+	// reports an address; the bootstrap provider is expected to start etcd on the first machine
+	// as a new etcd cluster, and on the other machines joining the existing etcd cluster, using
+	// the etcd CA stored in the <cluster-name>-etcd Secret.
+	BootstrapConfigRef corev1.ObjectReference `json:"bootstrapConfigRef"`
+
+	// NodeDeletionTimeout defines how long the machine controller will attempt to delete the Node that the Machine
+	// hosts after the Machine is marked for deletion. A duration of 0 will retry deletion indefinitely.
+	// If no value is provided, the default value for this property of the Machine resource will be used.
+	// +optional
+	NodeDeletionTimeout *metav1.Duration `json:"nodeDeletionTimeout,omitempty"`
+}
+
 // RolloutBefore describes when a rollout should be performed on the KCP machines.
 type RolloutBefore struct {
 	// CertificatesExpiryDays indicates a rollout needs to be performed if the
@@ -170,6 +234,25 @@ type RolloutStrategy struct {
 	// RolloutStrategyType = RollingUpdate.
 	// +optional
 	RollingUpdate *RollingUpdate `json:"rollingUpdate,omitempty"`
+
+	// InPlaceUpdate, if set, enables in-place updates of the control plane components.
+	// When enabled, changes to extraArgs and extraVolumes of apiServer, controllerManager and scheduler
+	// in kubeadmConfigSpec.clusterConfiguration are applied by regenerating the static pod manifests
+	// on the control plane nodes, one node at a time, instead of rolling out the control plane machines.
+	// Any other change, as well as a failure while updating a node in place, triggers a rollout.
+	// NOTE: This field can be set only if the KubeadmControlPlaneInPlaceUpdates feature gate is enabled.
+	// +optional
+	InPlaceUpdate *InPlaceUpdate `json:"inPlaceUpdate,omitempty"`
+}
+
+// InPlaceUpdate defines how control plane components are updated in place.
+type InPlaceUpdate struct {
+	// Image is the container image used to run kubeadm on the control plane nodes
+	// for regenerating the static pod manifests; the image must provide the chroot command.
+	// The image runs in a privileged container with the host root filesystem mounted, so it
+	// must be pinned by digest, e.g. registry.example.com/debian-base@sha256:<digest>.
+	// +kubebuilder:validation:MinLength=1
+	Image string `json:"image"`
 }
 
 // RollingUpdate is used to control the desired behavior of rolling update.
@@ -297,6 +380,31 @@ type KubeadmControlPlaneStatus struct {
 	// LastRemediation stores info about last remediation performed.
 	// +optional
 	LastRemediation *LastRemediationStatus `json:"lastRemediation,omitempty"`
+
+	// EtcdPool reports the observed state of the dedicated etcd pool, if any.
+	// +optional
+	EtcdPool *EtcdPoolStatus `json:"etcdPool,omitempty"`
+}
+
+// EtcdPoolStatus defines the observed state of a dedicated etcd pool.
+type EtcdPoolStatus struct {
+	// Total number of non-terminated etcd machines in the pool.
+	// +optional
+	Replicas int32 `json:"replicas"`
+
+	// Total number of non-terminated etcd machines in the pool that have the desired template spec.
+	// +optional
+	UpdatedReplicas int32 `json:"updatedReplicas"`
+
+	// Total number of etcd machines in the pool which are provisioned and host a healthy etcd member.
+	// +optional
+	ReadyReplicas int32 `json:"readyReplicas"`
+
+	// Endpoints is the list of etcd client endpoints the control plane machines are configured with.
+	// Endpoints are updated only when all the etcd machines host a healthy etcd member, and they don't
+	// include the etcd members which are about to be removed.
+	// +optional
+	Endpoints []string `json:"endpoints,omitempty"`
 }
 
 // LastRemediationStatus  stores info about last remediation performed.
@@ -27,6 +27,85 @@ import (
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdPool) DeepCopyInto(out *EtcdPool) {
+	*out = *in
+	if in.Replicas != nil {
+		in, out := &in.Replicas, &out.Replicas
+		*out = new(int32)
+		**out = **in
+	}
+	in.MachineTemplate.DeepCopyInto(&out.MachineTemplate)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdPool.
+func (in *EtcdPool) DeepCopy() *EtcdPool {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdPoolMachineTemplate) DeepCopyInto(out *EtcdPoolMachineTemplate) {
+	*out = *in
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	out.InfrastructureRef = in.InfrastructureRef
+	out.BootstrapConfigRef = in.BootstrapConfigRef
+	if in.NodeDeletionTimeout != nil {
+		in, out := &in.NodeDeletionTimeout, &out.NodeDeletionTimeout
+		*out = new(v1.Duration)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdPoolMachineTemplate.
+func (in *EtcdPoolMachineTemplate) DeepCopy() *EtcdPoolMachineTemplate {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdPoolMachineTemplate)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *EtcdPoolStatus) DeepCopyInto(out *EtcdPoolStatus) {
+	*out = *in
+	if in.Endpoints != nil {
+		in, out := &in.Endpoints, &out.Endpoints
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new EtcdPoolStatus.
+func (in *EtcdPoolStatus) DeepCopy() *EtcdPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(EtcdPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InPlaceUpdate) DeepCopyInto(out *InPlaceUpdate) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InPlaceUpdate.
+func (in *InPlaceUpdate) DeepCopy() *InPlaceUpdate {
+	if in == nil {
+		return nil
+	}
+	out := new(InPlaceUpdate)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *KubeadmControlPlane) DeepCopyInto(out *KubeadmControlPlane) {
 	*out = *in
@@ -147,6 +226,11 @@ func (in *KubeadmControlPlaneSpec) DeepCopyInto(out *KubeadmControlPlaneSpec) {
 		*out = new(RemediationStrategy)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EtcdPool != nil {
+		in, out := &in.EtcdPool, &out.EtcdPool
+		*out = new(EtcdPool)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneSpec.
@@ -184,6 +268,11 @@ func (in *KubeadmControlPlaneStatus) DeepCopyInto(out *KubeadmControlPlaneStatus
 		*out = new(LastRemediationStatus)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.EtcdPool != nil {
+		in, out := &in.EtcdPool, &out.EtcdPool
+		*out = new(EtcdPoolStatus)
+		(*in).DeepCopyInto(*out)
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new KubeadmControlPlaneStatus.
@@ -448,6 +537,11 @@ func (in *RolloutStrategy) DeepCopyInto(out *RolloutStrategy) {
 		*out = new(RollingUpdate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.InPlaceUpdate != nil {
+		in, out := &in.InPlaceUpdate, &out.InPlaceUpdate
+		*out = new(InPlaceUpdate)
+		**out = **in
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RolloutStrategy.
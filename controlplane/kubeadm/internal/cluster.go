@@ -48,6 +48,7 @@ type ManagementCluster interface {
 	GetMachinesForCluster(ctx context.Context, cluster *clusterv1.Cluster, filters ...collections.Func) (collections.Machines, error)
 	GetMachinePoolsForCluster(ctx context.Context, cluster *clusterv1.Cluster) (*expv1.MachinePoolList, error)
 	GetWorkloadCluster(ctx context.Context, clusterKey client.ObjectKey) (WorkloadCluster, error)
+	GetEtcdPool(ctx context.Context, clusterKey client.ObjectKey, endpoints []string) (EtcdPool, error)
 }
 
 // Management holds operations on the management cluster.
@@ -162,6 +163,37 @@ func (m *Management) GetWorkloadCluster(ctx context.Context, clusterKey client.O
 	}, nil
 }
 
+// GetEtcdPool builds an etcd pool object.
+// The etcd pool connects directly to the given endpoints, using a client certificate signed by the etcd CA.
+func (m *Management) GetEtcdPool(ctx context.Context, clusterKey client.ObjectKey, endpoints []string) (EtcdPool, error) {
+	// Retrieves the etcd CA key Pair
+	crtData, keyData, err := m.getEtcdCAKeyPair(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	if keyData == nil {
+		return nil, errors.Errorf("etcd tls key does not exist for cluster %s/%s", clusterKey.Namespace, clusterKey.Name)
+	}
+
+	clientKey, err := m.Tracker.GetEtcdClientCertificateKey(ctx, clusterKey)
+	if err != nil {
+		return nil, err
+	}
+	clientCert, err := generateClientCert(crtData, keyData, clientKey)
+	if err != nil {
+		return nil, err
+	}
+
+	caPool := x509.NewCertPool()
+	caPool.AppendCertsFromPEM(crtData)
+	tlsConfig := &tls.Config{
+		RootCAs:      caPool,
+		Certificates: []tls.Certificate{clientCert},
+		MinVersion:   tls.VersionTLS12,
+	}
+	return newEtcdPool(endpoints, tlsConfig, m.EtcdDialTimeout, m.EtcdCallTimeout), nil
+}
+
 func (m *Management) getEtcdCAKeyPair(ctx context.Context, clusterKey client.ObjectKey) ([]byte, []byte, error) {
 	etcdCASecret := &corev1.Secret{}
 	etcdCAObjectKey := client.ObjectKey{
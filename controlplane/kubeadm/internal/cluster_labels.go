@@ -39,3 +39,22 @@ func ControlPlaneMachineLabelsForCluster(kcp *controlplanev1.KubeadmControlPlane
 	labels[clusterv1.MachineControlPlaneNameLabel] = format.MustFormatValue(kcp.Name)
 	return labels
 }
+
+// EtcdPoolMachineLabelsForCluster returns a set of labels to add to an etcd pool machine for this specific cluster.
+func EtcdPoolMachineLabelsForCluster(kcp *controlplanev1.KubeadmControlPlane, clusterName string) map[string]string {
+	labels := map[string]string{}
+
+	// Add the labels from the etcd pool MachineTemplate.
+	// Note: we intentionally don't use the map directly to ensure we don't modify the map in KCP.
+	if kcp.Spec.EtcdPool != nil {
+		for k, v := range kcp.Spec.EtcdPool.MachineTemplate.ObjectMeta.Labels {
+			labels[k] = v
+		}
+	}
+
+	// Always force these labels over the ones coming from the spec.
+	labels[clusterv1.ClusterNameLabel] = clusterName
+	// Note: MustFormatValue is used here as the label value can be a hash if the control plane name is longer than 63 characters.
+	labels[controlplanev1.EtcdPoolNameLabel] = format.MustFormatValue(kcp.Name)
+	return labels
+}
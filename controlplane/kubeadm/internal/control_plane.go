@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"path"
 
 	"github.com/pkg/errors"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
@@ -30,9 +31,13 @@ import (
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controllers/external"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/failuredomains"
 	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/util/version"
 )
 
 // ControlPlane holds business logic around control planes.
@@ -52,6 +57,9 @@ type ControlPlane struct {
 	KubeadmConfigs map[string]*bootstrapv1.KubeadmConfig
 	InfraResources map[string]*unstructured.Unstructured
 
+	// EtcdMachines are the machines hosting the dedicated etcd pool, if KCP.Spec.EtcdPool is set.
+	EtcdMachines collections.Machines
+
 	managementCluster ManagementCluster
 	workloadCluster   WorkloadCluster
 }
@@ -142,6 +150,8 @@ func (c *ControlPlane) NextFailureDomainForScaleUp(ctx context.Context) *string
 func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
 	bootstrapSpec.JoinConfiguration = nil
+	c.setEtcdPoolExternalEtcd(bootstrapSpec)
+	c.setEtcdLearnerMode(bootstrapSpec)
 	return bootstrapSpec
 }
 
@@ -149,12 +159,137 @@ func (c *ControlPlane) InitialControlPlaneConfig() *bootstrapv1.KubeadmConfigSpe
 func (c *ControlPlane) JoinControlPlaneConfig() *bootstrapv1.KubeadmConfigSpec {
 	bootstrapSpec := c.KCP.Spec.KubeadmConfigSpec.DeepCopy()
 	bootstrapSpec.InitConfiguration = nil
+	c.setEtcdPoolExternalEtcd(bootstrapSpec)
+	c.setEtcdLearnerMode(bootstrapSpec)
 	// NOTE: For the joining we are preserving the ClusterConfiguration in order to determine if the
 	// cluster is using an external etcd in the kubeadm bootstrap provider (even if this is not required by kubeadm Join).
 	// TODO: Determine if this copy of cluster configuration can be used for rollouts (thus allowing to remove the annotation at machine level)
 	return bootstrapSpec
 }
 
+// setEtcdPoolExternalEtcd points the given KubeadmConfigSpec to the etcd pool, if any.
+func (c *ControlPlane) setEtcdPoolExternalEtcd(bootstrapSpec *bootstrapv1.KubeadmConfigSpec) {
+	if !c.HasEtcdPool() {
+		return
+	}
+	if bootstrapSpec.ClusterConfiguration == nil {
+		bootstrapSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+	}
+	bootstrapSpec.ClusterConfiguration.Etcd.External = c.EtcdPoolExternalEtcd()
+}
+
+// setEtcdLearnerMode explicitly enables the kubeadm EtcdLearnerMode feature gate in the given KubeadmConfigSpec,
+// if etcd learners are supported and the feature gate is not set in the KubeadmControlPlane.
+func (c *ControlPlane) setEtcdLearnerMode(bootstrapSpec *bootstrapv1.KubeadmConfigSpec) {
+	if !c.IsEtcdLearnerModeSupported() {
+		return
+	}
+	if bootstrapSpec.ClusterConfiguration == nil {
+		bootstrapSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+	}
+	if _, ok := bootstrapSpec.ClusterConfiguration.FeatureGates[kubeadm.EtcdLearnerModeFeatureGate]; ok {
+		return
+	}
+	if bootstrapSpec.ClusterConfiguration.FeatureGates == nil {
+		bootstrapSpec.ClusterConfiguration.FeatureGates = map[string]bool{}
+	}
+	bootstrapSpec.ClusterConfiguration.FeatureGates[kubeadm.EtcdLearnerModeFeatureGate] = true
+}
+
+// IsEtcdLearnerModeSupported returns true if control plane machines can join the stacked etcd cluster as learners,
+// i.e. if etcd is managed by KCP, kubeadm supports the EtcdLearnerMode feature gate and etcd is >= v3.4.
+func (c *ControlPlane) IsEtcdLearnerModeSupported() bool {
+	if !c.IsEtcdManaged() {
+		return false
+	}
+	kubernetesVersion, err := version.ParseMajorMinorPatchTolerant(c.KCP.Spec.Version)
+	if err != nil {
+		return false
+	}
+	etcdImageTag := ""
+	if clusterConfiguration := c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration; clusterConfiguration != nil && clusterConfiguration.Etcd.Local != nil {
+		etcdImageTag = clusterConfiguration.Etcd.Local.ImageTag
+	}
+	return kubeadm.IsEtcdLearnerModeSupported(kubernetesVersion, etcdImageTag)
+}
+
+// KubeadmFeatureGates returns the kubeadm feature gates for the control plane; if etcd learners are supported,
+// the EtcdLearnerMode feature gate is enabled unless explicitly set in the KubeadmControlPlane.
+func (c *ControlPlane) KubeadmFeatureGates() map[string]bool {
+	var featureGates map[string]bool
+	if c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
+		featureGates = c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.FeatureGates
+	}
+	if !c.IsEtcdLearnerModeSupported() {
+		return featureGates
+	}
+	if _, ok := featureGates[kubeadm.EtcdLearnerModeFeatureGate]; ok {
+		return featureGates
+	}
+	learnerModeFeatureGates := make(map[string]bool, len(featureGates)+1)
+	for name, enabled := range featureGates {
+		learnerModeFeatureGates[name] = enabled
+	}
+	learnerModeFeatureGates[kubeadm.EtcdLearnerModeFeatureGate] = true
+	return learnerModeFeatureGates
+}
+
+// IsEtcdLearnerModeEnabled returns true if control plane machines join the stacked etcd cluster as learners.
+func (c *ControlPlane) IsEtcdLearnerModeEnabled() bool {
+	return c.IsEtcdLearnerModeSupported() && c.KubeadmFeatureGates()[kubeadm.EtcdLearnerModeFeatureGate]
+}
+
+// HasEtcdPool returns true if the control plane relies on a dedicated etcd pool managed by KCP.
+func (c *ControlPlane) HasEtcdPool() bool {
+	return c.KCP.Spec.EtcdPool != nil
+}
+
+// ProvisionedEtcdPoolMachines returns the etcd pool machines which are provisioned and are not being deleted.
+func (c *ControlPlane) ProvisionedEtcdPoolMachines() collections.Machines {
+	return c.EtcdMachines.Filter(collections.Not(collections.HasDeletionTimestamp), isEtcdPoolMachineProvisioned)
+}
+
+// ReadyEtcdPoolMachines returns the etcd pool machines which are provisioned, are not being deleted
+// and host a healthy etcd member.
+func (c *ControlPlane) ReadyEtcdPoolMachines() collections.Machines {
+	return c.ProvisionedEtcdPoolMachines().Filter(hasHealthyEtcdMember)
+}
+
+// EtcdPoolEndpoints returns the client endpoints of the etcd pool the control plane machines should be configured with.
+// NOTE: The endpoints are stored in the KubeadmControlPlane status, and they are updated by the etcd pool
+// reconciliation only when the etcd pool is stable, so they don't change with the etcd members health.
+func (c *ControlPlane) EtcdPoolEndpoints() []string {
+	if c.KCP.Status.EtcdPool == nil {
+		return nil
+	}
+	return c.KCP.Status.EtcdPool.Endpoints
+}
+
+// EtcdPoolExternalEtcd returns the external etcd configuration to be used by control plane machines
+// to connect to the etcd pool.
+func (c *ControlPlane) EtcdPoolExternalEtcd() *bootstrapv1.ExternalEtcd {
+	certificatesDir := secret.DefaultCertificatesDir
+	if c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration != nil && c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.CertificatesDir != "" {
+		certificatesDir = c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.CertificatesDir
+	}
+	return &bootstrapv1.ExternalEtcd{
+		Endpoints: c.EtcdPoolEndpoints(),
+		CAFile:    path.Join(certificatesDir, "etcd", "ca.crt"),
+		CertFile:  path.Join(certificatesDir, "apiserver-etcd-client.crt"),
+		KeyFile:   path.Join(certificatesDir, "apiserver-etcd-client.key"),
+	}
+}
+
+// isEtcdPoolMachineProvisioned returns true if the etcd pool machine is provisioned and reports an address.
+func isEtcdPoolMachineProvisioned(machine *clusterv1.Machine) bool {
+	return machine.Status.BootstrapReady && machine.Status.InfrastructureReady && EtcdPoolEndpointForMachine(machine) != ""
+}
+
+// hasHealthyEtcdMember returns true if the etcd pool machine hosts a healthy etcd member.
+func hasHealthyEtcdMember(machine *clusterv1.Machine) bool {
+	return conditions.IsTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+}
+
 // HasDeletingMachine returns true if any machine in the control plane is in the process of being deleted.
 func (c *ControlPlane) HasDeletingMachine() bool {
 	return len(c.Machines.Filter(collections.HasDeletionTimestamp)) > 0
@@ -176,6 +311,9 @@ func (c *ControlPlane) MachinesNeedingRollout() (collections.Machines, map[strin
 	rolloutReasons := map[string]string{}
 	for _, m := range machines {
 		reason, needsRollout := NeedsRollout(&c.reconciliationTime, c.KCP.Spec.RolloutAfter, c.KCP.Spec.RolloutBefore, c.InfraResources, c.KubeadmConfigs, c.KCP, m)
+		if !needsRollout && c.HasEtcdPool() {
+			reason, needsRollout = c.needsEtcdPoolEndpointsRollout(m)
+		}
 		if needsRollout {
 			machinesNeedingRollout.Insert(m)
 			rolloutReasons[m.Name] = reason
@@ -190,6 +328,9 @@ func (c *ControlPlane) UpToDateMachines() collections.Machines {
 	upToDateMachines := make(collections.Machines, len(c.Machines))
 	for _, m := range c.Machines {
 		_, needsRollout := NeedsRollout(&c.reconciliationTime, c.KCP.Spec.RolloutAfter, c.KCP.Spec.RolloutBefore, c.InfraResources, c.KubeadmConfigs, c.KCP, m)
+		if !needsRollout && c.HasEtcdPool() {
+			_, needsRollout = c.needsEtcdPoolEndpointsRollout(m)
+		}
 		if !needsRollout {
 			upToDateMachines.Insert(m)
 		}
@@ -197,6 +338,18 @@ func (c *ControlPlane) UpToDateMachines() collections.Machines {
 	return upToDateMachines
 }
 
+// needsEtcdPoolEndpointsRollout returns true if the machine has been bootstrapped with a set of etcd pool
+// endpoints different from the current one.
+func (c *ControlPlane) needsEtcdPoolEndpointsRollout(machine *clusterv1.Machine) (string, bool) {
+	endpoints := c.EtcdPoolEndpoints()
+	// Do not trigger rollouts while the etcd pool is not reporting any endpoint, e.g. while it is being provisioned.
+	if len(endpoints) == 0 {
+		return "", false
+	}
+	reason, matches := matchesEtcdPoolEndpoints(endpoints, machine)
+	return reason, !matches
+}
+
 // getInfraResources fetches the external infrastructure resource for each machine in the collection and returns a map of machine.Name -> infraResource.
 func getInfraResources(ctx context.Context, cl client.Client, machines collections.Machines) (map[string]*unstructured.Unstructured, error) {
 	result := map[string]*unstructured.Unstructured{}
@@ -234,7 +387,11 @@ func getKubeadmConfigs(ctx context.Context, cl client.Client, machines collectio
 }
 
 // IsEtcdManaged returns true if the control plane relies on a managed etcd.
+// NOTE: When using an etcd pool, etcd is not hosted on the control plane machines.
 func (c *ControlPlane) IsEtcdManaged() bool {
+	if c.HasEtcdPool() {
+		return false
+	}
 	return c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration == nil || c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External == nil
 }
 
@@ -24,6 +24,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
 	"sigs.k8s.io/cluster-api/util/conditions"
@@ -176,3 +177,86 @@ func machine(name string, opts ...machineOpt) *clusterv1.Machine {
 	}
 	return m
 }
+
+func TestKubeadmFeatureGates(t *testing.T) {
+	tests := []struct {
+		name                 string
+		version              string
+		clusterConfiguration *bootstrapv1.ClusterConfiguration
+		expectedFeatureGates map[string]bool
+		expectedEnabled      bool
+	}{
+		{
+			name:                 "does not enable EtcdLearnerMode if the Kubernetes version does not support it",
+			version:              "v1.26.0",
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{FeatureGates: map[string]bool{"Foo": true}},
+			expectedFeatureGates: map[string]bool{"Foo": true},
+			expectedEnabled:      false,
+		},
+		{
+			name:                 "enables EtcdLearnerMode if supported",
+			version:              "v1.29.0",
+			expectedFeatureGates: map[string]bool{"EtcdLearnerMode": true},
+			expectedEnabled:      true,
+		},
+		{
+			name:                 "enables EtcdLearnerMode preserving other feature gates",
+			version:              "v1.29.0",
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{FeatureGates: map[string]bool{"Foo": true}},
+			expectedFeatureGates: map[string]bool{"Foo": true, "EtcdLearnerMode": true},
+			expectedEnabled:      true,
+		},
+		{
+			name:                 "does not override EtcdLearnerMode if explicitly set",
+			version:              "v1.29.0",
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{FeatureGates: map[string]bool{"EtcdLearnerMode": false}},
+			expectedFeatureGates: map[string]bool{"EtcdLearnerMode": false},
+			expectedEnabled:      false,
+		},
+		{
+			name:    "does not enable EtcdLearnerMode if the etcd version does not support learners",
+			version: "v1.29.0",
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				Etcd: bootstrapv1.Etcd{Local: &bootstrapv1.LocalEtcd{ImageMeta: bootstrapv1.ImageMeta{ImageTag: "3.3.17-0"}}},
+			},
+			expectedFeatureGates: nil,
+			expectedEnabled:      false,
+		},
+		{
+			name:    "does not enable EtcdLearnerMode when using external etcd",
+			version: "v1.29.0",
+			clusterConfiguration: &bootstrapv1.ClusterConfiguration{
+				Etcd: bootstrapv1.Etcd{External: &bootstrapv1.ExternalEtcd{}},
+			},
+			expectedFeatureGates: nil,
+			expectedEnabled:      false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			c := &ControlPlane{
+				KCP: &controlplanev1.KubeadmControlPlane{
+					Spec: controlplanev1.KubeadmControlPlaneSpec{
+						Version: tt.version,
+						KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+							ClusterConfiguration: tt.clusterConfiguration,
+						},
+					},
+				},
+			}
+
+			g.Expect(c.KubeadmFeatureGates()).To(Equal(tt.expectedFeatureGates))
+			g.Expect(c.IsEtcdLearnerModeEnabled()).To(Equal(tt.expectedEnabled))
+
+			// Machines joining the control plane should use the same feature gates.
+			joinConfig := c.JoinControlPlaneConfig()
+			if tt.expectedFeatureGates == nil {
+				g.Expect(joinConfig.ClusterConfiguration == nil || joinConfig.ClusterConfiguration.FeatureGates == nil).To(BeTrue())
+				return
+			}
+			g.Expect(joinConfig.ClusterConfiguration.FeatureGates).To(Equal(tt.expectedFeatureGates))
+		})
+	}
+}
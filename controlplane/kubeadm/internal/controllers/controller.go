@@ -54,6 +54,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
 	"sigs.k8s.io/cluster-api/util/secret"
+	"sigs.k8s.io/cluster-api/util/sharding"
 	"sigs.k8s.io/cluster-api/util/version"
 )
 
@@ -83,6 +84,9 @@ type KubeadmControlPlaneReconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
+
 	managementCluster         internal.ManagementCluster
 	managementClusterUncached internal.ManagementCluster
 	ssaCache                  ssa.Cache
@@ -103,11 +107,15 @@ func (r *KubeadmControlPlaneReconciler) SetupWithManager(ctx context.Context, mg
 					predicates.ClusterUnpausedAndInfrastructureReady(ctrl.LoggerFrom(ctx)),
 				),
 			),
-		).Build(r)
+		).Build(r.Sharder.Reconciler(mgr.GetClient(), &controlplanev1.KubeadmControlPlane{}, r))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if err := r.Sharder.Watch(c, handler.EnqueueRequestsFromMapFunc(r.ClusterToKubeadmControlPlane)); err != nil {
+		return errors.Wrap(err, "failed to watch claimed shards")
+	}
+
 	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("kubeadmcontrolplane-controller")
 	r.ssaCache = ssa.NewCache()
@@ -300,6 +308,15 @@ func (r *KubeadmControlPlaneReconciler) initControlPlaneScope(ctx context.Contex
 		log.Error(err, "failed to initialize control plane scope")
 		return nil, false, err
 	}
+
+	// Read etcd pool machines, if any.
+	// NOTE: Etcd pool machines are read also when the etcd pool is not defined, so they are deleted together with the KCP.
+	etcdMachines, err := r.managementClusterUncached.GetMachinesForCluster(ctx, cluster, collections.OwnedMachines(kcp), isEtcdPoolMachine)
+	if err != nil {
+		log.Error(err, "failed to retrieve etcd pool machines for cluster")
+		return nil, false, err
+	}
+	controlPlane.EtcdMachines = etcdMachines
 	return controlPlane, false, nil
 }
 
@@ -313,6 +330,7 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.EtcdPoolReadyCondition,
 		),
 	)
 
@@ -328,6 +346,8 @@ func patchKubeadmControlPlane(ctx context.Context, patchHelper *patch.Helper, kc
 			controlplanev1.MachinesReadyCondition,
 			controlplanev1.AvailableCondition,
 			controlplanev1.CertificatesAvailableCondition,
+			controlplanev1.EtcdPoolReadyCondition,
+			controlplanev1.EtcdLearnersPromotedCondition,
 		}},
 		patch.WithStatusObservedGeneration{},
 	)
@@ -394,6 +414,19 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 		return result, err
 	}
 
+	// Promotes etcd members which joined the etcd cluster as learners to voting members.
+	// NOTE: This happens after remediation, so learners hosted on unhealthy machines are deleted instead of being promoted.
+	// NOTE: Scale up and scale down operations are blocked until learners are promoted, see preflightChecks.
+	if err := r.reconcileEtcdLearners(ctx, controlPlane); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Reconcile the dedicated etcd pool, if any; operations on the etcd pool take precedence over
+	// the control plane machines rollout, and the control plane is initialized only after the etcd pool is provisioned.
+	if result, err := r.reconcileEtcdPool(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
 	// Control plane machines rollout due to configuration changes (e.g. upgrades) takes precedence over other operations.
 	machinesNeedingRollout, rolloutReasons := controlPlane.MachinesNeedingRollout()
 	switch {
@@ -402,6 +435,13 @@ func (r *KubeadmControlPlaneReconciler) reconcile(ctx context.Context, controlPl
 		for _, rolloutReason := range rolloutReasons {
 			reasons = append(reasons, rolloutReason)
 		}
+		// If all the machines needing rollout only have changes to the control plane components configuration,
+		// and in-place updates are enabled, update them without replacing the machines.
+		if machinesNeedingInPlaceUpdate := controlPlane.MachinesNeedingInPlaceUpdate(machinesNeedingRollout); len(machinesNeedingInPlaceUpdate) == len(machinesNeedingRollout) {
+			log.Info(fmt.Sprintf("Updating Control Plane machines in place: %s", strings.Join(reasons, ",")), "machinesNeedingInPlaceUpdate", machinesNeedingInPlaceUpdate.Names())
+			conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.InPlaceUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Updating %d replicas in place (%d replicas up to date)", len(machinesNeedingInPlaceUpdate), len(controlPlane.Machines)-len(machinesNeedingInPlaceUpdate))
+			return r.updateControlPlaneInPlace(ctx, controlPlane, machinesNeedingInPlaceUpdate)
+		}
 		log.Info(fmt.Sprintf("Rolling out Control Plane machines: %s", strings.Join(reasons, ",")), "machinesNeedingRollout", machinesNeedingRollout.Names())
 		conditions.MarkFalse(controlPlane.KCP, controlplanev1.MachinesSpecUpToDateCondition, controlplanev1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Rolling %d replicas with outdated spec (%d replicas up to date)", len(machinesNeedingRollout), len(controlPlane.Machines)-len(machinesNeedingRollout))
 		return r.upgradeControlPlane(ctx, controlPlane, machinesNeedingRollout)
@@ -511,8 +551,11 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, con
 	log := ctrl.LoggerFrom(ctx)
 	log.Info("Reconcile KubeadmControlPlane deletion")
 
-	// If no control plane machines remain, remove the finalizer
+	// If no control plane machines remain, delete the etcd pool machines, if any, and then remove the finalizer
 	if len(controlPlane.Machines) == 0 {
+		if len(controlPlane.EtcdMachines) > 0 {
+			return r.deleteEtcdPoolMachines(ctx, controlPlane)
+		}
 		controllerutil.RemoveFinalizer(controlPlane.KCP, controlplanev1.KubeadmControlPlaneFinalizer)
 		return ctrl.Result{}, nil
 	}
@@ -543,8 +586,8 @@ func (r *KubeadmControlPlaneReconciler) reconcileDelete(ctx context.Context, con
 			return ctrl.Result{}, err
 		}
 	}
-	// Verify that only control plane machines remain
-	if len(allMachines) != len(controlPlane.Machines) || len(allMachinePools.Items) != 0 {
+	// Verify that only control plane machines and etcd pool machines remain
+	if len(allMachines) != len(controlPlane.Machines)+len(controlPlane.EtcdMachines) || len(allMachinePools.Items) != 0 {
 		log.Info("Waiting for worker nodes to be deleted first")
 		conditions.MarkFalse(controlPlane.KCP, controlplanev1.ResizedCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "Waiting for worker nodes to be deleted first")
 		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
@@ -758,6 +801,69 @@ func (r *KubeadmControlPlaneReconciler) reconcileEtcdMembers(ctx context.Context
 	return nil
 }
 
+// reconcileEtcdLearners promotes etcd members which joined the etcd cluster as learners (non-voting members)
+// to voting members, once they are in sync with the leader.
+//
+// NOTE: this func uses KCP conditions, it is required to call reconcileControlPlaneConditions before this.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdLearners(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// If etcd is not managed by KCP, or it does not support learners (etcd < v3.4), this is a no-op.
+	if !controlPlane.IsEtcdLearnerModeSupported() {
+		return nil
+	}
+
+	// If there is no KCP-owned control-plane machines, then control-plane has not been initialized yet.
+	if controlPlane.Machines.Len() == 0 {
+		return nil
+	}
+
+	// Learners are surfaced using the EtcdClusterHealthyCondition; if this condition is true, and there are no
+	// learners waiting to be promoted from previous reconciliations, return early.
+	if conditions.IsTrue(controlPlane.KCP, controlplanev1.EtcdClusterHealthyCondition) &&
+		!conditions.IsFalse(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition) {
+		return nil
+	}
+
+	// Collect the node names for the healthy machines hosting etcd members; learners hosted on machines
+	// marked as unhealthy by MHC are not promoted, given that those machines are going to be remediated.
+	nodeNames := []string{}
+	for _, machine := range controlPlane.HealthyMachines() {
+		if machine.Status.NodeRef == nil || !machine.DeletionTimestamp.IsZero() {
+			continue
+		}
+		nodeNames = append(nodeNames, machine.Status.NodeRef.Name)
+	}
+	if len(nodeNames) == 0 {
+		return nil
+	}
+
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return errors.Wrap(err, "cannot get remote client to workload cluster")
+	}
+
+	pendingLearners, err := workloadCluster.PromoteEtcdLearners(ctx, nodeNames)
+	if err != nil {
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition, controlplanev1.EtcdLearnersPromotionFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return errors.Wrap(err, "failed attempt to promote etcd learners")
+	}
+
+	if len(pendingLearners) > 0 {
+		log.Info("Waiting for etcd learners to be in sync with the leader", "learners", pendingLearners)
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition, controlplanev1.WaitingForEtcdLearnersReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for etcd learners %s to be in sync with the leader", strings.Join(pendingLearners, ", "))
+		return nil
+	}
+
+	// NOTE: we are checking the condition already exists in order to avoid to set this condition when
+	// learners have never been observed.
+	if conditions.Has(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition) {
+		conditions.MarkTrue(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition)
+	}
+	return nil
+}
+
 func (r *KubeadmControlPlaneReconciler) reconcileCertificateExpiries(ctx context.Context, controlPlane *internal.ControlPlane) error {
 	log := ctrl.LoggerFrom(ctx)
 
@@ -1174,6 +1174,148 @@ func TestReconcileCertificateExpiries(t *testing.T) {
 	g.Expect(actualKubeadmConfig.Annotations).ToNot(ContainElement(clusterv1.MachineCertificatesExpiryDateAnnotation))
 }
 
+func TestReconcileEtcdLearners(t *testing.T) {
+	cluster := newCluster(&types.NamespacedName{Name: "foo", Namespace: metav1.NamespaceDefault})
+	machine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: "machine",
+		},
+		Spec: clusterv1.MachineSpec{
+			InfrastructureRef: corev1.ObjectReference{
+				Kind:       "GenericMachine",
+				APIVersion: "generic.io/v1",
+				Namespace:  metav1.NamespaceDefault,
+				Name:       "machine-infra",
+			},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{
+				Name: "machine",
+			},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		version           string
+		etcdImageTag      string
+		machineConditions clusterv1.Conditions
+		kcpConditions     clusterv1.Conditions
+		learners          []string
+		expectedCondition *clusterv1.Condition
+	}{
+		{
+			name:    "no-op if the Kubernetes version does not support etcd learners",
+			version: "v1.26.0",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			learners: []string{"machine"},
+		},
+		{
+			name:         "no-op if the etcd version does not support learners",
+			etcdImageTag: "3.3.17-0",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			learners: []string{"machine"},
+		},
+		{
+			name: "does not promote learners hosted on machines to be remediated",
+			machineConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(clusterv1.MachineHealthCheckSucceededCondition, clusterv1.MachineHasFailureReason, clusterv1.ConditionSeverityWarning, ""),
+				*conditions.FalseCondition(clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, ""),
+			},
+			kcpConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			learners: []string{"machine"},
+		},
+		{
+			name: "no-op if the etcd cluster is healthy",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition),
+			},
+			learners: []string{"machine"},
+		},
+		{
+			name: "waits for learners to be in sync with the leader",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			learners:          []string{"machine"},
+			expectedCondition: conditions.FalseCondition(controlplanev1.EtcdLearnersPromotedCondition, controlplanev1.WaitingForEtcdLearnersReason, clusterv1.ConditionSeverityInfo, "Waiting for etcd learners machine to be in sync with the leader"),
+		},
+		{
+			name: "does not set the condition if learners have never been observed",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityError, ""),
+			},
+		},
+		{
+			name: "reports learners have been promoted",
+			kcpConditions: clusterv1.Conditions{
+				*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition),
+				*conditions.FalseCondition(controlplanev1.EtcdLearnersPromotedCondition, controlplanev1.WaitingForEtcdLearnersReason, clusterv1.ConditionSeverityInfo, ""),
+			},
+			expectedCondition: conditions.TrueCondition(controlplanev1.EtcdLearnersPromotedCondition),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version: "v1.29.0",
+				},
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Initialized: true,
+					Conditions:  tt.kcpConditions,
+				},
+			}
+			if tt.version != "" {
+				kcp.Spec.Version = tt.version
+			}
+			if tt.etcdImageTag != "" {
+				kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{
+					Etcd: bootstrapv1.Etcd{
+						Local: &bootstrapv1.LocalEtcd{
+							ImageMeta: bootstrapv1.ImageMeta{
+								ImageTag: tt.etcdImageTag,
+							},
+						},
+					},
+				}
+			}
+			machine := machine.DeepCopy()
+			machine.Status.Conditions = tt.machineConditions
+
+			fakeClient := newFakeClient()
+			managementCluster := &fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					EtcdLearnersResult: tt.learners,
+				},
+			}
+			r := &KubeadmControlPlaneReconciler{
+				Client:              fakeClient,
+				SecretCachingClient: fakeClient,
+				managementCluster:   managementCluster,
+			}
+
+			controlPlane, err := internal.NewControlPlane(ctx, managementCluster, fakeClient, cluster, kcp, collections.FromMachines(machine))
+			g.Expect(err).ToNot(HaveOccurred())
+
+			g.Expect(r.reconcileEtcdLearners(ctx, controlPlane)).To(Succeed())
+			if tt.expectedCondition == nil {
+				g.Expect(conditions.Has(kcp, controlplanev1.EtcdLearnersPromotedCondition)).To(BeFalse())
+				return
+			}
+			g.Expect(*conditions.Get(kcp, controlplanev1.EtcdLearnersPromotedCondition)).To(conditions.MatchCondition(*tt.expectedCondition))
+		})
+	}
+}
+
 func TestReconcileInitializeControlPlane(t *testing.T) {
 	setup := func(t *testing.T, g *WithT) *corev1.Namespace {
 		t.Helper()
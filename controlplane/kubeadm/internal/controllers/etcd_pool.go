@@ -0,0 +1,539 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"crypto/x509"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apiserver/pkg/storage/names"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/util/ssa"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/failuredomains"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+const (
+	// etcdPoolClientCertificateCommonName is the common name of the certificate used by the API servers
+	// to connect to the etcd pool; it matches the one generated by kubeadm for the apiserver-etcd-client certificate.
+	etcdPoolClientCertificateCommonName = "kube-apiserver-etcd-client"
+)
+
+// isEtcdPoolMachine returns true if the machine is part of the etcd pool of a KubeadmControlPlane.
+func isEtcdPoolMachine(machine *clusterv1.Machine) bool {
+	_, ok := machine.Labels[controlplanev1.EtcdPoolNameLabel]
+	return ok
+}
+
+// reconcileEtcdPool reconciles the dedicated etcd pool of a KubeadmControlPlane, if any.
+// The etcd pool is scaled and rolled out one machine at a time; while an etcd pool operation is in progress
+// a non-zero result is returned, thus preventing any other operation on the control plane machines.
+// Vice versa, etcd members are removed only after the control plane machines are rolled out with the
+// current etcd pool endpoints, which don't include the etcd members to be removed.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdPool(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !controlPlane.HasEtcdPool() {
+		return ctrl.Result{}, nil
+	}
+	kcp := controlPlane.KCP
+	etcdPool := kcp.Spec.EtcdPool
+
+	// Make sure to reconcile the external references of the etcd pool machine template.
+	if err := r.reconcileExternalReference(ctx, controlPlane.Cluster, &etcdPool.MachineTemplate.InfrastructureRef); err != nil {
+		return ctrl.Result{}, err
+	}
+	if err := r.reconcileExternalReference(ctx, controlPlane.Cluster, &etcdPool.MachineTemplate.BootstrapConfigRef); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Ensure the certificate used by the API servers to connect to the etcd pool exists.
+	if err := r.reconcileEtcdPoolClientCertificate(ctx, controlPlane); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	machines := controlPlane.EtcdMachines
+	desiredReplicas := int(*etcdPool.Replicas)
+
+	// Wait for etcd pool machines being deleted to go away before performing further operations.
+	if deletingMachines := machines.Filter(collections.HasDeletionTimestamp); len(deletingMachines) > 0 {
+		log.Info("Waiting for etcd pool machines to be deleted", "Machines", deletingMachines.Names())
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolScalingDownReason, clusterv1.ConditionSeverityInfo, "Waiting for %d etcd machines to be deleted", len(deletingMachines))
+		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+	}
+
+	etcdPoolClient, err := r.managementCluster.GetEtcdPool(ctx, util.ObjectKey(controlPlane.Cluster), internal.EtcdPoolEndpoints(machines))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to create client to the etcd pool")
+	}
+
+	// Update the health of the etcd members hosted on the etcd pool machines.
+	if err := r.updateEtcdPoolMemberConditions(ctx, machines, etcdPoolClient); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Wait for all the etcd pool machines to be provisioned and to host a healthy etcd member before performing
+	// further operations, so that the etcd cluster membership changes one member at a time.
+	// The etcd member of a new machine is added as soon as the machine is provisioned, so etcd can join
+	// the existing etcd cluster when starting on the machine.
+	readyMachines := controlPlane.ReadyEtcdPoolMachines()
+	if len(readyMachines) != len(machines) {
+		for _, m := range controlPlane.ProvisionedEtcdPoolMachines().Difference(readyMachines) {
+			if err := etcdPoolClient.AddEtcdMemberForMachine(ctx, m); err != nil {
+				conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolMemberAddFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return ctrl.Result{}, errors.Wrapf(err, "failed to add etcd member for machine %s", m.Name)
+			}
+		}
+		log.Info("Waiting for etcd pool machines to be provisioned and to host a healthy etcd member", "Ready", len(readyMachines), "Existing", len(machines))
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolWaitingForMembersReason, clusterv1.ConditionSeverityInfo, "Waiting for %d etcd machines to be provisioned and to host a healthy etcd member", len(machines)-len(readyMachines))
+		r.markWaitingForEtcdPool(controlPlane)
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
+	machinesNeedingRollout, err := r.etcdPoolMachinesNeedingRollout(ctx, controlPlane)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// We are scaling up, or creating a replacement for an outdated machine.
+	numMachines := len(machines)
+	if numMachines < desiredReplicas || (numMachines == desiredReplicas && len(machinesNeedingRollout) > 0) {
+		if len(machinesNeedingRollout) > 0 {
+			log.Info("Rolling out etcd pool machines", "machinesNeedingRollout", machinesNeedingRollout.Names())
+			conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolRollingUpdateInProgressReason, clusterv1.ConditionSeverityWarning, "Rolling %d etcd machines with outdated spec", len(machinesNeedingRollout))
+		} else {
+			log.Info("Scaling up etcd pool", "Desired", desiredReplicas, "Existing", numMachines)
+			conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolScalingUpReason, clusterv1.ConditionSeverityInfo, "Scaling up etcd pool to %d replicas (actual %d)", desiredReplicas, numMachines)
+		}
+		r.markWaitingForEtcdPool(controlPlane)
+		if err := r.createEtcdPoolMachine(ctx, controlPlane); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	// Select the machines to be removed if there are more machines than desired, e.g. after the replacement
+	// for an outdated machine has been created, and configure the control plane machines with the endpoints of
+	// the etcd members which are going to remain in the etcd pool.
+	// NOTE: The endpoints are updated only when the etcd pool is stable, and before removing etcd members, so
+	// the control plane machines are rolled out once for each etcd pool operation, and the API servers
+	// never use an etcd member which is being removed.
+	machinesToRemove := selectEtcdPoolMachinesForScaleDown(machines, machinesNeedingRollout, numMachines-desiredReplicas)
+	setEtcdPoolEndpoints(kcp, internal.EtcdPoolEndpoints(machines.Difference(collections.FromMachines(machinesToRemove...))))
+
+	// We are scaling down, or deleting an outdated machine after its replacement has been created.
+	if len(machinesToRemove) > 0 {
+		// Before removing an etcd member, wait for the control plane machines to be rolled out with the current
+		// etcd pool endpoints.
+		// NOTE: returning a zero result allows the control plane rollout to proceed.
+		if controlPlaneMachinesNeedingRollout, _ := controlPlane.MachinesNeedingRollout(); len(controlPlaneMachinesNeedingRollout) > 0 || controlPlane.HasDeletingMachine() {
+			log.Info("Waiting for control plane machines to be rolled out before scaling down etcd pool")
+			conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolWaitingForControlPlaneReason, clusterv1.ConditionSeverityInfo, "Waiting for control plane machines to be rolled out")
+			return ctrl.Result{}, nil
+		}
+
+		log.Info("Scaling down etcd pool", "Desired", desiredReplicas, "Existing", numMachines)
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolScalingDownReason, clusterv1.ConditionSeverityInfo, "Scaling down etcd pool to %d replicas (actual %d)", desiredReplicas, numMachines)
+		if err := r.deleteEtcdPoolMachine(ctx, controlPlane, etcdPoolClient, machinesToRemove[0]); err != nil {
+			return ctrl.Result{}, err
+		}
+		return ctrl.Result{Requeue: true}, nil
+	}
+
+	conditions.MarkTrue(kcp, controlplanev1.EtcdPoolReadyCondition)
+	return ctrl.Result{}, nil
+}
+
+// setEtcdPoolEndpoints sets the etcd pool endpoints the control plane machines should be configured with.
+func setEtcdPoolEndpoints(kcp *controlplanev1.KubeadmControlPlane, endpoints []string) {
+	if kcp.Status.EtcdPool == nil {
+		kcp.Status.EtcdPool = &controlplanev1.EtcdPoolStatus{}
+	}
+	kcp.Status.EtcdPool.Endpoints = endpoints
+}
+
+// updateEtcdPoolMemberConditions updates the EtcdMemberHealthy condition of the etcd pool machines.
+func (r *KubeadmControlPlaneReconciler) updateEtcdPoolMemberConditions(ctx context.Context, machines collections.Machines, etcdPool internal.EtcdPool) error {
+	patchHelpers := map[string]*patch.Helper{}
+	for _, m := range machines {
+		patchHelper, err := patch.NewHelper(m, r.Client)
+		if err != nil {
+			return errors.Wrapf(err, "failed to create patch helper for etcd pool machine %s", m.Name)
+		}
+		patchHelpers[m.Name] = patchHelper
+	}
+
+	etcdPool.UpdateEtcdMemberConditions(ctx, machines)
+
+	var errs []error
+	for _, m := range machines {
+		if err := patchHelpers[m.Name].Patch(ctx, m, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			controlplanev1.MachineEtcdMemberHealthyCondition,
+		}}); err != nil {
+			errs = append(errs, errors.Wrapf(err, "failed to patch etcd pool machine %s", m.Name))
+		}
+	}
+	return kerrors.NewAggregate(errs)
+}
+
+// markWaitingForEtcdPool reports the control plane waiting for the etcd pool, if the control plane is not yet initialized.
+func (r *KubeadmControlPlaneReconciler) markWaitingForEtcdPool(controlPlane *internal.ControlPlane) {
+	if len(controlPlane.Machines) == 0 {
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.AvailableCondition, controlplanev1.WaitingForEtcdPoolReason, clusterv1.ConditionSeverityInfo, "")
+	}
+}
+
+// etcdPoolMachinesNeedingRollout returns the etcd pool machines which have not been created from
+// the current etcd pool infrastructure and bootstrap config templates.
+func (r *KubeadmControlPlaneReconciler) etcdPoolMachinesNeedingRollout(ctx context.Context, controlPlane *internal.ControlPlane) (collections.Machines, error) {
+	machinesNeedingRollout := collections.New()
+	template := controlPlane.KCP.Spec.EtcdPool.MachineTemplate
+	reconciliationTime := metav1.Now()
+	for _, m := range controlPlane.EtcdMachines.Filter(collections.Not(collections.HasDeletionTimestamp)) {
+		// Etcd pool machines are rolled out together with control plane machines when KCP.Spec.RolloutAfter expires.
+		if collections.ShouldRolloutAfter(&reconciliationTime, controlPlane.KCP.Spec.RolloutAfter)(m) {
+			machinesNeedingRollout.Insert(m)
+			continue
+		}
+		infraMatches, err := r.isClonedFromTemplate(ctx, &m.Spec.InfrastructureRef, template.InfrastructureRef, m.Namespace)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to retrieve infra obj for etcd machine %q", m.Name)
+		}
+		bootstrapMatches := true
+		if m.Spec.Bootstrap.ConfigRef != nil {
+			bootstrapMatches, err = r.isClonedFromTemplate(ctx, m.Spec.Bootstrap.ConfigRef, template.BootstrapConfigRef, m.Namespace)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to retrieve bootstrap config for etcd machine %q", m.Name)
+			}
+		}
+		if !infraMatches || !bootstrapMatches {
+			machinesNeedingRollout.Insert(m)
+		}
+	}
+	return machinesNeedingRollout, nil
+}
+
+// isClonedFromTemplate returns true if the referenced object has been cloned from the given template.
+// NOTE: Missing objects or objects without the cloned from annotations are considered matching, because we don't have
+// enough information to make a decision.
+func (r *KubeadmControlPlaneReconciler) isClonedFromTemplate(ctx context.Context, ref *corev1.ObjectReference, templateRef corev1.ObjectReference, namespace string) (bool, error) {
+	obj, err := external.Get(ctx, r.Client, ref, namespace)
+	if err != nil {
+		if apierrors.IsNotFound(errors.Cause(err)) {
+			return true, nil
+		}
+		return false, err
+	}
+
+	clonedFromName, ok1 := obj.GetAnnotations()[clusterv1.TemplateClonedFromNameAnnotation]
+	clonedFromGroupKind, ok2 := obj.GetAnnotations()[clusterv1.TemplateClonedFromGroupKindAnnotation]
+	if !ok1 || !ok2 {
+		return true, nil
+	}
+	return clonedFromName == templateRef.Name && clonedFromGroupKind == templateRef.GroupVersionKind().GroupKind().String(), nil
+}
+
+// selectEtcdPoolMachinesForScaleDown selects the given number of etcd pool machines to be deleted, in order of priority.
+func selectEtcdPoolMachinesForScaleDown(machines, machinesNeedingRollout collections.Machines, count int) []*clusterv1.Machine {
+	selected := []*clusterv1.Machine{}
+	remaining := machines
+	for len(selected) < count && len(remaining) > 0 {
+		m := selectEtcdPoolMachineForScaleDown(remaining, machinesNeedingRollout)
+		selected = append(selected, m)
+		remaining = remaining.Difference(collections.FromMachines(m))
+	}
+	return selected
+}
+
+// selectEtcdPoolMachineForScaleDown selects the etcd pool machine to be deleted, giving priority
+// to machines with the delete annotation, then to outdated machines, and then to the oldest machine.
+func selectEtcdPoolMachineForScaleDown(machines, machinesNeedingRollout collections.Machines) *clusterv1.Machine {
+	if annotatedMachines := machines.Filter(collections.HasAnnotationKey(clusterv1.DeleteMachineAnnotation)); len(annotatedMachines) > 0 {
+		return annotatedMachines.Oldest()
+	}
+	if outdatedMachines := machines.Filter(func(m *clusterv1.Machine) bool {
+		_, ok := machinesNeedingRollout[m.Name]
+		return ok
+	}); len(outdatedMachines) > 0 {
+		return outdatedMachines.Oldest()
+	}
+	return machines.Oldest()
+}
+
+// createEtcdPoolMachine creates a new etcd pool machine by cloning the etcd pool infrastructure and bootstrap config templates.
+func (r *KubeadmControlPlaneReconciler) createEtcdPoolMachine(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	kcp := controlPlane.KCP
+	cluster := controlPlane.Cluster
+	template := kcp.Spec.EtcdPool.MachineTemplate
+
+	machineName := names.SimpleNameGenerator.GenerateName(kcp.Name + "-etcd-")
+	labels := internal.EtcdPoolMachineLabelsForCluster(kcp, cluster.Name)
+
+	// Since the cloned resources should eventually have a controller ref for the Machine, we create an
+	// OwnerReference here without the Controller field set
+	cloneOwner := &metav1.OwnerReference{
+		APIVersion: controlplanev1.GroupVersion.String(),
+		Kind:       kubeadmControlPlaneKind,
+		Name:       kcp.Name,
+		UID:        kcp.UID,
+	}
+
+	// Clone the infrastructure template
+	infraRef, err := external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+		Client:      r.Client,
+		TemplateRef: &template.InfrastructureRef,
+		Namespace:   kcp.Namespace,
+		Name:        machineName,
+		OwnerRef:    cloneOwner,
+		ClusterName: cluster.Name,
+		Labels:      labels,
+		Annotations: template.ObjectMeta.Annotations,
+	})
+	if err != nil {
+		// Safe to return early here since no resources have been created yet.
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.InfrastructureTemplateCloningFailedReason,
+			clusterv1.ConditionSeverityError, err.Error())
+		return errors.Wrap(err, "failed to clone etcd pool infrastructure template")
+	}
+
+	// Clone the bootstrap config template
+	bootstrapRef, err := external.CreateFromTemplate(ctx, &external.CreateFromTemplateInput{
+		Client:      r.Client,
+		TemplateRef: &template.BootstrapConfigRef,
+		Namespace:   kcp.Namespace,
+		Name:        machineName,
+		OwnerRef:    cloneOwner,
+		ClusterName: cluster.Name,
+		Labels:      labels,
+		Annotations: template.ObjectMeta.Annotations,
+	})
+	if err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.BootstrapTemplateCloningFailedReason,
+			clusterv1.ConditionSeverityError, err.Error())
+		errs := []error{errors.Wrap(err, "failed to clone etcd pool bootstrap config template")}
+		if err := r.cleanupFromGeneration(ctx, infraRef); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to cleanup generated resources"))
+		}
+		return kerrors.NewAggregate(errs)
+	}
+
+	// Spread etcd machines across the control plane failure domains, if any.
+	var failureDomain *string
+	if len(controlPlane.FailureDomains().FilterControlPlane()) > 0 {
+		failureDomain = failuredomains.PickFewest(ctx, controlPlane.FailureDomains().FilterControlPlane(), controlPlane.EtcdMachines)
+	}
+
+	annotations := map[string]string{}
+	for k, v := range template.ObjectMeta.Annotations {
+		annotations[k] = v
+	}
+	machine := &clusterv1.Machine{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: clusterv1.GroupVersion.String(),
+			Kind:       "Machine",
+		},
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        machineName,
+			Namespace:   kcp.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			// Note: by setting the ownerRef on creation we signal to the Machine controller that this is not a stand-alone Machine.
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)),
+			},
+		},
+		Spec: clusterv1.MachineSpec{
+			ClusterName:       cluster.Name,
+			InfrastructureRef: *infraRef,
+			Bootstrap: clusterv1.Bootstrap{
+				ConfigRef: bootstrapRef,
+			},
+			FailureDomain:       failureDomain,
+			NodeDeletionTimeout: template.NodeDeletionTimeout,
+		},
+	}
+	if err := ssa.Patch(ctx, r.Client, kcpManagerName, machine); err != nil {
+		conditions.MarkFalse(kcp, controlplanev1.EtcdPoolReadyCondition, controlplanev1.MachineGenerationFailedReason,
+			clusterv1.ConditionSeverityError, err.Error())
+		errs := []error{errors.Wrap(err, "failed to create etcd pool Machine")}
+		if err := r.cleanupFromGeneration(ctx, infraRef, bootstrapRef); err != nil {
+			errs = append(errs, errors.Wrap(err, "failed to cleanup generated resources"))
+		}
+		return kerrors.NewAggregate(errs)
+	}
+	return nil
+}
+
+// deleteEtcdPoolMachine removes the etcd member hosted on an etcd pool machine and then deletes the machine.
+func (r *KubeadmControlPlaneReconciler) deleteEtcdPoolMachine(ctx context.Context, controlPlane *internal.ControlPlane, etcdPool internal.EtcdPool, machine *clusterv1.Machine) error {
+	log := ctrl.LoggerFrom(ctx).WithValues("Machine", klog.KObj(machine))
+
+	if err := etcdPool.RemoveEtcdMemberForMachine(ctx, machine); err != nil {
+		log.Error(err, "Failed to remove etcd member for machine")
+		conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdPoolReadyCondition, controlplanev1.EtcdPoolMemberRemovalFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+
+	log.Info("Deleting etcd pool machine")
+	if err := r.Client.Delete(ctx, machine); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete etcd pool machine %s", machine.Name)
+	}
+	return nil
+}
+
+// deleteEtcdPoolMachines deletes all the etcd pool machines; this is used when deleting the KubeadmControlPlane,
+// after all the control plane machines are gone.
+func (r *KubeadmControlPlaneReconciler) deleteEtcdPoolMachines(ctx context.Context, controlPlane *internal.ControlPlane) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	machinesToDelete := controlPlane.EtcdMachines.Filter(collections.Not(collections.HasDeletionTimestamp))
+	var errs []error
+	for i := range machinesToDelete {
+		m := machinesToDelete[i]
+		logger := log.WithValues("Machine", klog.KObj(m))
+		if err := r.Client.Delete(ctx, m); err != nil && !apierrors.IsNotFound(err) {
+			logger.Error(err, "Failed to cleanup owned etcd pool machine")
+			errs = append(errs, err)
+		}
+	}
+	if len(errs) > 0 {
+		err := kerrors.NewAggregate(errs)
+		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "FailedDelete",
+			"Failed to delete etcd pool Machines for cluster %s control plane: %v", klog.KObj(controlPlane.Cluster), err)
+		return ctrl.Result{}, err
+	}
+	conditions.MarkFalse(controlPlane.KCP, controlplanev1.EtcdPoolReadyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+	return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
+}
+
+// reconcileEtcdPoolClientCertificate ensures the client certificate used by the API servers to connect to the etcd pool
+// exists, it is not about to expire and it is signed by the current etcd CA, e.g. after the etcd CA has been rotated.
+// The certificate is stored in the same Secret used for the apiserver-etcd-client certificate when using an external etcd.
+// NOTE: A renewed certificate is picked up by control plane machines only when they are rolled out.
+func (r *KubeadmControlPlaneReconciler) reconcileEtcdPoolClientCertificate(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	clusterName := util.ObjectKey(controlPlane.Cluster)
+
+	etcdCASecret, err := secret.GetFromNamespacedName(ctx, r.SecretCachingClient, clusterName, secret.EtcdCA)
+	if err != nil {
+		return errors.Wrap(err, "failed to get etcd CA")
+	}
+	caCert, err := certs.DecodeCertPEM(etcdCASecret.Data[secret.TLSCrtDataName])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode etcd CA certificate")
+	}
+	caKey, err := certs.DecodePrivateKeyPEM(etcdCASecret.Data[secret.TLSKeyDataName])
+	if err != nil {
+		return errors.Wrap(err, "failed to decode etcd CA key")
+	}
+	if caCert == nil || caKey == nil {
+		return errors.Errorf("etcd CA for cluster %s does not contain a valid key pair", klog.KObj(controlPlane.Cluster))
+	}
+
+	clientSecret, err := secret.GetFromNamespacedName(ctx, r.SecretCachingClient, clusterName, secret.APIServerEtcdClient)
+	switch {
+	case apierrors.IsNotFound(err):
+		clientSecret = nil
+	case err != nil:
+		return errors.Wrap(err, "failed to get etcd pool client certificate")
+	default:
+		cert, err := certs.DecodeCertPEM(clientSecret.Data[secret.TLSCrtDataName])
+		if err != nil {
+			return errors.Wrap(err, "failed to decode etcd pool client certificate")
+		}
+		// Keep the certificate unless it is about to expire, or it is not signed by the current etcd CA
+		// e.g. because the etcd CA has been rotated.
+		if cert != nil && time.Until(cert.NotAfter) > certs.ClientCertificateRenewalDuration && cert.CheckSignatureFrom(caCert) == nil {
+			return nil
+		}
+	}
+
+	clientKey, err := certs.NewPrivateKey()
+	if err != nil {
+		return errors.Wrap(err, "failed to generate etcd pool client key")
+	}
+	cfg := certs.Config{
+		CommonName:   etcdPoolClientCertificateCommonName,
+		Organization: []string{"system:masters"},
+		Usages:       []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	clientCert, err := cfg.NewSignedCert(clientKey, caCert, caKey)
+	if err != nil {
+		return errors.Wrap(err, "failed to generate etcd pool client certificate")
+	}
+
+	if clientSecret != nil {
+		clientSecret.Data[secret.TLSCrtDataName] = certs.EncodeCertPEM(clientCert)
+		clientSecret.Data[secret.TLSKeyDataName] = certs.EncodePrivateKeyPEM(clientKey)
+		if err := r.Client.Update(ctx, clientSecret); err != nil {
+			return errors.Wrap(err, "failed to renew etcd pool client certificate")
+		}
+		return nil
+	}
+
+	certificate := &secret.Certificate{
+		Purpose: secret.APIServerEtcdClient,
+		KeyPair: &certs.KeyPair{
+			Cert: certs.EncodeCertPEM(clientCert),
+			Key:  certs.EncodePrivateKeyPEM(clientKey),
+		},
+		Generated: true,
+	}
+	controllerOwnerRef := *metav1.NewControllerRef(controlPlane.KCP, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind))
+	if err := r.Client.Create(ctx, certificate.AsSecret(clusterName, controllerOwnerRef)); err != nil && !apierrors.IsAlreadyExists(err) {
+		return errors.Wrap(err, "failed to create etcd pool client certificate")
+	}
+	return nil
+}
+
+// updateEtcdPoolStatus updates the etcd pool status of the KubeadmControlPlane.
+func (r *KubeadmControlPlaneReconciler) updateEtcdPoolStatus(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	if !controlPlane.HasEtcdPool() {
+		controlPlane.KCP.Status.EtcdPool = nil
+		return nil
+	}
+
+	machines := controlPlane.EtcdMachines.Filter(collections.Not(collections.HasDeletionTimestamp))
+	machinesNeedingRollout, err := r.etcdPoolMachinesNeedingRollout(ctx, controlPlane)
+	if err != nil {
+		return err
+	}
+	controlPlane.KCP.Status.EtcdPool = &controlplanev1.EtcdPoolStatus{
+		Replicas:        int32(len(machines)),
+		UpdatedReplicas: int32(len(machines) - len(machinesNeedingRollout)),
+		ReadyReplicas:   int32(len(controlPlane.ReadyEtcdPoolMachines())),
+		// NOTE: Endpoints are preserved, because they are updated by the etcd pool reconciliation only when the etcd pool is stable.
+		Endpoints: controlPlane.EtcdPoolEndpoints(),
+	}
+	return nil
+}
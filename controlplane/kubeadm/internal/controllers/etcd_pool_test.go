@@ -0,0 +1,535 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestIsEtcdPoolMachine(t *testing.T) {
+	g := NewWithT(t)
+
+	etcdMachine := machine("etcd-1")
+	etcdMachine.SetLabels(map[string]string{controlplanev1.EtcdPoolNameLabel: "kcp"})
+
+	g.Expect(isEtcdPoolMachine(etcdMachine)).To(BeTrue())
+	g.Expect(isEtcdPoolMachine(machine("cp-1"))).To(BeFalse())
+}
+
+func TestSelectEtcdPoolMachineForScaleDown(t *testing.T) {
+	startDate := time.Date(2000, 1, 1, 1, 0, 0, 0, time.UTC)
+	m1 := machine("etcd-1", withTimestamp(startDate.Add(time.Hour)))
+	m2 := machine("etcd-2", withTimestamp(startDate.Add(-3*time.Hour)))
+	m3 := machine("etcd-3", withTimestamp(startDate.Add(-4*time.Hour)))
+	m4 := machine("etcd-4", withTimestamp(startDate.Add(-time.Hour)), withAnnotation(clusterv1.DeleteMachineAnnotation))
+
+	tests := []struct {
+		name                   string
+		machines               collections.Machines
+		machinesNeedingRollout collections.Machines
+		expectedMachine        *clusterv1.Machine
+	}{
+		{
+			name:            "selects the oldest machine",
+			machines:        collections.FromMachines(m1, m2, m3),
+			expectedMachine: m3,
+		},
+		{
+			name:                   "selects the oldest machine needing rollout",
+			machines:               collections.FromMachines(m1, m2, m3),
+			machinesNeedingRollout: collections.FromMachines(m1, m2),
+			expectedMachine:        m2,
+		},
+		{
+			name:                   "selects machines annotated for deletion first",
+			machines:               collections.FromMachines(m1, m2, m3, m4),
+			machinesNeedingRollout: collections.FromMachines(m1, m2),
+			expectedMachine:        m4,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(selectEtcdPoolMachineForScaleDown(tt.machines, tt.machinesNeedingRollout).Name).To(Equal(tt.expectedMachine.Name))
+		})
+	}
+}
+
+func TestSelectEtcdPoolMachinesForScaleDown(t *testing.T) {
+	startDate := time.Date(2000, 1, 1, 1, 0, 0, 0, time.UTC)
+	m1 := machine("etcd-1", withTimestamp(startDate.Add(-time.Hour)))
+	m2 := machine("etcd-2", withTimestamp(startDate.Add(-2*time.Hour)))
+	m3 := machine("etcd-3", withTimestamp(startDate.Add(-3*time.Hour)))
+	m4 := machine("etcd-4", withTimestamp(startDate))
+
+	tests := []struct {
+		name                   string
+		machines               collections.Machines
+		machinesNeedingRollout collections.Machines
+		count                  int
+		expectedMachines       []string
+	}{
+		{
+			name:             "selects no machines",
+			machines:         collections.FromMachines(m1, m2, m3),
+			count:            0,
+			expectedMachines: []string{},
+		},
+		{
+			name:             "selects the oldest machines",
+			machines:         collections.FromMachines(m1, m2, m3),
+			count:            2,
+			expectedMachines: []string{"etcd-3", "etcd-2"},
+		},
+		{
+			name:                   "selects machines needing rollout first",
+			machines:               collections.FromMachines(m1, m2, m3, m4),
+			machinesNeedingRollout: collections.FromMachines(m4),
+			count:                  2,
+			expectedMachines:       []string{"etcd-4", "etcd-3"},
+		},
+		{
+			name:             "does not select more machines than existing",
+			machines:         collections.FromMachines(m1),
+			count:            2,
+			expectedMachines: []string{"etcd-1"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			selected := []string{}
+			for _, m := range selectEtcdPoolMachinesForScaleDown(tt.machines, tt.machinesNeedingRollout, tt.count) {
+				selected = append(selected, m.Name)
+			}
+			g.Expect(selected).To(Equal(tt.expectedMachines))
+		})
+	}
+}
+
+func TestReconcileEtcdPool(t *testing.T) {
+	type etcdMachine struct {
+		name        string
+		provisioned bool
+		healthy     bool
+		outdated    bool
+		deleting    bool
+	}
+
+	tests := []struct {
+		name                     string
+		replicas                 int32
+		etcdMachines             []etcdMachine
+		endpoints                []string
+		controlPlaneEndpoints    string
+		addMemberErr             error
+		expectErr                bool
+		expectResult             ctrl.Result
+		expectCreatedMachines    int
+		expectAddedMembers       []string
+		expectRemovedMembers     []string
+		expectEndpoints          []string
+		expectConditionReason    string
+		expectEtcdPoolReadyState bool
+	}{
+		{
+			name:                  "creates the first etcd machine",
+			replicas:              3,
+			expectResult:          ctrl.Result{Requeue: true},
+			expectCreatedMachines: 1,
+			expectConditionReason: controlplanev1.EtcdPoolScalingUpReason,
+		},
+		{
+			name:                  "waits for etcd machines to be provisioned",
+			replicas:              3,
+			etcdMachines:          []etcdMachine{{name: "etcd-1"}},
+			expectResult:          ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+			expectConditionReason: controlplanev1.EtcdPoolWaitingForMembersReason,
+		},
+		{
+			name:     "adds the etcd member of provisioned machines and waits for it to be healthy",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true},
+				{name: "etcd-2", provisioned: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379"},
+			expectResult:          ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+			expectAddedMembers:    []string{"etcd-2"},
+			expectEndpoints:       []string{"https://10.0.0.1:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolWaitingForMembersReason,
+		},
+		{
+			name:     "reports failures adding etcd members",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true},
+				{name: "etcd-2", provisioned: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379"},
+			addMemberErr:          errors.New("failed to add member"),
+			expectErr:             true,
+			expectEndpoints:       []string{"https://10.0.0.1:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolMemberAddFailedReason,
+		},
+		{
+			name:     "scales up when all the etcd machines host a healthy etcd member",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379"},
+			expectResult:          ctrl.Result{Requeue: true},
+			expectCreatedMachines: 1,
+			expectEndpoints:       []string{"https://10.0.0.1:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolScalingUpReason,
+		},
+		{
+			name:     "sets the endpoints once the etcd pool is stable",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+			},
+			endpoints:                []string{"https://10.0.0.1:2379"},
+			expectEndpoints:          []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			expectEtcdPoolReadyState: true,
+		},
+		{
+			name:     "creates a replacement for an outdated etcd machine without changing the endpoints",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true, outdated: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			expectResult:          ctrl.Result{Requeue: true},
+			expectCreatedMachines: 1,
+			expectEndpoints:       []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolRollingUpdateInProgressReason,
+		},
+		{
+			name:     "excludes the outdated etcd machine from the endpoints and waits for control plane machines to be rolled out",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true, outdated: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+				{name: "etcd-4", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			controlPlaneEndpoints: "https://10.0.0.1:2379,https://10.0.0.2:2379,https://10.0.0.3:2379",
+			expectEndpoints:       []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379", "https://10.0.0.4:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolWaitingForControlPlaneReason,
+		},
+		{
+			name:     "removes the outdated etcd machine once control plane machines are rolled out",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true, outdated: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+				{name: "etcd-4", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379", "https://10.0.0.4:2379"},
+			controlPlaneEndpoints: "https://10.0.0.2:2379,https://10.0.0.3:2379,https://10.0.0.4:2379",
+			expectResult:          ctrl.Result{Requeue: true},
+			expectRemovedMembers:  []string{"etcd-1"},
+			expectEndpoints:       []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379", "https://10.0.0.4:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolScalingDownReason,
+		},
+		{
+			name:     "excludes all the etcd machines to be removed from the endpoints when scaling down",
+			replicas: 1,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			controlPlaneEndpoints: "https://10.0.0.1:2379,https://10.0.0.2:2379,https://10.0.0.3:2379",
+			expectEndpoints:       []string{"https://10.0.0.3:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolWaitingForControlPlaneReason,
+		},
+		{
+			name:     "waits for etcd machines being deleted",
+			replicas: 3,
+			etcdMachines: []etcdMachine{
+				{name: "etcd-1", provisioned: true, healthy: true, deleting: true},
+				{name: "etcd-2", provisioned: true, healthy: true},
+				{name: "etcd-3", provisioned: true, healthy: true},
+			},
+			endpoints:             []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			expectResult:          ctrl.Result{RequeueAfter: deleteRequeueAfter},
+			expectEndpoints:       []string{"https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			expectConditionReason: controlplanev1.EtcdPoolScalingDownReason,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			ns, err := env.CreateNamespace(ctx, "test-kcp-reconcile-etcd-pool")
+			g.Expect(err).ToNot(HaveOccurred())
+			defer func() {
+				g.Expect(env.Delete(ctx, ns)).To(Succeed())
+			}()
+
+			cluster, kcp, genericInfrastructureMachineTemplate := createClusterWithControlPlane(ns.Name)
+			g.Expect(env.Create(ctx, genericInfrastructureMachineTemplate, client.FieldOwner("manager"))).To(Succeed())
+			genericBootstrapConfigTemplate := &unstructured.Unstructured{
+				Object: map[string]interface{}{
+					"kind":       builder.GenericBootstrapConfigTemplateKind,
+					"apiVersion": builder.BootstrapGroupVersion.String(),
+					"metadata": map[string]interface{}{
+						"name":      "bootstrap-foo",
+						"namespace": ns.Name,
+					},
+					"spec": map[string]interface{}{
+						"template": map[string]interface{}{
+							"spec": map[string]interface{}{},
+						},
+					},
+				},
+			}
+			g.Expect(env.Create(ctx, genericBootstrapConfigTemplate, client.FieldOwner("manager"))).To(Succeed())
+
+			cluster.UID = types.UID(util.RandomString(10))
+			kcp.UID = types.UID(util.RandomString(10))
+			// Machines created before RolloutAfter are outdated.
+			kcp.Spec.RolloutAfter = &metav1.Time{Time: time.Now().Add(-time.Hour)}
+			kcp.Spec.EtcdPool = &controlplanev1.EtcdPool{
+				Replicas: ptr.To[int32](tt.replicas),
+				MachineTemplate: controlplanev1.EtcdPoolMachineTemplate{
+					InfrastructureRef: corev1.ObjectReference{
+						Kind:       genericInfrastructureMachineTemplate.GetKind(),
+						APIVersion: genericInfrastructureMachineTemplate.GetAPIVersion(),
+						Name:       genericInfrastructureMachineTemplate.GetName(),
+						Namespace:  ns.Name,
+					},
+					BootstrapConfigRef: corev1.ObjectReference{
+						Kind:       genericBootstrapConfigTemplate.GetKind(),
+						APIVersion: genericBootstrapConfigTemplate.GetAPIVersion(),
+						Name:       genericBootstrapConfigTemplate.GetName(),
+						Namespace:  ns.Name,
+					},
+				},
+			}
+			if tt.endpoints != nil {
+				kcp.Status.EtcdPool = &controlplanev1.EtcdPoolStatus{Endpoints: tt.endpoints}
+			}
+
+			certificates := secret.NewCertificatesForInitialControlPlane(&bootstrapv1.ClusterConfiguration{})
+			g.Expect(certificates.Generate()).To(Succeed())
+			etcdCA := certificates.GetByPurpose(secret.EtcdCA).AsSecret(util.ObjectKey(cluster), *metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)))
+			g.Expect(env.Create(ctx, etcdCA)).To(Succeed())
+
+			etcdMachines := collections.New()
+			healthyMembers := []string{}
+			for i, em := range tt.etcdMachines {
+				m := &clusterv1.Machine{
+					ObjectMeta: metav1.ObjectMeta{
+						Name:      em.name,
+						Namespace: ns.Name,
+						Labels:    internal.EtcdPoolMachineLabelsForCluster(kcp, cluster.Name),
+					},
+					Spec: clusterv1.MachineSpec{
+						ClusterName: cluster.Name,
+						Bootstrap: clusterv1.Bootstrap{
+							DataSecretName: ptr.To("etcd-bootstrap-data"),
+						},
+						InfrastructureRef: corev1.ObjectReference{
+							Kind:       builder.GenericInfrastructureMachineKind,
+							APIVersion: builder.InfrastructureGroupVersion.String(),
+							Name:       em.name,
+							Namespace:  ns.Name,
+						},
+					},
+				}
+				g.Expect(env.Create(ctx, m)).To(Succeed())
+
+				m.CreationTimestamp = metav1.NewTime(time.Now().Add(-30 * time.Minute).Add(time.Duration(i) * time.Minute))
+				if em.outdated {
+					m.CreationTimestamp = metav1.NewTime(time.Now().Add(-2 * time.Hour).Add(time.Duration(i) * time.Minute))
+				}
+				if em.deleting {
+					m.DeletionTimestamp = ptr.To(metav1.Now())
+				}
+				if em.provisioned {
+					m.Status.BootstrapReady = true
+					m.Status.InfrastructureReady = true
+					m.Status.Addresses = clusterv1.MachineAddresses{
+						{Type: clusterv1.MachineInternalIP, Address: fmt.Sprintf("10.0.0.%d", i+1)},
+					}
+				}
+				if em.healthy {
+					healthyMembers = append(healthyMembers, em.name)
+				}
+				etcdMachines.Insert(m)
+			}
+
+			controlPlaneMachines := collections.New()
+			if tt.controlPlaneEndpoints != "" {
+				controlPlaneMachines.Insert(machine("cp-1",
+					withTimestamp(time.Now()),
+					func(m *clusterv1.Machine) {
+						m.Annotations = map[string]string{controlplanev1.EtcdPoolEndpointsAnnotation: tt.controlPlaneEndpoints}
+						m.Spec.Version = ptr.To(kcp.Spec.Version)
+					},
+				))
+			}
+
+			etcdPool := &fakeEtcdPool{
+				HealthyMembers: healthyMembers,
+				AddMemberErr:   tt.addMemberErr,
+			}
+			r := &KubeadmControlPlaneReconciler{
+				Client:              env,
+				SecretCachingClient: env,
+				recorder:            record.NewFakeRecorder(32),
+				managementCluster: &fakeManagementCluster{
+					EtcdPool: etcdPool,
+				},
+			}
+			controlPlane := &internal.ControlPlane{
+				Cluster:      cluster,
+				KCP:          kcp,
+				Machines:     controlPlaneMachines,
+				EtcdMachines: etcdMachines,
+			}
+
+			result, err := r.reconcileEtcdPool(ctx, controlPlane)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+			g.Expect(result).To(BeComparableTo(tt.expectResult))
+
+			g.Expect(etcdPool.AddedMembers).To(Equal(tt.expectAddedMembers))
+			g.Expect(etcdPool.RemovedMembers).To(Equal(tt.expectRemovedMembers))
+			g.Expect(controlPlane.EtcdPoolEndpoints()).To(Equal(tt.expectEndpoints))
+			if tt.expectEtcdPoolReadyState {
+				g.Expect(conditions.IsTrue(kcp, controlplanev1.EtcdPoolReadyCondition)).To(BeTrue())
+			} else {
+				g.Expect(conditions.IsFalse(kcp, controlplanev1.EtcdPoolReadyCondition)).To(BeTrue())
+				g.Expect(conditions.GetReason(kcp, controlplanev1.EtcdPoolReadyCondition)).To(Equal(tt.expectConditionReason))
+			}
+
+			machineList := &clusterv1.MachineList{}
+			g.Expect(env.GetAPIReader().List(ctx, machineList, client.InNamespace(ns.Name), client.HasLabels{controlplanev1.EtcdPoolNameLabel})).To(Succeed())
+			g.Expect(machineList.Items).To(HaveLen(len(tt.etcdMachines) + tt.expectCreatedMachines - len(tt.expectRemovedMembers)))
+			for _, m := range machineList.Items {
+				g.Expect(tt.expectRemovedMembers).ToNot(ContainElement(m.Name))
+				if etcdMachine, ok := etcdMachines[m.Name]; ok && conditions.Has(etcdMachine, controlplanev1.MachineEtcdMemberHealthyCondition) {
+					// The health of the etcd members is persisted on the etcd pool machines.
+					g.Expect(conditions.Get(&m, controlplanev1.MachineEtcdMemberHealthyCondition).Status).To(Equal(conditions.Get(etcdMachine, controlplanev1.MachineEtcdMemberHealthyCondition).Status))
+				}
+			}
+		})
+	}
+}
+
+func TestReconcileEtcdPoolClientCertificate(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := newCluster(&types.NamespacedName{Name: "foo", Namespace: metav1.NamespaceDefault})
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "foo",
+			Namespace: cluster.Namespace,
+			UID:       "abc-123-kcp-control-plane",
+		},
+	}
+
+	certificates := secret.NewCertificatesForInitialControlPlane(&bootstrapv1.ClusterConfiguration{})
+	g.Expect(certificates.Generate()).To(Succeed())
+	etcdCA := certificates.GetByPurpose(secret.EtcdCA).AsSecret(util.ObjectKey(cluster), *metav1.NewControllerRef(kcp, controlplanev1.GroupVersion.WithKind(kubeadmControlPlaneKind)))
+
+	fakeClient := newFakeClient(etcdCA)
+	r := &KubeadmControlPlaneReconciler{
+		Client:              fakeClient,
+		SecretCachingClient: fakeClient,
+	}
+	controlPlane := &internal.ControlPlane{
+		KCP:     kcp,
+		Cluster: cluster,
+	}
+
+	g.Expect(r.reconcileEtcdPoolClientCertificate(ctx, controlPlane)).To(Succeed())
+
+	clientSecret, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), secret.APIServerEtcdClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clientSecret.OwnerReferences).To(HaveLen(1))
+	g.Expect(clientSecret.OwnerReferences[0].Name).To(Equal(kcp.Name))
+
+	cert, err := certs.DecodeCertPEM(clientSecret.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.Subject.CommonName).To(Equal(etcdPoolClientCertificateCommonName))
+	caCert, err := certs.DecodeCertPEM(etcdCA.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(cert.CheckSignatureFrom(caCert)).To(Succeed())
+
+	// A valid client certificate is not regenerated.
+	g.Expect(r.reconcileEtcdPoolClientCertificate(ctx, controlPlane)).To(Succeed())
+	unchangedSecret, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), secret.APIServerEtcdClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(unchangedSecret.Data).To(Equal(clientSecret.Data))
+
+	// A client certificate not signed by the current etcd CA is regenerated.
+	rotatedCertificates := secret.Certificates{&secret.Certificate{Purpose: secret.EtcdCA}}
+	g.Expect(rotatedCertificates.Generate()).To(Succeed())
+	etcdCA.Data = rotatedCertificates.GetByPurpose(secret.EtcdCA).AsSecret(util.ObjectKey(cluster), metav1.OwnerReference{}).Data
+	g.Expect(fakeClient.Update(ctx, etcdCA)).To(Succeed())
+
+	g.Expect(r.reconcileEtcdPoolClientCertificate(ctx, controlPlane)).To(Succeed())
+	renewedSecret, err := secret.GetFromNamespacedName(ctx, fakeClient, util.ObjectKey(cluster), secret.APIServerEtcdClient)
+	g.Expect(err).ToNot(HaveOccurred())
+	renewedCert, err := certs.DecodeCertPEM(renewedSecret.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	rotatedCACert, err := certs.DecodeCertPEM(etcdCA.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renewedCert.CheckSignatureFrom(rotatedCACert)).To(Succeed())
+}
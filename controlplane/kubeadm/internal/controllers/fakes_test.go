@@ -22,13 +22,16 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/util/sets"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
 	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
 )
 
 type fakeManagementCluster struct {
@@ -37,6 +40,7 @@ type fakeManagementCluster struct {
 	Machines     collections.Machines
 	MachinePools *expv1.MachinePoolList
 	Workload     fakeWorkloadCluster
+	EtcdPool     *fakeEtcdPool
 	Reader       client.Reader
 }
 
@@ -52,6 +56,13 @@ func (f *fakeManagementCluster) GetWorkloadCluster(_ context.Context, _ client.O
 	return f.Workload, nil
 }
 
+func (f *fakeManagementCluster) GetEtcdPool(_ context.Context, _ client.ObjectKey, _ []string) (internal.EtcdPool, error) {
+	if f.EtcdPool == nil {
+		return nil, errors.New("etcd pool not available")
+	}
+	return f.EtcdPool, nil
+}
+
 func (f *fakeManagementCluster) GetMachinesForCluster(c context.Context, cluster *clusterv1.Cluster, filters ...collections.Func) (collections.Machines, error) {
 	if f.Management != nil {
 		return f.Management.GetMachinesForCluster(c, cluster, filters...)
@@ -66,10 +77,43 @@ func (f *fakeManagementCluster) GetMachinePoolsForCluster(c context.Context, clu
 	return f.MachinePools, nil
 }
 
+type fakeEtcdPool struct {
+	HealthyMembers []string
+	AddMemberErr   error
+	AddedMembers   []string
+	RemovedMembers []string
+}
+
+func (f *fakeEtcdPool) AddEtcdMemberForMachine(_ context.Context, machine *clusterv1.Machine) error {
+	if f.AddMemberErr != nil {
+		return f.AddMemberErr
+	}
+	f.AddedMembers = append(f.AddedMembers, machine.Name)
+	return nil
+}
+
+func (f *fakeEtcdPool) RemoveEtcdMemberForMachine(_ context.Context, machine *clusterv1.Machine) error {
+	f.RemovedMembers = append(f.RemovedMembers, machine.Name)
+	return nil
+}
+
+func (f *fakeEtcdPool) UpdateEtcdMemberConditions(_ context.Context, machines collections.Machines) {
+	healthyMembers := sets.New[string](f.HealthyMembers...)
+	for _, m := range machines {
+		if healthyMembers.Has(m.Name) {
+			conditions.MarkTrue(m, controlplanev1.MachineEtcdMemberHealthyCondition)
+			continue
+		}
+		conditions.MarkFalse(m, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "")
+	}
+}
+
 type fakeWorkloadCluster struct {
 	*internal.Workload
 	Status                     internal.ClusterStatus
 	EtcdMembersResult          []string
+	EtcdLearnersResult         []string
+	InPlaceUpdateResult        internal.InPlaceUpdateStatus
 	APIServerCertificateExpiry *time.Time
 }
 
@@ -80,6 +124,10 @@ func (f fakeWorkloadCluster) ForwardEtcdLeadership(_ context.Context, _ *cluster
 	return nil
 }
 
+func (f fakeWorkloadCluster) PromoteEtcdLearners(_ context.Context, _ []string) ([]string, error) {
+	return f.EtcdLearnersResult, nil
+}
+
 func (f fakeWorkloadCluster) ReconcileEtcdMembers(_ context.Context, _ []string, _ semver.Version) ([]string, error) {
 	return nil, nil
 }
@@ -136,6 +184,10 @@ func (f fakeWorkloadCluster) UpdateClusterConfiguration(context.Context, semver.
 	return nil
 }
 
+func (f fakeWorkloadCluster) UpdateControlPlaneComponentsInPlace(_ context.Context, _, _, _, _ string) (internal.InPlaceUpdateStatus, error) {
+	return f.InPlaceUpdateResult, nil
+}
+
 type fakeMigrator struct {
 	migrateCalled    bool
 	migrateErr       error
@@ -199,6 +199,11 @@ func (r *KubeadmControlPlaneReconciler) cloneConfigsAndGenerateMachine(ctx conte
 	}
 	machine.Spec.InfrastructureRef = *infraRef
 
+	// Track the etcd pool endpoints the machine is bootstrapped with, so it can be rolled out when they change.
+	if kcp.Spec.EtcdPool != nil && bootstrapSpec.ClusterConfiguration != nil && bootstrapSpec.ClusterConfiguration.Etcd.External != nil {
+		machine.Annotations[controlplanev1.EtcdPoolEndpointsAnnotation] = strings.Join(bootstrapSpec.ClusterConfiguration.Etcd.External.Endpoints, ",")
+	}
+
 	// Clone the bootstrap configuration
 	bootstrapRef, err := r.generateKubeadmConfig(ctx, kcp, cluster, bootstrapSpec, machine.Name)
 	if err != nil {
@@ -381,6 +386,11 @@ func (r *KubeadmControlPlaneReconciler) computeDesiredMachine(kcp *controlplanev
 		if remediationData, ok := existingMachine.Annotations[controlplanev1.RemediationForAnnotation]; ok {
 			annotations[controlplanev1.RemediationForAnnotation] = remediationData
 		}
+
+		// If the machine has been bootstrapped with etcd pool endpoints then preserve them.
+		if etcdPoolEndpoints, ok := existingMachine.Annotations[controlplanev1.EtcdPoolEndpointsAnnotation]; ok {
+			annotations[controlplanev1.EtcdPoolEndpointsAnnotation] = etcdPoolEndpoints
+		}
 	}
 
 	// Construct the basic Machine.
@@ -0,0 +1,161 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/blang/semver/v4"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/annotations"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+const inPlaceUpdateRequeueAfter = 10 * time.Second
+
+// updateControlPlaneInPlace updates the control plane components of machines whose only changes are to the
+// apiServer, controllerManager or scheduler configuration, one machine at a time, without replacing them.
+// If updating a machine in place fails, the machine is marked so it is rolled out instead.
+func (r *KubeadmControlPlaneReconciler) updateControlPlaneInPlace(ctx context.Context, controlPlane *internal.ControlPlane, machinesNeedingInPlaceUpdate collections.Machines) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Update one machine at a time, and only if the control plane is stable, e.g. all the static pods
+	// of the machine updated before are healthy again.
+	if result, err := r.preflightChecks(ctx, controlPlane); err != nil || !result.IsZero() {
+		return result, err
+	}
+
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrap(err, "failed to get remote client for workload cluster")
+	}
+
+	parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
+	}
+
+	// Make sure the kubeadm-config ConfigMap, which is read by kubeadm on the machines, has the desired configuration.
+	clusterConfig := controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration
+	if clusterConfig == nil {
+		clusterConfig = &bootstrapv1.ClusterConfiguration{}
+	}
+	if err := workloadCluster.UpdateClusterConfiguration(ctx, parsedVersion,
+		workloadCluster.UpdateAPIServerInKubeadmConfigMap(clusterConfig.APIServer),
+		workloadCluster.UpdateControllerManagerInKubeadmConfigMap(clusterConfig.ControllerManager),
+		workloadCluster.UpdateSchedulerInKubeadmConfigMap(clusterConfig.Scheduler),
+	); err != nil {
+		return ctrl.Result{}, err
+	}
+
+	configHash, err := controlPlane.ControlPlaneComponentsHash()
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	machine := machinesNeedingInPlaceUpdate.Oldest()
+	if machine.Status.NodeRef == nil {
+		return ctrl.Result{}, errors.Errorf("failed to update Machine %s in place: Machine.status.nodeRef not set", machine.Name)
+	}
+	log = log.WithValues("Machine", klog.KObj(machine), "Node", klog.KRef("", machine.Status.NodeRef.Name))
+	ctx = ctrl.LoggerInto(ctx, log)
+
+	status, err := workloadCluster.UpdateControlPlaneComponentsInPlace(ctx, machine.Status.NodeRef.Name, controlPlane.InPlaceUpdateImage(), configHash, inPlaceUpdatePatchesDirectory(controlPlane.KCP))
+	if err != nil {
+		return ctrl.Result{}, errors.Wrapf(err, "failed to update Machine %s in place", machine.Name)
+	}
+
+	switch status {
+	case internal.InPlaceUpdateSucceeded:
+		log.Info("Control plane components updated in place")
+		if err := r.markMachineUpdatedInPlace(ctx, machine, clusterConfig); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeNormal, "InPlaceUpdateSucceeded", "Control plane components of Machine %s updated in place", machine.Name)
+	case internal.InPlaceUpdateFailed:
+		log.Info("Failed to update control plane components in place, the Machine will be rolled out")
+		if err := r.markMachineInPlaceUpdateFailed(ctx, machine, configHash); err != nil {
+			return ctrl.Result{}, err
+		}
+		r.recorder.Eventf(controlPlane.KCP, corev1.EventTypeWarning, "InPlaceUpdateFailed", "Failed to update control plane components of Machine %s in place, the Machine will be rolled out", machine.Name)
+	default:
+		log.Info("Waiting for control plane components to be updated in place")
+	}
+	return ctrl.Result{RequeueAfter: inPlaceUpdateRequeueAfter}, nil
+}
+
+// markMachineUpdatedInPlace records the ClusterConfiguration the machine has been updated to, so
+// the machine is not considered as needing rollout anymore.
+func (r *KubeadmControlPlaneReconciler) markMachineUpdatedInPlace(ctx context.Context, machine *clusterv1.Machine, clusterConfig *bootstrapv1.ClusterConfiguration) error {
+	clusterConfigJSON, err := json.Marshal(clusterConfig)
+	if err != nil {
+		return errors.Wrap(err, "failed to marshal cluster configuration")
+	}
+
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for Machine %s", machine.Name)
+	}
+	annotations.AddAnnotations(machine, map[string]string{
+		controlplanev1.KubeadmClusterConfigurationAnnotation: string(clusterConfigJSON),
+	})
+	delete(machine.Annotations, controlplanev1.InPlaceUpdateFailedAnnotation)
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+	return nil
+}
+
+// markMachineInPlaceUpdateFailed records that updating the machine in place failed for the given
+// configuration, so the machine is rolled out instead.
+func (r *KubeadmControlPlaneReconciler) markMachineInPlaceUpdateFailed(ctx context.Context, machine *clusterv1.Machine, configHash string) error {
+	patchHelper, err := patch.NewHelper(machine, r.Client)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create patch helper for Machine %s", machine.Name)
+	}
+	annotations.AddAnnotations(machine, map[string]string{
+		controlplanev1.InPlaceUpdateFailedAnnotation: configHash,
+	})
+	if err := patchHelper.Patch(ctx, machine); err != nil {
+		return errors.Wrapf(err, "failed to patch Machine %s", machine.Name)
+	}
+	return nil
+}
+
+// inPlaceUpdatePatchesDirectory returns the directory with the kubeadm patches to be applied
+// when regenerating the static pod manifests, if any.
+func inPlaceUpdatePatchesDirectory(kcp *controlplanev1.KubeadmControlPlane) string {
+	spec := kcp.Spec.KubeadmConfigSpec
+	if spec.JoinConfiguration != nil && spec.JoinConfiguration.Patches != nil && spec.JoinConfiguration.Patches.Directory != "" {
+		return spec.JoinConfiguration.Patches.Directory
+	}
+	if spec.InitConfiguration != nil && spec.InitConfiguration.Patches != nil {
+		return spec.InitConfiguration.Patches.Directory
+	}
+	return ""
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestUpdateControlPlaneInPlace(t *testing.T) {
+	clusterConfig := &bootstrapv1.ClusterConfiguration{
+		APIServer: bootstrapv1.APIServer{
+			ControlPlaneComponent: bootstrapv1.ControlPlaneComponent{ExtraArgs: map[string]string{"v": "4"}},
+		},
+	}
+	configHash := func(g *WithT, controlPlane *internal.ControlPlane) string {
+		h, err := controlPlane.ControlPlaneComponentsHash()
+		g.Expect(err).ToNot(HaveOccurred())
+		return h
+	}
+
+	tests := []struct {
+		name                string
+		inPlaceUpdateResult internal.InPlaceUpdateStatus
+		expectAnnotations   func(g *WithT, controlPlane *internal.ControlPlane, annotations map[string]string)
+	}{
+		{
+			name:                "waits for the in-place update to complete",
+			inPlaceUpdateResult: internal.InPlaceUpdatePending,
+			expectAnnotations: func(g *WithT, _ *internal.ControlPlane, annotations map[string]string) {
+				g.Expect(annotations).To(HaveKeyWithValue(controlplanev1.KubeadmClusterConfigurationAnnotation, "{}"))
+				g.Expect(annotations).ToNot(HaveKey(controlplanev1.InPlaceUpdateFailedAnnotation))
+			},
+		},
+		{
+			name:                "records the new ClusterConfiguration when the in-place update succeeds",
+			inPlaceUpdateResult: internal.InPlaceUpdateSucceeded,
+			expectAnnotations: func(g *WithT, _ *internal.ControlPlane, annotations map[string]string) {
+				clusterConfigJSON, err := json.Marshal(clusterConfig)
+				g.Expect(err).ToNot(HaveOccurred())
+				g.Expect(annotations).To(HaveKeyWithValue(controlplanev1.KubeadmClusterConfigurationAnnotation, string(clusterConfigJSON)))
+				g.Expect(annotations).ToNot(HaveKey(controlplanev1.InPlaceUpdateFailedAnnotation))
+			},
+		},
+		{
+			name:                "marks the machine for rollout when the in-place update fails",
+			inPlaceUpdateResult: internal.InPlaceUpdateFailed,
+			expectAnnotations: func(g *WithT, controlPlane *internal.ControlPlane, annotations map[string]string) {
+				g.Expect(annotations).To(HaveKeyWithValue(controlplanev1.KubeadmClusterConfigurationAnnotation, "{}"))
+				g.Expect(annotations).To(HaveKeyWithValue(controlplanev1.InPlaceUpdateFailedAnnotation, configHash(g, controlPlane)))
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version: "v1.30.0",
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: clusterConfig,
+					},
+					RolloutStrategy: &controlplanev1.RolloutStrategy{
+						Type:          controlplanev1.RollingUpdateStrategyType,
+						InPlaceUpdate: &controlplanev1.InPlaceUpdate{Image: "example.com/kubeadm-runner@sha256:0000000000000000000000000000000000000000000000000000000000000000"},
+					},
+				},
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.TrueCondition(controlplanev1.ControlPlaneComponentsHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.EtcdClusterHealthyCondition),
+					},
+				},
+			}
+			m := &clusterv1.Machine{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "machine",
+					Namespace: metav1.NamespaceDefault,
+					Annotations: map[string]string{
+						controlplanev1.KubeadmClusterConfigurationAnnotation: "{}",
+					},
+				},
+				Status: clusterv1.MachineStatus{
+					NodeRef: &corev1.ObjectReference{
+						Kind: "Node",
+						Name: "node-1",
+					},
+					Conditions: clusterv1.Conditions{
+						*conditions.TrueCondition(controlplanev1.MachineAPIServerPodHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.MachineControllerManagerPodHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.MachineSchedulerPodHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.MachineEtcdPodHealthyCondition),
+						*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+					},
+				},
+			}
+
+			fakeClient := newFakeClient(m.DeepCopy())
+			r := &KubeadmControlPlaneReconciler{
+				Client:   fakeClient,
+				recorder: record.NewFakeRecorder(32),
+			}
+			controlPlane := &internal.ControlPlane{
+				Cluster:  &clusterv1.Cluster{},
+				KCP:      kcp,
+				Machines: collections.FromMachines(m),
+			}
+			controlPlane.InjectTestManagementCluster(&fakeManagementCluster{
+				Workload: fakeWorkloadCluster{
+					InPlaceUpdateResult: tt.inPlaceUpdateResult,
+				},
+			})
+
+			result, err := r.updateControlPlaneInPlace(ctx, controlPlane, collections.FromMachines(m))
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(result).To(Equal(ctrl.Result{RequeueAfter: inPlaceUpdateRequeueAfter}))
+
+			updatedMachine := &clusterv1.Machine{}
+			g.Expect(fakeClient.Get(ctx, client.ObjectKeyFromObject(m), updatedMachine)).To(Succeed())
+			tt.expectAnnotations(g, controlPlane, updatedMachine.Annotations)
+		})
+	}
+}
+
+func TestInPlaceUpdatePatchesDirectory(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{}
+	g.Expect(inPlaceUpdatePatchesDirectory(kcp)).To(BeEmpty())
+
+	kcp.Spec.KubeadmConfigSpec.InitConfiguration = &bootstrapv1.InitConfiguration{
+		Patches: &bootstrapv1.Patches{Directory: "/etc/kubeadm/init-patches"},
+	}
+	g.Expect(inPlaceUpdatePatchesDirectory(kcp)).To(Equal("/etc/kubeadm/init-patches"))
+
+	kcp.Spec.KubeadmConfigSpec.JoinConfiguration = &bootstrapv1.JoinConfiguration{
+		Patches: &bootstrapv1.Patches{Directory: "/etc/kubeadm/join-patches"},
+	}
+	g.Expect(inPlaceUpdatePatchesDirectory(kcp)).To(Equal("/etc/kubeadm/join-patches"))
+}
@@ -58,6 +58,14 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 		return result, err
 	}
 
+	// If control plane machines join the etcd cluster as learners, make sure the EtcdLearnerMode feature gate is
+	// enabled in the kubeadm config map, given that kubeadm join reads feature gates from there.
+	if controlPlane.IsEtcdLearnerModeEnabled() {
+		if err := r.enableEtcdLearnerMode(ctx, controlPlane); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+
 	// Create the bootstrap configuration
 	bootstrapSpec := controlPlane.JoinControlPlaneConfig()
 	fd := controlPlane.NextFailureDomainForScaleUp(ctx)
@@ -71,6 +79,24 @@ func (r *KubeadmControlPlaneReconciler) scaleUpControlPlane(ctx context.Context,
 	return ctrl.Result{Requeue: true}, nil
 }
 
+// enableEtcdLearnerMode enables the EtcdLearnerMode feature gate in the kubeadm config map of the workload cluster.
+func (r *KubeadmControlPlaneReconciler) enableEtcdLearnerMode(ctx context.Context, controlPlane *internal.ControlPlane) error {
+	workloadCluster, err := controlPlane.GetWorkloadCluster(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to create client to workload cluster")
+	}
+
+	parsedVersion, err := semver.ParseTolerant(controlPlane.KCP.Spec.Version)
+	if err != nil {
+		return errors.Wrapf(err, "failed to parse kubernetes version %q", controlPlane.KCP.Spec.Version)
+	}
+
+	if err := workloadCluster.UpdateClusterConfiguration(ctx, parsedVersion, workloadCluster.EnableEtcdLearnerModeInKubeadmConfigMap()); err != nil {
+		return errors.Wrap(err, "failed to enable etcd learner mode in the kubeadm config map")
+	}
+	return nil
+}
+
 func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 	ctx context.Context,
 	controlPlane *internal.ControlPlane,
@@ -139,6 +165,7 @@ func (r *KubeadmControlPlaneReconciler) scaleDownControlPlane(
 // preflightChecks checks if the control plane is stable before proceeding with a scale up/scale down operation,
 // where stable means that:
 // - There are no machine deletion in progress
+// - There are no etcd learners waiting to be promoted to voting members.
 // - All the health conditions on KCP are true.
 // - All the health conditions on the control plane machines are true.
 // If the control plane is not passing preflight checks, it requeue.
@@ -159,6 +186,13 @@ func (r *KubeadmControlPlaneReconciler) preflightChecks(ctx context.Context, con
 		return ctrl.Result{RequeueAfter: deleteRequeueAfter}, nil
 	}
 
+	// If there are etcd learners not yet promoted to voting members, wait for them to be in sync with the leader
+	// before changing the etcd cluster membership again.
+	if conditions.IsFalse(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition) {
+		logger.Info("Waiting for etcd learners to be promoted", "reason", conditions.GetMessage(controlPlane.KCP, controlplanev1.EtcdLearnersPromotedCondition))
+		return ctrl.Result{RequeueAfter: preflightFailedRequeueAfter}, nil
+	}
+
 	// Check machine health conditions; if there are conditions with False or Unknown, then wait.
 	allMachineHealthConditions := []clusterv1.ConditionType{
 		controlplanev1.MachineAPIServerPodHealthyCondition,
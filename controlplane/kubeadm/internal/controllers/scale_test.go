@@ -535,6 +535,27 @@ func TestPreflightChecks(t *testing.T) {
 			},
 			expectResult: ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
 		},
+		{
+			name: "control plane with etcd learners waiting to be promoted should requeue",
+			kcp: &controlplanev1.KubeadmControlPlane{
+				Status: controlplanev1.KubeadmControlPlaneStatus{
+					Conditions: clusterv1.Conditions{
+						*conditions.FalseCondition(controlplanev1.EtcdLearnersPromotedCondition, controlplanev1.WaitingForEtcdLearnersReason, clusterv1.ConditionSeverityInfo, ""),
+					},
+				},
+			},
+			machines: []*clusterv1.Machine{
+				{
+					Status: clusterv1.MachineStatus{
+						NodeRef: &corev1.ObjectReference{
+							Kind: "Node",
+							Name: "node-1",
+						},
+					},
+				},
+			},
+			expectResult: ctrl.Result{RequeueAfter: preflightFailedRequeueAfter},
+		},
 		{
 			name: "control plane with an healthy machine and an healthy kcp condition should pass",
 			kcp: &controlplanev1.KubeadmControlPlane{
@@ -46,6 +46,10 @@ func (r *KubeadmControlPlaneReconciler) updateStatus(ctx context.Context, contro
 	controlPlane.KCP.Status.ReadyReplicas = 0
 	controlPlane.KCP.Status.UnavailableReplicas = replicas
 
+	if err := r.updateEtcdPoolStatus(ctx, controlPlane); err != nil {
+		return errors.Wrap(err, "failed to update etcd pool status")
+	}
+
 	// Return early if the deletion timestamp is set, because we don't want to try to connect to the workload cluster
 	// and we don't want to report resize condition (because it is set to deleting into reconcile delete).
 	if !controlPlane.KCP.DeletionTimestamp.IsZero() {
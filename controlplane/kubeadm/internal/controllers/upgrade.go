@@ -90,21 +90,28 @@ func (r *KubeadmControlPlaneReconciler) upgradeControlPlane(
 
 		kubeadmCMMutators = append(kubeadmCMMutators,
 			workloadCluster.UpdateImageRepositoryInKubeadmConfigMap(imageRepository),
-			workloadCluster.UpdateFeatureGatesInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.FeatureGates),
+			workloadCluster.UpdateFeatureGatesInKubeadmConfigMap(controlPlane.KubeadmFeatureGates()),
 			workloadCluster.UpdateAPIServerInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.APIServer),
 			workloadCluster.UpdateControllerManagerInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.ControllerManager),
 			workloadCluster.UpdateSchedulerInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Scheduler))
 
 		// Etcd local and external are mutually exclusive and they cannot be switched, once set.
+		// NOTE: When using an etcd pool, the external etcd configuration is computed below.
 		if controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil {
 			kubeadmCMMutators = append(kubeadmCMMutators,
 				workloadCluster.UpdateEtcdLocalInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local))
-		} else {
+		} else if !controlPlane.HasEtcdPool() {
 			kubeadmCMMutators = append(kubeadmCMMutators,
 				workloadCluster.UpdateEtcdExternalInKubeadmConfigMap(controlPlane.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External))
 		}
 	}
 
+	// When using an etcd pool, make sure joining machines are using the current etcd pool endpoints.
+	if controlPlane.HasEtcdPool() {
+		kubeadmCMMutators = append(kubeadmCMMutators,
+			workloadCluster.UpdateEtcdExternalInKubeadmConfigMap(controlPlane.EtcdPoolExternalEtcd()))
+	}
+
 	// collectively update Kubeadm config map
 	if err = workloadCluster.UpdateClusterConfiguration(ctx, parsedVersion, kubeadmCMMutators...); err != nil {
 		return ctrl.Result{}, err
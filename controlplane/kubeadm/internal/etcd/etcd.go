@@ -24,6 +24,7 @@ import (
 
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	"google.golang.org/grpc"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -40,7 +41,9 @@ type etcd interface {
 	AlarmList(ctx context.Context) (*clientv3.AlarmResponse, error)
 	Close() error
 	Endpoints() []string
+	MemberAdd(ctx context.Context, peerAddrs []string) (*clientv3.MemberAddResponse, error)
 	MemberList(ctx context.Context) (*clientv3.MemberListResponse, error)
+	MemberPromote(ctx context.Context, id uint64) (*clientv3.MemberPromoteResponse, error)
 	MemberRemove(ctx context.Context, id uint64) (*clientv3.MemberRemoveResponse, error)
 	MemberUpdate(ctx context.Context, id uint64, peerURLs []string) (*clientv3.MemberUpdateResponse, error)
 	MoveLeader(ctx context.Context, id uint64) (*clientv3.MoveLeaderResponse, error)
@@ -83,6 +86,9 @@ const (
 // for read and write operations to etcd.
 const DefaultCallTimeout = 15 * time.Second
 
+// ErrLearnerNotReady is returned when promoting a learner member which is not yet in sync with the leader.
+var ErrLearnerNotReady = errors.New("learner member is not yet in sync with the leader")
+
 // AlarmTypeName provides a text translation for AlarmType codes.
 var AlarmTypeName = map[AlarmType]string{
 	AlarmOK:      "NONE",
@@ -139,20 +145,25 @@ type ClientConfiguration struct {
 }
 
 // NewClient creates a new etcd client with the given configuration.
+// If a Proxy is configured, the network connection is established through it, otherwise
+// the client connects directly to the endpoint (e.g. for etcd members running outside the workload cluster).
 func NewClient(ctx context.Context, config ClientConfiguration) (*Client, error) {
-	dialer, err := proxy.NewDialer(config.Proxy)
-	if err != nil {
-		return nil, errors.Wrap(err, "unable to create a dialer for etcd client")
+	dialOptions := []grpc.DialOption{
+		grpc.WithBlock(), // block until the underlying connection is up
+	}
+	if config.Proxy.KubeConfig != nil {
+		dialer, err := proxy.NewDialer(config.Proxy)
+		if err != nil {
+			return nil, errors.Wrap(err, "unable to create a dialer for etcd client")
+		}
+		dialOptions = append(dialOptions, grpc.WithContextDialer(dialer.DialContextWithAddr))
 	}
 
 	etcdClient, err := clientv3.New(clientv3.Config{
-		Endpoints:   []string{config.Endpoint}, // NOTE: endpoint is used only as a host for certificate validation, the network connection is defined by DialOptions.
+		Endpoints:   []string{config.Endpoint}, // NOTE: when using a proxy, endpoint is used only as a host for certificate validation, the network connection is defined by DialOptions.
 		DialTimeout: config.DialTimeout,
-		DialOptions: []grpc.DialOption{
-			grpc.WithBlock(), // block until the underlying connection is up
-			grpc.WithContextDialer(dialer.DialContextWithAddr),
-		},
-		TLS: config.TLSConfig,
+		DialOptions: dialOptions,
+		TLS:         config.TLSConfig,
 	})
 	if err != nil {
 		return nil, errors.Wrap(err, "unable to create etcd client")
@@ -239,6 +250,18 @@ func (c *Client) MoveLeader(ctx context.Context, newLeaderID uint64) error {
 	return errors.Wrapf(err, "failed to move etcd leader: %v", newLeaderID)
 }
 
+// AddMember adds a new member with the given peer URLs.
+func (c *Client) AddMember(ctx context.Context, peerURLs []string) (*Member, error) {
+	ctx, cancel := context.WithTimeout(ctx, c.CallTimeout)
+	defer cancel()
+
+	response, err := c.EtcdClient.MemberAdd(ctx, peerURLs)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to add member with peer URLs %v", peerURLs)
+	}
+	return pbMemberToMember(response.Member), nil
+}
+
 // RemoveMember removes a given member.
 func (c *Client) RemoveMember(ctx context.Context, id uint64) error {
 	ctx, cancel := context.WithTimeout(ctx, c.CallTimeout)
@@ -248,6 +271,25 @@ func (c *Client) RemoveMember(ctx context.Context, id uint64) error {
 	return errors.Wrapf(err, "failed to remove member: %v", id)
 }
 
+// PromoteMember promotes a learner member to a voting member.
+// If the learner member is not yet in sync with the leader, ErrLearnerNotReady is returned.
+func (c *Client) PromoteMember(ctx context.Context, id uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, c.CallTimeout)
+	defer cancel()
+
+	_, err := c.EtcdClient.MemberPromote(ctx, id)
+	switch {
+	case err == nil:
+		return nil
+	case errors.Is(err, rpctypes.ErrMemberNotLearner):
+		// The member has already been promoted, e.g. by kubeadm while joining the node.
+		return nil
+	case errors.Is(err, rpctypes.ErrMemberLearnerNotReady):
+		return ErrLearnerNotReady
+	}
+	return errors.Wrapf(err, "failed to promote member: %v", id)
+}
+
 // UpdateMemberPeerURLs updates the list of peer URLs.
 func (c *Client) UpdateMemberPeerURLs(ctx context.Context, id uint64, peerURLs []string) ([]*Member, error) {
 	ctx, cancel := context.WithTimeout(ctx, c.CallTimeout)
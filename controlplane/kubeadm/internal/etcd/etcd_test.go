@@ -22,6 +22,7 @@ import (
 	. "github.com/onsi/gomega"
 	"github.com/pkg/errors"
 	"go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -104,3 +105,78 @@ func TestEtcdMembers_WithSuccess(t *testing.T) {
 	g.Expect(updatedMembers[0].PeerURLs).To(HaveLen(2))
 	g.Expect(updatedMembers[0].PeerURLs).To(Equal([]string{"https://1.2.3.4:2000", "https://4.5.6.7:2000"}))
 }
+
+func TestEtcdPromoteMember(t *testing.T) {
+	tests := []struct {
+		name       string
+		promoteErr error
+		wantErr    error
+		wantAnyErr bool
+	}{
+		{
+			name: "learner is promoted",
+		},
+		{
+			name:       "member already promoted",
+			promoteErr: rpctypes.ErrMemberNotLearner,
+		},
+		{
+			name:       "learner not in sync with the leader",
+			promoteErr: rpctypes.ErrMemberLearnerNotReady,
+			wantErr:    ErrLearnerNotReady,
+		},
+		{
+			name:       "promotion fails",
+			promoteErr: errors.New("something went wrong"),
+			wantAnyErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeEtcdClient := &etcdfake.FakeEtcdClient{
+				EtcdEndpoints:    []string{"https://etcd-instance:2379"},
+				StatusResponse:   &clientv3.StatusResponse{},
+				MemberPromoteErr: tt.promoteErr,
+			}
+
+			client, err := newEtcdClient(ctx, fakeEtcdClient, DefaultCallTimeout)
+			g.Expect(err).ToNot(HaveOccurred())
+
+			err = client.PromoteMember(ctx, 1234)
+			switch {
+			case tt.wantErr != nil:
+				g.Expect(errors.Is(err, tt.wantErr)).To(BeTrue())
+			case tt.wantAnyErr:
+				g.Expect(err).To(HaveOccurred())
+			default:
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestEtcdAddMember(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeEtcdClient := &etcdfake.FakeEtcdClient{
+		EtcdEndpoints:  []string{"https://etcd-instance:2379"},
+		StatusResponse: &clientv3.StatusResponse{},
+		MemberAddResponse: &clientv3.MemberAddResponse{
+			Member: &etcdserverpb.Member{ID: 1234, PeerURLs: []string{"https://1.2.3.4:2380"}},
+		},
+	}
+
+	client, err := newEtcdClient(ctx, fakeEtcdClient, DefaultCallTimeout)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	member, err := client.AddMember(ctx, []string{"https://1.2.3.4:2380"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(member.ID).To(Equal(uint64(1234)))
+	g.Expect(fakeEtcdClient.AddedMemberPeerURLs).To(Equal([]string{"https://1.2.3.4:2380"}))
+
+	fakeEtcdClient.ErrorResponse = errors.New("something went wrong")
+	_, err = client.AddMember(ctx, []string{"https://1.2.3.4:2380"})
+	g.Expect(err).To(HaveOccurred())
+}
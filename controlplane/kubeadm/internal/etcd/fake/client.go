@@ -26,7 +26,10 @@ import (
 type FakeEtcdClient struct { //nolint:revive
 	AlarmResponse        *clientv3.AlarmResponse
 	EtcdEndpoints        []string
+	MemberAddErr         error
+	MemberAddResponse    *clientv3.MemberAddResponse
 	MemberListResponse   *clientv3.MemberListResponse
+	MemberPromoteErr     error
 	MemberRemoveResponse *clientv3.MemberRemoveResponse
 	MemberUpdateResponse *clientv3.MemberUpdateResponse
 	MoveLeaderResponse   *clientv3.MoveLeaderResponse
@@ -34,6 +37,8 @@ type FakeEtcdClient struct { //nolint:revive
 	ErrorResponse        error
 	MovedLeader          uint64
 	RemovedMember        uint64
+	PromotedMembers      []uint64
+	AddedMemberPeerURLs  []string
 }
 
 func (c *FakeEtcdClient) Endpoints() []string {
@@ -53,9 +58,24 @@ func (c *FakeEtcdClient) AlarmList(_ context.Context) (*clientv3.AlarmResponse,
 	return c.AlarmResponse, c.ErrorResponse
 }
 
+func (c *FakeEtcdClient) MemberAdd(_ context.Context, peerAddrs []string) (*clientv3.MemberAddResponse, error) {
+	if c.MemberAddErr != nil {
+		return nil, c.MemberAddErr
+	}
+	c.AddedMemberPeerURLs = append(c.AddedMemberPeerURLs, peerAddrs...)
+	return c.MemberAddResponse, c.ErrorResponse
+}
+
 func (c *FakeEtcdClient) MemberList(_ context.Context) (*clientv3.MemberListResponse, error) {
 	return c.MemberListResponse, c.ErrorResponse
 }
+func (c *FakeEtcdClient) MemberPromote(_ context.Context, i uint64) (*clientv3.MemberPromoteResponse, error) {
+	if c.MemberPromoteErr != nil {
+		return nil, c.MemberPromoteErr
+	}
+	c.PromotedMembers = append(c.PromotedMembers, i)
+	return &clientv3.MemberPromoteResponse{}, c.ErrorResponse
+}
 func (c *FakeEtcdClient) MemberRemove(_ context.Context, i uint64) (*clientv3.MemberRemoveResponse, error) {
 	c.RemovedMember = i
 	return c.MemberRemoveResponse, c.ErrorResponse
@@ -0,0 +1,336 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	etcdutil "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd/util"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	// etcdPoolClientPort is the port etcd members of the etcd pool are serving clients on.
+	etcdPoolClientPort = "2379"
+
+	// etcdPoolPeerPort is the port etcd members of the etcd pool are serving peers on.
+	etcdPoolPeerPort = "2380"
+)
+
+// EtcdPool defines operations on the etcd cluster hosted on the etcd pool machines of a KubeadmControlPlane.
+type EtcdPool interface {
+	// AddEtcdMemberForMachine adds an etcd member for the given machine, if it is not a member yet.
+	AddEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine) error
+	// RemoveEtcdMemberForMachine removes the etcd member hosted on the given machine, if any.
+	RemoveEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine) error
+	// UpdateEtcdMemberConditions updates the EtcdMemberHealthy condition of the given etcd pool machines.
+	UpdateEtcdMemberConditions(ctx context.Context, machines collections.Machines)
+}
+
+// etcdPool implements EtcdPool by connecting directly to the etcd pool endpoints.
+type etcdPool struct {
+	endpoints    []string
+	createClient clientCreator
+}
+
+var _ EtcdPool = &etcdPool{}
+
+// newEtcdPool returns an EtcdPool connecting directly to the given endpoints.
+func newEtcdPool(endpoints []string, tlsConfig *tls.Config, etcdDialTimeout, etcdCallTimeout time.Duration) *etcdPool {
+	return &etcdPool{
+		endpoints: endpoints,
+		createClient: func(ctx context.Context, endpoint string) (*etcd.Client, error) {
+			return etcd.NewClient(ctx, etcd.ClientConfiguration{
+				Endpoint:    endpoint,
+				TLSConfig:   tlsConfig,
+				DialTimeout: etcdDialTimeout,
+				CallTimeout: etcdCallTimeout,
+			})
+		},
+	}
+}
+
+// forFirstAvailableEndpoint takes a list of endpoints and returns a client for the first one that connects.
+func (p *etcdPool) forFirstAvailableEndpoint(ctx context.Context, endpoints []string) (*etcd.Client, error) {
+	// This is an additional safeguard for avoiding this func to return nil, nil.
+	if len(endpoints) == 0 {
+		return nil, errors.New("invalid argument: forFirstAvailableEndpoint can't be called with an empty list of endpoints")
+	}
+
+	var errs []error
+	for _, endpoint := range endpoints {
+		client, err := p.createClient(ctx, endpoint)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return client, nil
+	}
+	return nil, errors.Wrap(kerrors.NewAggregate(errs), "could not establish a connection to any etcd pool member")
+}
+
+// otherEndpoints returns the endpoints of the etcd pool, excluding the one of the given machine.
+func (p *etcdPool) otherEndpoints(machine *clusterv1.Machine) []string {
+	machineEndpoint := EtcdPoolEndpointForMachine(machine)
+	var endpoints []string
+	for _, endpoint := range p.endpoints {
+		if endpoint != machineEndpoint {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	return endpoints
+}
+
+// AddEtcdMemberForMachine adds an etcd member advertising a peer URL on the address of the given machine,
+// so etcd can join the existing etcd cluster when starting on the machine.
+// If there are no other members in the etcd pool, the machine is expected to bootstrap a new etcd cluster,
+// and no member is added.
+func (p *etcdPool) AddEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine) error {
+	if machine == nil {
+		return nil
+	}
+
+	peerURL := etcdPoolPeerURLForMachine(machine)
+	if peerURL == "" {
+		return errors.Errorf("machine %s does not report any address", machine.Name)
+	}
+
+	// Exclude the endpoint of the machine being added from the etcd client endpoint list.
+	otherEndpoints := p.otherEndpoints(machine)
+	if len(otherEndpoints) == 0 {
+		return nil
+	}
+
+	etcdClient, err := p.forFirstAvailableEndpoint(ctx, otherEndpoints)
+	if err != nil {
+		return errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+	for _, member := range members {
+		if etcdMemberMatchesMachine(member, machine) {
+			return nil
+		}
+	}
+
+	if _, err := etcdClient.AddMember(ctx, []string{peerURL}); err != nil {
+		return errors.Wrap(err, "failed to add member to etcd")
+	}
+	return nil
+}
+
+// RemoveEtcdMemberForMachine removes the etcd member hosted on the given machine.
+// Removing the last remaining member of the etcd pool is not supported.
+func (p *etcdPool) RemoveEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine) error {
+	if machine == nil {
+		return nil
+	}
+
+	// Exclude the endpoint of the machine being removed from the etcd client endpoint list.
+	remainingEndpoints := p.otherEndpoints(machine)
+	if len(remainingEndpoints) == 0 {
+		return errors.New("cannot remove the last member of the etcd pool")
+	}
+
+	etcdClient, err := p.forFirstAvailableEndpoint(ctx, remainingEndpoints)
+	if err != nil {
+		return errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	// List etcd members. This checks that the member is healthy, because the request goes through consensus.
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+
+	for _, member := range members {
+		if !etcdMemberMatchesMachine(member, machine) {
+			continue
+		}
+		if err := etcdClient.RemoveMember(ctx, member.ID); err != nil {
+			return errors.Wrap(err, "failed to remove member from etcd")
+		}
+	}
+	return nil
+}
+
+// UpdateEtcdMemberConditions updates the EtcdMemberHealthy condition of the given etcd pool machines, connecting
+// to the etcd member hosted on each machine; a member is healthy if it is started, it doesn't report errors or alarms,
+// and it agrees with the other members on the etcd cluster it belongs to.
+func (p *etcdPool) UpdateEtcdMemberConditions(ctx context.Context, machines collections.Machines) {
+	// clusterID is used to store and compare the etcd's cluster id.
+	var clusterID *uint64
+
+	for _, machine := range machines.SortedByCreationTimestamp() {
+		// If the machine is deleting, report the condition as deleting.
+		if !machine.ObjectMeta.DeletionTimestamp.IsZero() {
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, clusterv1.DeletingReason, clusterv1.ConditionSeverityInfo, "")
+			continue
+		}
+
+		endpoint := EtcdPoolEndpointForMachine(machine)
+		if endpoint == "" {
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityInfo, "Waiting for the machine to report an address")
+			continue
+		}
+
+		member, err := p.getEtcdMemberForMachine(ctx, machine, endpoint)
+		if err != nil {
+			continue
+		}
+
+		if len(member.Alarms) > 0 {
+			alarmList := []string{}
+			for _, alarm := range member.Alarms {
+				if alarm != etcd.AlarmOK {
+					alarmList = append(alarmList, etcd.AlarmTypeName[alarm])
+				}
+			}
+			if len(alarmList) > 0 {
+				conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member reports alarms: %s", strings.Join(alarmList, ", "))
+				continue
+			}
+		}
+
+		// Check if the member belongs to the same cluster as all other members.
+		// NOTE: the first member reporting this information is the baseline for this information.
+		if clusterID == nil {
+			clusterID = &member.ClusterID
+		}
+		if *clusterID != member.ClusterID {
+			conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "etcd member has cluster ID %d, but all previously seen etcd members have cluster ID %d", member.ClusterID, *clusterID)
+			continue
+		}
+
+		conditions.MarkTrue(machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	}
+}
+
+// getEtcdMemberForMachine connects to the etcd member hosted on the given machine, and returns the member as
+// reported by itself; if this fails, the EtcdMemberHealthy condition of the machine is updated accordingly.
+func (p *etcdPool) getEtcdMemberForMachine(ctx context.Context, machine *clusterv1.Machine, endpoint string) (*etcd.Member, error) {
+	etcdClient, err := p.createClient(ctx, endpoint)
+	if err != nil {
+		conditions.MarkUnknown(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberInspectionFailedReason, "Failed to connect to the etcd member on %s: %s", endpoint, err)
+		return nil, errors.Wrapf(err, "failed to connect to the etcd member on %s", endpoint)
+	}
+	defer etcdClient.Close()
+
+	// While creating a new client, the status for the endpoint is retrieved; check if the endpoint has errors.
+	if len(etcdClient.Errors) > 0 {
+		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
+		return nil, errors.Errorf("etcd member status reports errors: %s", strings.Join(etcdClient.Errors, ", "))
+	}
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "Failed to get answer from the etcd member on %s", endpoint)
+		return nil, errors.Wrapf(err, "failed to get answer from the etcd member on %s", endpoint)
+	}
+
+	for _, member := range members {
+		if etcdMemberMatchesMachine(member, machine) {
+			return member, nil
+		}
+	}
+	conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "etcd member reports the cluster is composed by members %s, but the member itself is not included", etcdutil.MemberNames(members))
+	return nil, errors.Errorf("etcd member on %s is not included in the etcd members", endpoint)
+}
+
+// EtcdPoolEndpointForMachine returns the client endpoint of the etcd member hosted on an etcd pool machine,
+// or an empty string if the machine doesn't report any address yet.
+func EtcdPoolEndpointForMachine(machine *clusterv1.Machine) string {
+	address := etcdPoolMachineAddress(machine)
+	if address == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(address, etcdPoolClientPort))
+}
+
+// etcdPoolPeerURLForMachine returns the peer URL of the etcd member hosted on an etcd pool machine,
+// or an empty string if the machine doesn't report any address yet.
+func etcdPoolPeerURLForMachine(machine *clusterv1.Machine) string {
+	address := etcdPoolMachineAddress(machine)
+	if address == "" {
+		return ""
+	}
+	return fmt.Sprintf("https://%s", net.JoinHostPort(address, etcdPoolPeerPort))
+}
+
+// EtcdPoolEndpoints returns the sorted list of client endpoints for the given etcd pool machines.
+func EtcdPoolEndpoints(machines collections.Machines) []string {
+	endpoints := []string{}
+	for _, machine := range machines {
+		if endpoint := EtcdPoolEndpointForMachine(machine); endpoint != "" {
+			endpoints = append(endpoints, endpoint)
+		}
+	}
+	sort.Strings(endpoints)
+	return endpoints
+}
+
+// etcdPoolMachineAddress returns the address etcd is serving on for an etcd pool machine;
+// internal addresses are preferred over external ones.
+func etcdPoolMachineAddress(machine *clusterv1.Machine) string {
+	for _, addressType := range []clusterv1.MachineAddressType{clusterv1.MachineInternalIP, clusterv1.MachineExternalIP} {
+		for _, address := range machine.Status.Addresses {
+			if address.Type == addressType && address.Address != "" {
+				return address.Address
+			}
+		}
+	}
+	return ""
+}
+
+// etcdMemberMatchesMachine returns true if the etcd member is hosted on the given machine, either because
+// the member is named after the machine or because it is advertising a peer URL on the machine address.
+func etcdMemberMatchesMachine(member *etcd.Member, machine *clusterv1.Machine) bool {
+	if member.Name != "" && member.Name == machine.Name {
+		return true
+	}
+	for _, peerURL := range member.PeerURLs {
+		u, err := url.Parse(peerURL)
+		if err != nil {
+			continue
+		}
+		for _, address := range machine.Status.Addresses {
+			if address.Address != "" && u.Hostname() == address.Address {
+				return true
+			}
+		}
+	}
+	return false
+}
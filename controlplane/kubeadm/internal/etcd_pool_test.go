@@ -0,0 +1,516 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	clientv3 "go.etcd.io/etcd/client/v3"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd"
+	fake2 "sigs.k8s.io/cluster-api/controlplane/kubeadm/internal/etcd/fake"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestEtcdPoolEndpoints(t *testing.T) {
+	g := NewWithT(t)
+
+	machines := collections.FromMachines(
+		machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"})),
+		machine("etcd-2",
+			withAddresses(
+				clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: "1.2.3.4"},
+				clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"},
+			),
+		),
+		machine("etcd-3", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineExternalIP, Address: "1.2.3.5"})),
+		machine("etcd-4", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineHostName, Address: "etcd-4"})),
+	)
+
+	g.Expect(EtcdPoolEndpoints(machines)).To(Equal([]string{
+		"https://1.2.3.5:2379",
+		"https://10.0.0.1:2379",
+		"https://10.0.0.2:2379",
+	}))
+}
+
+func TestEtcdMemberMatchesMachine(t *testing.T) {
+	m := machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}))
+
+	tests := []struct {
+		name   string
+		member *etcd.Member
+		want   bool
+	}{
+		{
+			name:   "member named after the machine",
+			member: &etcd.Member{Name: "etcd-1"},
+			want:   true,
+		},
+		{
+			name:   "member advertising a peer URL on the machine address",
+			member: &etcd.Member{Name: "ip-10-0-0-1", PeerURLs: []string{"https://10.0.0.1:2380"}},
+			want:   true,
+		},
+		{
+			name:   "member hosted on another machine",
+			member: &etcd.Member{Name: "etcd-2", PeerURLs: []string{"https://10.0.0.2:2380"}},
+			want:   false,
+		},
+		{
+			name:   "member not started yet",
+			member: &etcd.Member{PeerURLs: []string{"https://10.0.0.3:2380"}},
+			want:   false,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(etcdMemberMatchesMachine(tt.member, m)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestEtcdPoolRemoveEtcdMemberForMachine(t *testing.T) {
+	m := machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}))
+	memberList := &clientv3.MemberListResponse{
+		Members: []*pb.Member{
+			{Name: "etcd-1", ID: uint64(1), PeerURLs: []string{"https://10.0.0.1:2380"}},
+			{Name: "etcd-2", ID: uint64(2), PeerURLs: []string{"https://10.0.0.2:2380"}},
+			{Name: "etcd-3", ID: uint64(3), PeerURLs: []string{"https://10.0.0.3:2380"}},
+		},
+	}
+
+	tests := []struct {
+		name              string
+		endpoints         []string
+		fakeClient        *fake2.FakeEtcdClient
+		createClientErr   error
+		expectErr         bool
+		expectRemovedID   uint64
+		expectedEndpoints []string
+	}{
+		{
+			name:      "returns an error when removing the last member",
+			endpoints: []string{"https://10.0.0.1:2379"},
+			expectErr: true,
+		},
+		{
+			name:            "returns an error if it fails to create the etcd client",
+			endpoints:       []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379"},
+			createClientErr: errors.New("no client"),
+			expectErr:       true,
+		},
+		{
+			name:      "returns an error if the client errors getting etcd members",
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				ErrorResponse: errors.New("cannot get etcd members"),
+			},
+			expectErr: true,
+		},
+		{
+			name:      "removes the member from etcd connecting to the remaining members",
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				MemberListResponse: memberList,
+				AlarmResponse: &clientv3.AlarmResponse{
+					Alarms: []*pb.AlarmMember{},
+				},
+			},
+			expectRemovedID:   uint64(1),
+			expectedEndpoints: []string{"https://10.0.0.2:2379"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var usedEndpoints []string
+			pool := &etcdPool{
+				endpoints: tt.endpoints,
+				createClient: func(_ context.Context, endpoint string) (*etcd.Client, error) {
+					usedEndpoints = append(usedEndpoints, endpoint)
+					if tt.createClientErr != nil {
+						return nil, tt.createClientErr
+					}
+					return &etcd.Client{EtcdClient: tt.fakeClient}, nil
+				},
+			}
+
+			err := pool.RemoveEtcdMemberForMachine(ctx, m)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tt.fakeClient.RemovedMember).To(Equal(tt.expectRemovedID))
+			g.Expect(usedEndpoints).To(Equal(tt.expectedEndpoints))
+		})
+	}
+}
+
+func TestEtcdPoolAddEtcdMemberForMachine(t *testing.T) {
+	m := machine("etcd-3", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.3"}))
+	memberList := &clientv3.MemberListResponse{
+		Members: []*pb.Member{
+			{Name: "etcd-1", ID: uint64(1), PeerURLs: []string{"https://10.0.0.1:2380"}},
+			{Name: "etcd-2", ID: uint64(2), PeerURLs: []string{"https://10.0.0.2:2380"}},
+		},
+	}
+
+	tests := []struct {
+		name                string
+		machine             *clusterv1.Machine
+		endpoints           []string
+		fakeClient          *fake2.FakeEtcdClient
+		createClientErr     error
+		expectErr           bool
+		expectAddedPeerURLs []string
+		expectedEndpoints   []string
+	}{
+		{
+			name:      "returns an error if the machine does not report any address",
+			machine:   machine("etcd-3"),
+			endpoints: []string{"https://10.0.0.1:2379"},
+			expectErr: true,
+		},
+		{
+			name:       "does not add a member for the first machine of the etcd pool",
+			machine:    m,
+			endpoints:  []string{"https://10.0.0.3:2379"},
+			fakeClient: &fake2.FakeEtcdClient{},
+		},
+		{
+			name:            "returns an error if it fails to create the etcd client",
+			machine:         m,
+			endpoints:       []string{"https://10.0.0.1:2379", "https://10.0.0.3:2379"},
+			createClientErr: errors.New("no client"),
+			expectErr:       true,
+		},
+		{
+			name:      "returns an error if the client errors getting etcd members",
+			machine:   m,
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.3:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				ErrorResponse: errors.New("cannot get etcd members"),
+			},
+			expectErr: true,
+		},
+		{
+			name:      "returns an error if the client errors adding the etcd member",
+			machine:   m,
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.3:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				MemberListResponse: memberList,
+				AlarmResponse:      &clientv3.AlarmResponse{},
+				MemberAddErr:       errors.New("cannot add etcd member"),
+			},
+			expectErr: true,
+		},
+		{
+			name:      "does not add a member if the machine is already a member",
+			machine:   machine("etcd-2", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"})),
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				MemberListResponse: memberList,
+				AlarmResponse:      &clientv3.AlarmResponse{},
+			},
+			expectedEndpoints: []string{"https://10.0.0.1:2379"},
+		},
+		{
+			name:      "adds the member connecting to the other members",
+			machine:   m,
+			endpoints: []string{"https://10.0.0.1:2379", "https://10.0.0.2:2379", "https://10.0.0.3:2379"},
+			fakeClient: &fake2.FakeEtcdClient{
+				MemberListResponse: memberList,
+				AlarmResponse:      &clientv3.AlarmResponse{},
+				MemberAddResponse: &clientv3.MemberAddResponse{
+					Member: &pb.Member{ID: uint64(3), PeerURLs: []string{"https://10.0.0.3:2380"}},
+				},
+			},
+			expectAddedPeerURLs: []string{"https://10.0.0.3:2380"},
+			expectedEndpoints:   []string{"https://10.0.0.1:2379"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var usedEndpoints []string
+			pool := &etcdPool{
+				endpoints: tt.endpoints,
+				createClient: func(_ context.Context, endpoint string) (*etcd.Client, error) {
+					usedEndpoints = append(usedEndpoints, endpoint)
+					if tt.createClientErr != nil {
+						return nil, tt.createClientErr
+					}
+					return &etcd.Client{EtcdClient: tt.fakeClient}, nil
+				},
+			}
+
+			err := pool.AddEtcdMemberForMachine(ctx, tt.machine)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(tt.fakeClient.AddedMemberPeerURLs).To(Equal(tt.expectAddedPeerURLs))
+			g.Expect(usedEndpoints).To(Equal(tt.expectedEndpoints))
+		})
+	}
+}
+
+func TestEtcdPoolUpdateEtcdMemberConditions(t *testing.T) {
+	memberList := func(clusterID uint64) *clientv3.MemberListResponse {
+		return &clientv3.MemberListResponse{
+			Header: &pb.ResponseHeader{ClusterId: clusterID},
+			Members: []*pb.Member{
+				{Name: "etcd-1", ID: uint64(1), PeerURLs: []string{"https://10.0.0.1:2380"}},
+				{Name: "etcd-2", ID: uint64(2), PeerURLs: []string{"https://10.0.0.2:2380"}},
+			},
+		}
+	}
+	healthyClient := &fake2.FakeEtcdClient{
+		MemberListResponse: memberList(1),
+		AlarmResponse:      &clientv3.AlarmResponse{},
+	}
+
+	tests := []struct {
+		name            string
+		machine         *clusterv1.Machine
+		clients         map[string]*etcd.Client
+		expectedStatus  corev1.ConditionStatus
+		expectedReason  string
+		expectedMessage string
+	}{
+		{
+			name:           "machine being deleted",
+			machine:        machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}), withDeletionTimestamp()),
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: clusterv1.DeletingReason,
+		},
+		{
+			name:            "machine without address",
+			machine:         machine("etcd-1"),
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  controlplanev1.EtcdMemberUnhealthyReason,
+			expectedMessage: "Waiting for the machine to report an address",
+		},
+		{
+			name:           "failure connecting to the etcd member",
+			machine:        machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"})),
+			expectedStatus: corev1.ConditionUnknown,
+			expectedReason: controlplanev1.EtcdMemberInspectionFailedReason,
+		},
+		{
+			name:    "etcd member reporting errors",
+			machine: machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"})),
+			clients: map[string]*etcd.Client{
+				"https://10.0.0.1:2379": {EtcdClient: healthyClient, Errors: []string{"something went wrong"}},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  controlplanev1.EtcdMemberUnhealthyReason,
+			expectedMessage: "Etcd member status reports errors: something went wrong",
+		},
+		{
+			name:    "etcd member not included in the etcd members",
+			machine: machine("etcd-3", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.3"})),
+			clients: map[string]*etcd.Client{
+				"https://10.0.0.3:2379": {EtcdClient: healthyClient},
+			},
+			expectedStatus: corev1.ConditionFalse,
+			expectedReason: controlplanev1.EtcdMemberUnhealthyReason,
+		},
+		{
+			name:    "etcd member reporting alarms",
+			machine: machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"})),
+			clients: map[string]*etcd.Client{
+				"https://10.0.0.1:2379": {EtcdClient: &fake2.FakeEtcdClient{
+					MemberListResponse: memberList(1),
+					AlarmResponse: &clientv3.AlarmResponse{
+						Alarms: []*pb.AlarmMember{{MemberID: uint64(1), Alarm: pb.AlarmType_NOSPACE}},
+					},
+				}},
+			},
+			expectedStatus:  corev1.ConditionFalse,
+			expectedReason:  controlplanev1.EtcdMemberUnhealthyReason,
+			expectedMessage: "Etcd member reports alarms: NOSPACE",
+		},
+		{
+			name:    "healthy etcd member",
+			machine: machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"})),
+			clients: map[string]*etcd.Client{
+				"https://10.0.0.1:2379": {EtcdClient: healthyClient},
+			},
+			expectedStatus: corev1.ConditionTrue,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			pool := &etcdPool{
+				createClient: func(_ context.Context, endpoint string) (*etcd.Client, error) {
+					if c, ok := tt.clients[endpoint]; ok {
+						return c, nil
+					}
+					return nil, errors.New("no client")
+				},
+			}
+
+			pool.UpdateEtcdMemberConditions(ctx, collections.FromMachines(tt.machine))
+
+			condition := conditions.Get(tt.machine, controlplanev1.MachineEtcdMemberHealthyCondition)
+			g.Expect(condition).ToNot(BeNil())
+			g.Expect(condition.Status).To(Equal(tt.expectedStatus))
+			g.Expect(condition.Reason).To(Equal(tt.expectedReason))
+			if tt.expectedMessage != "" {
+				g.Expect(condition.Message).To(Equal(tt.expectedMessage))
+			}
+		})
+	}
+
+	t.Run("etcd members belonging to a different etcd cluster", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m1 := machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}), withCreationTimestamp(metav1.Now()))
+		m2 := machine("etcd-2", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"}), withCreationTimestamp(metav1.NewTime(m1.CreationTimestamp.Add(time.Minute))))
+		clients := map[string]*etcd.Client{
+			"https://10.0.0.1:2379": {EtcdClient: healthyClient},
+			"https://10.0.0.2:2379": {EtcdClient: &fake2.FakeEtcdClient{
+				MemberListResponse: memberList(2),
+				AlarmResponse:      &clientv3.AlarmResponse{},
+			}},
+		}
+		pool := &etcdPool{
+			createClient: func(_ context.Context, endpoint string) (*etcd.Client, error) {
+				return clients[endpoint], nil
+			},
+		}
+
+		pool.UpdateEtcdMemberConditions(ctx, collections.FromMachines(m1, m2))
+
+		g.Expect(conditions.IsTrue(m1, controlplanev1.MachineEtcdMemberHealthyCondition)).To(BeTrue())
+		g.Expect(conditions.IsFalse(m2, controlplanev1.MachineEtcdMemberHealthyCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(m2, controlplanev1.MachineEtcdMemberHealthyCondition)).To(Equal(controlplanev1.EtcdMemberUnhealthyReason))
+	})
+}
+
+func TestControlPlaneWithEtcdPool(t *testing.T) {
+	kcp := &controlplanev1.KubeadmControlPlane{
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+				InitConfiguration: &bootstrapv1.InitConfiguration{},
+				JoinConfiguration: &bootstrapv1.JoinConfiguration{},
+			},
+			EtcdPool: &controlplanev1.EtcdPool{},
+		},
+	}
+	kcp.Status.EtcdPool = &controlplanev1.EtcdPoolStatus{
+		Endpoints: []string{"https://10.0.0.1:2379"},
+	}
+	etcdMachine := machine("etcd-1", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.1"}))
+	etcdMachine.Status.BootstrapReady = true
+	etcdMachine.Status.InfrastructureReady = true
+	conditions.MarkTrue(etcdMachine, controlplanev1.MachineEtcdMemberHealthyCondition)
+	unhealthyEtcdMachine := machine("etcd-2", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.2"}))
+	unhealthyEtcdMachine.Status.BootstrapReady = true
+	unhealthyEtcdMachine.Status.InfrastructureReady = true
+	conditions.MarkFalse(unhealthyEtcdMachine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberUnhealthyReason, clusterv1.ConditionSeverityError, "")
+	provisioningEtcdMachine := machine("etcd-3", withAddresses(clusterv1.MachineAddress{Type: clusterv1.MachineInternalIP, Address: "10.0.0.3"}))
+
+	controlPlane := &ControlPlane{
+		KCP:          kcp,
+		EtcdMachines: collections.FromMachines(etcdMachine, unhealthyEtcdMachine, provisioningEtcdMachine),
+	}
+
+	t.Run("etcd is not managed", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(controlPlane.IsEtcdManaged()).To(BeFalse())
+	})
+
+	t.Run("provisioned etcd machines", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(controlPlane.ProvisionedEtcdPoolMachines().Names()).To(ConsistOf("etcd-1", "etcd-2"))
+	})
+
+	t.Run("ready etcd machines require a healthy etcd member", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(controlPlane.ReadyEtcdPoolMachines().Names()).To(ConsistOf("etcd-1"))
+	})
+
+	t.Run("endpoints are read from the etcd pool status", func(t *testing.T) {
+		g := NewWithT(t)
+		g.Expect(controlPlane.EtcdPoolEndpoints()).To(Equal([]string{"https://10.0.0.1:2379"}))
+	})
+
+	t.Run("bootstrap configs point to the etcd pool", func(t *testing.T) {
+		g := NewWithT(t)
+		expected := &bootstrapv1.ExternalEtcd{
+			Endpoints: []string{"https://10.0.0.1:2379"},
+			CAFile:    "/etc/kubernetes/pki/etcd/ca.crt",
+			CertFile:  "/etc/kubernetes/pki/apiserver-etcd-client.crt",
+			KeyFile:   "/etc/kubernetes/pki/apiserver-etcd-client.key",
+		}
+		g.Expect(controlPlane.InitialControlPlaneConfig().ClusterConfiguration.Etcd.External).To(Equal(expected))
+		g.Expect(controlPlane.JoinControlPlaneConfig().ClusterConfiguration.Etcd.External).To(Equal(expected))
+		g.Expect(kcp.Spec.KubeadmConfigSpec.ClusterConfiguration).To(BeNil())
+	})
+
+	t.Run("machines bootstrapped with outdated endpoints need rollout", func(t *testing.T) {
+		g := NewWithT(t)
+		m := machine("cp-1")
+		m.SetAnnotations(map[string]string{controlplanev1.EtcdPoolEndpointsAnnotation: "https://10.0.0.3:2379"})
+		_, needsRollout := controlPlane.needsEtcdPoolEndpointsRollout(m)
+		g.Expect(needsRollout).To(BeTrue())
+
+		m.SetAnnotations(map[string]string{controlplanev1.EtcdPoolEndpointsAnnotation: "https://10.0.0.1:2379"})
+		_, needsRollout = controlPlane.needsEtcdPoolEndpointsRollout(m)
+		g.Expect(needsRollout).To(BeFalse())
+	})
+}
+
+func withAddresses(addresses ...clusterv1.MachineAddress) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Status.Addresses = addresses
+	}
+}
+
+func withCreationTimestamp(timestamp metav1.Time) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.CreationTimestamp = timestamp
+	}
+}
+
+func withDeletionTimestamp() machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.DeletionTimestamp = ptr.To(metav1.Now())
+	}
+}
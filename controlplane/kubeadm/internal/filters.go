@@ -92,6 +92,21 @@ func NeedsRollout(reconciliationTime, rolloutAfter *metav1.Time, rolloutBefore *
 	return "", false
 }
 
+// matchesEtcdPoolEndpoints checks if a Machine has been bootstrapped with the given etcd pool endpoints
+// and if it doesn't returns the reason why.
+// NOTE: Machines without the EtcdPoolEndpointsAnnotation are considered matching, because we don't have
+// enough information to make a decision.
+func matchesEtcdPoolEndpoints(endpoints []string, machine *clusterv1.Machine) (string, bool) {
+	machineEndpoints, ok := machine.GetAnnotations()[controlplanev1.EtcdPoolEndpointsAnnotation]
+	if !ok {
+		return "", true
+	}
+	if machineEndpoints != strings.Join(endpoints, ",") {
+		return fmt.Sprintf("Machine %s needs rollout: etcd pool endpoints changed from %q to %q", machine.Name, machineEndpoints, strings.Join(endpoints, ",")), false
+	}
+	return "", true
+}
+
 // matchesTemplateClonedFrom checks if a Machine has a corresponding infrastructure machine that
 // matches a given KCP infra template and if it doesn't match returns the reason why.
 // Note: Differences to the labels and annotations on the infrastructure machine are not considered for matching
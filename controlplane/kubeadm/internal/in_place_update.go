@@ -0,0 +1,153 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+// controlPlaneComponents is the subset of the ClusterConfiguration which can be updated in place.
+type controlPlaneComponents struct {
+	APIServer         bootstrapv1.ControlPlaneComponent
+	ControllerManager bootstrapv1.ControlPlaneComponent
+	Scheduler         bootstrapv1.ControlPlaneComponent
+}
+
+// IsInPlaceUpdateEnabled returns true if the control plane components can be updated in place.
+// NOTE: An image must be set explicitly, because it is run with root access on the control plane nodes.
+func (c *ControlPlane) IsInPlaceUpdateEnabled() bool {
+	return feature.Gates.Enabled(feature.KubeadmControlPlaneInPlaceUpdates) &&
+		c.KCP.Spec.RolloutStrategy != nil &&
+		c.KCP.Spec.RolloutStrategy.InPlaceUpdate != nil &&
+		c.KCP.Spec.RolloutStrategy.InPlaceUpdate.Image != ""
+}
+
+// InPlaceUpdateImage returns the image used to run kubeadm on the control plane nodes when updating
+// control plane components in place.
+func (c *ControlPlane) InPlaceUpdateImage() string {
+	if !c.IsInPlaceUpdateEnabled() {
+		return ""
+	}
+	return c.KCP.Spec.RolloutStrategy.InPlaceUpdate.Image
+}
+
+// ControlPlaneComponentsHash returns the hash of the configuration of the control plane components
+// which can be updated in place.
+func (c *ControlPlane) ControlPlaneComponentsHash() (string, error) {
+	return controlPlaneComponentsHash(c.KCP.Spec.KubeadmConfigSpec.ClusterConfiguration)
+}
+
+// MachinesNeedingInPlaceUpdate returns the machines, among the given machines needing rollout, which can be
+// updated in place, i.e. machines whose only changes are to the control plane components configuration.
+// NOTE: If in-place updates are not enabled, no machines are returned.
+func (c *ControlPlane) MachinesNeedingInPlaceUpdate(machinesNeedingRollout collections.Machines) collections.Machines {
+	machines := collections.Machines{}
+	if !c.IsInPlaceUpdateEnabled() {
+		return machines
+	}
+
+	desiredHash, err := c.ControlPlaneComponentsHash()
+	if err != nil {
+		return machines
+	}
+
+	for _, m := range machinesNeedingRollout {
+		if c.canUpdateInPlace(m, desiredHash) {
+			machines.Insert(m)
+		}
+	}
+	return machines
+}
+
+// canUpdateInPlace returns true if the machine would not need rollout once the configuration of its
+// control plane components is updated in place.
+func (c *ControlPlane) canUpdateInPlace(machine *clusterv1.Machine, desiredHash string) bool {
+	// If updating this machine in place already failed for the desired configuration, the machine must be rolled out.
+	if machine.GetAnnotations()[controlplanev1.InPlaceUpdateFailedAnnotation] == desiredHash {
+		return false
+	}
+
+	// If the ClusterConfiguration the machine was created with is unknown, we don't have enough information to make a decision.
+	machineClusterConfig, err := getMachineClusterConfiguration(machine)
+	if err != nil || machineClusterConfig == nil {
+		return false
+	}
+
+	// Check if the machine matches KCP when ignoring the configuration of the control plane components.
+	kcp := c.KCP.DeepCopy()
+	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration == nil {
+		kcp.Spec.KubeadmConfigSpec.ClusterConfiguration = &bootstrapv1.ClusterConfiguration{}
+	}
+	clusterConfig := kcp.Spec.KubeadmConfigSpec.ClusterConfiguration
+	clusterConfig.APIServer.ControlPlaneComponent = machineClusterConfig.APIServer.ControlPlaneComponent
+	clusterConfig.ControllerManager = machineClusterConfig.ControllerManager
+	clusterConfig.Scheduler = machineClusterConfig.Scheduler
+
+	if _, needsRollout := NeedsRollout(&c.reconciliationTime, kcp.Spec.RolloutAfter, kcp.Spec.RolloutBefore, c.InfraResources, c.KubeadmConfigs, kcp, machine); needsRollout {
+		return false
+	}
+	if c.HasEtcdPool() {
+		if _, needsRollout := c.needsEtcdPoolEndpointsRollout(machine); needsRollout {
+			return false
+		}
+	}
+	return true
+}
+
+// getMachineClusterConfiguration returns the ClusterConfiguration stored in the KubeadmClusterConfigurationAnnotation
+// of a machine; nil is returned if the annotation is not set.
+func getMachineClusterConfiguration(machine *clusterv1.Machine) (*bootstrapv1.ClusterConfiguration, error) {
+	machineClusterConfigStr, ok := machine.GetAnnotations()[controlplanev1.KubeadmClusterConfigurationAnnotation]
+	if !ok {
+		return nil, nil
+	}
+
+	machineClusterConfig := &bootstrapv1.ClusterConfiguration{}
+	if err := json.Unmarshal([]byte(machineClusterConfigStr), &machineClusterConfig); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal %s annotation of Machine %s", controlplanev1.KubeadmClusterConfigurationAnnotation, machine.Name)
+	}
+	if machineClusterConfig == nil {
+		machineClusterConfig = &bootstrapv1.ClusterConfiguration{}
+	}
+	return machineClusterConfig, nil
+}
+
+// controlPlaneComponentsHash returns the hash of the configuration of the control plane components
+// which can be updated in place.
+func controlPlaneComponentsHash(clusterConfig *bootstrapv1.ClusterConfiguration) (string, error) {
+	components := controlPlaneComponents{}
+	if clusterConfig != nil {
+		components.APIServer = clusterConfig.APIServer.ControlPlaneComponent
+		components.ControllerManager = clusterConfig.ControllerManager
+		components.Scheduler = clusterConfig.Scheduler
+	}
+	h, err := hash.Compute(components)
+	if err != nil {
+		return "", errors.Wrap(err, "failed to compute hash of the control plane components configuration")
+	}
+	return fmt.Sprintf("%d", h), nil
+}
@@ -0,0 +1,245 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	batchv1 "k8s.io/api/batch/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	utilfeature "k8s.io/component-base/featuregate/testing"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
+	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
+	"sigs.k8s.io/cluster-api/util/collections"
+)
+
+const testInPlaceUpdateImage = "example.com/kubeadm-runner@sha256:0000000000000000000000000000000000000000000000000000000000000000"
+
+func TestMachinesNeedingInPlaceUpdate(t *testing.T) {
+	currentClusterConfig := &bootstrapv1.ClusterConfiguration{
+		APIServer: bootstrapv1.APIServer{
+			ControlPlaneComponent: bootstrapv1.ControlPlaneComponent{ExtraArgs: map[string]string{"v": "2"}},
+		},
+		ImageRepository: "registry.k8s.io",
+	}
+
+	desiredClusterConfig := currentClusterConfig.DeepCopy()
+	desiredClusterConfig.APIServer.ExtraArgs["v"] = "4"
+	desiredClusterConfig.Scheduler.ExtraArgs = map[string]string{"v": "4"}
+	desiredHash, err := controlPlaneComponentsHash(desiredClusterConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherClusterConfig := desiredClusterConfig.DeepCopy()
+	otherClusterConfig.ImageRepository = "example.com"
+
+	inPlaceUpdate := &controlplanev1.InPlaceUpdate{Image: testInPlaceUpdateImage}
+
+	tests := []struct {
+		name          string
+		disableGate   bool
+		inPlaceUpdate *controlplanev1.InPlaceUpdate
+		machine       *clusterv1.Machine
+		want          bool
+	}{
+		{
+			name:          "machine with only control plane components changes can be updated in place",
+			inPlaceUpdate: inPlaceUpdate,
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, nil),
+			want:          true,
+		},
+		{
+			name:          "machine is not updated in place if in-place updates are not enabled",
+			inPlaceUpdate: nil,
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, nil),
+			want:          false,
+		},
+		{
+			name:          "machine is not updated in place if the feature gate is disabled",
+			disableGate:   true,
+			inPlaceUpdate: inPlaceUpdate,
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, nil),
+			want:          false,
+		},
+		{
+			name:          "machine is not updated in place if the image is not set",
+			inPlaceUpdate: &controlplanev1.InPlaceUpdate{},
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, nil),
+			want:          false,
+		},
+		{
+			name:          "machine with other ClusterConfiguration changes can't be updated in place",
+			inPlaceUpdate: inPlaceUpdate,
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.30.0", otherClusterConfig, nil),
+			want:          false,
+		},
+		{
+			name:          "machine with a different version can't be updated in place",
+			inPlaceUpdate: inPlaceUpdate,
+			machine:       inPlaceUpdateMachine(t, "m1", "v1.29.0", currentClusterConfig, nil),
+			want:          false,
+		},
+		{
+			name:          "machine which failed to be updated in place to the desired configuration can't be updated in place",
+			inPlaceUpdate: inPlaceUpdate,
+			machine: inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, map[string]string{
+				controlplanev1.InPlaceUpdateFailedAnnotation: desiredHash,
+			}),
+			want: false,
+		},
+		{
+			name:          "machine which failed to be updated in place to a previous configuration can be updated in place",
+			inPlaceUpdate: inPlaceUpdate,
+			machine: inPlaceUpdateMachine(t, "m1", "v1.30.0", currentClusterConfig, map[string]string{
+				controlplanev1.InPlaceUpdateFailedAnnotation: "12345",
+			}),
+			want: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeadmControlPlaneInPlaceUpdates, !tt.disableGate)()
+
+			g := NewWithT(t)
+
+			kcp := &controlplanev1.KubeadmControlPlane{
+				Spec: controlplanev1.KubeadmControlPlaneSpec{
+					Version: "v1.30.0",
+					KubeadmConfigSpec: bootstrapv1.KubeadmConfigSpec{
+						ClusterConfiguration: desiredClusterConfig,
+					},
+					RolloutStrategy: &controlplanev1.RolloutStrategy{
+						Type:          controlplanev1.RollingUpdateStrategyType,
+						InPlaceUpdate: tt.inPlaceUpdate,
+					},
+				},
+			}
+			controlPlane := &ControlPlane{
+				KCP:      kcp,
+				Machines: collections.FromMachines(tt.machine),
+			}
+
+			machinesNeedingRollout, _ := controlPlane.MachinesNeedingRollout()
+			g.Expect(machinesNeedingRollout).To(HaveKey(tt.machine.Name))
+			_, ok := controlPlane.MachinesNeedingInPlaceUpdate(machinesNeedingRollout)[tt.machine.Name]
+			g.Expect(ok).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestInPlaceUpdateImage(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := &ControlPlane{
+		KCP: &controlplanev1.KubeadmControlPlane{
+			Spec: controlplanev1.KubeadmControlPlaneSpec{
+				RolloutStrategy: &controlplanev1.RolloutStrategy{
+					InPlaceUpdate: &controlplanev1.InPlaceUpdate{Image: testInPlaceUpdateImage},
+				},
+			},
+		},
+	}
+	// There is no default image, because the image is run with root access on the control plane nodes.
+	g.Expect(controlPlane.InPlaceUpdateImage()).To(BeEmpty())
+
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeadmControlPlaneInPlaceUpdates, true)()
+	g.Expect(controlPlane.InPlaceUpdateImage()).To(Equal(testInPlaceUpdateImage))
+}
+
+func TestUpdateControlPlaneComponentsInPlace(t *testing.T) {
+	g := NewWithT(t)
+
+	fakeClient := fake.NewClientBuilder().Build()
+	w := &Workload{
+		Client: fakeClient,
+	}
+
+	// The first call creates the Job.
+	status, err := w.UpdateControlPlaneComponentsInPlace(ctx, "node-1", "example.com/image:v1", "12345", "/etc/kubeadm/patches")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status).To(Equal(InPlaceUpdatePending))
+
+	jobs := &batchv1.JobList{}
+	g.Expect(fakeClient.List(ctx, jobs, ctrlclient.InNamespace(metav1.NamespaceSystem), ctrlclient.HasLabels{InPlaceUpdateJobLabel})).To(Succeed())
+	g.Expect(jobs.Items).To(HaveLen(1))
+	job := &jobs.Items[0]
+	g.Expect(job.Annotations).To(HaveKeyWithValue(inPlaceUpdateNodeAnnotation, "node-1"))
+	g.Expect(job.Annotations).To(HaveKeyWithValue(inPlaceUpdateHashAnnotation, "12345"))
+	g.Expect(job.Spec.BackoffLimit).To(Equal(ptr.To[int32](0)))
+	podSpec := job.Spec.Template.Spec
+	g.Expect(podSpec.NodeName).To(Equal("node-1"))
+	g.Expect(podSpec.HostPID).To(BeTrue())
+	g.Expect(podSpec.Containers).To(HaveLen(1))
+	g.Expect(podSpec.Containers[0].Image).To(Equal("example.com/image:v1"))
+	g.Expect(podSpec.Containers[0].Command).To(Equal([]string{
+		"chroot", "/host", "kubeadm", "upgrade", "node", "phase", "control-plane", "--certificate-renewal=false", "--etcd-upgrade=false",
+		"--patches", "/etc/kubeadm/patches",
+	}))
+
+	// While the Job is running, the update is pending.
+	status, err = w.UpdateControlPlaneComponentsInPlace(ctx, "node-1", "example.com/image:v1", "12345", "/etc/kubeadm/patches")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status).To(Equal(InPlaceUpdatePending))
+
+	// When the Job completes, the status is reported and the Job is deleted.
+	job.Status.Failed = 1
+	g.Expect(fakeClient.Status().Update(ctx, job)).To(Succeed())
+	status, err = w.UpdateControlPlaneComponentsInPlace(ctx, "node-1", "example.com/image:v1", "12345", "/etc/kubeadm/patches")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(status).To(Equal(InPlaceUpdateFailed))
+
+	g.Expect(fakeClient.List(ctx, jobs, ctrlclient.InNamespace(metav1.NamespaceSystem), ctrlclient.HasLabels{InPlaceUpdateJobLabel})).To(Succeed())
+	g.Expect(jobs.Items).To(BeEmpty())
+
+	// A different configuration uses a different Job.
+	otherName, err := inPlaceUpdateJobName("node-1", "67890")
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(otherName).ToNot(Equal(job.Name))
+}
+
+func inPlaceUpdateMachine(t *testing.T, name, version string, clusterConfig *bootstrapv1.ClusterConfiguration, annotations map[string]string) *clusterv1.Machine {
+	t.Helper()
+
+	clusterConfigJSON, err := json.Marshal(clusterConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := machine(name, withVersion(version))
+	m.Annotations = map[string]string{
+		controlplanev1.KubeadmClusterConfigurationAnnotation: string(clusterConfigJSON),
+	}
+	for k, v := range annotations {
+		m.Annotations[k] = v
+	}
+	return m
+}
+
+func withVersion(version string) machineOpt {
+	return func(m *clusterv1.Machine) {
+		m.Spec.Version = &version
+	}
+}
@@ -26,10 +26,12 @@ import (
 	"github.com/coredns/corefile-migration/migration"
 	jsonpatch "github.com/evanphx/json-patch/v5"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
+	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
 	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
@@ -37,6 +39,7 @@ import (
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	bootstrapv1 "sigs.k8s.io/cluster-api/bootstrap/kubeadm/api/v1beta1"
 	controlplanev1 "sigs.k8s.io/cluster-api/controlplane/kubeadm/api/v1beta1"
+	"sigs.k8s.io/cluster-api/feature"
 	"sigs.k8s.io/cluster-api/internal/util/kubeadm"
 	"sigs.k8s.io/cluster-api/util/container"
 	"sigs.k8s.io/cluster-api/util/version"
@@ -88,6 +91,26 @@ func defaultKubeadmControlPlaneSpec(s *controlplanev1.KubeadmControlPlaneSpec, n
 	s.KubeadmConfigSpec.Default()
 
 	s.RolloutStrategy = defaultRolloutStrategy(s.RolloutStrategy)
+
+	defaultEtcdPool(s.EtcdPool, namespace)
+}
+
+func defaultEtcdPool(etcdPool *controlplanev1.EtcdPool, namespace string) {
+	if etcdPool == nil {
+		return
+	}
+
+	if etcdPool.Replicas == nil {
+		etcdPool.Replicas = ptr.To[int32](3)
+	}
+
+	if etcdPool.MachineTemplate.InfrastructureRef.Namespace == "" {
+		etcdPool.MachineTemplate.InfrastructureRef.Namespace = namespace
+	}
+
+	if etcdPool.MachineTemplate.BootstrapConfigRef.Namespace == "" {
+		etcdPool.MachineTemplate.BootstrapConfigRef.Namespace = namespace
+	}
 }
 
 func defaultRolloutStrategy(rolloutStrategy *controlplanev1.RolloutStrategy) *controlplanev1.RolloutStrategy {
@@ -151,6 +174,7 @@ const (
 	ignition             = "ignition"
 	diskSetup            = "diskSetup"
 	featureGates         = "featureGates"
+	etcdPool             = "etcdPool"
 )
 
 const minimumCertificatesExpiryDays = 7
@@ -239,6 +263,17 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 		{spec, "rolloutBefore", "*"},
 		{spec, "rolloutStrategy"},
 		{spec, "rolloutStrategy", "*"},
+		// spec.etcdPool
+		{spec, etcdPool, "replicas"},
+		{spec, etcdPool, "machineTemplate", "metadata"},
+		{spec, etcdPool, "machineTemplate", "metadata", "*"},
+		{spec, etcdPool, "machineTemplate", "infrastructureRef", "apiVersion"},
+		{spec, etcdPool, "machineTemplate", "infrastructureRef", "name"},
+		{spec, etcdPool, "machineTemplate", "infrastructureRef", "kind"},
+		{spec, etcdPool, "machineTemplate", "bootstrapConfigRef", "apiVersion"},
+		{spec, etcdPool, "machineTemplate", "bootstrapConfigRef", "name"},
+		{spec, etcdPool, "machineTemplate", "bootstrapConfigRef", "kind"},
+		{spec, etcdPool, "machineTemplate", "nodeDeletionTimeout"},
 	}
 
 	oldK, ok := oldObj.(*controlplanev1.KubeadmControlPlane)
@@ -287,6 +322,12 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 		}
 	}
 
+	// The etcd pool cannot be added or removed after creation, because this would require
+	// migrating etcd data from stacked members to the pool or vice versa.
+	if (oldK.Spec.EtcdPool == nil) != (newK.Spec.EtcdPool == nil) {
+		allErrs = append(allErrs, field.Forbidden(field.NewPath(spec, etcdPool), "cannot be added or removed"))
+	}
+
 	allErrs = append(allErrs, webhook.validateVersion(oldK, newK)...)
 	allErrs = append(allErrs, validateClusterConfiguration(oldK.Spec.KubeadmConfigSpec.ClusterConfiguration, newK.Spec.KubeadmConfigSpec.ClusterConfiguration, field.NewPath("spec", "kubeadmConfigSpec", "clusterConfiguration"))...)
 	allErrs = append(allErrs, webhook.validateCoreDNSVersion(oldK, newK)...)
@@ -299,6 +340,27 @@ func (webhook *KubeadmControlPlane) ValidateUpdate(_ context.Context, oldObj, ne
 	return nil, nil
 }
 
+// isEtcdLearnerModeEnabled returns true if control plane machines join the stacked etcd cluster as learners, i.e.
+// if learners are supported for the Kubernetes and etcd versions in use and the kubeadm EtcdLearnerMode feature gate
+// is not explicitly disabled.
+// NOTE: KCP enables the EtcdLearnerMode feature gate when learners are supported, see ControlPlane.KubeadmFeatureGates.
+func isEtcdLearnerModeEnabled(s controlplanev1.KubeadmControlPlaneSpec) bool {
+	kubernetesVersion, err := version.ParseMajorMinorPatchTolerant(s.Version)
+	if err != nil {
+		return false
+	}
+	etcdImageTag := ""
+	if s.KubeadmConfigSpec.ClusterConfiguration != nil {
+		if s.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil {
+			etcdImageTag = s.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local.ImageTag
+		}
+		if enabled, ok := s.KubeadmConfigSpec.ClusterConfiguration.FeatureGates[kubeadm.EtcdLearnerModeFeatureGate]; ok && !enabled {
+			return false
+		}
+	}
+	return kubeadm.IsEtcdLearnerModeSupported(kubernetesVersion, etcdImageTag)
+}
+
 func validateKubeadmControlPlaneSpec(s controlplanev1.KubeadmControlPlaneSpec, namespace string, pathPrefix *field.Path) field.ErrorList {
 	allErrs := field.ErrorList{}
 
@@ -323,20 +385,22 @@ func validateKubeadmControlPlaneSpec(s controlplanev1.KubeadmControlPlaneSpec, n
 		)
 	}
 
-	externalEtcd := false
+	externalEtcd := s.EtcdPool != nil
 	if s.KubeadmConfigSpec.ClusterConfiguration != nil {
 		if s.KubeadmConfigSpec.ClusterConfiguration.Etcd.External != nil {
 			externalEtcd = true
 		}
 	}
 
-	if !externalEtcd {
+	// NOTE: Even replicas are allowed when control plane machines join the stacked etcd cluster as learners,
+	// because learners do not count towards quorum until they are promoted.
+	if !externalEtcd && !isEtcdLearnerModeEnabled(s) {
 		if s.Replicas != nil && *s.Replicas%2 == 0 {
 			allErrs = append(
 				allErrs,
 				field.Forbidden(
 					pathPrefix.Child("replicas"),
-					"cannot be an even number when etcd is stacked",
+					"cannot be an even number when etcd is stacked, unless etcd learners are used",
 				),
 			)
 		}
@@ -392,6 +456,59 @@ func validateKubeadmControlPlaneSpec(s controlplanev1.KubeadmControlPlaneSpec, n
 
 	allErrs = append(allErrs, validateRolloutBefore(s.RolloutBefore, pathPrefix.Child("rolloutBefore"))...)
 	allErrs = append(allErrs, validateRolloutStrategy(s.RolloutStrategy, s.Replicas, pathPrefix.Child("rolloutStrategy"))...)
+	allErrs = append(allErrs, validateEtcdPool(s, namespace, pathPrefix.Child(etcdPool))...)
+
+	return allErrs
+}
+
+func validateEtcdPool(s controlplanev1.KubeadmControlPlaneSpec, namespace string, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if s.EtcdPool == nil {
+		return allErrs
+	}
+
+	if s.KubeadmConfigSpec.ClusterConfiguration != nil &&
+		(s.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local != nil || s.KubeadmConfigSpec.ClusterConfiguration.Etcd.External != nil) {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(
+				field.NewPath(spec, kubeadmConfigSpec, clusterConfiguration, "etcd"),
+				"cannot be set when etcdPool is set",
+			),
+		)
+	}
+
+	if s.EtcdPool.Replicas != nil {
+		if *s.EtcdPool.Replicas <= 0 {
+			allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("replicas"), "cannot be less than or equal to 0"))
+		} else if *s.EtcdPool.Replicas%2 == 0 {
+			allErrs = append(allErrs, field.Forbidden(pathPrefix.Child("replicas"), "cannot be an even number"))
+		}
+	}
+
+	allErrs = append(allErrs, validateEtcdPoolTemplateRef(s.EtcdPool.MachineTemplate.InfrastructureRef, namespace, pathPrefix.Child("machineTemplate", "infrastructureRef"))...)
+	allErrs = append(allErrs, validateEtcdPoolTemplateRef(s.EtcdPool.MachineTemplate.BootstrapConfigRef, namespace, pathPrefix.Child("machineTemplate", "bootstrapConfigRef"))...)
+	allErrs = append(allErrs, s.EtcdPool.MachineTemplate.ObjectMeta.Validate(pathPrefix.Child("machineTemplate", "metadata"))...)
+
+	return allErrs
+}
+
+func validateEtcdPoolTemplateRef(ref corev1.ObjectReference, namespace string, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if ref.APIVersion == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("apiVersion"), ref.APIVersion, "cannot be empty"))
+	}
+	if ref.Kind == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("kind"), ref.Kind, "cannot be empty"))
+	}
+	if ref.Name == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("name"), ref.Name, "cannot be empty"))
+	}
+	if ref.Namespace != namespace {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("namespace"), ref.Namespace, "must match metadata.namespace"))
+	}
 
 	return allErrs
 }
@@ -452,6 +569,36 @@ func validateRolloutStrategy(rolloutStrategy *controlplanev1.RolloutStrategy, re
 		)
 	}
 
+	allErrs = append(allErrs, validateInPlaceUpdate(rolloutStrategy.InPlaceUpdate, pathPrefix.Child("inPlaceUpdate"))...)
+
+	return allErrs
+}
+
+func validateInPlaceUpdate(inPlaceUpdate *controlplanev1.InPlaceUpdate, pathPrefix *field.Path) field.ErrorList {
+	allErrs := field.ErrorList{}
+
+	if inPlaceUpdate == nil {
+		return allErrs
+	}
+
+	// In-place updates run a privileged Job on the control plane nodes of the workload cluster;
+	// the web hook must prevent enabling them in case the feature flag is disabled.
+	if !feature.Gates.Enabled(feature.KubeadmControlPlaneInPlaceUpdates) {
+		return append(allErrs, field.Forbidden(pathPrefix, "can be set only if the KubeadmControlPlaneInPlaceUpdates feature flag is enabled"))
+	}
+
+	if inPlaceUpdate.Image == "" {
+		return append(allErrs, field.Required(pathPrefix.Child("image"), "image must be set"))
+	}
+
+	image, err := container.ImageFromString(inPlaceUpdate.Image)
+	if err != nil {
+		return append(allErrs, field.Invalid(pathPrefix.Child("image"), inPlaceUpdate.Image, err.Error()))
+	}
+	if image.Digest == "" {
+		allErrs = append(allErrs, field.Invalid(pathPrefix.Child("image"), inPlaceUpdate.Image, "image must be pinned by digest"))
+	}
+
 	return allErrs
 }
 
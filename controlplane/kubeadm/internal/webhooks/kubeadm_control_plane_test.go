@@ -78,6 +78,46 @@ func TestKubeadmControlPlaneDefault(t *testing.T) {
 	g.Expect(kcp.Spec.RolloutStrategy.RollingUpdate.MaxSurge.IntVal).To(Equal(int32(1)))
 }
 
+func TestKubeadmControlPlaneDefaultEtcdPool(t *testing.T) {
+	g := NewWithT(t)
+
+	kcp := &controlplanev1.KubeadmControlPlane{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foo",
+		},
+		Spec: controlplanev1.KubeadmControlPlaneSpec{
+			Version: "v1.18.3",
+			MachineTemplate: controlplanev1.KubeadmControlPlaneMachineTemplate{
+				InfrastructureRef: corev1.ObjectReference{
+					APIVersion: "test/v1alpha1",
+					Kind:       "UnknownInfraMachine",
+					Name:       "foo",
+				},
+			},
+			EtcdPool: &controlplanev1.EtcdPool{
+				MachineTemplate: controlplanev1.EtcdPoolMachineTemplate{
+					InfrastructureRef: corev1.ObjectReference{
+						APIVersion: "test/v1alpha1",
+						Kind:       "UnknownInfraMachine",
+						Name:       "etcd",
+					},
+					BootstrapConfigRef: corev1.ObjectReference{
+						APIVersion: "test/v1alpha1",
+						Kind:       "UnknownBootstrapConfigTemplate",
+						Name:       "etcd",
+					},
+				},
+			},
+		},
+	}
+	webhook := &KubeadmControlPlane{}
+	g.Expect(webhook.Default(ctx, kcp)).To(Succeed())
+
+	g.Expect(kcp.Spec.EtcdPool.Replicas).To(Equal(ptr.To[int32](3)))
+	g.Expect(kcp.Spec.EtcdPool.MachineTemplate.InfrastructureRef.Namespace).To(Equal(kcp.Namespace))
+	g.Expect(kcp.Spec.EtcdPool.MachineTemplate.BootstrapConfigRef.Namespace).To(Equal(kcp.Namespace))
+}
+
 func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	valid := &controlplanev1.KubeadmControlPlane{
 		ObjectMeta: metav1.ObjectMeta{
@@ -137,6 +177,51 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 		},
 	}
 
+	evenReplicasEtcdLearners := evenReplicas.DeepCopy()
+	evenReplicasEtcdLearners.Spec.Version = "v1.29.0"
+
+	evenReplicasEtcdLearnersUnsupportedEtcd := evenReplicasEtcdLearners.DeepCopy()
+	evenReplicasEtcdLearnersUnsupportedEtcd.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.Local = &bootstrapv1.LocalEtcd{
+		ImageMeta: bootstrapv1.ImageMeta{
+			ImageTag: "3.3.17-0",
+		},
+	}
+
+	evenReplicasEtcdLearnersDisabled := evenReplicasEtcdLearners.DeepCopy()
+	evenReplicasEtcdLearnersDisabled.Spec.KubeadmConfigSpec.ClusterConfiguration.FeatureGates = map[string]bool{"EtcdLearnerMode": false}
+
+	validEtcdPool := valid.DeepCopy()
+	validEtcdPool.Spec.Replicas = ptr.To[int32](2)
+	validEtcdPool.Spec.EtcdPool = &controlplanev1.EtcdPool{
+		Replicas: ptr.To[int32](3),
+		MachineTemplate: controlplanev1.EtcdPoolMachineTemplate{
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "test/v1alpha1",
+				Kind:       "UnknownInfraMachine",
+				Namespace:  "foo",
+				Name:       "etcdInfraTemplate",
+			},
+			BootstrapConfigRef: corev1.ObjectReference{
+				APIVersion: "test/v1alpha1",
+				Kind:       "UnknownBootstrapConfigTemplate",
+				Namespace:  "foo",
+				Name:       "etcdBootstrapTemplate",
+			},
+		},
+	}
+
+	evenReplicasEtcdPool := validEtcdPool.DeepCopy()
+	evenReplicasEtcdPool.Spec.EtcdPool.Replicas = ptr.To[int32](2)
+
+	etcdPoolWithExternalEtcd := validEtcdPool.DeepCopy()
+	etcdPoolWithExternalEtcd.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External = &bootstrapv1.ExternalEtcd{}
+
+	etcdPoolInvalidNamespace := validEtcdPool.DeepCopy()
+	etcdPoolInvalidNamespace.Spec.EtcdPool.MachineTemplate.BootstrapConfigRef.Namespace = invalidNamespaceName
+
+	etcdPoolMissingBootstrapConfigRef := validEtcdPool.DeepCopy()
+	etcdPoolMissingBootstrapConfigRef.Spec.EtcdPool.MachineTemplate.BootstrapConfigRef = corev1.ObjectReference{Namespace: "foo"}
+
 	validVersion := valid.DeepCopy()
 	validVersion.Spec.Version = "v1.16.6"
 
@@ -154,6 +239,19 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 		CertificatesExpiryDays: ptr.To[int32](5), // less than minimum
 	}
 
+	validInPlaceUpdate := valid.DeepCopy()
+	validInPlaceUpdate.Spec.RolloutStrategy.InPlaceUpdate = &controlplanev1.InPlaceUpdate{
+		Image: "example.com/kubeadm-runner@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+	}
+
+	inPlaceUpdateWithoutImage := valid.DeepCopy()
+	inPlaceUpdateWithoutImage.Spec.RolloutStrategy.InPlaceUpdate = &controlplanev1.InPlaceUpdate{}
+
+	inPlaceUpdateWithImageNotPinned := valid.DeepCopy()
+	inPlaceUpdateWithImageNotPinned.Spec.RolloutStrategy.InPlaceUpdate = &controlplanev1.InPlaceUpdate{
+		Image: "example.com/kubeadm-runner:v1",
+	}
+
 	invalidIgnitionConfiguration := valid.DeepCopy()
 	invalidIgnitionConfiguration.Spec.KubeadmConfigSpec.Ignition = &bootstrapv1.IgnitionSpec{}
 
@@ -172,10 +270,11 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 	}
 
 	tests := []struct {
-		name                  string
-		enableIgnitionFeature bool
-		expectErr             bool
-		kcp                   *controlplanev1.KubeadmControlPlane
+		name                       string
+		enableIgnitionFeature      bool
+		enableInPlaceUpdateFeature bool
+		expectErr                  bool
+		kcp                        *controlplanev1.KubeadmControlPlane
 	}{
 		{
 			name:      "should succeed when given a valid config",
@@ -207,6 +306,46 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: false,
 			kcp:       evenReplicasExternalEtcd,
 		},
+		{
+			name:      "should allow even replicas when using etcd learners",
+			expectErr: false,
+			kcp:       evenReplicasEtcdLearners,
+		},
+		{
+			name:      "should return error when replicas is even and etcd does not support learners",
+			expectErr: true,
+			kcp:       evenReplicasEtcdLearnersUnsupportedEtcd,
+		},
+		{
+			name:      "should return error when replicas is even and etcd learners are disabled",
+			expectErr: true,
+			kcp:       evenReplicasEtcdLearnersDisabled,
+		},
+		{
+			name:      "should allow even replicas when using an etcd pool",
+			expectErr: false,
+			kcp:       validEtcdPool,
+		},
+		{
+			name:      "should return error when etcd pool replicas is even",
+			expectErr: true,
+			kcp:       evenReplicasEtcdPool,
+		},
+		{
+			name:      "should return error when etcd pool is set together with clusterConfiguration.etcd",
+			expectErr: true,
+			kcp:       etcdPoolWithExternalEtcd,
+		},
+		{
+			name:      "should return error when kubeadmControlPlane namespace and etcd pool template namespace mismatch",
+			expectErr: true,
+			kcp:       etcdPoolInvalidNamespace,
+		},
+		{
+			name:      "should return error when etcd pool bootstrapConfigRef is missing",
+			expectErr: true,
+			kcp:       etcdPoolMissingBootstrapConfigRef,
+		},
 		{
 			name:      "should succeed when given a valid semantic version with prepended 'v'",
 			expectErr: false,
@@ -242,6 +381,29 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 			expectErr: true,
 			kcp:       invalidRolloutBeforeCertificateExpiryDays,
 		},
+		{
+			name:      "should return error when rolloutStrategy.inPlaceUpdate is set and the feature flag is disabled",
+			expectErr: true,
+			kcp:       validInPlaceUpdate,
+		},
+		{
+			name:                       "should succeed when rolloutStrategy.inPlaceUpdate has an image pinned by digest",
+			enableInPlaceUpdateFeature: true,
+			expectErr:                  false,
+			kcp:                        validInPlaceUpdate,
+		},
+		{
+			name:                       "should return error when rolloutStrategy.inPlaceUpdate.image is not set",
+			enableInPlaceUpdateFeature: true,
+			expectErr:                  true,
+			kcp:                        inPlaceUpdateWithoutImage,
+		},
+		{
+			name:                       "should return error when rolloutStrategy.inPlaceUpdate.image is not pinned by digest",
+			enableInPlaceUpdateFeature: true,
+			expectErr:                  true,
+			kcp:                        inPlaceUpdateWithImageNotPinned,
+		},
 
 		{
 			name:                  "should return error when Ignition configuration is invalid",
@@ -270,6 +432,11 @@ func TestKubeadmControlPlaneValidateCreate(t *testing.T) {
 				// Enabling the feature flag temporarily for this test.
 				defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeadmBootstrapFormatIgnition, true)()
 			}
+			if tt.enableInPlaceUpdateFeature {
+				// NOTE: KubeadmControlPlaneInPlaceUpdates feature flag is disabled by default.
+				// Enabling the feature flag temporarily for this test.
+				defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.KubeadmControlPlaneInPlaceUpdates, true)()
+			}
 
 			g := NewWithT(t)
 
@@ -700,6 +867,32 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 		"/invalid-key": "foo",
 	}
 
+	addEtcdPool := before.DeepCopy()
+	addEtcdPool.Spec.EtcdPool = &controlplanev1.EtcdPool{
+		Replicas: ptr.To[int32](3),
+		MachineTemplate: controlplanev1.EtcdPoolMachineTemplate{
+			InfrastructureRef: corev1.ObjectReference{
+				APIVersion: "test/v1alpha1",
+				Kind:       "UnknownInfraMachine",
+				Namespace:  "foo",
+				Name:       "etcdInfraTemplate",
+			},
+			BootstrapConfigRef: corev1.ObjectReference{
+				APIVersion: "test/v1alpha1",
+				Kind:       "UnknownBootstrapConfigTemplate",
+				Namespace:  "foo",
+				Name:       "etcdBootstrapTemplate",
+			},
+		},
+	}
+
+	scaleEtcdPool := addEtcdPool.DeepCopy()
+	scaleEtcdPool.Spec.EtcdPool.Replicas = ptr.To[int32](5)
+
+	rotateEtcdPoolTemplates := addEtcdPool.DeepCopy()
+	rotateEtcdPoolTemplates.Spec.EtcdPool.MachineTemplate.InfrastructureRef.Name = "etcdInfraTemplate-2"
+	rotateEtcdPoolTemplates.Spec.EtcdPool.MachineTemplate.BootstrapConfigRef.Name = "etcdBootstrapTemplate-2"
+
 	beforeUseExperimentalRetryJoin := before.DeepCopy()
 	beforeUseExperimentalRetryJoin.Spec.KubeadmConfigSpec.UseExperimentalRetryJoin = true //nolint:staticcheck
 	updateUseExperimentalRetryJoin := before.DeepCopy()
@@ -1045,6 +1238,30 @@ func TestKubeadmControlPlaneValidateUpdate(t *testing.T) {
 			before:    beforeUseExperimentalRetryJoin,
 			kcp:       updateUseExperimentalRetryJoin,
 		},
+		{
+			name:      "should return error when adding an etcd pool",
+			expectErr: true,
+			before:    before,
+			kcp:       addEtcdPool,
+		},
+		{
+			name:      "should return error when removing an etcd pool",
+			expectErr: true,
+			before:    addEtcdPool,
+			kcp:       before,
+		},
+		{
+			name:      "should allow scaling the etcd pool",
+			expectErr: false,
+			before:    addEtcdPool,
+			kcp:       scaleEtcdPool,
+		},
+		{
+			name:      "should allow rotating the etcd pool templates",
+			expectErr: false,
+			before:    addEtcdPool,
+			kcp:       rotateEtcdPoolTemplates,
+		},
 	}
 
 	for _, tt := range tests {
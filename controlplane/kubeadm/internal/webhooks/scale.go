@@ -67,7 +67,7 @@ func (v *ScaleValidator) Handle(ctx context.Context, req admission.Request) admi
 		return admission.Denied("replicas cannot be 0")
 	}
 
-	externalEtcd := false
+	externalEtcd := kcp.Spec.EtcdPool != nil
 	if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration != nil {
 		if kcp.Spec.KubeadmConfigSpec.ClusterConfiguration.Etcd.External != nil {
 			externalEtcd = true
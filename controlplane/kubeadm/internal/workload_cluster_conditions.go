@@ -157,6 +157,9 @@ func (w *Workload) updateManagedEtcdConditions(ctx context.Context, controlPlane
 	// Make sure that the list of etcd members and machines is consistent.
 	kcpErrors = compareMachinesAndMembers(controlPlane, members, kcpErrors)
 
+	// Surface etcd members which joined the etcd cluster as learners and are not yet promoted to voting members.
+	markEtcdLearners(controlPlane, members)
+
 	// Aggregate components error from machines at KCP level
 	aggregateFromMachinesToKCP(aggregateFromMachinesToKCPInput{
 		controlPlane:      controlPlane,
@@ -196,6 +199,19 @@ func (w *Workload) getCurrentEtcdMembers(ctx context.Context, machine *clusterv1
 	return currentMembers, nil
 }
 
+func markEtcdLearners(controlPlane *ControlPlane, members []*etcd.Member) {
+	for _, member := range members {
+		if !member.IsLearner {
+			continue
+		}
+		for _, machine := range controlPlane.Machines {
+			if machine.Status.NodeRef != nil && machine.Status.NodeRef.Name == member.Name {
+				conditions.MarkFalse(machine, controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberLearnerReason, clusterv1.ConditionSeverityInfo, "Etcd member is a learner, waiting for it to be promoted to voting member")
+			}
+		}
+	}
+}
+
 func compareMachinesAndMembers(controlPlane *ControlPlane, members []*etcd.Member, kcpErrors []string) []string {
 	// NOTE: We run this check only if we actually know the list of members, otherwise the first for loop
 	// could generate a false negative when reporting missing etcd members.
@@ -379,6 +379,64 @@ func TestUpdateEtcdConditions(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "etcd learners should report false condition",
+			machines: []*clusterv1.Machine{
+				fakeMachine("m1", withNodeRef("n1")),
+				fakeMachine("m2", withNodeRef("n2")),
+			},
+			injectClient: &fakeClient{
+				list: &corev1.NodeList{
+					Items: []corev1.Node{
+						*fakeNode("n1"),
+						*fakeNode("n2"),
+					},
+				},
+			},
+			injectEtcdClientGenerator: &fakeEtcdClientGenerator{
+				forNodesClientFunc: func(n []string) (*etcd.Client, error) {
+					switch n[0] {
+					case "n1":
+						return &etcd.Client{
+							EtcdClient: &fake2.FakeEtcdClient{
+								EtcdEndpoints: []string{},
+								MemberListResponse: &clientv3.MemberListResponse{
+									Header: &pb.ResponseHeader{
+										ClusterId: uint64(1),
+									},
+									Members: []*pb.Member{
+										{Name: "n1", ID: uint64(1)},
+										{Name: "n2", ID: uint64(2), IsLearner: true},
+									},
+								},
+								AlarmResponse: &clientv3.AlarmResponse{
+									Alarms: []*pb.AlarmMember{},
+								},
+							},
+						}, nil
+					case "n2":
+						// NOTE: learners are not serving most of the etcd APIs.
+						return &etcd.Client{
+							EtcdClient: &fake2.FakeEtcdClient{
+								EtcdEndpoints: []string{},
+								ErrorResponse: errors.New("rpc not supported for learner"),
+							},
+						}, nil
+					default:
+						return nil, errors.New("no client for this node")
+					}
+				},
+			},
+			expectedKCPCondition: conditions.FalseCondition(controlplanev1.EtcdClusterHealthyCondition, controlplanev1.EtcdClusterUnhealthyReason, clusterv1.ConditionSeverityInfo, "Following machines are reporting etcd member info: %s", "m2"),
+			expectedMachineConditions: map[string]clusterv1.Conditions{
+				"m1": {
+					*conditions.TrueCondition(controlplanev1.MachineEtcdMemberHealthyCondition),
+				},
+				"m2": {
+					*conditions.FalseCondition(controlplanev1.MachineEtcdMemberHealthyCondition, controlplanev1.EtcdMemberLearnerReason, clusterv1.ConditionSeverityInfo, "Etcd member is a learner, waiting for it to be promoted to voting member"),
+				},
+			},
+		},
 		{
 			name: "healthy etcd members should report true",
 			machines: []*clusterv1.Machine{
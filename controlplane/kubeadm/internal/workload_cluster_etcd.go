@@ -18,6 +18,7 @@ package internal
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
@@ -209,6 +210,63 @@ func (w *Workload) ForwardEtcdLeadership(ctx context.Context, machine *clusterv1
 	return nil
 }
 
+// PromoteEtcdLearners promotes etcd learners (non-voting members) hosted on the given nodes to voting members,
+// if they are in sync with the leader. It returns the names of the learners still waiting to be promoted.
+//
+// NOTE: etcd learners do not count towards quorum, so members joining as learners are not shifting quorum
+// until they are promoted; learners are promoted only when they are in sync with the leader.
+func (w *Workload) PromoteEtcdLearners(ctx context.Context, nodeNames []string) ([]string, error) {
+	// NOTE: Use the leader, given that learners are not serving most of the etcd APIs.
+	etcdClient, err := w.etcdClientGenerator.forLeader(ctx, nodeNames)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create etcd client")
+	}
+	defer etcdClient.Close()
+
+	members, err := etcdClient.Members(ctx)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list etcd members using etcd client")
+	}
+
+	pendingLearners := []string{}
+	errs := []error{}
+	for _, member := range members {
+		if !member.IsLearner {
+			continue
+		}
+
+		// If this learner is just added, it has an empty name until the etcd pod starts, and
+		// it cannot be in sync with the leader yet.
+		if member.Name == "" {
+			pendingLearners = append(pendingLearners, fmt.Sprintf("%d (Name not yet assigned)", member.ID))
+			continue
+		}
+
+		// Promote only learners hosted on the given nodes, so learners without a corresponding
+		// machine are not going to count towards quorum.
+		hasNode := false
+		for _, nodeName := range nodeNames {
+			if member.Name == nodeName {
+				hasNode = true
+				break
+			}
+		}
+		if !hasNode {
+			pendingLearners = append(pendingLearners, member.Name)
+			continue
+		}
+
+		if err := etcdClient.PromoteMember(ctx, member.ID); err != nil {
+			if errors.Is(err, etcd.ErrLearnerNotReady) {
+				pendingLearners = append(pendingLearners, member.Name)
+				continue
+			}
+			errs = append(errs, errors.Wrapf(err, "failed to promote etcd learner %s", member.Name))
+		}
+	}
+	return pendingLearners, kerrors.NewAggregate(errs)
+}
+
 // EtcdMemberStatus contains status information for a single etcd member.
 type EtcdMemberStatus struct {
 	Name       string
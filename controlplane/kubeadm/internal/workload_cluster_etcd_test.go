@@ -25,6 +25,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	pb "go.etcd.io/etcd/api/v3/etcdserverpb"
+	"go.etcd.io/etcd/api/v3/v3rpc/rpctypes"
 	clientv3 "go.etcd.io/etcd/client/v3"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -717,6 +718,80 @@ func TestReconcileEtcdMembers(t *testing.T) {
 	}
 }
 
+func TestPromoteEtcdLearners(t *testing.T) {
+	members := []*pb.Member{
+		{Name: "voting-node", ID: uint64(101)},
+		{Name: "learner-node", ID: uint64(102), IsLearner: true},
+		{Name: "", ID: uint64(103), IsLearner: true},
+		{Name: "orphan-node", ID: uint64(104), IsLearner: true},
+	}
+
+	tests := []struct {
+		name           string
+		promoteErr     error
+		expectErr      bool
+		expectPending  []string
+		expectPromoted []uint64
+	}{
+		{
+			name:           "promotes learners hosted on the given nodes",
+			expectPending:  []string{"103 (Name not yet assigned)", "orphan-node"},
+			expectPromoted: []uint64{102},
+		},
+		{
+			name:          "does not promote learners not in sync with the leader",
+			promoteErr:    rpctypes.ErrMemberLearnerNotReady,
+			expectPending: []string{"learner-node", "103 (Name not yet assigned)", "orphan-node"},
+		},
+		{
+			name:       "returns an error if it fails to promote learners",
+			promoteErr: errors.New("failed to promote"),
+			expectErr:  true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			fakeEtcdClient := &fake2.FakeEtcdClient{
+				MemberListResponse: &clientv3.MemberListResponse{
+					Members: members,
+				},
+				AlarmResponse: &clientv3.AlarmResponse{
+					Alarms: []*pb.AlarmMember{},
+				},
+				MemberPromoteErr: tt.promoteErr,
+			}
+			w := &Workload{
+				etcdClientGenerator: &fakeEtcdClientGenerator{
+					forLeaderClient: &etcd.Client{
+						EtcdClient: fakeEtcdClient,
+					},
+				},
+			}
+
+			pending, err := w.PromoteEtcdLearners(ctx, []string{"voting-node", "learner-node"})
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(pending).To(Equal(tt.expectPending))
+			g.Expect(fakeEtcdClient.PromotedMembers).To(Equal(tt.expectPromoted))
+		})
+	}
+
+	t.Run("returns an error if it can't create an etcd client", func(t *testing.T) {
+		g := NewWithT(t)
+
+		w := &Workload{
+			etcdClientGenerator: &fakeEtcdClientGenerator{forLeaderErr: errors.New("no etcdClient")},
+		}
+		_, err := w.PromoteEtcdLearners(ctx, []string{"voting-node"})
+		g.Expect(err).To(HaveOccurred())
+	})
+}
+
 func TestRemoveNodeFromKubeadmConfigMap(t *testing.T) {
 	tests := []struct {
 		name              string
@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package internal
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	batchv1 "k8s.io/api/batch/v1"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrlclient "sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/internal/util/hash"
+)
+
+const (
+	// InPlaceUpdateJobLabel is the label applied to the Jobs updating control plane components in place.
+	InPlaceUpdateJobLabel = "controlplane.cluster.x-k8s.io/in-place-update"
+
+	// inPlaceUpdateNodeAnnotation is the annotation storing the name of the Node updated by an in-place update Job.
+	inPlaceUpdateNodeAnnotation = "controlplane.cluster.x-k8s.io/in-place-update-node"
+
+	// inPlaceUpdateHashAnnotation is the annotation storing the hash of the configuration applied by an in-place update Job.
+	inPlaceUpdateHashAnnotation = "controlplane.cluster.x-k8s.io/in-place-update-hash"
+
+	inPlaceUpdateJobPrefix     = "kcp-in-place-update-"
+	inPlaceUpdateHostMountPath = "/host"
+)
+
+// InPlaceUpdateStatus is the status of an in-place update of the control plane components on a Node.
+type InPlaceUpdateStatus string
+
+const (
+	// InPlaceUpdatePending signals that the in-place update is still running.
+	InPlaceUpdatePending = InPlaceUpdateStatus("Pending")
+
+	// InPlaceUpdateSucceeded signals that the in-place update completed successfully.
+	InPlaceUpdateSucceeded = InPlaceUpdateStatus("Succeeded")
+
+	// InPlaceUpdateFailed signals that the in-place update failed.
+	InPlaceUpdateFailed = InPlaceUpdateStatus("Failed")
+)
+
+// UpdateControlPlaneComponentsInPlace regenerates the static Pod manifests of the control plane components on a Node
+// by running "kubeadm upgrade node phase control-plane" in a privileged Job pinned to the Node; the configuration used
+// is the one stored in the kubeadm-config ConfigMap, which must be updated before calling this func.
+// The Job is created if it does not exist yet, and it is deleted as soon as it completes.
+func (w *Workload) UpdateControlPlaneComponentsInPlace(ctx context.Context, nodeName, image, configHash, patchesDirectory string) (InPlaceUpdateStatus, error) {
+	name, err := inPlaceUpdateJobName(nodeName, configHash)
+	if err != nil {
+		return "", err
+	}
+
+	job := &batchv1.Job{}
+	if err := w.Client.Get(ctx, ctrlclient.ObjectKey{Name: name, Namespace: metav1.NamespaceSystem}, job); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return "", errors.Wrapf(err, "failed to get in-place update Job %s", name)
+		}
+
+		job = newInPlaceUpdateJob(name, nodeName, image, configHash, patchesDirectory)
+		if err := w.Client.Create(ctx, job); err != nil {
+			return "", errors.Wrapf(err, "failed to create in-place update Job %s", name)
+		}
+		return InPlaceUpdatePending, nil
+	}
+
+	status := InPlaceUpdatePending
+	switch {
+	case job.Status.Succeeded > 0:
+		status = InPlaceUpdateSucceeded
+	case job.Status.Failed > 0:
+		status = InPlaceUpdateFailed
+	}
+	if status == InPlaceUpdatePending {
+		return status, nil
+	}
+
+	if err := w.Client.Delete(ctx, job, ctrlclient.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+		return "", errors.Wrapf(err, "failed to delete in-place update Job %s", name)
+	}
+	return status, nil
+}
+
+// inPlaceUpdateJobName returns a name for the in-place update Job which is unique for the given Node and configuration.
+func inPlaceUpdateJobName(nodeName, configHash string) (string, error) {
+	h, err := hash.Compute(struct {
+		NodeName   string
+		ConfigHash string
+	}{NodeName: nodeName, ConfigHash: configHash})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to compute in-place update Job name for Node %s", nodeName)
+	}
+	return fmt.Sprintf("%s%d", inPlaceUpdateJobPrefix, h), nil
+}
+
+func newInPlaceUpdateJob(name, nodeName, image, configHash, patchesDirectory string) *batchv1.Job {
+	command := []string{"chroot", inPlaceUpdateHostMountPath, "kubeadm", "upgrade", "node", "phase", "control-plane", "--certificate-renewal=false", "--etcd-upgrade=false"}
+	if patchesDirectory != "" {
+		command = append(command, "--patches", patchesDirectory)
+	}
+
+	return &batchv1.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      name,
+			Namespace: metav1.NamespaceSystem,
+			Labels: map[string]string{
+				InPlaceUpdateJobLabel: "",
+			},
+			Annotations: map[string]string{
+				inPlaceUpdateNodeAnnotation: nodeName,
+				inPlaceUpdateHashAnnotation: configHash,
+			},
+		},
+		Spec: batchv1.JobSpec{
+			BackoffLimit: ptr.To[int32](0),
+			Template: corev1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{
+					Labels: map[string]string{
+						InPlaceUpdateJobLabel: "",
+					},
+				},
+				Spec: corev1.PodSpec{
+					NodeName:      nodeName,
+					HostPID:       true,
+					RestartPolicy: corev1.RestartPolicyNever,
+					Tolerations: []corev1.Toleration{
+						{Operator: corev1.TolerationOpExists},
+					},
+					Containers: []corev1.Container{
+						{
+							Name:    "kubeadm",
+							Image:   image,
+							Command: command,
+							SecurityContext: &corev1.SecurityContext{
+								Privileged: ptr.To(true),
+							},
+							VolumeMounts: []corev1.VolumeMount{
+								{Name: "host", MountPath: inPlaceUpdateHostMountPath},
+							},
+						},
+					},
+					Volumes: []corev1.Volume{
+						{
+							Name: "host",
+							VolumeSource: corev1.VolumeSource{
+								HostPath: &corev1.HostPathVolumeSource{Path: "/"},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+}
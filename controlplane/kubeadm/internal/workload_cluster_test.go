@@ -1326,6 +1326,65 @@ func TestUpdateFeatureGatesInKubeadmConfigMap(t *testing.T) {
 	}
 }
 
+func TestEnableEtcdLearnerModeInKubeadmConfigMap(t *testing.T) {
+	tests := []struct {
+		name                     string
+		clusterConfigurationData string
+		wantFeatureGates         map[string]bool
+	}{
+		{
+			name: "it enables EtcdLearnerMode",
+			clusterConfigurationData: utilyaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta3
+				kind: ClusterConfiguration`),
+			wantFeatureGates: map[string]bool{"EtcdLearnerMode": true},
+		},
+		{
+			name: "it enables EtcdLearnerMode preserving other feature gates",
+			clusterConfigurationData: utilyaml.Raw(`
+				apiVersion: kubeadm.k8s.io/v1beta3
+				kind: ClusterConfiguration
+				featureGates:
+				  EtcdLearnerMode: false
+				  Foo: true
+				`),
+			wantFeatureGates: map[string]bool{"EtcdLearnerMode": true, "Foo": true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			fakeClient := fake.NewClientBuilder().WithObjects(&corev1.ConfigMap{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      kubeadmConfigKey,
+					Namespace: metav1.NamespaceSystem,
+				},
+				Data: map[string]string{
+					clusterConfigurationKey: tt.clusterConfigurationData,
+				},
+			}).Build()
+
+			w := &Workload{
+				Client: fakeClient,
+			}
+			err := w.UpdateClusterConfiguration(ctx, semver.MustParse("1.29.0"), w.EnableEtcdLearnerModeInKubeadmConfigMap())
+			g.Expect(err).ToNot(HaveOccurred())
+
+			var actualConfig corev1.ConfigMap
+			g.Expect(w.Client.Get(
+				ctx,
+				client.ObjectKey{Name: kubeadmConfigKey, Namespace: metav1.NamespaceSystem},
+				&actualConfig,
+			)).To(Succeed())
+
+			actualConfiguration := bootstrapv1.ClusterConfiguration{}
+			g.Expect(yaml.Unmarshal([]byte(actualConfig.Data[clusterConfigurationKey]), &actualConfiguration)).To(Succeed())
+			g.Expect(actualConfiguration.FeatureGates).Should(Equal(tt.wantFeatureGates))
+		})
+	}
+}
+
 func getProxyImageInfo(ctx context.Context, c client.Client) (string, error) {
 	ds := &appsv1.DaemonSet{}
 
@@ -56,6 +56,7 @@ import (
 	controlplanev1alpha3 "sigs.k8s.io/cluster-api/internal/apis/controlplane/kubeadm/v1alpha3"
 	controlplanev1alpha4 "sigs.k8s.io/cluster-api/internal/apis/controlplane/kubeadm/v1alpha4"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/sharding"
 	"sigs.k8s.io/cluster-api/version"
 )
 
@@ -69,6 +70,9 @@ var (
 	leaderElectionLeaseDuration time.Duration
 	leaderElectionRenewDeadline time.Duration
 	leaderElectionRetryPeriod   time.Duration
+	shards                      int
+	shardLeaseDuration          time.Duration
+	shardRenewPeriod            time.Duration
 	watchFilterValue            string
 	watchNamespace              string
 	profilerAddress             string
@@ -85,6 +89,10 @@ var (
 	// KCP specific flags.
 	kubeadmControlPlaneConcurrency int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerMaxClusters int
+	clusterCacheTrackerIdleTimeout time.Duration
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
 	etcdDialTimeout                time.Duration
 	etcdCallTimeout                time.Duration
 )
@@ -115,6 +123,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&leaderElectionRetryPeriod, "leader-elect-retry-period", 5*time.Second,
 		"Duration the LeaderElector clients should wait between tries of actions (duration string)")
 
+	fs.IntVar(&shards, "shards", 0,
+		"Number of shards Clusters are distributed across; each replica of the controller manager claims a share of the shards and only reconciles the Clusters in its shards. 0 disables sharding")
+
+	fs.DurationVar(&shardLeaseDuration, "shard-lease-duration", 15*time.Second,
+		"Duration after which the shards of a replica which stopped renewing them are claimed by other replicas (duration string)")
+
+	fs.DurationVar(&shardRenewPeriod, "shard-renew-period", 5*time.Second,
+		"Interval at which a replica renews its shards and rebalances shards with the other replicas (duration string)")
+
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
@@ -133,6 +150,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.IntVar(&clusterCacheTrackerMaxClusters, "clustercachetracker-max-clusters", 0,
+		"Maximum number of workload clusters with a cached client; when reached, the cache of the least recently used workload cluster without watches is stopped. 0 means unlimited")
+
+	fs.DurationVar(&clusterCacheTrackerIdleTimeout, "clustercachetracker-idle-timeout", 0,
+		"Duration after which the cache of a workload cluster without watches which has not been accessed is stopped. 0 means caches are never stopped because they are idle")
+
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 0,
+		"Maximum queries per second from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 0,
+		"Maximum burst for throttle from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
 	fs.DurationVar(&syncPeriod, "sync-period", 10*time.Minute,
 		"The minimum interval at which watched resources are reconciled (e.g. 15m)")
 
@@ -302,11 +331,29 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		os.Exit(1)
 	}
 
+	var sharder *sharding.Sharder
+	if shards > 0 {
+		sharder, err = sharding.New(mgr, sharding.Options{
+			Shards:        shards,
+			Name:          "kubeadm-control-plane-manager-sharding-capi",
+			LeaseDuration: shardLeaseDuration,
+			RenewPeriod:   shardRenewPeriod,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create sharder")
+			os.Exit(1)
+		}
+	}
+
 	// Set up a ClusterCacheTracker to provide to controllers
 	// requiring a connection to a remote cluster
 	tracker, err := remote.NewClusterCacheTracker(mgr, remote.ClusterCacheTrackerOptions{
 		SecretCachingClient: secretCachingClient,
 		ControllerName:      controllerName,
+		MaxAccessors:        clusterCacheTrackerMaxClusters,
+		AccessorIdleTimeout: clusterCacheTrackerIdleTimeout,
+		ClientQPS:           clusterCacheTrackerClientQPS,
+		ClientBurst:         clusterCacheTrackerClientBurst,
 		Log:                 &ctrl.Log,
 		ClientUncachedObjects: []client.Object{
 			&corev1.ConfigMap{},
@@ -324,7 +371,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterCacheTrackerConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(clusterCacheTrackerConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterCacheReconciler")
 		os.Exit(1)
 	}
@@ -336,7 +383,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) {
 		WatchFilterValue:    watchFilterValue,
 		EtcdDialTimeout:     etcdDialTimeout,
 		EtcdCallTimeout:     etcdCallTimeout,
-	}).SetupWithManager(ctx, mgr, concurrency(kubeadmControlPlaneConcurrency)); err != nil {
+		Sharder:             sharder,
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(kubeadmControlPlaneConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "KubeadmControlPlane")
 		os.Exit(1)
 	}
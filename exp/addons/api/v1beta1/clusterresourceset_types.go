@@ -41,38 +41,188 @@ type ClusterResourceSetSpec struct {
 	// Label selector cannot be empty.
 	ClusterSelector metav1.LabelSelector `json:"clusterSelector"`
 
-	// Resources is a list of Secrets/ConfigMaps where each contains 1 or more resources to be applied to remote clusters.
+	// Resources is a list of Secrets/ConfigMaps where each contains 1 or more resources to be applied to remote clusters,
+	// or of Helm charts and Kustomizations rendered for each remote cluster.
 	// +optional
 	Resources []ResourceRef `json:"resources,omitempty"`
 
+	// HelmCharts is a list of Helm charts which can be referenced by Resources with kind HelmChart.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	HelmCharts []HelmChartSource `json:"helmCharts,omitempty"`
+
+	// Kustomizations is a list of Kustomize overlays which can be referenced by Resources with kind Kustomization.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Kustomizations []KustomizationSource `json:"kustomizations,omitempty"`
+
+	// Waves groups Resources in waves which are applied in order: the resources of a wave are applied only after all
+	// the resources of the previous waves are applied and their objects are ready, e.g. Deployments are available,
+	// DaemonSets are rolled out and CustomResourceDefinitions are established.
+	// Resources not part of any wave are applied after all the waves.
+	// +optional
+	// +listType=map
+	// +listMapKey=name
+	Waves []ResourceWave `json:"waves,omitempty"`
+
 	// Strategy is the strategy to be used during applying resources. Defaults to ApplyOnce. This field is immutable.
 	// +kubebuilder:validation:Enum=ApplyOnce;Reconcile
 	// +optional
 	Strategy string `json:"strategy,omitempty"`
+
+	// Prune defines if objects applied by the ClusterResourceSet are deleted from a Cluster when they are no longer
+	// part of the set, e.g. because a resource has been removed from the ClusterResourceSet, or because
+	// the ClusterResourceSet no longer matches the Cluster, or because the ClusterResourceSet has been deleted.
+	// With DryRun, objects are not deleted, but they are listed in status.pruneCandidates.
+	// Prune can be enabled only with the Reconcile strategy. Defaults to Disabled.
+	// +kubebuilder:validation:Enum=Disabled;DryRun;Enabled
+	// +optional
+	Prune string `json:"prune,omitempty"`
+
+	// DriftDetection configures the periodic detection of changes made in the Clusters to the objects applied by
+	// the ClusterResourceSet; drifted objects are reported in the ClusterResourceSetBinding status of each Cluster.
+	// Drift detection can be enabled only with the Reconcile strategy.
+	// +optional
+	DriftDetection *ClusterResourceSetDriftDetection `json:"driftDetection,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetSpec
 
+// ResourceWave is a group of resources applied together.
+type ResourceWave struct {
+	// Name is the name of the wave.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Resources is the list of resources in the wave; resources must be listed in spec.resources,
+	// and they can be part of only one wave.
+	// +kubebuilder:validation:MinItems=1
+	Resources []ResourceRef `json:"resources"`
+}
+
+// ClusterResourceSetDriftDetection configures drift detection for the objects applied by a ClusterResourceSet.
+type ClusterResourceSetDriftDetection struct {
+	// Interval is the interval between drift checks. Defaults to 10m.
+	// +optional
+	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// Remediate defines if drifted objects are applied again to the Clusters.
+	// If not set, drifted objects are only reported.
+	// +optional
+	Remediate bool `json:"remediate,omitempty"`
+}
+
 // ClusterResourceSetResourceKind is a string representation of a ClusterResourceSet resource kind.
 type ClusterResourceSetResourceKind string
 
 // Define the ClusterResourceSetResourceKind constants.
 const (
-	SecretClusterResourceSetResourceKind    ClusterResourceSetResourceKind = "Secret"
-	ConfigMapClusterResourceSetResourceKind ClusterResourceSetResourceKind = "ConfigMap"
+	SecretClusterResourceSetResourceKind        ClusterResourceSetResourceKind = "Secret"
+	ConfigMapClusterResourceSetResourceKind     ClusterResourceSetResourceKind = "ConfigMap"
+	HelmChartClusterResourceSetResourceKind     ClusterResourceSetResourceKind = "HelmChart"
+	KustomizationClusterResourceSetResourceKind ClusterResourceSetResourceKind = "Kustomization"
 )
 
 // ResourceRef specifies a resource.
 type ResourceRef struct {
 	// Name of the resource that is in the same namespace with ClusterResourceSet object.
+	// For HelmChart and Kustomization kinds, name of the corresponding entry in ClusterResourceSet.spec.helmCharts
+	// or ClusterResourceSet.spec.kustomizations.
 	// +kubebuilder:validation:MinLength=1
 	Name string `json:"name"`
 
-	// Kind of the resource. Supported kinds are: Secrets and ConfigMaps.
+	// Kind of the resource. Supported kinds are: Secrets, ConfigMaps, HelmCharts and Kustomizations.
+	// +kubebuilder:validation:Enum=Secret;ConfigMap;HelmChart;Kustomization
+	Kind string `json:"kind"`
+}
+
+// SourceRef references a Secret or a ConfigMap in the same namespace with the ClusterResourceSet object.
+type SourceRef struct {
+	// Name of the Secret or ConfigMap.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Kind of the source. Supported kinds are: Secrets and ConfigMaps.
 	// +kubebuilder:validation:Enum=Secret;ConfigMap
 	Kind string `json:"kind"`
 }
 
+// HelmChartSource defines a Helm chart to be rendered and applied to the remote clusters.
+// The chart is rendered for each cluster, and the values can reference the cluster variables
+// using the ${VARIABLE_NAME} syntax, e.g. ${CLUSTER_NAME} or the name of a Cluster topology variable.
+type HelmChartSource struct {
+	// Name of the Helm chart source, used to reference it from ClusterResourceSet.spec.resources.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// ChartRef references a Secret or a ConfigMap containing the packaged chart (the .tgz archive)
+	// under the ChartKey key; ConfigMaps must store the archive in binaryData.
+	// Exactly one of ChartRef or OCI must be set.
+	// +optional
+	ChartRef *SourceRef `json:"chartRef,omitempty"`
+
+	// ChartKey is the key of the packaged chart in the Secret or ConfigMap referenced by ChartRef.
+	// Defaults to "chart.tgz".
+	// +optional
+	ChartKey string `json:"chartKey,omitempty"`
+
+	// OCI is the reference of the chart in an OCI registry, e.g. oci://registry.example.com/charts/cilium.
+	// Exactly one of ChartRef or OCI must be set.
+	// +optional
+	OCI string `json:"oci,omitempty"`
+
+	// Version of the chart to be pulled from the OCI registry, either a version or a version constraint;
+	// if not set, the latest version is used.
+	// +optional
+	Version string `json:"version,omitempty"`
+
+	// CredentialsSecretRef references a Secret, in the namespace of the ClusterResourceSet, with the credentials
+	// used to pull the chart from the OCI registry; the Secret must either be of type kubernetes.io/dockerconfigjson,
+	// or contain the username and password keys. If not set, the chart is pulled anonymously.
+	// +optional
+	CredentialsSecretRef *corev1.LocalObjectReference `json:"credentialsSecretRef,omitempty"`
+
+	// ReleaseName is the name of the Helm release used to render the chart. Defaults to the name of the Helm chart source.
+	// +optional
+	ReleaseName string `json:"releaseName,omitempty"`
+
+	// ReleaseNamespace is the namespace of the Helm release used to render the chart; objects without a namespace
+	// are created in this namespace. Defaults to "default".
+	// +optional
+	ReleaseNamespace string `json:"releaseNamespace,omitempty"`
+
+	// Values is a YAML document with the values used to render the chart.
+	// +optional
+	Values string `json:"values,omitempty"`
+}
+
+// KustomizationSource defines a Kustomize overlay to be rendered and applied to the remote clusters.
+// The overlay is rendered for each cluster, and the rendered objects can reference the cluster variables
+// using the ${VARIABLE_NAME} syntax, e.g. ${CLUSTER_NAME} or the name of a Cluster topology variable.
+type KustomizationSource struct {
+	// Name of the Kustomization source, used to reference it from ClusterResourceSet.spec.resources.
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+
+	// Files is a list of Secrets or ConfigMaps whose keys are written as files into the directory tree
+	// used to run kustomize; the root directory must contain a kustomization.yaml file.
+	// +kubebuilder:validation:MinItems=1
+	Files []KustomizationFiles `json:"files"`
+}
+
+// KustomizationFiles defines a set of files of a Kustomize directory tree.
+type KustomizationFiles struct {
+	// SourceRef references the Secret or ConfigMap whose keys are the files names, and values the files content.
+	SourceRef SourceRef `json:"sourceRef"`
+
+	// Path is the directory, relative to the root of the directory tree, where files are written.
+	// Defaults to the root directory.
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
 // ClusterResourceSetStrategy is a string representation of a ClusterResourceSet Strategy.
 type ClusterResourceSetStrategy string
 
@@ -85,11 +235,52 @@ const (
 	ClusterResourceSetStrategyReconcile ClusterResourceSetStrategy = "Reconcile"
 )
 
+// ClusterResourceSetPrunePolicy is a string representation of a ClusterResourceSet Prune policy.
+type ClusterResourceSetPrunePolicy string
+
+const (
+	// ClusterResourceSetPruneDisabled leaves the objects which are no longer part of a ClusterResourceSet in the Cluster.
+	ClusterResourceSetPruneDisabled ClusterResourceSetPrunePolicy = "Disabled"
+	// ClusterResourceSetPruneDryRun lists the objects which are no longer part of a ClusterResourceSet
+	// in the ClusterResourceSet status, without deleting them.
+	ClusterResourceSetPruneDryRun ClusterResourceSetPrunePolicy = "DryRun"
+	// ClusterResourceSetPruneEnabled deletes the objects which are no longer part of a ClusterResourceSet from the Cluster.
+	ClusterResourceSetPruneEnabled ClusterResourceSetPrunePolicy = "Enabled"
+)
+
+// GetHelmChart returns the Helm chart source with the given name, if any.
+func (c *ClusterResourceSetSpec) GetHelmChart(name string) *HelmChartSource {
+	for i := range c.HelmCharts {
+		if c.HelmCharts[i].Name == name {
+			return &c.HelmCharts[i]
+		}
+	}
+	return nil
+}
+
+// GetKustomization returns the Kustomization source with the given name, if any.
+func (c *ClusterResourceSetSpec) GetKustomization(name string) *KustomizationSource {
+	for i := range c.Kustomizations {
+		if c.Kustomizations[i].Name == name {
+			return &c.Kustomizations[i]
+		}
+	}
+	return nil
+}
+
 // SetTypedStrategy sets the Strategy field to the string representation of ClusterResourceSetStrategy.
 func (c *ClusterResourceSetSpec) SetTypedStrategy(p ClusterResourceSetStrategy) {
 	c.Strategy = string(p)
 }
 
+// GetTypedPrune returns the type-safe representation of the Prune field, defaulting to Disabled.
+func (c *ClusterResourceSetSpec) GetTypedPrune() ClusterResourceSetPrunePolicy {
+	if c.Prune == "" {
+		return ClusterResourceSetPruneDisabled
+	}
+	return ClusterResourceSetPrunePolicy(c.Prune)
+}
+
 // ANCHOR: ClusterResourceSetStatus
 
 // ClusterResourceSetStatus defines the observed state of ClusterResourceSet.
@@ -101,10 +292,24 @@ type ClusterResourceSetStatus struct {
 	// Conditions defines current state of the ClusterResourceSet.
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
+
+	// PruneCandidates lists the objects which would be deleted from the matching Clusters if prune was enabled.
+	// It is populated only when spec.prune is DryRun.
+	// +optional
+	PruneCandidates []PruneCandidate `json:"pruneCandidates,omitempty"`
 }
 
 // ANCHOR_END: ClusterResourceSetStatus
 
+// PruneCandidate is an object applied by a ClusterResourceSet to a Cluster which is no longer part of the set.
+type PruneCandidate struct {
+	// ClusterName is the name of the Cluster the object has been applied to.
+	ClusterName string `json:"clusterName"`
+
+	// AppliedObjectReference is a reference to the object in the Cluster.
+	AppliedObjectReference `json:",inline"`
+}
+
 // GetConditions returns the set of conditions for this object.
 func (m *ClusterResourceSet) GetConditions() clusterv1.Conditions {
 	return m.Status.Conditions
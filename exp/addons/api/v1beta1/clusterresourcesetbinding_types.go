@@ -17,6 +17,7 @@ limitations under the License.
 package v1beta1
 
 import (
+	"fmt"
 	"reflect"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -41,10 +42,39 @@ type ResourceBinding struct {
 
 	// Applied is to track if a resource is applied to the cluster or not.
 	Applied bool `json:"applied"`
+
+	// Objects is the list of objects applied to the cluster from this resource, which are deleted from the cluster
+	// if they are no longer part of the ClusterResourceSet and ClusterResourceSet.spec.prune is Enabled.
+	// +optional
+	Objects []AppliedObjectReference `json:"objects,omitempty"`
 }
 
 // ANCHOR_END: ResourceBinding
 
+// AppliedObjectReference is a reference to an object applied to a cluster by a ClusterResourceSet.
+type AppliedObjectReference struct {
+	// APIVersion of the object.
+	APIVersion string `json:"apiVersion"`
+
+	// Kind of the object.
+	Kind string `json:"kind"`
+
+	// Namespace of the object; empty for cluster-scoped objects.
+	// +optional
+	Namespace string `json:"namespace,omitempty"`
+
+	// Name of the object.
+	Name string `json:"name"`
+}
+
+// String returns a string representation of the AppliedObjectReference.
+func (r AppliedObjectReference) String() string {
+	if r.Namespace == "" {
+		return fmt.Sprintf("%s %s/%s", r.APIVersion, r.Kind, r.Name)
+	}
+	return fmt.Sprintf("%s %s/%s/%s", r.APIVersion, r.Kind, r.Namespace, r.Name)
+}
+
 // ResourceSetBinding keeps info on all of the resources in a ClusterResourceSet.
 type ResourceSetBinding struct {
 	// ClusterResourceSetName is the name of the ClusterResourceSet that is applied to the owner cluster of the binding.
@@ -83,6 +113,16 @@ func (r *ResourceSetBinding) SetBinding(resourceBinding ResourceBinding) {
 	r.Resources = append(r.Resources, resourceBinding)
 }
 
+// RemoveResource removes the ResourceBinding for a resource ref from the ResourceSetBinding, if present.
+func (r *ResourceSetBinding) RemoveResource(resourceRef ResourceRef) {
+	for i := range r.Resources {
+		if reflect.DeepEqual(r.Resources[i].ResourceRef, resourceRef) {
+			r.Resources = append(r.Resources[:i], r.Resources[i+1:]...)
+			return
+		}
+	}
+}
+
 // GetOrCreateBinding returns the ResourceSetBinding for a given ClusterResourceSet if exists,
 // otherwise creates one and updates ClusterResourceSet with it.
 func (c *ClusterResourceSetBinding) GetOrCreateBinding(clusterResourceSet *ClusterResourceSet) *ResourceSetBinding {
@@ -96,7 +136,8 @@ func (c *ClusterResourceSetBinding) GetOrCreateBinding(clusterResourceSet *Clust
 	return binding
 }
 
-// RemoveBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list.
+// RemoveBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list,
+// together with the drifted objects and the waves of the ClusterResourceSet.
 func (c *ClusterResourceSetBinding) RemoveBinding(clusterResourceSet *ClusterResourceSet) {
 	for i, binding := range c.Spec.Bindings {
 		if binding.ClusterResourceSetName == clusterResourceSet.Name {
@@ -105,6 +146,8 @@ func (c *ClusterResourceSetBinding) RemoveBinding(clusterResourceSet *ClusterRes
 			break
 		}
 	}
+	c.SetDriftedObjects(clusterResourceSet.Name, nil)
+	c.SetWaveStatuses(clusterResourceSet.Name, nil)
 }
 
 // DeleteBinding removes the ClusterResourceSet from the ClusterResourceSetBinding Bindings list.
@@ -175,7 +218,8 @@ func referSameObject(a, b metav1.OwnerReference) bool {
 type ClusterResourceSetBinding struct {
 	metav1.TypeMeta   `json:",inline"`
 	metav1.ObjectMeta `json:"metadata,omitempty"`
-	Spec              ClusterResourceSetBindingSpec `json:"spec,omitempty"`
+	Spec              ClusterResourceSetBindingSpec   `json:"spec,omitempty"`
+	Status            ClusterResourceSetBindingStatus `json:"status,omitempty"`
 }
 
 // ANCHOR: ClusterResourceSetBindingSpec
@@ -194,6 +238,140 @@ type ClusterResourceSetBindingSpec struct {
 
 // ANCHOR_END: ClusterResourceSetBindingSpec
 
+// ANCHOR: ClusterResourceSetBindingStatus
+
+// ClusterResourceSetBindingStatus defines the observed state of ClusterResourceSetBinding.
+type ClusterResourceSetBindingStatus struct {
+	// DriftedObjects is the list of objects applied by ClusterResourceSets with drift detection enabled,
+	// which differ in the cluster from the state defined by the ClusterResourceSets.
+	// +optional
+	DriftedObjects []DriftedObject `json:"driftedObjects,omitempty"`
+
+	// Waves is the status of the waves of the ClusterResourceSets applied to the cluster.
+	// +optional
+	Waves []WaveStatus `json:"waves,omitempty"`
+}
+
+// ANCHOR_END: ClusterResourceSetBindingStatus
+
+// DriftReason is the reason why an object is drifted.
+type DriftReason string
+
+const (
+	// ObjectModifiedDriftReason documents an object modified in the cluster.
+	ObjectModifiedDriftReason DriftReason = "Modified"
+
+	// ObjectDeletedDriftReason documents an object deleted from the cluster.
+	ObjectDeletedDriftReason DriftReason = "Deleted"
+)
+
+// DriftedObject is an object applied by a ClusterResourceSet which differs in the cluster from the desired state.
+type DriftedObject struct {
+	// ClusterResourceSetName is the name of the ClusterResourceSet which applied the object.
+	ClusterResourceSetName string `json:"clusterResourceSetName"`
+
+	// Resource is the ClusterResourceSet resource defining the object.
+	Resource ResourceRef `json:"resource"`
+
+	// AppliedObjectReference is a reference to the object in the cluster.
+	AppliedObjectReference `json:",inline"`
+
+	// Reason is the reason why the object is drifted.
+	// +kubebuilder:validation:Enum=Modified;Deleted
+	Reason DriftReason `json:"reason"`
+
+	// DetectedTime is the time when the drift was first detected.
+	DetectedTime metav1.Time `json:"detectedTime"`
+}
+
+// WavePhase is the phase of a wave of resources.
+type WavePhase string
+
+const (
+	// WavePending documents a wave waiting for the previous waves to be ready.
+	WavePending WavePhase = "Pending"
+
+	// WaveProgressing documents a wave whose resources are applied, waiting for their objects to be ready.
+	WaveProgressing WavePhase = "Progressing"
+
+	// WaveReady documents a wave whose resources are applied and whose objects are ready.
+	WaveReady WavePhase = "Ready"
+
+	// WaveFailed documents a wave with at least one resource which failed to be applied.
+	WaveFailed WavePhase = "Failed"
+)
+
+// WaveStatus is the status of a wave of resources of a ClusterResourceSet applied to the cluster.
+type WaveStatus struct {
+	// ClusterResourceSetName is the name of the ClusterResourceSet defining the wave.
+	ClusterResourceSetName string `json:"clusterResourceSetName"`
+
+	// Name is the name of the wave.
+	Name string `json:"name"`
+
+	// Phase is the phase of the wave.
+	// +kubebuilder:validation:Enum=Pending;Progressing;Ready;Failed
+	Phase WavePhase `json:"phase"`
+
+	// Message is a human readable message indicating details about the phase, e.g. the objects which are not ready.
+	// +optional
+	Message string `json:"message,omitempty"`
+}
+
+// SetWaveStatuses sets the status of the waves of a ClusterResourceSet.
+func (c *ClusterResourceSetBinding) SetWaveStatuses(clusterResourceSetName string, waves []WaveStatus) {
+	result := []WaveStatus{}
+	for _, wave := range c.Status.Waves {
+		if wave.ClusterResourceSetName != clusterResourceSetName {
+			result = append(result, wave)
+		}
+	}
+	for _, wave := range waves {
+		wave.ClusterResourceSetName = clusterResourceSetName
+		result = append(result, wave)
+	}
+	if len(result) == 0 {
+		result = nil
+	}
+	c.Status.Waves = result
+}
+
+// GetDriftedObjects returns the drifted objects for a ClusterResourceSet.
+func (c *ClusterResourceSetBinding) GetDriftedObjects(clusterResourceSetName string) []DriftedObject {
+	driftedObjects := []DriftedObject{}
+	for _, driftedObject := range c.Status.DriftedObjects {
+		if driftedObject.ClusterResourceSetName == clusterResourceSetName {
+			driftedObjects = append(driftedObjects, driftedObject)
+		}
+	}
+	return driftedObjects
+}
+
+// SetDriftedObjects sets the drifted objects for a ClusterResourceSet, preserving the time when drift was first
+// detected for objects which were already drifted, even if the reason of the drift changed.
+func (c *ClusterResourceSetBinding) SetDriftedObjects(clusterResourceSetName string, driftedObjects []DriftedObject) {
+	result := []DriftedObject{}
+	previous := map[AppliedObjectReference]DriftedObject{}
+	for _, driftedObject := range c.Status.DriftedObjects {
+		if driftedObject.ClusterResourceSetName != clusterResourceSetName {
+			result = append(result, driftedObject)
+			continue
+		}
+		previous[driftedObject.AppliedObjectReference] = driftedObject
+	}
+	for _, driftedObject := range driftedObjects {
+		driftedObject.ClusterResourceSetName = clusterResourceSetName
+		if p, ok := previous[driftedObject.AppliedObjectReference]; ok {
+			driftedObject.DetectedTime = p.DetectedTime
+		}
+		result = append(result, driftedObject)
+	}
+	if len(result) == 0 {
+		result = nil
+	}
+	c.Status.DriftedObjects = result
+}
+
 // +kubebuilder:object:root=true
 
 // ClusterResourceSetBindingList contains a list of ClusterResourceSetBinding.
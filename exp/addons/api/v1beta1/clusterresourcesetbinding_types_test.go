@@ -216,3 +216,81 @@ func TestSetResourceBinding(t *testing.T) {
 		})
 	}
 }
+
+func TestSetDriftedObjects(t *testing.T) {
+	gs := NewWithT(t)
+
+	firstDetected := metav1.NewTime(time.Now().UTC().Add(-time.Hour))
+	now := metav1.NewTime(time.Now().UTC())
+	resource := ResourceRef{Name: "resource", Kind: "ConfigMap"}
+	modified := AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "modified"}
+	deleted := AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "deleted"}
+
+	binding := &ClusterResourceSetBinding{
+		Status: ClusterResourceSetBindingStatus{
+			DriftedObjects: []DriftedObject{
+				{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+				{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: deleted, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+				{ClusterResourceSetName: "crs2", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+			},
+		},
+	}
+
+	gs.Expect(binding.GetDriftedObjects("crs1")).To(Equal([]DriftedObject{
+		{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+		{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: deleted, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+	}))
+
+	// The time when drift was first detected is preserved for objects which are still drifted, even if the reason changed.
+	added := AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "added"}
+	binding.SetDriftedObjects("crs1", []DriftedObject{
+		{Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: now},
+		{Resource: resource, AppliedObjectReference: deleted, Reason: ObjectDeletedDriftReason, DetectedTime: now},
+		{Resource: resource, AppliedObjectReference: added, Reason: ObjectModifiedDriftReason, DetectedTime: now},
+	})
+	gs.Expect(binding.Status.DriftedObjects).To(Equal([]DriftedObject{
+		{ClusterResourceSetName: "crs2", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+		{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+		{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: deleted, Reason: ObjectDeletedDriftReason, DetectedTime: firstDetected},
+		{ClusterResourceSetName: "crs1", Resource: resource, AppliedObjectReference: added, Reason: ObjectModifiedDriftReason, DetectedTime: now},
+	}))
+
+	binding.RemoveBinding(&ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: "crs1"}})
+	gs.Expect(binding.Status.DriftedObjects).To(Equal([]DriftedObject{
+		{ClusterResourceSetName: "crs2", Resource: resource, AppliedObjectReference: modified, Reason: ObjectModifiedDriftReason, DetectedTime: firstDetected},
+	}))
+
+	binding.SetDriftedObjects("crs2", nil)
+	gs.Expect(binding.Status.DriftedObjects).To(BeNil())
+}
+
+func TestSetWaveStatuses(t *testing.T) {
+	gs := NewWithT(t)
+
+	binding := &ClusterResourceSetBinding{
+		Status: ClusterResourceSetBindingStatus{
+			Waves: []WaveStatus{
+				{ClusterResourceSetName: "crs1", Name: "networking", Phase: WaveProgressing},
+				{ClusterResourceSetName: "crs2", Name: "storage", Phase: WaveReady},
+			},
+		},
+	}
+
+	binding.SetWaveStatuses("crs1", []WaveStatus{
+		{Name: "networking", Phase: WaveReady},
+		{Name: "workloads", Phase: WavePending},
+	})
+	gs.Expect(binding.Status.Waves).To(Equal([]WaveStatus{
+		{ClusterResourceSetName: "crs2", Name: "storage", Phase: WaveReady},
+		{ClusterResourceSetName: "crs1", Name: "networking", Phase: WaveReady},
+		{ClusterResourceSetName: "crs1", Name: "workloads", Phase: WavePending},
+	}))
+
+	binding.RemoveBinding(&ClusterResourceSet{ObjectMeta: metav1.ObjectMeta{Name: "crs1"}})
+	gs.Expect(binding.Status.Waves).To(Equal([]WaveStatus{
+		{ClusterResourceSetName: "crs2", Name: "storage", Phase: WaveReady},
+	}))
+
+	binding.SetWaveStatuses("crs2", nil)
+	gs.Expect(binding.Status.Waves).To(BeNil())
+}
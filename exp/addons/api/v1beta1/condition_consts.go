@@ -39,4 +39,16 @@ const (
 
 	// WrongSecretTypeReason (Severity=Warning) documents at least one of the Secret's type in the resource list is not supported.
 	WrongSecretTypeReason = "WrongSecretType"
+
+	// RenderingResourceFailedReason (Severity=Warning) documents at least one of the Helm charts or Kustomizations
+	// in the resource list failed to be rendered.
+	RenderingResourceFailedReason = "RenderingResourceFailed"
+
+	// PruneFailedReason (Severity=Warning) documents deleting at least one of the objects which are no longer
+	// part of the ClusterResourceSet from one of the clusters is failed.
+	PruneFailedReason = "PruneFailed"
+
+	// WaitingForWaveReason (Severity=Info) documents a ClusterResourceSet waiting for the objects of a wave
+	// to be ready in one of the clusters before applying the next wave.
+	WaitingForWaveReason = "WaitingForWave"
 )
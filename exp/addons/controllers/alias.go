@@ -27,20 +27,34 @@ import (
 	clusterresourcesets "sigs.k8s.io/cluster-api/exp/addons/internal/controllers"
 )
 
+// HelmChartRenderer renders Helm charts for ClusterResourceSets.
+type HelmChartRenderer = clusterresourcesets.HelmChartRenderer
+
+// HelmChartRenderOptions are the options used to render a Helm chart.
+type HelmChartRenderOptions = clusterresourcesets.HelmChartRenderOptions
+
+// HelmRegistryCredentials are the credentials used to pull Helm charts from an OCI registry.
+type HelmRegistryCredentials = clusterresourcesets.HelmRegistryCredentials
+
 // ClusterResourceSetReconciler reconciles a ClusterResourceSet object.
 type ClusterResourceSetReconciler struct {
 	Client  client.Client
 	Tracker *remote.ClusterCacheTracker
 
+	// HelmChartRenderer renders the Helm charts of ClusterResourceSets; if not set, Helm charts can't be applied.
+	// NOTE: sigs.k8s.io/cluster-api/exp/addons/helm provides an implementation based on the Helm libraries.
+	HelmChartRenderer HelmChartRenderer
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 }
 
 func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
 	return (&clusterresourcesets.ClusterResourceSetReconciler{
-		Client:           r.Client,
-		Tracker:          r.Tracker,
-		WatchFilterValue: r.WatchFilterValue,
+		Client:            r.Client,
+		Tracker:           r.Tracker,
+		HelmChartRenderer: r.HelmChartRenderer,
+		WatchFilterValue:  r.WatchFilterValue,
 	}).SetupWithManager(ctx, mgr, options)
 }
 
@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+
+	"github.com/Masterminds/semver/v3"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/registry"
+	"oras.land/oras-go/v2/content"
+	orasregistry "oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+
+	addonscontrollers "sigs.k8s.io/cluster-api/exp/addons/controllers"
+)
+
+const (
+	// maxManifestSize is the maximum size of the manifest of a chart.
+	maxManifestSize = 4 * 1024 * 1024
+
+	// maxChartSize is the maximum size of a packaged chart.
+	maxChartSize = 20 * 1024 * 1024
+)
+
+// Resolve returns the digest of the manifest of a Helm chart in an OCI registry; version can be a version
+// or a version constraint, if empty the latest version is resolved.
+func (r *Renderer) Resolve(ctx context.Context, ref, version string, credentials *addonscontrollers.HelmRegistryCredentials) (string, error) {
+	repo, err := r.repository(ref, credentials)
+	if err != nil {
+		return "", err
+	}
+
+	tags, err := orasregistry.Tags(ctx, repo)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get tags for chart %s", ref)
+	}
+	chartVersion, err := registry.GetTagMatchingVersionOrConstraint(chartVersions(tags), version)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get version %q of chart %s", version, ref)
+	}
+
+	// NOTE: Helm replaces + with _ in the tags of chart versions, because + is not allowed in tags.
+	desc, err := repo.Resolve(ctx, strings.ReplaceAll(chartVersion, "+", "_"))
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to resolve version %q of chart %s", chartVersion, ref)
+	}
+	return desc.Digest.String(), nil
+}
+
+// Fetch returns the packaged Helm chart with the given manifest digest from an OCI registry.
+func (r *Renderer) Fetch(ctx context.Context, ref, digest string, credentials *addonscontrollers.HelmRegistryCredentials) ([]byte, error) {
+	repo, err := r.repository(ref, credentials)
+	if err != nil {
+		return nil, err
+	}
+
+	desc, err := repo.Resolve(ctx, digest)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to resolve chart %s@%s", ref, digest)
+	}
+	if desc.Size > maxManifestSize {
+		return nil, errors.Errorf("failed to fetch chart %s@%s: manifest size %d exceeds the limit of %d bytes", ref, digest, desc.Size, maxManifestSize)
+	}
+	// NOTE: FetchAll verifies the fetched content against the size and the digest of the descriptor.
+	data, err := content.FetchAll(ctx, repo, desc)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to fetch manifest of chart %s@%s", ref, digest)
+	}
+
+	manifest := ocispec.Manifest{}
+	if err := json.Unmarshal(data, &manifest); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse manifest of chart %s@%s", ref, digest)
+	}
+	for _, layer := range manifest.Layers {
+		if layer.MediaType != registry.ChartLayerMediaType && layer.MediaType != registry.LegacyChartLayerMediaType {
+			continue
+		}
+		if layer.Size > maxChartSize {
+			return nil, errors.Errorf("failed to fetch chart %s@%s: chart size %d exceeds the limit of %d bytes", ref, digest, layer.Size, maxChartSize)
+		}
+		archive, err := content.FetchAll(ctx, repo, layer)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to fetch chart %s@%s", ref, digest)
+		}
+		return archive, nil
+	}
+	return nil, errors.Errorf("failed to fetch chart %s@%s: manifest has no chart layer", ref, digest)
+}
+
+// repository returns the OCI repository for a chart, authenticated with the given credentials.
+func (r *Renderer) repository(ref string, credentials *addonscontrollers.HelmRegistryCredentials) (*remote.Repository, error) {
+	repo, err := remote.NewRepository(ref)
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid chart reference %s", ref)
+	}
+	repo.PlainHTTP = r.plainHTTP
+
+	client := &auth.Client{
+		Client: retry.DefaultClient,
+		Cache:  auth.NewCache(),
+	}
+	if credentials != nil {
+		client.Credential = auth.StaticCredential(repo.Reference.Registry, auth.Credential{
+			Username: credentials.Username,
+			Password: credentials.Password,
+		})
+	}
+	repo.Client = client
+	return repo, nil
+}
+
+// chartVersions returns the chart versions from the tags of a repository, sorted from the latest.
+// NOTE: This is consistent with the Helm registry client, which ignores tags that are not semantic versions.
+func chartVersions(tags []string) []string {
+	versions := []*semver.Version{}
+	for _, tag := range tags {
+		if version, err := semver.StrictNewVersion(strings.ReplaceAll(tag, "_", "+")); err == nil {
+			versions = append(versions, version)
+		}
+	}
+	sort.Sort(sort.Reverse(semver.Collection(versions)))
+
+	chartVersions := make([]string, 0, len(versions))
+	for _, version := range versions {
+		chartVersions = append(chartVersions, version.String())
+	}
+	return chartVersions
+}
@@ -0,0 +1,197 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/opencontainers/go-digest"
+	ocispec "github.com/opencontainers/image-spec/specs-go/v1"
+	"helm.sh/helm/v3/pkg/registry"
+
+	addonscontrollers "sigs.k8s.io/cluster-api/exp/addons/controllers"
+)
+
+// testRegistry is a minimal OCI registry serving charts of the charts/test repository.
+type testRegistry struct {
+	charts    map[string][]byte
+	manifests map[string][]byte
+	blobs     map[string][]byte
+	username  string
+	password  string
+}
+
+func newTestRegistry(t *testing.T, charts map[string][]byte) *testRegistry {
+	t.Helper()
+
+	r := &testRegistry{
+		charts:    charts,
+		manifests: map[string][]byte{},
+		blobs:     map[string][]byte{},
+	}
+	for tag, archive := range charts {
+		config := []byte("{}")
+		manifest, err := json.Marshal(ocispec.Manifest{
+			MediaType: ocispec.MediaTypeImageManifest,
+			Config:    ocispec.Descriptor{MediaType: registry.ConfigMediaType, Digest: digest.FromBytes(config), Size: int64(len(config))},
+			Layers:    []ocispec.Descriptor{{MediaType: registry.ChartLayerMediaType, Digest: digest.FromBytes(archive), Size: int64(len(archive))}},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		r.manifests[tag] = manifest
+		r.manifests[digest.FromBytes(manifest).String()] = manifest
+		r.blobs[digest.FromBytes(config).String()] = config
+		r.blobs[digest.FromBytes(archive).String()] = archive
+	}
+	return r
+}
+
+func (r *testRegistry) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	if r.username != "" {
+		if username, password, ok := req.BasicAuth(); !ok || username != r.username || password != r.password {
+			w.Header().Set("WWW-Authenticate", `Basic realm="test"`)
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+	}
+
+	path := strings.TrimPrefix(req.URL.Path, "/v2/charts/test/")
+	switch {
+	case path == "tags/list":
+		tags := []string{"latest"}
+		for tag := range r.charts {
+			tags = append(tags, tag)
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"name": "charts/test", "tags": tags})
+	case strings.HasPrefix(path, "manifests/"):
+		manifest, ok := r.manifests[strings.TrimPrefix(path, "manifests/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", ocispec.MediaTypeImageManifest)
+		w.Header().Set("Docker-Content-Digest", digest.FromBytes(manifest).String())
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(manifest)))
+		if req.Method == http.MethodGet {
+			_, _ = w.Write(manifest)
+		}
+	case strings.HasPrefix(path, "blobs/"):
+		blob, ok := r.blobs[strings.TrimPrefix(path, "blobs/")]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Length", fmt.Sprintf("%d", len(blob)))
+		_, _ = w.Write(blob)
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func TestResolveAndFetch(t *testing.T) {
+	g := NewWithT(t)
+
+	testRegistry := newTestRegistry(t, map[string][]byte{
+		"0.1.0":       []byte("chart-0.1.0"),
+		"0.2.0":       []byte("chart-0.2.0"),
+		"1.0.0_build": []byte("chart-1.0.0+build"),
+	})
+	server := httptest.NewServer(testRegistry)
+	defer server.Close()
+
+	r := &Renderer{plainHTTP: true}
+	ref := strings.TrimPrefix(server.URL, "http://") + "/charts/test"
+
+	tests := []struct {
+		version string
+		want    string
+	}{
+		{version: "", want: "chart-1.0.0+build"},
+		{version: "0.1.0", want: "chart-0.1.0"},
+		{version: "~0.1", want: "chart-0.1.0"},
+		{version: "<1.0.0", want: "chart-0.2.0"},
+	}
+	for _, tt := range tests {
+		digest, err := r.Resolve(context.Background(), ref, tt.version, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		archive, err := r.Fetch(context.Background(), ref, digest, nil)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(string(archive)).To(Equal(tt.want), "version %q", tt.version)
+	}
+
+	// Fails if no version matches.
+	_, err := r.Resolve(context.Background(), ref, "2.0.0", nil)
+	g.Expect(err).To(HaveOccurred())
+
+	// Fails if the digest doesn't exist.
+	_, err = r.Fetch(context.Background(), ref, "sha256:0000000000000000000000000000000000000000000000000000000000000000", nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestResolveAndFetchWithCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	testRegistry := newTestRegistry(t, map[string][]byte{
+		"0.1.0": []byte("chart-0.1.0"),
+	})
+	testRegistry.username = "user"
+	testRegistry.password = "password"
+	server := httptest.NewServer(testRegistry)
+	defer server.Close()
+
+	r := &Renderer{plainHTTP: true}
+	ref := strings.TrimPrefix(server.URL, "http://") + "/charts/test"
+
+	// Fails without credentials.
+	_, err := r.Resolve(context.Background(), ref, "", nil)
+	g.Expect(err).To(HaveOccurred())
+
+	credentials := &addonscontrollers.HelmRegistryCredentials{Username: "user", Password: "password"}
+	digest, err := r.Resolve(context.Background(), ref, "", credentials)
+	g.Expect(err).ToNot(HaveOccurred())
+	archive, err := r.Fetch(context.Background(), ref, digest, credentials)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(string(archive)).To(Equal("chart-0.1.0"))
+}
+
+func TestResolveHonorsContext(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(newTestRegistry(t, map[string][]byte{"0.1.0": []byte("chart-0.1.0")}))
+	defer server.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	r := &Renderer{plainHTTP: true}
+	_, err := r.Resolve(ctx, strings.TrimPrefix(server.URL, "http://")+"/charts/test", "", nil)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestChartVersions(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(chartVersions([]string{"0.1.0", "latest", "1.0.0_build", "0.10.0", "v2"})).To(Equal([]string{"1.0.0+build", "0.10.0", "0.1.0"}))
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package helm implements rendering Helm charts for ClusterResourceSets.
+//
+// The package is a separate Go module, so the Helm and OCI registry dependencies are linked only into
+// managers setting ClusterResourceSetReconciler.HelmChartRenderer, e.g.:
+//
+//	(&addonscontrollers.ClusterResourceSetReconciler{
+//		Client:            mgr.GetClient(),
+//		Tracker:           tracker,
+//		HelmChartRenderer: helm.NewRenderer(),
+//	}).SetupWithManager(ctx, mgr, options)
+//
+// The ClusterResourceSet webhook of such managers must be set up with HelmChartsEnabled.
+package helm
+
+import (
+	"bytes"
+	"context"
+	"strings"
+
+	"github.com/pkg/errors"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chart/loader"
+	"helm.sh/helm/v3/pkg/chartutil"
+	"helm.sh/helm/v3/pkg/engine"
+	"helm.sh/helm/v3/pkg/releaseutil"
+
+	addonscontrollers "sigs.k8s.io/cluster-api/exp/addons/controllers"
+)
+
+const notesFileSuffix = "NOTES.txt"
+
+var _ addonscontrollers.HelmChartRenderer = &Renderer{}
+
+// Renderer renders Helm charts with the Helm libraries, and pulls charts from OCI registries.
+type Renderer struct {
+	// plainHTTP signals to use HTTP instead of HTTPS for OCI registries; only used in tests.
+	plainHTTP bool
+}
+
+// NewRenderer returns a new Renderer.
+func NewRenderer() *Renderer {
+	return &Renderer{}
+}
+
+// Render renders a packaged Helm chart with the given values, and returns the rendered manifests.
+// CRDs in the chart are returned first, followed by the chart templates sorted in install order; hooks are not rendered.
+func (r *Renderer) Render(_ context.Context, archive, values []byte, options addonscontrollers.HelmChartRenderOptions) ([][]byte, error) {
+	chrt, err := loader.LoadArchive(bytes.NewReader(archive))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to load chart")
+	}
+
+	chartValues, err := chartutil.ReadValues(values)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to read values of chart %s", chrt.Name())
+	}
+
+	releaseOptions := chartutil.ReleaseOptions{
+		Name:      options.ReleaseName,
+		Namespace: options.ReleaseNamespace,
+		Revision:  1,
+		IsInstall: true,
+	}
+	return renderTemplates(chrt, chartValues, releaseOptions, capabilities(options.KubernetesVersion))
+}
+
+func renderTemplates(chrt *chart.Chart, values chartutil.Values, options chartutil.ReleaseOptions, caps *chartutil.Capabilities) ([][]byte, error) {
+	if err := chartutil.ProcessDependenciesWithMerge(chrt, values); err != nil {
+		return nil, errors.Wrapf(err, "failed to process dependencies of chart %s", chrt.Name())
+	}
+
+	renderValues, err := chartutil.ToRenderValues(chrt, values, options, caps)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to compute values of chart %s", chrt.Name())
+	}
+
+	files, err := engine.Render(chrt, renderValues)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render chart %s", chrt.Name())
+	}
+	for name := range files {
+		if strings.HasSuffix(name, notesFileSuffix) {
+			delete(files, name)
+		}
+	}
+
+	_, manifests, err := releaseutil.SortManifests(files, caps.APIVersions, releaseutil.InstallOrder)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to sort manifests of chart %s", chrt.Name())
+	}
+
+	data := [][]byte{}
+	for _, crd := range chrt.CRDObjects() {
+		data = append(data, crd.File.Data)
+	}
+	for _, manifest := range manifests {
+		data = append(data, []byte(manifest.Content))
+	}
+	return data, nil
+}
+
+// capabilities returns the capabilities of the Cluster used when rendering charts.
+// NOTE: API versions are the Helm defaults.
+func capabilities(kubernetesVersion string) *chartutil.Capabilities {
+	caps := chartutil.DefaultCapabilities.Copy()
+	if kubernetesVersion != "" {
+		if kubeVersion, err := chartutil.ParseKubeVersion(kubernetesVersion); err == nil {
+			caps.KubeVersion = *kubeVersion
+		}
+	}
+	return caps
+}
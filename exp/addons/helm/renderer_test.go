@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helm
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"helm.sh/helm/v3/pkg/chart"
+	"helm.sh/helm/v3/pkg/chartutil"
+
+	addonscontrollers "sigs.k8s.io/cluster-api/exp/addons/controllers"
+)
+
+func newTestChart() *chart.Chart {
+	return &chart.Chart{
+		Metadata: &chart.Metadata{
+			APIVersion: chart.APIVersionV2,
+			Name:       "test-chart",
+			Version:    "0.1.0",
+		},
+		Values: map[string]interface{}{
+			"replicas": 1,
+		},
+		Templates: []*chart.File{
+			{
+				Name: "templates/deployment.yaml",
+				Data: []byte("apiVersion: apps/v1\nkind: Deployment\nmetadata:\n  name: {{ .Release.Name }}\n  namespace: {{ .Release.Namespace }}\nspec:\n  replicas: {{ .Values.replicas }}\n"),
+			},
+			{
+				Name: "templates/serviceaccount.yaml",
+				Data: []byte("apiVersion: v1\nkind: ServiceAccount\nmetadata:\n  name: {{ .Release.Name }}\n"),
+			},
+			{
+				Name: "templates/hook.yaml",
+				Data: []byte("apiVersion: batch/v1\nkind: Job\nmetadata:\n  name: hook\n  annotations:\n    helm.sh/hook: post-install\n"),
+			},
+			{
+				Name: "templates/NOTES.txt",
+				Data: []byte("Installed {{ .Release.Name }}"),
+			},
+			{
+				Name: "templates/version.yaml",
+				Data: []byte("apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: version\ndata:\n  version: {{ .Capabilities.KubeVersion.Version }}\n"),
+			},
+		},
+		Files: []*chart.File{
+			{
+				Name: "crds/crd.yaml",
+				Data: []byte("apiVersion: apiextensions.k8s.io/v1\nkind: CustomResourceDefinition\nmetadata:\n  name: tests.example.com\n"),
+			},
+		},
+	}
+}
+
+func TestRender(t *testing.T) {
+	g := NewWithT(t)
+
+	archivePath, err := chartutil.Save(newTestChart(), t.TempDir())
+	g.Expect(err).ToNot(HaveOccurred())
+	archive, err := os.ReadFile(filepath.Clean(archivePath))
+	g.Expect(err).ToNot(HaveOccurred())
+
+	data, err := NewRenderer().Render(context.Background(), archive, []byte("replicas: 3"), addonscontrollers.HelmChartRenderOptions{
+		ReleaseName:       "test",
+		ReleaseNamespace:  "test-system",
+		KubernetesVersion: "v1.30.1",
+	})
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// CRDs first, then templates in install order; hooks and notes are not rendered.
+	g.Expect(data).To(HaveLen(4))
+	g.Expect(string(data[0])).To(ContainSubstring("kind: CustomResourceDefinition"))
+	g.Expect(string(data[1])).To(ContainSubstring("kind: ServiceAccount"))
+	g.Expect(string(data[2])).To(ContainSubstring("kind: ConfigMap"))
+	g.Expect(string(data[2])).To(ContainSubstring("version: v1.30.1"))
+	g.Expect(string(data[3])).To(ContainSubstring("kind: Deployment"))
+	g.Expect(string(data[3])).To(ContainSubstring("name: test\n"))
+	g.Expect(string(data[3])).To(ContainSubstring("namespace: test-system"))
+	g.Expect(string(data[3])).To(ContainSubstring("replicas: 3"))
+
+	// Fails if the archive is not a packaged chart.
+	_, err = NewRenderer().Render(context.Background(), []byte("not a chart"), nil, addonscontrollers.HelmChartRenderOptions{})
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestCapabilities(t *testing.T) {
+	g := NewWithT(t)
+
+	caps := capabilities("")
+	g.Expect(caps.KubeVersion).To(Equal(chartutil.DefaultCapabilities.KubeVersion))
+
+	caps = capabilities("v1.30.1")
+	g.Expect(caps.KubeVersion.Version).To(Equal("v1.30.1"))
+}
@@ -61,8 +61,13 @@ type ClusterResourceSetReconciler struct {
 	Client  client.Client
 	Tracker *remote.ClusterCacheTracker
 
+	// HelmChartRenderer renders the Helm charts of ClusterResourceSets; if not set, Helm charts can't be applied.
+	HelmChartRenderer HelmChartRenderer
+
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
+
+	helmCharts helmChartCache
 }
 
 func (r *ClusterResourceSetReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
@@ -143,8 +148,12 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{}, nil
 	}
 
+	// Prune candidates are computed again for all the clusters.
+	clusterResourceSet.Status.PruneCandidates = nil
+
 	errs := []error{}
 	errClusterLockedOccurred := false
+	waitingForWave := false
 	for _, cluster := range clusters {
 		if err := r.ApplyClusterResourceSet(ctx, cluster, clusterResourceSet); err != nil {
 			// Requeue if the reconcile failed because the ClusterCacheTracker was locked for
@@ -152,6 +161,9 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 			if errors.Is(err, remote.ErrClusterLocked) {
 				log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
 				errClusterLockedOccurred = true
+			} else if errors.Is(err, errWaitingForWave) {
+				// Requeue to check again the readiness of the objects of the wave being applied.
+				waitingForWave = true
 			} else {
 				// Append the error if the error is not ErrClusterLocked.
 				errs = append(errs, err)
@@ -159,6 +171,16 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		}
 	}
 
+	// Prune objects from the clusters which are no longer matched by the ClusterResourceSet.
+	if err := r.reconcilePruneUnmatchedClusters(ctx, clusters, clusterResourceSet); err != nil {
+		if errors.Is(err, remote.ErrClusterLocked) {
+			log.V(5).Info("Requeuing because another worker has the lock on the ClusterCacheTracker")
+			errClusterLockedOccurred = true
+		} else {
+			errs = append(errs, err)
+		}
+	}
+
 	// Return an aggregated error if errors occurred.
 	if len(errs) > 0 {
 		return ctrl.Result{}, kerrors.NewAggregate(errs)
@@ -171,13 +193,24 @@ func (r *ClusterResourceSetReconciler) Reconcile(ctx context.Context, req ctrl.R
 		return ctrl.Result{RequeueAfter: time.Minute}, nil
 	}
 
+	// Requeue if the resources of a wave are waiting for the previous waves to be ready.
+	if waitingForWave {
+		return ctrl.Result{RequeueAfter: waveReadinessRequeueAfter}, nil
+	}
+
+	// Requeue to periodically check drift of the applied objects.
+	if clusterResourceSet.Spec.DriftDetection != nil {
+		return ctrl.Result{RequeueAfter: driftDetectionInterval(clusterResourceSet)}, nil
+	}
+
 	return ctrl.Result{}, nil
 }
 
 // reconcileDelete removes the deleted ClusterResourceSet from all the ClusterResourceSetBindings it is added to.
+// If prune is enabled, the objects applied by the ClusterResourceSet are deleted from the clusters first.
 func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clusters []*clusterv1.Cluster, crs *addonsv1.ClusterResourceSet) error {
 	for _, cluster := range clusters {
-		log := ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster))
+		ctx := ctrl.LoggerInto(ctx, ctrl.LoggerFrom(ctx, "Cluster", klog.KObj(cluster)))
 
 		clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{}
 		clusterResourceSetBindingKey := client.ObjectKey{
@@ -198,20 +231,14 @@ func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clus
 			return err
 		}
 
-		clusterResourceSetBinding.RemoveBinding(crs)
-		clusterResourceSetBinding.OwnerReferences = util.RemoveOwnerRef(clusterResourceSetBinding.GetOwnerReferences(), metav1.OwnerReference{
-			APIVersion: addonsv1.GroupVersion.String(),
-			Kind:       "ClusterResourceSet",
-			Name:       crs.Name,
-		})
-
-		// If CRS list is empty in the binding, delete the binding else
-		// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
-		if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
-			if r.Client.Delete(ctx, clusterResourceSetBinding) != nil {
-				log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+		if crs.Spec.GetTypedPrune() == addonsv1.ClusterResourceSetPruneEnabled {
+			if err := r.pruneClusterResourceSetBinding(ctx, cluster, crs, clusterResourceSetBinding); err != nil {
+				conditions.MarkFalse(crs, addonsv1.ResourcesAppliedCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+				return errors.Wrapf(err, "failed to prune objects from Cluster %s", klog.KObj(cluster))
 			}
-		} else if err := patchHelper.Patch(ctx, clusterResourceSetBinding); err != nil {
+		}
+
+		if err := r.removeClusterResourceSetBinding(ctx, crs, clusterResourceSetBinding, patchHelper); err != nil {
 			return err
 		}
 	}
@@ -220,6 +247,29 @@ func (r *ClusterResourceSetReconciler) reconcileDelete(ctx context.Context, clus
 	return nil
 }
 
+// removeClusterResourceSetBinding removes the ClusterResourceSet from a ClusterResourceSetBinding, and it deletes
+// the ClusterResourceSetBinding if no other ClusterResourceSet is bound to the cluster.
+func (r *ClusterResourceSetReconciler) removeClusterResourceSetBinding(ctx context.Context, crs *addonsv1.ClusterResourceSet, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, patchHelper *patch.Helper) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	clusterResourceSetBinding.RemoveBinding(crs)
+	clusterResourceSetBinding.OwnerReferences = util.RemoveOwnerRef(clusterResourceSetBinding.GetOwnerReferences(), metav1.OwnerReference{
+		APIVersion: addonsv1.GroupVersion.String(),
+		Kind:       "ClusterResourceSet",
+		Name:       crs.Name,
+	})
+
+	// If CRS list is empty in the binding, delete the binding else
+	// attempt to Patch the ClusterResourceSetBinding object after delete reconciliation if there is at least 1 binding left.
+	if len(clusterResourceSetBinding.Spec.Bindings) == 0 {
+		if err := r.Client.Delete(ctx, clusterResourceSetBinding); err != nil {
+			log.Error(err, "failed to delete empty ClusterResourceSetBinding")
+		}
+		return nil
+	}
+	return patchHelper.Patch(ctx, clusterResourceSetBinding)
+}
+
 // getClustersByClusterResourceSetSelector fetches Clusters matched by the ClusterResourceSet's label selector that are in the same namespace as the ClusterResourceSet object.
 func (r *ClusterResourceSetReconciler) getClustersByClusterResourceSetSelector(ctx context.Context, clusterResourceSet *addonsv1.ClusterResourceSet) ([]*clusterv1.Cluster, error) {
 	log := ctrl.LoggerFrom(ctx)
@@ -304,7 +354,96 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 	resourceSetBinding := clusterResourceSetBinding.GetOrCreateBinding(clusterResourceSet)
 
 	// Iterate all resources and apply them to the cluster and update the resource status in the ClusterResourceSetBinding object.
-	for _, resource := range clusterResourceSet.Spec.Resources {
+	// Resources are applied wave by wave, and the resources of a wave are applied only when the objects of all the
+	// previous waves are ready; resources which are not part of any wave are applied after all the waves.
+	// The objects defined by the resources are collected, so objects which are no longer part of the ClusterResourceSet can be pruned.
+	desired := newDesiredObjects()
+	driftedObjects := []addonsv1.DriftedObject{}
+	previousDriftedObjects := clusterResourceSetBinding.GetDriftedObjects(clusterResourceSet.Name)
+	waveStatuses := []addonsv1.WaveStatus{}
+	waitingForWave := ""
+	for _, group := range resourceGroups(clusterResourceSet) {
+		if waitingForWave != "" {
+			if group.wave != "" {
+				waveStatuses = append(waveStatuses, addonsv1.WaveStatus{Name: group.wave, Phase: addonsv1.WavePending})
+			}
+			continue
+		}
+
+		applyFailed := false
+		notApplied := []string{}
+		waveObjs := []unstructured.Unstructured{}
+		for _, resource := range group.resources {
+			resourceScope, drifted, err := r.reconcileResource(ctx, remoteClient, cluster, clusterResourceSet, resourceSetBinding, resource, previousDriftedObjects)
+			if err != nil {
+				errList = append(errList, err)
+				applyFailed = true
+			}
+			driftedObjects = append(driftedObjects, drifted...)
+			if resourceScope == nil {
+				notApplied = append(notApplied, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+				continue
+			}
+			desired.add(resource, resourceScope.objs())
+			if !resourceSetBinding.IsApplied(resource) {
+				notApplied = append(notApplied, fmt.Sprintf("%s/%s", resource.Kind, resource.Name))
+			}
+			waveObjs = append(waveObjs, resourceScope.objs()...)
+		}
+
+		if group.wave == "" {
+			continue
+		}
+		waveStatus, err := getWaveStatus(ctx, remoteClient, group.wave, applyFailed, notApplied, waveObjs)
+		if err != nil {
+			errList = append(errList, err)
+		}
+		waveStatuses = append(waveStatuses, waveStatus)
+		if waveStatus.Phase != addonsv1.WaveReady {
+			waitingForWave = group.wave
+		}
+	}
+
+	clusterResourceSetBinding.SetDriftedObjects(clusterResourceSet.Name, driftedObjects)
+	clusterResourceSetBinding.SetWaveStatuses(clusterResourceSet.Name, waveStatuses)
+
+	// Delete, or list in dry-run mode, the objects which are no longer part of the ClusterResourceSet.
+	if err := r.reconcilePrune(ctx, remoteClient, cluster, clusterResourceSet, clusterResourceSetBinding, resourceSetBinding, desired); err != nil {
+		errList = append(errList, err)
+	}
+
+	if len(errList) > 0 {
+		return kerrors.NewAggregate(errList)
+	}
+
+	if waitingForWave != "" {
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WaitingForWaveReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for wave %s to be ready in cluster %s", waitingForWave, klog.KObj(cluster))
+		return errWaitingForWave
+	}
+
+	conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+
+	return nil
+}
+
+// reconcileResource applies a resource of a ClusterResourceSet to a cluster, and updates the resource status in the ResourceSetBinding.
+// It returns the scope for applying the resource, which is nil if the resource can't be retrieved or rendered,
+// and the objects of the resource which drifted in the cluster; previousDriftedObjects are the drifted objects recorded
+// by the previous reconcile, used to preserve the time when drift was first detected.
+func (r *ClusterResourceSetReconciler) reconcileResource(ctx context.Context, remoteClient client.Client, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet, resourceSetBinding *addonsv1.ResourceSetBinding, resource addonsv1.ResourceRef, previousDriftedObjects []addonsv1.DriftedObject) (resourceReconcileScope, []addonsv1.DriftedObject, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	errList := []error{}
+	var resourceScope resourceReconcileScope
+	if isRenderedResource(resource) {
+		// Helm charts and Kustomizations are rendered for each cluster before being applied.
+		var err error
+		resourceScope, err = r.reconcileScopeForRenderedResource(ctx, remoteClient, cluster, clusterResourceSet, resource, resourceSetBinding)
+		if err != nil {
+			return nil, nil, err
+		}
+	} else {
 		unstructuredObj, err := r.getResource(ctx, resource, cluster.GetNamespace())
 		if err != nil {
 			if err == ErrSecretTypeNotSupported {
@@ -314,11 +453,10 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 
 				// Continue without adding the error to the aggregate if we can't find the resource.
 				if apierrors.IsNotFound(err) {
-					continue
+					return nil, nil, nil
 				}
 			}
-			errList = append(errList, err)
-			continue
+			return nil, nil, err
 		}
 
 		// Ensure an ownerReference to the clusterResourceSet is on the resource.
@@ -328,56 +466,153 @@ func (r *ClusterResourceSetReconciler) ApplyClusterResourceSet(ctx context.Conte
 			errList = append(errList, err)
 		}
 
-		resourceScope, err := reconcileScopeForResource(clusterResourceSet, resource, resourceSetBinding, unstructuredObj)
+		resourceScope, err = reconcileScopeForResource(clusterResourceSet, resource, resourceSetBinding, unstructuredObj)
 		if err != nil {
 			resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
 				ResourceRef:     resource,
 				Hash:            "",
 				Applied:         false,
 				LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+				Objects:         trackedObjects(resourceSetBinding, resource),
 			})
 
 			errList = append(errList, err)
-			continue
+			return nil, nil, kerrors.NewAggregate(errList)
 		}
+	}
+
+	var driftedObjects []addonsv1.DriftedObject
+	// Detect drift only for objects already applied, which are not going to be applied again.
+	if clusterResourceSet.Spec.DriftDetection != nil && !resourceScope.needsApply() {
+		drifted, err := r.reconcileDrift(ctx, remoteClient, clusterResourceSet, resource, resourceScope, previousDriftedObjects)
+		if err != nil {
+			errList = append(errList, err)
+		}
+		driftedObjects = append(driftedObjects, drifted...)
+	}
+
+	if err := r.applyResource(ctx, remoteClient, clusterResourceSet, resource, resourceSetBinding, resourceScope); err != nil {
+		errList = append(errList, err)
+	}
 
-		if !resourceScope.needsApply() {
+	return resourceScope, driftedObjects, kerrors.NewAggregate(errList)
+}
+
+// reconcileScopeForRenderedResource renders a Helm chart or a Kustomization for a Cluster, and returns the scope
+// for applying the rendered objects; the hash of the rendered objects is used to detect changes with the Reconcile strategy.
+func (r *ClusterResourceSetReconciler) reconcileScopeForRenderedResource(ctx context.Context, remoteClient client.Client, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef, resourceSetBinding *addonsv1.ResourceSetBinding) (resourceReconcileScope, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Ensure an ownerReference to the clusterResourceSet is on the Secrets and ConfigMaps used for rendering.
+	for _, sourceRef := range sourceRefsForResource(clusterResourceSet, resource) {
+		source, err := r.getResource(ctx, addonsv1.ResourceRef{Name: sourceRef.Name, Kind: sourceRef.Kind}, cluster.GetNamespace())
+		if err != nil {
 			continue
 		}
+		if err := r.ensureResourceOwnerRef(ctx, clusterResourceSet, source); err != nil {
+			log.Error(err, "Failed to add ClusterResourceSet as resource owner reference",
+				"Resource type", source.GetKind(), "Resource name", source.GetName())
+		}
+	}
 
-		// Set status in ClusterResourceSetBinding in case of early continue due to a failure.
-		// Set only when resource is retrieved successfully.
-		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
-			ResourceRef:     resource,
-			Hash:            "",
-			Applied:         false,
-			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
-		})
-
-		// Apply all values in the key-value pair of the resource to the cluster.
-		// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
-		isSuccessful := true
-		if err := resourceScope.apply(ctx, remoteClient); err != nil {
-			isSuccessful = false
-			log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
-			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
-			errList = append(errList, err)
+	data, err := r.renderResource(ctx, cluster, clusterResourceSet, resource)
+	if err != nil {
+		if errors.Is(err, ErrSecretTypeNotSupported) {
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.WrongSecretTypeReason, clusterv1.ConditionSeverityWarning, err.Error())
+		} else {
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RenderingResourceFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
 		}
+		return nil, err
+	}
 
-		resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
-			ResourceRef:     resource,
-			Hash:            resourceScope.hash(),
-			Applied:         isSuccessful,
-			LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
-		})
+	objs, err := objsFromYamlData(data)
+	if err != nil {
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.RenderingResourceFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return nil, err
 	}
-	if len(errList) > 0 {
-		return kerrors.NewAggregate(errList)
+
+	// Objects rendered from a Helm chart without a namespace are created in the release namespace.
+	if resource.Kind == string(addonsv1.HelmChartClusterResourceSetResourceKind) {
+		releaseNamespace := helmChartRenderOptions(clusterResourceSet.Spec.GetHelmChart(resource.Name), cluster).ReleaseNamespace
+		for i := range objs {
+			if objs[i].GetNamespace() != "" {
+				continue
+			}
+			// NOTE: if the object kind is not known yet, e.g. because its CRD is created by the same chart, the
+			// object is created without a namespace.
+			if namespaced, err := remoteClient.IsObjectNamespaced(&objs[i]); err == nil && namespaced {
+				objs[i].SetNamespace(releaseNamespace)
+			}
+		}
 	}
 
-	conditions.MarkTrue(clusterResourceSet, addonsv1.ResourcesAppliedCondition)
+	return newResourceReconcileScope(clusterResourceSet, resource, resourceSetBinding, data, objs), nil
+}
 
-	return nil
+// applyResource applies the objects defined by a resource to the cluster if required by the ClusterResourceSet strategy,
+// and updates the resource status in the ClusterResourceSetBinding object, including the list of applied objects.
+func (r *ClusterResourceSetReconciler) applyResource(ctx context.Context, remoteClient client.Client, clusterResourceSet *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef, resourceSetBinding *addonsv1.ResourceSetBinding, resourceScope resourceReconcileScope) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if !resourceScope.needsApply() {
+		// Start tracking objects applied before object tracking was introduced; this is possible only with
+		// the Reconcile strategy, where an unchanged hash ensures the objects are the ones applied.
+		if resourceBinding := resourceSetBinding.GetResource(resource); resourceBinding != nil && len(resourceBinding.Objects) == 0 &&
+			clusterResourceSet.Spec.Strategy == string(addonsv1.ClusterResourceSetStrategyReconcile) {
+			resourceBinding.Objects = appliedObjectReferences(resourceScope.objs())
+			resourceSetBinding.SetBinding(*resourceBinding)
+		}
+		return nil
+	}
+
+	// Set status in ClusterResourceSetBinding in case of early return due to a failure.
+	// Set only when resource is retrieved successfully.
+	previousObjects := trackedObjects(resourceSetBinding, resource)
+	resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
+		ResourceRef:     resource,
+		Hash:            "",
+		Applied:         false,
+		LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+		Objects:         previousObjects,
+	})
+
+	// Apply all values in the key-value pair of the resource to the cluster.
+	// As there can be multiple key-value pairs in a resource, each value may have multiple objects in it.
+	var applyErr error
+	if err := resourceScope.apply(ctx, remoteClient); err != nil {
+		log.Error(err, "failed to apply ClusterResourceSet resource", "Resource kind", resource.Kind, "Resource name", resource.Name)
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		applyErr = err
+	}
+
+	resourceSetBinding.SetBinding(addonsv1.ResourceBinding{
+		ResourceRef:     resource,
+		Hash:            resourceScope.hash(),
+		Applied:         applyErr == nil,
+		LastAppliedTime: &metav1.Time{Time: time.Now().UTC()},
+		Objects:         trackObjects(clusterResourceSet, previousObjects, resourceScope.objs()),
+	})
+	return applyErr
+}
+
+// renderResource renders a Helm chart or a Kustomization for a Cluster.
+func (r *ClusterResourceSetReconciler) renderResource(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef) ([][]byte, error) {
+	switch resource.Kind {
+	case string(addonsv1.HelmChartClusterResourceSetResourceKind):
+		source := clusterResourceSet.Spec.GetHelmChart(resource.Name)
+		if source == nil {
+			return nil, errors.Errorf("Helm chart %s is not defined in spec.helmCharts", resource.Name)
+		}
+		return r.renderHelmChart(ctx, clusterResourceSet, cluster, source)
+	case string(addonsv1.KustomizationClusterResourceSetResourceKind):
+		source := clusterResourceSet.Spec.GetKustomization(resource.Name)
+		if source == nil {
+			return nil, errors.Errorf("Kustomization %s is not defined in spec.kustomizations", resource.Name)
+		}
+		return r.renderKustomization(ctx, clusterResourceSet, cluster, source)
+	default:
+		return nil, errors.Errorf("resource kind %q can't be rendered", resource.Kind)
+	}
 }
 
 // getResource retrieves the requested resource and convert it to unstructured type.
@@ -468,9 +703,32 @@ func (r *ClusterResourceSetReconciler) clusterToClusterResourceSet(ctx context.C
 		name := client.ObjectKey{Namespace: rs.Namespace, Name: rs.Name}
 		result = append(result, ctrl.Request{NamespacedName: name})
 	}
+
+	// Add the ClusterResourceSets bound to the cluster, so objects can be pruned when the cluster is no longer matched.
+	binding := &addonsv1.ClusterResourceSetBinding{}
+	if err := r.Client.Get(ctx, client.ObjectKeyFromObject(cluster), binding); err == nil {
+		for _, b := range binding.Spec.Bindings {
+			if b == nil {
+				continue
+			}
+			name := client.ObjectKey{Namespace: cluster.Namespace, Name: b.ClusterResourceSetName}
+			if !containsRequest(result, name) {
+				result = append(result, ctrl.Request{NamespacedName: name})
+			}
+		}
+	}
 	return result
 }
 
+func containsRequest(requests []ctrl.Request, name client.ObjectKey) bool {
+	for _, request := range requests {
+		if request.NamespacedName == name {
+			return true
+		}
+	}
+	return false
+}
+
 // resourceToClusterResourceSet is mapper function that maps resources to ClusterResourceSet.
 func (r *ClusterResourceSetReconciler) resourceToClusterResourceSet(ctx context.Context, o client.Object) []ctrl.Request {
 	result := []ctrl.Request{}
@@ -502,15 +760,29 @@ func (r *ClusterResourceSetReconciler) resourceToClusterResourceSet(ctx context.
 	if err != nil {
 		return nil
 	}
-	for _, crs := range crsList.Items {
-		for _, resource := range crs.Spec.Resources {
-			if resource.Kind == objKind.Kind && resource.Name == o.GetName() {
-				name := client.ObjectKey{Namespace: o.GetNamespace(), Name: crs.Name}
-				result = append(result, ctrl.Request{NamespacedName: name})
-				break
-			}
+	for i := range crsList.Items {
+		crs := &crsList.Items[i]
+		if clusterResourceSetReferences(crs, objKind.Kind, o.GetName()) {
+			name := client.ObjectKey{Namespace: o.GetNamespace(), Name: crs.Name}
+			result = append(result, ctrl.Request{NamespacedName: name})
 		}
 	}
 
 	return result
 }
+
+// clusterResourceSetReferences returns true if a ClusterResourceSet references a Secret or a ConfigMap,
+// either as a resource or as a source of a Helm chart or Kustomization.
+func clusterResourceSetReferences(crs *addonsv1.ClusterResourceSet, kind, name string) bool {
+	for _, resource := range crs.Spec.Resources {
+		if resource.Kind == kind && resource.Name == name {
+			return true
+		}
+		for _, sourceRef := range sourceRefsForResource(crs, resource) {
+			if sourceRef.Kind == kind && sourceRef.Name == name {
+				return true
+			}
+		}
+	}
+	return false
+}
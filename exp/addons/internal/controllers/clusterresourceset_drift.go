@@ -0,0 +1,136 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+const (
+	defaultDriftDetectionInterval = 10 * time.Minute
+
+	// driftDetectionFieldManager is the field manager used for the server-side apply dry-run requests
+	// computing the desired state of objects.
+	driftDetectionFieldManager = "capi-clusterresourceset-drift-detection"
+)
+
+// driftDetectionInterval returns the interval between drift checks for a ClusterResourceSet.
+func driftDetectionInterval(clusterResourceSet *addonsv1.ClusterResourceSet) time.Duration {
+	if clusterResourceSet.Spec.DriftDetection == nil || clusterResourceSet.Spec.DriftDetection.Interval == nil {
+		return defaultDriftDetectionInterval
+	}
+	return clusterResourceSet.Spec.DriftDetection.Interval.Duration
+}
+
+// reconcileDrift detects drift for the objects of a resource already applied to a Cluster, and applies them again
+// if remediation is enabled; it returns the objects which are drifted after remediation.
+func (r *ClusterResourceSetReconciler) reconcileDrift(ctx context.Context, remoteClient client.Client, clusterResourceSet *addonsv1.ClusterResourceSet, resource addonsv1.ResourceRef, resourceScope resourceReconcileScope, previousDriftedObjects []addonsv1.DriftedObject) ([]addonsv1.DriftedObject, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	driftedObjects, err := detectDrift(ctx, remoteClient, resource, resourceScope.objs(), previousDriftedObjects)
+	if err != nil {
+		return driftedObjects, err
+	}
+	if len(driftedObjects) == 0 || !clusterResourceSet.Spec.DriftDetection.Remediate {
+		return driftedObjects, nil
+	}
+
+	log.Info("Applying drifted objects again", "Resource kind", resource.Kind, "Resource name", resource.Name)
+	if err := resourceScope.apply(ctx, remoteClient); err != nil {
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.ApplyFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return driftedObjects, err
+	}
+	return nil, nil
+}
+
+// detectDrift compares the objects in the cluster with their desired state, computed by using a server-side apply dry-run
+// of the objects; fields not defined by the objects, e.g. fields added in the cluster, are not considered drift.
+// The time when drift was first detected is preserved for objects which are already in previousDriftedObjects.
+func detectDrift(ctx context.Context, c client.Client, resource addonsv1.ResourceRef, objs []unstructured.Unstructured, previousDriftedObjects []addonsv1.DriftedObject) ([]addonsv1.DriftedObject, error) {
+	driftedObjects := []addonsv1.DriftedObject{}
+	for i := range objs {
+		obj := &objs[i]
+
+		current := &unstructured.Unstructured{}
+		current.SetAPIVersion(obj.GetAPIVersion())
+		current.SetKind(obj.GetKind())
+		if err := c.Get(ctx, client.ObjectKeyFromObject(obj), current); err != nil {
+			if apierrors.IsNotFound(err) {
+				driftedObjects = append(driftedObjects, newDriftedObject(resource, obj, addonsv1.ObjectDeletedDriftReason, previousDriftedObjects))
+				continue
+			}
+			return driftedObjects, errors.Wrapf(err, "reading object %s %s", obj.GroupVersionKind(), klog.KObj(obj))
+		}
+
+		desired := obj.DeepCopy()
+		desired.SetResourceVersion("")
+		if err := c.Patch(ctx, desired, client.Apply, client.DryRunAll, client.ForceOwnership, client.FieldOwner(driftDetectionFieldManager)); err != nil {
+			return driftedObjects, errors.Wrapf(err, "computing desired state of object %s %s", obj.GroupVersionKind(), klog.KObj(obj))
+		}
+
+		if isDrifted(current, desired) {
+			driftedObjects = append(driftedObjects, newDriftedObject(resource, obj, addonsv1.ObjectModifiedDriftReason, previousDriftedObjects))
+		}
+	}
+	return driftedObjects, nil
+}
+
+// isDrifted returns true if the current object differs from the desired object, ignoring the status and
+// the metadata fields changed by the server-side apply dry-run.
+func isDrifted(current, desired *unstructured.Unstructured) bool {
+	normalize := func(obj *unstructured.Unstructured) map[string]interface{} {
+		normalized := obj.DeepCopy()
+		normalized.SetManagedFields(nil)
+		normalized.SetResourceVersion("")
+		normalized.SetGeneration(0)
+		unstructured.RemoveNestedField(normalized.Object, "status")
+		return normalized.Object
+	}
+	return !equality.Semantic.DeepEqual(normalize(current), normalize(desired))
+}
+
+// newDriftedObject returns a DriftedObject for an object of a resource; if the object was already drifted, the time
+// when drift was first detected is preserved, even if the reason of the drift changed.
+func newDriftedObject(resource addonsv1.ResourceRef, obj *unstructured.Unstructured, reason addonsv1.DriftReason, previousDriftedObjects []addonsv1.DriftedObject) addonsv1.DriftedObject {
+	driftedObject := addonsv1.DriftedObject{
+		Resource:               resource,
+		AppliedObjectReference: appliedObjectReferences([]unstructured.Unstructured{*obj})[0],
+		Reason:                 reason,
+		DetectedTime:           metav1.Time{Time: time.Now().UTC()},
+	}
+	for _, previous := range previousDriftedObjects {
+		if previous.Resource == driftedObject.Resource && previous.AppliedObjectReference == driftedObject.AppliedObjectReference {
+			driftedObject.DetectedTime = previous.DetectedTime
+			break
+		}
+	}
+	return driftedObject
+}
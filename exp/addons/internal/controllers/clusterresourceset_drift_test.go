@@ -0,0 +1,132 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestIsDrifted(t *testing.T) {
+	current := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "config",
+			"namespace":       "kube-system",
+			"resourceVersion": "1",
+			"managedFields":   []interface{}{map[string]interface{}{"manager": "kubectl"}},
+		},
+		"data": map[string]interface{}{"key": "value"},
+	}}
+
+	tests := []struct {
+		name    string
+		desired func() *unstructured.Unstructured
+		want    bool
+	}{
+		{
+			name: "should not detect drift when only server-side apply metadata differs",
+			desired: func() *unstructured.Unstructured {
+				desired := current.DeepCopy()
+				desired.SetResourceVersion("2")
+				desired.SetManagedFields([]metav1.ManagedFieldsEntry{{Manager: driftDetectionFieldManager}})
+				return desired
+			},
+			want: false,
+		},
+		{
+			name: "should not detect drift when only status differs",
+			desired: func() *unstructured.Unstructured {
+				desired := current.DeepCopy()
+				desired.Object["status"] = map[string]interface{}{"ready": true}
+				return desired
+			},
+			want: false,
+		},
+		{
+			name: "should detect drift when data differs",
+			desired: func() *unstructured.Unstructured {
+				desired := current.DeepCopy()
+				desired.Object["data"] = map[string]interface{}{"key": "other"}
+				return desired
+			},
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(isDrifted(current, tt.desired())).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestDetectDriftDeletedObject(t *testing.T) {
+	g := NewWithT(t)
+
+	resource := addonsv1.ResourceRef{Name: "resource", Kind: "ConfigMap"}
+	objs := []unstructured.Unstructured{newTestObject("v1", "ConfigMap", "kube-system", "deleted")}
+
+	driftedObjects, err := detectDrift(ctx, fake.NewClientBuilder().Build(), resource, objs, nil)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(driftedObjects).To(HaveLen(1))
+	g.Expect(driftedObjects[0].Resource).To(Equal(resource))
+	g.Expect(driftedObjects[0].AppliedObjectReference).To(Equal(addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "deleted"}))
+	g.Expect(driftedObjects[0].Reason).To(Equal(addonsv1.ObjectDeletedDriftReason))
+	g.Expect(driftedObjects[0].DetectedTime.IsZero()).To(BeFalse())
+}
+
+func TestDetectDriftPreservesDetectedTime(t *testing.T) {
+	g := NewWithT(t)
+
+	resource := addonsv1.ResourceRef{Name: "resource", Kind: "ConfigMap"}
+	objs := []unstructured.Unstructured{newTestObject("v1", "ConfigMap", "kube-system", "deleted")}
+	ref := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "deleted"}
+	firstDetected := metav1.NewTime(time.Now().UTC().Add(-time.Hour))
+
+	previousDriftedObjects := []addonsv1.DriftedObject{
+		// Drift for the same object detected from another resource should be ignored.
+		{Resource: addonsv1.ResourceRef{Name: "other", Kind: "Secret"}, AppliedObjectReference: ref, Reason: addonsv1.ObjectModifiedDriftReason, DetectedTime: metav1.NewTime(firstDetected.Add(-time.Hour))},
+		// The object was already drifted, even if for a different reason.
+		{Resource: resource, AppliedObjectReference: ref, Reason: addonsv1.ObjectModifiedDriftReason, DetectedTime: firstDetected},
+	}
+
+	driftedObjects, err := detectDrift(ctx, fake.NewClientBuilder().Build(), resource, objs, previousDriftedObjects)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(driftedObjects).To(HaveLen(1))
+	g.Expect(driftedObjects[0].Reason).To(Equal(addonsv1.ObjectDeletedDriftReason))
+	g.Expect(driftedObjects[0].DetectedTime).To(Equal(firstDetected))
+}
+
+func TestDriftDetectionInterval(t *testing.T) {
+	g := NewWithT(t)
+
+	crs := &addonsv1.ClusterResourceSet{}
+	g.Expect(driftDetectionInterval(crs)).To(Equal(defaultDriftDetectionInterval))
+
+	crs.Spec.DriftDetection = &addonsv1.ClusterResourceSetDriftDetection{Interval: &metav1.Duration{Duration: 2 * defaultDriftDetectionInterval}}
+	g.Expect(driftDetectionInterval(crs)).To(Equal(2 * defaultDriftDetectionInterval))
+}
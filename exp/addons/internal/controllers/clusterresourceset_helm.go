@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/cache"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+const (
+	defaultHelmChartKey              = "chart.tgz"
+	defaultHelmChartReleaseNamespace = "default"
+	helmChartOCIScheme               = "oci://"
+
+	// helmChartPullTimeout is the timeout for resolving and pulling a Helm chart from an OCI registry.
+	helmChartPullTimeout = 2 * time.Minute
+
+	// helmChartResolveTTL is the duration for which the digest a Helm chart version is resolved to is cached.
+	helmChartResolveTTL = 10 * time.Minute
+
+	// helmChartArchiveTTL is the duration for which a Helm chart pulled from an OCI registry is cached.
+	helmChartArchiveTTL = 24 * time.Hour
+
+	// helmChartCacheSize is the maximum number of digests and Helm charts in the cache.
+	helmChartCacheSize = 64
+)
+
+// ErrHelmChartRendererNotConfigured signals that Helm charts can't be rendered because no HelmChartRenderer is configured.
+var ErrHelmChartRendererNotConfigured = errors.New("rendering Helm charts is not supported by this controller, see the ClusterResourceSet documentation")
+
+// HelmChartRenderer renders Helm charts for ClusterResourceSets.
+// NOTE: The Helm implementation is provided by the sigs.k8s.io/cluster-api/exp/addons/helm module, so the Helm
+// and OCI registry dependencies are linked only into managers rendering Helm charts.
+type HelmChartRenderer interface {
+	// Render renders a packaged Helm chart with the given values, and returns the rendered manifests.
+	// CRDs in the chart are returned first, followed by the chart templates sorted in install order; hooks are not rendered.
+	Render(ctx context.Context, archive, values []byte, options HelmChartRenderOptions) ([][]byte, error)
+
+	// Resolve returns the digest of the manifest of a Helm chart in an OCI registry; version can be a version
+	// or a version constraint, if empty the latest version is resolved.
+	Resolve(ctx context.Context, ref, version string, credentials *HelmRegistryCredentials) (string, error)
+
+	// Fetch returns the packaged Helm chart with the given manifest digest from an OCI registry.
+	Fetch(ctx context.Context, ref, digest string, credentials *HelmRegistryCredentials) ([]byte, error)
+}
+
+// HelmRegistryCredentials are the credentials used to pull Helm charts from an OCI registry.
+type HelmRegistryCredentials struct {
+	Username string
+	Password string
+}
+
+// HelmChartRenderOptions are the options used to render a Helm chart.
+type HelmChartRenderOptions struct {
+	// ReleaseName is the name of the Helm release.
+	ReleaseName string
+
+	// ReleaseNamespace is the namespace of the Helm release.
+	ReleaseNamespace string
+
+	// KubernetesVersion is the Kubernetes version of the Cluster, if known.
+	KubernetesVersion string
+}
+
+// renderHelmChart renders a Helm chart for a Cluster, and returns the rendered manifests.
+func (r *ClusterResourceSetReconciler) renderHelmChart(ctx context.Context, crs *addonsv1.ClusterResourceSet, cluster *clusterv1.Cluster, source *addonsv1.HelmChartSource) ([][]byte, error) {
+	if r.HelmChartRenderer == nil {
+		return nil, errors.Wrapf(ErrHelmChartRendererNotConfigured, "failed to render Helm chart %s", source.Name)
+	}
+
+	archive, err := r.getHelmChartArchive(ctx, crs, source)
+	if err != nil {
+		return nil, err
+	}
+
+	values := substituteVariables([]byte(source.Values), clusterVariables(cluster))
+	data, err := r.HelmChartRenderer.Render(ctx, archive, values, helmChartRenderOptions(source, cluster))
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Helm chart %s", source.Name)
+	}
+	return data, nil
+}
+
+// getHelmChartArchive returns the packaged Helm chart, either from a Secret/ConfigMap or from an OCI registry.
+func (r *ClusterResourceSetReconciler) getHelmChartArchive(ctx context.Context, crs *addonsv1.ClusterResourceSet, source *addonsv1.HelmChartSource) ([]byte, error) {
+	if source.ChartRef != nil {
+		key := source.ChartKey
+		if key == "" {
+			key = defaultHelmChartKey
+		}
+		files, err := r.getSourceFiles(ctx, crs, *source.ChartRef)
+		if err != nil {
+			return nil, err
+		}
+		archive, ok := files[key]
+		if !ok {
+			return nil, errors.Errorf("failed to get Helm chart %s: key %q not found in %s %s", source.Name, key, source.ChartRef.Kind, source.ChartRef.Name)
+		}
+		return archive, nil
+	}
+
+	if source.OCI == "" {
+		return nil, errors.Errorf("failed to get Helm chart %s: one of chartRef or oci must be set", source.Name)
+	}
+	archive, err := r.pullHelmChart(ctx, crs, source)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get Helm chart %s", source.Name)
+	}
+	return archive, nil
+}
+
+// pullHelmChart pulls a packaged Helm chart from an OCI registry.
+// The version is resolved to a digest at most every helmChartResolveTTL, and charts are cached by digest,
+// so charts are not pulled again on every reconcile, including charts without an explicit version.
+func (r *ClusterResourceSetReconciler) pullHelmChart(ctx context.Context, crs *addonsv1.ClusterResourceSet, source *addonsv1.HelmChartSource) ([]byte, error) {
+	ctx, cancel := context.WithTimeout(ctx, helmChartPullTimeout)
+	defer cancel()
+
+	ref := strings.TrimPrefix(source.OCI, helmChartOCIScheme)
+	credentials, credentialsVersion, err := r.getHelmRegistryCredentials(ctx, crs, ref, source.CredentialsSecretRef)
+	if err != nil {
+		return nil, err
+	}
+
+	// NOTE: The resolved digest is cached per credentials, so the access to the chart is checked again when credentials change.
+	resolveKey := fmt.Sprintf("%s:%s/%s", ref, source.Version, credentialsVersion)
+	digest, ok := r.helmCharts.getDigest(resolveKey)
+	if !ok {
+		digest, err = r.HelmChartRenderer.Resolve(ctx, ref, source.Version, credentials)
+		if err != nil {
+			return nil, err
+		}
+		r.helmCharts.setDigest(resolveKey, digest)
+	}
+
+	if archive, ok := r.helmCharts.getArchive(digest); ok {
+		return archive, nil
+	}
+	archive, err := r.HelmChartRenderer.Fetch(ctx, ref, digest, credentials)
+	if err != nil {
+		return nil, err
+	}
+	r.helmCharts.setArchive(digest, archive)
+	return archive, nil
+}
+
+// getHelmRegistryCredentials returns the credentials used to pull a Helm chart from an OCI registry, and a string
+// identifying the version of the credentials; credentials are nil if no Secret is referenced.
+func (r *ClusterResourceSetReconciler) getHelmRegistryCredentials(ctx context.Context, crs *addonsv1.ClusterResourceSet, ref string, secretRef *corev1.LocalObjectReference) (*HelmRegistryCredentials, string, error) {
+	if secretRef == nil {
+		return nil, "", nil
+	}
+
+	secretKey := types.NamespacedName{Namespace: crs.Namespace, Name: secretRef.Name}
+	secret, err := getSecret(ctx, r.Client, secretKey)
+	if err != nil {
+		return nil, "", errors.Wrapf(err, "failed to get credentials Secret %s", secretKey)
+	}
+	credentialsVersion := fmt.Sprintf("%s/%s", secretKey, secret.ResourceVersion)
+
+	if secret.Type == corev1.SecretTypeDockerConfigJson {
+		host, _, _ := strings.Cut(ref, "/")
+		credentials, err := dockerConfigCredentials(secret.Data[corev1.DockerConfigJsonKey], host)
+		if err != nil {
+			return nil, "", errors.Wrapf(err, "failed to get credentials from Secret %s", secretKey)
+		}
+		return credentials, credentialsVersion, nil
+	}
+
+	username, password := secret.Data[corev1.BasicAuthUsernameKey], secret.Data[corev1.BasicAuthPasswordKey]
+	if len(username) == 0 || len(password) == 0 {
+		return nil, "", errors.Errorf("failed to get credentials from Secret %s: Secret must be of type %s or contain the %s and %s keys",
+			secretKey, corev1.SecretTypeDockerConfigJson, corev1.BasicAuthUsernameKey, corev1.BasicAuthPasswordKey)
+	}
+	return &HelmRegistryCredentials{Username: string(username), Password: string(password)}, credentialsVersion, nil
+}
+
+// dockerConfigCredentials returns the credentials for a registry host from a .dockerconfigjson document.
+func dockerConfigCredentials(data []byte, host string) (*HelmRegistryCredentials, error) {
+	config := struct {
+		Auths map[string]struct {
+			Username string `json:"username,omitempty"`
+			Password string `json:"password,omitempty"`
+			Auth     string `json:"auth,omitempty"`
+		} `json:"auths"`
+	}{}
+	if err := json.Unmarshal(data, &config); err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s", corev1.DockerConfigJsonKey)
+	}
+
+	for registry, entry := range config.Auths {
+		// Registries can be defined with or without a scheme, e.g. https://registry.example.com.
+		registry = strings.TrimPrefix(strings.TrimPrefix(registry, "https://"), "http://")
+		registry, _, _ = strings.Cut(registry, "/")
+		if registry != host {
+			continue
+		}
+		if entry.Auth != "" {
+			auth, err := base64.StdEncoding.DecodeString(entry.Auth)
+			if err != nil {
+				return nil, errors.Wrapf(err, "failed to decode auth for registry %s", host)
+			}
+			username, password, ok := strings.Cut(string(auth), ":")
+			if !ok {
+				return nil, errors.Errorf("invalid auth for registry %s", host)
+			}
+			return &HelmRegistryCredentials{Username: username, Password: password}, nil
+		}
+		return &HelmRegistryCredentials{Username: entry.Username, Password: entry.Password}, nil
+	}
+	return nil, errors.Errorf("no credentials for registry %s", host)
+}
+
+// helmChartCache caches the digests Helm chart versions are resolved to, and the charts pulled from OCI registries by digest.
+// Both caches are bounded; the zero value is ready to use.
+type helmChartCache struct {
+	once     sync.Once
+	digests  *cache.LRUExpireCache
+	archives *cache.LRUExpireCache
+}
+
+func (c *helmChartCache) init() {
+	c.once.Do(func() {
+		c.digests = cache.NewLRUExpireCache(helmChartCacheSize)
+		c.archives = cache.NewLRUExpireCache(helmChartCacheSize)
+	})
+}
+
+func (c *helmChartCache) getDigest(key string) (string, bool) {
+	c.init()
+	digest, ok := c.digests.Get(key)
+	if !ok {
+		return "", false
+	}
+	return digest.(string), true
+}
+
+func (c *helmChartCache) setDigest(key, digest string) {
+	c.init()
+	c.digests.Add(key, digest, helmChartResolveTTL)
+}
+
+func (c *helmChartCache) getArchive(digest string) ([]byte, bool) {
+	c.init()
+	archive, ok := c.archives.Get(digest)
+	if !ok {
+		return nil, false
+	}
+	return archive.([]byte), true
+}
+
+func (c *helmChartCache) setArchive(digest string, archive []byte) {
+	c.init()
+	c.archives.Add(digest, archive, helmChartArchiveTTL)
+}
+
+// helmChartRenderOptions returns the options used to render a Helm chart for a Cluster.
+// NOTE: The Kubernetes version is known only for Clusters with a managed topology.
+func helmChartRenderOptions(source *addonsv1.HelmChartSource, cluster *clusterv1.Cluster) HelmChartRenderOptions {
+	options := HelmChartRenderOptions{
+		ReleaseName:      source.ReleaseName,
+		ReleaseNamespace: source.ReleaseNamespace,
+	}
+	if options.ReleaseName == "" {
+		options.ReleaseName = source.Name
+	}
+	if options.ReleaseNamespace == "" {
+		options.ReleaseNamespace = defaultHelmChartReleaseNamespace
+	}
+	if cluster != nil && cluster.Spec.Topology != nil {
+		options.KubernetesVersion = cluster.Spec.Topology.Version
+	}
+	return options
+}
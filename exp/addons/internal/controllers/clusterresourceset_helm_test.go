@@ -0,0 +1,256 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+// fakeHelmChartRenderer records the charts it renders and pulls, and returns a manifest with the rendered values.
+type fakeHelmChartRenderer struct {
+	archive     []byte
+	values      []byte
+	options     HelmChartRenderOptions
+	resolved    []string
+	fetched     []string
+	credentials *HelmRegistryCredentials
+}
+
+func (f *fakeHelmChartRenderer) Render(_ context.Context, archive, values []byte, options HelmChartRenderOptions) ([][]byte, error) {
+	f.archive = archive
+	f.values = values
+	f.options = options
+	return [][]byte{values}, nil
+}
+
+func (f *fakeHelmChartRenderer) Resolve(ctx context.Context, ref, version string, credentials *HelmRegistryCredentials) (string, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return "", errors.New("resolving charts requires a timeout")
+	}
+	if ref == "registry.example.com/charts/missing" {
+		return "", errors.New("not found")
+	}
+	f.resolved = append(f.resolved, ref+":"+version)
+	f.credentials = credentials
+	return "sha256:" + version, nil
+}
+
+func (f *fakeHelmChartRenderer) Fetch(ctx context.Context, ref, digest string, _ *HelmRegistryCredentials) ([]byte, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		return nil, errors.New("fetching charts requires a timeout")
+	}
+	f.fetched = append(f.fetched, ref+"@"+digest)
+	return []byte("pulled"), nil
+}
+
+func TestRenderHelmChart(t *testing.T) {
+	g := NewWithT(t)
+
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-chart",
+			Namespace: metav1.NamespaceDefault,
+		},
+		BinaryData: map[string][]byte{
+			defaultHelmChartKey: []byte("archive"),
+		},
+	}
+	crs := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crs",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	source := &addonsv1.HelmChartSource{
+		Name:     "test",
+		ChartRef: &addonsv1.SourceRef{Name: "test-chart", Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)},
+		Values:   "replicas: 2\nextra: ${CLUSTER_NAME}",
+	}
+
+	// Fails if no renderer is configured.
+	r := &ClusterResourceSetReconciler{
+		Client: fake.NewClientBuilder().WithObjects(configMap).Build(),
+	}
+	_, err := r.renderHelmChart(ctx, crs, cluster, source)
+	g.Expect(errors.Is(err, ErrHelmChartRendererNotConfigured)).To(BeTrue())
+
+	renderer := &fakeHelmChartRenderer{}
+	r.HelmChartRenderer = renderer
+	data, err := r.renderHelmChart(ctx, crs, cluster, source)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(data).To(HaveLen(1))
+	g.Expect(string(renderer.archive)).To(Equal("archive"))
+	g.Expect(string(renderer.values)).To(Equal("replicas: 2\nextra: cluster1"))
+	g.Expect(renderer.options).To(Equal(HelmChartRenderOptions{ReleaseName: "test", ReleaseNamespace: "default"}))
+
+	// Fails if the chart is not found in the ConfigMap.
+	source.ChartKey = "other.tgz"
+	_, err = r.renderHelmChart(ctx, crs, cluster, source)
+	g.Expect(err).To(HaveOccurred())
+
+	// Charts from OCI registries are pulled without the oci:// scheme.
+	source.ChartRef = nil
+	source.OCI = "oci://registry.example.com/charts/test"
+	source.Version = "1.0.0"
+	_, err = r.renderHelmChart(ctx, crs, cluster, source)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(renderer.resolved).To(ConsistOf("registry.example.com/charts/test:1.0.0"))
+	g.Expect(renderer.fetched).To(ConsistOf("registry.example.com/charts/test@sha256:1.0.0"))
+	g.Expect(string(renderer.archive)).To(Equal("pulled"))
+
+	source.OCI = "oci://registry.example.com/charts/missing"
+	_, err = r.renderHelmChart(ctx, crs, cluster, source)
+	g.Expect(err).To(HaveOccurred())
+}
+
+func TestPullHelmChart(t *testing.T) {
+	crs := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crs",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	basicAuthSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "basic-auth",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Type: corev1.SecretTypeBasicAuth,
+		Data: map[string][]byte{
+			corev1.BasicAuthUsernameKey: []byte("user"),
+			corev1.BasicAuthPasswordKey: []byte("password"),
+		},
+	}
+	dockerConfigSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "docker-config",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Type: corev1.SecretTypeDockerConfigJson,
+		Data: map[string][]byte{
+			corev1.DockerConfigJsonKey: []byte(`{"auths":{"other.example.com":{"auth":"b3RoZXI6b3RoZXI="},"https://registry.example.com":{"auth":"ZG9ja2VyOnNlY3JldA=="}}}`),
+		},
+	}
+	invalidSecret := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "invalid",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string][]byte{
+			"token": []byte("token"),
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(basicAuthSecret, dockerConfigSecret, invalidSecret).Build()
+
+	t.Run("charts are pulled once per digest, including charts without a version", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &fakeHelmChartRenderer{}
+		r := &ClusterResourceSetReconciler{Client: c, HelmChartRenderer: renderer}
+
+		source := &addonsv1.HelmChartSource{Name: "test", OCI: "oci://registry.example.com/charts/test"}
+		for i := 0; i < 3; i++ {
+			archive, err := r.pullHelmChart(ctx, crs, source)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(string(archive)).To(Equal("pulled"))
+		}
+		g.Expect(renderer.resolved).To(HaveLen(1))
+		g.Expect(renderer.fetched).To(HaveLen(1))
+		g.Expect(renderer.credentials).To(BeNil())
+
+		// A different version resolving to a new digest is pulled.
+		source.Version = "1.0.0"
+		_, err := r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.resolved).To(HaveLen(2))
+		g.Expect(renderer.fetched).To(HaveLen(2))
+	})
+
+	t.Run("charts are pulled with the credentials from a basic-auth Secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &fakeHelmChartRenderer{}
+		r := &ClusterResourceSetReconciler{Client: c, HelmChartRenderer: renderer}
+
+		source := &addonsv1.HelmChartSource{Name: "test", OCI: "oci://registry.example.com/charts/test", CredentialsSecretRef: &corev1.LocalObjectReference{Name: "basic-auth"}}
+		_, err := r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.credentials).To(Equal(&HelmRegistryCredentials{Username: "user", Password: "password"}))
+	})
+
+	t.Run("charts are pulled with the credentials from a dockerconfigjson Secret", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &fakeHelmChartRenderer{}
+		r := &ClusterResourceSetReconciler{Client: c, HelmChartRenderer: renderer}
+
+		source := &addonsv1.HelmChartSource{Name: "test", OCI: "oci://registry.example.com/charts/test", CredentialsSecretRef: &corev1.LocalObjectReference{Name: "docker-config"}}
+		_, err := r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(renderer.credentials).To(Equal(&HelmRegistryCredentials{Username: "docker", Password: "secret"}))
+
+		// Fails if there are no credentials for the registry.
+		source.OCI = "oci://unknown.example.com/charts/test"
+		_, err = r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).To(HaveOccurred())
+	})
+
+	t.Run("fails if the credentials Secret is invalid or missing", func(t *testing.T) {
+		g := NewWithT(t)
+
+		renderer := &fakeHelmChartRenderer{}
+		r := &ClusterResourceSetReconciler{Client: c, HelmChartRenderer: renderer}
+
+		source := &addonsv1.HelmChartSource{Name: "test", OCI: "oci://registry.example.com/charts/test", CredentialsSecretRef: &corev1.LocalObjectReference{Name: "invalid"}}
+		_, err := r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).To(HaveOccurred())
+
+		source.CredentialsSecretRef.Name = "missing"
+		_, err = r.pullHelmChart(ctx, crs, source)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(renderer.resolved).To(BeEmpty())
+	})
+}
+
+func TestHelmChartRenderOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	options := helmChartRenderOptions(&addonsv1.HelmChartSource{Name: "test"}, &clusterv1.Cluster{})
+	g.Expect(options).To(Equal(HelmChartRenderOptions{ReleaseName: "test", ReleaseNamespace: defaultHelmChartReleaseNamespace}))
+
+	options = helmChartRenderOptions(
+		&addonsv1.HelmChartSource{Name: "test", ReleaseName: "release", ReleaseNamespace: "kube-system"},
+		&clusterv1.Cluster{Spec: clusterv1.ClusterSpec{Topology: &clusterv1.Topology{Version: "v1.30.1"}}},
+	)
+	g.Expect(options).To(Equal(HelmChartRenderOptions{ReleaseName: "release", ReleaseNamespace: "kube-system", KubernetesVersion: "v1.30.1"}))
+}
@@ -254,3 +254,62 @@ func ensureKubernetesServiceCreated(ctx context.Context, client client.Client) e
 	}
 	return nil
 }
+
+// getSourceFiles returns the content of a Secret or ConfigMap referenced by a Helm chart or Kustomization source,
+// indexed by key; both data and binaryData of ConfigMaps are returned.
+func (r *ClusterResourceSetReconciler) getSourceFiles(ctx context.Context, crs *addonsv1.ClusterResourceSet, sourceRef addonsv1.SourceRef) (map[string][]byte, error) {
+	sourceName := types.NamespacedName{Name: sourceRef.Name, Namespace: crs.Namespace}
+
+	files := map[string][]byte{}
+	switch sourceRef.Kind {
+	case string(addonsv1.ConfigMapClusterResourceSetResourceKind):
+		configMap, err := getConfigMap(ctx, r.Client, sourceName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get ConfigMap %s", sourceName)
+		}
+		for key, value := range configMap.Data {
+			files[key] = []byte(value)
+		}
+		for key, value := range configMap.BinaryData {
+			files[key] = value
+		}
+	case string(addonsv1.SecretClusterResourceSetResourceKind):
+		secret, err := getSecret(ctx, r.Client, sourceName)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get Secret %s", sourceName)
+		}
+		if secret.Type != addonsv1.ClusterResourceSetSecretType {
+			return nil, ErrSecretTypeNotSupported
+		}
+		for key, value := range secret.Data {
+			files[key] = value
+		}
+	default:
+		return nil, errors.Errorf("unsupported source kind %q", sourceRef.Kind)
+	}
+	return files, nil
+}
+
+// sourceRefsForResource returns the Secrets and ConfigMaps referenced by a Helm chart or Kustomization resource.
+func sourceRefsForResource(crs *addonsv1.ClusterResourceSet, resourceRef addonsv1.ResourceRef) []addonsv1.SourceRef {
+	sourceRefs := []addonsv1.SourceRef{}
+	switch resourceRef.Kind {
+	case string(addonsv1.HelmChartClusterResourceSetResourceKind):
+		if source := crs.Spec.GetHelmChart(resourceRef.Name); source != nil && source.ChartRef != nil {
+			sourceRefs = append(sourceRefs, *source.ChartRef)
+		}
+	case string(addonsv1.KustomizationClusterResourceSetResourceKind):
+		if source := crs.Spec.GetKustomization(resourceRef.Name); source != nil {
+			for _, files := range source.Files {
+				sourceRefs = append(sourceRefs, files.SourceRef)
+			}
+		}
+	}
+	return sourceRefs
+}
+
+// isRenderedResource returns true if the resource is rendered for each Cluster, i.e. it is a Helm chart or a Kustomization.
+func isRenderedResource(resourceRef addonsv1.ResourceRef) bool {
+	return resourceRef.Kind == string(addonsv1.HelmChartClusterResourceSetResourceKind) ||
+		resourceRef.Kind == string(addonsv1.KustomizationClusterResourceSetResourceKind)
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+
+	"github.com/pkg/errors"
+	"sigs.k8s.io/kustomize/api/krusty"
+	"sigs.k8s.io/kustomize/kyaml/filesys"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+const kustomizationRoot = "/"
+
+// renderKustomization renders a Kustomize overlay for a Cluster, and returns the rendered manifests.
+// References to cluster variables are substituted in the rendered manifests.
+func (r *ClusterResourceSetReconciler) renderKustomization(ctx context.Context, crs *addonsv1.ClusterResourceSet, cluster *clusterv1.Cluster, source *addonsv1.KustomizationSource) ([][]byte, error) {
+	fSys := filesys.MakeFsInMemory()
+	for _, files := range source.Files {
+		dir, err := kustomizationFilesPath(files.Path)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to render Kustomization %s", source.Name)
+		}
+
+		sourceFiles, err := r.getSourceFiles(ctx, crs, files.SourceRef)
+		if err != nil {
+			return nil, err
+		}
+		if err := fSys.MkdirAll(dir); err != nil {
+			return nil, errors.Wrapf(err, "failed to render Kustomization %s", source.Name)
+		}
+		for name, content := range sourceFiles {
+			if err := fSys.WriteFile(filepath.Join(dir, name), content); err != nil {
+				return nil, errors.Wrapf(err, "failed to render Kustomization %s", source.Name)
+			}
+		}
+	}
+
+	resources, err := krusty.MakeKustomizer(krusty.MakeDefaultOptions()).Run(fSys, kustomizationRoot)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Kustomization %s", source.Name)
+	}
+	data, err := resources.AsYaml()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to render Kustomization %s", source.Name)
+	}
+
+	return [][]byte{substituteVariables(data, clusterVariables(cluster))}, nil
+}
+
+// kustomizationFilesPath returns the absolute path of a directory in the Kustomize directory tree.
+func kustomizationFilesPath(path string) (string, error) {
+	dir := filepath.Join(kustomizationRoot, path)
+	if path != "" && (filepath.IsAbs(path) || strings.HasPrefix(filepath.Clean(path), "..")) {
+		return "", errors.Errorf("path %q must be relative to the root of the directory tree", path)
+	}
+	return dir, nil
+}
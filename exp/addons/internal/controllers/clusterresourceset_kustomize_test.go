@@ -0,0 +1,112 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestRenderKustomization(t *testing.T) {
+	base := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "base",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Data: map[string]string{
+			"kustomization.yaml": "resources:\n- configmap.yaml\n",
+			"configmap.yaml":     "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: config\n  namespace: kube-system\ndata:\n  cluster: ${CLUSTER_NAME}\n",
+		},
+	}
+	overlay := &corev1.Secret{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "overlay",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Type: addonsv1.ClusterResourceSetSecretType,
+		Data: map[string][]byte{
+			"kustomization.yaml": []byte("resources:\n- base\nnamePrefix: overlay-\n"),
+		},
+	}
+	crs := &addonsv1.ClusterResourceSet{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "crs",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+
+	tests := []struct {
+		name    string
+		files   []addonsv1.KustomizationFiles
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "should render an overlay with a base in a sub directory",
+			files: []addonsv1.KustomizationFiles{
+				{SourceRef: addonsv1.SourceRef{Name: "overlay", Kind: string(addonsv1.SecretClusterResourceSetResourceKind)}},
+				{SourceRef: addonsv1.SourceRef{Name: "base", Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)}, Path: "base"},
+			},
+			want: "apiVersion: v1\ndata:\n  cluster: cluster1\nkind: ConfigMap\nmetadata:\n  name: overlay-config\n  namespace: kube-system\n",
+		},
+		{
+			name: "should return error for a path outside of the directory tree",
+			files: []addonsv1.KustomizationFiles{
+				{SourceRef: addonsv1.SourceRef{Name: "base", Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)}, Path: "../base"},
+			},
+			wantErr: true,
+		},
+		{
+			name: "should return error when a source does not exist",
+			files: []addonsv1.KustomizationFiles{
+				{SourceRef: addonsv1.SourceRef{Name: "missing", Kind: string(addonsv1.ConfigMapClusterResourceSetResourceKind)}},
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r := &ClusterResourceSetReconciler{
+				Client: fake.NewClientBuilder().WithObjects(base.DeepCopy(), overlay.DeepCopy()).Build(),
+			}
+			data, err := r.renderKustomization(ctx, crs, cluster, &addonsv1.KustomizationSource{Name: "test", Files: tt.files})
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(data).To(HaveLen(1))
+			g.Expect(string(data[0])).To(Equal(tt.want))
+		})
+	}
+}
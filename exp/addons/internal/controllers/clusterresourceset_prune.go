@@ -0,0 +1,369 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+)
+
+// objectKey identifies an object in a cluster independently of its API version.
+type objectKey struct {
+	groupKind schema.GroupKind
+	namespace string
+	name      string
+}
+
+func objectKeyFromReference(ref addonsv1.AppliedObjectReference) objectKey {
+	return objectKey{
+		groupKind: schema.FromAPIVersionAndKind(ref.APIVersion, ref.Kind).GroupKind(),
+		namespace: ref.Namespace,
+		name:      ref.Name,
+	}
+}
+
+// desiredObjects collects the objects defined by the resources of a ClusterResourceSet for a Cluster.
+type desiredObjects struct {
+	// resources are the resources which have been successfully retrieved or rendered.
+	resources map[addonsv1.ResourceRef]bool
+	objects   map[objectKey]bool
+}
+
+func newDesiredObjects() *desiredObjects {
+	return &desiredObjects{
+		resources: map[addonsv1.ResourceRef]bool{},
+		objects:   map[objectKey]bool{},
+	}
+}
+
+func (d *desiredObjects) add(resource addonsv1.ResourceRef, objs []unstructured.Unstructured) {
+	d.resources[resource] = true
+	for _, ref := range appliedObjectReferences(objs) {
+		d.objects[objectKeyFromReference(ref)] = true
+	}
+}
+
+// appliedObjectReferences returns the references to the given objects.
+func appliedObjectReferences(objs []unstructured.Unstructured) []addonsv1.AppliedObjectReference {
+	refs := make([]addonsv1.AppliedObjectReference, 0, len(objs))
+	for i := range objs {
+		refs = append(refs, addonsv1.AppliedObjectReference{
+			APIVersion: objs[i].GetAPIVersion(),
+			Kind:       objs[i].GetKind(),
+			Namespace:  objs[i].GetNamespace(),
+			Name:       objs[i].GetName(),
+		})
+	}
+	return refs
+}
+
+// trackedObjects returns the objects tracked in the ResourceSetBinding for a resource.
+func trackedObjects(resourceSetBinding *addonsv1.ResourceSetBinding, resource addonsv1.ResourceRef) []addonsv1.AppliedObjectReference {
+	resourceBinding := resourceSetBinding.GetResource(resource)
+	if resourceBinding == nil {
+		return nil
+	}
+	return resourceBinding.Objects
+}
+
+// trackObjects returns the objects to be tracked for a resource after applying objs.
+// When prune is enabled, or in dry-run mode, previously applied objects are tracked until they are pruned.
+func trackObjects(clusterResourceSet *addonsv1.ClusterResourceSet, previous []addonsv1.AppliedObjectReference, objs []unstructured.Unstructured) []addonsv1.AppliedObjectReference {
+	tracked := appliedObjectReferences(objs)
+	if clusterResourceSet.Spec.GetTypedPrune() == addonsv1.ClusterResourceSetPruneDisabled {
+		return tracked
+	}
+
+	keys := map[objectKey]bool{}
+	for _, ref := range tracked {
+		keys[objectKeyFromReference(ref)] = true
+	}
+	for _, ref := range previous {
+		if !keys[objectKeyFromReference(ref)] {
+			tracked = append(tracked, ref)
+		}
+	}
+	return tracked
+}
+
+// resourcePruneCandidates are the objects applied from a resource which are no longer part of the ClusterResourceSet.
+type resourcePruneCandidates struct {
+	resourceRef addonsv1.ResourceRef
+	// removed is true if the resource is no longer part of the ClusterResourceSet.
+	removed bool
+	objects []addonsv1.AppliedObjectReference
+}
+
+// pruneCandidates returns the objects tracked in a ResourceSetBinding which are no longer part of the ClusterResourceSet.
+// Objects of resources which could not be retrieved or rendered, and objects applied by other ClusterResourceSets
+// to the same Cluster, are never pruned.
+func pruneCandidates(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, resourceSetBinding *addonsv1.ResourceSetBinding, resources []addonsv1.ResourceRef, desired *desiredObjects) []resourcePruneCandidates {
+	inSpec := map[addonsv1.ResourceRef]bool{}
+	for _, resource := range resources {
+		inSpec[resource] = true
+	}
+
+	keep := map[objectKey]bool{}
+	for key := range desired.objects {
+		keep[key] = true
+	}
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding == nil || binding.ClusterResourceSetName == resourceSetBinding.ClusterResourceSetName {
+			continue
+		}
+		for _, resourceBinding := range binding.Resources {
+			for _, ref := range resourceBinding.Objects {
+				keep[objectKeyFromReference(ref)] = true
+			}
+		}
+	}
+	for _, resourceBinding := range resourceSetBinding.Resources {
+		if inSpec[resourceBinding.ResourceRef] && !desired.resources[resourceBinding.ResourceRef] {
+			for _, ref := range resourceBinding.Objects {
+				keep[objectKeyFromReference(ref)] = true
+			}
+		}
+	}
+
+	candidates := []resourcePruneCandidates{}
+	for _, resourceBinding := range resourceSetBinding.Resources {
+		resourceCandidates := resourcePruneCandidates{
+			resourceRef: resourceBinding.ResourceRef,
+			removed:     !inSpec[resourceBinding.ResourceRef],
+		}
+		for _, ref := range resourceBinding.Objects {
+			if !keep[objectKeyFromReference(ref)] {
+				resourceCandidates.objects = append(resourceCandidates.objects, ref)
+			}
+		}
+		if resourceCandidates.removed || len(resourceCandidates.objects) > 0 {
+			candidates = append(candidates, resourceCandidates)
+		}
+	}
+	return candidates
+}
+
+// reconcilePrune deletes from a Cluster the objects which are no longer part of the ClusterResourceSet when prune is enabled,
+// or lists them in the ClusterResourceSet status in dry-run mode.
+func (r *ClusterResourceSetReconciler) reconcilePrune(ctx context.Context, remoteClient client.Client, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, resourceSetBinding *addonsv1.ResourceSetBinding, desired *desiredObjects) error {
+	prune := clusterResourceSet.Spec.GetTypedPrune()
+	if prune == addonsv1.ClusterResourceSetPruneDisabled {
+		return nil
+	}
+
+	candidates := pruneCandidates(clusterResourceSetBinding, resourceSetBinding, clusterResourceSet.Spec.Resources, desired)
+	if prune == addonsv1.ClusterResourceSetPruneDryRun {
+		setPruneCandidates(clusterResourceSet, cluster, candidates)
+		return nil
+	}
+
+	if err := pruneResources(ctx, remoteClient, resourceSetBinding, candidates); err != nil {
+		conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return err
+	}
+	return nil
+}
+
+// reconcilePruneUnmatchedClusters deletes the objects applied by the ClusterResourceSet from Clusters which are no longer
+// matched by the ClusterResourceSet when prune is enabled, or lists them in the ClusterResourceSet status in dry-run mode.
+// Once all the objects are deleted from a Cluster, the ClusterResourceSet is removed from the ClusterResourceSetBinding.
+func (r *ClusterResourceSetReconciler) reconcilePruneUnmatchedClusters(ctx context.Context, clusters []*clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet) error {
+	prune := clusterResourceSet.Spec.GetTypedPrune()
+	if prune == addonsv1.ClusterResourceSetPruneDisabled {
+		return nil
+	}
+
+	matched := map[string]bool{}
+	for _, cluster := range clusters {
+		matched[cluster.Name] = true
+	}
+
+	bindings := &addonsv1.ClusterResourceSetBindingList{}
+	if err := r.Client.List(ctx, bindings, client.InNamespace(clusterResourceSet.Namespace)); err != nil {
+		return errors.Wrap(err, "failed to list ClusterResourceSetBindings")
+	}
+
+	errList := []error{}
+	for i := range bindings.Items {
+		clusterResourceSetBinding := &bindings.Items[i]
+		resourceSetBinding := getResourceSetBinding(clusterResourceSetBinding, clusterResourceSet)
+		if resourceSetBinding == nil {
+			continue
+		}
+
+		clusterName := clusterResourceSetBinding.Spec.ClusterName
+		if clusterName == "" {
+			clusterName = clusterResourceSetBinding.Name
+		}
+		if matched[clusterName] {
+			continue
+		}
+
+		// Objects are not pruned from Clusters being deleted.
+		cluster := &clusterv1.Cluster{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: clusterResourceSetBinding.Namespace, Name: clusterName}, cluster); err != nil {
+			if !apierrors.IsNotFound(err) {
+				errList = append(errList, errors.Wrapf(err, "failed to get Cluster %s", clusterName))
+			}
+			continue
+		}
+		if !cluster.DeletionTimestamp.IsZero() {
+			continue
+		}
+
+		if prune == addonsv1.ClusterResourceSetPruneDryRun {
+			setPruneCandidates(clusterResourceSet, cluster, pruneCandidates(clusterResourceSetBinding, resourceSetBinding, nil, newDesiredObjects()))
+			continue
+		}
+
+		patchHelper, err := patch.NewHelper(clusterResourceSetBinding, r.Client)
+		if err != nil {
+			errList = append(errList, err)
+			continue
+		}
+		if err := r.pruneClusterResourceSetBinding(ctx, cluster, clusterResourceSet, clusterResourceSetBinding); err != nil {
+			conditions.MarkFalse(clusterResourceSet, addonsv1.ResourcesAppliedCondition, addonsv1.PruneFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			errList = append(errList, err)
+			continue
+		}
+		if err := r.removeClusterResourceSetBinding(ctx, clusterResourceSet, clusterResourceSetBinding, patchHelper); err != nil {
+			errList = append(errList, err)
+		}
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// pruneClusterResourceSetBinding deletes from a Cluster all the objects applied by the ClusterResourceSet,
+// except objects also applied by other ClusterResourceSets.
+func (r *ClusterResourceSetReconciler) pruneClusterResourceSetBinding(ctx context.Context, cluster *clusterv1.Cluster, clusterResourceSet *addonsv1.ClusterResourceSet, clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding) error {
+	resourceSetBinding := getResourceSetBinding(clusterResourceSetBinding, clusterResourceSet)
+	if resourceSetBinding == nil {
+		return nil
+	}
+
+	candidates := pruneCandidates(clusterResourceSetBinding, resourceSetBinding, nil, newDesiredObjects())
+	if len(candidates) == 0 {
+		return nil
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return err
+	}
+	return pruneResources(ctx, remoteClient, resourceSetBinding, candidates)
+}
+
+// pruneResources deletes the prune candidates from a Cluster, and removes the deleted objects from the ResourceSetBinding.
+// Resources which are no longer part of the ClusterResourceSet are removed from the ResourceSetBinding once all
+// their objects are deleted.
+func pruneResources(ctx context.Context, remoteClient client.Client, resourceSetBinding *addonsv1.ResourceSetBinding, candidates []resourcePruneCandidates) error {
+	errList := []error{}
+	for _, resourceCandidates := range candidates {
+		failed, err := pruneObjects(ctx, remoteClient, resourceCandidates.objects)
+		if err != nil {
+			errList = append(errList, err)
+		}
+
+		if resourceCandidates.removed {
+			if len(failed) == 0 {
+				resourceSetBinding.RemoveResource(resourceCandidates.resourceRef)
+				continue
+			}
+			resourceBinding := resourceSetBinding.GetResource(resourceCandidates.resourceRef)
+			resourceBinding.Objects = failed
+			resourceSetBinding.SetBinding(*resourceBinding)
+			continue
+		}
+
+		pruned := map[objectKey]bool{}
+		for _, ref := range resourceCandidates.objects {
+			pruned[objectKeyFromReference(ref)] = true
+		}
+		for _, ref := range failed {
+			delete(pruned, objectKeyFromReference(ref))
+		}
+		resourceBinding := resourceSetBinding.GetResource(resourceCandidates.resourceRef)
+		objects := []addonsv1.AppliedObjectReference{}
+		for _, ref := range resourceBinding.Objects {
+			if !pruned[objectKeyFromReference(ref)] {
+				objects = append(objects, ref)
+			}
+		}
+		resourceBinding.Objects = objects
+		resourceSetBinding.SetBinding(*resourceBinding)
+	}
+	return kerrors.NewAggregate(errList)
+}
+
+// pruneObjects deletes objects from a Cluster in reverse order, and returns the objects which failed to be deleted.
+func pruneObjects(ctx context.Context, remoteClient client.Client, refs []addonsv1.AppliedObjectReference) ([]addonsv1.AppliedObjectReference, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	failed := []addonsv1.AppliedObjectReference{}
+	errList := []error{}
+	for i := len(refs) - 1; i >= 0; i-- {
+		obj := &unstructured.Unstructured{}
+		obj.SetAPIVersion(refs[i].APIVersion)
+		obj.SetKind(refs[i].Kind)
+		obj.SetNamespace(refs[i].Namespace)
+		obj.SetName(refs[i].Name)
+
+		log.Info("Pruning object no longer part of the ClusterResourceSet", "object", refs[i].String())
+		if err := remoteClient.Delete(ctx, obj, client.PropagationPolicy(metav1.DeletePropagationBackground)); err != nil && !apierrors.IsNotFound(err) {
+			failed = append([]addonsv1.AppliedObjectReference{refs[i]}, failed...)
+			errList = append(errList, errors.Wrapf(err, "failed to delete %s %s", obj.GroupVersionKind(), klog.KObj(obj)))
+		}
+	}
+	return failed, kerrors.NewAggregate(errList)
+}
+
+// setPruneCandidates adds the prune candidates for a Cluster to the ClusterResourceSet status.
+func setPruneCandidates(clusterResourceSet *addonsv1.ClusterResourceSet, cluster *clusterv1.Cluster, candidates []resourcePruneCandidates) {
+	for _, resourceCandidates := range candidates {
+		for _, ref := range resourceCandidates.objects {
+			clusterResourceSet.Status.PruneCandidates = append(clusterResourceSet.Status.PruneCandidates, addonsv1.PruneCandidate{
+				ClusterName:            cluster.Name,
+				AppliedObjectReference: ref,
+			})
+		}
+	}
+}
+
+// getResourceSetBinding returns the ResourceSetBinding for a ClusterResourceSet, if any.
+func getResourceSetBinding(clusterResourceSetBinding *addonsv1.ClusterResourceSetBinding, clusterResourceSet *addonsv1.ClusterResourceSet) *addonsv1.ResourceSetBinding {
+	for _, binding := range clusterResourceSetBinding.Spec.Bindings {
+		if binding != nil && binding.ClusterResourceSetName == clusterResourceSet.Name {
+			return binding
+		}
+	}
+	return nil
+}
@@ -0,0 +1,186 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func newTestObject(apiVersion, kind, namespace, name string) unstructured.Unstructured {
+	obj := unstructured.Unstructured{}
+	obj.SetAPIVersion(apiVersion)
+	obj.SetKind(kind)
+	obj.SetNamespace(namespace)
+	obj.SetName(name)
+	return obj
+}
+
+func TestTrackObjects(t *testing.T) {
+	previous := []addonsv1.AppliedObjectReference{
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "kept"},
+		{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "dropped"},
+	}
+	objs := []unstructured.Unstructured{
+		newTestObject("v1", "ConfigMap", metav1.NamespaceDefault, "kept"),
+		newTestObject("v1", "ConfigMap", metav1.NamespaceDefault, "added"),
+	}
+
+	tests := []struct {
+		name  string
+		prune addonsv1.ClusterResourceSetPrunePolicy
+		want  []addonsv1.AppliedObjectReference
+	}{
+		{
+			name:  "should track only the applied objects when prune is disabled",
+			prune: addonsv1.ClusterResourceSetPruneDisabled,
+			want: []addonsv1.AppliedObjectReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "kept"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "added"},
+			},
+		},
+		{
+			name:  "should keep tracking previously applied objects when prune is enabled",
+			prune: addonsv1.ClusterResourceSetPruneEnabled,
+			want: []addonsv1.AppliedObjectReference{
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "kept"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "added"},
+				{APIVersion: "v1", Kind: "ConfigMap", Namespace: metav1.NamespaceDefault, Name: "dropped"},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			crs := &addonsv1.ClusterResourceSet{Spec: addonsv1.ClusterResourceSetSpec{Prune: string(tt.prune)}}
+			g.Expect(trackObjects(crs, previous, objs)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestPruneCandidates(t *testing.T) {
+	g := NewWithT(t)
+
+	kept := addonsv1.AppliedObjectReference{APIVersion: "apps/v1", Kind: "Deployment", Namespace: "kube-system", Name: "kept"}
+	dropped := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "dropped"}
+	removed := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "kube-system", Name: "removed"}
+	shared := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "Namespace", Name: "shared"}
+	notRetrieved := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "Secret", Namespace: "kube-system", Name: "not-retrieved"}
+
+	resourceSetBinding := &addonsv1.ResourceSetBinding{
+		ClusterResourceSetName: "crs1",
+		Resources: []addonsv1.ResourceBinding{
+			{ResourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{kept, dropped}},
+			{ResourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{removed, shared}},
+			{ResourceRef: addonsv1.ResourceRef{Name: "not-retrieved", Kind: "Secret"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{notRetrieved}},
+		},
+	}
+	clusterResourceSetBinding := &addonsv1.ClusterResourceSetBinding{
+		Spec: addonsv1.ClusterResourceSetBindingSpec{
+			Bindings: []*addonsv1.ResourceSetBinding{
+				resourceSetBinding,
+				{
+					ClusterResourceSetName: "crs2",
+					Resources: []addonsv1.ResourceBinding{
+						{ResourceRef: addonsv1.ResourceRef{Name: "other", Kind: "ConfigMap"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{shared}},
+					},
+				},
+			},
+		},
+	}
+	resources := []addonsv1.ResourceRef{
+		{Name: "current", Kind: "ConfigMap"},
+		{Name: "not-retrieved", Kind: "Secret"},
+	}
+
+	// Objects are compared independently of the API version.
+	desired := newDesiredObjects()
+	desired.add(resources[0], []unstructured.Unstructured{newTestObject("apps/v1beta2", "Deployment", "kube-system", "kept")})
+
+	g.Expect(pruneCandidates(clusterResourceSetBinding, resourceSetBinding, resources, desired)).To(Equal([]resourcePruneCandidates{
+		{resourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, objects: []addonsv1.AppliedObjectReference{dropped}},
+		{resourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, removed: true, objects: []addonsv1.AppliedObjectReference{removed}},
+	}))
+
+	// All the objects not applied by other ClusterResourceSets are candidates when the Cluster is no longer matched.
+	g.Expect(pruneCandidates(clusterResourceSetBinding, resourceSetBinding, nil, newDesiredObjects())).To(Equal([]resourcePruneCandidates{
+		{resourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, removed: true, objects: []addonsv1.AppliedObjectReference{kept, dropped}},
+		{resourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, removed: true, objects: []addonsv1.AppliedObjectReference{removed}},
+		{resourceRef: addonsv1.ResourceRef{Name: "not-retrieved", Kind: "Secret"}, removed: true, objects: []addonsv1.AppliedObjectReference{notRetrieved}},
+	}))
+}
+
+func TestPruneResources(t *testing.T) {
+	g := NewWithT(t)
+
+	current := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "current"}}
+	dropped := &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "dropped"}}
+	removed := &corev1.ServiceAccount{ObjectMeta: metav1.ObjectMeta{Namespace: "kube-system", Name: "removed"}}
+	remoteClient := fake.NewClientBuilder().WithObjects(current, dropped, removed).Build()
+
+	currentRef := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "current"}
+	droppedRef := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "dropped"}
+	removedRef := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "kube-system", Name: "removed"}
+	deletedRef := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ServiceAccount", Namespace: "kube-system", Name: "already-deleted"}
+
+	resourceSetBinding := &addonsv1.ResourceSetBinding{
+		ClusterResourceSetName: "crs1",
+		Resources: []addonsv1.ResourceBinding{
+			{ResourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{currentRef, droppedRef}},
+			{ResourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, Applied: true, Objects: []addonsv1.AppliedObjectReference{removedRef, deletedRef}},
+		},
+	}
+	candidates := []resourcePruneCandidates{
+		{resourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, objects: []addonsv1.AppliedObjectReference{droppedRef}},
+		{resourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, removed: true, objects: []addonsv1.AppliedObjectReference{removedRef, deletedRef}},
+	}
+
+	g.Expect(pruneResources(ctx, remoteClient, resourceSetBinding, candidates)).To(Succeed())
+
+	g.Expect(resourceSetBinding.Resources).To(HaveLen(1))
+	g.Expect(resourceSetBinding.Resources[0].Objects).To(Equal([]addonsv1.AppliedObjectReference{currentRef}))
+
+	g.Expect(remoteClient.Get(ctx, client.ObjectKeyFromObject(current), &corev1.ConfigMap{})).To(Succeed())
+	g.Expect(apierrors.IsNotFound(remoteClient.Get(ctx, client.ObjectKeyFromObject(dropped), &corev1.ConfigMap{}))).To(BeTrue())
+	g.Expect(apierrors.IsNotFound(remoteClient.Get(ctx, client.ObjectKeyFromObject(removed), &corev1.ServiceAccount{}))).To(BeTrue())
+}
+
+func TestSetPruneCandidates(t *testing.T) {
+	g := NewWithT(t)
+
+	ref := addonsv1.AppliedObjectReference{APIVersion: "v1", Kind: "ConfigMap", Namespace: "kube-system", Name: "dropped"}
+	crs := &addonsv1.ClusterResourceSet{}
+	cluster := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Name: "cluster1"}}
+
+	setPruneCandidates(crs, cluster, []resourcePruneCandidates{
+		{resourceRef: addonsv1.ResourceRef{Name: "removed", Kind: "ConfigMap"}, removed: true},
+		{resourceRef: addonsv1.ResourceRef{Name: "current", Kind: "ConfigMap"}, objects: []addonsv1.AppliedObjectReference{ref}},
+	})
+	g.Expect(crs.Status.PruneCandidates).To(Equal([]addonsv1.PruneCandidate{{ClusterName: "cluster1", AppliedObjectReference: ref}}))
+}
@@ -40,6 +40,8 @@ type resourceReconcileScope interface {
 	// hash returns a computed hash of the defined objects in the resource. It is consistent
 	// between runs.
 	hash() string
+	// objs returns the objects defined by the resource.
+	objs() []unstructured.Unstructured
 }
 
 func reconcileScopeForResource(
@@ -0,0 +1,93 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+// Builtin variables available when rendering Helm charts and Kustomizations.
+const (
+	clusterNameVariable              = "CLUSTER_NAME"
+	clusterNamespaceVariable         = "CLUSTER_NAMESPACE"
+	kubernetesVersionVariable        = "KUBERNETES_VERSION"
+	podCIDRVariable                  = "POD_CIDR"
+	serviceCIDRVariable              = "SERVICE_CIDR"
+	serviceDomainVariable            = "SERVICE_DOMAIN"
+	controlPlaneEndpointHostVariable = "CONTROL_PLANE_ENDPOINT_HOST"
+	controlPlaneEndpointPortVariable = "CONTROL_PLANE_ENDPOINT_PORT"
+)
+
+// variableReferenceRegex matches ${VARIABLE_NAME} references to cluster variables.
+var variableReferenceRegex = regexp.MustCompile(`\$\{([A-Za-z_][A-Za-z0-9_-]*)\}`)
+
+// clusterVariables returns the variables of a Cluster which can be referenced when rendering Helm charts and Kustomizations.
+// Variables include builtin variables, e.g. CLUSTER_NAME, and the Cluster topology variables; topology variables
+// with a string value are returned unquoted, while all the other values are returned as JSON.
+func clusterVariables(cluster *clusterv1.Cluster) map[string]string {
+	variables := map[string]string{
+		clusterNameVariable:      cluster.Name,
+		clusterNamespaceVariable: cluster.Namespace,
+	}
+
+	if cluster.Spec.ClusterNetwork != nil {
+		if cluster.Spec.ClusterNetwork.Pods != nil && len(cluster.Spec.ClusterNetwork.Pods.CIDRBlocks) > 0 {
+			variables[podCIDRVariable] = cluster.Spec.ClusterNetwork.Pods.CIDRBlocks[0]
+		}
+		if cluster.Spec.ClusterNetwork.Services != nil && len(cluster.Spec.ClusterNetwork.Services.CIDRBlocks) > 0 {
+			variables[serviceCIDRVariable] = cluster.Spec.ClusterNetwork.Services.CIDRBlocks[0]
+		}
+		if cluster.Spec.ClusterNetwork.ServiceDomain != "" {
+			variables[serviceDomainVariable] = cluster.Spec.ClusterNetwork.ServiceDomain
+		}
+	}
+
+	if cluster.Spec.ControlPlaneEndpoint.IsValid() {
+		variables[controlPlaneEndpointHostVariable] = cluster.Spec.ControlPlaneEndpoint.Host
+		variables[controlPlaneEndpointPortVariable] = fmt.Sprintf("%d", cluster.Spec.ControlPlaneEndpoint.Port)
+	}
+
+	if cluster.Spec.Topology != nil {
+		variables[kubernetesVersionVariable] = cluster.Spec.Topology.Version
+
+		for _, variable := range cluster.Spec.Topology.Variables {
+			var value string
+			if err := json.Unmarshal(variable.Value.Raw, &value); err != nil {
+				value = string(variable.Value.Raw)
+			}
+			variables[variable.Name] = value
+		}
+	}
+
+	return variables
+}
+
+// substituteVariables replaces ${VARIABLE_NAME} references to cluster variables in data;
+// references to unknown variables are left unchanged.
+func substituteVariables(data []byte, variables map[string]string) []byte {
+	return variableReferenceRegex.ReplaceAllFunc(data, func(reference []byte) []byte {
+		name := variableReferenceRegex.FindSubmatch(reference)[1]
+		if value, ok := variables[string(name)]; ok {
+			return []byte(value)
+		}
+		return reference
+	})
+}
@@ -0,0 +1,78 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestClusterVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "cluster1",
+			Namespace: metav1.NamespaceDefault,
+		},
+		Spec: clusterv1.ClusterSpec{
+			ClusterNetwork: &clusterv1.ClusterNetwork{
+				Pods:          &clusterv1.NetworkRanges{CIDRBlocks: []string{"192.168.0.0/16"}},
+				Services:      &clusterv1.NetworkRanges{CIDRBlocks: []string{"10.96.0.0/12"}},
+				ServiceDomain: "cluster.local",
+			},
+			ControlPlaneEndpoint: clusterv1.APIEndpoint{Host: "10.0.0.1", Port: 6443},
+			Topology: &clusterv1.Topology{
+				Version: "v1.30.0",
+				Variables: []clusterv1.ClusterVariable{
+					{Name: "region", Value: apiextensionsv1.JSON{Raw: []byte(`"us-east-1"`)}},
+					{Name: "replicas", Value: apiextensionsv1.JSON{Raw: []byte(`3`)}},
+				},
+			},
+		},
+	}
+
+	g.Expect(clusterVariables(cluster)).To(Equal(map[string]string{
+		clusterNameVariable:              "cluster1",
+		clusterNamespaceVariable:         metav1.NamespaceDefault,
+		kubernetesVersionVariable:        "v1.30.0",
+		podCIDRVariable:                  "192.168.0.0/16",
+		serviceCIDRVariable:              "10.96.0.0/12",
+		serviceDomainVariable:            "cluster.local",
+		controlPlaneEndpointHostVariable: "10.0.0.1",
+		controlPlaneEndpointPortVariable: "6443",
+		"region":                         "us-east-1",
+		"replicas":                       "3",
+	}))
+}
+
+func TestSubstituteVariables(t *testing.T) {
+	g := NewWithT(t)
+
+	variables := map[string]string{
+		clusterNameVariable: "cluster1",
+		"region":            "us-east-1",
+	}
+	data := []byte("name: ${CLUSTER_NAME}-${region}\nvalue: ${UNKNOWN}\nother: $CLUSTER_NAME")
+
+	g.Expect(string(substituteVariables(data, variables))).To(Equal("name: cluster1-us-east-1\nvalue: ${UNKNOWN}\nother: $CLUSTER_NAME"))
+}
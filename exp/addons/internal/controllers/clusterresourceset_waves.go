@@ -0,0 +1,242 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+// waveReadinessRequeueAfter is the interval between readiness checks of the objects of a wave.
+const waveReadinessRequeueAfter = 20 * time.Second
+
+// errWaitingForWave signals that the resources of a ClusterResourceSet are not fully applied to a Cluster because
+// the objects of a wave are not ready yet.
+var errWaitingForWave = errors.New("waiting for the objects of a wave to be ready")
+
+var (
+	deploymentGroupKind  = schema.GroupKind{Group: appsv1.GroupName, Kind: "Deployment"}
+	daemonSetGroupKind   = schema.GroupKind{Group: appsv1.GroupName, Kind: "DaemonSet"}
+	statefulSetGroupKind = schema.GroupKind{Group: appsv1.GroupName, Kind: "StatefulSet"}
+	crdGroupKind         = schema.GroupKind{Group: apiextensionsv1.GroupName, Kind: "CustomResourceDefinition"}
+)
+
+// resourceGroup is a group of resources of a ClusterResourceSet applied together.
+type resourceGroup struct {
+	// wave is the name of the wave, empty for the resources which are not part of any wave.
+	wave      string
+	resources []addonsv1.ResourceRef
+}
+
+// resourceGroups returns the resources of a ClusterResourceSet grouped in the order they are applied: the waves
+// in the order they are defined, then the resources which are not part of any wave, in the order they are listed.
+func resourceGroups(clusterResourceSet *addonsv1.ClusterResourceSet) []resourceGroup {
+	inSpec := map[addonsv1.ResourceRef]bool{}
+	for _, resource := range clusterResourceSet.Spec.Resources {
+		inSpec[resource] = true
+	}
+
+	groups := []resourceGroup{}
+	inWave := map[addonsv1.ResourceRef]bool{}
+	for _, wave := range clusterResourceSet.Spec.Waves {
+		group := resourceGroup{wave: wave.Name}
+		for _, resource := range wave.Resources {
+			// Resources must be listed in spec.resources to be applied, and they are applied only once.
+			if !inSpec[resource] || inWave[resource] {
+				continue
+			}
+			inWave[resource] = true
+			group.resources = append(group.resources, resource)
+		}
+		groups = append(groups, group)
+	}
+
+	remaining := resourceGroup{}
+	for _, resource := range clusterResourceSet.Spec.Resources {
+		if !inWave[resource] {
+			remaining.resources = append(remaining.resources, resource)
+		}
+	}
+	if len(remaining.resources) > 0 {
+		groups = append(groups, remaining)
+	}
+	return groups
+}
+
+// getWaveStatus returns the status of a wave given the outcome of applying its resources; a wave is ready when all
+// its resources are applied and all its objects are ready in the Cluster.
+func getWaveStatus(ctx context.Context, remoteClient client.Client, name string, applyFailed bool, notApplied []string, objs []unstructured.Unstructured) (addonsv1.WaveStatus, error) {
+	status := addonsv1.WaveStatus{Name: name}
+
+	if applyFailed {
+		status.Phase = addonsv1.WaveFailed
+		status.Message = "Failed to apply resources"
+		if len(notApplied) > 0 {
+			status.Message = fmt.Sprintf("Failed to apply resources: %s", strings.Join(notApplied, ", "))
+		}
+		return status, nil
+	}
+
+	if len(notApplied) > 0 {
+		status.Phase = addonsv1.WaveProgressing
+		status.Message = fmt.Sprintf("Waiting for resources to be applied: %s", strings.Join(notApplied, ", "))
+		return status, nil
+	}
+
+	notReady := []string{}
+	for i := range objs {
+		ready, err := isObjectReady(ctx, remoteClient, &objs[i])
+		if err != nil {
+			status.Phase = addonsv1.WaveFailed
+			status.Message = err.Error()
+			return status, err
+		}
+		if !ready {
+			notReady = append(notReady, objectString(&objs[i]))
+		}
+	}
+	if len(notReady) > 0 {
+		status.Phase = addonsv1.WaveProgressing
+		status.Message = fmt.Sprintf("Waiting for objects to be ready: %s", strings.Join(notReady, ", "))
+		return status, nil
+	}
+
+	status.Phase = addonsv1.WaveReady
+	return status, nil
+}
+
+// isObjectReady checks the readiness of an object in a Cluster: Deployments must be available and rolled out,
+// DaemonSets and StatefulSets must be rolled out, CustomResourceDefinitions must be established, while objects
+// of any other kind are ready as soon as they exist.
+func isObjectReady(ctx context.Context, remoteClient client.Client, obj *unstructured.Unstructured) (bool, error) {
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(obj.GroupVersionKind())
+	key := client.ObjectKeyFromObject(obj)
+	// Namespaced objects without a namespace are applied to the default namespace.
+	if key.Namespace == "" {
+		if namespaced, err := remoteClient.IsObjectNamespaced(current); err == nil && namespaced {
+			key.Namespace = metav1.NamespaceDefault
+		}
+	}
+	if err := remoteClient.Get(ctx, key, current); err != nil {
+		if apierrors.IsNotFound(err) {
+			return false, nil
+		}
+		return false, errors.Wrapf(err, "failed to get %s", objectString(obj))
+	}
+
+	switch obj.GroupVersionKind().GroupKind() {
+	case deploymentGroupKind:
+		deployment := &appsv1.Deployment{}
+		if err := fromUnstructured(current, deployment); err != nil {
+			return false, err
+		}
+		return isDeploymentReady(deployment), nil
+	case daemonSetGroupKind:
+		daemonSet := &appsv1.DaemonSet{}
+		if err := fromUnstructured(current, daemonSet); err != nil {
+			return false, err
+		}
+		return isDaemonSetReady(daemonSet), nil
+	case statefulSetGroupKind:
+		statefulSet := &appsv1.StatefulSet{}
+		if err := fromUnstructured(current, statefulSet); err != nil {
+			return false, err
+		}
+		return isStatefulSetReady(statefulSet), nil
+	case crdGroupKind:
+		crd := &apiextensionsv1.CustomResourceDefinition{}
+		if err := fromUnstructured(current, crd); err != nil {
+			return false, err
+		}
+		return isCRDEstablished(crd), nil
+	default:
+		return true, nil
+	}
+}
+
+func fromUnstructured(u *unstructured.Unstructured, obj interface{}) error {
+	if err := runtime.DefaultUnstructuredConverter.FromUnstructured(u.Object, obj); err != nil {
+		return errors.Wrapf(err, "failed to convert %s", objectString(u))
+	}
+	return nil
+}
+
+func isDeploymentReady(deployment *appsv1.Deployment) bool {
+	if deployment.Status.ObservedGeneration < deployment.Generation {
+		return false
+	}
+	replicas := ptr.Deref(deployment.Spec.Replicas, 1)
+	if deployment.Status.UpdatedReplicas < replicas {
+		return false
+	}
+	for _, condition := range deployment.Status.Conditions {
+		if condition.Type == appsv1.DeploymentAvailable {
+			return condition.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+func isDaemonSetReady(daemonSet *appsv1.DaemonSet) bool {
+	if daemonSet.Status.ObservedGeneration < daemonSet.Generation {
+		return false
+	}
+	return daemonSet.Status.UpdatedNumberScheduled >= daemonSet.Status.DesiredNumberScheduled &&
+		daemonSet.Status.NumberAvailable >= daemonSet.Status.DesiredNumberScheduled
+}
+
+func isStatefulSetReady(statefulSet *appsv1.StatefulSet) bool {
+	if statefulSet.Status.ObservedGeneration < statefulSet.Generation {
+		return false
+	}
+	replicas := ptr.Deref(statefulSet.Spec.Replicas, 1)
+	return statefulSet.Status.ReadyReplicas >= replicas && statefulSet.Status.UpdatedReplicas >= replicas
+}
+
+func isCRDEstablished(crd *apiextensionsv1.CustomResourceDefinition) bool {
+	for _, condition := range crd.Status.Conditions {
+		if condition.Type == apiextensionsv1.Established {
+			return condition.Status == apiextensionsv1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// objectString returns a human readable reference to an object, e.g. Deployment kube-system/coredns.
+func objectString(obj *unstructured.Unstructured) string {
+	if obj.GetNamespace() == "" {
+		return fmt.Sprintf("%s %s", obj.GetKind(), obj.GetName())
+	}
+	return fmt.Sprintf("%s %s/%s", obj.GetKind(), obj.GetNamespace(), obj.GetName())
+}
@@ -0,0 +1,191 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	appsv1 "k8s.io/api/apps/v1"
+	corev1 "k8s.io/api/core/v1"
+	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	addonsv1 "sigs.k8s.io/cluster-api/exp/addons/api/v1beta1"
+)
+
+func TestResourceGroups(t *testing.T) {
+	g := NewWithT(t)
+
+	cni := addonsv1.ResourceRef{Name: "cni", Kind: "ConfigMap"}
+	crds := addonsv1.ResourceRef{Name: "crds", Kind: "Secret"}
+	operator := addonsv1.ResourceRef{Name: "operator", Kind: "HelmChart"}
+	workloads := addonsv1.ResourceRef{Name: "workloads", Kind: "ConfigMap"}
+
+	clusterResourceSet := &addonsv1.ClusterResourceSet{
+		Spec: addonsv1.ClusterResourceSetSpec{
+			Resources: []addonsv1.ResourceRef{workloads, operator, crds, cni},
+			Waves: []addonsv1.ResourceWave{
+				{Name: "networking", Resources: []addonsv1.ResourceRef{cni}},
+				{Name: "operators", Resources: []addonsv1.ResourceRef{crds, operator, {Name: "missing", Kind: "ConfigMap"}}},
+			},
+		},
+	}
+
+	g.Expect(resourceGroups(clusterResourceSet)).To(Equal([]resourceGroup{
+		{wave: "networking", resources: []addonsv1.ResourceRef{cni}},
+		{wave: "operators", resources: []addonsv1.ResourceRef{crds, operator}},
+		{resources: []addonsv1.ResourceRef{workloads}},
+	}))
+
+	clusterResourceSet.Spec.Waves = nil
+	g.Expect(resourceGroups(clusterResourceSet)).To(Equal([]resourceGroup{
+		{resources: []addonsv1.ResourceRef{workloads, operator, crds, cni}},
+	}))
+}
+
+func TestGetWaveStatus(t *testing.T) {
+	scheme := runtime.NewScheme()
+	_ = clientgoscheme.AddToScheme(scheme)
+	_ = apiextensionsv1.AddToScheme(scheme)
+
+	readyDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: metav1.NamespaceSystem, Generation: 2},
+		Spec:       appsv1.DeploymentSpec{Replicas: ptr.To[int32](2)},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 2,
+			UpdatedReplicas:    2,
+			Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		},
+	}
+	progressingDeployment := &appsv1.Deployment{
+		ObjectMeta: metav1.ObjectMeta{Name: "progressing", Namespace: metav1.NamespaceSystem, Generation: 2},
+		Status: appsv1.DeploymentStatus{
+			ObservedGeneration: 1,
+			UpdatedReplicas:    1,
+			Conditions:         []appsv1.DeploymentCondition{{Type: appsv1.DeploymentAvailable, Status: corev1.ConditionTrue}},
+		},
+	}
+	readyDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "ready", Namespace: metav1.NamespaceSystem},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 3},
+	}
+	rollingOutDaemonSet := &appsv1.DaemonSet{
+		ObjectMeta: metav1.ObjectMeta{Name: "rolling-out", Namespace: metav1.NamespaceSystem},
+		Status:     appsv1.DaemonSetStatus{DesiredNumberScheduled: 3, UpdatedNumberScheduled: 3, NumberAvailable: 2},
+	}
+	establishedCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "established.example.com"},
+		Status: apiextensionsv1.CustomResourceDefinitionStatus{
+			Conditions: []apiextensionsv1.CustomResourceDefinitionCondition{{Type: apiextensionsv1.Established, Status: apiextensionsv1.ConditionTrue}},
+		},
+	}
+	pendingCRD := &apiextensionsv1.CustomResourceDefinition{
+		ObjectMeta: metav1.ObjectMeta{Name: "pending.example.com"},
+	}
+	configMap := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: "config", Namespace: metav1.NamespaceDefault},
+	}
+
+	restMapper := meta.NewDefaultRESTMapper([]schema.GroupVersion{corev1.SchemeGroupVersion, appsv1.SchemeGroupVersion, apiextensionsv1.SchemeGroupVersion})
+	restMapper.Add(corev1.SchemeGroupVersion.WithKind("ConfigMap"), meta.RESTScopeNamespace)
+	restMapper.Add(appsv1.SchemeGroupVersion.WithKind("Deployment"), meta.RESTScopeNamespace)
+	restMapper.Add(appsv1.SchemeGroupVersion.WithKind("DaemonSet"), meta.RESTScopeNamespace)
+	restMapper.Add(apiextensionsv1.SchemeGroupVersion.WithKind("CustomResourceDefinition"), meta.RESTScopeRoot)
+
+	remoteClient := fake.NewClientBuilder().WithScheme(scheme).WithRESTMapper(restMapper).WithObjects(
+		readyDeployment, progressingDeployment, readyDaemonSet, rollingOutDaemonSet, establishedCRD, pendingCRD, configMap,
+	).Build()
+
+	tests := []struct {
+		name        string
+		applyFailed bool
+		notApplied  []string
+		objs        []unstructured.Unstructured
+		wantPhase   addonsv1.WavePhase
+		wantMessage string
+	}{
+		{
+			name: "should be ready when all the objects are ready",
+			objs: []unstructured.Unstructured{
+				newTestObject("apps/v1", "Deployment", metav1.NamespaceSystem, readyDeployment.Name),
+				newTestObject("apps/v1", "DaemonSet", metav1.NamespaceSystem, readyDaemonSet.Name),
+				newTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", establishedCRD.Name),
+				// Objects without namespace are applied to the default namespace.
+				newTestObject("v1", "ConfigMap", "", configMap.Name),
+			},
+			wantPhase: addonsv1.WaveReady,
+		},
+		{
+			name: "should be progressing when some objects are not ready",
+			objs: []unstructured.Unstructured{
+				newTestObject("apps/v1", "Deployment", metav1.NamespaceSystem, readyDeployment.Name),
+				newTestObject("apps/v1", "Deployment", metav1.NamespaceSystem, progressingDeployment.Name),
+				newTestObject("apps/v1", "DaemonSet", metav1.NamespaceSystem, rollingOutDaemonSet.Name),
+				newTestObject("apiextensions.k8s.io/v1", "CustomResourceDefinition", "", pendingCRD.Name),
+				newTestObject("v1", "ConfigMap", metav1.NamespaceDefault, "missing"),
+			},
+			wantPhase:   addonsv1.WaveProgressing,
+			wantMessage: "Waiting for objects to be ready: Deployment kube-system/progressing, DaemonSet kube-system/rolling-out, CustomResourceDefinition pending.example.com, ConfigMap default/missing",
+		},
+		{
+			name:        "should be progressing when some resources are not applied",
+			notApplied:  []string{"ConfigMap/cni"},
+			wantPhase:   addonsv1.WaveProgressing,
+			wantMessage: "Waiting for resources to be applied: ConfigMap/cni",
+		},
+		{
+			name:        "should be failed when resources failed to be applied",
+			applyFailed: true,
+			notApplied:  []string{"ConfigMap/cni"},
+			wantPhase:   addonsv1.WaveFailed,
+			wantMessage: "Failed to apply resources: ConfigMap/cni",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			status, err := getWaveStatus(ctx, remoteClient, "wave", tt.applyFailed, tt.notApplied, tt.objs)
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(status.Name).To(Equal("wave"))
+			g.Expect(status.Phase).To(Equal(tt.wantPhase))
+			g.Expect(status.Message).To(Equal(tt.wantMessage))
+		})
+	}
+}
+
+func TestIsStatefulSetReady(t *testing.T) {
+	g := NewWithT(t)
+
+	statefulSet := &appsv1.StatefulSet{
+		Spec:   appsv1.StatefulSetSpec{Replicas: ptr.To[int32](3)},
+		Status: appsv1.StatefulSetStatus{ReadyReplicas: 3, UpdatedReplicas: 2},
+	}
+	g.Expect(isStatefulSetReady(statefulSet)).To(BeFalse())
+
+	statefulSet.Status.UpdatedReplicas = 3
+	g.Expect(isStatefulSetReady(statefulSet)).To(BeTrue())
+}
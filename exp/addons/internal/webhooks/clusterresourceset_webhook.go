@@ -19,7 +19,10 @@ package webhooks
 import (
 	"context"
 	"fmt"
+	"path"
 	"reflect"
+	"strings"
+	"time"
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -34,7 +37,11 @@ import (
 )
 
 // ClusterResourceSet implements a validation and defaulting webhook for ClusterResourceSet.
-type ClusterResourceSet struct{}
+type ClusterResourceSet struct {
+	// HelmChartsEnabled allows Helm charts to be set; it must be set only when the ClusterResourceSetReconciler
+	// is configured with a HelmChartRenderer.
+	HelmChartsEnabled bool
+}
 
 func (webhook *ClusterResourceSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return ctrl.NewWebhookManagedBy(mgr).
@@ -132,9 +139,116 @@ func (webhook *ClusterResourceSet) validate(oldCRS, newCRS *addonsv1.ClusterReso
 		)
 	}
 
+	if newCRS.Spec.GetTypedPrune() != addonsv1.ClusterResourceSetPruneDisabled &&
+		newCRS.Spec.Strategy != string(addonsv1.ClusterResourceSetStrategyReconcile) {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "prune"), "can be enabled only with the Reconcile strategy"),
+		)
+	}
+
+	if newCRS.Spec.DriftDetection != nil {
+		if newCRS.Spec.Strategy != string(addonsv1.ClusterResourceSetStrategyReconcile) {
+			allErrs = append(
+				allErrs,
+				field.Forbidden(field.NewPath("spec", "driftDetection"), "can be enabled only with the Reconcile strategy"),
+			)
+		}
+		if newCRS.Spec.DriftDetection.Interval != nil && newCRS.Spec.DriftDetection.Interval.Duration < time.Minute {
+			allErrs = append(
+				allErrs,
+				field.Invalid(field.NewPath("spec", "driftDetection", "interval"), newCRS.Spec.DriftDetection.Interval.Duration.String(), "must be at least 1m"),
+			)
+		}
+	}
+
+	// Helm charts can't be applied when the controller doesn't have a HelmChartRenderer, so they are rejected upfront.
+	if len(newCRS.Spec.HelmCharts) > 0 && !webhook.HelmChartsEnabled {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(field.NewPath("spec", "helmCharts"), "can be set only if the controller is configured for rendering Helm charts"),
+		)
+	}
+
+	allErrs = append(allErrs, validateRenderedResources(&newCRS.Spec)...)
+	allErrs = append(allErrs, validateWaves(&newCRS.Spec)...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 
 	return apierrors.NewInvalid(addonsv1.GroupVersion.WithKind("ClusterResourceSet").GroupKind(), newCRS.Name, allErrs)
 }
+
+// validateRenderedResources validates Helm charts and Kustomizations, and the resources referencing them.
+func validateRenderedResources(spec *addonsv1.ClusterResourceSetSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	for i, resource := range spec.Resources {
+		switch resource.Kind {
+		case string(addonsv1.HelmChartClusterResourceSetResourceKind):
+			if spec.GetHelmChart(resource.Name) == nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("name"), resource.Name, "must reference a Helm chart defined in spec.helmCharts"))
+			}
+		case string(addonsv1.KustomizationClusterResourceSetResourceKind):
+			if spec.GetKustomization(resource.Name) == nil {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "resources").Index(i).Child("name"), resource.Name, "must reference a Kustomization defined in spec.kustomizations"))
+			}
+		}
+	}
+
+	for i, helmChart := range spec.HelmCharts {
+		fldPath := field.NewPath("spec", "helmCharts").Index(i)
+		switch {
+		case helmChart.ChartRef == nil && helmChart.OCI == "":
+			allErrs = append(allErrs, field.Required(fldPath, "one of chartRef or oci must be set"))
+		case helmChart.ChartRef != nil && helmChart.OCI != "":
+			allErrs = append(allErrs, field.Forbidden(fldPath, "only one of chartRef or oci can be set"))
+		case helmChart.OCI != "" && !strings.HasPrefix(helmChart.OCI, "oci://"):
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("oci"), helmChart.OCI, "must start with oci://"))
+		}
+		if helmChart.Version != "" && helmChart.ChartRef != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("version"), "can be set only when oci is set"))
+		}
+		if helmChart.CredentialsSecretRef != nil && helmChart.ChartRef != nil {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("credentialsSecretRef"), "can be set only when oci is set"))
+		}
+	}
+
+	for i, kustomization := range spec.Kustomizations {
+		for j, files := range kustomization.Files {
+			if path.IsAbs(files.Path) || strings.HasPrefix(path.Clean(files.Path), "..") {
+				allErrs = append(allErrs, field.Invalid(field.NewPath("spec", "kustomizations").Index(i).Child("files").Index(j).Child("path"), files.Path, "must be relative to the root of the directory tree"))
+			}
+		}
+	}
+
+	return allErrs
+}
+
+// validateWaves validates that the resources of the waves are listed in spec.resources, and are part of one wave only.
+func validateWaves(spec *addonsv1.ClusterResourceSetSpec) field.ErrorList {
+	var allErrs field.ErrorList
+
+	resources := map[addonsv1.ResourceRef]bool{}
+	for _, resource := range spec.Resources {
+		resources[resource] = true
+	}
+
+	inWave := map[addonsv1.ResourceRef]string{}
+	for i, wave := range spec.Waves {
+		for j, resource := range wave.Resources {
+			fldPath := field.NewPath("spec", "waves").Index(i).Child("resources").Index(j)
+			if !resources[resource] {
+				allErrs = append(allErrs, field.Invalid(fldPath, resource, "must reference a resource defined in spec.resources"))
+			}
+			if other, ok := inWave[resource]; ok {
+				allErrs = append(allErrs, field.Invalid(fldPath, resource, fmt.Sprintf("must not be part of more than one wave, already part of wave %s", other)))
+				continue
+			}
+			inWave[resource] = wave.Name
+		}
+	}
+
+	return allErrs
+}
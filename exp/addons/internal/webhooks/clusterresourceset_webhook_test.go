@@ -18,8 +18,10 @@ package webhooks
 
 import (
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	ctrl "sigs.k8s.io/controller-runtime"
 
@@ -213,3 +215,294 @@ func TestClusterResourceSetSelectorNotEmptyValidation(t *testing.T) {
 	g.Expect(err).To(HaveOccurred())
 	g.Expect(err.Error()).To(ContainSubstring("selector must not be empty"))
 }
+
+func TestClusterResourceSetRenderedResourcesValidation(t *testing.T) {
+	tests := []struct {
+		name               string
+		spec               addonsv1.ClusterResourceSetSpec
+		helmChartsDisabled bool
+		expectErr          string
+	}{
+		{
+			name: "should not return error for valid Helm chart and Kustomization",
+			spec: addonsv1.ClusterResourceSetSpec{
+				Resources: []addonsv1.ResourceRef{
+					{Name: "cni", Kind: string(addonsv1.HelmChartClusterResourceSetResourceKind)},
+					{Name: "addons", Kind: string(addonsv1.KustomizationClusterResourceSetResourceKind)},
+				},
+				HelmCharts: []addonsv1.HelmChartSource{
+					{Name: "cni", OCI: "oci://registry.example.com/charts/cni", Version: "1.0.0"},
+				},
+				Kustomizations: []addonsv1.KustomizationSource{
+					{Name: "addons", Files: []addonsv1.KustomizationFiles{{SourceRef: addonsv1.SourceRef{Name: "base", Kind: "ConfigMap"}, Path: "base"}}},
+				},
+			},
+		},
+		{
+			name: "should return error for resource referencing an undefined Helm chart",
+			spec: addonsv1.ClusterResourceSetSpec{
+				Resources: []addonsv1.ResourceRef{{Name: "cni", Kind: string(addonsv1.HelmChartClusterResourceSetResourceKind)}},
+			},
+			expectErr: "must reference a Helm chart defined in spec.helmCharts",
+		},
+		{
+			name: "should return error for resource referencing an undefined Kustomization",
+			spec: addonsv1.ClusterResourceSetSpec{
+				Resources: []addonsv1.ResourceRef{{Name: "addons", Kind: string(addonsv1.KustomizationClusterResourceSetResourceKind)}},
+			},
+			expectErr: "must reference a Kustomization defined in spec.kustomizations",
+		},
+		{
+			name: "should return error for Helm chart without chartRef and oci",
+			spec: addonsv1.ClusterResourceSetSpec{
+				HelmCharts: []addonsv1.HelmChartSource{{Name: "cni"}},
+			},
+			expectErr: "one of chartRef or oci must be set",
+		},
+		{
+			name: "should return error for Helm chart with both chartRef and oci",
+			spec: addonsv1.ClusterResourceSetSpec{
+				HelmCharts: []addonsv1.HelmChartSource{{Name: "cni", ChartRef: &addonsv1.SourceRef{Name: "cni", Kind: "Secret"}, OCI: "oci://registry.example.com/charts/cni"}},
+			},
+			expectErr: "only one of chartRef or oci can be set",
+		},
+		{
+			name: "should return error for Helm chart with an invalid oci reference",
+			spec: addonsv1.ClusterResourceSetSpec{
+				HelmCharts: []addonsv1.HelmChartSource{{Name: "cni", OCI: "registry.example.com/charts/cni"}},
+			},
+			expectErr: "must start with oci://",
+		},
+		{
+			name: "should return error for Helm chart with chartRef and version",
+			spec: addonsv1.ClusterResourceSetSpec{
+				HelmCharts: []addonsv1.HelmChartSource{{Name: "cni", ChartRef: &addonsv1.SourceRef{Name: "cni", Kind: "Secret"}, Version: "1.0.0"}},
+			},
+			expectErr: "can be set only when oci is set",
+		},
+		{
+			name: "should return error for Helm chart with chartRef and credentialsSecretRef",
+			spec: addonsv1.ClusterResourceSetSpec{
+				HelmCharts: []addonsv1.HelmChartSource{{Name: "cni", ChartRef: &addonsv1.SourceRef{Name: "cni", Kind: "Secret"}, CredentialsSecretRef: &corev1.LocalObjectReference{Name: "credentials"}}},
+			},
+			expectErr: "can be set only when oci is set",
+		},
+		{
+			name: "should return error for Helm chart if rendering Helm charts is not enabled",
+			spec: addonsv1.ClusterResourceSetSpec{
+				Resources: []addonsv1.ResourceRef{
+					{Name: "cni", Kind: string(addonsv1.HelmChartClusterResourceSetResourceKind)},
+				},
+				HelmCharts: []addonsv1.HelmChartSource{
+					{Name: "cni", OCI: "oci://registry.example.com/charts/cni", Version: "1.0.0"},
+				},
+			},
+			helmChartsDisabled: true,
+			expectErr:          "can be set only if the controller is configured for rendering Helm charts",
+		},
+		{
+			name: "should return error for Kustomization with a path outside of the directory tree",
+			spec: addonsv1.ClusterResourceSetSpec{
+				Kustomizations: []addonsv1.KustomizationSource{
+					{Name: "addons", Files: []addonsv1.KustomizationFiles{{SourceRef: addonsv1.SourceRef{Name: "base", Kind: "ConfigMap"}, Path: "../base"}}},
+				},
+			},
+			expectErr: "must be relative to the root of the directory tree",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: tt.spec,
+			}
+			clusterResourceSet.Spec.ClusterSelector = metav1.LabelSelector{
+				MatchLabels: map[string]string{"foo": "bar"},
+			}
+			webhook := ClusterResourceSet{HelmChartsEnabled: !tt.helmChartsDisabled}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.expectErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterResourceSetPruneValidation(t *testing.T) {
+	tests := []struct {
+		name      string
+		strategy  addonsv1.ClusterResourceSetStrategy
+		prune     addonsv1.ClusterResourceSetPrunePolicy
+		expectErr bool
+	}{
+		{
+			name:      "should not return error when prune is not set",
+			strategy:  addonsv1.ClusterResourceSetStrategyApplyOnce,
+			expectErr: false,
+		},
+		{
+			name:      "should not return error when prune is disabled with the ApplyOnce strategy",
+			strategy:  addonsv1.ClusterResourceSetStrategyApplyOnce,
+			prune:     addonsv1.ClusterResourceSetPruneDisabled,
+			expectErr: false,
+		},
+		{
+			name:      "should not return error when prune is enabled with the Reconcile strategy",
+			strategy:  addonsv1.ClusterResourceSetStrategyReconcile,
+			prune:     addonsv1.ClusterResourceSetPruneEnabled,
+			expectErr: false,
+		},
+		{
+			name:      "should not return error when prune is in dry-run mode with the Reconcile strategy",
+			strategy:  addonsv1.ClusterResourceSetStrategyReconcile,
+			prune:     addonsv1.ClusterResourceSetPruneDryRun,
+			expectErr: false,
+		},
+		{
+			name:      "should return error when prune is enabled with the ApplyOnce strategy",
+			strategy:  addonsv1.ClusterResourceSetStrategyApplyOnce,
+			prune:     addonsv1.ClusterResourceSetPruneEnabled,
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Strategy: string(tt.strategy),
+					Prune:    string(tt.prune),
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring("can be enabled only with the Reconcile strategy"))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterResourceSetDriftDetectionValidation(t *testing.T) {
+	tests := []struct {
+		name           string
+		strategy       addonsv1.ClusterResourceSetStrategy
+		driftDetection *addonsv1.ClusterResourceSetDriftDetection
+		expectErr      string
+	}{
+		{
+			name:     "should not return error when drift detection is enabled with the Reconcile strategy",
+			strategy: addonsv1.ClusterResourceSetStrategyReconcile,
+			driftDetection: &addonsv1.ClusterResourceSetDriftDetection{
+				Interval:  &metav1.Duration{Duration: 5 * time.Minute},
+				Remediate: true,
+			},
+		},
+		{
+			name:           "should return error when drift detection is enabled with the ApplyOnce strategy",
+			strategy:       addonsv1.ClusterResourceSetStrategyApplyOnce,
+			driftDetection: &addonsv1.ClusterResourceSetDriftDetection{},
+			expectErr:      "can be enabled only with the Reconcile strategy",
+		},
+		{
+			name:     "should return error when the drift detection interval is too short",
+			strategy: addonsv1.ClusterResourceSetStrategyReconcile,
+			driftDetection: &addonsv1.ClusterResourceSetDriftDetection{
+				Interval: &metav1.Duration{Duration: 10 * time.Second},
+			},
+			expectErr: "must be at least 1m",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Strategy:       string(tt.strategy),
+					DriftDetection: tt.driftDetection,
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.expectErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
+func TestClusterResourceSetWavesValidation(t *testing.T) {
+	cni := addonsv1.ResourceRef{Name: "cni", Kind: "ConfigMap"}
+	csi := addonsv1.ResourceRef{Name: "csi", Kind: "Secret"}
+	workloads := addonsv1.ResourceRef{Name: "workloads", Kind: "ConfigMap"}
+
+	tests := []struct {
+		name      string
+		waves     []addonsv1.ResourceWave
+		expectErr string
+	}{
+		{
+			name: "should not return error when the waves reference resources defined in spec.resources",
+			waves: []addonsv1.ResourceWave{
+				{Name: "networking", Resources: []addonsv1.ResourceRef{cni}},
+				{Name: "storage", Resources: []addonsv1.ResourceRef{csi}},
+			},
+		},
+		{
+			name: "should return error when a wave references a resource not defined in spec.resources",
+			waves: []addonsv1.ResourceWave{
+				{Name: "networking", Resources: []addonsv1.ResourceRef{{Name: "missing", Kind: "ConfigMap"}}},
+			},
+			expectErr: "must reference a resource defined in spec.resources",
+		},
+		{
+			name: "should return error when a resource is part of more than one wave",
+			waves: []addonsv1.ResourceWave{
+				{Name: "networking", Resources: []addonsv1.ResourceRef{cni}},
+				{Name: "storage", Resources: []addonsv1.ResourceRef{csi, cni}},
+			},
+			expectErr: "must not be part of more than one wave, already part of wave networking",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			clusterResourceSet := &addonsv1.ClusterResourceSet{
+				Spec: addonsv1.ClusterResourceSetSpec{
+					ClusterSelector: metav1.LabelSelector{
+						MatchLabels: map[string]string{"foo": "bar"},
+					},
+					Resources: []addonsv1.ResourceRef{cni, csi, workloads},
+					Waves:     tt.waves,
+				},
+			}
+			webhook := ClusterResourceSet{}
+			err := webhook.validate(nil, clusterResourceSet)
+			if tt.expectErr != "" {
+				g.Expect(err).To(HaveOccurred())
+				g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
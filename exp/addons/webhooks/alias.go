@@ -23,11 +23,17 @@ import (
 )
 
 // ClusterResourceSet implements a validating and defaulting webhook for ClusterResourceSet.
-type ClusterResourceSet struct{}
+type ClusterResourceSet struct {
+	// HelmChartsEnabled allows Helm charts to be set; it must be set only when the ClusterResourceSetReconciler
+	// is configured with a HelmChartRenderer.
+	HelmChartsEnabled bool
+}
 
 // SetupWebhookWithManager sets up ClusterResourceSet webhooks.
 func (webhook *ClusterResourceSet) SetupWebhookWithManager(mgr ctrl.Manager) error {
-	return (&webhooks.ClusterResourceSet{}).SetupWebhookWithManager(mgr)
+	return (&webhooks.ClusterResourceSet{
+		HelmChartsEnabled: webhook.HelmChartsEnabled,
+	}).SetupWebhookWithManager(mgr)
 }
 
 // ClusterResourceSetBinding implements a validating webhook for ClusterResourceSetBinding.
@@ -28,3 +28,15 @@ const (
 	// to be ready.
 	WaitingForReplicasReadyReason = "WaitingForReplicasReady"
 )
+
+const (
+	// MachinesUpToDateCondition reports whether all the Machines of a MachinePool with a strategy are up-to-date.
+	MachinesUpToDateCondition clusterv1.ConditionType = "MachinesUpToDate"
+
+	// RollingUpdateInProgressReason (Severity=Info) documents a MachinePool replacing the Machines which are not up-to-date.
+	RollingUpdateInProgressReason = "RollingUpdateInProgress"
+
+	// WaitingForMachinesDeletionReason (Severity=Info) documents a MachinePool with the OnDelete strategy waiting for
+	// the Machines which are not up-to-date to be deleted.
+	WaitingForMachinesDeletionReason = "WaitingForMachinesDeletion"
+)
@@ -19,6 +19,7 @@ package v1beta1
 import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 
 	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
 	capierrors "sigs.k8s.io/cluster-api/errors"
@@ -61,10 +62,66 @@ type MachinePoolSpec struct {
 	// FailureDomains is the list of failure domains this MachinePool should be attached to.
 	// +optional
 	FailureDomains []string `json:"failureDomains,omitempty"`
+
+	// Strategy is the strategy used to replace the Machines of the MachinePool which are not up-to-date.
+	// It applies only to MachinePools whose infrastructure provider supports MachinePool Machines; when not set,
+	// the replacement of the instances is left entirely to the infrastructure provider.
+	// +optional
+	Strategy *MachinePoolStrategy `json:"strategy,omitempty"`
 }
 
 // ANCHOR_END: MachinePoolSpec
 
+// MachinePoolStrategyType defines the type of MachinePool rollout strategies.
+type MachinePoolStrategyType string
+
+const (
+	// RollingUpdateMachinePoolStrategyType replaces the Machines which are not up-to-date by deleting them
+	// gradually, within the bounds of maxUnavailable and maxSurge.
+	RollingUpdateMachinePoolStrategyType MachinePoolStrategyType = "RollingUpdate"
+
+	// OnDeleteMachinePoolStrategyType replaces the Machines which are not up-to-date only when they are
+	// deleted by the user.
+	OnDeleteMachinePoolStrategyType MachinePoolStrategyType = "OnDelete"
+)
+
+// MachinePoolStrategy describes how to replace the Machines of a MachinePool which are not up-to-date.
+type MachinePoolStrategy struct {
+	// Type of the strategy. Allowed values are RollingUpdate and OnDelete.
+	// The default is RollingUpdate.
+	// +kubebuilder:validation:Enum=RollingUpdate;OnDelete
+	// +optional
+	Type MachinePoolStrategyType `json:"type,omitempty"`
+
+	// RollingUpdate configures the RollingUpdate strategy.
+	// Present only if type is RollingUpdate.
+	// +optional
+	RollingUpdate *MachinePoolRollingUpdate `json:"rollingUpdate,omitempty"`
+}
+
+// MachinePoolRollingUpdate is used to control the desired behavior of a MachinePool rolling update.
+type MachinePoolRollingUpdate struct {
+	// MaxUnavailable is the maximum number of Machines that can be unavailable during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired replicas (ex: 10%).
+	// Absolute number is calculated from percentage by rounding down.
+	// This can not be 0 if MaxSurge is 0.
+	// Defaults to 1.
+	// +optional
+	MaxUnavailable *intstr.IntOrString `json:"maxUnavailable,omitempty"`
+
+	// MaxSurge is the maximum number of Machines that can exist above the desired replicas during the update.
+	// Value can be an absolute number (ex: 5) or a percentage of desired replicas (ex: 10%).
+	// Absolute number is calculated from percentage by rounding up.
+	// The infrastructure provider is responsible for creating the surge instances; the MachinePool controller
+	// counts them when computing how many Machines which are not up-to-date can be deleted, and it deletes
+	// Machines only as long as the Machines not available plus the Machines exceeding the desired replicas
+	// don't exceed MaxSurge + MaxUnavailable.
+	// This can not be 0 if MaxUnavailable is 0.
+	// Defaults to 0.
+	// +optional
+	MaxSurge *intstr.IntOrString `json:"maxSurge,omitempty"`
+}
+
 // ANCHOR: MachinePoolStatus
 
 // MachinePoolStatus defines the observed state of MachinePool.
@@ -108,6 +165,11 @@ type MachinePoolStatus struct {
 	// +optional
 	Phase string `json:"phase,omitempty"`
 
+	// UpToDateReplicas is the number of MachinePool Machines whose infrastructure is up-to-date with the
+	// MachinePool. It is set only for MachinePools with a strategy.
+	// +optional
+	UpToDateReplicas int32 `json:"upToDateReplicas,omitempty"`
+
 	// BootstrapReady is the state of the bootstrap provider.
 	// +optional
 	BootstrapReady bool `json:"bootstrapReady"`
@@ -380,6 +380,15 @@ func (r *MachinePoolReconciler) reconcileMachines(ctx context.Context, mp *expv1
 		return errors.Wrapf(err, "failed to create machines for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
 	}
 
+	machines, err := r.reconcileUnhealthyMachines(ctx, machineList.Items)
+	if err != nil {
+		return err
+	}
+
+	if err := r.reconcileRollout(ctx, mp, machines, infraMachineList.Items); err != nil {
+		return errors.Wrapf(err, "failed to roll out Machines for MachinePool %q in namespace %q", mp.Name, mp.Namespace)
+	}
+
 	return nil
 }
 
@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileUnhealthyMachines deletes the MachinePool Machines marked as unhealthy by the MachineHealthCheck controller
+// and returns the Machines which have not been deleted.
+// Note: deleting a Machine drains the corresponding Node and deletes the infraMachine; the InfraMachinePool
+// controller is then responsible for deleting the instance and for replacing it if required.
+func (r *MachinePoolReconciler) reconcileUnhealthyMachines(ctx context.Context, machines []clusterv1.Machine) ([]clusterv1.Machine, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	remainingMachines := make([]clusterv1.Machine, 0, len(machines))
+	var errs []error
+	for i := range machines {
+		m := &machines[i]
+		// Skip Machines already being deleted and Machines which are not marked for remediation.
+		if !m.DeletionTimestamp.IsZero() || !conditions.IsFalse(m, clusterv1.MachineOwnerRemediatedCondition) {
+			remainingMachines = append(remainingMachines, *m)
+			continue
+		}
+
+		log.Info(fmt.Sprintf("Deleting Machine %s because it was marked as unhealthy by the MachineHealthCheck controller", klog.KObj(m)))
+		patch := client.MergeFrom(m.DeepCopy())
+		if err := r.Client.Delete(ctx, m); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(m)))
+			continue
+		}
+		conditions.MarkTrue(m, clusterv1.MachineOwnerRemediatedCondition)
+		if err := r.Client.Status().Patch(ctx, m, patch); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to update status of Machine %s", klog.KObj(m)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return nil, errors.Wrapf(kerrors.NewAggregate(errs), "failed to delete unhealthy Machines")
+	}
+	return remainingMachines, nil
+}
+
+// reconcileRollout replaces the MachinePool Machines which are not up-to-date according to the MachinePool strategy.
+//
+// Note: The InfraMachinePool controller reports if an instance is up-to-date with the MachinePool by setting
+// status.upToDate on the corresponding infraMachine; infraMachines without this field are considered up-to-date.
+// Deleting a Machine drains the corresponding Node and deletes the infraMachine, and the InfraMachinePool
+// controller is responsible for replacing the instance.
+func (r *MachinePoolReconciler) reconcileRollout(ctx context.Context, mp *expv1.MachinePool, machines []clusterv1.Machine, infraMachines []unstructured.Unstructured) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	if mp.Spec.Strategy == nil {
+		mp.Status.UpToDateReplicas = 0
+		conditions.Delete(mp, expv1.MachinesUpToDateCondition)
+		return nil
+	}
+
+	upToDateInfraMachines := map[string]bool{}
+	for i := range infraMachines {
+		upToDate, err := isInfraMachineUpToDate(&infraMachines[i])
+		if err != nil {
+			return err
+		}
+		upToDateInfraMachines[infraMachines[i].GetName()] = upToDate
+	}
+
+	var upToDateReplicas int32
+	total := 0
+	available := 0
+	unavailableOutdated := 0
+	outdatedMachines := []*clusterv1.Machine{}
+	for i := range machines {
+		m := &machines[i]
+		upToDate, ok := upToDateInfraMachines[m.Spec.InfrastructureRef.Name]
+		if !ok || !m.DeletionTimestamp.IsZero() {
+			continue
+		}
+		total++
+		ready := conditions.IsTrue(m, clusterv1.ReadyCondition)
+		if ready {
+			available++
+		}
+		if upToDate {
+			upToDateReplicas++
+			continue
+		}
+		if !ready {
+			unavailableOutdated++
+		}
+		outdatedMachines = append(outdatedMachines, m)
+	}
+	mp.Status.UpToDateReplicas = upToDateReplicas
+
+	if len(outdatedMachines) == 0 {
+		conditions.MarkTrue(mp, expv1.MachinesUpToDateCondition)
+		return nil
+	}
+
+	if mp.Spec.Strategy.Type == expv1.OnDeleteMachinePoolStrategyType {
+		conditions.MarkFalse(mp, expv1.MachinesUpToDateCondition, expv1.WaitingForMachinesDeletionReason, clusterv1.ConditionSeverityInfo,
+			"%d of %d Machines are not up-to-date", len(outdatedMachines), len(outdatedMachines)+int(upToDateReplicas))
+		return nil
+	}
+
+	conditions.MarkFalse(mp, expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo,
+		"%d of %d Machines are not up-to-date", len(outdatedMachines), len(outdatedMachines)+int(upToDateReplicas))
+
+	maxUnavailable, maxSurge, err := machinePoolRollingUpdateLimits(mp)
+	if err != nil {
+		return err
+	}
+
+	// Machines which are not available can always be deleted, while available Machines can be deleted only:
+	// - as long as the number of available Machines doesn't drop below replicas - maxUnavailable; surge instances
+	//   created by the infrastructure provider count as available once ready.
+	// - as long as the number of Machines being replaced, i.e. the Machines which are not available plus the
+	//   Machines exceeding replicas, doesn't exceed maxSurge + maxUnavailable; each deleted Machine is replaced
+	//   by the infrastructure provider with a Machine which is not available until ready.
+	replicas := int(ptr.Deref(mp.Spec.Replicas, 1))
+	budget := min(
+		available-(replicas-maxUnavailable),
+		maxSurge+maxUnavailable-(total-available-unavailableOutdated),
+	)
+
+	// Delete the Machines which are not available first, then the oldest ones.
+	sort.SliceStable(outdatedMachines, func(i, j int) bool {
+		iReady := conditions.IsTrue(outdatedMachines[i], clusterv1.ReadyCondition)
+		jReady := conditions.IsTrue(outdatedMachines[j], clusterv1.ReadyCondition)
+		if iReady != jReady {
+			return !iReady
+		}
+		if !outdatedMachines[i].CreationTimestamp.Equal(&outdatedMachines[j].CreationTimestamp) {
+			return outdatedMachines[i].CreationTimestamp.Before(&outdatedMachines[j].CreationTimestamp)
+		}
+		return outdatedMachines[i].Name < outdatedMachines[j].Name
+	})
+
+	var errs []error
+	for _, m := range outdatedMachines {
+		ready := conditions.IsTrue(m, clusterv1.ReadyCondition)
+		if ready {
+			if budget <= 0 {
+				break
+			}
+			budget--
+		}
+
+		log.Info(fmt.Sprintf("Deleting Machine %s because it is not up-to-date", klog.KObj(m)))
+		if err := r.Client.Delete(ctx, m); err != nil && !apierrors.IsNotFound(err) {
+			errs = append(errs, errors.Wrapf(err, "failed to delete Machine %s", klog.KObj(m)))
+		}
+	}
+
+	if len(errs) > 0 {
+		return errors.Wrapf(kerrors.NewAggregate(errs), "failed to delete Machines which are not up-to-date")
+	}
+	return nil
+}
+
+// isInfraMachineUpToDate returns true if the infraMachine doesn't report being out of date with its InfraMachinePool.
+func isInfraMachineUpToDate(infraMachine *unstructured.Unstructured) (bool, error) {
+	var upToDate bool
+	if err := util.UnstructuredUnmarshalField(infraMachine, &upToDate, "status", "upToDate"); err != nil {
+		if errors.Is(err, util.ErrUnstructuredFieldNotFound) {
+			return true, nil
+		}
+		return false, errors.Wrapf(err, "failed to retrieve upToDate from %s %s", infraMachine.GetKind(), klog.KObj(infraMachine))
+	}
+	return upToDate, nil
+}
+
+// machinePoolRollingUpdateLimits returns the maximum number of Machines that can be unavailable and the maximum
+// number of Machines that can exist above the desired replicas during a rolling update.
+func machinePoolRollingUpdateLimits(mp *expv1.MachinePool) (int, int, error) {
+	maxUnavailable := intstr.FromInt(1)
+	maxSurge := intstr.FromInt(0)
+	if rollingUpdate := mp.Spec.Strategy.RollingUpdate; rollingUpdate != nil {
+		if rollingUpdate.MaxUnavailable != nil {
+			maxUnavailable = *rollingUpdate.MaxUnavailable
+		}
+		if rollingUpdate.MaxSurge != nil {
+			maxSurge = *rollingUpdate.MaxSurge
+		}
+	}
+
+	replicas := int(ptr.Deref(mp.Spec.Replicas, 1))
+	unavailableValue, err := intstr.GetScaledValueFromIntOrPercent(&maxUnavailable, replicas, false)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to compute maxUnavailable for MachinePool %s", klog.KObj(mp))
+	}
+	surgeValue, err := intstr.GetScaledValueFromIntOrPercent(&maxSurge, replicas, true)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to compute maxSurge for MachinePool %s", klog.KObj(mp))
+	}
+	return unavailableValue, surgeValue, nil
+}
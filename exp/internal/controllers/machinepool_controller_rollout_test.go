@@ -0,0 +1,260 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileUnhealthyMachines(t *testing.T) {
+	g := NewWithT(t)
+
+	machines := getMachines(2, "mp", "cluster", metav1.NamespaceDefault)
+	conditions.MarkFalse(&machines[0], clusterv1.MachineOwnerRemediatedCondition, clusterv1.WaitingForRemediationReason, clusterv1.ConditionSeverityWarning, "")
+
+	objs := []client.Object{}
+	for i := range machines {
+		objs = append(objs, &machines[i])
+	}
+	c := fake.NewClientBuilder().WithObjects(objs...).WithStatusSubresource(&clusterv1.Machine{}).Build()
+	r := &MachinePoolReconciler{Client: c}
+
+	remainingMachines, err := r.reconcileUnhealthyMachines(ctx, machines)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(remainingMachines).To(HaveLen(1))
+	g.Expect(remainingMachines[0].Name).To(Equal(machines[1].Name))
+
+	err = c.Get(ctx, client.ObjectKeyFromObject(&machines[0]), &clusterv1.Machine{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(&machines[1]), &clusterv1.Machine{})).To(Succeed())
+}
+
+func TestReconcileRollout(t *testing.T) {
+	newInfraMachine := func(name string, upToDate *bool) unstructured.Unstructured {
+		infraMachine := unstructured.Unstructured{Object: map[string]interface{}{
+			"kind":       builder.GenericInfrastructureMachineKind,
+			"apiVersion": builder.InfrastructureGroupVersion.String(),
+			"metadata": map[string]interface{}{
+				"name":      name,
+				"namespace": metav1.NamespaceDefault,
+			},
+		}}
+		if upToDate != nil {
+			infraMachine.Object["status"] = map[string]interface{}{"upToDate": *upToDate}
+		}
+		return infraMachine
+	}
+
+	oneUnavailable := intstr.FromInt(1)
+	noneUnavailable := intstr.FromInt(0)
+	oneSurge := intstr.FromInt(1)
+	twoUnavailable := intstr.FromInt(2)
+
+	tests := []struct {
+		name                     string
+		strategy                 *expv1.MachinePoolStrategy
+		replicas                 int32
+		machines                 int
+		notReady                 []int
+		upToDate                 []*bool
+		expectedDeleted          []int
+		expectedUpToDateReplicas int32
+		expectedCondition        *clusterv1.Condition
+	}{
+		{
+			name:                     "should not delete Machines without a strategy",
+			replicas:                 2,
+			machines:                 2,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false)},
+			expectedUpToDateReplicas: 0,
+		},
+		{
+			name:                     "should consider Machines without upToDate as up-to-date",
+			strategy:                 &expv1.MachinePoolStrategy{Type: expv1.RollingUpdateMachinePoolStrategyType},
+			replicas:                 2,
+			machines:                 2,
+			upToDate:                 []*bool{nil, ptr.To(true)},
+			expectedUpToDateReplicas: 2,
+			expectedCondition:        conditions.TrueCondition(expv1.MachinesUpToDateCondition),
+		},
+		{
+			name:                     "should not delete Machines with the OnDelete strategy",
+			strategy:                 &expv1.MachinePoolStrategy{Type: expv1.OnDeleteMachinePoolStrategyType},
+			replicas:                 2,
+			machines:                 2,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(true)},
+			expectedUpToDateReplicas: 1,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.WaitingForMachinesDeletionReason, clusterv1.ConditionSeverityInfo, "1 of 2 Machines are not up-to-date"),
+		},
+		{
+			name: "should delete the oldest Machines within maxUnavailable",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &oneUnavailable},
+			},
+			replicas:                 3,
+			machines:                 3,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false), ptr.To(false)},
+			expectedDeleted:          []int{0},
+			expectedUpToDateReplicas: 0,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "3 of 3 Machines are not up-to-date"),
+		},
+		{
+			name: "should delete Machines which are not available first",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &oneUnavailable},
+			},
+			replicas:                 3,
+			machines:                 3,
+			notReady:                 []int{2},
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false), ptr.To(false)},
+			expectedDeleted:          []int{2},
+			expectedUpToDateReplicas: 0,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "3 of 3 Machines are not up-to-date"),
+		},
+		{
+			name: "should delete Machines only when surge instances are available if maxUnavailable is 0",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &noneUnavailable, MaxSurge: &oneSurge},
+			},
+			replicas:                 2,
+			machines:                 3,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false), ptr.To(true)},
+			expectedDeleted:          []int{0},
+			expectedUpToDateReplicas: 1,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "2 of 3 Machines are not up-to-date"),
+		},
+		{
+			name: "should not delete Machines if maxUnavailable is 0 and there are no surge instances",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &noneUnavailable, MaxSurge: &oneSurge},
+			},
+			replicas:                 2,
+			machines:                 2,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false)},
+			expectedUpToDateReplicas: 0,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "2 of 2 Machines are not up-to-date"),
+		},
+		{
+			name: "should not delete more Machines than maxSurge + maxUnavailable even if more surge instances are available",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &noneUnavailable, MaxSurge: &oneSurge},
+			},
+			replicas:                 2,
+			machines:                 4,
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false), ptr.To(true), ptr.To(true)},
+			expectedDeleted:          []int{0},
+			expectedUpToDateReplicas: 2,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "2 of 4 Machines are not up-to-date"),
+		},
+		{
+			name: "should not delete available Machines while surge instances being created exceed maxSurge",
+			strategy: &expv1.MachinePoolStrategy{
+				Type:          expv1.RollingUpdateMachinePoolStrategyType,
+				RollingUpdate: &expv1.MachinePoolRollingUpdate{MaxUnavailable: &twoUnavailable},
+			},
+			replicas:                 3,
+			machines:                 5,
+			notReady:                 []int{3, 4},
+			upToDate:                 []*bool{ptr.To(false), ptr.To(false), ptr.To(false), ptr.To(true), ptr.To(true)},
+			expectedUpToDateReplicas: 2,
+			expectedCondition:        conditions.FalseCondition(expv1.MachinesUpToDateCondition, expv1.RollingUpdateInProgressReason, clusterv1.ConditionSeverityInfo, "3 of 5 Machines are not up-to-date"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:      "mp",
+					Namespace: metav1.NamespaceDefault,
+				},
+				Spec: expv1.MachinePoolSpec{
+					ClusterName: "cluster",
+					Replicas:    ptr.To(tt.replicas),
+					Strategy:    tt.strategy,
+				},
+			}
+
+			machines := getMachines(tt.machines, mp.Name, mp.Spec.ClusterName, mp.Namespace)
+			infraMachines := make([]unstructured.Unstructured, 0, len(machines))
+			objs := []client.Object{}
+			for i := range machines {
+				machines[i].CreationTimestamp = metav1.NewTime(time.Now().Add(time.Duration(i) * time.Minute))
+				conditions.MarkTrue(&machines[i], clusterv1.ReadyCondition)
+				infraMachines = append(infraMachines, newInfraMachine(machines[i].Spec.InfrastructureRef.Name, tt.upToDate[i]))
+			}
+			for _, i := range tt.notReady {
+				conditions.MarkFalse(&machines[i], clusterv1.ReadyCondition, "NotReady", clusterv1.ConditionSeverityInfo, "")
+			}
+			for i := range machines {
+				objs = append(objs, &machines[i])
+			}
+
+			c := fake.NewClientBuilder().WithObjects(objs...).Build()
+			r := &MachinePoolReconciler{Client: c}
+
+			g.Expect(r.reconcileRollout(ctx, mp, machines, infraMachines)).To(Succeed())
+			g.Expect(mp.Status.UpToDateReplicas).To(Equal(tt.expectedUpToDateReplicas))
+
+			if tt.expectedCondition == nil {
+				g.Expect(conditions.Get(mp, expv1.MachinesUpToDateCondition)).To(BeNil())
+			} else {
+				condition := conditions.Get(mp, expv1.MachinesUpToDateCondition)
+				g.Expect(condition).ToNot(BeNil())
+				g.Expect(condition.Status).To(Equal(tt.expectedCondition.Status))
+				g.Expect(condition.Reason).To(Equal(tt.expectedCondition.Reason))
+				g.Expect(condition.Message).To(Equal(tt.expectedCondition.Message))
+			}
+
+			deleted := map[int]bool{}
+			for _, i := range tt.expectedDeleted {
+				deleted[i] = true
+			}
+			for i := range machines {
+				err := c.Get(ctx, client.ObjectKeyFromObject(&machines[i]), &clusterv1.Machine{})
+				if deleted[i] {
+					g.Expect(apierrors.IsNotFound(err)).To(BeTrue(), fmt.Sprintf("expected Machine %s to be deleted", machines[i].Name))
+				} else {
+					g.Expect(err).ToNot(HaveOccurred(), fmt.Sprintf("expected Machine %s not to be deleted", machines[i].Name))
+				}
+			}
+		})
+	}
+}
@@ -23,6 +23,7 @@ import (
 
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -72,6 +73,27 @@ func (webhook *MachinePool) Default(_ context.Context, obj runtime.Object) error
 		m.Spec.MinReadySeconds = ptr.To[int32](0)
 	}
 
+	// Default the strategy only if it is set, so the replacement of the instances is otherwise left
+	// to the infrastructure provider.
+	if m.Spec.Strategy != nil {
+		if m.Spec.Strategy.Type == "" {
+			m.Spec.Strategy.Type = expv1.RollingUpdateMachinePoolStrategyType
+		}
+		if m.Spec.Strategy.Type == expv1.RollingUpdateMachinePoolStrategyType {
+			if m.Spec.Strategy.RollingUpdate == nil {
+				m.Spec.Strategy.RollingUpdate = &expv1.MachinePoolRollingUpdate{}
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxSurge == nil {
+				ios0 := intstr.FromInt(0)
+				m.Spec.Strategy.RollingUpdate.MaxSurge = &ios0
+			}
+			if m.Spec.Strategy.RollingUpdate.MaxUnavailable == nil {
+				ios1 := intstr.FromInt(1)
+				m.Spec.Strategy.RollingUpdate.MaxUnavailable = &ios1
+			}
+		}
+	}
+
 	if m.Spec.Template.Spec.Bootstrap.ConfigRef != nil && m.Spec.Template.Spec.Bootstrap.ConfigRef.Namespace == "" {
 		m.Spec.Template.Spec.Bootstrap.ConfigRef.Namespace = m.Namespace
 	}
@@ -179,11 +201,57 @@ func (webhook *MachinePool) validate(oldObj, newObj *expv1.MachinePool) error {
 		}
 	}
 
+	if newObj.Spec.Strategy != nil && newObj.Spec.Strategy.RollingUpdate != nil {
+		allErrs = append(allErrs, validateMachinePoolRollingUpdate(newObj, specPath.Child("strategy", "rollingUpdate"))...)
+	}
+
 	// Validate the metadata of the MachinePool template.
 	allErrs = append(allErrs, newObj.Spec.Template.ObjectMeta.Validate(specPath.Child("template", "metadata"))...)
 
+	// IP addresses are claimed only for the Machines created from MachineSets, so they can't be set on MachinePools.
+	if len(newObj.Spec.Template.Spec.IPAddressClaims) > 0 {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(specPath.Child("template", "spec", "ipAddressClaims"), "is not supported for MachinePools"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("MachinePool").GroupKind(), newObj.Name, allErrs)
 }
+
+// validateMachinePoolRollingUpdate validates the rolling update of a MachinePool; maxSurge and maxUnavailable
+// can't be both 0, otherwise the Machines which are not up-to-date could never be deleted.
+func validateMachinePoolRollingUpdate(mp *expv1.MachinePool, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	total := 1
+	if mp.Spec.Replicas != nil {
+		total = int(*mp.Spec.Replicas)
+	}
+
+	rollingUpdate := mp.Spec.Strategy.RollingUpdate
+	maxSurge, maxUnavailable := 0, 1
+	if rollingUpdate.MaxSurge != nil {
+		value, err := intstr.GetScaledValueFromIntOrPercent(rollingUpdate.MaxSurge, total, true)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxSurge"), rollingUpdate.MaxSurge,
+				fmt.Sprintf("must be either an int or a percentage: %v", err.Error())))
+		}
+		maxSurge = value
+	}
+	if rollingUpdate.MaxUnavailable != nil {
+		value, err := intstr.GetScaledValueFromIntOrPercent(rollingUpdate.MaxUnavailable, total, false)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), rollingUpdate.MaxUnavailable,
+				fmt.Sprintf("must be either an int or a percentage: %v", err.Error())))
+		}
+		maxUnavailable = value
+	}
+	if len(allErrs) == 0 && maxSurge == 0 && maxUnavailable == 0 {
+		allErrs = append(allErrs, field.Invalid(fldPath.Child("maxUnavailable"), rollingUpdate.MaxUnavailable,
+			"can not be 0 if maxSurge is 0"))
+	}
+	return allErrs
+}
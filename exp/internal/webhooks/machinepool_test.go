@@ -23,6 +23,7 @@ import (
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	ctrl "sigs.k8s.io/controller-runtime"
@@ -67,6 +68,120 @@ func TestMachinePoolDefault(t *testing.T) {
 	g.Expect(mp.Spec.Template.Spec.Version).To(Equal(ptr.To("v1.20.0")))
 }
 
+func TestMachinePoolStrategyDefault(t *testing.T) {
+	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+
+	g := NewWithT(t)
+
+	mp := &expv1.MachinePool{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: "foobar",
+		},
+		Spec: expv1.MachinePoolSpec{
+			Strategy: &expv1.MachinePoolStrategy{},
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+				},
+			},
+		},
+	}
+	webhook := &MachinePool{}
+	g.Expect(webhook.Default(ctx, mp)).To(Succeed())
+
+	g.Expect(mp.Spec.Strategy.Type).To(Equal(expv1.RollingUpdateMachinePoolStrategyType))
+	g.Expect(mp.Spec.Strategy.RollingUpdate).ToNot(BeNil())
+	g.Expect(mp.Spec.Strategy.RollingUpdate.MaxSurge.IntValue()).To(Equal(0))
+	g.Expect(mp.Spec.Strategy.RollingUpdate.MaxUnavailable.IntValue()).To(Equal(1))
+
+	// A MachinePool without strategy must not be defaulted to one.
+	mp.Spec.Strategy = nil
+	g.Expect(webhook.Default(ctx, mp)).To(Succeed())
+	g.Expect(mp.Spec.Strategy).To(BeNil())
+}
+
+func TestMachinePoolStrategyValidation(t *testing.T) {
+	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+
+	zero := intstr.FromInt(0)
+	one := intstr.FromInt(1)
+	tenPercent := intstr.FromString("10%")
+	invalid := intstr.FromString("foo")
+
+	tests := []struct {
+		name           string
+		maxSurge       *intstr.IntOrString
+		maxUnavailable *intstr.IntOrString
+		expectErr      bool
+	}{
+		{
+			name:           "should succeed with maxUnavailable 1",
+			maxSurge:       &zero,
+			maxUnavailable: &one,
+		},
+		{
+			name:           "should succeed with maxSurge 1",
+			maxSurge:       &one,
+			maxUnavailable: &zero,
+		},
+		{
+			name:           "should fail if maxSurge and maxUnavailable are 0",
+			maxSurge:       &zero,
+			maxUnavailable: &zero,
+			expectErr:      true,
+		},
+		{
+			name:           "should fail if maxUnavailable rounds down to 0 and maxSurge is 0",
+			maxSurge:       &zero,
+			maxUnavailable: &tenPercent,
+			expectErr:      true,
+		},
+		{
+			name:           "should fail if maxSurge is not an int or a percentage",
+			maxSurge:       &invalid,
+			maxUnavailable: &one,
+			expectErr:      true,
+		},
+	}
+
+	for i := range tests {
+		tt := tests[i]
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			mp := &expv1.MachinePool{
+				Spec: expv1.MachinePoolSpec{
+					Replicas: ptr.To[int32](3),
+					Strategy: &expv1.MachinePoolStrategy{
+						Type: expv1.RollingUpdateMachinePoolStrategyType,
+						RollingUpdate: &expv1.MachinePoolRollingUpdate{
+							MaxSurge:       tt.maxSurge,
+							MaxUnavailable: tt.maxUnavailable,
+						},
+					},
+					Template: clusterv1.MachineTemplateSpec{
+						Spec: clusterv1.MachineSpec{
+							Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+						},
+					},
+				},
+			}
+			webhook := &MachinePool{}
+
+			_, err := webhook.ValidateCreate(ctx, mp)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestMachinePoolBootstrapValidation(t *testing.T) {
 	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
 	// Enabling the feature flag temporarily for this test.
@@ -332,6 +447,40 @@ func TestMachinePoolVersionValidation(t *testing.T) {
 	}
 }
 
+func TestMachinePoolIPAddressClaimsValidation(t *testing.T) {
+	// NOTE: MachinePool feature flag is disabled by default, thus preventing to create or update MachinePool.
+	// Enabling the feature flag temporarily for this test.
+	defer utilfeature.SetFeatureGateDuringTest(t, feature.Gates, feature.MachinePool, true)()
+	g := NewWithT(t)
+
+	mp := &expv1.MachinePool{
+		Spec: expv1.MachinePoolSpec{
+			Template: clusterv1.MachineTemplateSpec{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap: clusterv1.Bootstrap{ConfigRef: &corev1.ObjectReference{}},
+					IPAddressClaims: []clusterv1.MachineIPAddressClaim{{
+						Name: "eth0",
+						PoolRef: corev1.TypedLocalObjectReference{
+							APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+							Kind:     "InClusterIPPool",
+							Name:     "pool",
+						},
+					}},
+				},
+			},
+		},
+	}
+	webhook := &MachinePool{}
+
+	warnings, err := webhook.ValidateCreate(ctx, mp)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(err.Error()).To(ContainSubstring("is not supported for MachinePools"))
+	g.Expect(warnings).To(BeEmpty())
+	warnings, err = webhook.ValidateUpdate(ctx, mp, mp)
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(warnings).To(BeEmpty())
+}
+
 func TestMachinePoolMetadataValidation(t *testing.T) {
 	tests := []struct {
 		name        string
@@ -17,21 +17,36 @@ limitations under the License.
 package v1alpha1
 
 import (
+	apiconversion "k8s.io/apimachinery/pkg/conversion"
 	"sigs.k8s.io/controller-runtime/pkg/conversion"
 
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	utilconversion "sigs.k8s.io/cluster-api/util/conversion"
 )
 
 func (src *IPAddress) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*ipamv1.IPAddress)
 
-	return Convert_v1alpha1_IPAddress_To_v1beta1_IPAddress(src, dst, nil)
+	if err := Convert_v1alpha1_IPAddress_To_v1beta1_IPAddress(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &ipamv1.IPAddress{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.Family = restored.Spec.Family
+	return nil
 }
 
 func (dst *IPAddress) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*ipamv1.IPAddress)
 
-	return Convert_v1beta1_IPAddress_To_v1alpha1_IPAddress(src, dst, nil)
+	if err := Convert_v1beta1_IPAddress_To_v1alpha1_IPAddress(src, dst, nil); err != nil {
+		return err
+	}
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *IPAddressList) ConvertTo(dstRaw conversion.Hub) error {
@@ -49,13 +64,27 @@ func (dst *IPAddressList) ConvertFrom(srcRaw conversion.Hub) error {
 func (src *IPAddressClaim) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*ipamv1.IPAddressClaim)
 
-	return Convert_v1alpha1_IPAddressClaim_To_v1beta1_IPAddressClaim(src, dst, nil)
+	if err := Convert_v1alpha1_IPAddressClaim_To_v1beta1_IPAddressClaim(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &ipamv1.IPAddressClaim{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.AddressFamilies = restored.Spec.AddressFamilies
+	dst.Status.AddressRefs = restored.Status.AddressRefs
+	return nil
 }
 
 func (dst *IPAddressClaim) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*ipamv1.IPAddressClaim)
 
-	return Convert_v1beta1_IPAddressClaim_To_v1alpha1_IPAddressClaim(src, dst, nil)
+	if err := Convert_v1beta1_IPAddressClaim_To_v1alpha1_IPAddressClaim(src, dst, nil); err != nil {
+		return err
+	}
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *IPAddressClaimList) ConvertTo(dstRaw conversion.Hub) error {
@@ -69,3 +98,18 @@ func (dst *IPAddressClaimList) ConvertFrom(srcRaw conversion.Hub) error {
 
 	return Convert_v1beta1_IPAddressClaimList_To_v1alpha1_IPAddressClaimList(src, dst, nil)
 }
+
+func Convert_v1beta1_IPAddressSpec_To_v1alpha1_IPAddressSpec(in *ipamv1.IPAddressSpec, out *IPAddressSpec, s apiconversion.Scope) error {
+	// spec.family has been added with v1beta1.
+	return autoConvert_v1beta1_IPAddressSpec_To_v1alpha1_IPAddressSpec(in, out, s)
+}
+
+func Convert_v1beta1_IPAddressClaimSpec_To_v1alpha1_IPAddressClaimSpec(in *ipamv1.IPAddressClaimSpec, out *IPAddressClaimSpec, s apiconversion.Scope) error {
+	// spec.addressFamilies has been added with v1beta1.
+	return autoConvert_v1beta1_IPAddressClaimSpec_To_v1alpha1_IPAddressClaimSpec(in, out, s)
+}
+
+func Convert_v1beta1_IPAddressClaimStatus_To_v1alpha1_IPAddressClaimStatus(in *ipamv1.IPAddressClaimStatus, out *IPAddressClaimStatus, s apiconversion.Scope) error {
+	// status.addressRefs has been added with v1beta1.
+	return autoConvert_v1beta1_IPAddressClaimStatus_To_v1alpha1_IPAddressClaimStatus(in, out, s)
+}
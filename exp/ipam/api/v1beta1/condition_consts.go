@@ -0,0 +1,38 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+
+// Conditions and condition Reasons for the IPAddressClaim object.
+
+const (
+	// AddressAllocatedCondition reports whether an IPAddress has been allocated for an IPAddressClaim.
+	AddressAllocatedCondition clusterv1.ConditionType = "AddressAllocated"
+
+	// PoolNotFoundReason (Severity=Warning) documents an IPAddressClaim referencing a pool which doesn't exist.
+	PoolNotFoundReason = "PoolNotFound"
+
+	// PoolDeletingReason (Severity=Warning) documents an IPAddressClaim referencing a pool which is being deleted.
+	PoolDeletingReason = "PoolDeleting"
+
+	// PoolExhaustedReason (Severity=Warning) documents an IPAddressClaim referencing a pool with no free addresses.
+	PoolExhaustedReason = "PoolExhausted"
+
+	// AllocationFailedReason (Severity=Error) documents an IPAddressClaim for which allocating an address failed.
+	AllocationFailedReason = "AllocationFailed"
+)
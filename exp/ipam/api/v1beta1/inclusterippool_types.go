@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1beta1
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// InClusterIPPoolKind is the kind of InClusterIPPool.
+	InClusterIPPoolKind = "InClusterIPPool"
+
+	// GlobalInClusterIPPoolKind is the kind of GlobalInClusterIPPool.
+	GlobalInClusterIPPoolKind = "GlobalInClusterIPPool"
+
+	// ReleaseAddressFinalizer is added to IPAddressClaims fulfilled by an in-cluster pool, so the allocated
+	// IPAddress is released before the claim is deleted.
+	ReleaseAddressFinalizer = "ipam.cluster.x-k8s.io/release-address"
+
+	// ProtectAddressFinalizer is added to IPAddresses allocated from an in-cluster pool, so the address can't be
+	// deleted while the claim it was allocated for still exists.
+	ProtectAddressFinalizer = "ipam.cluster.x-k8s.io/protect-address"
+
+	// ProtectPoolFinalizer is added to in-cluster pools, so a pool can't be deleted while addresses allocated
+	// from it still exist.
+	ProtectPoolFinalizer = "ipam.cluster.x-k8s.io/protect-pool"
+)
+
+// InClusterIPPoolSpec is the desired state of an InClusterIPPool or a GlobalInClusterIPPool.
+type InClusterIPPoolSpec struct {
+	// Addresses is the list of IP addresses which can be allocated from the pool; the addresses can be non-contiguous.
+	// Each entry can be a single address (e.g. 10.0.0.10), a range (e.g. 10.0.0.10-10.0.0.20) or a CIDR (e.g. 10.0.0.0/28).
+	// All the addresses must be of the same IP family.
+	// +kubebuilder:validation:MinItems=1
+	Addresses []string `json:"addresses"`
+
+	// Prefix is the prefix of the network the addresses are from; it is set on the allocated IPAddresses.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=128
+	Prefix int `json:"prefix"`
+
+	// Gateway is the gateway of the network the addresses are from; it is never allocated.
+	// +optional
+	Gateway string `json:"gateway,omitempty"`
+
+	// ExcludedAddresses is the list of IP addresses which must not be allocated from the pool, in the same formats as
+	// Addresses.
+	// +optional
+	ExcludedAddresses []string `json:"excludedAddresses,omitempty"`
+
+	// AllocateReservedIPAddresses allows to allocate the network and broadcast addresses of the network defined by
+	// the gateway and the prefix; by default, they are never allocated.
+	// +optional
+	AllocateReservedIPAddresses bool `json:"allocateReservedIPAddresses,omitempty"`
+}
+
+// InClusterIPPoolStatus is the observed state of an InClusterIPPool or a GlobalInClusterIPPool.
+type InClusterIPPoolStatus struct {
+	// Addresses reports the usage of the addresses of the pool.
+	// +optional
+	Addresses *IPPoolAddressesSummary `json:"ipAddresses,omitempty"`
+}
+
+// IPPoolAddressesSummary summarizes the usage of the addresses of a pool.
+type IPPoolAddressesSummary struct {
+	// Total is the number of addresses which can be allocated from the pool.
+	Total int `json:"total"`
+
+	// Used is the number of addresses allocated from the pool.
+	Used int `json:"used"`
+
+	// Free is the number of addresses which can still be allocated from the pool.
+	Free int `json:"free"`
+
+	// OutOfRange is the number of allocated addresses which are no longer part of the pool, e.g. because the
+	// addresses of the pool have been changed; they are not released until their claims are deleted.
+	// +optional
+	OutOfRange int `json:"outOfRange,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=inclusterippools,scope=Namespaced,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Addresses",type="string",JSONPath=".spec.addresses",description="List of addresses of the pool"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.ipAddresses.total",description="Number of addresses of the pool"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.ipAddresses.free",description="Number of free addresses of the pool"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.ipAddresses.used",description="Number of allocated addresses of the pool"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of InClusterIPPool"
+
+// InClusterIPPool is the Schema for the inclusterippools API; it allocates addresses to the IPAddressClaims
+// in its namespace.
+type InClusterIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InClusterIPPoolSpec   `json:"spec,omitempty"`
+	Status InClusterIPPoolStatus `json:"status,omitempty"`
+}
+
+// PoolSpec returns the spec of the pool.
+func (p *InClusterIPPool) PoolSpec() *InClusterIPPoolSpec {
+	return &p.Spec
+}
+
+// PoolStatus returns the status of the pool.
+func (p *InClusterIPPool) PoolStatus() *InClusterIPPoolStatus {
+	return &p.Status
+}
+
+// +kubebuilder:object:root=true
+
+// InClusterIPPoolList is a list of InClusterIPPools.
+type InClusterIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []InClusterIPPool `json:"items"`
+}
+
+// +kubebuilder:object:root=true
+// +kubebuilder:resource:path=globalinclusterippools,scope=Cluster,categories=cluster-api
+// +kubebuilder:subresource:status
+// +kubebuilder:storageversion
+// +kubebuilder:printcolumn:name="Addresses",type="string",JSONPath=".spec.addresses",description="List of addresses of the pool"
+// +kubebuilder:printcolumn:name="Total",type="integer",JSONPath=".status.ipAddresses.total",description="Number of addresses of the pool"
+// +kubebuilder:printcolumn:name="Free",type="integer",JSONPath=".status.ipAddresses.free",description="Number of free addresses of the pool"
+// +kubebuilder:printcolumn:name="Used",type="integer",JSONPath=".status.ipAddresses.used",description="Number of allocated addresses of the pool"
+// +kubebuilder:printcolumn:name="Age",type="date",JSONPath=".metadata.creationTimestamp",description="Time duration since creation of GlobalInClusterIPPool"
+
+// GlobalInClusterIPPool is the Schema for the globalinclusterippools API; it allocates addresses to the
+// IPAddressClaims in all namespaces.
+type GlobalInClusterIPPool struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   InClusterIPPoolSpec   `json:"spec,omitempty"`
+	Status InClusterIPPoolStatus `json:"status,omitempty"`
+}
+
+// PoolSpec returns the spec of the pool.
+func (p *GlobalInClusterIPPool) PoolSpec() *InClusterIPPoolSpec {
+	return &p.Spec
+}
+
+// PoolStatus returns the status of the pool.
+func (p *GlobalInClusterIPPool) PoolStatus() *InClusterIPPoolStatus {
+	return &p.Status
+}
+
+// +kubebuilder:object:root=true
+
+// GlobalInClusterIPPoolList is a list of GlobalInClusterIPPools.
+type GlobalInClusterIPPoolList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []GlobalInClusterIPPool `json:"items"`
+}
+
+func init() {
+	objectTypes = append(objectTypes, &InClusterIPPool{}, &InClusterIPPoolList{}, &GlobalInClusterIPPool{}, &GlobalInClusterIPPoolList{})
+}
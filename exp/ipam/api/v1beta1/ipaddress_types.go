@@ -38,8 +38,24 @@ type IPAddressSpec struct {
 	// Gateway is the network gateway of the network the address is from.
 	// +optional
 	Gateway string `json:"gateway,omitempty"`
+
+	// Family is the family of the address, set when the address was requested for a given family by the claim.
+	// +optional
+	Family IPAddressFamily `json:"family,omitempty"`
 }
 
+// IPAddressFamily is the family of an IP address.
+// +kubebuilder:validation:Enum=IPv4;IPv6
+type IPAddressFamily string
+
+const (
+	// IPv4Family is the IPv4 address family.
+	IPv4Family IPAddressFamily = "IPv4"
+
+	// IPv6Family is the IPv6 address family.
+	IPv6Family IPAddressFamily = "IPv6"
+)
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ipaddresses,scope=Namespaced,categories=cluster-api
 // +kubebuilder:storageversion
@@ -27,19 +27,65 @@ import (
 type IPAddressClaimSpec struct {
 	// PoolRef is a reference to the pool from which an IP address should be created.
 	PoolRef corev1.TypedLocalObjectReference `json:"poolRef"`
+
+	// AddressFamilies requests one address per family, e.g. an IPv4 and an IPv6 address for a dual-stack node.
+	// When empty, a single address of any of the families provided by the pool is requested.
+	// +optional
+	// +listType=map
+	// +listMapKey=family
+	// +kubebuilder:validation:MaxItems=2
+	AddressFamilies []IPAddressClaimFamily `json:"addressFamilies,omitempty"`
+}
+
+// IPAddressClaimFamily defines an address of a given family requested by an IPAddressClaim.
+type IPAddressClaimFamily struct {
+	// Family is the family of the requested address.
+	Family IPAddressFamily `json:"family"`
+
+	// PoolRef is a reference to the pool the address of this family is allocated from.
+	// It must have the same apiGroup as spec.poolRef; defaults to spec.poolRef.
+	// +optional
+	PoolRef *corev1.TypedLocalObjectReference `json:"poolRef,omitempty"`
 }
 
 // IPAddressClaimStatus is the observed status of a IPAddressClaim.
 type IPAddressClaimStatus struct {
-	// AddressRef is a reference to the address that was created for this claim.
+	// AddressRef is a reference to the address that was created for this claim. When spec.addressFamilies is set,
+	// it references the address of the first family, and it is set only after the addresses of all the families
+	// are created.
 	// +optional
 	AddressRef corev1.LocalObjectReference `json:"addressRef,omitempty"`
 
+	// AddressRefs are references to the addresses created for each of the families in spec.addressFamilies.
+	// +optional
+	// +listType=map
+	// +listMapKey=family
+	AddressRefs []IPAddressReference `json:"addressRefs,omitempty"`
+
 	// Conditions summarises the current state of the IPAddressClaim
 	// +optional
 	Conditions clusterv1.Conditions `json:"conditions,omitempty"`
 }
 
+// IPAddressReference is a reference to an address of a given family.
+type IPAddressReference struct {
+	// Family is the family of the address.
+	Family IPAddressFamily `json:"family"`
+
+	// Name is the name of the IPAddress.
+	Name string `json:"name"`
+}
+
+// PoolRefForFamily returns the reference to the pool the address of the given family is allocated from.
+func (s *IPAddressClaimSpec) PoolRefForFamily(family IPAddressFamily) corev1.TypedLocalObjectReference {
+	for _, f := range s.AddressFamilies {
+		if f.Family == family && f.PoolRef != nil {
+			return *f.PoolRef
+		}
+	}
+	return s.PoolRef
+}
+
 // +kubebuilder:object:root=true
 // +kubebuilder:resource:path=ipaddressclaims,scope=Namespaced,categories=cluster-api
 // +kubebuilder:subresource:status
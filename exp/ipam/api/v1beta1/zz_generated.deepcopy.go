@@ -21,10 +21,70 @@ limitations under the License.
 package v1beta1
 
 import (
+	"k8s.io/api/core/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	apiv1beta1 "sigs.k8s.io/cluster-api/api/v1beta1"
 )
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalInClusterIPPool) DeepCopyInto(out *GlobalInClusterIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalInClusterIPPool.
+func (in *GlobalInClusterIPPool) DeepCopy() *GlobalInClusterIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalInClusterIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalInClusterIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *GlobalInClusterIPPoolList) DeepCopyInto(out *GlobalInClusterIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]GlobalInClusterIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new GlobalInClusterIPPoolList.
+func (in *GlobalInClusterIPPoolList) DeepCopy() *GlobalInClusterIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(GlobalInClusterIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *GlobalInClusterIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAddress) DeepCopyInto(out *IPAddress) {
 	*out = *in
@@ -78,6 +138,26 @@ func (in *IPAddressClaim) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAddressClaimFamily) DeepCopyInto(out *IPAddressClaimFamily) {
+	*out = *in
+	if in.PoolRef != nil {
+		in, out := &in.PoolRef, &out.PoolRef
+		*out = new(v1.TypedLocalObjectReference)
+		(*in).DeepCopyInto(*out)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAddressClaimFamily.
+func (in *IPAddressClaimFamily) DeepCopy() *IPAddressClaimFamily {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAddressClaimFamily)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAddressClaimList) DeepCopyInto(out *IPAddressClaimList) {
 	*out = *in
@@ -114,6 +194,13 @@ func (in *IPAddressClaimList) DeepCopyObject() runtime.Object {
 func (in *IPAddressClaimSpec) DeepCopyInto(out *IPAddressClaimSpec) {
 	*out = *in
 	in.PoolRef.DeepCopyInto(&out.PoolRef)
+	if in.AddressFamilies != nil {
+		in, out := &in.AddressFamilies, &out.AddressFamilies
+		*out = make([]IPAddressClaimFamily, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 }
 
 // DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAddressClaimSpec.
@@ -130,6 +217,11 @@ func (in *IPAddressClaimSpec) DeepCopy() *IPAddressClaimSpec {
 func (in *IPAddressClaimStatus) DeepCopyInto(out *IPAddressClaimStatus) {
 	*out = *in
 	out.AddressRef = in.AddressRef
+	if in.AddressRefs != nil {
+		in, out := &in.AddressRefs, &out.AddressRefs
+		*out = make([]IPAddressReference, len(*in))
+		copy(*out, *in)
+	}
 	if in.Conditions != nil {
 		in, out := &in.Conditions, &out.Conditions
 		*out = make(apiv1beta1.Conditions, len(*in))
@@ -181,6 +273,21 @@ func (in *IPAddressList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPAddressReference) DeepCopyInto(out *IPAddressReference) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPAddressReference.
+func (in *IPAddressReference) DeepCopy() *IPAddressReference {
+	if in == nil {
+		return nil
+	}
+	out := new(IPAddressReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *IPAddressSpec) DeepCopyInto(out *IPAddressSpec) {
 	*out = *in
@@ -197,3 +304,122 @@ func (in *IPAddressSpec) DeepCopy() *IPAddressSpec {
 	in.DeepCopyInto(out)
 	return out
 }
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *IPPoolAddressesSummary) DeepCopyInto(out *IPPoolAddressesSummary) {
+	*out = *in
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new IPPoolAddressesSummary.
+func (in *IPPoolAddressesSummary) DeepCopy() *IPPoolAddressesSummary {
+	if in == nil {
+		return nil
+	}
+	out := new(IPPoolAddressesSummary)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InClusterIPPool) DeepCopyInto(out *InClusterIPPool) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterIPPool.
+func (in *InClusterIPPool) DeepCopy() *InClusterIPPool {
+	if in == nil {
+		return nil
+	}
+	out := new(InClusterIPPool)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InClusterIPPool) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InClusterIPPoolList) DeepCopyInto(out *InClusterIPPoolList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]InClusterIPPool, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterIPPoolList.
+func (in *InClusterIPPoolList) DeepCopy() *InClusterIPPoolList {
+	if in == nil {
+		return nil
+	}
+	out := new(InClusterIPPoolList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *InClusterIPPoolList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InClusterIPPoolSpec) DeepCopyInto(out *InClusterIPPoolSpec) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.ExcludedAddresses != nil {
+		in, out := &in.ExcludedAddresses, &out.ExcludedAddresses
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterIPPoolSpec.
+func (in *InClusterIPPoolSpec) DeepCopy() *InClusterIPPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(InClusterIPPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *InClusterIPPoolStatus) DeepCopyInto(out *InClusterIPPoolStatus) {
+	*out = *in
+	if in.Addresses != nil {
+		in, out := &in.Addresses, &out.Addresses
+		*out = new(IPPoolAddressesSummary)
+		**out = **in
+	}
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new InClusterIPPoolStatus.
+func (in *InClusterIPPoolStatus) DeepCopy() *InClusterIPPoolStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(InClusterIPPoolStatus)
+	in.DeepCopyInto(out)
+	return out
+}
@@ -0,0 +1,175 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools;globalinclusterippools,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools/status;inclusterippools/finalizers;globalinclusterippools/status;globalinclusterippools/finalizers,verbs=get;update;patch
+
+// InClusterIPPoolReconciler reconciles an InClusterIPPool object.
+type InClusterIPPoolReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *InClusterIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		Watches(
+			&ipamv1.IPAddress{},
+			handler.EnqueueRequestsFromMapFunc(ipAddressToPool(ipamv1.InClusterIPPoolKind)),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *InClusterIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pool := &ipamv1.InClusterIPPool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, reconcilePool(ctx, r.Client, pool)
+}
+
+// GlobalInClusterIPPoolReconciler reconciles a GlobalInClusterIPPool object.
+type GlobalInClusterIPPoolReconciler struct {
+	Client client.Client
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+}
+
+func (r *GlobalInClusterIPPoolReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.GlobalInClusterIPPool{}).
+		Watches(
+			&ipamv1.IPAddress{},
+			handler.EnqueueRequestsFromMapFunc(ipAddressToPool(ipamv1.GlobalInClusterIPPoolKind)),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *GlobalInClusterIPPoolReconciler) Reconcile(ctx context.Context, req ctrl.Request) (ctrl.Result, error) {
+	pool := &ipamv1.GlobalInClusterIPPool{}
+	if err := r.Client.Get(ctx, req.NamespacedName, pool); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+	return ctrl.Result{}, reconcilePool(ctx, r.Client, pool)
+}
+
+// reconcilePool updates the usage of the addresses of a pool, and prevents the deletion of the pool until all the
+// addresses allocated from it are released.
+func reconcilePool(ctx context.Context, c client.Client, pool inClusterPool) (reterr error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	patchHelper, err := patch.NewHelper(pool, c)
+	if err != nil {
+		return err
+	}
+
+	defer func() {
+		if err := patchHelper.Patch(ctx, pool); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	addresses, err := poolAddresses(ctx, c, pool)
+	if err != nil {
+		return err
+	}
+
+	// Handle deletion reconciliation loop.
+	if !pool.GetDeletionTimestamp().IsZero() {
+		if len(addresses) > 0 {
+			log.Info(fmt.Sprintf("Waiting for %d IPAddresses to be released before deleting the pool", len(addresses)))
+			return nil
+		}
+		controllerutil.RemoveFinalizer(pool, ipamv1.ProtectPoolFinalizer)
+		return nil
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
+	if !controllerutil.ContainsFinalizer(pool, ipamv1.ProtectPoolFinalizer) {
+		controllerutil.AddFinalizer(pool, ipamv1.ProtectPoolFinalizer)
+		return nil
+	}
+
+	set, err := poolAddressSet(pool.PoolSpec())
+	if err != nil {
+		return errors.Wrapf(err, "invalid addresses in %s %s", poolKind(pool), client.ObjectKeyFromObject(pool))
+	}
+	pool.PoolStatus().Addresses = addressesSummary(set, addresses)
+	return nil
+}
+
+// ipAddressToPool returns a mapper function that maps an IPAddress to the pool of the given kind it is allocated from.
+func ipAddressToPool(kind string) handler.MapFunc {
+	return func(_ context.Context, o client.Object) []ctrl.Request {
+		address, ok := o.(*ipamv1.IPAddress)
+		if !ok {
+			panic(fmt.Sprintf("Expected an IPAddress but got a %T", o))
+		}
+		if !isInClusterPoolRef(address.Spec.PoolRef) || address.Spec.PoolRef.Kind != kind {
+			return nil
+		}
+
+		key := client.ObjectKey{Name: address.Spec.PoolRef.Name}
+		if kind == ipamv1.InClusterIPPoolKind {
+			key.Namespace = address.Namespace
+		}
+		return []ctrl.Request{{NamespacedName: key}}
+	}
+}
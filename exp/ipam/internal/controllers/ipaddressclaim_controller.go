@@ -0,0 +1,419 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	kerrors "k8s.io/apimachinery/pkg/util/errors"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/controller/controllerutil"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/patch"
+	"sigs.k8s.io/cluster-api/util/predicates"
+)
+
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims,verbs=get;list;watch;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddressclaims/status;ipaddressclaims/finalizers,verbs=get;update;patch
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=ipaddresses,verbs=get;list;watch;create;update;patch;delete
+// +kubebuilder:rbac:groups=ipam.cluster.x-k8s.io,resources=inclusterippools;globalinclusterippools,verbs=get;list;watch
+
+// errPoolExhausted signals that there are no free addresses in a pool.
+var errPoolExhausted = errors.New("no free addresses in the pool")
+
+// IPAddressClaimReconciler allocates IPAddresses from InClusterIPPools and GlobalInClusterIPPools to IPAddressClaims.
+type IPAddressClaimReconciler struct {
+	Client client.Client
+
+	// APIReader is used to list the IPAddresses of a pool when allocating a new address, so that an address
+	// is never allocated twice because of a stale cache.
+	APIReader client.Reader
+
+	// WatchFilterValue is the label value used to filter events prior to reconciliation.
+	WatchFilterValue string
+
+	// poolLocks serializes the allocations from each pool.
+	poolLocks sync.Map
+}
+
+func (r *IPAddressClaimReconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, options controller.Options) error {
+	err := ctrl.NewControllerManagedBy(mgr).
+		For(&ipamv1.IPAddressClaim{}, builder.WithPredicates(predicate.NewPredicateFuncs(func(o client.Object) bool {
+			claim, ok := o.(*ipamv1.IPAddressClaim)
+			return ok && isInClusterPoolRef(claim.Spec.PoolRef)
+		}))).
+		Owns(&ipamv1.IPAddress{}).
+		Watches(
+			&ipamv1.InClusterIPPool{},
+			handler.EnqueueRequestsFromMapFunc(r.poolToPendingIPAddressClaims),
+		).
+		Watches(
+			&ipamv1.GlobalInClusterIPPool{},
+			handler.EnqueueRequestsFromMapFunc(r.poolToPendingIPAddressClaims),
+		).
+		Watches(
+			&ipamv1.IPAddress{},
+			handler.EnqueueRequestsFromMapFunc(r.ipAddressToPendingIPAddressClaims),
+		).
+		WithOptions(options).
+		WithEventFilter(predicates.ResourceNotPausedAndHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue)).
+		Complete(r)
+	if err != nil {
+		return errors.Wrap(err, "failed setting up with a controller manager")
+	}
+
+	return nil
+}
+
+func (r *IPAddressClaimReconciler) Reconcile(ctx context.Context, req ctrl.Request) (_ ctrl.Result, reterr error) {
+	// Fetch the IPAddressClaim instance.
+	claim := &ipamv1.IPAddressClaim{}
+	if err := r.Client.Get(ctx, req.NamespacedName, claim); err != nil {
+		if apierrors.IsNotFound(err) {
+			return ctrl.Result{}, nil
+		}
+		return ctrl.Result{}, err
+	}
+
+	// Claims for pools of other providers are ignored.
+	if !isInClusterPoolRef(claim.Spec.PoolRef) {
+		return ctrl.Result{}, nil
+	}
+
+	patchHelper, err := patch.NewHelper(claim, r.Client)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	defer func() {
+		conditions.SetSummary(claim, conditions.WithConditions(ipamv1.AddressAllocatedCondition))
+		if err := patchHelper.Patch(ctx, claim, patch.WithOwnedConditions{Conditions: []clusterv1.ConditionType{
+			clusterv1.ReadyCondition,
+			ipamv1.AddressAllocatedCondition,
+		}}); err != nil {
+			reterr = kerrors.NewAggregate([]error{reterr, err})
+		}
+	}()
+
+	// Handle deletion reconciliation loop.
+	if !claim.DeletionTimestamp.IsZero() {
+		return ctrl.Result{}, r.reconcileDelete(ctx, claim)
+	}
+
+	// Add finalizer first if not set to avoid the race condition between init and delete.
+	// Note: Finalizers in general can only be added when the deletionTimestamp is not set.
+	if !controllerutil.ContainsFinalizer(claim, ipamv1.ReleaseAddressFinalizer) {
+		controllerutil.AddFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+		return ctrl.Result{}, nil
+	}
+
+	return ctrl.Result{}, r.reconcileNormal(ctx, claim)
+}
+
+// addressRequest is an address requested by a claim.
+type addressRequest struct {
+	// name is the name of the IPAddress. It is the name of the claim for claims which don't request
+	// address families, and the name of the claim suffixed with the family otherwise.
+	name    string
+	family  ipamv1.IPAddressFamily
+	poolRef corev1.TypedLocalObjectReference
+}
+
+// addressRequests returns the addresses requested by a claim.
+func addressRequests(claim *ipamv1.IPAddressClaim) []addressRequest {
+	if len(claim.Spec.AddressFamilies) == 0 {
+		return []addressRequest{{name: claim.Name, poolRef: claim.Spec.PoolRef}}
+	}
+
+	requests := make([]addressRequest, 0, len(claim.Spec.AddressFamilies))
+	for _, f := range claim.Spec.AddressFamilies {
+		requests = append(requests, addressRequest{
+			name:    fmt.Sprintf("%s-%s", claim.Name, strings.ToLower(string(f.Family))),
+			family:  f.Family,
+			poolRef: claim.Spec.PoolRefForFamily(f.Family),
+		})
+	}
+	return requests
+}
+
+func (r *IPAddressClaimReconciler) reconcileNormal(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	var refs []ipamv1.IPAddressReference
+	requests := addressRequests(claim)
+	for _, request := range requests {
+		address, err := r.reconcileAddress(ctx, claim, request)
+		if err != nil || address == nil {
+			return err
+		}
+		if request.family != "" {
+			refs = append(refs, ipamv1.IPAddressReference{Family: request.family, Name: address.Name})
+		}
+	}
+
+	// The claim is bound only when the addresses of all the requested families are allocated.
+	claim.Status.AddressRef = corev1.LocalObjectReference{Name: requests[0].name}
+	claim.Status.AddressRefs = refs
+	conditions.MarkTrue(claim, ipamv1.AddressAllocatedCondition)
+	return nil
+}
+
+// reconcileAddress returns the IPAddress allocated for an address requested by a claim, allocating it if required.
+// If the address can't be allocated yet, the AddressAllocated condition of the claim is set to false and a nil
+// address is returned.
+func (r *IPAddressClaimReconciler) reconcileAddress(ctx context.Context, claim *ipamv1.IPAddressClaim, request addressRequest) (*ipamv1.IPAddress, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	address := &ipamv1.IPAddress{}
+	if err := r.Client.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: request.name}, address); err == nil {
+		return address, nil
+	} else if !apierrors.IsNotFound(err) {
+		return nil, errors.Wrapf(err, "failed to get IPAddress for IPAddressClaim %s", klog.KObj(claim))
+	}
+
+	if !isInClusterPoolRef(request.poolRef) {
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.AllocationFailedReason, clusterv1.ConditionSeverityWarning,
+			"%s %s is not an in-cluster pool", request.poolRef.Kind, request.poolRef.Name)
+		return nil, nil
+	}
+
+	pool, err := r.getPool(ctx, claim.Namespace, request.poolRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolNotFoundReason, clusterv1.ConditionSeverityWarning,
+				"%s %s not found", request.poolRef.Kind, request.poolRef.Name)
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !pool.GetDeletionTimestamp().IsZero() {
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolDeletingReason, clusterv1.ConditionSeverityWarning,
+			"%s %s is being deleted", request.poolRef.Kind, request.poolRef.Name)
+		return nil, nil
+	}
+	if request.family != "" {
+		if family, err := poolFamily(pool.PoolSpec()); err != nil || family != request.family {
+			conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.AllocationFailedReason, clusterv1.ConditionSeverityWarning,
+				"%s %s has no %s addresses", request.poolRef.Kind, request.poolRef.Name, request.family)
+			return nil, nil
+		}
+	}
+
+	address, err = r.allocate(ctx, claim, request, pool)
+	if err != nil {
+		if errors.Is(err, errPoolExhausted) {
+			// The claim is reconciled again when an address of the pool is released or the pool is changed.
+			conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.PoolExhaustedReason, clusterv1.ConditionSeverityWarning,
+				"%s %s has no free addresses", request.poolRef.Kind, request.poolRef.Name)
+			return nil, nil
+		}
+		conditions.MarkFalse(claim, ipamv1.AddressAllocatedCondition, ipamv1.AllocationFailedReason, clusterv1.ConditionSeverityError, err.Error())
+		return nil, err
+	}
+	log.Info("Allocated IPAddress", "IPAddress", klog.KObj(address), "address", address.Spec.Address)
+	return address, nil
+}
+
+// allocate creates an IPAddress with the lowest free address of the pool for an address requested by a claim.
+// Allocations from the same pool are serialized, and the addresses already allocated are read from the API server,
+// so concurrent claims never get the same address.
+func (r *IPAddressClaimReconciler) allocate(ctx context.Context, claim *ipamv1.IPAddressClaim, request addressRequest, pool inClusterPool) (*ipamv1.IPAddress, error) {
+	lock, _ := r.poolLocks.LoadOrStore(poolKind(pool)+"/"+client.ObjectKeyFromObject(pool).String(), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	addresses, err := poolAddresses(ctx, r.APIReader, pool)
+	if err != nil {
+		return nil, err
+	}
+
+	inUse := map[netip.Addr]bool{}
+	for i := range addresses {
+		// Return the address already allocated for the request, if any.
+		if addresses[i].Namespace == claim.Namespace && addresses[i].Name == request.name {
+			return &addresses[i], nil
+		}
+		if addr, err := netip.ParseAddr(addresses[i].Spec.Address); err == nil {
+			inUse[addr.Unmap()] = true
+		}
+	}
+
+	set, err := poolAddressSet(pool.PoolSpec())
+	if err != nil {
+		return nil, errors.Wrapf(err, "invalid addresses in %s %s", poolKind(pool), client.ObjectKeyFromObject(pool))
+	}
+	free, ok := set.FirstFree(inUse)
+	if !ok {
+		return nil, errPoolExhausted
+	}
+
+	address := &ipamv1.IPAddress{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       request.name,
+			Namespace:  claim.Namespace,
+			Finalizers: []string{ipamv1.ProtectAddressFinalizer},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(claim, ipamv1.GroupVersion.WithKind("IPAddressClaim")),
+				{
+					APIVersion: ipamv1.GroupVersion.String(),
+					Kind:       poolKind(pool),
+					Name:       pool.GetName(),
+					UID:        pool.GetUID(),
+				},
+			},
+		},
+		Spec: ipamv1.IPAddressSpec{
+			ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+			PoolRef:  request.poolRef,
+			Address:  free.String(),
+			Prefix:   pool.PoolSpec().Prefix,
+			Gateway:  pool.PoolSpec().Gateway,
+			Family:   request.family,
+		},
+	}
+	if err := r.Client.Create(ctx, address); err != nil {
+		return nil, errors.Wrapf(err, "failed to create IPAddress %s", klog.KObj(address))
+	}
+	return address, nil
+}
+
+// reconcileDelete releases the IPAddresses allocated for a claim being deleted.
+func (r *IPAddressClaimReconciler) reconcileDelete(ctx context.Context, claim *ipamv1.IPAddressClaim) error {
+	for _, request := range addressRequests(claim) {
+		if err := r.release(ctx, claim, request.name); err != nil {
+			return err
+		}
+	}
+
+	controllerutil.RemoveFinalizer(claim, ipamv1.ReleaseAddressFinalizer)
+	return nil
+}
+
+// release deletes an IPAddress allocated for a claim.
+func (r *IPAddressClaimReconciler) release(ctx context.Context, claim *ipamv1.IPAddressClaim, name string) error {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Read the IPAddress from the API server, so an address just allocated is not leaked because of a stale cache.
+	address := &ipamv1.IPAddress{}
+	if err := r.APIReader.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: name}, address); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get IPAddress for IPAddressClaim %s", klog.KObj(claim))
+		}
+		return nil
+	}
+
+	if controllerutil.ContainsFinalizer(address, ipamv1.ProtectAddressFinalizer) {
+		addressPatchHelper, err := patch.NewHelper(address, r.Client)
+		if err != nil {
+			return err
+		}
+		controllerutil.RemoveFinalizer(address, ipamv1.ProtectAddressFinalizer)
+		if err := addressPatchHelper.Patch(ctx, address); err != nil {
+			return errors.Wrapf(err, "failed to remove finalizer from IPAddress %s", klog.KObj(address))
+		}
+	}
+	if err := r.Client.Delete(ctx, address); err != nil && !apierrors.IsNotFound(err) {
+		return errors.Wrapf(err, "failed to delete IPAddress %s", klog.KObj(address))
+	}
+	log.Info("Released IPAddress", "IPAddress", klog.KObj(address), "address", address.Spec.Address)
+	return nil
+}
+
+// getPool returns a pool referenced by a claim; InClusterIPPools must be in the namespace of the claim.
+func (r *IPAddressClaimReconciler) getPool(ctx context.Context, namespace string, ref corev1.TypedLocalObjectReference) (inClusterPool, error) {
+	var pool inClusterPool
+	key := client.ObjectKey{Name: ref.Name}
+	switch ref.Kind {
+	case ipamv1.GlobalInClusterIPPoolKind:
+		pool = &ipamv1.GlobalInClusterIPPool{}
+	default:
+		pool = &ipamv1.InClusterIPPool{}
+		key.Namespace = namespace
+	}
+	if err := r.Client.Get(ctx, key, pool); err != nil {
+		return nil, err
+	}
+	return pool, nil
+}
+
+// poolToPendingIPAddressClaims maps a pool to the claims referencing it which don't have an address yet.
+func (r *IPAddressClaimReconciler) poolToPendingIPAddressClaims(ctx context.Context, o client.Object) []ctrl.Request {
+	pool, ok := o.(inClusterPool)
+	if !ok {
+		panic(fmt.Sprintf("Expected an in-cluster pool but got a %T", o))
+	}
+	return r.pendingIPAddressClaims(ctx, pool)
+}
+
+// ipAddressToPendingIPAddressClaims maps an IPAddress to the claims which don't have an address yet and
+// reference the same pool, so they are reconciled when an address is released.
+func (r *IPAddressClaimReconciler) ipAddressToPendingIPAddressClaims(ctx context.Context, o client.Object) []ctrl.Request {
+	address, ok := o.(*ipamv1.IPAddress)
+	if !ok {
+		panic(fmt.Sprintf("Expected an IPAddress but got a %T", o))
+	}
+	if !isInClusterPoolRef(address.Spec.PoolRef) {
+		return nil
+	}
+
+	var pool inClusterPool
+	switch address.Spec.PoolRef.Kind {
+	case ipamv1.GlobalInClusterIPPoolKind:
+		pool = &ipamv1.GlobalInClusterIPPool{ObjectMeta: metav1.ObjectMeta{Name: address.Spec.PoolRef.Name}}
+	default:
+		pool = &ipamv1.InClusterIPPool{ObjectMeta: metav1.ObjectMeta{Name: address.Spec.PoolRef.Name, Namespace: address.Namespace}}
+	}
+	return r.pendingIPAddressClaims(ctx, pool)
+}
+
+// pendingIPAddressClaims returns the claims referencing a pool which don't have an address yet.
+func (r *IPAddressClaimReconciler) pendingIPAddressClaims(ctx context.Context, pool inClusterPool) []ctrl.Request {
+	log := ctrl.LoggerFrom(ctx)
+
+	claimList := &ipamv1.IPAddressClaimList{}
+	listOpts := []client.ListOption{}
+	if pool.GetNamespace() != "" {
+		listOpts = append(listOpts, client.InNamespace(pool.GetNamespace()))
+	}
+	if err := r.Client.List(ctx, claimList, listOpts...); err != nil {
+		log.Error(err, "Failed to list IPAddressClaims")
+		return nil
+	}
+
+	requests := []ctrl.Request{}
+	for _, claim := range claimList.Items {
+		if claim.Status.AddressRef.Name == "" && claimReferencesPool(&claim, pool) {
+			requests = append(requests, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(&claim)})
+		}
+	}
+	return requests
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func newFakeClient(objs ...client.Object) client.Client {
+	scheme := runtime.NewScheme()
+	_ = ipamv1.AddToScheme(scheme)
+	return fake.NewClientBuilder().
+		WithScheme(scheme).
+		WithObjects(objs...).
+		WithStatusSubresource(&ipamv1.IPAddressClaim{}, &ipamv1.InClusterIPPool{}, &ipamv1.GlobalInClusterIPPool{}).
+		Build()
+}
+
+func newClaim(namespace, name, poolKind, poolName string) *ipamv1.IPAddressClaim {
+	return &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+		Spec: ipamv1.IPAddressClaimSpec{
+			PoolRef: corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(ipamv1.GroupVersion.Group),
+				Kind:     poolKind,
+				Name:     poolName,
+			},
+		},
+	}
+}
+
+// reconcileClaim reconciles a claim until the finalizer is added and an address is allocated.
+func reconcileClaim(ctx context.Context, g *WithT, r *IPAddressClaimReconciler, claim *ipamv1.IPAddressClaim) {
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(r.Client.Get(ctx, client.ObjectKeyFromObject(claim), claim)).To(Succeed())
+}
+
+func TestIPAddressClaimReconciler(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("should allocate addresses from an InClusterIPPool until the pool is exhausted", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := &ipamv1.InClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: metav1.NamespaceDefault},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.0/29"},
+				ExcludedAddresses: []string{"10.0.0.3"},
+				Prefix:            29,
+				Gateway:           "10.0.0.1",
+			},
+		}
+		claims := []*ipamv1.IPAddressClaim{}
+		for _, name := range []string{"claim-1", "claim-2", "claim-3", "claim-4", "claim-5"} {
+			claims = append(claims, newClaim(metav1.NamespaceDefault, name, ipamv1.InClusterIPPoolKind, pool.Name))
+		}
+		c := newFakeClient(pool, claims[0], claims[1], claims[2], claims[3], claims[4])
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		// 10.0.0.0 is the network address, 10.0.0.1 the gateway and 10.0.0.3 is excluded.
+		for i, want := range []string{"10.0.0.2", "10.0.0.4", "10.0.0.5", "10.0.0.6"} {
+			reconcileClaim(ctx, g, r, claims[i])
+			g.Expect(claims[i].Finalizers).To(ContainElement(ipamv1.ReleaseAddressFinalizer))
+			g.Expect(claims[i].Status.AddressRef.Name).To(Equal(claims[i].Name))
+			g.Expect(conditions.IsTrue(claims[i], ipamv1.AddressAllocatedCondition)).To(BeTrue())
+
+			address := &ipamv1.IPAddress{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[i]), address)).To(Succeed())
+			g.Expect(address.Spec.Address).To(Equal(want))
+			g.Expect(address.Spec.Prefix).To(Equal(29))
+			g.Expect(address.Spec.Gateway).To(Equal("10.0.0.1"))
+			g.Expect(address.Spec.PoolRef).To(Equal(claims[i].Spec.PoolRef))
+			g.Expect(address.Finalizers).To(ContainElement(ipamv1.ProtectAddressFinalizer))
+			g.Expect(metav1.IsControlledBy(address, claims[i])).To(BeTrue())
+		}
+
+		// 10.0.0.7 is the broadcast address, so the pool is exhausted.
+		reconcileClaim(ctx, g, r, claims[4])
+		g.Expect(claims[4].Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.GetReason(claims[4], ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.PoolExhaustedReason))
+		g.Expect(r.pendingIPAddressClaims(ctx, pool)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claims[4])}))
+
+		// Deleting a claim releases its address, which is allocated to the pending claim.
+		g.Expect(c.Delete(ctx, claims[1])).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claims[1])})
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[1]), &ipamv1.IPAddressClaim{})).ToNot(Succeed())
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[1]), &ipamv1.IPAddress{})).ToNot(Succeed())
+
+		reconcileClaim(ctx, g, r, claims[4])
+		address := &ipamv1.IPAddress{}
+		g.Expect(c.Get(ctx, client.ObjectKeyFromObject(claims[4]), address)).To(Succeed())
+		g.Expect(address.Spec.Address).To(Equal("10.0.0.4"))
+	})
+
+	t.Run("should allocate addresses from a GlobalInClusterIPPool to claims in different namespaces", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := &ipamv1.GlobalInClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "global"},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::10-fd00::20"},
+				Prefix:    64,
+			},
+		}
+		claim1 := newClaim("ns1", "claim", ipamv1.GlobalInClusterIPPoolKind, pool.Name)
+		claim2 := newClaim("ns2", "claim", ipamv1.GlobalInClusterIPPoolKind, pool.Name)
+		c := newFakeClient(pool, claim1, claim2)
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		for _, want := range []struct {
+			claim   *ipamv1.IPAddressClaim
+			address string
+		}{{claim1, "fd00::10"}, {claim2, "fd00::11"}} {
+			reconcileClaim(ctx, g, r, want.claim)
+			address := &ipamv1.IPAddress{}
+			g.Expect(c.Get(ctx, client.ObjectKeyFromObject(want.claim), address)).To(Succeed())
+			g.Expect(address.Spec.Address).To(Equal(want.address))
+		}
+	})
+
+	t.Run("should allocate one address per family for dual-stack claims", func(t *testing.T) {
+		g := NewWithT(t)
+
+		v4Pool := &ipamv1.InClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "v4", Namespace: metav1.NamespaceDefault},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10-10.0.0.20"},
+				Prefix:    24,
+			},
+		}
+		v6Pool := &ipamv1.InClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "v6", Namespace: metav1.NamespaceDefault},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::10-fd00::20"},
+				Prefix:    64,
+			},
+		}
+		claim := newClaim(metav1.NamespaceDefault, "claim", ipamv1.InClusterIPPoolKind, v4Pool.Name)
+		claim.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+			{Family: ipamv1.IPv4Family},
+			{Family: ipamv1.IPv6Family, PoolRef: &corev1.TypedLocalObjectReference{
+				APIGroup: ptr.To(ipamv1.GroupVersion.Group),
+				Kind:     ipamv1.InClusterIPPoolKind,
+				Name:     v6Pool.Name,
+			}},
+		}
+		c := newFakeClient(claim)
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		// The claim is not bound until the addresses of all the families are allocated.
+		g.Expect(c.Create(ctx, v4Pool)).To(Succeed())
+		reconcileClaim(ctx, g, r, claim)
+		g.Expect(claim.Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.GetReason(claim, ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.PoolNotFoundReason))
+		g.Expect(r.pendingIPAddressClaims(ctx, v6Pool)).To(ConsistOf(ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)}))
+
+		g.Expect(c.Create(ctx, v6Pool)).To(Succeed())
+		reconcileClaim(ctx, g, r, claim)
+		g.Expect(conditions.IsTrue(claim, ipamv1.AddressAllocatedCondition)).To(BeTrue())
+		g.Expect(claim.Status.AddressRef.Name).To(Equal("claim-ipv4"))
+		g.Expect(claim.Status.AddressRefs).To(Equal([]ipamv1.IPAddressReference{
+			{Family: ipamv1.IPv4Family, Name: "claim-ipv4"},
+			{Family: ipamv1.IPv6Family, Name: "claim-ipv6"},
+		}))
+
+		for name, want := range map[string]ipamv1.IPAddressSpec{
+			"claim-ipv4": {Address: "10.0.0.10", Prefix: 24, Family: ipamv1.IPv4Family, PoolRef: claim.Spec.PoolRef},
+			"claim-ipv6": {Address: "fd00::10", Prefix: 64, Family: ipamv1.IPv6Family, PoolRef: *claim.Spec.AddressFamilies[1].PoolRef},
+		} {
+			address := &ipamv1.IPAddress{}
+			g.Expect(c.Get(ctx, client.ObjectKey{Namespace: claim.Namespace, Name: name}, address)).To(Succeed())
+			want.ClaimRef = corev1.LocalObjectReference{Name: claim.Name}
+			g.Expect(address.Spec).To(Equal(want))
+		}
+
+		// Deleting the claim releases the addresses of all the families.
+		g.Expect(c.Delete(ctx, claim)).To(Succeed())
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(claim)})
+		g.Expect(err).ToNot(HaveOccurred())
+		addresses := &ipamv1.IPAddressList{}
+		g.Expect(c.List(ctx, addresses)).To(Succeed())
+		g.Expect(addresses.Items).To(BeEmpty())
+	})
+
+	t.Run("should report pools without addresses of the requested family", func(t *testing.T) {
+		g := NewWithT(t)
+
+		pool := &ipamv1.InClusterIPPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "v4", Namespace: metav1.NamespaceDefault},
+			Spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10-10.0.0.20"},
+				Prefix:    24,
+			},
+		}
+		claim := newClaim(metav1.NamespaceDefault, "claim", ipamv1.InClusterIPPoolKind, pool.Name)
+		claim.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{{Family: ipamv1.IPv6Family}}
+		c := newFakeClient(pool, claim)
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		reconcileClaim(ctx, g, r, claim)
+		g.Expect(claim.Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.GetReason(claim, ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.AllocationFailedReason))
+	})
+
+	t.Run("should report claims referencing a pool which doesn't exist", func(t *testing.T) {
+		g := NewWithT(t)
+
+		claim := newClaim(metav1.NamespaceDefault, "claim", ipamv1.InClusterIPPoolKind, "missing")
+		c := newFakeClient(claim)
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		reconcileClaim(ctx, g, r, claim)
+		g.Expect(claim.Status.AddressRef.Name).To(BeEmpty())
+		g.Expect(conditions.GetReason(claim, ipamv1.AddressAllocatedCondition)).To(Equal(ipamv1.PoolNotFoundReason))
+	})
+
+	t.Run("should ignore claims for pools of other providers", func(t *testing.T) {
+		g := NewWithT(t)
+
+		claim := newClaim(metav1.NamespaceDefault, "claim", "OtherPool", "pool")
+		c := newFakeClient(claim)
+		r := &IPAddressClaimReconciler{Client: c, APIReader: c}
+
+		reconcileClaim(ctx, g, r, claim)
+		g.Expect(claim.Finalizers).To(BeEmpty())
+		g.Expect(claim.Status.Conditions).To(BeEmpty())
+	})
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"net/netip"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/iprange"
+)
+
+// inClusterPool is implemented by InClusterIPPool and GlobalInClusterIPPool.
+type inClusterPool interface {
+	client.Object
+	PoolSpec() *ipamv1.InClusterIPPoolSpec
+	PoolStatus() *ipamv1.InClusterIPPoolStatus
+}
+
+// poolKind returns the kind of an in-cluster pool.
+func poolKind(pool inClusterPool) string {
+	if _, ok := pool.(*ipamv1.GlobalInClusterIPPool); ok {
+		return ipamv1.GlobalInClusterIPPoolKind
+	}
+	return ipamv1.InClusterIPPoolKind
+}
+
+// isInClusterPoolRef returns true if the reference is to an InClusterIPPool or a GlobalInClusterIPPool.
+func isInClusterPoolRef(ref corev1.TypedLocalObjectReference) bool {
+	return ref.APIGroup != nil && *ref.APIGroup == ipamv1.GroupVersion.Group &&
+		(ref.Kind == ipamv1.InClusterIPPoolKind || ref.Kind == ipamv1.GlobalInClusterIPPoolKind)
+}
+
+// isPoolRef returns true if the reference is to the given pool.
+func isPoolRef(ref corev1.TypedLocalObjectReference, pool inClusterPool) bool {
+	return isInClusterPoolRef(ref) && ref.Kind == poolKind(pool) && ref.Name == pool.GetName()
+}
+
+// claimReferencesPool returns true if any of the pools a claim allocates addresses from is the given pool.
+func claimReferencesPool(claim *ipamv1.IPAddressClaim, pool inClusterPool) bool {
+	if isPoolRef(claim.Spec.PoolRef, pool) {
+		return true
+	}
+	for _, f := range claim.Spec.AddressFamilies {
+		if f.PoolRef != nil && isPoolRef(*f.PoolRef, pool) {
+			return true
+		}
+	}
+	return false
+}
+
+// poolFamily returns the family of the addresses of a pool; all the addresses of a pool are of the same family.
+func poolFamily(spec *ipamv1.InClusterIPPoolSpec) (ipamv1.IPAddressFamily, error) {
+	set, err := iprange.ParseSet(spec.Addresses)
+	if err != nil {
+		return "", err
+	}
+	ranges := set.Ranges()
+	if len(ranges) == 0 {
+		return "", errors.New("the pool has no addresses")
+	}
+	if ranges[0].From.Is4() {
+		return ipamv1.IPv4Family, nil
+	}
+	return ipamv1.IPv6Family, nil
+}
+
+// poolAddresses returns the IPAddresses allocated from a pool; addresses from an InClusterIPPool are in the namespace
+// of the pool, while addresses from a GlobalInClusterIPPool can be in any namespace.
+func poolAddresses(ctx context.Context, c client.Reader, pool inClusterPool) ([]ipamv1.IPAddress, error) {
+	addressList := &ipamv1.IPAddressList{}
+	listOpts := []client.ListOption{}
+	if pool.GetNamespace() != "" {
+		listOpts = append(listOpts, client.InNamespace(pool.GetNamespace()))
+	}
+	if err := c.List(ctx, addressList, listOpts...); err != nil {
+		return nil, errors.Wrapf(err, "failed to list IPAddresses of %s %s", poolKind(pool), client.ObjectKeyFromObject(pool))
+	}
+
+	addresses := []ipamv1.IPAddress{}
+	for _, address := range addressList.Items {
+		if isPoolRef(address.Spec.PoolRef, pool) {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses, nil
+}
+
+// poolAddressSet returns the addresses which can be allocated from a pool: the addresses of the pool except
+// the excluded addresses, the gateway and, unless allowed, the reserved addresses of the networks of the pool.
+func poolAddressSet(spec *ipamv1.InClusterIPPoolSpec) (*iprange.Set, error) {
+	set, err := iprange.ParseSet(spec.Addresses)
+	if err != nil {
+		return nil, err
+	}
+
+	excluded, err := iprange.ParseSet(spec.ExcludedAddresses)
+	if err != nil {
+		return nil, err
+	}
+	if spec.Gateway != "" {
+		gateway, err := iprange.Parse(spec.Gateway)
+		if err != nil {
+			return nil, err
+		}
+		excluded.Add(gateway)
+	}
+	if !spec.AllocateReservedIPAddresses {
+		for _, r := range set.Ranges() {
+			for _, addr := range []netip.Addr{r.From, r.To} {
+				for _, reserved := range reservedAddresses(addr, spec.Prefix) {
+					excluded.Add(iprange.Range{From: reserved, To: reserved})
+				}
+			}
+		}
+	}
+
+	for _, r := range excluded.Ranges() {
+		set.Remove(r)
+	}
+	return set, nil
+}
+
+// reservedAddresses returns the reserved addresses of the network of an address: the network and broadcast
+// addresses for IPv4, and the subnet-router anycast address for IPv6.
+func reservedAddresses(addr netip.Addr, prefixLength int) []netip.Addr {
+	prefix, err := addr.Prefix(prefixLength)
+	if err != nil {
+		return nil
+	}
+	network := iprange.PrefixRange(prefix)
+	switch {
+	case addr.Is4() && prefixLength < 31:
+		return []netip.Addr{network.From, network.To}
+	case addr.Is6() && prefixLength < 127:
+		return []netip.Addr{network.From}
+	default:
+		return nil
+	}
+}
+
+// addressesSummary summarizes the usage of the addresses of a pool.
+func addressesSummary(set *iprange.Set, addresses []ipamv1.IPAddress) *ipamv1.IPPoolAddressesSummary {
+	summary := &ipamv1.IPPoolAddressesSummary{Total: set.Size()}
+	for _, address := range addresses {
+		addr, err := netip.ParseAddr(address.Spec.Address)
+		if err != nil || !set.Contains(addr) {
+			summary.OutOfRange++
+			continue
+		}
+		summary.Used++
+	}
+	summary.Free = summary.Total - summary.Used
+	return summary
+}
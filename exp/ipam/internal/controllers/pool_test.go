@@ -0,0 +1,149 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controllers
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+)
+
+func TestPoolAddressSet(t *testing.T) {
+	tests := []struct {
+		name string
+		spec ipamv1.InClusterIPPoolSpec
+		want []string
+	}{
+		{
+			name: "should exclude the gateway, the excluded addresses and the reserved IPv4 addresses",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.0/29", "10.0.1.250-10.0.1.255"},
+				ExcludedAddresses: []string{"10.0.0.4-10.0.0.5"},
+				Prefix:            24,
+				Gateway:           "10.0.0.1",
+			},
+			want: []string{"10.0.0.2-10.0.0.3", "10.0.0.6-10.0.0.7", "10.0.1.250-10.0.1.254"},
+		},
+		{
+			name: "should allocate the reserved addresses if allowed",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:                   []string{"10.0.0.0/30"},
+				Prefix:                      30,
+				AllocateReservedIPAddresses: true,
+			},
+			want: []string{"10.0.0.0-10.0.0.3"},
+		},
+		{
+			name: "should exclude the anycast IPv6 address",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::/126"},
+				Prefix:    64,
+			},
+			want: []string{"fd00::1-fd00::3"},
+		},
+		{
+			name: "should not exclude reserved addresses of point-to-point networks",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.0/31"},
+				Prefix:    31,
+			},
+			want: []string{"10.0.0.0-10.0.0.1"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			set, err := poolAddressSet(&tt.spec)
+			g.Expect(err).ToNot(HaveOccurred())
+			got := []string{}
+			for _, r := range set.Ranges() {
+				got = append(got, r.String())
+			}
+			g.Expect(got).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestReconcilePool(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	pool := &ipamv1.InClusterIPPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "pool", Namespace: metav1.NamespaceDefault},
+		Spec: ipamv1.InClusterIPPoolSpec{
+			Addresses: []string{"10.0.0.10-10.0.0.19"},
+			Prefix:    24,
+		},
+	}
+	newAddress := func(namespace, name, poolKind, address string) *ipamv1.IPAddress {
+		return &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: namespace},
+			Spec: ipamv1.IPAddressSpec{
+				ClaimRef: corev1.LocalObjectReference{Name: name},
+				PoolRef:  corev1.TypedLocalObjectReference{APIGroup: ptr.To(ipamv1.GroupVersion.Group), Kind: poolKind, Name: pool.Name},
+				Address:  address,
+				Prefix:   24,
+			},
+		}
+	}
+	c := newFakeClient(pool,
+		newAddress(metav1.NamespaceDefault, "in-range", ipamv1.InClusterIPPoolKind, "10.0.0.10"),
+		newAddress(metav1.NamespaceDefault, "out-of-range", ipamv1.InClusterIPPoolKind, "10.0.0.30"),
+		// Addresses of other pools are not counted.
+		newAddress(metav1.NamespaceDefault, "other-kind", ipamv1.GlobalInClusterIPPoolKind, "10.0.0.11"),
+		newAddress("other", "other-namespace", ipamv1.InClusterIPPoolKind, "10.0.0.12"),
+	)
+	r := &InClusterIPPoolReconciler{Client: c}
+
+	for i := 0; i < 2; i++ {
+		_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+		g.Expect(err).ToNot(HaveOccurred())
+	}
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+	g.Expect(pool.Finalizers).To(ContainElement(ipamv1.ProtectPoolFinalizer))
+	g.Expect(pool.Status.Addresses).To(Equal(&ipamv1.IPPoolAddressesSummary{Total: 10, Used: 1, Free: 9, OutOfRange: 1}))
+
+	// The pool is not deleted until all its addresses are released.
+	g.Expect(c.Delete(ctx, pool)).To(Succeed())
+	_, err := r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), pool)).To(Succeed())
+
+	g.Expect(c.DeleteAllOf(ctx, &ipamv1.IPAddress{}, client.InNamespace(metav1.NamespaceDefault))).To(Succeed())
+	_, err = r.Reconcile(ctx, ctrl.Request{NamespacedName: client.ObjectKeyFromObject(pool)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(pool), pool)).ToNot(Succeed())
+}
+
+func TestAddressesSummaryLargePool(t *testing.T) {
+	g := NewWithT(t)
+
+	set, err := poolAddressSet(&ipamv1.InClusterIPPoolSpec{Addresses: []string{"fd00::/64"}, Prefix: 64})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(addressesSummary(set, nil)).To(Equal(&ipamv1.IPPoolAddressesSummary{Total: math.MaxInt, Free: math.MaxInt}))
+}
@@ -0,0 +1,204 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package iprange implements sets of IP addresses defined by single addresses, ranges and CIDRs.
+package iprange
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"net/netip"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// Range is an inclusive range of IP addresses of the same family.
+type Range struct {
+	From netip.Addr
+	To   netip.Addr
+}
+
+// Contains returns true if the range contains the address.
+func (r Range) Contains(addr netip.Addr) bool {
+	return r.From.Compare(addr) <= 0 && addr.Compare(r.To) <= 0
+}
+
+// String returns the string representation of the range.
+func (r Range) String() string {
+	if r.From == r.To {
+		return r.From.String()
+	}
+	return fmt.Sprintf("%s-%s", r.From, r.To)
+}
+
+// Parse parses a single address (e.g. 10.0.0.10), a range (e.g. 10.0.0.10-10.0.0.20) or a CIDR (e.g. 10.0.0.0/28).
+func Parse(s string) (Range, error) {
+	s = strings.TrimSpace(s)
+	switch {
+	case strings.Contains(s, "-"):
+		from, to, _ := strings.Cut(s, "-")
+		fromAddr, err := netip.ParseAddr(strings.TrimSpace(from))
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid range %q", s)
+		}
+		toAddr, err := netip.ParseAddr(strings.TrimSpace(to))
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid range %q", s)
+		}
+		if fromAddr.Is4() != toAddr.Is4() {
+			return Range{}, errors.Errorf("invalid range %q: addresses must be of the same IP family", s)
+		}
+		if fromAddr.Compare(toAddr) > 0 {
+			return Range{}, errors.Errorf("invalid range %q: the first address must not be greater than the last address", s)
+		}
+		return Range{From: fromAddr.Unmap(), To: toAddr.Unmap()}, nil
+	case strings.Contains(s, "/"):
+		prefix, err := netip.ParsePrefix(s)
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid CIDR %q", s)
+		}
+		return PrefixRange(prefix), nil
+	default:
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return Range{}, errors.Wrapf(err, "invalid address %q", s)
+		}
+		return Range{From: addr.Unmap(), To: addr.Unmap()}, nil
+	}
+}
+
+// PrefixRange returns the range of all the addresses of a prefix, including the network and broadcast addresses.
+func PrefixRange(prefix netip.Prefix) Range {
+	prefix = prefix.Masked()
+	from := prefix.Addr()
+	bytes := from.As16()
+	hostBits := from.BitLen() - prefix.Bits()
+	for i := 15; i >= 0 && hostBits > 0; i-- {
+		bits := min(hostBits, 8)
+		bytes[i] |= byte(1<<bits - 1)
+		hostBits -= bits
+	}
+	to := netip.AddrFrom16(bytes)
+	if from.Is4() {
+		to = to.Unmap()
+	}
+	return Range{From: from, To: to}
+}
+
+// Set is a set of IP addresses, stored as sorted and non-overlapping ranges.
+type Set struct {
+	ranges []Range
+}
+
+// ParseSet parses a list of single addresses, ranges and CIDRs into a Set.
+func ParseSet(entries []string) (*Set, error) {
+	s := &Set{}
+	for _, entry := range entries {
+		r, err := Parse(entry)
+		if err != nil {
+			return nil, err
+		}
+		s.Add(r)
+	}
+	return s, nil
+}
+
+// Ranges returns the ranges of the set.
+func (s *Set) Ranges() []Range {
+	return s.ranges
+}
+
+// Add adds a range to the set.
+func (s *Set) Add(r Range) {
+	ranges := append(s.ranges, r)
+	sort.Slice(ranges, func(i, j int) bool {
+		return ranges[i].From.Less(ranges[j].From)
+	})
+
+	merged := []Range{}
+	for _, current := range ranges {
+		if len(merged) > 0 {
+			last := &merged[len(merged)-1]
+			// Merge overlapping or adjacent ranges of the same family.
+			if last.To.Is4() == current.From.Is4() && (current.From.Compare(last.To) <= 0 || current.From == last.To.Next()) {
+				if current.To.Compare(last.To) > 0 {
+					last.To = current.To
+				}
+				continue
+			}
+		}
+		merged = append(merged, current)
+	}
+	s.ranges = merged
+}
+
+// Remove removes a range from the set.
+func (s *Set) Remove(r Range) {
+	result := []Range{}
+	for _, current := range s.ranges {
+		if current.To.Is4() != r.From.Is4() || current.To.Compare(r.From) < 0 || current.From.Compare(r.To) > 0 {
+			result = append(result, current)
+			continue
+		}
+		if current.From.Compare(r.From) < 0 {
+			result = append(result, Range{From: current.From, To: r.From.Prev()})
+		}
+		if current.To.Compare(r.To) > 0 {
+			result = append(result, Range{From: r.To.Next(), To: current.To})
+		}
+	}
+	s.ranges = result
+}
+
+// Contains returns true if the set contains the address.
+func (s *Set) Contains(addr netip.Addr) bool {
+	addr = addr.Unmap()
+	for _, r := range s.ranges {
+		if r.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// Size returns the number of addresses in the set, capped to math.MaxInt.
+func (s *Set) Size() int {
+	size := big.NewInt(0)
+	for _, r := range s.ranges {
+		from := new(big.Int).SetBytes(r.From.AsSlice())
+		to := new(big.Int).SetBytes(r.To.AsSlice())
+		size.Add(size, to.Sub(to, from).Add(to, big.NewInt(1)))
+	}
+	if !size.IsInt64() || size.Int64() > math.MaxInt {
+		return math.MaxInt
+	}
+	return int(size.Int64())
+}
+
+// FirstFree returns the lowest address of the set which is not in use.
+func (s *Set) FirstFree(inUse map[netip.Addr]bool) (netip.Addr, bool) {
+	for _, r := range s.ranges {
+		for addr := r.From; addr.IsValid() && addr.Compare(r.To) <= 0; addr = addr.Next() {
+			if !inUse[addr] {
+				return addr, true
+			}
+		}
+	}
+	return netip.Addr{}, false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iprange
+
+import (
+	"math"
+	"net/netip"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    string
+		wantErr bool
+	}{
+		{name: "single address", input: "10.0.0.10", want: "10.0.0.10"},
+		{name: "range", input: "10.0.0.10-10.0.0.20", want: "10.0.0.10-10.0.0.20"},
+		{name: "IPv4 CIDR", input: "10.0.0.0/28", want: "10.0.0.0-10.0.0.15"},
+		{name: "unmasked IPv4 CIDR", input: "10.0.0.5/30", want: "10.0.0.4-10.0.0.7"},
+		{name: "IPv6 CIDR", input: "fd00::/120", want: "fd00::-fd00::ff"},
+		{name: "IPv4 CIDR not aligned to bytes", input: "10.0.0.0/23", want: "10.0.0.0-10.0.1.255"},
+		{name: "invalid address", input: "10.0.0.300", wantErr: true},
+		{name: "range with mixed families", input: "10.0.0.1-fd00::1", wantErr: true},
+		{name: "reversed range", input: "10.0.0.20-10.0.0.10", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			r, err := Parse(tt.input)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(r.String()).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSet(t *testing.T) {
+	g := NewWithT(t)
+
+	s, err := ParseSet([]string{"10.0.0.10-10.0.0.20", "10.0.0.0/29", "10.0.0.8", "10.0.0.15"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(s.Ranges()).To(HaveLen(2))
+	g.Expect(s.Ranges()[0].String()).To(Equal("10.0.0.0-10.0.0.8"))
+	g.Expect(s.Ranges()[1].String()).To(Equal("10.0.0.10-10.0.0.20"))
+	g.Expect(s.Size()).To(Equal(20))
+
+	s.Remove(Range{From: netip.MustParseAddr("10.0.0.0"), To: netip.MustParseAddr("10.0.0.1")})
+	s.Remove(Range{From: netip.MustParseAddr("10.0.0.12"), To: netip.MustParseAddr("10.0.0.12")})
+	g.Expect(s.Size()).To(Equal(17))
+	g.Expect(s.Contains(netip.MustParseAddr("10.0.0.1"))).To(BeFalse())
+	g.Expect(s.Contains(netip.MustParseAddr("10.0.0.12"))).To(BeFalse())
+	g.Expect(s.Contains(netip.MustParseAddr("10.0.0.13"))).To(BeTrue())
+	g.Expect(s.Contains(netip.MustParseAddr("::ffff:10.0.0.13"))).To(BeTrue())
+
+	free, ok := s.FirstFree(map[netip.Addr]bool{
+		netip.MustParseAddr("10.0.0.2"): true,
+		netip.MustParseAddr("10.0.0.3"): true,
+	})
+	g.Expect(ok).To(BeTrue())
+	g.Expect(free).To(Equal(netip.MustParseAddr("10.0.0.4")))
+
+	small, err := ParseSet([]string{"10.0.0.1-10.0.0.2"})
+	g.Expect(err).ToNot(HaveOccurred())
+	_, ok = small.FirstFree(map[netip.Addr]bool{
+		netip.MustParseAddr("10.0.0.1"): true,
+		netip.MustParseAddr("10.0.0.2"): true,
+	})
+	g.Expect(ok).To(BeFalse())
+
+	huge, err := ParseSet([]string{"fd00::/8"})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(huge.Size()).To(Equal(math.MaxInt))
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"fmt"
+	"net/netip"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/webhook"
+	"sigs.k8s.io/controller-runtime/pkg/webhook/admission"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/exp/ipam/internal/iprange"
+)
+
+// SetupWebhookWithManager sets up InClusterIPPool webhooks.
+func (webhook *InClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&ipamv1.InClusterIPPool{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ipam-cluster-x-k8s-io-v1beta1-inclusterippool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=ipam.cluster.x-k8s.io,resources=inclusterippools,versions=v1beta1,name=validation.inclusterippool.ipam.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// InClusterIPPool implements a validating webhook for InClusterIPPool.
+type InClusterIPPool struct {
+}
+
+var _ webhook.CustomValidator = &InClusterIPPool{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pool, ok := obj.(*ipamv1.InClusterIPPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected an InClusterIPPool but got a %T", obj))
+	}
+	return nil, validateInClusterIPPoolSpec(&pool.Spec).ToAggregate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return webhook.ValidateCreate(ctx, newObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (webhook *InClusterIPPool) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// SetupWebhookWithManager sets up GlobalInClusterIPPool webhooks.
+func (webhook *GlobalInClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return ctrl.NewWebhookManagedBy(mgr).
+		For(&ipamv1.GlobalInClusterIPPool{}).
+		WithValidator(webhook).
+		Complete()
+}
+
+// +kubebuilder:webhook:verbs=create;update,path=/validate-ipam-cluster-x-k8s-io-v1beta1-globalinclusterippool,mutating=false,failurePolicy=fail,matchPolicy=Equivalent,groups=ipam.cluster.x-k8s.io,resources=globalinclusterippools,versions=v1beta1,name=validation.globalinclusterippool.ipam.cluster.x-k8s.io,sideEffects=None,admissionReviewVersions=v1;v1beta1
+
+// GlobalInClusterIPPool implements a validating webhook for GlobalInClusterIPPool.
+type GlobalInClusterIPPool struct {
+}
+
+var _ webhook.CustomValidator = &GlobalInClusterIPPool{}
+
+// ValidateCreate implements webhook.CustomValidator.
+func (webhook *GlobalInClusterIPPool) ValidateCreate(_ context.Context, obj runtime.Object) (admission.Warnings, error) {
+	pool, ok := obj.(*ipamv1.GlobalInClusterIPPool)
+	if !ok {
+		return nil, apierrors.NewBadRequest(fmt.Sprintf("expected a GlobalInClusterIPPool but got a %T", obj))
+	}
+	return nil, validateInClusterIPPoolSpec(&pool.Spec).ToAggregate()
+}
+
+// ValidateUpdate implements webhook.CustomValidator.
+func (webhook *GlobalInClusterIPPool) ValidateUpdate(ctx context.Context, _, newObj runtime.Object) (admission.Warnings, error) {
+	return webhook.ValidateCreate(ctx, newObj)
+}
+
+// ValidateDelete implements webhook.CustomValidator.
+func (webhook *GlobalInClusterIPPool) ValidateDelete(_ context.Context, _ runtime.Object) (admission.Warnings, error) {
+	return nil, nil
+}
+
+// validateInClusterIPPoolSpec validates that all the addresses of a pool are valid and of the same IP family,
+// and that they are part of the network defined by the gateway and the prefix.
+func validateInClusterIPPoolSpec(spec *ipamv1.InClusterIPPoolSpec) field.ErrorList {
+	allErrs := field.ErrorList{}
+	specPath := field.NewPath("spec")
+
+	var network *netip.Prefix
+	is4 := true
+	if spec.Gateway != "" {
+		gateway, err := netip.ParseAddr(spec.Gateway)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("gateway"), spec.Gateway, "not a valid IP address"))
+		} else {
+			gateway = gateway.Unmap()
+			is4 = gateway.Is4()
+			if prefix, err := gateway.Prefix(spec.Prefix); err == nil {
+				network = &prefix
+			}
+		}
+	}
+
+	// The IP family of the pool is the family of the gateway, or of the first address if the gateway is not set.
+	familyKnown := spec.Gateway != ""
+	for i, address := range spec.Addresses {
+		r, err := iprange.Parse(address)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("addresses").Index(i), address, err.Error()))
+			continue
+		}
+		if !familyKnown {
+			is4 = r.From.Is4()
+			familyKnown = true
+		}
+		if r.From.Is4() != is4 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("addresses").Index(i), address, "addresses must be of the same IP family as the gateway and the other addresses"))
+			continue
+		}
+		if network != nil && (!network.Contains(r.From) || !network.Contains(r.To)) {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("addresses").Index(i), address, fmt.Sprintf("addresses must be part of the network %s", network)))
+		}
+	}
+
+	for i, address := range spec.ExcludedAddresses {
+		r, err := iprange.Parse(address)
+		if err != nil {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("excludedAddresses").Index(i), address, err.Error()))
+			continue
+		}
+		if r.From.Is4() != is4 {
+			allErrs = append(allErrs, field.Invalid(specPath.Child("excludedAddresses").Index(i), address, "addresses must be of the same IP family as the addresses of the pool"))
+		}
+	}
+
+	if is4 && spec.Prefix > 32 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), spec.Prefix, "prefix is too large for an IPv4 address"))
+	}
+	if spec.Prefix < 0 || spec.Prefix > 128 {
+		allErrs = append(allErrs, field.Invalid(specPath.Child("prefix"), spec.Prefix, "prefix must be between 0 and 128"))
+	}
+
+	return allErrs
+}
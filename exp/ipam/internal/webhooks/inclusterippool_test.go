@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package webhooks
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+)
+
+func TestInClusterIPPoolValidate(t *testing.T) {
+	tests := []struct {
+		name      string
+		spec      ipamv1.InClusterIPPoolSpec
+		expectErr string
+	}{
+		{
+			name: "should accept a valid IPv4 pool",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.10", "10.0.0.20-10.0.0.30", "10.0.0.128/25"},
+				ExcludedAddresses: []string{"10.0.0.200"},
+				Prefix:            24,
+				Gateway:           "10.0.0.1",
+			},
+		},
+		{
+			name: "should accept a valid IPv6 pool without gateway",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"fd00::10-fd00::20"},
+				Prefix:    64,
+			},
+		},
+		{
+			name: "should reject invalid addresses",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.300"},
+				Prefix:    24,
+			},
+			expectErr: "spec.addresses[0]",
+		},
+		{
+			name: "should reject addresses of different IP families",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10", "fd00::10"},
+				Prefix:    24,
+			},
+			expectErr: "addresses must be of the same IP family",
+		},
+		{
+			name: "should reject addresses outside of the network of the gateway",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.1.10"},
+				Prefix:    24,
+				Gateway:   "10.0.0.1",
+			},
+			expectErr: "addresses must be part of the network 10.0.0.0/24",
+		},
+		{
+			name: "should reject an invalid gateway",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10"},
+				Prefix:    24,
+				Gateway:   "gateway",
+			},
+			expectErr: "spec.gateway",
+		},
+		{
+			name: "should reject excluded addresses of a different IP family",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses:         []string{"10.0.0.10"},
+				ExcludedAddresses: []string{"fd00::10"},
+				Prefix:            24,
+			},
+			expectErr: "spec.excludedAddresses[0]",
+		},
+		{
+			name: "should reject a prefix too large for IPv4",
+			spec: ipamv1.InClusterIPPoolSpec{
+				Addresses: []string{"10.0.0.10"},
+				Prefix:    64,
+			},
+			expectErr: "prefix is too large for an IPv4 address",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			for _, pool := range []struct {
+				validate func() error
+			}{
+				{validate: func() error {
+					_, err := (&InClusterIPPool{}).ValidateCreate(context.Background(), &ipamv1.InClusterIPPool{Spec: tt.spec})
+					return err
+				}},
+				{validate: func() error {
+					_, err := (&GlobalInClusterIPPool{}).ValidateUpdate(context.Background(), &ipamv1.GlobalInClusterIPPool{}, &ipamv1.GlobalInClusterIPPool{Spec: tt.spec})
+					return err
+				}},
+			} {
+				err := pool.validate()
+				if tt.expectErr != "" {
+					g.Expect(err).To(HaveOccurred())
+					g.Expect(err.Error()).To(ContainSubstring(tt.expectErr))
+				} else {
+					g.Expect(err).ToNot(HaveOccurred())
+				}
+			}
+		})
+	}
+}
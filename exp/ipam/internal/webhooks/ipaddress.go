@@ -124,14 +124,31 @@ func (webhook *IPAddress) validate(ctx context.Context, ip *ipamv1.IPAddress) er
 			))
 	}
 
+	if ip.Spec.Family != "" && addr.IsValid() && addressFamily(addr) != ip.Spec.Family {
+		allErrs = append(allErrs,
+			field.Invalid(
+				specPath.Child("family"),
+				ip.Spec.Family,
+				fmt.Sprintf("does not match the family of address %s", ip.Spec.Address),
+			))
+	}
+
 	if ip.Spec.Gateway != "" {
-		if _, err := netip.ParseAddr(ip.Spec.Gateway); err != nil {
+		gateway, err := netip.ParseAddr(ip.Spec.Gateway)
+		if err != nil {
 			allErrs = append(allErrs,
 				field.Invalid(
 					specPath.Child("gateway"),
 					ip.Spec.Gateway,
 					"not a valid IP address",
 				))
+		} else if addr.IsValid() && addressFamily(gateway) != addressFamily(addr) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					specPath.Child("gateway"),
+					ip.Spec.Gateway,
+					"the gateway and the address must be of the same family",
+				))
 		}
 	}
 
@@ -155,18 +172,48 @@ func (webhook *IPAddress) validate(ctx context.Context, ip *ipamv1.IPAddress) er
 		)
 	}
 
-	if claim.Name != "" && // only report non-matching pool if the claim exists
-		!(ip.Spec.PoolRef.APIGroup != nil && claim.Spec.PoolRef.APIGroup != nil &&
-			*ip.Spec.PoolRef.APIGroup == *claim.Spec.PoolRef.APIGroup &&
-			ip.Spec.PoolRef.Kind == claim.Spec.PoolRef.Kind &&
-			ip.Spec.PoolRef.Name == claim.Spec.PoolRef.Name) {
-		allErrs = append(allErrs,
-			field.Invalid(
-				specPath.Child("poolRef"),
-				ip.Spec.PoolRef,
-				"the referenced pool is different from the pool referenced by the claim this address should fulfill",
-			))
+	// Only report non-matching pool and family if the claim exists.
+	if claim.Name != "" {
+		poolRef := claim.Spec.PoolRefForFamily(ip.Spec.Family)
+		if !(ip.Spec.PoolRef.APIGroup != nil && poolRef.APIGroup != nil &&
+			*ip.Spec.PoolRef.APIGroup == *poolRef.APIGroup &&
+			ip.Spec.PoolRef.Kind == poolRef.Kind &&
+			ip.Spec.PoolRef.Name == poolRef.Name) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					specPath.Child("poolRef"),
+					ip.Spec.PoolRef,
+					"the referenced pool is different from the pool referenced by the claim this address should fulfill",
+				))
+		}
+
+		if len(claim.Spec.AddressFamilies) > 0 && !claimRequestsFamily(claim, ip.Spec.Family) {
+			allErrs = append(allErrs,
+				field.Invalid(
+					specPath.Child("family"),
+					ip.Spec.Family,
+					"must be one of the families requested by the claim this address should fulfill",
+				))
+		}
 	}
 
 	return allErrs.ToAggregate()
 }
+
+// addressFamily returns the family of an address.
+func addressFamily(addr netip.Addr) ipamv1.IPAddressFamily {
+	if addr.Unmap().Is4() {
+		return ipamv1.IPv4Family
+	}
+	return ipamv1.IPv6Family
+}
+
+// claimRequestsFamily returns true if a claim requests an address of the given family.
+func claimRequestsFamily(claim *ipamv1.IPAddressClaim, family ipamv1.IPAddressFamily) bool {
+	for _, f := range claim.Spec.AddressFamilies {
+		if f.Family == family {
+			return true
+		}
+	}
+	return false
+}
@@ -50,6 +50,20 @@ func TestIPAddressValidateCreate(t *testing.T) {
 		},
 	}
 
+	dualStackClaim := claim.DeepCopy()
+	dualStackClaim.Name = "dual-stack-claim"
+	dualStackClaim.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+		{Family: ipamv1.IPv4Family},
+		{
+			Family: ipamv1.IPv6Family,
+			PoolRef: &corev1.TypedLocalObjectReference{
+				Kind:     "TestPool",
+				Name:     "pool-v6",
+				APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+			},
+		},
+	}
+
 	getAddress := func(v6 bool, fn func(addr *ipamv1.IPAddress)) ipamv1.IPAddress {
 		addr := ipamv1.IPAddress{
 			ObjectMeta: metav1.ObjectMeta{
@@ -146,6 +160,58 @@ func TestIPAddressValidateCreate(t *testing.T) {
 			extraObjs: []client.Object{claim},
 			expectErr: true,
 		},
+		{
+			name: "a gateway of a different family should be rejected",
+			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
+				addr.Spec.Gateway = "42::ffff"
+			}),
+			extraObjs: []client.Object{claim},
+			expectErr: true,
+		},
+		{
+			name: "a family that does not match the address should be rejected",
+			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
+				addr.Spec.Family = ipamv1.IPv6Family
+			}),
+			extraObjs: []client.Object{claim},
+			expectErr: true,
+		},
+		{
+			name: "an IPv6 address from the pool of the IPv6 family of the claim should be accepted",
+			ip: getAddress(true, func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = dualStackClaim.Name
+				addr.Spec.PoolRef = *dualStackClaim.Spec.AddressFamilies[1].PoolRef
+				addr.Spec.Family = ipamv1.IPv6Family
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: false,
+		},
+		{
+			name: "an IPv4 address from the default pool of the claim should be accepted",
+			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = dualStackClaim.Name
+				addr.Spec.Family = ipamv1.IPv4Family
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: false,
+		},
+		{
+			name: "an IPv6 address from the default pool of the claim should be rejected",
+			ip: getAddress(true, func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = dualStackClaim.Name
+				addr.Spec.Family = ipamv1.IPv6Family
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: true,
+		},
+		{
+			name: "an address without a family should be rejected for a claim requesting families",
+			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
+				addr.Spec.ClaimRef.Name = dualStackClaim.Name
+			}),
+			extraObjs: []client.Object{dualStackClaim},
+			expectErr: true,
+		},
 		{
 			name: "a pool reference that does not contain a group should be rejected",
 			ip: getAddress(false, func(addr *ipamv1.IPAddress) {
@@ -61,7 +61,41 @@ func (webhook *IPAddressClaim) ValidateCreate(_ context.Context, obj runtime.Obj
 			"the pool reference needs to contain a group")
 	}
 
-	return nil, nil
+	return nil, validateAddressFamilies(claim).ToAggregate()
+}
+
+// validateAddressFamilies validates the address families requested by a claim. All the pools must have the
+// group of spec.poolRef, so a single IPAM provider is responsible for the claim.
+func validateAddressFamilies(claim *ipamv1.IPAddressClaim) field.ErrorList {
+	allErrs := field.ErrorList{}
+	fldPath := field.NewPath("spec", "addressFamilies")
+	families := map[ipamv1.IPAddressFamily]bool{}
+	for i, f := range claim.Spec.AddressFamilies {
+		switch f.Family {
+		case ipamv1.IPv4Family, ipamv1.IPv6Family:
+		default:
+			allErrs = append(allErrs, field.NotSupported(fldPath.Index(i).Child("family"), f.Family, []string{string(ipamv1.IPv4Family), string(ipamv1.IPv6Family)}))
+		}
+		if families[f.Family] {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("family"), f.Family))
+		}
+		families[f.Family] = true
+
+		if f.PoolRef == nil {
+			continue
+		}
+		if f.PoolRef.APIGroup == nil || *f.PoolRef.APIGroup != *claim.Spec.PoolRef.APIGroup {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("poolRef", "apiGroup"), f.PoolRef.APIGroup,
+				"must be the group of spec.poolRef"))
+		}
+		if f.PoolRef.Kind == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("poolRef", "kind"), "must be set"))
+		}
+		if f.PoolRef.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("poolRef", "name"), "must be set"))
+		}
+	}
+	return allErrs
 }
 
 // ValidateUpdate implements webhook.CustomValidator.
@@ -59,6 +59,52 @@ func TestIPAddressClaimValidateCreate(t *testing.T) {
 			}),
 			expectErr: true,
 		},
+		{
+			name: "should accept a dual-stack claim",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+					{Family: ipamv1.IPv4Family},
+					{Family: ipamv1.IPv6Family, PoolRef: &corev1.TypedLocalObjectReference{
+						Name:     "v6",
+						Kind:     "TestPool",
+						APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+					}},
+				}
+			}),
+			expectErr: false,
+		},
+		{
+			name: "should reject a duplicated family",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+					{Family: ipamv1.IPv4Family},
+					{Family: ipamv1.IPv4Family},
+				}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject an unknown family",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+					{Family: "IPX"},
+				}
+			}),
+			expectErr: true,
+		},
+		{
+			name: "should reject a family pool reference with a different group",
+			claim: getClaim(func(addr *ipamv1.IPAddressClaim) {
+				addr.Spec.AddressFamilies = []ipamv1.IPAddressClaimFamily{
+					{Family: ipamv1.IPv6Family, PoolRef: &corev1.TypedLocalObjectReference{
+						Name:     "v6",
+						Kind:     "OtherPool",
+						APIGroup: ptr.To("other.example.com"),
+					}},
+				}
+			}),
+			expectErr: true,
+		},
 	}
 
 	for i := range tests {
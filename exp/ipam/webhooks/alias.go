@@ -43,3 +43,19 @@ type IPAddressClaim struct {
 func (webhook *IPAddressClaim) SetupWebhookWithManager(mgr ctrl.Manager) error {
 	return (&webhooks.IPAddressClaim{}).SetupWebhookWithManager(mgr)
 }
+
+// InClusterIPPool implements a validating webhook for InClusterIPPool.
+type InClusterIPPool struct{}
+
+// SetupWebhookWithManager sets up InClusterIPPool webhooks.
+func (webhook *InClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.InClusterIPPool{}).SetupWebhookWithManager(mgr)
+}
+
+// GlobalInClusterIPPool implements a validating webhook for GlobalInClusterIPPool.
+type GlobalInClusterIPPool struct{}
+
+// SetupWebhookWithManager sets up GlobalInClusterIPPool webhooks.
+func (webhook *GlobalInClusterIPPool) SetupWebhookWithManager(mgr ctrl.Manager) error {
+	return (&webhooks.GlobalInClusterIPPool{}).SetupWebhookWithManager(mgr)
+}
@@ -60,6 +60,18 @@ const (
 	//
 	// alpha: v1.5
 	MachineSetPreflightChecks featuregate.Feature = "MachineSetPreflightChecks"
+
+	// InClusterIPAM is a feature gate for the in-cluster IPAM provider, allocating IPAddresses to IPAddressClaims
+	// from InClusterIPPools and GlobalInClusterIPPools.
+	//
+	// alpha: v1.7
+	InClusterIPAM featuregate.Feature = "InClusterIPAM"
+
+	// KubeadmControlPlaneInPlaceUpdates is a feature gate for the KubeadmControlPlane in-place updates
+	// of the control plane components.
+	//
+	// alpha: v1.7
+	KubeadmControlPlaneInPlaceUpdates featuregate.Feature = "KubeadmControlPlaneInPlaceUpdates"
 )
 
 func init() {
@@ -70,10 +82,12 @@ func init() {
 // To add a new feature, define a key for it above and add it here.
 var defaultClusterAPIFeatureGates = map[featuregate.Feature]featuregate.FeatureSpec{
 	// Every feature should be initiated here:
-	MachinePool:                    {Default: false, PreRelease: featuregate.Alpha},
-	ClusterResourceSet:             {Default: true, PreRelease: featuregate.Beta},
-	ClusterTopology:                {Default: false, PreRelease: featuregate.Alpha},
-	KubeadmBootstrapFormatIgnition: {Default: false, PreRelease: featuregate.Alpha},
-	RuntimeSDK:                     {Default: false, PreRelease: featuregate.Alpha},
-	MachineSetPreflightChecks:      {Default: false, PreRelease: featuregate.Alpha},
+	MachinePool:                       {Default: false, PreRelease: featuregate.Alpha},
+	ClusterResourceSet:                {Default: true, PreRelease: featuregate.Beta},
+	ClusterTopology:                   {Default: false, PreRelease: featuregate.Alpha},
+	KubeadmBootstrapFormatIgnition:    {Default: false, PreRelease: featuregate.Alpha},
+	RuntimeSDK:                        {Default: false, PreRelease: featuregate.Alpha},
+	MachineSetPreflightChecks:         {Default: false, PreRelease: featuregate.Alpha},
+	InClusterIPAM:                     {Default: false, PreRelease: featuregate.Alpha},
+	KubeadmControlPlaneInPlaceUpdates: {Default: false, PreRelease: featuregate.Alpha},
 }
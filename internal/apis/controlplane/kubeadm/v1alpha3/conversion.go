@@ -106,6 +106,16 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
 
+	dst.Spec.EtcdPool = restored.Spec.EtcdPool
+	dst.Status.EtcdPool = restored.Status.EtcdPool
+
+	if restored.Spec.RolloutStrategy != nil && restored.Spec.RolloutStrategy.InPlaceUpdate != nil {
+		if dst.Spec.RolloutStrategy == nil {
+			dst.Spec.RolloutStrategy = &controlplanev1.RolloutStrategy{}
+		}
+		dst.Spec.RolloutStrategy.InPlaceUpdate = restored.Spec.RolloutStrategy.InPlaceUpdate
+	}
+
 	return nil
 }
 
@@ -154,3 +164,8 @@ func Convert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneSpec
 	out.MachineTemplate.NodeDrainTimeout = in.NodeDrainTimeout
 	return autoConvert_v1alpha3_KubeadmControlPlaneSpec_To_v1beta1_KubeadmControlPlaneSpec(in, out, s)
 }
+
+func Convert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in *controlplanev1.RolloutStrategy, out *RolloutStrategy, s apiconversion.Scope) error {
+	// .InPlaceUpdate was added in v1beta1.
+	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha3_RolloutStrategy(in, out, s)
+}
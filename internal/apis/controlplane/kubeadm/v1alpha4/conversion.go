@@ -91,6 +91,16 @@ func (src *KubeadmControlPlane) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Status.LastRemediation = restored.Status.LastRemediation
 	}
 
+	dst.Spec.EtcdPool = restored.Spec.EtcdPool
+	dst.Status.EtcdPool = restored.Status.EtcdPool
+
+	if restored.Spec.RolloutStrategy != nil && restored.Spec.RolloutStrategy.InPlaceUpdate != nil {
+		if dst.Spec.RolloutStrategy == nil {
+			dst.Spec.RolloutStrategy = &controlplanev1.RolloutStrategy{}
+		}
+		dst.Spec.RolloutStrategy.InPlaceUpdate = restored.Spec.RolloutStrategy.InPlaceUpdate
+	}
+
 	return nil
 }
 
@@ -189,6 +199,13 @@ func (src *KubeadmControlPlaneTemplate) ConvertTo(dstRaw conversion.Hub) error {
 		dst.Spec.Template.Spec.RemediationStrategy = restored.Spec.Template.Spec.RemediationStrategy
 	}
 
+	if restored.Spec.Template.Spec.RolloutStrategy != nil && restored.Spec.Template.Spec.RolloutStrategy.InPlaceUpdate != nil {
+		if dst.Spec.Template.Spec.RolloutStrategy == nil {
+			dst.Spec.Template.Spec.RolloutStrategy = &controlplanev1.RolloutStrategy{}
+		}
+		dst.Spec.Template.Spec.RolloutStrategy.InPlaceUpdate = restored.Spec.Template.Spec.RolloutStrategy.InPlaceUpdate
+	}
+
 	return nil
 }
 
@@ -279,11 +296,13 @@ func Convert_v1beta1_KubeadmControlPlaneMachineTemplate_To_v1alpha4_KubeadmContr
 func Convert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in *controlplanev1.KubeadmControlPlaneSpec, out *KubeadmControlPlaneSpec, scope apiconversion.Scope) error {
 	// .RolloutBefore was added in v1beta1.
 	// .RemediationStrategy was added in v1beta1.
+	// .EtcdPool was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneSpec_To_v1alpha4_KubeadmControlPlaneSpec(in, out, scope)
 }
 
 func Convert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in *controlplanev1.KubeadmControlPlaneStatus, out *KubeadmControlPlaneStatus, scope apiconversion.Scope) error {
 	// .LastRemediation was added in v1beta1.
+	// .EtcdPool was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneStatus_To_v1alpha4_KubeadmControlPlaneStatus(in, out, scope)
 }
 
@@ -291,3 +310,8 @@ func Convert_v1beta1_KubeadmControlPlaneTemplateResource_To_v1alpha4_KubeadmCont
 	// .metadata and .spec.machineTemplate.metadata was added in v1beta1.
 	return autoConvert_v1beta1_KubeadmControlPlaneTemplateResource_To_v1alpha4_KubeadmControlPlaneTemplateResource(in, out, scope)
 }
+
+func Convert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in *controlplanev1.RolloutStrategy, out *RolloutStrategy, s apiconversion.Scope) error {
+	// .InPlaceUpdate was added in v1beta1.
+	return autoConvert_v1beta1_RolloutStrategy_To_v1alpha4_RolloutStrategy(in, out, s)
+}
@@ -118,6 +118,7 @@ type ClusterResourceSetBinding struct {
 // ANCHOR: ClusterResourceSetBindingSpec
 
 // ClusterResourceSetBindingSpec defines the desired state of ClusterResourceSetBinding.
+// +k8s:conversion-gen=false
 type ClusterResourceSetBindingSpec struct {
 	// Bindings is a list of ClusterResourceSets and their resources.
 	Bindings []*ResourceSetBinding `json:"bindings,omitempty"`
@@ -27,13 +27,33 @@ import (
 func (src *ClusterResourceSet) ConvertTo(dstRaw conversion.Hub) error {
 	dst := dstRaw.(*addonsv1.ClusterResourceSet)
 
-	return Convert_v1alpha3_ClusterResourceSet_To_v1beta1_ClusterResourceSet(src, dst, nil)
+	if err := Convert_v1alpha3_ClusterResourceSet_To_v1beta1_ClusterResourceSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Manually restore data.
+	restored := &addonsv1.ClusterResourceSet{}
+	if ok, err := utilconversion.UnmarshalData(src, restored); err != nil || !ok {
+		return err
+	}
+	dst.Spec.HelmCharts = restored.Spec.HelmCharts
+	dst.Spec.Kustomizations = restored.Spec.Kustomizations
+	dst.Spec.Prune = restored.Spec.Prune
+	dst.Spec.DriftDetection = restored.Spec.DriftDetection
+	dst.Spec.Waves = restored.Spec.Waves
+	dst.Status.PruneCandidates = restored.Status.PruneCandidates
+	return nil
 }
 
 func (dst *ClusterResourceSet) ConvertFrom(srcRaw conversion.Hub) error {
 	src := srcRaw.(*addonsv1.ClusterResourceSet)
 
-	return Convert_v1beta1_ClusterResourceSet_To_v1alpha3_ClusterResourceSet(src, dst, nil)
+	if err := Convert_v1beta1_ClusterResourceSet_To_v1alpha3_ClusterResourceSet(src, dst, nil); err != nil {
+		return err
+	}
+
+	// Preserve Hub data on down-conversion except for metadata
+	return utilconversion.MarshalData(src, dst)
 }
 
 func (src *ClusterResourceSetList) ConvertTo(dstRaw conversion.Hub) error {
@@ -60,6 +80,19 @@ func (src *ClusterResourceSetBinding) ConvertTo(dstRaw conversion.Hub) error {
 		return err
 	}
 	dst.Spec.ClusterName = restored.Spec.ClusterName
+	dst.Status = restored.Status
+	for _, restoredBinding := range restored.Spec.Bindings {
+		for _, binding := range dst.Spec.Bindings {
+			if binding == nil || restoredBinding == nil || binding.ClusterResourceSetName != restoredBinding.ClusterResourceSetName {
+				continue
+			}
+			for i := range binding.Resources {
+				if restoredResource := restoredBinding.GetResource(binding.Resources[i].ResourceRef); restoredResource != nil {
+					binding.Resources[i].Objects = restoredResource.Objects
+				}
+			}
+		}
+	}
 	return nil
 }
 
@@ -90,8 +123,67 @@ func (dst *ClusterResourceSetBindingList) ConvertFrom(srcRaw conversion.Hub) err
 	return Convert_v1beta1_ClusterResourceSetBindingList_To_v1alpha3_ClusterResourceSetBindingList(src, dst, nil)
 }
 
+// Convert_v1alpha3_ClusterResourceSetBindingSpec_To_v1beta1_ClusterResourceSetBindingSpec is a conversion function.
+// NOTE: conversion-gen can't generate conversions for slices of pointers to types requiring manual conversion.
+func Convert_v1alpha3_ClusterResourceSetBindingSpec_To_v1beta1_ClusterResourceSetBindingSpec(in *ClusterResourceSetBindingSpec, out *addonsv1.ClusterResourceSetBindingSpec, s apiconversion.Scope) error {
+	if in.Bindings == nil {
+		out.Bindings = nil
+		return nil
+	}
+	out.Bindings = make([]*addonsv1.ResourceSetBinding, len(in.Bindings))
+	for i := range in.Bindings {
+		if in.Bindings[i] == nil {
+			continue
+		}
+		out.Bindings[i] = &addonsv1.ResourceSetBinding{}
+		if err := Convert_v1alpha3_ResourceSetBinding_To_v1beta1_ResourceSetBinding(in.Bindings[i], out.Bindings[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // Convert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha3_ClusterResourceSetBindingSpec is a conversion function.
+// NOTE: conversion-gen can't generate conversions for slices of pointers to types requiring manual conversion.
 func Convert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha3_ClusterResourceSetBindingSpec(in *addonsv1.ClusterResourceSetBindingSpec, out *ClusterResourceSetBindingSpec, s apiconversion.Scope) error {
 	// Spec.ClusterName does not exist in ClusterResourceSetBinding v1alpha3 API.
-	return autoConvert_v1beta1_ClusterResourceSetBindingSpec_To_v1alpha3_ClusterResourceSetBindingSpec(in, out, s)
+	if in.Bindings == nil {
+		out.Bindings = nil
+		return nil
+	}
+	out.Bindings = make([]*ResourceSetBinding, len(in.Bindings))
+	for i := range in.Bindings {
+		if in.Bindings[i] == nil {
+			continue
+		}
+		out.Bindings[i] = &ResourceSetBinding{}
+		if err := Convert_v1beta1_ResourceSetBinding_To_v1alpha3_ResourceSetBinding(in.Bindings[i], out.Bindings[i], s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Convert_v1beta1_ClusterResourceSetSpec_To_v1alpha3_ClusterResourceSetSpec is a conversion function.
+func Convert_v1beta1_ClusterResourceSetSpec_To_v1alpha3_ClusterResourceSetSpec(in *addonsv1.ClusterResourceSetSpec, out *ClusterResourceSetSpec, s apiconversion.Scope) error {
+	// Spec.HelmCharts, Spec.Kustomizations, Spec.Waves, Spec.Prune and Spec.DriftDetection do not exist in ClusterResourceSet v1alpha3 API.
+	return autoConvert_v1beta1_ClusterResourceSetSpec_To_v1alpha3_ClusterResourceSetSpec(in, out, s)
+}
+
+// Convert_v1beta1_ClusterResourceSetStatus_To_v1alpha3_ClusterResourceSetStatus is a conversion function.
+func Convert_v1beta1_ClusterResourceSetStatus_To_v1alpha3_ClusterResourceSetStatus(in *addonsv1.ClusterResourceSetStatus, out *ClusterResourceSetStatus, s apiconversion.Scope) error {
+	// Status.PruneCandidates does not exist in ClusterResourceSet v1alpha3 API.
+	return autoConvert_v1beta1_ClusterResourceSetStatus_To_v1alpha3_ClusterResourceSetStatus(in, out, s)
+}
+
+// Convert_v1beta1_ResourceBinding_To_v1alpha3_ResourceBinding is a conversion function.
+func Convert_v1beta1_ResourceBinding_To_v1alpha3_ResourceBinding(in *addonsv1.ResourceBinding, out *ResourceBinding, s apiconversion.Scope) error {
+	// ResourceBinding.Objects does not exist in ClusterResourceSetBinding v1alpha3 API.
+	return autoConvert_v1beta1_ResourceBinding_To_v1alpha3_ResourceBinding(in, out, s)
+}
+
+// Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha3_ClusterResourceSetBinding is a conversion function.
+func Convert_v1beta1_ClusterResourceSetBinding_To_v1alpha3_ClusterResourceSetBinding(in *addonsv1.ClusterResourceSetBinding, out *ClusterResourceSetBinding, s apiconversion.Scope) error {
+	// Status does not exist in ClusterResourceSetBinding v1alpha3 API.
+	return autoConvert_v1beta1_ClusterResourceSetBinding_To_v1alpha3_ClusterResourceSetBinding(in, out, s)
 }
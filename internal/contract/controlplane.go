@@ -60,6 +60,15 @@ func (c *ControlPlaneContract) Version() *String {
 	}
 }
 
+// RolloutAfter provide access to the spec.rolloutAfter field in a ControlPlane object, if any.
+// NOTE: When working with unstructured there is no way to understand if the ControlPlane provider
+// do support a field in the type definition from the fact that a field is not set in a given instance.
+func (c *ControlPlaneContract) RolloutAfter() *String {
+	return &String{
+		path: []string{"spec", "rolloutAfter"},
+	}
+}
+
 // StatusVersion provide access to the version field in a ControlPlane object status, if any.
 func (c *ControlPlaneContract) StatusVersion() *String {
 	return &String{
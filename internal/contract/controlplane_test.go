@@ -43,6 +43,19 @@ func TestControlPlane(t *testing.T) {
 		g.Expect(got).ToNot(BeNil())
 		g.Expect(*got).To(Equal("vFoo"))
 	})
+	t.Run("Manages spec.rolloutAfter", func(t *testing.T) {
+		g := NewWithT(t)
+
+		g.Expect(ControlPlane().RolloutAfter().Path()).To(Equal(Path{"spec", "rolloutAfter"}))
+
+		err := ControlPlane().RolloutAfter().Set(obj, "2024-01-01T00:00:00Z")
+		g.Expect(err).ToNot(HaveOccurred())
+
+		got, err := ControlPlane().RolloutAfter().Get(obj)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(got).ToNot(BeNil())
+		g.Expect(*got).To(Equal("2024-01-01T00:00:00Z"))
+	})
 	t.Run("Manages status.version", func(t *testing.T) {
 		g := NewWithT(t)
 
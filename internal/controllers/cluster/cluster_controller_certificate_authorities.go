@@ -0,0 +1,432 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"bytes"
+	"context"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/collections"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+const (
+	// certificateAuthoritiesRotationRequeueAfter is how long to wait before checking again the progress of the
+	// machine rollouts triggered by a certificate authorities rotation.
+	certificateAuthoritiesRotationRequeueAfter = 30 * time.Second
+
+	// clusterInfoNamespace and clusterInfoName identify the ConfigMap used by kubeadm for the discovery of the
+	// cluster certificate authority when joining new nodes.
+	clusterInfoNamespace = metav1.NamespacePublic
+	clusterInfoName      = "cluster-info"
+
+	// clusterInfoKubeconfigKey is the key storing the Kubeconfig in the cluster-info ConfigMap.
+	clusterInfoKubeconfigKey = "kubeconfig"
+)
+
+// reconcileCertificateAuthorities rotates the certificate authorities and the service account keys generated by
+// Cluster API for the Cluster, when requested using the RotateCertificateAuthoritiesAnnotation or when a certificate
+// authority is about to expire.
+//
+// Every phase of the rotation (see secret.RotationPhase) updates the certificate authority secrets, the cluster-info
+// ConfigMap in the workload cluster and the Kubeconfig secret, then rolls out the control plane machines first and the
+// MachineDeployment machines after, using spec.rolloutAfter; each phase is reported as a condition on the Cluster.
+// NOTE: Machines not owned by the control plane or by a MachineDeployment, e.g. MachinePool machines, are not rolled out.
+func (r *Reconciler) reconcileCertificateAuthorities(ctx context.Context, cluster *clusterv1.Cluster) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+
+	// Certificate authorities can be rotated only once the control plane is initialized.
+	if cluster.Spec.ControlPlaneRef == nil || !conditions.IsTrue(cluster, clusterv1.ControlPlaneInitializedCondition) {
+		return ctrl.Result{}, nil
+	}
+
+	caSecrets, err := r.getRotatableCertificateAuthorities(ctx, cluster)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	if len(caSecrets) == 0 {
+		return ctrl.Result{}, nil
+	}
+
+	phase, err := currentRotationPhase(caSecrets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+
+	// Align certificate authorities lagging behind, e.g. because of a partial failure when moving to the current phase.
+	now := time.Now().UTC().Truncate(time.Second)
+	aligned := false
+	for purpose, caSecret := range caSecrets {
+		secretPhase, err := secret.GetRotationPhase(caSecret)
+		if err != nil {
+			return ctrl.Result{}, err
+		}
+		if secretPhase == phase {
+			continue
+		}
+		if err := r.advanceCertificateAuthority(ctx, caSecret, purpose, now); err != nil {
+			return ctrl.Result{}, err
+		}
+		aligned = true
+	}
+
+	if phase == secret.RotationPhaseNone {
+		// Complete a rotation which failed while retiring the previous certificate authorities.
+		if aligned {
+			conditions.MarkTrue(cluster, clusterv1.PreviousCertificateAuthoritiesRetiredCondition)
+		}
+
+		if !r.isCertificateAuthoritiesRotationRequired(ctx, cluster, caSecrets) {
+			return ctrl.Result{}, nil
+		}
+
+		log.Info("Starting certificate authorities rotation")
+		for purpose, caSecret := range caSecrets {
+			if err := r.advanceCertificateAuthority(ctx, caSecret, purpose, now); err != nil {
+				return ctrl.Result{}, err
+			}
+		}
+		phase = secret.RotationPhaseTrust
+
+		conditions.Delete(cluster, clusterv1.TrustBundleDistributedCondition)
+		conditions.Delete(cluster, clusterv1.CertificateAuthoritiesRotatedCondition)
+		conditions.Delete(cluster, clusterv1.PreviousCertificateAuthoritiesRetiredCondition)
+	}
+
+	// The rotation request is fulfilled by the rotation in progress.
+	if _, ok := cluster.GetAnnotations()[clusterv1.RotateCertificateAuthoritiesAnnotation]; ok {
+		annotations := cluster.GetAnnotations()
+		delete(annotations, clusterv1.RotateCertificateAuthoritiesAnnotation)
+		cluster.SetAnnotations(annotations)
+	}
+
+	condition := rotationPhaseCondition(phase)
+	if err := r.reconcileTrustBundle(ctx, cluster, caSecrets[secret.ClusterCA]); err != nil {
+		conditions.MarkFalse(cluster, condition, clusterv1.CertificateAuthoritiesRotationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+
+	timestamp, err := rotationTimestamp(caSecrets)
+	if err != nil {
+		return ctrl.Result{}, err
+	}
+	rolledOut, err := r.reconcileCertificateAuthoritiesRollout(ctx, cluster, condition, timestamp)
+	if err != nil {
+		conditions.MarkFalse(cluster, condition, clusterv1.CertificateAuthoritiesRotationFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+		return ctrl.Result{}, err
+	}
+	if !rolledOut {
+		return ctrl.Result{RequeueAfter: certificateAuthoritiesRotationRequeueAfter}, nil
+	}
+
+	// All the machines have been rolled out, move to the next phase.
+	log.Info("Completed certificate authorities rotation phase", "phase", phase)
+	for purpose, caSecret := range caSecrets {
+		if err := r.advanceCertificateAuthority(ctx, caSecret, purpose, now); err != nil {
+			return ctrl.Result{}, err
+		}
+	}
+	conditions.MarkTrue(cluster, condition)
+
+	if phase.Next() == secret.RotationPhaseNone {
+		log.Info("Completed certificate authorities rotation")
+		return ctrl.Result{}, nil
+	}
+	return ctrl.Result{Requeue: true}, nil
+}
+
+// getRotatableCertificateAuthorities returns the secrets storing the certificate authorities, and the service account
+// keys, that can be rotated for a Cluster, i.e. the ones generated by Cluster API.
+// NOTE: Certificate authorities provided by the user are not owned by any controller, while external certificate
+// authorities, e.g. the one of an external etcd, don't come with a key.
+func (r *Reconciler) getRotatableCertificateAuthorities(ctx context.Context, cluster *clusterv1.Cluster) (map[secret.Purpose]*corev1.Secret, error) {
+	caSecrets := map[secret.Purpose]*corev1.Secret{}
+	for _, purpose := range secret.RotatablePurposes {
+		caSecret, err := secret.GetFromNamespacedName(ctx, r.Client, util.ObjectKey(cluster), purpose)
+		if err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return nil, errors.Wrapf(err, "failed to get %s secret", purpose)
+		}
+		if metav1.GetControllerOf(caSecret) == nil || len(caSecret.Data[secret.TLSKeyDataName]) == 0 {
+			continue
+		}
+		caSecrets[purpose] = caSecret
+	}
+	return caSecrets, nil
+}
+
+// currentRotationPhase returns the rotation phase of the given certificate authorities. If certificate authorities are in
+// different phases, e.g. because of a partial failure when moving to the next phase, the most advanced phase is returned.
+func currentRotationPhase(caSecrets map[secret.Purpose]*corev1.Secret) (secret.RotationPhase, error) {
+	phases := map[secret.RotationPhase]bool{}
+	for _, caSecret := range caSecrets {
+		phase, err := secret.GetRotationPhase(caSecret)
+		if err != nil {
+			return secret.RotationPhaseNone, err
+		}
+		phases[phase] = true
+	}
+
+	for phase := range phases {
+		if len(phases) == 1 {
+			return phase, nil
+		}
+		// With two phases, the current phase is the one following the other.
+		if len(phases) == 2 && phases[phase.Next()] {
+			return phase.Next(), nil
+		}
+	}
+	return secret.RotationPhaseNone, errors.New("certificate authorities are in inconsistent rotation phases")
+}
+
+// rotationTimestamp returns the time when the current rotation phase has been started.
+func rotationTimestamp(caSecrets map[secret.Purpose]*corev1.Secret) (time.Time, error) {
+	var timestamp time.Time
+	for _, caSecret := range caSecrets {
+		t, err := secret.GetRotationTimestamp(caSecret)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if t != nil && t.After(timestamp) {
+			timestamp = *t
+		}
+	}
+	return timestamp, nil
+}
+
+// rotationPhaseCondition returns the condition reporting the progress of a rotation phase.
+func rotationPhaseCondition(phase secret.RotationPhase) clusterv1.ConditionType {
+	switch phase {
+	case secret.RotationPhaseTrust:
+		return clusterv1.TrustBundleDistributedCondition
+	case secret.RotationPhaseSign:
+		return clusterv1.CertificateAuthoritiesRotatedCondition
+	default:
+		return clusterv1.PreviousCertificateAuthoritiesRetiredCondition
+	}
+}
+
+// isCertificateAuthoritiesRotationRequired returns true if the rotation of the certificate authorities has been requested,
+// or if a certificate authority is about to expire.
+func (r *Reconciler) isCertificateAuthoritiesRotationRequired(ctx context.Context, cluster *clusterv1.Cluster, caSecrets map[secret.Purpose]*corev1.Secret) bool {
+	log := ctrl.LoggerFrom(ctx)
+
+	_, requested := cluster.GetAnnotations()[clusterv1.RotateCertificateAuthoritiesAnnotation]
+	if !requested {
+		for purpose, caSecret := range caSecrets {
+			if purpose == secret.ServiceAccount {
+				continue
+			}
+			caCert, err := certs.DecodeCertPEM(caSecret.Data[secret.TLSCrtDataName])
+			if err != nil || caCert == nil {
+				log.Error(err, "Failed to decode certificate authority", "Secret", klog.KObj(caSecret))
+				continue
+			}
+			if time.Until(caCert.NotAfter) < certs.CertificateAuthorityRenewalDuration {
+				log.Info("Certificate authority is about to expire", "Secret", klog.KObj(caSecret), "expiry", caCert.NotAfter)
+				requested = true
+			}
+		}
+	}
+	return requested
+}
+
+// advanceCertificateAuthority moves a certificate authority to the next rotation phase.
+func (r *Reconciler) advanceCertificateAuthority(ctx context.Context, caSecret *corev1.Secret, purpose secret.Purpose, now time.Time) error {
+	original := caSecret.DeepCopy()
+	if err := secret.AdvanceRotation(caSecret, purpose, now); err != nil {
+		return errors.Wrapf(err, "failed to rotate %s", purpose)
+	}
+	if err := r.Client.Patch(ctx, caSecret, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to patch secret %s", klog.KObj(caSecret))
+	}
+	return nil
+}
+
+// reconcileTrustBundle ensures the Kubeconfig secret and the cluster-info ConfigMap in the workload cluster trust
+// the certificate authorities in the cluster CA secret, and that the Kubeconfig uses a client certificate signed
+// by the current certificate authority.
+func (r *Reconciler) reconcileTrustBundle(ctx context.Context, cluster *clusterv1.Cluster, caSecret *corev1.Secret) error {
+	if caSecret == nil {
+		return nil
+	}
+	caData := caSecret.Data[secret.TLSCrtDataName]
+
+	configSecret, err := secret.GetFromNamespacedName(ctx, r.Client, util.ObjectKey(cluster), secret.Kubeconfig)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return errors.Wrap(err, "failed to get Kubeconfig secret")
+	default:
+		needsRotation, err := kubeconfig.NeedsCertificateAuthorityRotation(configSecret, caData)
+		if err != nil {
+			return err
+		}
+		if needsRotation {
+			if err := kubeconfig.RegenerateSecret(ctx, r.Client, configSecret); err != nil {
+				return errors.Wrap(err, "failed to regenerate Kubeconfig secret")
+			}
+		}
+	}
+
+	remoteClient, err := r.Tracker.GetClient(ctx, util.ObjectKey(cluster))
+	if err != nil {
+		return errors.Wrap(err, "failed to create client for the workload cluster")
+	}
+	clusterInfo := &corev1.ConfigMap{}
+	if err := remoteClient.Get(ctx, client.ObjectKey{Namespace: clusterInfoNamespace, Name: clusterInfoName}, clusterInfo); err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get %s ConfigMap", clusterInfoName)
+	}
+	config, err := clientcmd.Load([]byte(clusterInfo.Data[clusterInfoKubeconfigKey]))
+	if err != nil {
+		return errors.Wrapf(err, "failed to load Kubeconfig from %s ConfigMap", clusterInfoName)
+	}
+	changed := false
+	for _, c := range config.Clusters {
+		if !bytes.Equal(c.CertificateAuthorityData, caData) {
+			c.CertificateAuthorityData = caData
+			changed = true
+		}
+	}
+	if !changed {
+		return nil
+	}
+	out, err := clientcmd.Write(*config)
+	if err != nil {
+		return errors.Wrap(err, "failed to serialize Kubeconfig")
+	}
+	original := clusterInfo.DeepCopy()
+	clusterInfo.Data[clusterInfoKubeconfigKey] = string(out)
+	if err := remoteClient.Patch(ctx, clusterInfo, client.MergeFrom(original)); err != nil {
+		return errors.Wrapf(err, "failed to patch %s ConfigMap", clusterInfoName)
+	}
+	return nil
+}
+
+// reconcileCertificateAuthoritiesRollout rolls out the control plane machines first, and the MachineDeployment machines
+// after, so they pick up the certificate authorities for the current rotation phase, started at the given time.
+// It returns true when all the machines have been rolled out.
+func (r *Reconciler) reconcileCertificateAuthoritiesRollout(ctx context.Context, cluster *clusterv1.Cluster, condition clusterv1.ConditionType, timestamp time.Time) (bool, error) {
+	controlPlane, err := external.Get(ctx, r.UnstructuredCachingClient, cluster.Spec.ControlPlaneRef, cluster.Namespace)
+	if err != nil {
+		return false, err
+	}
+	rolloutAfter, err := contract.ControlPlane().RolloutAfter().Get(controlPlane)
+	if err != nil && !errors.Is(err, contract.ErrFieldNotFound) {
+		return false, err
+	}
+	if rolloutAfter == nil || isBefore(*rolloutAfter, timestamp) {
+		original := controlPlane.DeepCopy()
+		if err := contract.ControlPlane().RolloutAfter().Set(controlPlane, timestamp.Format(time.RFC3339)); err != nil {
+			return false, err
+		}
+		if err := r.Client.Patch(ctx, controlPlane, client.MergeFrom(original)); err != nil {
+			return false, errors.Wrapf(err, "failed to set rolloutAfter on %s %s", controlPlane.GetKind(), klog.KObj(controlPlane))
+		}
+	}
+
+	controlPlaneMachines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster, collections.ControlPlaneMachines(cluster.Name))
+	if err != nil {
+		return false, err
+	}
+	if outdated := controlPlaneMachines.Filter(isMachineOutdated(timestamp)); len(outdated) > 0 || len(controlPlaneMachines) == 0 {
+		conditions.MarkFalse(cluster, condition, clusterv1.WaitingForControlPlaneRolloutReason, clusterv1.ConditionSeverityInfo,
+			"Rolled out %d of %d control plane machines", len(controlPlaneMachines)-len(outdated), len(controlPlaneMachines))
+		return false, nil
+	}
+
+	// Start rolling out workers only after the control plane machines are rolled out, so new worker machines
+	// get certificates from control plane machines using the current certificate authorities.
+	machineDeployments := &clusterv1.MachineDeploymentList{}
+	if err := r.Client.List(ctx, machineDeployments, client.InNamespace(cluster.Namespace), client.MatchingLabels{clusterv1.ClusterNameLabel: cluster.Name}); err != nil {
+		return false, errors.Wrap(err, "failed to list MachineDeployments")
+	}
+	rolledOut := 0
+	for i := range machineDeployments.Items {
+		md := &machineDeployments.Items[i]
+		if !md.DeletionTimestamp.IsZero() {
+			rolledOut++
+			continue
+		}
+		if md.Spec.RolloutAfter == nil || md.Spec.RolloutAfter.Time.Before(timestamp) {
+			original := md.DeepCopy()
+			md.Spec.RolloutAfter = &metav1.Time{Time: time.Now().UTC().Truncate(time.Second)}
+			if err := r.Client.Patch(ctx, md, client.MergeFrom(original)); err != nil {
+				return false, errors.Wrapf(err, "failed to set rolloutAfter on MachineDeployment %s", klog.KObj(md))
+			}
+		}
+
+		machines, err := collections.GetFilteredMachinesForCluster(ctx, r.Client, cluster, isMachineDeploymentMachine(md.Name))
+		if err != nil {
+			return false, err
+		}
+		if outdated := machines.Filter(isMachineOutdated(md.Spec.RolloutAfter.Time)); len(outdated) == 0 {
+			rolledOut++
+		}
+	}
+	if rolledOut < len(machineDeployments.Items) {
+		conditions.MarkFalse(cluster, condition, clusterv1.WaitingForWorkersRolloutReason, clusterv1.ConditionSeverityInfo,
+			"Rolled out %d of %d MachineDeployments", rolledOut, len(machineDeployments.Items))
+		return false, nil
+	}
+	return true, nil
+}
+
+// isBefore returns true if the RFC3339 time in value is before t; values that cannot be parsed are considered before t.
+func isBefore(value string, t time.Time) bool {
+	parsed, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return true
+	}
+	return parsed.Before(t)
+}
+
+// isMachineOutdated returns a filter to find machines created before the given time, being deleted or without a Node.
+func isMachineOutdated(t time.Time) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		return machine.CreationTimestamp.Time.Before(t) || !machine.DeletionTimestamp.IsZero() || machine.Status.NodeRef == nil
+	}
+}
+
+// isMachineDeploymentMachine returns a filter to find machines belonging to the given MachineDeployment.
+func isMachineDeploymentMachine(name string) collections.Func {
+	return func(machine *clusterv1.Machine) bool {
+		return machine.Labels[clusterv1.MachineDeploymentNameLabel] == name
+	}
+}
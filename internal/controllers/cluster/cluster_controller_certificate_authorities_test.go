@@ -0,0 +1,261 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cluster
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/record"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	"sigs.k8s.io/cluster-api/controllers/external"
+	"sigs.k8s.io/cluster-api/controllers/remote"
+	"sigs.k8s.io/cluster-api/internal/contract"
+	"sigs.k8s.io/cluster-api/internal/test/builder"
+	"sigs.k8s.io/cluster-api/util"
+	"sigs.k8s.io/cluster-api/util/certs"
+	"sigs.k8s.io/cluster-api/util/conditions"
+	"sigs.k8s.io/cluster-api/util/kubeconfig"
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestClusterReconcileCertificateAuthorities(t *testing.T) {
+	g := NewWithT(t)
+
+	controlPlane := builder.ControlPlane(metav1.NamespaceDefault, "cp").Build()
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "test-cluster-uid",
+			Annotations: map[string]string{
+				clusterv1.RotateCertificateAuthoritiesAnnotation: "",
+			},
+		},
+		Spec: clusterv1.ClusterSpec{
+			ControlPlaneRef: external.GetObjectReference(controlPlane),
+		},
+	}
+	conditions.MarkTrue(cluster, clusterv1.ControlPlaneInitializedCondition)
+	owner := metav1.OwnerReference{
+		APIVersion: clusterv1.GroupVersion.String(),
+		Kind:       "Cluster",
+		Name:       cluster.Name,
+		UID:        cluster.UID,
+		Controller: ptr.To(true),
+	}
+
+	certificates := secret.Certificates{
+		&secret.Certificate{Purpose: secret.ClusterCA},
+		&secret.Certificate{Purpose: secret.ServiceAccount},
+	}
+	g.Expect(certificates.Generate()).To(Succeed())
+	clusterCA := certificates.GetByPurpose(secret.ClusterCA).AsSecret(util.ObjectKey(cluster), owner)
+	serviceAccount := certificates.GetByPurpose(secret.ServiceAccount).AsSecret(util.ObjectKey(cluster), owner)
+	// Certificate authorities provided by the user are not rotated.
+	userCertificates := secret.Certificates{&secret.Certificate{Purpose: secret.FrontProxyCA}}
+	g.Expect(userCertificates.Generate()).To(Succeed())
+	frontProxyCA := userCertificates.GetByPurpose(secret.FrontProxyCA).AsSecret(util.ObjectKey(cluster), owner)
+	frontProxyCA.OwnerReferences = nil
+
+	caCert, err := certs.DecodeCertPEM(clusterCA.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	caKey, err := certs.DecodePrivateKeyPEM(clusterCA.Data[secret.TLSKeyDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	config, err := kubeconfig.New(cluster.Name, "https://1.2.3.4:6443", caCert, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err := clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+	kubeconfigSecret := kubeconfig.GenerateSecret(cluster, out)
+	clusterInfo := &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: clusterInfoNamespace,
+			Name:      clusterInfoName,
+		},
+		Data: map[string]string{
+			clusterInfoKubeconfigKey: string(out),
+		},
+	}
+
+	oldMachine := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "old-machine",
+			Namespace:         cluster.Namespace,
+			CreationTimestamp: metav1.Time{Time: time.Now().Add(-time.Hour)},
+			Labels: map[string]string{
+				clusterv1.ClusterNameLabel:         cluster.Name,
+				clusterv1.MachineControlPlaneLabel: "",
+			},
+		},
+		Status: clusterv1.MachineStatus{
+			NodeRef: &corev1.ObjectReference{Name: "old-node"},
+		},
+	}
+	newMachine := oldMachine.DeepCopy()
+	newMachine.Name = "new-machine"
+	newMachine.CreationTimestamp = metav1.Time{Time: time.Now().Add(time.Hour)}
+	md := builder.MachineDeployment(cluster.Namespace, "md").WithClusterName(cluster.Name).Build()
+	md.SetLabels(map[string]string{clusterv1.ClusterNameLabel: cluster.Name})
+
+	c := fake.NewClientBuilder().
+		WithScheme(fakeScheme).
+		WithObjects(cluster, controlPlane, clusterCA, serviceAccount, frontProxyCA, kubeconfigSecret, oldMachine, md).
+		Build()
+	remoteClient := fake.NewClientBuilder().WithScheme(fakeScheme).WithObjects(clusterInfo).Build()
+	r := &Reconciler{
+		Client:                    c,
+		UnstructuredCachingClient: c,
+		Tracker:                   remote.NewTestClusterCacheTracker(ctrl.Log, c, remoteClient, fakeScheme, util.ObjectKey(cluster)),
+		recorder:                  record.NewFakeRecorder(32),
+	}
+
+	getSecret := func(purpose secret.Purpose) *corev1.Secret {
+		s, err := secret.GetFromNamespacedName(ctx, c, util.ObjectKey(cluster), purpose)
+		g.Expect(err).ToNot(HaveOccurred())
+		return s
+	}
+	getPhase := func(purpose secret.Purpose) secret.RotationPhase {
+		phase, err := secret.GetRotationPhase(getSecret(purpose))
+		g.Expect(err).ToNot(HaveOccurred())
+		return phase
+	}
+
+	// The rotation starts, distributes the trust bundle and rolls out the control plane.
+	res, err := r.reconcileCertificateAuthorities(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.RequeueAfter).To(Equal(certificateAuthoritiesRotationRequeueAfter))
+	g.Expect(cluster.Annotations).ToNot(HaveKey(clusterv1.RotateCertificateAuthoritiesAnnotation))
+	g.Expect(getPhase(secret.ClusterCA)).To(Equal(secret.RotationPhaseTrust))
+	g.Expect(getPhase(secret.ServiceAccount)).To(Equal(secret.RotationPhaseTrust))
+	g.Expect(getPhase(secret.FrontProxyCA)).To(Equal(secret.RotationPhaseNone))
+	g.Expect(conditions.IsFalse(cluster, clusterv1.TrustBundleDistributedCondition)).To(BeTrue())
+	g.Expect(conditions.GetReason(cluster, clusterv1.TrustBundleDistributedCondition)).To(Equal(clusterv1.WaitingForControlPlaneRolloutReason))
+
+	trustBundle := getSecret(secret.ClusterCA).Data[secret.TLSCrtDataName]
+	g.Expect(remoteClient.Get(ctx, client.ObjectKeyFromObject(clusterInfo), clusterInfo)).To(Succeed())
+	clusterInfoConfig, err := clientcmd.Load([]byte(clusterInfo.Data[clusterInfoKubeconfigKey]))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterInfoConfig.Clusters[cluster.Name].CertificateAuthorityData).To(Equal(trustBundle))
+	needsRotation, err := kubeconfig.NeedsCertificateAuthorityRotation(getSecret(secret.Kubeconfig), trustBundle)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(needsRotation).To(BeFalse())
+
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(controlPlane), controlPlane)).To(Succeed())
+	rolloutAfter, err := contract.ControlPlane().RolloutAfter().Get(controlPlane)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(*rolloutAfter).To(Equal(getSecret(secret.ClusterCA).Annotations[secret.RotationTimestampAnnotation]))
+
+	// Once control plane machines are rolled out, workers are rolled out; MachineDeployments without machines
+	// are rolled out immediately, so the rotation moves to the next phase.
+	g.Expect(c.Delete(ctx, oldMachine)).To(Succeed())
+	g.Expect(c.Create(ctx, newMachine)).To(Succeed())
+	_, err = r.reconcileCertificateAuthorities(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getPhase(secret.ClusterCA)).To(Equal(secret.RotationPhaseSign))
+	g.Expect(getPhase(secret.ServiceAccount)).To(Equal(secret.RotationPhaseSign))
+	g.Expect(conditions.IsTrue(cluster, clusterv1.TrustBundleDistributedCondition)).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(md), md)).To(Succeed())
+	g.Expect(md.Spec.RolloutAfter).ToNot(BeNil())
+
+	// The previous certificate authorities are retired.
+	_, err = r.reconcileCertificateAuthorities(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(getPhase(secret.ClusterCA)).To(Equal(secret.RotationPhaseRetire))
+	g.Expect(conditions.IsTrue(cluster, clusterv1.CertificateAuthoritiesRotatedCondition)).To(BeTrue())
+
+	// The rotation completes.
+	res, err = r.reconcileCertificateAuthorities(ctx, cluster)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(res.IsZero()).To(BeTrue())
+	g.Expect(getPhase(secret.ClusterCA)).To(Equal(secret.RotationPhaseNone))
+	g.Expect(getPhase(secret.ServiceAccount)).To(Equal(secret.RotationPhaseNone))
+	g.Expect(conditions.IsTrue(cluster, clusterv1.PreviousCertificateAuthoritiesRetiredCondition)).To(BeTrue())
+
+	rotatedCACert, err := certs.DecodeCertPEM(getSecret(secret.ClusterCA).Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(rotatedCACert.Equal(caCert)).To(BeFalse())
+	g.Expect(remoteClient.Get(ctx, client.ObjectKeyFromObject(clusterInfo), clusterInfo)).To(Succeed())
+	clusterInfoConfig, err = clientcmd.Load([]byte(clusterInfo.Data[clusterInfoKubeconfigKey]))
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(clusterInfoConfig.Clusters[cluster.Name].CertificateAuthorityData).To(Equal(certs.EncodeCertPEM(rotatedCACert)))
+}
+
+func TestCurrentRotationPhase(t *testing.T) {
+	secretWithPhase := func(phase secret.RotationPhase) *corev1.Secret {
+		certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ClusterCA}}
+		_ = certificates.Generate()
+		s := certificates.GetByPurpose(secret.ClusterCA).AsSecret(client.ObjectKey{Name: "foo"}, metav1.OwnerReference{})
+		for p := secret.RotationPhaseNone; p != phase; p = p.Next() {
+			_ = secret.AdvanceRotation(s, secret.ClusterCA, time.Now())
+		}
+		return s
+	}
+
+	tests := []struct {
+		name      string
+		phases    []secret.RotationPhase
+		wantPhase secret.RotationPhase
+		wantErr   bool
+	}{
+		{
+			name:      "all certificate authorities in the same phase",
+			phases:    []secret.RotationPhase{secret.RotationPhaseSign, secret.RotationPhaseSign},
+			wantPhase: secret.RotationPhaseSign,
+		},
+		{
+			name:      "certificate authorities lagging behind",
+			phases:    []secret.RotationPhase{secret.RotationPhaseNone, secret.RotationPhaseTrust},
+			wantPhase: secret.RotationPhaseTrust,
+		},
+		{
+			name:      "certificate authorities lagging behind while completing the rotation",
+			phases:    []secret.RotationPhase{secret.RotationPhaseRetire, secret.RotationPhaseNone},
+			wantPhase: secret.RotationPhaseNone,
+		},
+		{
+			name:    "inconsistent phases",
+			phases:  []secret.RotationPhase{secret.RotationPhaseNone, secret.RotationPhaseSign},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			caSecrets := map[secret.Purpose]*corev1.Secret{}
+			for i, phase := range tt.phases {
+				caSecrets[secret.RotatablePurposes[i]] = secretWithPhase(phase)
+			}
+			phase, err := currentRotationPhase(caSecrets)
+			if tt.wantErr {
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).ToNot(HaveOccurred())
+			g.Expect(phase).To(Equal(tt.wantPhase))
+		})
+	}
+}
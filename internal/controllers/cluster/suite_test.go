@@ -96,6 +96,7 @@ func TestMain(m *testing.M) {
 			Client:                    mgr.GetClient(),
 			UnstructuredCachingClient: unstructuredCachingClient,
 			APIReader:                 mgr.GetClient(),
+			Tracker:                   tracker,
 		}).SetupWithManager(ctx, mgr, controller.Options{MaxConcurrentReconciles: 1}); err != nil {
 			panic(fmt.Sprintf("Failed to start ClusterReconciler: %v", err))
 		}
@@ -0,0 +1,156 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/klog/v2"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+// reconcileIPAddressClaims ensures an IPAddressClaim exists for each of the IP addresses required by the Machine,
+// and surfaces the bound addresses in the Machine status. The infrastructure of the Machine is not reconciled
+// until all the claims are bound.
+func (r *Reconciler) reconcileIPAddressClaims(ctx context.Context, s *scope) (ctrl.Result, error) {
+	log := ctrl.LoggerFrom(ctx)
+	m := s.machine
+
+	if len(m.Spec.IPAddressClaims) == 0 {
+		m.Status.IPAddresses = nil
+		conditions.Delete(m, clusterv1.IPAddressesBoundCondition)
+		return ctrl.Result{}, nil
+	}
+
+	s.waitingForIPAddresses = true
+	addresses := []clusterv1.MachineIPAddress{}
+	pending := []string{}
+	for _, c := range m.Spec.IPAddressClaims {
+		claim, err := r.ensureIPAddressClaim(ctx, s.cluster, m, c)
+		if err != nil {
+			conditions.MarkFalse(m, clusterv1.IPAddressesBoundCondition, clusterv1.IPAddressClaimFailedReason, clusterv1.ConditionSeverityWarning, err.Error())
+			return ctrl.Result{}, err
+		}
+
+		if claim.Status.AddressRef.Name == "" {
+			pending = append(pending, c.Name)
+			continue
+		}
+
+		address := &ipamv1.IPAddress{}
+		if err := r.Client.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: claim.Status.AddressRef.Name}, address); err != nil {
+			if apierrors.IsNotFound(err) {
+				pending = append(pending, c.Name)
+				continue
+			}
+			return ctrl.Result{}, errors.Wrapf(err, "failed to get IPAddress %s for IPAddressClaim %s", claim.Status.AddressRef.Name, claim.Name)
+		}
+
+		addresses = append(addresses, clusterv1.MachineIPAddress{
+			Name:      c.Name,
+			ClaimName: claim.Name,
+			Address:   address.Spec.Address,
+			Prefix:    address.Spec.Prefix,
+			Gateway:   address.Spec.Gateway,
+		})
+	}
+	m.Status.IPAddresses = addresses
+
+	if len(pending) > 0 {
+		log.Info("Waiting for IPAddressClaims to be bound", "pending", strings.Join(pending, ","))
+		conditions.MarkFalse(m, clusterv1.IPAddressesBoundCondition, clusterv1.WaitingForIPAddressesReason, clusterv1.ConditionSeverityInfo,
+			"Waiting for IP addresses %s to be bound", strings.Join(pending, ", "))
+		return ctrl.Result{}, nil
+	}
+
+	s.waitingForIPAddresses = false
+	conditions.MarkTrue(m, clusterv1.IPAddressesBoundCondition)
+	return ctrl.Result{}, nil
+}
+
+// ensureIPAddressClaim gets the IPAddressClaim for an IP address required by the Machine, creating it if it does not exist yet.
+func (r *Reconciler) ensureIPAddressClaim(ctx context.Context, cluster *clusterv1.Cluster, m *clusterv1.Machine, c clusterv1.MachineIPAddressClaim) (*ipamv1.IPAddressClaim, error) {
+	claim := &ipamv1.IPAddressClaim{}
+	key := client.ObjectKey{Namespace: m.Namespace, Name: ipAddressClaimName(m, c.Name)}
+	if err := r.Client.Get(ctx, key, claim); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return nil, errors.Wrapf(err, "failed to get IPAddressClaim %s", key.Name)
+		}
+
+		claim = &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      key.Name,
+				Namespace: key.Namespace,
+				Labels: map[string]string{
+					clusterv1.ClusterNameLabel: cluster.Name,
+				},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(m, clusterv1.GroupVersion.WithKind("Machine")),
+				},
+			},
+			Spec: ipamv1.IPAddressClaimSpec{
+				PoolRef: c.PoolRef,
+			},
+		}
+		if err := r.Client.Create(ctx, claim); err != nil {
+			return nil, errors.Wrapf(err, "failed to create IPAddressClaim %s", key.Name)
+		}
+		ctrl.LoggerFrom(ctx).Info("Created IPAddressClaim", "IPAddressClaim", klog.KObj(claim))
+		return claim, nil
+	}
+
+	if !metav1.IsControlledBy(claim, m) {
+		return nil, errors.Errorf("IPAddressClaim %s already exists and is not controlled by Machine %s", key.Name, m.Name)
+	}
+	return claim, nil
+}
+
+// reconcileDeleteIPAddressClaims deletes the IPAddressClaims of the Machine, releasing the IP addresses bound to them.
+func (r *Reconciler) reconcileDeleteIPAddressClaims(ctx context.Context, m *clusterv1.Machine) error {
+	for _, c := range m.Spec.IPAddressClaims {
+		claim := &ipamv1.IPAddressClaim{}
+		key := client.ObjectKey{Namespace: m.Namespace, Name: ipAddressClaimName(m, c.Name)}
+		if err := r.Client.Get(ctx, key, claim); err != nil {
+			if apierrors.IsNotFound(err) {
+				continue
+			}
+			return errors.Wrapf(err, "failed to get IPAddressClaim %s", key.Name)
+		}
+		if !metav1.IsControlledBy(claim, m) || !claim.DeletionTimestamp.IsZero() {
+			continue
+		}
+		if err := r.Client.Delete(ctx, claim); err != nil && !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to delete IPAddressClaim %s", key.Name)
+		}
+	}
+	return nil
+}
+
+// ipAddressClaimName returns the name of the IPAddressClaim for an IP address required by a Machine.
+func ipAddressClaimName(m *clusterv1.Machine, name string) string {
+	return fmt.Sprintf("%s-%s", m.Name, name)
+}
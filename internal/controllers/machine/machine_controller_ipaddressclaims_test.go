@@ -0,0 +1,202 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package machine
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	"sigs.k8s.io/cluster-api/util/conditions"
+)
+
+func TestReconcileIPAddressClaims(t *testing.T) {
+	cluster := &clusterv1.Cluster{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "test-cluster",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	poolRef := corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To(ipamv1.GroupVersion.Group),
+		Kind:     ipamv1.InClusterIPPoolKind,
+		Name:     "pool",
+	}
+	newMachine := func() *clusterv1.Machine {
+		return &clusterv1.Machine{
+			TypeMeta: metav1.TypeMeta{
+				APIVersion: clusterv1.GroupVersion.String(),
+				Kind:       "Machine",
+			},
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine-test",
+				Namespace: metav1.NamespaceDefault,
+				UID:       "machine-uid",
+			},
+			Spec: clusterv1.MachineSpec{
+				ClusterName: cluster.Name,
+				IPAddressClaims: []clusterv1.MachineIPAddressClaim{
+					{Name: "eth0", PoolRef: poolRef},
+				},
+			},
+		}
+	}
+
+	t.Run("does nothing when no IP addresses are required", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := newMachine()
+		m.Spec.IPAddressClaims = nil
+		c := fake.NewClientBuilder().WithObjects(m).Build()
+		r := &Reconciler{Client: c}
+		s := &scope{cluster: cluster, machine: m}
+
+		_, err := r.reconcileIPAddressClaims(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s.waitingForIPAddresses).To(BeFalse())
+		g.Expect(conditions.Has(m, clusterv1.IPAddressesBoundCondition)).To(BeFalse())
+	})
+
+	t.Run("creates the IPAddressClaims and waits for them to be bound", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := newMachine()
+		c := fake.NewClientBuilder().WithObjects(m).Build()
+		r := &Reconciler{Client: c}
+		s := &scope{cluster: cluster, machine: m}
+
+		_, err := r.reconcileIPAddressClaims(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s.waitingForIPAddresses).To(BeTrue())
+		g.Expect(conditions.IsFalse(m, clusterv1.IPAddressesBoundCondition)).To(BeTrue())
+		g.Expect(conditions.GetReason(m, clusterv1.IPAddressesBoundCondition)).To(Equal(clusterv1.WaitingForIPAddressesReason))
+
+		claim := &ipamv1.IPAddressClaim{}
+		g.Expect(c.Get(ctx, client.ObjectKey{Namespace: m.Namespace, Name: "machine-test-eth0"}, claim)).To(Succeed())
+		g.Expect(claim.Spec.PoolRef).To(Equal(poolRef))
+		g.Expect(claim.Labels).To(HaveKeyWithValue(clusterv1.ClusterNameLabel, cluster.Name))
+		g.Expect(metav1.IsControlledBy(claim, m)).To(BeTrue())
+	})
+
+	t.Run("reports the bound IP addresses", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := newMachine()
+		claim := &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:            "machine-test-eth0",
+				Namespace:       metav1.NamespaceDefault,
+				OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(m, clusterv1.GroupVersion.WithKind("Machine"))},
+			},
+			Spec: ipamv1.IPAddressClaimSpec{PoolRef: poolRef},
+			Status: ipamv1.IPAddressClaimStatus{
+				AddressRef: corev1.LocalObjectReference{Name: "machine-test-eth0"},
+			},
+		}
+		address := &ipamv1.IPAddress{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine-test-eth0",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: ipamv1.IPAddressSpec{
+				ClaimRef: corev1.LocalObjectReference{Name: claim.Name},
+				PoolRef:  poolRef,
+				Address:  "10.0.0.10",
+				Prefix:   24,
+				Gateway:  "10.0.0.1",
+			},
+		}
+		c := fake.NewClientBuilder().WithObjects(m, claim, address).Build()
+		r := &Reconciler{Client: c}
+		s := &scope{cluster: cluster, machine: m}
+
+		_, err := r.reconcileIPAddressClaims(ctx, s)
+		g.Expect(err).ToNot(HaveOccurred())
+		g.Expect(s.waitingForIPAddresses).To(BeFalse())
+		g.Expect(conditions.IsTrue(m, clusterv1.IPAddressesBoundCondition)).To(BeTrue())
+		g.Expect(m.Status.IPAddresses).To(Equal([]clusterv1.MachineIPAddress{
+			{Name: "eth0", ClaimName: "machine-test-eth0", Address: "10.0.0.10", Prefix: 24, Gateway: "10.0.0.1"},
+		}))
+	})
+
+	t.Run("fails when the IPAddressClaim is not controlled by the Machine", func(t *testing.T) {
+		g := NewWithT(t)
+
+		m := newMachine()
+		claim := &ipamv1.IPAddressClaim{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      "machine-test-eth0",
+				Namespace: metav1.NamespaceDefault,
+			},
+			Spec: ipamv1.IPAddressClaimSpec{PoolRef: poolRef},
+		}
+		c := fake.NewClientBuilder().WithObjects(m, claim).Build()
+		r := &Reconciler{Client: c}
+		s := &scope{cluster: cluster, machine: m}
+
+		_, err := r.reconcileIPAddressClaims(ctx, s)
+		g.Expect(err).To(HaveOccurred())
+		g.Expect(s.waitingForIPAddresses).To(BeTrue())
+		g.Expect(conditions.GetReason(m, clusterv1.IPAddressesBoundCondition)).To(Equal(clusterv1.IPAddressClaimFailedReason))
+	})
+}
+
+func TestReconcileDeleteIPAddressClaims(t *testing.T) {
+	g := NewWithT(t)
+
+	m := &clusterv1.Machine{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test",
+			Namespace: metav1.NamespaceDefault,
+			UID:       "machine-uid",
+		},
+		Spec: clusterv1.MachineSpec{
+			IPAddressClaims: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth0"},
+				{Name: "eth1"},
+			},
+		},
+	}
+	owned := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:            "machine-test-eth0",
+			Namespace:       metav1.NamespaceDefault,
+			OwnerReferences: []metav1.OwnerReference{*metav1.NewControllerRef(m, clusterv1.GroupVersion.WithKind("Machine"))},
+		},
+	}
+	notOwned := &ipamv1.IPAddressClaim{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      "machine-test-eth1",
+			Namespace: metav1.NamespaceDefault,
+		},
+	}
+	c := fake.NewClientBuilder().WithObjects(m, owned, notOwned).Build()
+	r := &Reconciler{Client: c}
+
+	g.Expect(r.reconcileDeleteIPAddressClaims(ctx, m)).To(Succeed())
+	err := c.Get(ctx, client.ObjectKeyFromObject(owned), &ipamv1.IPAddressClaim{})
+	g.Expect(apierrors.IsNotFound(err)).To(BeTrue())
+	g.Expect(c.Get(ctx, client.ObjectKeyFromObject(notOwned), &ipamv1.IPAddressClaim{})).To(Succeed())
+}
@@ -258,6 +258,13 @@ func (r *Reconciler) reconcileInfrastructure(ctx context.Context, s *scope) (ctr
 	cluster := s.cluster
 	m := s.machine
 
+	// Do not take ownership of the infrastructure until the IP addresses required by the Machine are bound;
+	// infrastructure providers wait for the owner Machine to be set before provisioning.
+	if s.waitingForIPAddresses {
+		log.Info("Waiting for IPAddressClaims to be bound before reconciling infrastructure")
+		return ctrl.Result{}, nil
+	}
+
 	// Call generic external reconciler.
 	infraReconcileResult, err := r.reconcileExternal(ctx, cluster, m, &m.Spec.InfrastructureRef)
 	if err != nil {
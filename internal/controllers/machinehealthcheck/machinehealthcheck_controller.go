@@ -52,6 +52,7 @@ import (
 	"sigs.k8s.io/cluster-api/util/conditions"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/sharding"
 )
 
 const (
@@ -80,6 +81,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
+
 	controller controller.Controller
 	recorder   record.EventRecorder
 }
@@ -103,11 +107,15 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Build(r)
+		).Build(r.Sharder.Reconciler(mgr.GetClient(), &clusterv1.MachineHealthCheck{}, r))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if err := r.Sharder.Watch(c, handler.EnqueueRequestsFromMapFunc(r.clusterToMachineHealthCheck)); err != nil {
+		return errors.Wrap(err, "failed to watch claimed shards")
+	}
+
 	r.controller = c
 	r.recorder = mgr.GetEventRecorderFor("machinehealthcheck-controller")
 	return nil
@@ -55,6 +55,7 @@ import (
 	clog "sigs.k8s.io/cluster-api/util/log"
 	"sigs.k8s.io/cluster-api/util/patch"
 	"sigs.k8s.io/cluster-api/util/predicates"
+	"sigs.k8s.io/cluster-api/util/sharding"
 )
 
 var (
@@ -87,6 +88,9 @@ type Reconciler struct {
 	// WatchFilterValue is the label value used to filter events prior to reconciliation.
 	WatchFilterValue string
 
+	// Sharder restricts reconciliation to the Clusters in the shards owned by this replica, if set.
+	Sharder *sharding.Sharder
+
 	ssaCache ssa.Cache
 	recorder record.EventRecorder
 }
@@ -97,7 +101,7 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 		return err
 	}
 
-	err = ctrl.NewControllerManagedBy(mgr).
+	c, err := ctrl.NewControllerManagedBy(mgr).
 		For(&clusterv1.MachineSet{}).
 		Owns(&clusterv1.Machine{}).
 		Watches(
@@ -116,11 +120,15 @@ func (r *Reconciler) SetupWithManager(ctx context.Context, mgr ctrl.Manager, opt
 					predicates.ResourceHasFilterLabel(ctrl.LoggerFrom(ctx), r.WatchFilterValue),
 				),
 			),
-		).Complete(r)
+		).Build(r.Sharder.Reconciler(mgr.GetClient(), &clusterv1.MachineSet{}, r))
 	if err != nil {
 		return errors.Wrap(err, "failed setting up with a controller manager")
 	}
 
+	if err := r.Sharder.Watch(c, handler.EnqueueRequestsFromMapFunc(clusterToMachineSets)); err != nil {
+		return errors.Wrap(err, "failed to watch claimed shards")
+	}
+
 	r.recorder = mgr.GetEventRecorderFor("machineset-controller")
 	r.ssaCache = ssa.NewCache()
 	return nil
@@ -623,15 +631,17 @@ func (r *Reconciler) computeDesiredMachine(machineSet *clusterv1.MachineSet, exi
 	desiredMachine.Spec.InfrastructureRef = corev1.ObjectReference{}
 	desiredMachine.Spec.Bootstrap.ConfigRef = nil
 
-	// If we are updating an existing Machine reuse the name, uid, infrastructureRef and bootstrap.configRef
-	// from the existingMachine.
+	// If we are updating an existing Machine reuse the name, uid, infrastructureRef, bootstrap.configRef
+	// and ipAddressClaims from the existingMachine.
 	// Note: we use UID to force SSA to update the existing Machine and to not accidentally create a new Machine.
 	// infrastructureRef and bootstrap.configRef remain the same for an existing Machine.
+	// ipAddressClaims are immutable, so changes to the MachineSet apply only to new Machines.
 	if existingMachine != nil {
 		desiredMachine.SetName(existingMachine.Name)
 		desiredMachine.SetUID(existingMachine.UID)
 		desiredMachine.Spec.Bootstrap.ConfigRef = existingMachine.Spec.Bootstrap.ConfigRef
 		desiredMachine.Spec.InfrastructureRef = existingMachine.Spec.InfrastructureRef
+		desiredMachine.Spec.IPAddressClaims = existingMachine.Spec.IPAddressClaims
 	}
 
 	// Set the in-place mutable fields.
@@ -1556,6 +1556,14 @@ func TestComputeDesiredMachine(t *testing.T) {
 		Name:       "bootstrap-template-1",
 		APIVersion: "bootstrap.cluster.x-k8s.io/v1beta1",
 	}
+	ipAddressClaims := []clusterv1.MachineIPAddressClaim{{
+		Name: "eth0",
+		PoolRef: corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+			Kind:     "InClusterIPPool",
+			Name:     "pool-2",
+		},
+	}}
 
 	ms := &clusterv1.MachineSet{
 		ObjectMeta: metav1.ObjectMeta{
@@ -1586,6 +1594,7 @@ func TestComputeDesiredMachine(t *testing.T) {
 					NodeDrainTimeout:        duration10s,
 					NodeVolumeDetachTimeout: duration10s,
 					NodeDeletionTimeout:     duration10s,
+					IPAddressClaims:         ipAddressClaims,
 				},
 			},
 		},
@@ -1608,6 +1617,7 @@ func TestComputeDesiredMachine(t *testing.T) {
 			NodeDrainTimeout:        duration10s,
 			NodeVolumeDetachTimeout: duration10s,
 			NodeDeletionTimeout:     duration10s,
+			IPAddressClaims:         ipAddressClaims,
 		},
 	}
 
@@ -1633,12 +1643,22 @@ func TestComputeDesiredMachine(t *testing.T) {
 	existingMachine.Spec.NodeDrainTimeout = duration5s
 	existingMachine.Spec.NodeDeletionTimeout = duration5s
 	existingMachine.Spec.NodeVolumeDetachTimeout = duration5s
+	existingMachine.Spec.IPAddressClaims = []clusterv1.MachineIPAddressClaim{{
+		Name: "eth0",
+		PoolRef: corev1.TypedLocalObjectReference{
+			APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+			Kind:     "InClusterIPPool",
+			Name:     "pool-1",
+		},
+	}}
 
 	expectedUpdatedMachine := skeletonMachine.DeepCopy()
 	expectedUpdatedMachine.Name = existingMachine.Name
 	expectedUpdatedMachine.UID = existingMachine.UID
 	expectedUpdatedMachine.Spec.InfrastructureRef = *existingMachine.Spec.InfrastructureRef.DeepCopy()
 	expectedUpdatedMachine.Spec.Bootstrap.ConfigRef = existingMachine.Spec.Bootstrap.ConfigRef.DeepCopy()
+	// ipAddressClaims are immutable, so the claims of the existing Machine are preserved.
+	expectedUpdatedMachine.Spec.IPAddressClaims = existingMachine.Spec.IPAddressClaims
 
 	tests := []struct {
 		name            string
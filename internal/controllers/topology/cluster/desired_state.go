@@ -19,6 +19,9 @@ package cluster
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
@@ -758,6 +761,11 @@ func computeMachineDeployment(ctx context.Context, s *scope.Scope, machineDeploy
 	delete(machineDeploymentAnnotations, clusterv1.ClusterTopologyDeferUpgradeAnnotation)
 	desiredMachineDeploymentObj.SetAnnotations(machineDeploymentAnnotations)
 	desiredMachineDeploymentObj.Spec.Template.Annotations = machineDeploymentAnnotations
+	// Keep the Cluster Autoscaler annotations in sync with the autoscaling configuration of the topology.
+	// NOTE: Those annotations are read from the MachineDeployment only, so they are not propagated to the Machines.
+	if machineDeploymentTopology.Autoscaling != nil {
+		desiredMachineDeploymentObj.SetAnnotations(util.MergeMap(autoscalingAnnotations(machineDeploymentTopology.Autoscaling), machineDeploymentAnnotations))
+	}
 
 	// Apply Labels
 	// NOTE: On top of all the labels applied to managed objects we are applying the ClusterTopologyMachineDeploymentLabel
@@ -1106,6 +1114,11 @@ func computeMachinePool(_ context.Context, s *scope.Scope, machinePoolTopology c
 	delete(machinePoolAnnotations, clusterv1.ClusterTopologyDeferUpgradeAnnotation)
 	desiredMachinePoolObj.SetAnnotations(machinePoolAnnotations)
 	desiredMachinePoolObj.Spec.Template.Annotations = machinePoolAnnotations
+	// Keep the Cluster Autoscaler annotations in sync with the autoscaling configuration of the topology.
+	// NOTE: Those annotations are read from the MachinePool only, so they are not propagated to the Machines.
+	if machinePoolTopology.Autoscaling != nil {
+		desiredMachinePoolObj.SetAnnotations(util.MergeMap(autoscalingAnnotations(machinePoolTopology.Autoscaling), machinePoolAnnotations))
+	}
 
 	// Apply Labels
 	// NOTE: On top of all the labels applied to managed objects we are applying the ClusterTopologyMachinePoolLabel
@@ -1411,3 +1424,44 @@ func selectorForMachineDeploymentMHC(md *clusterv1.MachineDeployment) *metav1.La
 	},
 	}
 }
+
+// autoscalingAnnotations returns the Cluster Autoscaler annotations corresponding to the autoscaling configuration
+// of a MachineDeployment or MachinePool topology.
+func autoscalingAnnotations(autoscaling *clusterv1.AutoscalingTopology) map[string]string {
+	annotations := map[string]string{
+		clusterv1.AutoscalerMinSizeAnnotation: strconv.Itoa(int(autoscaling.MinSize)),
+		clusterv1.AutoscalerMaxSizeAnnotation: strconv.Itoa(int(autoscaling.MaxSize)),
+	}
+
+	capacity := autoscaling.ScaleFromZero
+	if capacity == nil {
+		return annotations
+	}
+	if capacity.CPU != nil {
+		annotations[clusterv1.AutoscalerCapacityCPUAnnotation] = capacity.CPU.String()
+	}
+	if capacity.Memory != nil {
+		annotations[clusterv1.AutoscalerCapacityMemoryAnnotation] = capacity.Memory.String()
+	}
+	if capacity.GPU != nil {
+		annotations[clusterv1.AutoscalerCapacityGPUTypeAnnotation] = capacity.GPU.Type
+		annotations[clusterv1.AutoscalerCapacityGPUCountAnnotation] = strconv.Itoa(int(capacity.GPU.Count))
+	}
+	if len(capacity.Labels) > 0 {
+		labels := make([]string, 0, len(capacity.Labels))
+		for k, v := range capacity.Labels {
+			labels = append(labels, fmt.Sprintf("%s=%s", k, v))
+		}
+		// Sort the labels to get a stable annotation value and avoid unnecessary updates.
+		sort.Strings(labels)
+		annotations[clusterv1.AutoscalerCapacityLabelsAnnotation] = strings.Join(labels, ",")
+	}
+	if len(capacity.Taints) > 0 {
+		taints := make([]string, 0, len(capacity.Taints))
+		for _, taint := range capacity.Taints {
+			taints = append(taints, fmt.Sprintf("%s=%s:%s", taint.Key, taint.Value, taint.Effect))
+		}
+		annotations[clusterv1.AutoscalerCapacityTaintsAnnotation] = strings.Join(taints, ",")
+	}
+	return annotations
+}
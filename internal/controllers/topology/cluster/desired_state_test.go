@@ -24,6 +24,7 @@ import (
 	"github.com/google/go-cmp/cmp"
 	. "github.com/onsi/gomega"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -3053,3 +3054,55 @@ func TestCalculateRefDesiredAPIVersion(t *testing.T) {
 		})
 	}
 }
+
+func Test_autoscalingAnnotations(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoscaling *clusterv1.AutoscalingTopology
+		want        map[string]string
+	}{
+		{
+			name:        "min and max size",
+			autoscaling: &clusterv1.AutoscalingTopology{MinSize: 1, MaxSize: 5},
+			want: map[string]string{
+				clusterv1.AutoscalerMinSizeAnnotation: "1",
+				clusterv1.AutoscalerMaxSizeAnnotation: "5",
+			},
+		},
+		{
+			name: "scale from zero capacity",
+			autoscaling: &clusterv1.AutoscalingTopology{
+				MinSize: 0,
+				MaxSize: 3,
+				ScaleFromZero: &clusterv1.ScaleFromZeroCapacity{
+					CPU:    ptr.To(resource.MustParse("4")),
+					Memory: ptr.To(resource.MustParse("16Gi")),
+					GPU:    &clusterv1.GPUCapacity{Type: "nvidia.com/gpu", Count: 2},
+					Labels: map[string]string{"b": "2", "a": "1"},
+					Taints: []corev1.Taint{
+						{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule},
+						{Key: "dedicated", Value: "ml", Effect: corev1.TaintEffectNoExecute},
+					},
+				},
+			},
+			want: map[string]string{
+				clusterv1.AutoscalerMinSizeAnnotation:          "0",
+				clusterv1.AutoscalerMaxSizeAnnotation:          "3",
+				clusterv1.AutoscalerCapacityCPUAnnotation:      "4",
+				clusterv1.AutoscalerCapacityMemoryAnnotation:   "16Gi",
+				clusterv1.AutoscalerCapacityGPUTypeAnnotation:  "nvidia.com/gpu",
+				clusterv1.AutoscalerCapacityGPUCountAnnotation: "2",
+				clusterv1.AutoscalerCapacityLabelsAnnotation:   "a=1,b=2",
+				clusterv1.AutoscalerCapacityTaintsAnnotation:   "gpu=true:NoSchedule,dedicated=ml:NoExecute",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(autoscalingAnnotations(tt.autoscaling)).To(Equal(tt.want))
+		})
+	}
+}
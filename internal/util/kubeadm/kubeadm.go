@@ -17,13 +17,20 @@ limitations under the License.
 // Package kubeadm contains utils related to kubeadm.
 package kubeadm
 
-import "github.com/blang/semver/v4"
+import (
+	"github.com/blang/semver/v4"
+
+	"sigs.k8s.io/cluster-api/util/version"
+)
 
 const (
 	// DefaultImageRepository is the new default Kubernetes image registry.
 	DefaultImageRepository = "registry.k8s.io"
 	// OldDefaultImageRepository is the old default Kubernetes image registry.
 	OldDefaultImageRepository = "k8s.gcr.io"
+
+	// EtcdLearnerModeFeatureGate is the kubeadm feature gate to join control plane machines as etcd learners.
+	EtcdLearnerModeFeatureGate = "EtcdLearnerMode"
 )
 
 var (
@@ -34,8 +41,33 @@ var (
 	// NextKubernetesVersionImageRegistryMigration is the next minor version after
 	// the default image registry in kubeadm changed to registry.k8s.io.
 	NextKubernetesVersionImageRegistryMigration = semver.MustParse("1.26.0")
+
+	// MinKubernetesVersionEtcdLearnerMode is the first Kubernetes minor version where kubeadm can join
+	// control plane machines as etcd learners, using the EtcdLearnerMode feature gate.
+	MinKubernetesVersionEtcdLearnerMode = semver.MustParse("1.27.0")
+
+	// MinEtcdVersionLearners is the first etcd minor version supporting learners.
+	MinEtcdVersionLearners = semver.MustParse("3.4.0")
 )
 
+// IsEtcdLearnerModeSupported returns true if kubeadm can join control plane machines as learners of a stacked
+// etcd cluster for the given Kubernetes version and etcd image tag.
+// NOTE: If the etcd image tag is empty, kubeadm uses the default etcd version for the given Kubernetes version,
+// which is etcd >= v3.5 for all the Kubernetes versions supporting the EtcdLearnerMode feature gate.
+func IsEtcdLearnerModeSupported(kubernetesVersion semver.Version, etcdImageTag string) bool {
+	if version.Compare(kubernetesVersion, MinKubernetesVersionEtcdLearnerMode, version.WithoutPreReleases()) < 0 {
+		return false
+	}
+	if etcdImageTag == "" {
+		return true
+	}
+	etcdVersion, err := version.ParseMajorMinorPatchTolerant(etcdImageTag)
+	if err != nil {
+		return false
+	}
+	return version.Compare(etcdVersion, MinEtcdVersionLearners, version.WithoutPreReleases()) >= 0
+}
+
 // GetDefaultRegistry returns the default registry of the given kubeadm version.
 func GetDefaultRegistry(version semver.Version) string {
 	// If version <= v1.22.16 return k8s.gcr.io
@@ -66,3 +66,28 @@ func TestGetDefaultRegistry(t *testing.T) {
 		})
 	}
 }
+
+func TestIsEtcdLearnerModeSupported(t *testing.T) {
+	tests := []struct {
+		version      string
+		etcdImageTag string
+		expected     bool
+	}{
+		{version: "1.26.5", expected: false},
+		{version: "1.27.0-rc.0", expected: true},
+		{version: "1.27.0", expected: true},
+		{version: "1.29.1", expected: true},
+		{version: "1.29.1", etcdImageTag: "3.5.10-0", expected: true},
+		{version: "1.29.1", etcdImageTag: "v3.4.0", expected: true},
+		{version: "1.29.1", etcdImageTag: "3.3.17-0", expected: false},
+		{version: "1.29.1", etcdImageTag: "latest", expected: false},
+		{version: "1.26.5", etcdImageTag: "3.5.10-0", expected: false},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprintf("version: %s, etcd image tag: %s", tt.version, tt.etcdImageTag), func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(IsEtcdLearnerModeSupported(semver.MustParse(tt.version), tt.etcdImageTag)).To(Equal(tt.expected))
+		})
+	}
+}
@@ -26,6 +26,7 @@ import (
 
 	"github.com/blang/semver/v4"
 	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/runtime"
 	kerrors "k8s.io/apimachinery/pkg/util/errors"
@@ -269,6 +270,9 @@ func (webhook *Cluster) validateTopology(ctx context.Context, oldCluster, newClu
 	// metadata in topology should be valid
 	allErrs = append(allErrs, validateTopologyMetadata(newCluster.Spec.Topology, fldPath)...)
 
+	// autoscaling in topology should be valid
+	allErrs = append(allErrs, validateTopologyAutoscaling(newCluster.Spec.Topology, fldPath)...)
+
 	// upgrade concurrency should be a numeric value.
 	if concurrency, ok := newCluster.Annotations[clusterv1.ClusterTopologyUpgradeConcurrencyAnnotation]; ok {
 		concurrencyAnnotationField := field.NewPath("metadata", "annotations", clusterv1.ClusterTopologyUpgradeConcurrencyAnnotation)
@@ -882,3 +886,112 @@ func validateTopologyMetadata(topology *clusterv1.Topology, fldPath *field.Path)
 	}
 	return allErrs
 }
+
+// autoscalerAnnotations are the Cluster Autoscaler annotations kept in sync by the topology controller
+// for MachineDeployments and MachinePools with autoscaling.
+var autoscalerAnnotations = []string{
+	clusterv1.AutoscalerMinSizeAnnotation,
+	clusterv1.AutoscalerMaxSizeAnnotation,
+	clusterv1.AutoscalerCapacityCPUAnnotation,
+	clusterv1.AutoscalerCapacityMemoryAnnotation,
+	clusterv1.AutoscalerCapacityGPUTypeAnnotation,
+	clusterv1.AutoscalerCapacityGPUCountAnnotation,
+	clusterv1.AutoscalerCapacityLabelsAnnotation,
+	clusterv1.AutoscalerCapacityTaintsAnnotation,
+}
+
+func validateTopologyAutoscaling(topology *clusterv1.Topology, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if topology.Workers == nil {
+		return allErrs
+	}
+	for idx, md := range topology.Workers.MachineDeployments {
+		allErrs = append(allErrs, validateAutoscaling(md.Autoscaling, md.Replicas, md.Metadata,
+			fldPath.Child("workers", "machineDeployments").Index(idx))...)
+	}
+	for idx, mp := range topology.Workers.MachinePools {
+		allErrs = append(allErrs, validateAutoscaling(mp.Autoscaling, mp.Replicas, mp.Metadata,
+			fldPath.Child("workers", "machinePools").Index(idx))...)
+	}
+	return allErrs
+}
+
+// validateAutoscaling validates the autoscaling configuration of a MachineDeployment or MachinePool topology.
+// Replicas and the Cluster Autoscaler annotations can't be set together with autoscaling, otherwise the topology
+// controller and the Cluster Autoscaler would fight over them.
+func validateAutoscaling(autoscaling *clusterv1.AutoscalingTopology, replicas *int32, metadata clusterv1.ObjectMeta, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	if autoscaling == nil {
+		return allErrs
+	}
+
+	if replicas != nil {
+		allErrs = append(allErrs, field.Forbidden(fldPath.Child("replicas"),
+			"must not be set when autoscaling is set, replicas are managed by the Cluster Autoscaler"))
+	}
+	for _, annotation := range autoscalerAnnotations {
+		if _, ok := metadata.Annotations[annotation]; ok {
+			allErrs = append(allErrs, field.Forbidden(fldPath.Child("metadata", "annotations").Key(annotation),
+				"must not be set when autoscaling is set, use autoscaling instead"))
+		}
+	}
+
+	autoscalingPath := fldPath.Child("autoscaling")
+	if autoscaling.MinSize < 0 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("minSize"), autoscaling.MinSize, "must be greater than or equal to 0"))
+	}
+	if autoscaling.MaxSize < 1 {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxSize"), autoscaling.MaxSize, "must be greater than 0"))
+	}
+	if autoscaling.MaxSize < autoscaling.MinSize {
+		allErrs = append(allErrs, field.Invalid(autoscalingPath.Child("maxSize"), autoscaling.MaxSize, "must be greater than or equal to minSize"))
+	}
+
+	capacity := autoscaling.ScaleFromZero
+	if capacity == nil {
+		return allErrs
+	}
+	capacityPath := autoscalingPath.Child("scaleFromZero")
+	if capacity.CPU != nil && capacity.CPU.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(capacityPath.Child("cpu"), capacity.CPU.String(), "must be greater than 0"))
+	}
+	if capacity.Memory != nil && capacity.Memory.Sign() <= 0 {
+		allErrs = append(allErrs, field.Invalid(capacityPath.Child("memory"), capacity.Memory.String(), "must be greater than 0"))
+	}
+	if capacity.GPU != nil {
+		if capacity.GPU.Type == "" {
+			allErrs = append(allErrs, field.Required(capacityPath.Child("gpu", "type"), "must be set"))
+		} else {
+			for _, msg := range validation.IsQualifiedName(capacity.GPU.Type) {
+				allErrs = append(allErrs, field.Invalid(capacityPath.Child("gpu", "type"), capacity.GPU.Type, msg))
+			}
+		}
+		if capacity.GPU.Count < 1 {
+			allErrs = append(allErrs, field.Invalid(capacityPath.Child("gpu", "count"), capacity.GPU.Count, "must be greater than 0"))
+		}
+	}
+	for key, value := range capacity.Labels {
+		for _, msg := range validation.IsQualifiedName(key) {
+			allErrs = append(allErrs, field.Invalid(capacityPath.Child("labels"), key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(value) {
+			allErrs = append(allErrs, field.Invalid(capacityPath.Child("labels").Key(key), value, msg))
+		}
+	}
+	for idx, taint := range capacity.Taints {
+		taintPath := capacityPath.Child("taints").Index(idx)
+		for _, msg := range validation.IsQualifiedName(taint.Key) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("key"), taint.Key, msg))
+		}
+		for _, msg := range validation.IsValidLabelValue(taint.Value) {
+			allErrs = append(allErrs, field.Invalid(taintPath.Child("value"), taint.Value, msg))
+		}
+		switch taint.Effect {
+		case corev1.TaintEffectNoSchedule, corev1.TaintEffectPreferNoSchedule, corev1.TaintEffectNoExecute:
+		default:
+			allErrs = append(allErrs, field.NotSupported(taintPath.Child("effect"), taint.Effect,
+				[]string{string(corev1.TaintEffectNoSchedule), string(corev1.TaintEffectPreferNoSchedule), string(corev1.TaintEffectNoExecute)}))
+		}
+	}
+	return allErrs
+}
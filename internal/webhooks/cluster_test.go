@@ -25,10 +25,12 @@ import (
 	"github.com/pkg/errors"
 	corev1 "k8s.io/api/core/v1"
 	apiextensionsv1 "k8s.io/apiextensions-apiserver/pkg/apis/apiextensions/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/validation/field"
 	utilfeature "k8s.io/component-base/featuregate/testing"
 	"k8s.io/utils/ptr"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -3095,3 +3097,77 @@ type fakeClusterCacheTracker struct {
 func (f *fakeClusterCacheTracker) GetReader(_ context.Context, _ types.NamespacedName) (client.Reader, error) {
 	return f.client, nil
 }
+
+func Test_validateAutoscaling(t *testing.T) {
+	tests := []struct {
+		name        string
+		autoscaling *clusterv1.AutoscalingTopology
+		replicas    *int32
+		metadata    clusterv1.ObjectMeta
+		wantErrs    int
+	}{
+		{
+			name: "should pass without autoscaling",
+		},
+		{
+			name:     "should pass with replicas and without autoscaling",
+			replicas: ptr.To[int32](3),
+		},
+		{
+			name: "should pass with a valid autoscaling configuration",
+			autoscaling: &clusterv1.AutoscalingTopology{
+				MinSize: 0,
+				MaxSize: 5,
+				ScaleFromZero: &clusterv1.ScaleFromZeroCapacity{
+					CPU:    ptr.To(resource.MustParse("4")),
+					Memory: ptr.To(resource.MustParse("16Gi")),
+					GPU:    &clusterv1.GPUCapacity{Type: "nvidia.com/gpu", Count: 1},
+					Labels: map[string]string{"node.kubernetes.io/instance-type": "large"},
+					Taints: []corev1.Taint{{Key: "gpu", Value: "true", Effect: corev1.TaintEffectNoSchedule}},
+				},
+			},
+		},
+		{
+			name:        "should fail if replicas are set together with autoscaling",
+			autoscaling: &clusterv1.AutoscalingTopology{MinSize: 1, MaxSize: 3},
+			replicas:    ptr.To[int32](2),
+			wantErrs:    1,
+		},
+		{
+			name:        "should fail if the autoscaler annotations are set together with autoscaling",
+			autoscaling: &clusterv1.AutoscalingTopology{MinSize: 1, MaxSize: 3},
+			metadata: clusterv1.ObjectMeta{Annotations: map[string]string{
+				clusterv1.AutoscalerMinSizeAnnotation: "1",
+			}},
+			wantErrs: 1,
+		},
+		{
+			name:        "should fail if maxSize is lower than minSize",
+			autoscaling: &clusterv1.AutoscalingTopology{MinSize: 3, MaxSize: 2},
+			wantErrs:    1,
+		},
+		{
+			name: "should fail with an invalid scale from zero capacity",
+			autoscaling: &clusterv1.AutoscalingTopology{
+				MinSize: 0,
+				MaxSize: 5,
+				ScaleFromZero: &clusterv1.ScaleFromZeroCapacity{
+					CPU:    ptr.To(resource.MustParse("0")),
+					GPU:    &clusterv1.GPUCapacity{Count: 0},
+					Labels: map[string]string{"foo": "bar,baz"},
+					Taints: []corev1.Taint{{Key: "gpu", Effect: "Invalid"}},
+				},
+			},
+			wantErrs: 5,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			errs := validateAutoscaling(tt.autoscaling, tt.replicas, tt.metadata, field.NewPath("spec", "topology", "workers", "machineDeployments").Index(0))
+			g.Expect(errs).To(HaveLen(tt.wantErrs))
+		})
+	}
+}
@@ -22,9 +22,12 @@ import (
 	"strings"
 	"time"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/apimachinery/pkg/util/validation"
 	"k8s.io/apimachinery/pkg/util/validation/field"
 	ctrl "sigs.k8s.io/controller-runtime"
 	"sigs.k8s.io/controller-runtime/pkg/webhook"
@@ -167,8 +170,43 @@ func (webhook *Machine) validate(oldM, newM *clusterv1.Machine) error {
 		}
 	}
 
+	allErrs = append(allErrs, validateMachineIPAddressClaims(newM.Spec.IPAddressClaims, specPath.Child("ipAddressClaims"))...)
+	if oldM != nil && !equality.Semantic.DeepEqual(oldM.Spec.IPAddressClaims, newM.Spec.IPAddressClaims) {
+		allErrs = append(
+			allErrs,
+			field.Forbidden(specPath.Child("ipAddressClaims"), "field is immutable"),
+		)
+	}
+
 	if len(allErrs) == 0 {
 		return nil
 	}
 	return apierrors.NewInvalid(clusterv1.GroupVersion.WithKind("Machine").GroupKind(), newM.Name, allErrs)
 }
+
+// validateMachineIPAddressClaims validates the IP addresses required by a Machine.
+// Names are used to compute the name of the IPAddressClaims, so they must be valid DNS labels.
+func validateMachineIPAddressClaims(claims []clusterv1.MachineIPAddressClaim, fldPath *field.Path) field.ErrorList {
+	var allErrs field.ErrorList
+	names := sets.Set[string]{}
+	for i, c := range claims {
+		if errs := validation.IsDNS1123Label(c.Name); len(errs) != 0 {
+			allErrs = append(allErrs, field.Invalid(fldPath.Index(i).Child("name"), c.Name, strings.Join(errs, "; ")))
+		}
+		if names.Has(c.Name) {
+			allErrs = append(allErrs, field.Duplicate(fldPath.Index(i).Child("name"), c.Name))
+		}
+		names.Insert(c.Name)
+
+		if c.PoolRef.APIGroup == nil || *c.PoolRef.APIGroup == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("poolRef", "apiGroup"), "must be set"))
+		}
+		if c.PoolRef.Kind == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("poolRef", "kind"), "must be set"))
+		}
+		if c.PoolRef.Name == "" {
+			allErrs = append(allErrs, field.Required(fldPath.Index(i).Child("poolRef", "name"), "must be set"))
+		}
+	}
+	return allErrs
+}
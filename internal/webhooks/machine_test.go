@@ -220,6 +220,85 @@ func TestMachineClusterNameImmutable(t *testing.T) {
 	}
 }
 
+func TestMachineIPAddressClaimsValidation(t *testing.T) {
+	poolRef := corev1.TypedLocalObjectReference{
+		APIGroup: ptr.To("ipam.cluster.x-k8s.io"),
+		Kind:     "InClusterIPPool",
+		Name:     "pool",
+	}
+	tests := []struct {
+		name      string
+		old       []clusterv1.MachineIPAddressClaim
+		new       []clusterv1.MachineIPAddressClaim
+		expectErr bool
+	}{
+		{
+			name: "should succeed with valid claims",
+			new: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth0", PoolRef: poolRef},
+				{Name: "eth1", PoolRef: poolRef},
+			},
+			expectErr: false,
+		},
+		{
+			name: "should return error with duplicated names",
+			new: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth0", PoolRef: poolRef},
+				{Name: "eth0", PoolRef: poolRef},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when the name is not a valid DNS label",
+			new: []clusterv1.MachineIPAddressClaim{
+				{Name: "Eth_0", PoolRef: poolRef},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when the pool apiGroup is not set",
+			new: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth0", PoolRef: corev1.TypedLocalObjectReference{Kind: "InClusterIPPool", Name: "pool"}},
+			},
+			expectErr: true,
+		},
+		{
+			name: "should return error when claims are changed",
+			old: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth0", PoolRef: poolRef},
+			},
+			new: []clusterv1.MachineIPAddressClaim{
+				{Name: "eth1", PoolRef: poolRef},
+			},
+			expectErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			newMachine := &clusterv1.Machine{
+				Spec: clusterv1.MachineSpec{
+					Bootstrap:       clusterv1.Bootstrap{DataSecretName: ptr.To("test")},
+					IPAddressClaims: tt.new,
+				},
+			}
+			oldMachine := newMachine.DeepCopy()
+			if tt.old != nil {
+				oldMachine.Spec.IPAddressClaims = tt.old
+			}
+
+			_, err := (&Machine{}).ValidateUpdate(ctx, oldMachine, newMachine)
+			if tt.expectErr {
+				g.Expect(err).To(HaveOccurred())
+			} else {
+				g.Expect(err).ToNot(HaveOccurred())
+			}
+		})
+	}
+}
+
 func TestMachineVersionValidation(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -277,6 +277,8 @@ func (webhook *MachineDeployment) validate(oldMD, newMD *clusterv1.MachineDeploy
 	// Validate the metadata of the template.
 	allErrs = append(allErrs, newMD.Spec.Template.ObjectMeta.Validate(specPath.Child("template", "metadata"))...)
 
+	allErrs = append(allErrs, validateMachineIPAddressClaims(newMD.Spec.Template.Spec.IPAddressClaims, specPath.Child("template", "spec", "ipAddressClaims"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
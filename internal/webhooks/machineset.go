@@ -213,6 +213,8 @@ func (webhook *MachineSet) validate(oldMS, newMS *clusterv1.MachineSet) error {
 	// Validate the metadata of the template.
 	allErrs = append(allErrs, newMS.Spec.Template.ObjectMeta.Validate(specPath.Child("template", "metadata"))...)
 
+	allErrs = append(allErrs, validateMachineIPAddressClaims(newMS.Spec.Template.Spec.IPAddressClaims, specPath.Child("template", "spec", "ipAddressClaims"))...)
+
 	if len(allErrs) == 0 {
 		return nil
 	}
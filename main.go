@@ -55,6 +55,7 @@ import (
 	expv1 "sigs.k8s.io/cluster-api/exp/api/v1beta1"
 	expcontrollers "sigs.k8s.io/cluster-api/exp/controllers"
 	ipamv1 "sigs.k8s.io/cluster-api/exp/ipam/api/v1beta1"
+	ipamcontrollers "sigs.k8s.io/cluster-api/exp/ipam/controllers"
 	expipamwebhooks "sigs.k8s.io/cluster-api/exp/ipam/webhooks"
 	runtimev1 "sigs.k8s.io/cluster-api/exp/runtime/api/v1alpha1"
 	runtimecatalog "sigs.k8s.io/cluster-api/exp/runtime/catalog"
@@ -72,6 +73,7 @@ import (
 	runtimeregistry "sigs.k8s.io/cluster-api/internal/runtime/registry"
 	runtimewebhooks "sigs.k8s.io/cluster-api/internal/webhooks/runtime"
 	"sigs.k8s.io/cluster-api/util/flags"
+	"sigs.k8s.io/cluster-api/util/sharding"
 	"sigs.k8s.io/cluster-api/version"
 	"sigs.k8s.io/cluster-api/webhooks"
 )
@@ -87,6 +89,9 @@ var (
 	leaderElectionLeaseDuration time.Duration
 	leaderElectionRenewDeadline time.Duration
 	leaderElectionRetryPeriod   time.Duration
+	shards                      int
+	shardLeaseDuration          time.Duration
+	shardRenewPeriod            time.Duration
 	watchFilterValue            string
 	watchNamespace              string
 	profilerAddress             string
@@ -103,6 +108,10 @@ var (
 	// core Cluster API specific flags.
 	clusterTopologyConcurrency     int
 	clusterCacheTrackerConcurrency int
+	clusterCacheTrackerMaxClusters int
+	clusterCacheTrackerIdleTimeout time.Duration
+	clusterCacheTrackerClientQPS   float32
+	clusterCacheTrackerClientBurst int
 	clusterClassConcurrency        int
 	clusterConcurrency             int
 	extensionConfigConcurrency     int
@@ -111,6 +120,7 @@ var (
 	machineDeploymentConcurrency   int
 	machinePoolConcurrency         int
 	clusterResourceSetConcurrency  int
+	inClusterIPAMConcurrency       int
 	machineHealthCheckConcurrency  int
 	nodeDrainClientTimeout         time.Duration
 )
@@ -155,6 +165,15 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.DurationVar(&leaderElectionRetryPeriod, "leader-elect-retry-period", 2*time.Second,
 		"Duration the LeaderElector clients should wait between tries of actions (duration string)")
 
+	fs.IntVar(&shards, "shards", 0,
+		"Number of shards Clusters are distributed across; each replica of the controller manager claims a share of the shards and only reconciles the Clusters in its shards. 0 disables sharding")
+
+	fs.DurationVar(&shardLeaseDuration, "shard-lease-duration", 15*time.Second,
+		"Duration after which the shards of a replica which stopped renewing them are claimed by other replicas (duration string)")
+
+	fs.DurationVar(&shardRenewPeriod, "shard-renew-period", 5*time.Second,
+		"Interval at which a replica renews its shards and rebalances shards with the other replicas (duration string)")
+
 	fs.StringVar(&watchNamespace, "namespace", "",
 		"Namespace that the controller watches to reconcile cluster-api objects. If unspecified, the controller watches for cluster-api objects across all namespaces.")
 
@@ -179,6 +198,18 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterCacheTrackerConcurrency, "clustercachetracker-concurrency", 10,
 		"Number of clusters to process simultaneously")
 
+	fs.IntVar(&clusterCacheTrackerMaxClusters, "clustercachetracker-max-clusters", 0,
+		"Maximum number of workload clusters with a cached client; when reached, the cache of the least recently used workload cluster without watches is stopped. 0 means unlimited")
+
+	fs.DurationVar(&clusterCacheTrackerIdleTimeout, "clustercachetracker-idle-timeout", 0,
+		"Duration after which the cache of a workload cluster without watches which has not been accessed is stopped. 0 means caches are never stopped because they are idle")
+
+	fs.Float32Var(&clusterCacheTrackerClientQPS, "clustercachetracker-client-qps", 0,
+		"Maximum queries per second from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
+	fs.IntVar(&clusterCacheTrackerClientBurst, "clustercachetracker-client-burst", 0,
+		"Maximum burst for throttle from the controller client to the apiserver of each workload cluster. 0 means the client-go default is used")
+
 	fs.IntVar(&extensionConfigConcurrency, "extensionconfig-concurrency", 10,
 		"Number of extension configs to process simultaneously")
 
@@ -197,6 +228,9 @@ func InitFlags(fs *pflag.FlagSet) {
 	fs.IntVar(&clusterResourceSetConcurrency, "clusterresourceset-concurrency", 10,
 		"Number of cluster resource sets to process simultaneously")
 
+	fs.IntVar(&inClusterIPAMConcurrency, "inclusteripam-concurrency", 10,
+		"Number of IP address claims and in-cluster IP pools to process simultaneously")
+
 	fs.IntVar(&machineHealthCheckConcurrency, "machinehealthcheck-concurrency", 10,
 		"Number of machine health checks to process simultaneously")
 
@@ -384,6 +418,20 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		os.Exit(1)
 	}
 
+	var sharder *sharding.Sharder
+	if shards > 0 {
+		sharder, err = sharding.New(mgr, sharding.Options{
+			Shards:        shards,
+			Name:          "controller-sharding-capi",
+			LeaseDuration: shardLeaseDuration,
+			RenewPeriod:   shardRenewPeriod,
+		})
+		if err != nil {
+			setupLog.Error(err, "unable to create sharder")
+			os.Exit(1)
+		}
+	}
+
 	// Set up a ClusterCacheTracker and ClusterCacheReconciler to provide to controllers
 	// requiring a connection to a remote cluster
 	tracker, err := remote.NewClusterCacheTracker(
@@ -391,6 +439,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		remote.ClusterCacheTrackerOptions{
 			SecretCachingClient: secretCachingClient,
 			ControllerName:      controllerName,
+			MaxAccessors:        clusterCacheTrackerMaxClusters,
+			AccessorIdleTimeout: clusterCacheTrackerIdleTimeout,
+			ClientQPS:           clusterCacheTrackerClientQPS,
+			ClientBurst:         clusterCacheTrackerClientBurst,
 			Log:                 &ctrl.Log,
 			Indexes:             []remote.Index{remote.NodeProviderIDIndex},
 		},
@@ -404,7 +456,7 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterCacheTrackerConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(clusterCacheTrackerConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "ClusterCacheReconciler")
 		os.Exit(1)
 	}
@@ -450,7 +502,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 			Tracker:                   tracker,
 			UnstructuredCachingClient: unstructuredCachingClient,
 			WatchFilterValue:          watchFilterValue,
-		}).SetupWithManager(ctx, mgr, concurrency(clusterTopologyConcurrency)); err != nil {
+			Sharder:                   sharder,
+		}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(clusterTopologyConcurrency))); err != nil {
 			setupLog.Error(err, "unable to create controller", "controller", "ClusterTopology")
 			os.Exit(1)
 		}
@@ -490,8 +543,10 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		Client:                    mgr.GetClient(),
 		UnstructuredCachingClient: unstructuredCachingClient,
 		APIReader:                 mgr.GetAPIReader(),
+		Tracker:                   tracker,
 		WatchFilterValue:          watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(clusterConcurrency)); err != nil {
+		Sharder:                   sharder,
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(clusterConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Cluster")
 		os.Exit(1)
 	}
@@ -501,8 +556,9 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		APIReader:                 mgr.GetAPIReader(),
 		Tracker:                   tracker,
 		WatchFilterValue:          watchFilterValue,
+		Sharder:                   sharder,
 		NodeDrainClientTimeout:    nodeDrainClientTimeout,
-	}).SetupWithManager(ctx, mgr, concurrency(machineConcurrency)); err != nil {
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(machineConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "Machine")
 		os.Exit(1)
 	}
@@ -512,7 +568,8 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		APIReader:                 mgr.GetAPIReader(),
 		Tracker:                   tracker,
 		WatchFilterValue:          watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineSetConcurrency)); err != nil {
+		Sharder:                   sharder,
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(machineSetConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineSet")
 		os.Exit(1)
 	}
@@ -556,11 +613,37 @@ func setupReconcilers(ctx context.Context, mgr ctrl.Manager) webhooks.ClusterCac
 		}
 	}
 
+	if feature.Gates.Enabled(feature.InClusterIPAM) {
+		if err := (&ipamcontrollers.IPAddressClaimReconciler{
+			Client:           mgr.GetClient(),
+			APIReader:        mgr.GetAPIReader(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPAMConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "IPAddressClaim")
+			os.Exit(1)
+		}
+		if err := (&ipamcontrollers.InClusterIPPoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPAMConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "InClusterIPPool")
+			os.Exit(1)
+		}
+		if err := (&ipamcontrollers.GlobalInClusterIPPoolReconciler{
+			Client:           mgr.GetClient(),
+			WatchFilterValue: watchFilterValue,
+		}).SetupWithManager(ctx, mgr, concurrency(inClusterIPAMConcurrency)); err != nil {
+			setupLog.Error(err, "unable to create controller", "controller", "GlobalInClusterIPPool")
+			os.Exit(1)
+		}
+	}
+
 	if err := (&controllers.MachineHealthCheckReconciler{
 		Client:           mgr.GetClient(),
 		Tracker:          tracker,
 		WatchFilterValue: watchFilterValue,
-	}).SetupWithManager(ctx, mgr, concurrency(machineHealthCheckConcurrency)); err != nil {
+		Sharder:          sharder,
+	}).SetupWithManager(ctx, mgr, sharder.ControllerOptions(concurrency(machineHealthCheckConcurrency))); err != nil {
 		setupLog.Error(err, "unable to create controller", "controller", "MachineHealthCheck")
 		os.Exit(1)
 	}
@@ -641,6 +724,14 @@ func setupWebhooks(mgr ctrl.Manager, tracker webhooks.ClusterCacheTrackerReader)
 		setupLog.Error(err, "unable to create webhook", "webhook", "IPAddressClaim")
 		os.Exit(1)
 	}
+	if err := (&expipamwebhooks.InClusterIPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "InClusterIPPool")
+		os.Exit(1)
+	}
+	if err := (&expipamwebhooks.GlobalInClusterIPPool{}).SetupWebhookWithManager(mgr); err != nil {
+		setupLog.Error(err, "unable to create webhook", "webhook", "GlobalInClusterIPPool")
+		os.Exit(1)
+	}
 }
 
 func concurrency(c int) controller.Options {
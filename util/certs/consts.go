@@ -28,4 +28,8 @@ const (
 	// ClientCertificateRenewalDuration determines when a certificate should
 	// be regerenated.
 	ClientCertificateRenewalDuration = DefaultCertDuration / 2
+
+	// CertificateAuthorityRenewalDuration determines when a certificate authority
+	// should be rotated.
+	CertificateAuthorityRenewalDuration = DefaultCertDuration
 )
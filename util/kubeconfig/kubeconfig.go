@@ -18,6 +18,7 @@ limitations under the License.
 package kubeconfig
 
 import (
+	"bytes"
 	"context"
 	"crypto"
 	"crypto/x509"
@@ -176,6 +177,45 @@ func NeedsClientCertRotation(configSecret *corev1.Secret, threshold time.Duratio
 	return false, nil
 }
 
+// NeedsCertificateAuthorityRotation returns whether the Kubeconfig secret is not trusting the given certificate authority
+// bundle, or if any of its client certificates is not signed by the first certificate authority in the bundle.
+func NeedsCertificateAuthorityRotation(configSecret *corev1.Secret, caData []byte) (bool, error) {
+	data, err := toKubeconfigBytes(configSecret)
+	if err != nil {
+		return false, err
+	}
+
+	config, err := clientcmd.Load(data)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to convert kubeconfig Secret into a clientcmdapi.Config")
+	}
+
+	caCert, err := certs.DecodeCertPEM(caData)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to decode CA Cert")
+	} else if caCert == nil {
+		return false, errors.New("certificate not found in CA data")
+	}
+
+	for _, cluster := range config.Clusters {
+		if !bytes.Equal(cluster.CertificateAuthorityData, caData) {
+			return true, nil
+		}
+	}
+
+	for _, authInfo := range config.AuthInfos {
+		cert, err := certs.DecodeCertPEM(authInfo.ClientCertificateData)
+		if err != nil {
+			return false, errors.Wrap(err, "failed to decode kubeconfig client certificate")
+		}
+		if cert == nil || cert.CheckSignatureFrom(caCert) != nil {
+			return true, nil
+		}
+	}
+
+	return false, nil
+}
+
 // RegenerateSecret creates and stores a new Kubeconfig in the given secret.
 func RegenerateSecret(ctx context.Context, c client.Client, configSecret *corev1.Secret) error {
 	clusterName, _, err := secret.ParseSecretName(configSecret.Name)
@@ -228,6 +268,9 @@ func generateKubeconfig(ctx context.Context, c client.Client, clusterName client
 	if err != nil {
 		return nil, errors.Wrap(err, "failed to generate a kubeconfig")
 	}
+	// Trust all the certificate authorities in the CA secret, e.g. both the previous and the new one while
+	// the certificate authority is being rotated.
+	cfg.Clusters[clusterName.Name].CertificateAuthorityData = clusterCA.Data[secret.TLSCrtDataName]
 
 	out, err := clientcmd.Write(*cfg)
 	if err != nil {
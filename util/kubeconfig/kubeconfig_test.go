@@ -380,6 +380,47 @@ func TestNeedsClientCertRotation(t *testing.T) {
 	g.Expect(NeedsClientCertRotation(kubeconfigSecret, certs.DefaultCertDuration-time.Hour)).To(BeFalse())
 }
 
+func TestNeedsCertificateAuthorityRotation(t *testing.T) {
+	g := NewWithT(t)
+	caKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	caCert, err := getTestCACert(caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	nextCAKey, err := certs.NewPrivateKey()
+	g.Expect(err).ToNot(HaveOccurred())
+	nextCACert, err := getTestCACert(nextCAKey)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	config, err := New("foo", "https://127:0.0.1:4003", caCert, caKey)
+	g.Expect(err).ToNot(HaveOccurred())
+	out, err := clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+
+	kubeconfigSecret := GenerateSecretWithOwner(
+		client.ObjectKey{
+			Name:      "foo",
+			Namespace: "test",
+		},
+		out,
+		metav1.OwnerReference{},
+	)
+
+	currentCAData := certs.EncodeCertPEM(caCert)
+	trustBundle := append(certs.EncodeCertPEM(caCert), certs.EncodeCertPEM(nextCACert)...)
+	nextTrustBundle := append(certs.EncodeCertPEM(nextCACert), certs.EncodeCertPEM(caCert)...)
+
+	g.Expect(NeedsCertificateAuthorityRotation(kubeconfigSecret, currentCAData)).To(BeFalse())
+	// The Kubeconfig must trust the new certificate authority.
+	g.Expect(NeedsCertificateAuthorityRotation(kubeconfigSecret, trustBundle)).To(BeTrue())
+	// The client certificate must be signed by the new certificate authority.
+	config.Clusters["foo"].CertificateAuthorityData = nextTrustBundle
+	out, err = clientcmd.Write(*config)
+	g.Expect(err).ToNot(HaveOccurred())
+	kubeconfigSecret.Data[secret.KubeconfigDataName] = out
+	g.Expect(NeedsCertificateAuthorityRotation(kubeconfigSecret, nextTrustBundle)).To(BeTrue())
+}
+
 func TestRegenerateClientCerts(t *testing.T) {
 	g := NewWithT(t)
 	caKey, err := certs.NewPrivateKey()
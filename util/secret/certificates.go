@@ -379,7 +379,9 @@ func (c *Certificate) AsFiles() []bootstrapv1.File {
 			Content:     string(c.KeyPair.Cert),
 		})
 	}
-	if len(c.KeyPair.Key) > 0 {
+	// NOTE: External certificates (e.g. the etcd CA of a dedicated etcd pool) might come with a key
+	// that must not be distributed to the machines; those certificates don't define a KeyFile.
+	if len(c.KeyPair.Key) > 0 && c.KeyFile != "" {
 		out = append(out, bootstrapv1.File{
 			Path:        c.KeyFile,
 			Owner:       rootOwnerValue,
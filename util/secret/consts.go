@@ -25,6 +25,14 @@ const (
 
 	// TLSCrtDataName is the key used to store a TLS certificate in the secret's data field.
 	TLSCrtDataName = "tls.crt"
+
+	// NextTLSKeyDataName is the key used to store the private key of the next certificate authority
+	// in the secret's data field while a certificate authority is being rotated.
+	NextTLSKeyDataName = "next.tls.key"
+
+	// RotationTimestampAnnotation is the annotation set on certificate authority secrets to record when the
+	// current phase of the certificate authority rotation has been started.
+	RotationTimestampAnnotation = "cluster.x-k8s.io/certificate-authority-rotation-timestamp"
 )
 
 // Purpose is the name to append to the secret generated for a cluster.
@@ -51,6 +59,10 @@ const (
 )
 
 var (
+	// RotatablePurposes defines the list of certificate authorities, and the service account keys, that
+	// Cluster API can rotate when it has generated them.
+	RotatablePurposes = []Purpose{ClusterCA, EtcdCA, FrontProxyCA, ServiceAccount}
+
 	// allSecretPurposes defines a lists with all the secret suffix used by Cluster API.
 	allSecretPurposes = []Purpose{Kubeconfig, ClusterCA, EtcdCA, ServiceAccount, FrontProxyCA, APIServerEtcdClient}
 )
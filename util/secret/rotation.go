@@ -0,0 +1,159 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret
+
+import (
+	"bytes"
+	"encoding/pem"
+	"time"
+
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/klog/v2"
+)
+
+// RotationPhase is the phase of the rotation of a certificate authority, or of the service account keys.
+//
+// A rotation goes through the following phases:
+//   - Trust: a new certificate authority is generated and appended to the trust bundle, while the previous
+//     certificate authority is still used for signing certificates.
+//   - Sign: the new certificate authority is used for signing certificates, while the previous certificate
+//     authority is still part of the trust bundle.
+//   - Retire: the previous certificate authority is removed from the trust bundle.
+//
+// Machines are expected to be rolled out in every phase, so they pick up the new trust bundle and
+// certificates signed by the current certificate authority.
+type RotationPhase string
+
+const (
+	// RotationPhaseNone documents a certificate authority not being rotated.
+	RotationPhaseNone = RotationPhase("")
+
+	// RotationPhaseTrust documents a certificate authority rotation adding the new certificate authority to the trust bundle.
+	RotationPhaseTrust = RotationPhase("Trust")
+
+	// RotationPhaseSign documents a certificate authority rotation signing certificates with the new certificate authority.
+	RotationPhaseSign = RotationPhase("Sign")
+
+	// RotationPhaseRetire documents a certificate authority rotation removing the previous certificate authority from the trust bundle.
+	RotationPhaseRetire = RotationPhase("Retire")
+)
+
+// Next returns the phase following the current one; a rotation moves from RotationPhaseRetire back to RotationPhaseNone.
+func (p RotationPhase) Next() RotationPhase {
+	switch p {
+	case RotationPhaseNone:
+		return RotationPhaseTrust
+	case RotationPhaseTrust:
+		return RotationPhaseSign
+	case RotationPhaseSign:
+		return RotationPhaseRetire
+	default:
+		return RotationPhaseNone
+	}
+}
+
+// GetRotationPhase returns the rotation phase of the certificate authority stored in the given secret.
+func GetRotationPhase(s *corev1.Secret) (RotationPhase, error) {
+	blocks := pemBlocks(s.Data[TLSCrtDataName])
+	_, hasNextKey := s.Data[NextTLSKeyDataName]
+	_, hasTimestamp := s.GetAnnotations()[RotationTimestampAnnotation]
+
+	switch {
+	case hasNextKey && len(blocks) == 2:
+		return RotationPhaseTrust, nil
+	case !hasNextKey && len(blocks) == 2:
+		return RotationPhaseSign, nil
+	case !hasNextKey && len(blocks) == 1 && hasTimestamp:
+		return RotationPhaseRetire, nil
+	case !hasNextKey && len(blocks) == 1:
+		return RotationPhaseNone, nil
+	}
+	return RotationPhaseNone, errors.Errorf("unexpected content in secret %s: found %d PEM blocks in %s", klog.KObj(s), len(blocks), TLSCrtDataName)
+}
+
+// GetRotationTimestamp returns the time when the current phase of the rotation of the certificate authority
+// stored in the given secret has been started, if any.
+func GetRotationTimestamp(s *corev1.Secret) (*time.Time, error) {
+	value, ok := s.GetAnnotations()[RotationTimestampAnnotation]
+	if !ok {
+		return nil, nil
+	}
+	timestamp, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to parse %s annotation on secret %s", RotationTimestampAnnotation, klog.KObj(s))
+	}
+	return &timestamp, nil
+}
+
+// AdvanceRotation moves the certificate authority stored in the given secret to the next rotation phase,
+// recording now as the time when the phase has been started.
+func AdvanceRotation(s *corev1.Secret, purpose Purpose, now time.Time) error {
+	phase, err := GetRotationPhase(s)
+	if err != nil {
+		return err
+	}
+	blocks := pemBlocks(s.Data[TLSCrtDataName])
+
+	switch phase {
+	case RotationPhaseNone:
+		next := &Certificate{Purpose: purpose}
+		if err := next.Generate(); err != nil {
+			return errors.Wrapf(err, "failed to generate %s certificate authority", purpose)
+		}
+		if next.KeyPair == nil {
+			return errors.Errorf("rotation of %s is not supported", purpose)
+		}
+		s.Data[TLSCrtDataName] = bytes.Join([][]byte{blocks[0], next.KeyPair.Cert}, nil)
+		s.Data[NextTLSKeyDataName] = next.KeyPair.Key
+	case RotationPhaseTrust:
+		s.Data[TLSCrtDataName] = bytes.Join([][]byte{blocks[1], blocks[0]}, nil)
+		s.Data[TLSKeyDataName] = s.Data[NextTLSKeyDataName]
+		delete(s.Data, NextTLSKeyDataName)
+	case RotationPhaseSign:
+		s.Data[TLSCrtDataName] = blocks[0]
+	case RotationPhaseRetire:
+		annotations := s.GetAnnotations()
+		delete(annotations, RotationTimestampAnnotation)
+		s.SetAnnotations(annotations)
+		return nil
+	}
+
+	annotations := s.GetAnnotations()
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[RotationTimestampAnnotation] = now.UTC().Format(time.RFC3339)
+	s.SetAnnotations(annotations)
+	return nil
+}
+
+// pemBlocks returns the PEM encoded blocks in data, e.g. the certificates in a certificate bundle.
+func pemBlocks(data []byte) [][]byte {
+	var blocks [][]byte
+	rest := bytes.TrimSpace(data)
+	for len(rest) > 0 {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+		blocks = append(blocks, pem.EncodeToMemory(block))
+		rest = bytes.TrimSpace(rest)
+	}
+	return blocks
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secret_test
+
+import (
+	"crypto/tls"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/cert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+
+	"sigs.k8s.io/cluster-api/util/secret"
+)
+
+func TestAdvanceRotation(t *testing.T) {
+	g := NewWithT(t)
+
+	certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ClusterCA}}
+	g.Expect(certificates.Generate()).To(Succeed())
+	s := certificates.GetByPurpose(secret.ClusterCA).AsSecret(client.ObjectKey{Name: "foo", Namespace: metav1.NamespaceDefault}, metav1.OwnerReference{})
+
+	initialCAs, err := cert.ParseCertsPEM(s.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(initialCAs).To(HaveLen(1))
+
+	phase, err := secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseNone))
+	timestamp, err := secret.GetRotationTimestamp(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(timestamp).To(BeNil())
+
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Trust: the new CA is appended to the trust bundle, the previous CA is still used for signing.
+	g.Expect(secret.AdvanceRotation(s, secret.ClusterCA, now)).To(Succeed())
+	phase, err = secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseTrust))
+	timestamp, err = secret.GetRotationTimestamp(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(*timestamp).To(Equal(now))
+
+	bundle, err := cert.ParseCertsPEM(s.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(bundle).To(HaveLen(2))
+	g.Expect(bundle[0].Equal(initialCAs[0])).To(BeTrue())
+	g.Expect(s.Data).To(HaveKey(secret.NextTLSKeyDataName))
+	_, err = tls.X509KeyPair(s.Data[secret.TLSCrtDataName], s.Data[secret.TLSKeyDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	newCA := bundle[1]
+
+	// Sign: the new CA is used for signing, the previous CA is still trusted.
+	g.Expect(secret.AdvanceRotation(s, secret.ClusterCA, now.Add(time.Hour))).To(Succeed())
+	phase, err = secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseSign))
+
+	bundle, err = cert.ParseCertsPEM(s.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(bundle).To(HaveLen(2))
+	g.Expect(bundle[0].Equal(newCA)).To(BeTrue())
+	g.Expect(bundle[1].Equal(initialCAs[0])).To(BeTrue())
+	g.Expect(s.Data).ToNot(HaveKey(secret.NextTLSKeyDataName))
+	_, err = tls.X509KeyPair(s.Data[secret.TLSCrtDataName], s.Data[secret.TLSKeyDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+
+	// Retire: the previous CA is removed from the trust bundle.
+	g.Expect(secret.AdvanceRotation(s, secret.ClusterCA, now.Add(2*time.Hour))).To(Succeed())
+	phase, err = secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseRetire))
+
+	bundle, err = cert.ParseCertsPEM(s.Data[secret.TLSCrtDataName])
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(bundle).To(HaveLen(1))
+	g.Expect(bundle[0].Equal(newCA)).To(BeTrue())
+
+	// Completing the rotation removes the rotation timestamp.
+	g.Expect(secret.AdvanceRotation(s, secret.ClusterCA, now.Add(3*time.Hour))).To(Succeed())
+	phase, err = secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseNone))
+	g.Expect(s.Annotations).ToNot(HaveKey(secret.RotationTimestampAnnotation))
+}
+
+func TestAdvanceRotationServiceAccount(t *testing.T) {
+	g := NewWithT(t)
+
+	certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ServiceAccount}}
+	g.Expect(certificates.Generate()).To(Succeed())
+	s := certificates.GetByPurpose(secret.ServiceAccount).AsSecret(client.ObjectKey{Name: "foo", Namespace: metav1.NamespaceDefault}, metav1.OwnerReference{})
+	initialKey := s.Data[secret.TLSKeyDataName]
+
+	g.Expect(secret.AdvanceRotation(s, secret.ServiceAccount, time.Now())).To(Succeed())
+	phase, err := secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseTrust))
+	g.Expect(s.Data[secret.TLSKeyDataName]).To(Equal(initialKey))
+	nextKey := s.Data[secret.NextTLSKeyDataName]
+
+	g.Expect(secret.AdvanceRotation(s, secret.ServiceAccount, time.Now())).To(Succeed())
+	phase, err = secret.GetRotationPhase(s)
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(phase).To(Equal(secret.RotationPhaseSign))
+	g.Expect(s.Data[secret.TLSKeyDataName]).To(Equal(nextKey))
+}
+
+func TestAdvanceRotationNotSupported(t *testing.T) {
+	g := NewWithT(t)
+
+	certificates := secret.Certificates{&secret.Certificate{Purpose: secret.ClusterCA}}
+	g.Expect(certificates.Generate()).To(Succeed())
+	s := certificates.GetByPurpose(secret.ClusterCA).AsSecret(client.ObjectKey{Name: "foo", Namespace: metav1.NamespaceDefault}, metav1.OwnerReference{})
+
+	g.Expect(secret.AdvanceRotation(s, secret.APIServerEtcdClient, time.Now())).ToNot(Succeed())
+}
@@ -0,0 +1,588 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"fmt"
+	"hash/fnv"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+	"github.com/pkg/errors"
+	coordinationv1 "k8s.io/api/coordination/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/util/uuid"
+	"k8s.io/apimachinery/pkg/util/wait"
+	"k8s.io/klog/v2"
+	"k8s.io/utils/ptr"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+const (
+	// ShardLabel can be set on a Cluster to assign the Cluster, and all the objects belonging to it,
+	// to a specific shard instead of the shard computed from the hash of the Cluster name.
+	// The value must be a non-negative integer; it is wrapped around the number of shards.
+	ShardLabel = "sharding.cluster.x-k8s.io/shard"
+
+	// leaseGroupLabel is set on all the Leases of a Sharder and contains the Sharder name.
+	leaseGroupLabel = "sharding.cluster.x-k8s.io/group"
+
+	// leaseTypeLabel is set on all the Leases of a Sharder and contains the type of the Lease.
+	leaseTypeLabel = "sharding.cluster.x-k8s.io/lease-type"
+
+	leaseTypeMember = "member"
+	leaseTypeShard  = "shard"
+
+	inClusterNamespacePath = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+// Options are the options of a Sharder.
+type Options struct {
+	// Shards is the number of shards Clusters are distributed across.
+	Shards int
+
+	// Name is the name of the group of replicas sharing the shards; it is used as
+	// prefix for the names of the Leases.
+	Name string
+
+	// Namespace is the namespace of the Leases.
+	// Defaults to the namespace the controller is running in.
+	Namespace string
+
+	// Identity is the identity of this replica.
+	// Defaults to the hostname with a random suffix.
+	Identity string
+
+	// LeaseDuration is the duration after which a shard held by a replica which stopped
+	// renewing its Lease can be claimed by another replica.
+	// Defaults to 15 seconds.
+	LeaseDuration time.Duration
+
+	// RenewPeriod is the interval at which Leases are renewed and shards are rebalanced.
+	// Defaults to 5 seconds.
+	RenewPeriod time.Duration
+
+	// Log is the logger used by the Sharder.
+	Log *logr.Logger
+}
+
+// Sharder distributes Clusters across the replicas of a controller manager.
+//
+// Each replica keeps a member Lease up to date and claims up to its fair share of
+// shards, i.e. the number of shards divided by the number of live replicas, via a
+// Lease per shard. When a replica joins, the other replicas release the shards
+// exceeding their fair share; when a replica dies, its shard Leases expire and are
+// claimed by the remaining replicas.
+//
+// A nil Sharder owns all the shards; this allows controllers to use it unconditionally.
+type Sharder struct {
+	client client.Client
+	reader client.Reader
+	cache  client.Reader
+	log    logr.Logger
+
+	options Options
+
+	lock sync.RWMutex
+	// owned contains the shards owned by this replica and the time the corresponding Lease was last renewed.
+	owned       map[int]time.Time
+	subscribers []chan event.GenericEvent
+}
+
+// New creates a Sharder and adds it to the manager, so shards are claimed
+// on every replica, not only on the leader.
+func New(mgr ctrl.Manager, options Options) (*Sharder, error) {
+	s, err := newSharder(mgr.GetClient(), mgr.GetAPIReader(), mgr.GetClient(), options)
+	if err != nil {
+		return nil, err
+	}
+	if err := mgr.Add(s); err != nil {
+		return nil, errors.Wrap(err, "failed to add sharder to the manager")
+	}
+	return s, nil
+}
+
+func newSharder(c client.Client, reader, cache client.Reader, options Options) (*Sharder, error) {
+	if options.Shards <= 0 {
+		return nil, errors.New("the number of shards must be greater than zero")
+	}
+	if options.Name == "" {
+		return nil, errors.New("the name of the sharder must be set")
+	}
+	if options.Namespace == "" {
+		namespace, err := os.ReadFile(inClusterNamespacePath)
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine the namespace of the shard Leases, the namespace must be set when not running in a cluster")
+		}
+		options.Namespace = strings.TrimSpace(string(namespace))
+	}
+	if options.Identity == "" {
+		hostname, err := os.Hostname()
+		if err != nil {
+			return nil, errors.Wrap(err, "failed to determine the identity of the replica")
+		}
+		options.Identity = hostname + "_" + string(uuid.NewUUID())
+	}
+	if options.LeaseDuration == 0 {
+		options.LeaseDuration = 15 * time.Second
+	}
+	if options.RenewPeriod == 0 {
+		options.RenewPeriod = 5 * time.Second
+	}
+	if options.RenewPeriod >= options.LeaseDuration {
+		return nil, errors.Errorf("the renew period (%s) must be shorter than the lease duration (%s)", options.RenewPeriod, options.LeaseDuration)
+	}
+	log := ctrl.Log.WithName("sharder")
+	if options.Log != nil {
+		log = *options.Log
+	}
+
+	return &Sharder{
+		client:  c,
+		reader:  reader,
+		cache:   cache,
+		log:     log.WithValues("sharder", options.Name, "identity", options.Identity),
+		options: options,
+		owned:   map[int]time.Time{},
+	}, nil
+}
+
+// NeedLeaderElection implements manager.LeaderElectionRunnable; shards are claimed on all the replicas.
+func (s *Sharder) NeedLeaderElection() bool {
+	return false
+}
+
+// Start claims and renews shards until the context is cancelled, then releases them
+// so other replicas can take them over without waiting for the Leases to expire.
+func (s *Sharder) Start(ctx context.Context) error {
+	s.log.Info("Starting sharder", "shards", s.options.Shards)
+	wait.UntilWithContext(ctx, s.reconcileLeases, s.options.RenewPeriod)
+
+	releaseCtx, cancel := context.WithTimeout(context.Background(), s.options.RenewPeriod)
+	defer cancel()
+	s.release(releaseCtx)
+	return nil
+}
+
+// ShardFor returns the shard of the Cluster with the given key, computed from the hash of
+// the Cluster namespace and name.
+func ShardFor(cluster client.ObjectKey, shards int) int {
+	return int(hashOf(cluster.String()) % uint32(shards))
+}
+
+// ClusterKeyForObject returns the key of the Cluster an object belongs to, determined
+// from the cluster name label or from the Cluster owner reference.
+// Objects not belonging to a Cluster are sharded by their own namespace and name.
+func ClusterKeyForObject(obj client.Object) client.ObjectKey {
+	key := client.ObjectKey{Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	if _, ok := obj.(*clusterv1.Cluster); ok {
+		return key
+	}
+	if name := obj.GetLabels()[clusterv1.ClusterNameLabel]; name != "" {
+		key.Name = name
+		return key
+	}
+	for _, ref := range obj.GetOwnerReferences() {
+		gv, err := schema.ParseGroupVersion(ref.APIVersion)
+		if err != nil {
+			continue
+		}
+		if ref.Kind == "Cluster" && gv.Group == clusterv1.GroupVersion.Group {
+			key.Name = ref.Name
+			return key
+		}
+	}
+	return key
+}
+
+// Owns returns true if the object belongs to a shard owned by this replica.
+func (s *Sharder) Owns(ctx context.Context, obj client.Object) bool {
+	if s == nil {
+		return true
+	}
+
+	cluster, ok := obj.(*clusterv1.Cluster)
+	if !ok {
+		cluster = &clusterv1.Cluster{}
+		if err := s.cache.Get(ctx, ClusterKeyForObject(obj), cluster); err != nil {
+			// Fall back to the shard computed from the Cluster name, e.g. if the Cluster is already gone.
+			cluster = &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: obj.GetNamespace(), Name: ClusterKeyForObject(obj).Name}}
+		}
+	}
+	return s.ownsShard(s.shardOf(cluster))
+}
+
+// shardOf returns the shard of a Cluster, taking into account the ShardLabel.
+func (s *Sharder) shardOf(cluster *clusterv1.Cluster) int {
+	if value, ok := cluster.Labels[ShardLabel]; ok {
+		if shard, err := strconv.Atoi(value); err == nil && shard >= 0 {
+			return shard % s.options.Shards
+		}
+	}
+	return ShardFor(client.ObjectKeyFromObject(cluster), s.options.Shards)
+}
+
+func (s *Sharder) ownsShard(shard int) bool {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	renewed, ok := s.owned[shard]
+	if !ok {
+		return false
+	}
+	// Stop reconciling before the Lease expires, so there is no overlap with a replica taking over the shard.
+	return time.Since(renewed) < s.options.LeaseDuration-s.options.RenewPeriod
+}
+
+// OwnedShards returns the shards currently owned by this replica.
+func (s *Sharder) OwnedShards() []int {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	shards := make([]int, 0, len(s.owned))
+	for shard := range s.owned {
+		shards = append(shards, shard)
+	}
+	sort.Ints(shards)
+	return shards
+}
+
+// ControllerOptions returns the given options adjusted for a sharded controller, which
+// has to run on all the replicas instead of only on the leader.
+func (s *Sharder) ControllerOptions(options controller.Options) controller.Options {
+	if s == nil {
+		return options
+	}
+	options.NeedLeaderElection = ptr.To(false)
+	return options
+}
+
+// Reconciler wraps a reconciler so that only objects belonging to a shard owned by this replica are reconciled.
+// The object is used as a template to read the reconciled objects from the given client.
+func (s *Sharder) Reconciler(c client.Reader, obj client.Object, r reconcile.Reconciler) reconcile.Reconciler {
+	if s == nil {
+		return r
+	}
+	return &shardedReconciler{
+		sharder:    s,
+		client:     c,
+		object:     obj,
+		Reconciler: r,
+	}
+}
+
+// Watch triggers the given handler with all the Clusters of a shard when it is claimed by this replica,
+// so the objects which were skipped while the shard was owned by another replica are reconciled.
+func (s *Sharder) Watch(c controller.Controller, h handler.EventHandler, prct ...predicate.Predicate) error {
+	if s == nil {
+		return nil
+	}
+
+	ch := make(chan event.GenericEvent)
+	s.lock.Lock()
+	s.subscribers = append(s.subscribers, ch)
+	s.lock.Unlock()
+
+	return c.Watch(&source.Channel{Source: ch}, h, prct...)
+}
+
+type shardedReconciler struct {
+	sharder *Sharder
+	client  client.Reader
+	object  client.Object
+	reconcile.Reconciler
+}
+
+func (r *shardedReconciler) Reconcile(ctx context.Context, req reconcile.Request) (reconcile.Result, error) {
+	obj := r.object.DeepCopyObject().(client.Object)
+	if err := r.client.Get(ctx, req.NamespacedName, obj); err != nil {
+		if apierrors.IsNotFound(err) {
+			return reconcile.Result{}, nil
+		}
+		return reconcile.Result{}, err
+	}
+
+	if !r.sharder.Owns(ctx, obj) {
+		ctrl.LoggerFrom(ctx).V(5).Info("Skipping reconcile, the object belongs to a shard owned by another replica")
+		return reconcile.Result{}, nil
+	}
+	return r.Reconciler.Reconcile(ctx, req)
+}
+
+// reconcileLeases renews the member Lease of this replica, then renews, releases and claims
+// shard Leases so that this replica holds its fair share of the shards.
+func (s *Sharder) reconcileLeases(ctx context.Context) {
+	now := time.Now()
+	if err := s.renewMemberLease(ctx, now); err != nil {
+		s.log.Error(err, "Failed to renew member Lease")
+		return
+	}
+
+	leases := &coordinationv1.LeaseList{}
+	if err := s.reader.List(ctx, leases, client.InNamespace(s.options.Namespace), client.MatchingLabels{leaseGroupLabel: s.options.Name}); err != nil {
+		s.log.Error(err, "Failed to list Leases")
+		return
+	}
+
+	members := 0
+	shardLeases := map[string]*coordinationv1.Lease{}
+	for i := range leases.Items {
+		lease := &leases.Items[i]
+		switch lease.Labels[leaseTypeLabel] {
+		case leaseTypeMember:
+			if !isExpired(lease, now) {
+				members++
+			}
+		case leaseTypeShard:
+			shardLeases[lease.Name] = lease
+		}
+	}
+	if members == 0 {
+		members = 1
+	}
+	fairShare := (s.options.Shards + members - 1) / members
+
+	held := map[int]bool{}
+
+	// Renew the shards held by this replica, releasing the ones exceeding the fair share.
+	for shard := 0; shard < s.options.Shards; shard++ {
+		lease, ok := shardLeases[s.shardLeaseName(shard)]
+		if !ok || ptr.Deref(lease.Spec.HolderIdentity, "") != s.options.Identity {
+			continue
+		}
+		if len(held) >= fairShare {
+			if err := s.releaseLease(ctx, lease); err != nil {
+				s.log.Error(err, "Failed to release shard Lease", "shard", shard)
+			}
+			continue
+		}
+		lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+		lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.options.LeaseDuration.Seconds()))
+		if err := s.client.Update(ctx, lease); err != nil {
+			s.log.Error(err, "Failed to renew shard Lease", "shard", shard)
+			continue
+		}
+		held[shard] = true
+	}
+
+	// Claim free or expired shards up to the fair share; start from an offset depending
+	// on the identity to reduce contention between replicas.
+	offset := int(hashOf(s.options.Identity) % uint32(s.options.Shards))
+	for i := 0; i < s.options.Shards && len(held) < fairShare; i++ {
+		shard := (offset + i) % s.options.Shards
+		if held[shard] {
+			continue
+		}
+		lease, ok := shardLeases[s.shardLeaseName(shard)]
+		switch {
+		case !ok:
+			lease = s.newLease(s.shardLeaseName(shard), leaseTypeShard, now)
+			if err := s.client.Create(ctx, lease); err != nil {
+				if !apierrors.IsAlreadyExists(err) {
+					s.log.Error(err, "Failed to create shard Lease", "shard", shard)
+				}
+				continue
+			}
+		case ptr.Deref(lease.Spec.HolderIdentity, "") == "" || isExpired(lease, now):
+			lease.Spec.HolderIdentity = ptr.To(s.options.Identity)
+			lease.Spec.AcquireTime = &metav1.MicroTime{Time: now}
+			lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+			lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.options.LeaseDuration.Seconds()))
+			lease.Spec.LeaseTransitions = ptr.To(ptr.Deref(lease.Spec.LeaseTransitions, 0) + 1)
+			if err := s.client.Update(ctx, lease); err != nil {
+				if !apierrors.IsConflict(err) {
+					s.log.Error(err, "Failed to claim shard Lease", "shard", shard)
+				}
+				continue
+			}
+		default:
+			continue
+		}
+		held[shard] = true
+	}
+
+	s.setOwned(ctx, held, now)
+}
+
+// setOwned records the shards held by this replica and notifies the subscribers about the Clusters of newly claimed shards.
+func (s *Sharder) setOwned(ctx context.Context, held map[int]bool, now time.Time) {
+	s.lock.Lock()
+	var claimed, lost []int
+	for shard := range held {
+		if _, ok := s.owned[shard]; !ok {
+			claimed = append(claimed, shard)
+		}
+	}
+	for shard := range s.owned {
+		if !held[shard] {
+			lost = append(lost, shard)
+		}
+	}
+	owned := make(map[int]time.Time, len(held))
+	for shard := range held {
+		owned[shard] = now
+	}
+	s.owned = owned
+	subscribers := append([]chan event.GenericEvent{}, s.subscribers...)
+	s.lock.Unlock()
+
+	if len(claimed) == 0 && len(lost) == 0 {
+		return
+	}
+	sort.Ints(claimed)
+	sort.Ints(lost)
+	s.log.Info("Shards changed", "claimed", claimed, "released", lost, "owned", len(held))
+
+	if len(claimed) == 0 || len(subscribers) == 0 {
+		return
+	}
+
+	clusters := &clusterv1.ClusterList{}
+	if err := s.cache.List(ctx, clusters); err != nil {
+		s.log.Error(err, "Failed to list Clusters of claimed shards")
+		return
+	}
+	claimedSet := map[int]bool{}
+	for _, shard := range claimed {
+		claimedSet[shard] = true
+	}
+	var events []event.GenericEvent
+	for i := range clusters.Items {
+		cluster := &clusters.Items[i]
+		if claimedSet[s.shardOf(cluster)] {
+			events = append(events, event.GenericEvent{Object: cluster})
+		}
+	}
+
+	// Notify the subscribers asynchronously, the controllers might not be started yet.
+	go func() {
+		for _, ch := range subscribers {
+			for _, e := range events {
+				select {
+				case ch <- e:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+}
+
+// release gives up all the shards held by this replica and deletes its member Lease.
+func (s *Sharder) release(ctx context.Context) {
+	s.lock.Lock()
+	owned := s.owned
+	s.owned = map[int]time.Time{}
+	s.lock.Unlock()
+
+	for shard := range owned {
+		lease := &coordinationv1.Lease{}
+		if err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.options.Namespace, Name: s.shardLeaseName(shard)}, lease); err != nil {
+			continue
+		}
+		if ptr.Deref(lease.Spec.HolderIdentity, "") != s.options.Identity {
+			continue
+		}
+		if err := s.releaseLease(ctx, lease); err != nil {
+			s.log.Error(err, "Failed to release shard Lease", "shard", shard)
+		}
+	}
+
+	member := &coordinationv1.Lease{ObjectMeta: metav1.ObjectMeta{Namespace: s.options.Namespace, Name: s.memberLeaseName()}}
+	if err := s.client.Delete(ctx, member); err != nil && !apierrors.IsNotFound(err) {
+		s.log.Error(err, "Failed to delete member Lease")
+	}
+}
+
+func (s *Sharder) releaseLease(ctx context.Context, lease *coordinationv1.Lease) error {
+	lease.Spec.HolderIdentity = nil
+	lease.Spec.AcquireTime = nil
+	lease.Spec.RenewTime = nil
+	return s.client.Update(ctx, lease)
+}
+
+func (s *Sharder) renewMemberLease(ctx context.Context, now time.Time) error {
+	lease := &coordinationv1.Lease{}
+	if err := s.reader.Get(ctx, client.ObjectKey{Namespace: s.options.Namespace, Name: s.memberLeaseName()}, lease); err != nil {
+		if !apierrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get Lease %s", klog.KRef(s.options.Namespace, s.memberLeaseName()))
+		}
+		return s.client.Create(ctx, s.newLease(s.memberLeaseName(), leaseTypeMember, now))
+	}
+	lease.Spec.HolderIdentity = ptr.To(s.options.Identity)
+	lease.Spec.RenewTime = &metav1.MicroTime{Time: now}
+	lease.Spec.LeaseDurationSeconds = ptr.To(int32(s.options.LeaseDuration.Seconds()))
+	return s.client.Update(ctx, lease)
+}
+
+func (s *Sharder) newLease(name, leaseType string, now time.Time) *coordinationv1.Lease {
+	return &coordinationv1.Lease{
+		ObjectMeta: metav1.ObjectMeta{
+			Namespace: s.options.Namespace,
+			Name:      name,
+			Labels: map[string]string{
+				leaseGroupLabel: s.options.Name,
+				leaseTypeLabel:  leaseType,
+			},
+		},
+		Spec: coordinationv1.LeaseSpec{
+			HolderIdentity:       ptr.To(s.options.Identity),
+			LeaseDurationSeconds: ptr.To(int32(s.options.LeaseDuration.Seconds())),
+			AcquireTime:          &metav1.MicroTime{Time: now},
+			RenewTime:            &metav1.MicroTime{Time: now},
+		},
+	}
+}
+
+func (s *Sharder) shardLeaseName(shard int) string {
+	return fmt.Sprintf("%s-shard-%d", s.options.Name, shard)
+}
+
+func (s *Sharder) memberLeaseName() string {
+	// The identity is hashed because it might contain characters which are not valid in a name.
+	return fmt.Sprintf("%s-member-%08x", s.options.Name, hashOf(s.options.Identity))
+}
+
+func isExpired(lease *coordinationv1.Lease, now time.Time) bool {
+	if lease.Spec.RenewTime == nil || lease.Spec.LeaseDurationSeconds == nil {
+		return true
+	}
+	return now.After(lease.Spec.RenewTime.Add(time.Duration(*lease.Spec.LeaseDurationSeconds) * time.Second))
+}
+
+func hashOf(s string) uint32 {
+	h := fnv.New32a()
+	_, _ = h.Write([]byte(s))
+	return h.Sum32()
+}
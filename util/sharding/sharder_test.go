@@ -0,0 +1,182 @@
+/*
+Copyright 2024 The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sharding
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+
+	clusterv1 "sigs.k8s.io/cluster-api/api/v1beta1"
+)
+
+func TestShardFor(t *testing.T) {
+	g := NewWithT(t)
+
+	counts := make([]int, 4)
+	for i := 0; i < 400; i++ {
+		key := client.ObjectKey{Namespace: "ns", Name: "cluster-" + string(rune('a'+i%26)) + string(rune('a'+i/26))}
+		shard := ShardFor(key, 4)
+		g.Expect(shard).To(BeNumerically(">=", 0))
+		g.Expect(shard).To(BeNumerically("<", 4))
+		g.Expect(ShardFor(key, 4)).To(Equal(shard), "shard must be stable")
+		counts[shard]++
+	}
+	for _, count := range counts {
+		g.Expect(count).To(BeNumerically(">", 50), "clusters must be spread across shards")
+	}
+}
+
+func TestClusterKeyForObject(t *testing.T) {
+	tests := []struct {
+		name string
+		obj  client.Object
+		want client.ObjectKey
+	}{
+		{
+			name: "Cluster",
+			obj:  &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "cluster"}},
+			want: client.ObjectKey{Namespace: "ns", Name: "cluster"},
+		},
+		{
+			name: "object with the cluster name label",
+			obj: &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "machine", Labels: map[string]string{
+				clusterv1.ClusterNameLabel: "cluster",
+			}}},
+			want: client.ObjectKey{Namespace: "ns", Name: "cluster"},
+		},
+		{
+			name: "object owned by a Cluster",
+			obj: &clusterv1.MachineSet{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "ms", OwnerReferences: []metav1.OwnerReference{
+				{APIVersion: "v1", Kind: "Cluster", Name: "not-a-cluster"},
+				{APIVersion: clusterv1.GroupVersion.String(), Kind: "Cluster", Name: "cluster"},
+			}}},
+			want: client.ObjectKey{Namespace: "ns", Name: "cluster"},
+		},
+		{
+			name: "object not belonging to a Cluster",
+			obj:  &clusterv1.MachineHealthCheck{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "mhc"}},
+			want: client.ObjectKey{Namespace: "ns", Name: "mhc"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			g := NewWithT(t)
+			g.Expect(ClusterKeyForObject(tt.obj)).To(Equal(tt.want))
+		})
+	}
+}
+
+func TestSharderRebalancesShards(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	c := fake.NewClientBuilder().WithScheme(newScheme(g)).Build()
+	newTestSharder := func(identity string) *Sharder {
+		s, err := newSharder(c, c, c, Options{
+			Shards:    5,
+			Name:      "test",
+			Namespace: "ns",
+			Identity:  identity,
+		})
+		g.Expect(err).ToNot(HaveOccurred())
+		return s
+	}
+
+	// A single replica claims all the shards.
+	s1 := newTestSharder("replica-1")
+	s1.reconcileLeases(ctx)
+	g.Expect(s1.OwnedShards()).To(Equal([]int{0, 1, 2, 3, 4}))
+
+	// A new replica cannot claim shards held by another replica.
+	s2 := newTestSharder("replica-2")
+	s2.reconcileLeases(ctx)
+	g.Expect(s2.OwnedShards()).To(BeEmpty())
+
+	// The first replica releases the shards exceeding its fair share, which are then claimed by the new replica.
+	s1.reconcileLeases(ctx)
+	g.Expect(s1.OwnedShards()).To(HaveLen(3))
+	s2.reconcileLeases(ctx)
+	g.Expect(s2.OwnedShards()).To(HaveLen(2))
+	g.Expect(append(s1.OwnedShards(), s2.OwnedShards()...)).To(ConsistOf(0, 1, 2, 3, 4))
+
+	// When a replica goes away, the remaining replica takes over its shards.
+	s2.release(ctx)
+	s1.reconcileLeases(ctx)
+	g.Expect(s1.OwnedShards()).To(Equal([]int{0, 1, 2, 3, 4}))
+}
+
+func TestSharderReconciler(t *testing.T) {
+	g := NewWithT(t)
+	ctx := context.Background()
+
+	owned := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "owned", Labels: map[string]string{ShardLabel: "0"}}}
+	notOwned := &clusterv1.Cluster{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "not-owned", Labels: map[string]string{ShardLabel: "1"}}}
+	machine := &clusterv1.Machine{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "machine", Labels: map[string]string{clusterv1.ClusterNameLabel: "not-owned"}}}
+	c := fake.NewClientBuilder().WithScheme(newScheme(g)).WithObjects(owned, notOwned, machine).Build()
+
+	s, err := newSharder(c, c, c, Options{Shards: 2, Name: "test", Namespace: "ns", Identity: "replica"})
+	g.Expect(err).ToNot(HaveOccurred())
+	s.owned = map[int]time.Time{0: time.Now()}
+
+	reconciled := 0
+	inner := reconcile.Func(func(context.Context, reconcile.Request) (reconcile.Result, error) {
+		reconciled++
+		return reconcile.Result{}, nil
+	})
+
+	clusterReconciler := s.Reconciler(c, &clusterv1.Cluster{}, inner)
+	_, err = clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(owned)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reconciled).To(Equal(1))
+
+	_, err = clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(notOwned)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reconciled).To(Equal(1))
+
+	// Objects belonging to a Cluster follow the shard of the Cluster.
+	machineReconciler := s.Reconciler(c, &clusterv1.Machine{}, inner)
+	_, err = machineReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(machine)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reconciled).To(Equal(1))
+
+	// Ownership expires if the Leases are not renewed.
+	s.owned = map[int]time.Time{0: time.Now().Add(-time.Minute)}
+	_, err = clusterReconciler.Reconcile(ctx, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(owned)})
+	g.Expect(err).ToNot(HaveOccurred())
+	g.Expect(reconciled).To(Equal(1))
+
+	// A nil Sharder does not filter.
+	var nilSharder *Sharder
+	g.Expect(nilSharder.Reconciler(c, &clusterv1.Cluster{}, inner)).ToNot(BeAssignableToTypeOf(&shardedReconciler{}))
+	g.Expect(nilSharder.Owns(ctx, notOwned)).To(BeTrue())
+}
+
+func newScheme(g *WithT) *runtime.Scheme {
+	scheme := runtime.NewScheme()
+	g.Expect(clientgoscheme.AddToScheme(scheme)).To(Succeed())
+	g.Expect(clusterv1.AddToScheme(scheme)).To(Succeed())
+	return scheme
+}